@@ -0,0 +1,302 @@
+// Package conditions is the condition vocabulary of GatewayHostnameRequest status: the
+// condition types the controller sets, the reasons it uses for each, and helpers to read and
+// set them. Tooling such as CLIs and dashboards can rely on these names; they only change
+// with a new API version.
+//
+// Positive conditions (Claimed through Ready) are True once their provisioning step is done.
+// Negative conditions (GatewayOverCapacity, GatewayChangePending, ReprovisionPending, Migrating,
+// DNSSECDegraded, ResourceValidationError, Quarantined, ForeignOwnership) are only present while
+// the situation they describe lasts. Validated is only present on requests that are only
+// validated.
+package conditions
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition types, in provisioning order
+const (
+	// TypeClaimed is True once the hostname is claimed for the request through a DomainClaim
+	TypeClaimed = "Claimed"
+	// TypeZoneDelegated is True once spec.delegatedZone exists and is delegated from the
+	// parent zone; only set on requests with a delegated zone
+	TypeZoneDelegated = "ZoneDelegated"
+	// TypeCertificateRequested is True once the certificate is requested from ACM or ordered
+	// through ACME
+	TypeCertificateRequested = "CertificateRequested"
+	// TypeDnsValidated is True once the DNS validation (or DNS-01 challenge) records are in place
+	TypeDnsValidated = "DnsValidated"
+	// TypeCertificateIssued is True once the certificate is issued
+	TypeCertificateIssued = "CertificateIssued"
+	// TypeListenerAttached is True once the hostname is assigned to a Gateway, its
+	// certificate attached and the AWS Load Balancer Controller programmed the Gateway
+	TypeListenerAttached = "ListenerAttached"
+	// TypeDnsAliasReady is True once the Route53 ALIAS records point at the load balancer
+	TypeDnsAliasReady = "DnsAliasReady"
+	// TypeNamespaceLabeled is True once the requesting namespace carries the access label of
+	// the Gateway; only set in strict route access mode with namespace labels
+	TypeNamespaceLabeled = "NamespaceLabeled"
+	// TypeAllowedRoutesConfigured is True once the Gateway's listeners accept HTTPRoutes from
+	// all namespaces; only set in strict route access mode
+	TypeAllowedRoutesConfigured = "AllowedRoutesConfigured"
+	// TypeReady is True once the hostname is fully provisioned
+	TypeReady = "Ready"
+	// TypeDeleting is set while deprovisioning waits for the certificate to be detached or
+	// between cleanup steps
+	TypeDeleting = "Deleting"
+)
+
+// Negative condition types
+const (
+	// TypeGatewayOverCapacity is True while the assigned Gateway is over capacity or being
+	// drained and the hostname waits to be moved off it
+	TypeGatewayOverCapacity = "GatewayOverCapacity"
+	// TypeGatewayChangePending is True while a change to a shared Gateway waits for confirmation
+	TypeGatewayChangePending = "GatewayChangePending"
+	// TypeReprovisionPending is True while a spec change waits for the re-provisioning rate
+	// limit; the hostname keeps being served with its previous spec meanwhile
+	TypeReprovisionPending = "ReprovisionPending"
+	// TypeMigrating is True while the hostname moves to another Gateway after its
+	// gatewaySelector or visibility changed, with the phase as reason. It is False with
+	// ReasonRolledBack if the move failed and the hostname went back to its previous Gateway.
+	TypeMigrating = "Migrating"
+	// TypeDNSSECDegraded is True while the hosted zone's DNSSEC signing is not healthy
+	TypeDNSSECDegraded = "DNSSECDegraded"
+	// TypeResourceValidationError is True when drift validation found resources to recreate
+	TypeResourceValidationError = "ResourceValidationError"
+	// TypeQuarantined is True while a suspicious hostname waits for manual approval before it
+	// is provisioned
+	TypeQuarantined = "Quarantined"
+	// TypeForeignOwnership is True while another manager claims the assigned Gateway or its
+	// LoadBalancerConfiguration through the gateway.opendi.com/managed-by annotation, and the
+	// controller leaves them alone
+	TypeForeignOwnership = "ForeignOwnership"
+)
+
+// TypeValidated is True if a request annotated with gateway.opendi.com/validate-only passed the
+// checks that precede provisioning, and False with what failed
+const TypeValidated = "Validated"
+
+// Reason is the machine-readable reason of a condition
+type Reason string
+
+// Reasons of Claimed
+const (
+	ReasonClaimed        Reason = "Claimed"
+	ReasonAlreadyClaimed Reason = "AlreadyClaimed"
+	ReasonClaimFailed    Reason = "ClaimFailed"
+	// ReasonReservedForNamespace means the hostname is in a subdomain tree that a Subtree
+	// DomainClaim reserves for another namespace
+	ReasonReservedForNamespace Reason = "ReservedForNamespace"
+	// ReasonResolvesElsewhere means the hostname already resolves in public DNS to addresses
+	// the controller doesn't manage, and the request doesn't set allowTakeover
+	ReasonResolvesElsewhere Reason = "ResolvesElsewhere"
+	// ReasonOwnershipMismatch means the hostname is claimed with sharedOwnership and the
+	// request doesn't set it, or the other way around
+	ReasonOwnershipMismatch Reason = "OwnershipMismatch"
+	// ReasonSetIdentifierTaken means another owner of a shared hostname holds the request's
+	// set identifier
+	ReasonSetIdentifierTaken Reason = "SetIdentifierTaken"
+)
+
+// Reasons of ZoneDelegated
+const (
+	ReasonDelegated Reason = "Delegated"
+	// ReasonZoneNotFound means the delegated zone doesn't exist and the controller doesn't
+	// create zones (--manage-delegated-zones is off)
+	ReasonZoneNotFound     Reason = "ZoneNotFound"
+	ReasonDelegationFailed Reason = "DelegationFailed"
+)
+
+// Reasons of CertificateRequested
+const (
+	ReasonRequested           Reason = "Requested"
+	ReasonOrdered             Reason = "Ordered"
+	ReasonRequestFailed       Reason = "RequestFailed"
+	ReasonIssuerNotConfigured Reason = "IssuerNotConfigured"
+	// ReasonCAAForbidsAmazon means the domain's CAA records don't allow Amazon to issue certificates
+	ReasonCAAForbidsAmazon Reason = "CAAForbidsAmazon"
+	// ReasonSharedCertificate means the hostname joined the certificate of another request
+	ReasonSharedCertificate Reason = "SharedCertificate"
+)
+
+// Reasons of DnsValidated
+const (
+	ReasonRecordsCreated              Reason = "RecordsCreated"
+	ReasonPendingValidationRecords    Reason = "PendingValidationRecords"
+	ReasonValidationRecordFailed      Reason = "ValidationRecordFailed"
+	ReasonChallengeRecordsPropagating Reason = "ChallengeRecordsPropagating"
+	ReasonChallengesAccepted          Reason = "ChallengesAccepted"
+	ReasonChallengeFailed             Reason = "ChallengeFailed"
+)
+
+// Reasons of CertificateIssued
+const (
+	ReasonIssued          Reason = "Issued"
+	ReasonPendingIssuance Reason = "PendingIssuance"
+	ReasonCheckFailed     Reason = "CheckFailed"
+	ReasonOrderFailed     Reason = "OrderFailed"
+	// ReasonPendingExpansion means the shared certificate is being re-issued to cover the hostname
+	ReasonPendingExpansion Reason = "PendingExpansion"
+)
+
+// Reasons of ListenerAttached
+const (
+	ReasonAttached         Reason = "Attached"
+	ReasonAttachmentFailed Reason = "AttachmentFailed"
+	// ReasonGatewayCreationThrottled means the request waits for a new Gateway under the
+	// creation cooldown; requests with this reason form the creation queue
+	ReasonGatewayCreationThrottled Reason = "GatewayCreationThrottled"
+	// ReasonPendingAcceptance means the AWS Load Balancer Controller hasn't processed the
+	// Gateway and its LoadBalancerConfiguration since the certificate was attached
+	ReasonPendingAcceptance Reason = "PendingAcceptance"
+	// ReasonAttachmentRejected means the AWS Load Balancer Controller reported the Gateway or
+	// its LoadBalancerConfiguration invalid
+	ReasonAttachmentRejected Reason = "AttachmentRejected"
+)
+
+// Reasons of DnsAliasReady
+const (
+	ReasonCreated     Reason = "Created"
+	ReasonAliasFailed Reason = "AliasFailed"
+)
+
+// Reasons of NamespaceLabeled
+const (
+	ReasonLabeled     Reason = "Labeled"
+	ReasonLabelFailed Reason = "LabelFailed"
+)
+
+// Reasons of AllowedRoutesConfigured
+const (
+	ReasonConfigured          Reason = "Configured"
+	ReasonConfigurationFailed Reason = "ConfigurationFailed"
+)
+
+// ReasonDNSSECDegraded is set on DnsValidated or DnsAliasReady when record changes are held
+// because the zone's DNSSEC signing is degraded
+const ReasonDNSSECDegraded Reason = "DNSSECDegraded"
+
+// Reasons of Ready
+const (
+	ReasonReady            Reason = "Ready"
+	ReasonValidationFailed Reason = "ValidationFailed"
+	// ReasonMigrating means the hostname is moving to another Gateway because of a placement change
+	ReasonMigrating Reason = "Migrating"
+	// ReasonRebalancing means the hostname is moving off an over-capacity Gateway; also a
+	// reason of GatewayOverCapacity
+	ReasonRebalancing Reason = "Rebalancing"
+	// ReasonLoadBalancerFailed means the ALB of the assigned Gateway failed or was deleted in AWS
+	// and the hostname is moving to a replacement Gateway
+	ReasonLoadBalancerFailed Reason = "LoadBalancerFailed"
+	// ReasonPolicyDenied means the hostname policy webhook vetoed the request
+	ReasonPolicyDenied Reason = "PolicyDenied"
+	// ReasonRouteAccessFailed means HTTPRoutes can't attach to the Gateway because
+	// NamespaceLabeled or AllowedRoutesConfigured is False
+	ReasonRouteAccessFailed Reason = "RouteAccessFailed"
+	// ReasonValidateOnly means the request is only validated and nothing is provisioned
+	ReasonValidateOnly Reason = "ValidateOnly"
+)
+
+// Reasons of Deleting
+const (
+	ReasonWaitingForCertDetachment Reason = "WaitingForCertDetachment"
+	// ReasonWaitingBetweenSteps means the deletion waits the cleanup step delay between
+	// deleting the DNS records and detaching the certificate
+	ReasonWaitingBetweenSteps Reason = "WaitingBetweenSteps"
+)
+
+// Reasons of GatewayOverCapacity
+const (
+	ReasonRebalancePending Reason = "RebalancePending"
+	// ReasonGatewayDrained means the Gateway is being drained and the hostname moves to another one
+	ReasonGatewayDrained Reason = "GatewayDrained"
+)
+
+// Reasons of GatewayChangePending
+const (
+	ReasonConfirmationRequired Reason = "ConfirmationRequired"
+)
+
+// Reasons of ReprovisionPending
+const (
+	ReasonReprovisioningThrottled Reason = "ReprovisioningThrottled"
+)
+
+// Reasons of Migrating: the phases of a move between Gateways
+const (
+	ReasonProvisioning Reason = "Provisioning"
+	ReasonSwitchingDNS Reason = "SwitchingDNS"
+	ReasonDraining     Reason = "Draining"
+	ReasonRolledBack   Reason = "RolledBack"
+)
+
+// Reasons of DNSSECDegraded
+const (
+	ReasonSigningDegraded Reason = "SigningDegraded"
+)
+
+// Reasons of Quarantined
+const (
+	// ReasonSuspiciousPattern means the hostname matches a configured quarantine pattern
+	ReasonSuspiciousPattern Reason = "SuspiciousPattern"
+	// ReasonHomoglyph means a punycode label of the hostname imitates Latin letters
+	ReasonHomoglyph Reason = "Homoglyph"
+)
+
+// Reasons of ForeignOwnership
+const (
+	ReasonForeignManager Reason = "ForeignManager"
+)
+
+// Reasons of Validated; a failed validation has ReasonValidationFailed
+const (
+	ReasonValid Reason = "Valid"
+)
+
+// Set sets a condition, keeping LastTransitionTime if the status doesn't change. It reports
+// whether the status or reason changed, i.e. whether this is a transition worth recording.
+func Set(conditions *[]metav1.Condition, condType string, status metav1.ConditionStatus, reason Reason, message string, generation int64) bool {
+	existing := meta.FindStatusCondition(*conditions, condType)
+	changed := existing == nil || existing.Status != status || existing.Reason != string(reason)
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               condType,
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+	return changed
+}
+
+// Remove removes a condition; negative conditions are removed once the situation is over
+func Remove(conditions *[]metav1.Condition, condType string) {
+	meta.RemoveStatusCondition(conditions, condType)
+}
+
+// Get returns the condition of the given type, or nil
+func Get(conditions []metav1.Condition, condType string) *metav1.Condition {
+	return meta.FindStatusCondition(conditions, condType)
+}
+
+// IsTrue reports whether the condition is present and True
+func IsTrue(conditions []metav1.Condition, condType string) bool {
+	return meta.IsStatusConditionTrue(conditions, condType)
+}
+
+// IsFalse reports whether the condition is present and False
+func IsFalse(conditions []metav1.Condition, condType string) bool {
+	return meta.IsStatusConditionFalse(conditions, condType)
+}
+
+// HasReason reports whether the condition is present with the given status and reason
+func HasReason(conditions []metav1.Condition, condType string, status metav1.ConditionStatus, reason Reason) bool {
+	cond := Get(conditions, condType)
+	return cond != nil && cond.Status == status && cond.Reason == string(reason)
+}
+
+// IsReady reports whether the hostname is fully provisioned
+func IsReady(conditions []metav1.Condition) bool {
+	return IsTrue(conditions, TypeReady)
+}
@@ -0,0 +1,39 @@
+package conditions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSet(t *testing.T) {
+	var conds []metav1.Condition
+
+	assert.True(t, Set(&conds, TypeCertificateIssued, metav1.ConditionFalse, ReasonPendingIssuance, "Waiting", 1))
+	transition := Get(conds, TypeCertificateIssued).LastTransitionTime
+
+	// Same status and reason: not a transition, even with a new message
+	assert.False(t, Set(&conds, TypeCertificateIssued, metav1.ConditionFalse, ReasonPendingIssuance, "Still waiting", 2))
+	cond := Get(conds, TypeCertificateIssued)
+	assert.Equal(t, "Still waiting", cond.Message)
+	assert.Equal(t, int64(2), cond.ObservedGeneration)
+	assert.Equal(t, transition, cond.LastTransitionTime)
+
+	assert.True(t, Set(&conds, TypeCertificateIssued, metav1.ConditionFalse, ReasonCheckFailed, "AccessDenied", 2))
+	assert.True(t, HasReason(conds, TypeCertificateIssued, metav1.ConditionFalse, ReasonCheckFailed))
+	assert.True(t, IsFalse(conds, TypeCertificateIssued))
+	assert.False(t, IsTrue(conds, TypeCertificateIssued))
+
+	Remove(&conds, TypeCertificateIssued)
+	assert.Nil(t, Get(conds, TypeCertificateIssued))
+	assert.False(t, HasReason(conds, TypeCertificateIssued, metav1.ConditionFalse, ReasonCheckFailed))
+}
+
+func TestIsReady(t *testing.T) {
+	var conds []metav1.Condition
+	assert.False(t, IsReady(conds))
+
+	Set(&conds, TypeReady, metav1.ConditionTrue, ReasonReady, "", 1)
+	assert.True(t, IsReady(conds))
+}
@@ -4,6 +4,16 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// DomainClaim scopes
+const (
+	// DomainClaimScopeHostname claims exactly spec.hostname for the owning request
+	DomainClaimScopeHostname = "Hostname"
+
+	// DomainClaimScopeSubtree claims spec.hostname and every name beneath it for the namespace
+	// spec.ownerRef.namespace
+	DomainClaimScopeSubtree = "Subtree"
+)
+
 // DomainClaimSpec defines the desired state of DomainClaim
 type DomainClaimSpec struct {
 	// ZoneId is the Route53 hosted zone ID
@@ -14,9 +24,32 @@ type DomainClaimSpec struct {
 	// +kubebuilder:validation:Required
 	Hostname string `json:"hostname"`
 
-	// OwnerRef references the GatewayHostnameRequest that owns this claim
+	// Environment is the spec.environment of the owning request, for information. On Subtree
+	// claims it limits the claim to requests for that environment.
+	// +optional
+	Environment string `json:"environment,omitempty"`
+
+	// Scope is Hostname (default), created by the controller for each request, or Subtree,
+	// created by platform teams to reserve a subdomain tree for a namespace. Requests for
+	// spec.hostname or names beneath it can then only claim them from that namespace.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Hostname;Subtree
+	Scope string `json:"scope,omitempty"`
+
+	// OwnerRef references the GatewayHostnameRequest that owns this claim, or only the
+	// namespace for Subtree claims. For shared claims it is the first of the owners.
 	// +kubebuilder:validation:Required
 	OwnerRef DomainClaimOwnerRef `json:"ownerRef"`
+
+	// Shared is set on claims of requests with sharedOwnership. Other requests with
+	// sharedOwnership join them; requests without it can't.
+	// +optional
+	Shared bool `json:"shared,omitempty"`
+
+	// Owners lists the requests sharing the claim, each with the set identifier of its
+	// weighted records
+	// +optional
+	Owners []DomainClaimOwner `json:"owners,omitempty"`
 }
 
 type DomainClaimOwnerRef struct {
@@ -24,10 +57,24 @@ type DomainClaimOwnerRef struct {
 	Namespace string `json:"namespace"`
 
 	// Name of the owning GatewayHostnameRequest
-	Name string `json:"name"`
+	// +optional
+	Name string `json:"name,omitempty"`
 
 	// UID of the owning GatewayHostnameRequest
-	UID string `json:"uid"`
+	// +optional
+	UID string `json:"uid,omitempty"`
+}
+
+// DomainClaimOwner is one of the requests sharing a claim
+type DomainClaimOwner struct {
+	DomainClaimOwnerRef `json:",inline"`
+
+	// Cluster that holds the request, for claims in a shared claim store
+	// +optional
+	Cluster string `json:"cluster,omitempty"`
+
+	// SetIdentifier of the owner's weighted records
+	SetIdentifier string `json:"setIdentifier"`
 }
 
 // DomainClaimStatus defines the observed state of DomainClaim
@@ -42,10 +89,12 @@ type DomainClaimStatus struct {
 // +kubebuilder:resource:scope=Cluster,shortName=dc
 // +kubebuilder:printcolumn:name="Hostname",type=string,JSONPath=`.spec.hostname`
 // +kubebuilder:printcolumn:name="Owner",type=string,JSONPath=`.spec.ownerRef.namespace`
+// +kubebuilder:printcolumn:name="Scope",type=string,JSONPath=`.spec.scope`
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // DomainClaim is the Schema for the domainclaims API
-// Implements atomic first-come-first-serve for (zoneId, hostname) pairs
+// Implements atomic first-come-first-serve for (zoneId, hostname) pairs.
+// Subtree claims reserve a hostname and all names beneath it for a namespace.
 type DomainClaim struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -5,13 +5,46 @@ import (
 )
 
 // GatewayHostnameRequestSpec defines the desired state of GatewayHostnameRequest
+// +kubebuilder:validation:XValidation:rule="!has(self.aliasTarget) || (!has(self.gatewaySelector) && !has(self.wafArn) && !has(self.accessLogs))",message="gatewaySelector, wafArn and accessLogs do not apply to DNS-only requests with aliasTarget"
+// +kubebuilder:validation:XValidation:rule="!has(self.tls) || self.tls != 'disabled' || (!has(self.aliasTarget) && !has(self.certificateIssuer) && !has(self.certificateRegion))",message="aliasTarget, certificateIssuer and certificateRegion do not apply to requests with tls disabled"
+// +kubebuilder:validation:XValidation:rule="!has(self.delegatedZone) || self.hostname == self.delegatedZone || self.hostname.endsWith('.' + self.delegatedZone)",message="hostname must be in delegatedZone"
+// +kubebuilder:validation:XValidation:rule="!has(self.delegatedZone) || self.zoneId == oldSelf.zoneId",message="zoneId is immutable for requests with a delegatedZone"
+// +kubebuilder:validation:XValidation:rule="!has(self.aliasTarget) || !has(self.overrideTarget)",message="overrideTarget does not apply to DNS-only requests with aliasTarget"
+// +kubebuilder:validation:XValidation:rule="!has(self.ipv6Only) || !self.ipv6Only || ((!has(self.aliasTarget) || (has(self.aliasTarget.ipv6) && self.aliasTarget.ipv6)) && (!has(self.overrideTarget) || (has(self.overrideTarget.ipv6) && self.overrideTarget.ipv6)))",message="ipv6Only requires ipv6 on aliasTarget and overrideTarget"
+// +kubebuilder:validation:XValidation:rule="has(self.sharedOwnership) == has(oldSelf.sharedOwnership)",message="sharedOwnership can't be added or removed; delete and recreate the request"
+// +kubebuilder:validation:XValidation:rule="!has(self.sharedOwnership) || self.zoneId == oldSelf.zoneId",message="zoneId is immutable for requests with sharedOwnership"
+// +kubebuilder:validation:XValidation:rule="!has(self.sharedOwnership) || (!has(self.delegatedZone) && (!has(self.certificateIssuer) || self.certificateIssuer != 'ACME'))",message="sharedOwnership does not support delegatedZone or certificateIssuer ACME"
 type GatewayHostnameRequestSpec struct {
-	// ZoneId is the Route53 hosted zone ID where DNS records will be created
+	// ZoneId is the Route53 hosted zone ID where DNS records will be created.
+	// Changing it moves the records: they are created in the new zone before they are deleted
+	// from the old one, so the hostname keeps resolving. Immutable with a DelegatedZone.
 	// +kubebuilder:validation:Required
 	ZoneId string `json:"zoneId"`
 
-	// Hostname is the FQDN to expose (e.g., test.opendi.com or *.opendi.de for wildcard)
+	// AdditionalZoneIds lists further Route53 hosted zones (e.g., a legacy zone during a
+	// DNS migration) where the ALIAS record for the hostname is also maintained.
+	// Certificate validation records and the DomainClaim only use ZoneId.
+	// Zones can be added or removed without re-provisioning the certificate.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=5
+	AdditionalZoneIds []string `json:"additionalZoneIds,omitempty"`
+
+	// DelegatedZone is a sub-zone of the hosted zone in ZoneId that holds the hostname's records
+	// (e.g., team-a.example.com for api.team-a.example.com). If no public hosted zone with that
+	// name exists, the controller creates it and delegates it from ZoneId with NS records
+	// (requires --manage-delegated-zones). The DomainClaim still uses ZoneId.
+	// Immutable: to change it, delete and recreate the request.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="delegatedZone is immutable; delete and recreate the request to change it"
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]+(-[a-z0-9]+)*\.)+[a-z]{2,}$`
+	DelegatedZone string `json:"delegatedZone,omitempty"`
+
+	// Hostname is the FQDN to expose (e.g., test.opendi.com or *.opendi.de for wildcard).
+	// Immutable: to change it, delete and recreate the request.
 	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="hostname is immutable; delete and recreate the request to change it"
+	// +kubebuilder:validation:XValidation:rule="self != 'cluster.local' && !self.endsWith('.cluster.local') && !self.endsWith('.svc')",message="hostname must not be in a cluster-internal domain (cluster.local, svc)"
+	// +kubebuilder:validation:MaxLength=253
 	// +kubebuilder:validation:Pattern=`^(\*\.)?([a-z0-9]+(-[a-z0-9]+)*\.)+[a-z]{2,}$`
 	Hostname string `json:"hostname"`
 
@@ -20,13 +53,16 @@ type GatewayHostnameRequestSpec struct {
 	// +kubebuilder:validation:Enum=dev;staging;prod
 	Environment string `json:"environment,omitempty"`
 
-	// Visibility specifies whether the Gateway should be internet-facing or internal
+	// Visibility specifies whether the Gateway should be internet-facing or internal.
+	// When unset, the namespace annotation gateway.opendi.com/default-visibility applies
+	// (if the defaulting webhook is enabled), falling back to internet-facing.
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Enum=internet-facing;internal
-	// +kubebuilder:default=internet-facing
 	Visibility string `json:"visibility,omitempty"`
 
-	// GatewayClass specifies which GatewayClass to use
+	// GatewayClass specifies which GatewayClass to use. A controller instance serves only its
+	// --gateway-class and applies that class's settings whatever the value; run an instance
+	// per class to give classes different settings.
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default=aws-alb
 	GatewayClass string `json:"gatewayClass,omitempty"`
@@ -42,9 +78,179 @@ type GatewayHostnameRequestSpec struct {
 	// - Already has this WAF ARN configured, or
 	// - Has no WAF configured (this request will set it)
 	// All hostnames on the same Gateway will share the same WAF (ALB constraint).
+	// When unset, the namespace annotation gateway.opendi.com/default-waf-arn applies
+	// (if the defaulting webhook is enabled).
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Pattern=`^arn:aws:wafv2:[a-z0-9-]+:[0-9]+:.*$`
+	// +kubebuilder:validation:XValidation:rule="self.matches(':regional/webacl/')",message="wafArn must be a regional WebACL; global (CloudFront) WebACLs cannot be associated with an ALB"
 	WafArn string `json:"wafArn,omitempty"`
+
+	// AliasTarget makes this a DNS-only request: the certificate, its validation records and
+	// the ALIAS records pointing at this target are managed, but no Gateway is assigned.
+	// Use it for CloudFront, API Gateway or an externally managed load balancer.
+	// +kubebuilder:validation:Optional
+	AliasTarget *AliasTarget `json:"aliasTarget,omitempty"`
+
+	// CertificateIssuer selects where the certificate comes from: ACM (default) issues it,
+	// ACME issues it through the controller's ACME CA (e.g. Let's Encrypt) with DNS-01 and
+	// imports it into ACM. Use ACME for domains whose CAA records don't allow Amazon.
+	// Changing it re-provisions the certificate.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=ACM;ACME
+	CertificateIssuer string `json:"certificateIssuer,omitempty"`
+
+	// CertificateRegion pins the certificate to an AWS region other than the controller's, e.g.
+	// us-east-1 for a CloudFront distribution in aliasTarget. ALBs only use certificates of their
+	// own region, so requests served by a Gateway can only pin the Gateway's region (see
+	// status.gatewayRegion). Changing it re-provisions the certificate.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[a-z]{2}(-[a-z]+)+-[0-9]+$`
+	CertificateRegion string `json:"certificateRegion,omitempty"`
+
+	// TLS set to disabled serves the hostname over plain HTTP only: no certificate is requested
+	// and routes attach to the Gateway's HTTP listener. Use it for redirect domains or hosts
+	// answering ACME HTTP-01 challenges. Changing it re-provisions the hostname.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=enabled;disabled
+	TLS string `json:"tls,omitempty"`
+
+	// SecurityHeaders are added as response headers to the HTTPRoutes serving the hostname.
+	// Unset fields fall back to the controller-wide defaults.
+	// +kubebuilder:validation:Optional
+	SecurityHeaders *SecurityHeaders `json:"securityHeaders,omitempty"`
+
+	// TTL is how long after creation the request is deleted automatically, which deprovisions
+	// its certificate, DNS records and DomainClaim (e.g., 72h for pull request preview
+	// environments). It can be extended or removed at any time without re-provisioning.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s')",message="ttl must be positive"
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// AccessLogs opts the hostname into access logs of its own: its requests are copied from the
+	// access logs of the shared ALB to a prefix only they are written to, partitioned by date
+	// for Athena (see status.accessLogLocation). Requires the controller's access log bucket.
+	// +kubebuilder:validation:Optional
+	AccessLogs bool `json:"accessLogs,omitempty"`
+
+	// AllowTakeover acknowledges that the hostname already resolves to addresses the controller
+	// doesn't manage, and that provisioning it replaces the live records. Without it such
+	// hostnames aren't claimed while the controller verifies existing DNS.
+	// +kubebuilder:validation:Optional
+	AllowTakeover bool `json:"allowTakeover,omitempty"`
+
+	// Backends tune the target groups of the Services the hostname's HTTPRoutes route to. They
+	// are rendered into the TargetGroupConfigurations the controller manages for those Services.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=20
+	// +listType=map
+	// +listMapKey=service
+	Backends []BackendSettings `json:"backends,omitempty"`
+
+	// OverrideTarget is a break-glass ALIAS target the hostname points at instead of its ALB,
+	// e.g. an S3 website endpoint serving a maintenance page. The certificate and the Gateway
+	// keep the hostname; clearing the field points it back at the ALB. Only members of the
+	// controller's --override-target-groups may set or change it, which the validating webhook
+	// enforces; without the webhooks the controller ignores the field.
+	// +kubebuilder:validation:Optional
+	OverrideTarget *AliasTarget `json:"overrideTarget,omitempty"`
+
+	// IPv6Only publishes only an AAAA alias and no A record, for IPv6-only products. Requires
+	// dualstack ALBs (--ip-address-type), or ipv6 on aliasTarget and overrideTarget. Can be
+	// changed without re-provisioning; the records of the other type are removed.
+	// +kubebuilder:validation:Optional
+	IPv6Only bool `json:"ipv6Only,omitempty"`
+
+	// SharedOwnership serves the hostname together with requests in other clusters (or other
+	// requests in this one) that set it too: they share the DomainClaim, and each publishes
+	// weighted ALIAS records under its own set identifier. Can't be added or removed later.
+	// +kubebuilder:validation:Optional
+	SharedOwnership *SharedOwnership `json:"sharedOwnership,omitempty"`
+}
+
+// SharedOwnership is a request's share of a hostname served by several owners
+type SharedOwnership struct {
+	// SetIdentifier names this owner's weighted record sets, e.g. the cluster name. Owners of
+	// a hostname need distinct identifiers; only the owner holding one writes its records.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=128
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="setIdentifier is immutable; delete and recreate the request to change it"
+	SetIdentifier string `json:"setIdentifier"`
+
+	// Weight is this owner's share of the DNS answers relative to the other owners' weights;
+	// 0 takes the owner out of rotation while others have a weight above 0
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=255
+	Weight int64 `json:"weight"`
+}
+
+// BackendSettings tunes the target group of a backend Service
+type BackendSettings struct {
+	// Service is the name of the Service in the request's namespace
+	// +kubebuilder:validation:MinLength=1
+	Service string `json:"service"`
+
+	// Stickiness routes a client's requests to the same target
+	// +kubebuilder:validation:Optional
+	Stickiness *Stickiness `json:"stickiness,omitempty"`
+
+	// SlowStartSeconds ramps a new target up to its full share of requests over this many
+	// seconds, e.g. to warm up caches or a JIT
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=30
+	// +kubebuilder:validation:Maximum=900
+	SlowStartSeconds *int32 `json:"slowStartSeconds,omitempty"`
+}
+
+// Stickiness binds a client to a target with a cookie
+type Stickiness struct {
+	// Duration is how long a client sticks to its target, from 1s to 7 days
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('1s') && duration(self) <= duration('168h')",message="stickiness duration must be between 1s and 7 days"
+	Duration metav1.Duration `json:"duration"`
+
+	// CookieName is the application's cookie that binds clients. Unset uses a cookie generated
+	// by the load balancer.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_.-]+$`
+	// +kubebuilder:validation:XValidation:rule="!self.startsWith('AWSALB')",message="cookie names starting with AWSALB are reserved for the load balancer"
+	CookieName string `json:"cookieName,omitempty"`
+}
+
+// SecurityHeaders are security response headers enforced for a hostname
+type SecurityHeaders struct {
+	// StrictTransportSecurity is the Strict-Transport-Security value (e.g., max-age=31536000; includeSubDomains)
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^max-age=[0-9]+(; ?includeSubDomains)?(; ?preload)?$`
+	StrictTransportSecurity string `json:"strictTransportSecurity,omitempty"`
+
+	// FrameOptions is the X-Frame-Options value
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=DENY;SAMEORIGIN
+	FrameOptions string `json:"frameOptions,omitempty"`
+}
+
+// AliasTarget is an externally managed Route53 ALIAS target
+// +kubebuilder:validation:XValidation:rule="!self.dnsName.endsWith('.cloudfront.net') || !has(self.evaluateTargetHealth) || !self.evaluateTargetHealth",message="evaluateTargetHealth must be false for CloudFront distributions"
+type AliasTarget struct {
+	// DNSName of the target (e.g., d111111abcdef8.cloudfront.net)
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	DNSName string `json:"dnsName"`
+
+	// HostedZoneId is the target's canonical hosted zone ID (e.g., Z2FDTNDATAQYW2 for CloudFront).
+	// Derived from DNSName when unset for CloudFront distributions, regional API Gateway custom
+	// domains and S3 website endpoints; required for other targets such as load balancers.
+	// +kubebuilder:validation:Optional
+	HostedZoneId string `json:"hostedZoneId,omitempty"`
+
+	// EvaluateTargetHealth sets the Route53 alias health evaluation (must be false for CloudFront)
+	// +kubebuilder:validation:Optional
+	EvaluateTargetHealth bool `json:"evaluateTargetHealth,omitempty"`
+
+	// IPv6 also publishes an AAAA alias (only if the target serves IPv6)
+	// +kubebuilder:validation:Optional
+	IPv6 bool `json:"ipv6,omitempty"`
 }
 
 // GatewayHostnameRequestStatus defines the observed state of GatewayHostnameRequest
@@ -53,10 +259,27 @@ type GatewayHostnameRequestStatus struct {
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
+	// Message is a human-readable summary of the request's state, composed from the conditions:
+	// what it is waiting for, or why it is stuck
+	// +optional
+	Message string `json:"message,omitempty"`
+
 	// ObservedSpecHash is a hash of the spec fields that require re-provisioning when changed
 	// +optional
 	ObservedSpecHash string `json:"observedSpecHash,omitempty"`
 
+	// ObservedStateHash is a hash of the desired state the last full reconcile applied: the
+	// spec and annotations, the assignment and the Gateway, LoadBalancerConfiguration,
+	// namespace and HTTPRoutes it configured. Reconciles are skipped while it is unchanged,
+	// until NextFullReconcileTime.
+	// +optional
+	ObservedStateHash string `json:"observedStateHash,omitempty"`
+
+	// NextFullReconcileTime is when the request is fully reconciled again even if its desired
+	// state is unchanged
+	// +optional
+	NextFullReconcileTime *metav1.Time `json:"nextFullReconcileTime,omitempty"`
+
 	// AssignedGateway is the name of the Gateway this hostname is assigned to
 	// +optional
 	AssignedGateway string `json:"assignedGateway,omitempty"`
@@ -65,19 +288,278 @@ type GatewayHostnameRequestStatus struct {
 	// +optional
 	AssignedGatewayNamespace string `json:"assignedGatewayNamespace,omitempty"`
 
+	// MigratingFromGateway is the Gateway this hostname is being moved off, because it is over
+	// capacity or no longer matches the request's gatewaySelector or visibility.
+	// The old Gateway keeps serving the certificate until DNS points at the new one.
+	// +optional
+	MigratingFromGateway string `json:"migratingFromGateway,omitempty"`
+
+	// MigratingFromGatewayNamespace is the namespace of MigratingFromGateway. Empty means
+	// AssignedGatewayNamespace.
+	// +optional
+	MigratingFromGatewayNamespace string `json:"migratingFromGatewayNamespace,omitempty"`
+
 	// AssignedLoadBalancer is the ALB DNS name
 	// +optional
 	AssignedLoadBalancer string `json:"assignedLoadBalancer,omitempty"`
 
+	// GatewayRegion is the AWS region of AssignedLoadBalancer
+	// +optional
+	GatewayRegion string `json:"gatewayRegion,omitempty"`
+
+	// OverrideTarget is the DNS name of spec.overrideTarget while the ALIAS records point there
+	// instead of at AssignedLoadBalancer
+	// +optional
+	OverrideTarget string `json:"overrideTarget,omitempty"`
+
+	// DelegatedZoneId is the hosted zone of spec.delegatedZone, once it was found or created and
+	// delegated from spec.zoneId
+	// +optional
+	DelegatedZoneId string `json:"delegatedZoneId,omitempty"`
+
+	// AliasZoneIds are the hosted zones the ALIAS records are currently published in
+	// +optional
+	AliasZoneIds []string `json:"aliasZoneIds,omitempty"`
+
+	// MigratingFromZoneId is the hosted zone the records are being moved out of after
+	// spec.zoneId changed. They are deleted there once they exist in the new zone.
+	// +optional
+	MigratingFromZoneId string `json:"migratingFromZoneId,omitempty"`
+
+	// AliasRecordTypes are the ALIAS record types currently published (A, plus AAAA for dualstack
+	// ALBs, or only AAAA for IPv6-only hostnames)
+	// +optional
+	AliasRecordTypes []string `json:"aliasRecordTypes,omitempty"`
+
+	// AliasWeight is the weight the ALIAS records were published with, for requests with
+	// sharedOwnership
+	// +optional
+	AliasWeight *int64 `json:"aliasWeight,omitempty"`
+
+	// PrivateZoneVpcs are the associations of the request's private hosted zones with VPCs of
+	// the OrchestratorConfig's spec.privateZoneVpcs that the controller created, as
+	// <zoneId>/<region>/<vpcId>
+	// +optional
+	PrivateZoneVpcs []string `json:"privateZoneVpcs,omitempty"`
+
+	// AccessLogLocation is the S3 location the hostname's access logs are delivered to, if it
+	// opted into access logs
+	// +optional
+	AccessLogLocation string `json:"accessLogLocation,omitempty"`
+
 	// CertificateArn is the ACM certificate ARN
 	// +optional
 	CertificateArn string `json:"certificateArn,omitempty"`
 
+	// CertificateRegion is the AWS region of CertificateArn, which differs from GatewayRegion
+	// for DNS-only requests with a spec.certificateRegion
+	// +optional
+	CertificateRegion string `json:"certificateRegion,omitempty"`
+
+	// CertificateIssuer is the issuer of the current certificate (ACM or ACME). It differs from
+	// spec.certificateIssuer when the controller fell back to ACME on an ACM quota error.
+	// +optional
+	CertificateIssuer string `json:"certificateIssuer,omitempty"`
+
+	// CertificateOrderURL is the ACME order in progress, for issuance or renewal
+	// +optional
+	CertificateOrderURL string `json:"certificateOrderURL,omitempty"`
+
+	// CertificateNotAfter is the expiry of an ACME-issued certificate. Unlike ACM certificates,
+	// these are renewed by the controller, ahead of this time.
+	// +optional
+	CertificateNotAfter *metav1.Time `json:"certificateNotAfter,omitempty"`
+
+	// CertificateReplacements counts how often the certificate was discarded to request a new one,
+	// after a spec change or drift. It keeps ACM idempotency tokens unique per certificate.
+	// +optional
+	CertificateReplacements int32 `json:"certificateReplacements,omitempty"`
+
+	// CertificateHost is the request in the same namespace whose certificate also covers this
+	// hostname. Set when the controller runs with --expand-certificates and the hostname joined
+	// that request's certificate instead of getting its own.
+	// +optional
+	CertificateHost string `json:"certificateHost,omitempty"`
+
+	// CertificateSANs are the hostnames of other requests the certificate covers besides
+	// spec.hostname, after it was expanded for them
+	// +optional
+	CertificateSANs []string `json:"certificateSANs,omitempty"`
+
+	// PendingCertificateArn is the expanded certificate awaiting issuance. Once issued it
+	// replaces CertificateArn on the listener.
+	// +optional
+	PendingCertificateArn string `json:"pendingCertificateArn,omitempty"`
+
+	// RetiredCertificateArn is the certificate an expanded one replaced. It is deleted once no
+	// request and no load balancer uses it anymore.
+	// +optional
+	RetiredCertificateArn string `json:"retiredCertificateArn,omitempty"`
+
+	// Routes are the HTTPRoutes in the request namespace that match the hostname, kept current
+	// as HTTPRoutes change. A route not attached to the assigned Gateway gets none of the
+	// hostname's traffic, e.g. after the hostname moved to another Gateway.
+	// +optional
+	Routes []HostnameRoute `json:"routes,omitempty"`
+
+	// CostEstimate is a rough monthly estimate of the request's share of the AWS resources the
+	// controller manages for it. Only set when the controller runs with --cost-estimates.
+	// +optional
+	CostEstimate *CostEstimate `json:"costEstimate,omitempty"`
+
+	// Journal records AWS resources created for the request whose status write failed, so
+	// the next reconcile adopts them instead of creating duplicates. Entries are removed once
+	// the status reflects them.
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	Journal []JournalEntry `json:"journal,omitempty"`
+
+	// ExpiresAt is when the request will be deleted because of spec.ttl
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// LastHandledReconcileNow is the value of the gateway.opendi.com/reconcile-now annotation
+	// for which the last forced full reconcile completed
+	// +optional
+	LastHandledReconcileNow string `json:"lastHandledReconcileNow,omitempty"`
+
+	// ConsecutiveFailures counts the reconciles that failed with an error since the last one
+	// that didn't
+	// +optional
+	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
+
+	// LastFailure is the error of the most recent failed reconcile
+	// +optional
+	LastFailure string `json:"lastFailure,omitempty"`
+
+	// TimeToReadySeconds is how long the request took to become Ready the last time, from its
+	// creation, spec change or loss of Ready
+	// +optional
+	TimeToReadySeconds int64 `json:"timeToReadySeconds,omitempty"`
+
+	// CleanupOrder is the order in which the deletion removes the DNS records and the
+	// certificate (dns-first or certificate-first), fixed when the deletion starts
+	// +optional
+	CleanupOrder string `json:"cleanupOrder,omitempty"`
+
 	// Conditions represent the latest available observations of an object's state
 	// +optional
 	// +listType=map
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// History is a bounded, oldest-first record of significant lifecycle transitions.
+	// It survives event TTLs and is readable by anyone who can read the request.
+	// +optional
+	// +kubebuilder:validation:MaxItems=20
+	History []HistoryEntry `json:"history,omitempty"`
+
+	// AWSChanges are the most recent mutating Route53 and ACM calls made for the request,
+	// oldest first, with the IDs to find them in CloudTrail or quote in an AWS support case
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	AWSChanges []AWSChange `json:"awsChanges,omitempty"`
+}
+
+// HostnameRoute is an HTTPRoute matching the hostname of a GatewayHostnameRequest
+type HostnameRoute struct {
+	// Name is the name of the HTTPRoute
+	Name string `json:"name"`
+
+	// Gateways are the Gateways the route references as parents, as <namespace>/<name>
+	// +optional
+	Gateways []string `json:"gateways,omitempty"`
+
+	// Attached is whether the route references the assigned Gateway
+	Attached bool `json:"attached"`
+}
+
+// JournalEntry is an AWS resource created for a GatewayHostnameRequest before its status
+// reflected it
+type JournalEntry struct {
+	// Time is when the resource was created
+	Time metav1.Time `json:"time"`
+
+	// Step is the provisioning step that created the resource, e.g. CertificateRequested
+	Step string `json:"step"`
+
+	// Key identifies the attempt within the step, such as the ACM idempotency token. The
+	// resource is only adopted by the same attempt.
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// Resource is the ARN of the resource
+	Resource string `json:"resource"`
+}
+
+// AWSChange is a mutating AWS call made for a GatewayHostnameRequest
+type AWSChange struct {
+	// Time is when the call was made
+	Time metav1.Time `json:"time"`
+
+	// Operation is the service and API operation, e.g. route53:ChangeResourceRecordSets
+	Operation string `json:"operation"`
+
+	// Resource is what the call changed: the hosted zone and records, or the certificate
+	// +optional
+	Resource string `json:"resource,omitempty"`
+
+	// RequestId is the AWS request ID, as recorded in CloudTrail
+	// +optional
+	RequestId string `json:"requestId,omitempty"`
+
+	// ChangeId is the Route53 change ID, for GetChange and AWS support
+	// +optional
+	ChangeId string `json:"changeId,omitempty"`
+
+	// Error is the error the call failed with, if it did
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// CostEstimate is a rough monthly cost estimate in USD, split by AWS service. Amounts are
+// decimal strings with two fractional digits. Traffic-dependent charges are not measured: the
+// load balancer share assumes the configured baseline LCUs.
+type CostEstimate struct {
+	// LoadBalancer is the share of the ALB hourly and baseline LCU charges
+	// +optional
+	LoadBalancer string `json:"loadBalancer,omitempty"`
+
+	// WAF is the share of the web ACL charge, if the Gateway has a WAF
+	// +optional
+	WAF string `json:"waf,omitempty"`
+
+	// Route53 is the share of the hosted zone charge. Queries for ALIAS records to AWS
+	// resources are free.
+	// +optional
+	Route53 string `json:"route53,omitempty"`
+
+	// Total is the sum of all shares
+	Total string `json:"total"`
+
+	// SharedWith is the number of hostnames sharing the Gateway, including this one
+	// +optional
+	SharedWith int32 `json:"sharedWith,omitempty"`
+}
+
+// HistoryEntry records a single significant transition of a GatewayHostnameRequest
+type HistoryEntry struct {
+	// Time is when the transition was observed
+	Time metav1.Time `json:"time"`
+
+	// Type is the condition type or lifecycle phase that transitioned (e.g., Ready, SpecChanged)
+	Type string `json:"type"`
+
+	// Status is the new condition status, if the transition was a condition change
+	// +optional
+	Status metav1.ConditionStatus `json:"status,omitempty"`
+
+	// Reason is a CamelCase identifier for the transition
+	Reason string `json:"reason"`
+
+	// Message is a human-readable description of the transition
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -86,6 +568,10 @@ type GatewayHostnameRequestStatus struct {
 // +kubebuilder:printcolumn:name="Hostname",type=string,JSONPath=`.spec.hostname`
 // +kubebuilder:printcolumn:name="Gateway",type=string,JSONPath=`.status.assignedGateway`
 // +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Message",type=string,JSONPath=`.status.message`,priority=1
+// +kubebuilder:printcolumn:name="Routes",type=string,JSONPath=`.status.routes[*].name`,priority=1
+// +kubebuilder:printcolumn:name="Failures",type=integer,JSONPath=`.status.consecutiveFailures`,priority=1
+// +kubebuilder:printcolumn:name="Expires",type=date,JSONPath=`.status.expiresAt`,priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // GatewayHostnameRequest is the Schema for the gatewayhostnamerequests API
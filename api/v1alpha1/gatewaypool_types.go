@@ -0,0 +1,117 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Gateway health values reported in PoolGatewayStatus
+const (
+	GatewayHealthHealthy   = "Healthy"
+	GatewayHealthUnhealthy = "Unhealthy"
+	GatewayHealthPending   = "Pending"
+)
+
+// PoolGatewayStatus summarizes one managed Gateway
+type PoolGatewayStatus struct {
+	// Name of the Gateway
+	Name string `json:"name"`
+
+	// Namespace of the Gateway
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Visibility of the ALB (internet-facing or internal)
+	// +optional
+	Visibility string `json:"visibility,omitempty"`
+
+	// WafArn associated with the ALB
+	// +optional
+	WafArn string `json:"wafArn,omitempty"`
+
+	// Hostnames assigned to the Gateway
+	// +optional
+	Hostnames []string `json:"hostnames,omitempty"`
+
+	// CertificateCount is the number of certificates on the ALB
+	CertificateCount int `json:"certificateCount"`
+
+	// RuleCount is the number of listener rules on the ALB
+	RuleCount int `json:"ruleCount"`
+
+	// RoutesByNamespace is the number of HTTPRoutes each namespace attaches to the Gateway
+	// +optional
+	RoutesByNamespace map[string]int `json:"routesByNamespace,omitempty"`
+
+	// LoadBalancerDNS is the DNS name of the ALB
+	// +optional
+	LoadBalancerDNS string `json:"loadBalancerDNS,omitempty"`
+
+	// Cordoned is the cordon reason if the Gateway takes no new hostnames
+	// +optional
+	Cordoned string `json:"cordoned,omitempty"`
+
+	// Health is derived from the Gateway's Programmed condition: Healthy, Unhealthy or Pending
+	Health string `json:"health"`
+}
+
+// GatewayPoolStatus defines the observed state of GatewayPool
+type GatewayPoolStatus struct {
+	// GatewayNamespace is the default namespace of the managed Gateways
+	// +optional
+	GatewayNamespace string `json:"gatewayNamespace,omitempty"`
+
+	// GatewayClassName is the GatewayClass of the managed Gateways
+	// +optional
+	GatewayClassName string `json:"gatewayClassName,omitempty"`
+
+	// MaxCertificatesPerGateway is the certificate limit per Gateway
+	// +optional
+	MaxCertificatesPerGateway int `json:"maxCertificatesPerGateway,omitempty"`
+
+	// MaxRoutesPerNamespace is the HTTPRoute limit per namespace and Gateway, 0 if unlimited
+	// +optional
+	MaxRoutesPerNamespace int `json:"maxRoutesPerNamespace,omitempty"`
+
+	// GatewayCount is the number of managed Gateways
+	GatewayCount int `json:"gatewayCount"`
+
+	// HostnameCount is the number of hostnames assigned across all Gateways
+	HostnameCount int `json:"hostnameCount"`
+
+	// Gateways summarizes each managed Gateway, sorted by name
+	// +optional
+	Gateways []PoolGatewayStatus `json:"gateways,omitempty"`
+
+	// LastUpdated is when the summary last changed
+	// +optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=gp
+// +kubebuilder:printcolumn:name="Gateways",type=integer,JSONPath=`.status.gatewayCount`
+// +kubebuilder:printcolumn:name="Hostnames",type=integer,JSONPath=`.status.hostnameCount`
+// +kubebuilder:printcolumn:name="Updated",type=date,JSONPath=`.status.lastUpdated`
+
+// GatewayPool is the Schema for the gatewaypools API
+// Read-only summary of a Gateway pool, maintained by the controller and named after the pool namespace
+type GatewayPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status GatewayPoolStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GatewayPoolList contains a list of GatewayPool
+type GatewayPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GatewayPool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GatewayPool{}, &GatewayPoolList{})
+}
@@ -0,0 +1,215 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OrchestratorConfigPool holds the Gateway pool settings
+type OrchestratorConfigPool struct {
+	// MaxCertificatesPerGateway is the certificate limit per Gateway. Gateways above it are
+	// cordoned (see --rebalance-over-capacity).
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxCertificatesPerGateway *int32 `json:"maxCertificatesPerGateway,omitempty"`
+
+	// GatewayCreationCooldown is the minimum time between two new Gateways (ALBs); 0s disables it
+	// +optional
+	GatewayCreationCooldown *metav1.Duration `json:"gatewayCreationCooldown,omitempty"`
+
+	// HTTPPort is the HTTP listener port of new Gateways. Existing Gateways keep their listeners.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	HTTPPort *int32 `json:"httpPort,omitempty"`
+
+	// HTTPSPort is the HTTPS listener port of new Gateways. Existing Gateways keep their listeners.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	HTTPSPort *int32 `json:"httpsPort,omitempty"`
+
+	// HTTPSOnly creates new Gateways without the HTTP listener, so their ALBs don't open the
+	// HTTP port. Requests with tls: disabled then need a spec.gatewaySelector picking a Gateway
+	// that has one. Existing Gateways keep their listeners.
+	// +optional
+	HTTPSOnly *bool `json:"httpsOnly,omitempty"`
+
+	// Subnets are the subnets of the Gateways' ALBs by visibility. Unset lets the AWS Load
+	// Balancer Controller discover them from the subnet tags.
+	// +optional
+	Subnets OrchestratorConfigSubnets `json:"subnets,omitempty"`
+
+	// Gateways the pool keeps from startup on, whether or not hostnames use them, so a new
+	// cluster comes up with its edge topology instead of creating the first Gateway on the first
+	// request. Missing ones are created when the controller starts and when the list changes.
+	// Gateways dropped from the list rejoin the pool and are deleted once they are empty.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Gateways []OrchestratorConfigGateway `json:"gateways,omitempty"`
+
+	// TargetGroup holds attributes of the target groups of all backend Services. Spec.backends
+	// of the requests tune stickiness and slow start on top.
+	// +optional
+	TargetGroup OrchestratorConfigTargetGroup `json:"targetGroup,omitempty"`
+}
+
+// OrchestratorConfigTargetGroup holds target group attributes the pool sets for every backend
+// Service. Unset attributes keep the AWS defaults.
+type OrchestratorConfigTargetGroup struct {
+	// DeregistrationDelay is how long the ALB drains a target before deregistering it, from 0s
+	// to 1h (AWS default 5m). Short delays speed up deploys of services without long requests.
+	// +kubebuilder:validation:XValidation:rule="duration(self) >= duration('0s') && duration(self) <= duration('1h')",message="deregistrationDelay must be between 0s and 1h"
+	// +optional
+	DeregistrationDelay *metav1.Duration `json:"deregistrationDelay,omitempty"`
+}
+
+// OrchestratorConfigGateway is a Gateway the pool keeps. The Gateway gets the pool's usual
+// generated name; the gateway.opendi.com/bootstrap annotation ties it to the entry.
+type OrchestratorConfigGateway struct {
+	// Name identifies the entry
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Visibility of the ALB. Fixed once the Gateway exists.
+	// +kubebuilder:validation:Enum=internet-facing;internal
+	// +kubebuilder:default=internet-facing
+	// +optional
+	Visibility string `json:"visibility,omitempty"`
+
+	// WafArn is the regional WAFv2 WebACL of the ALB. Fixed once the Gateway exists; requests
+	// are only assigned to it with the same wafArn.
+	// +kubebuilder:validation:Pattern=`^arn:aws:wafv2:[a-z0-9-]+:[0-9]+:.*$`
+	// +optional
+	WafArn string `json:"wafArn,omitempty"`
+
+	// HTTPSOnly overrides pool.httpsOnly for this Gateway. Fixed once the Gateway exists.
+	// +optional
+	HTTPSOnly *bool `json:"httpsOnly,omitempty"`
+
+	// Labels are set on the Gateway, so requests can pick it with spec.gatewaySelector
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// OrchestratorConfigSubnets lists subnet IDs per ALB scheme
+type OrchestratorConfigSubnets struct {
+	// InternetFacing are the subnets of internet-facing ALBs, usually public ones
+	// +kubebuilder:validation:items:Pattern=`^subnet-[0-9a-f]+$`
+	// +optional
+	InternetFacing []string `json:"internetFacing,omitempty"`
+
+	// Internal are the subnets of internal ALBs
+	// +kubebuilder:validation:items:Pattern=`^subnet-[0-9a-f]+$`
+	// +optional
+	Internal []string `json:"internal,omitempty"`
+}
+
+// OrchestratorConfigRequeue tunes how often the controller looks at requests that wait for AWS
+type OrchestratorConfigRequeue struct {
+	// CertificatePollBackoff are the delays between ACM checks while a certificate is pending
+	// issuance; the last one repeats
+	// +kubebuilder:validation:MinItems=1
+	// +optional
+	CertificatePollBackoff []metav1.Duration `json:"certificatePollBackoff,omitempty"`
+}
+
+// OrchestratorConfigSpec defines the controller settings. Unset fields keep the value of the
+// corresponding command-line flag.
+type OrchestratorConfigSpec struct {
+	// Pool holds the Gateway pool settings
+	// +optional
+	Pool OrchestratorConfigPool `json:"pool,omitempty"`
+
+	// CertificateTags are added to the ACM certificates the controller requests or imports.
+	// The controller's own tags (hostname, namespace, name) take precedence.
+	// Changing them only affects new certificates.
+	// +kubebuilder:validation:MaxProperties=40
+	// +optional
+	CertificateTags map[string]string `json:"certificateTags,omitempty"`
+
+	// AllowedDomains restricts the hostnames requests may use to these domains and their
+	// subdomains (e.g., example.com allows example.com and api.example.com). Requests for other
+	// hostnames fail validation. Empty allows all hostnames.
+	// +optional
+	AllowedDomains []string `json:"allowedDomains,omitempty"`
+
+	// DefaultWafArn is the regional WAFv2 WebACL of requests that set no spec.wafArn, neither
+	// themselves nor through their namespace. DNS-only requests have no ALB and get none.
+	// +kubebuilder:validation:Pattern=`^arn:aws:wafv2:[a-z0-9-]+:[0-9]+:.*$`
+	// +kubebuilder:validation:XValidation:rule="self.matches(':regional/webacl/')",message="defaultWafArn must be a regional WebACL; global (CloudFront) WebACLs cannot be associated with an ALB"
+	// +optional
+	DefaultWafArn string `json:"defaultWafArn,omitempty"`
+
+	// Requeue tunes polling intervals
+	// +optional
+	Requeue OrchestratorConfigRequeue `json:"requeue,omitempty"`
+
+	// PrivateZoneVpcs are VPCs, e.g. peered or spoke VPCs, that private hosted zones of
+	// requests with visibility internal are associated with, so internal hostnames resolve
+	// there too. The controller removes the associations it created when the last internal
+	// request in the zone goes away or the VPC is dropped from the list.
+	// +listType=map
+	// +listMapKey=vpcId
+	// +optional
+	PrivateZoneVpcs []OrchestratorConfigVPC `json:"privateZoneVpcs,omitempty"`
+}
+
+// OrchestratorConfigVPC is a VPC private hosted zones are associated with. VPCs of other
+// accounts need a VPC association authorization from the zone's account first.
+type OrchestratorConfigVPC struct {
+	// VpcId is the VPC ID
+	// +kubebuilder:validation:Pattern=`^vpc-[0-9a-f]+$`
+	VpcId string `json:"vpcId"`
+
+	// Region is the region of the VPC
+	// +kubebuilder:validation:Pattern=`^[a-z]{2}(-[a-z]+)+-[0-9]$`
+	Region string `json:"region"`
+}
+
+// OrchestratorConfigStatus defines the observed state of OrchestratorConfig
+type OrchestratorConfigStatus struct {
+	// ObservedGeneration is the generation that was last applied
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions: Applied is True once the spec is in effect, False if it was rejected
+	// (the previous settings stay in effect)
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=oc
+// +kubebuilder:printcolumn:name="Applied",type=string,JSONPath=`.status.conditions[?(@.type=="Applied")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// OrchestratorConfig is the Schema for the orchestratorconfigs API
+// Cluster-wide controller settings, read from the object the spec.parametersRef of the pool's
+// GatewayClass points at, or else the one named by --orchestrator-config.
+// They override the command-line flags and are applied without a restart.
+type OrchestratorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OrchestratorConfigSpec   `json:"spec,omitempty"`
+	Status OrchestratorConfigStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OrchestratorConfigList contains a list of OrchestratorConfig
+type OrchestratorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OrchestratorConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OrchestratorConfig{}, &OrchestratorConfigList{})
+}
@@ -0,0 +1,101 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OrphanSpec defines the AWS resources a force-deleted request left behind
+type OrphanSpec struct {
+	// Request references the deleted GatewayHostnameRequest
+	// +kubebuilder:validation:Required
+	Request DomainClaimOwnerRef `json:"request"`
+
+	// Hostname of the deleted request
+	// +kubebuilder:validation:Required
+	Hostname string `json:"hostname"`
+
+	// Reason explains why the cleanup was abandoned
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Records are the Route53 records that may still exist
+	// +optional
+	Records []OrphanRecord `json:"records,omitempty"`
+
+	// CertificateArn is the ACM certificate that may still exist. Its validation records are
+	// deleted with it.
+	// +optional
+	CertificateArn string `json:"certificateArn,omitempty"`
+
+	// CertificateZoneId is the hosted zone of the certificate's validation records
+	// +optional
+	CertificateZoneId string `json:"certificateZoneId,omitempty"`
+}
+
+// OrphanRecord is a Route53 record left behind
+type OrphanRecord struct {
+	// ZoneId is the Route53 hosted zone ID
+	ZoneId string `json:"zoneId"`
+
+	// Name of the record
+	Name string `json:"name"`
+
+	// Type of the record, e.g. A or AAAA
+	Type string `json:"type"`
+
+	// AliasTarget is the load balancer the record pointed at. A record pointing elsewhere
+	// belongs to someone else now and is left alone.
+	// +optional
+	AliasTarget string `json:"aliasTarget,omitempty"`
+
+	// SetIdentifier of a weighted record of a request with sharedOwnership
+	// +optional
+	SetIdentifier string `json:"setIdentifier,omitempty"`
+}
+
+// OrphanStatus defines the observed state of Orphan
+type OrphanStatus struct {
+	// Attempts counts the sweeps that failed to delete the resources
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// LastAttemptTime is when a sweep last tried to delete the resources
+	// +optional
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+
+	// LastError is why the last sweep couldn't delete the resources
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Hostname",type=string,JSONPath=`.spec.hostname`
+// +kubebuilder:printcolumn:name="Namespace",type=string,JSONPath=`.spec.request.namespace`
+// +kubebuilder:printcolumn:name="Attempts",type=integer,JSONPath=`.status.attempts`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Orphan is the Schema for the orphans API
+// Records the AWS resources of a GatewayHostnameRequest whose finalizer was removed after
+// --force-delete-after although its cleanup kept failing. The orphan sweep deletes them.
+type Orphan struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OrphanSpec   `json:"spec,omitempty"`
+	Status OrphanStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OrphanList contains a list of Orphan
+type OrphanList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Orphan `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Orphan{}, &OrphanList{})
+}
@@ -25,12 +25,83 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSChange) DeepCopyInto(out *AWSChange) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSChange.
+func (in *AWSChange) DeepCopy() *AWSChange {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AliasTarget) DeepCopyInto(out *AliasTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AliasTarget.
+func (in *AliasTarget) DeepCopy() *AliasTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(AliasTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackendSettings) DeepCopyInto(out *BackendSettings) {
+	*out = *in
+	if in.Stickiness != nil {
+		in, out := &in.Stickiness, &out.Stickiness
+		*out = new(Stickiness)
+		**out = **in
+	}
+	if in.SlowStartSeconds != nil {
+		in, out := &in.SlowStartSeconds, &out.SlowStartSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackendSettings.
+func (in *BackendSettings) DeepCopy() *BackendSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(BackendSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostEstimate) DeepCopyInto(out *CostEstimate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostEstimate.
+func (in *CostEstimate) DeepCopy() *CostEstimate {
+	if in == nil {
+		return nil
+	}
+	out := new(CostEstimate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DomainClaim) DeepCopyInto(out *DomainClaim) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -84,6 +155,22 @@ func (in *DomainClaimList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DomainClaimOwner) DeepCopyInto(out *DomainClaimOwner) {
+	*out = *in
+	out.DomainClaimOwnerRef = in.DomainClaimOwnerRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainClaimOwner.
+func (in *DomainClaimOwner) DeepCopy() *DomainClaimOwner {
+	if in == nil {
+		return nil
+	}
+	out := new(DomainClaimOwner)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DomainClaimOwnerRef) DeepCopyInto(out *DomainClaimOwnerRef) {
 	*out = *in
@@ -103,6 +190,11 @@ func (in *DomainClaimOwnerRef) DeepCopy() *DomainClaimOwnerRef {
 func (in *DomainClaimSpec) DeepCopyInto(out *DomainClaimSpec) {
 	*out = *in
 	out.OwnerRef = in.OwnerRef
+	if in.Owners != nil {
+		in, out := &in.Owners, &out.Owners
+		*out = make([]DomainClaimOwner, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DomainClaimSpec.
@@ -196,11 +288,48 @@ func (in *GatewayHostnameRequestList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayHostnameRequestSpec) DeepCopyInto(out *GatewayHostnameRequestSpec) {
 	*out = *in
+	if in.AdditionalZoneIds != nil {
+		in, out := &in.AdditionalZoneIds, &out.AdditionalZoneIds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.GatewaySelector != nil {
 		in, out := &in.GatewaySelector, &out.GatewaySelector
 		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AliasTarget != nil {
+		in, out := &in.AliasTarget, &out.AliasTarget
+		*out = new(AliasTarget)
+		**out = **in
+	}
+	if in.SecurityHeaders != nil {
+		in, out := &in.SecurityHeaders, &out.SecurityHeaders
+		*out = new(SecurityHeaders)
+		**out = **in
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]BackendSettings, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OverrideTarget != nil {
+		in, out := &in.OverrideTarget, &out.OverrideTarget
+		*out = new(AliasTarget)
+		**out = **in
+	}
+	if in.SharedOwnership != nil {
+		in, out := &in.SharedOwnership, &out.SharedOwnership
+		*out = new(SharedOwnership)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayHostnameRequestSpec.
@@ -216,6 +345,62 @@ func (in *GatewayHostnameRequestSpec) DeepCopy() *GatewayHostnameRequestSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayHostnameRequestStatus) DeepCopyInto(out *GatewayHostnameRequestStatus) {
 	*out = *in
+	if in.NextFullReconcileTime != nil {
+		in, out := &in.NextFullReconcileTime, &out.NextFullReconcileTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AliasZoneIds != nil {
+		in, out := &in.AliasZoneIds, &out.AliasZoneIds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AliasRecordTypes != nil {
+		in, out := &in.AliasRecordTypes, &out.AliasRecordTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AliasWeight != nil {
+		in, out := &in.AliasWeight, &out.AliasWeight
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PrivateZoneVpcs != nil {
+		in, out := &in.PrivateZoneVpcs, &out.PrivateZoneVpcs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CertificateNotAfter != nil {
+		in, out := &in.CertificateNotAfter, &out.CertificateNotAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.CertificateSANs != nil {
+		in, out := &in.CertificateSANs, &out.CertificateSANs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]HostnameRoute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CostEstimate != nil {
+		in, out := &in.CostEstimate, &out.CostEstimate
+		*out = new(CostEstimate)
+		**out = **in
+	}
+	if in.Journal != nil {
+		in, out := &in.Journal, &out.Journal
+		*out = make([]JournalEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -223,6 +408,20 @@ func (in *GatewayHostnameRequestStatus) DeepCopyInto(out *GatewayHostnameRequest
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]HistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AWSChanges != nil {
+		in, out := &in.AWSChanges, &out.AWSChanges
+		*out = make([]AWSChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayHostnameRequestStatus.
@@ -235,6 +434,106 @@ func (in *GatewayHostnameRequestStatus) DeepCopy() *GatewayHostnameRequestStatus
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayPool) DeepCopyInto(out *GatewayPool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayPool.
+func (in *GatewayPool) DeepCopy() *GatewayPool {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GatewayPool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayPoolList) DeepCopyInto(out *GatewayPoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GatewayPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayPoolList.
+func (in *GatewayPoolList) DeepCopy() *GatewayPoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayPoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GatewayPoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayPoolStatus) DeepCopyInto(out *GatewayPoolStatus) {
+	*out = *in
+	if in.Gateways != nil {
+		in, out := &in.Gateways, &out.Gateways
+		*out = make([]PoolGatewayStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayPoolStatus.
+func (in *GatewayPoolStatus) DeepCopy() *GatewayPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HistoryEntry) DeepCopyInto(out *HistoryEntry) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HistoryEntry.
+func (in *HistoryEntry) DeepCopy() *HistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(HistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostnameGrant) DeepCopyInto(out *HostnameGrant) {
 	*out = *in
@@ -332,3 +631,497 @@ func (in *HostnameGrantStatus) DeepCopy() *HostnameGrantStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostnameRoute) DeepCopyInto(out *HostnameRoute) {
+	*out = *in
+	if in.Gateways != nil {
+		in, out := &in.Gateways, &out.Gateways
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostnameRoute.
+func (in *HostnameRoute) DeepCopy() *HostnameRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(HostnameRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JournalEntry) DeepCopyInto(out *JournalEntry) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JournalEntry.
+func (in *JournalEntry) DeepCopy() *JournalEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(JournalEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrchestratorConfig) DeepCopyInto(out *OrchestratorConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrchestratorConfig.
+func (in *OrchestratorConfig) DeepCopy() *OrchestratorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OrchestratorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OrchestratorConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrchestratorConfigGateway) DeepCopyInto(out *OrchestratorConfigGateway) {
+	*out = *in
+	if in.HTTPSOnly != nil {
+		in, out := &in.HTTPSOnly, &out.HTTPSOnly
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrchestratorConfigGateway.
+func (in *OrchestratorConfigGateway) DeepCopy() *OrchestratorConfigGateway {
+	if in == nil {
+		return nil
+	}
+	out := new(OrchestratorConfigGateway)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrchestratorConfigList) DeepCopyInto(out *OrchestratorConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OrchestratorConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrchestratorConfigList.
+func (in *OrchestratorConfigList) DeepCopy() *OrchestratorConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(OrchestratorConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OrchestratorConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrchestratorConfigPool) DeepCopyInto(out *OrchestratorConfigPool) {
+	*out = *in
+	if in.MaxCertificatesPerGateway != nil {
+		in, out := &in.MaxCertificatesPerGateway, &out.MaxCertificatesPerGateway
+		*out = new(int32)
+		**out = **in
+	}
+	if in.GatewayCreationCooldown != nil {
+		in, out := &in.GatewayCreationCooldown, &out.GatewayCreationCooldown
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.HTTPPort != nil {
+		in, out := &in.HTTPPort, &out.HTTPPort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.HTTPSPort != nil {
+		in, out := &in.HTTPSPort, &out.HTTPSPort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.HTTPSOnly != nil {
+		in, out := &in.HTTPSOnly, &out.HTTPSOnly
+		*out = new(bool)
+		**out = **in
+	}
+	in.Subnets.DeepCopyInto(&out.Subnets)
+	if in.Gateways != nil {
+		in, out := &in.Gateways, &out.Gateways
+		*out = make([]OrchestratorConfigGateway, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.TargetGroup.DeepCopyInto(&out.TargetGroup)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrchestratorConfigPool.
+func (in *OrchestratorConfigPool) DeepCopy() *OrchestratorConfigPool {
+	if in == nil {
+		return nil
+	}
+	out := new(OrchestratorConfigPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrchestratorConfigRequeue) DeepCopyInto(out *OrchestratorConfigRequeue) {
+	*out = *in
+	if in.CertificatePollBackoff != nil {
+		in, out := &in.CertificatePollBackoff, &out.CertificatePollBackoff
+		*out = make([]v1.Duration, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrchestratorConfigRequeue.
+func (in *OrchestratorConfigRequeue) DeepCopy() *OrchestratorConfigRequeue {
+	if in == nil {
+		return nil
+	}
+	out := new(OrchestratorConfigRequeue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrchestratorConfigSpec) DeepCopyInto(out *OrchestratorConfigSpec) {
+	*out = *in
+	in.Pool.DeepCopyInto(&out.Pool)
+	if in.CertificateTags != nil {
+		in, out := &in.CertificateTags, &out.CertificateTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AllowedDomains != nil {
+		in, out := &in.AllowedDomains, &out.AllowedDomains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Requeue.DeepCopyInto(&out.Requeue)
+	if in.PrivateZoneVpcs != nil {
+		in, out := &in.PrivateZoneVpcs, &out.PrivateZoneVpcs
+		*out = make([]OrchestratorConfigVPC, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrchestratorConfigSpec.
+func (in *OrchestratorConfigSpec) DeepCopy() *OrchestratorConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OrchestratorConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrchestratorConfigStatus) DeepCopyInto(out *OrchestratorConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrchestratorConfigStatus.
+func (in *OrchestratorConfigStatus) DeepCopy() *OrchestratorConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OrchestratorConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrchestratorConfigSubnets) DeepCopyInto(out *OrchestratorConfigSubnets) {
+	*out = *in
+	if in.InternetFacing != nil {
+		in, out := &in.InternetFacing, &out.InternetFacing
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Internal != nil {
+		in, out := &in.Internal, &out.Internal
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrchestratorConfigSubnets.
+func (in *OrchestratorConfigSubnets) DeepCopy() *OrchestratorConfigSubnets {
+	if in == nil {
+		return nil
+	}
+	out := new(OrchestratorConfigSubnets)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrchestratorConfigTargetGroup) DeepCopyInto(out *OrchestratorConfigTargetGroup) {
+	*out = *in
+	if in.DeregistrationDelay != nil {
+		in, out := &in.DeregistrationDelay, &out.DeregistrationDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrchestratorConfigTargetGroup.
+func (in *OrchestratorConfigTargetGroup) DeepCopy() *OrchestratorConfigTargetGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(OrchestratorConfigTargetGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrchestratorConfigVPC) DeepCopyInto(out *OrchestratorConfigVPC) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrchestratorConfigVPC.
+func (in *OrchestratorConfigVPC) DeepCopy() *OrchestratorConfigVPC {
+	if in == nil {
+		return nil
+	}
+	out := new(OrchestratorConfigVPC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Orphan) DeepCopyInto(out *Orphan) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Orphan.
+func (in *Orphan) DeepCopy() *Orphan {
+	if in == nil {
+		return nil
+	}
+	out := new(Orphan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Orphan) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrphanList) DeepCopyInto(out *OrphanList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Orphan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanList.
+func (in *OrphanList) DeepCopy() *OrphanList {
+	if in == nil {
+		return nil
+	}
+	out := new(OrphanList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OrphanList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrphanRecord) DeepCopyInto(out *OrphanRecord) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanRecord.
+func (in *OrphanRecord) DeepCopy() *OrphanRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(OrphanRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrphanSpec) DeepCopyInto(out *OrphanSpec) {
+	*out = *in
+	out.Request = in.Request
+	if in.Records != nil {
+		in, out := &in.Records, &out.Records
+		*out = make([]OrphanRecord, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanSpec.
+func (in *OrphanSpec) DeepCopy() *OrphanSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OrphanSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrphanStatus) DeepCopyInto(out *OrphanStatus) {
+	*out = *in
+	if in.LastAttemptTime != nil {
+		in, out := &in.LastAttemptTime, &out.LastAttemptTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrphanStatus.
+func (in *OrphanStatus) DeepCopy() *OrphanStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OrphanStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolGatewayStatus) DeepCopyInto(out *PoolGatewayStatus) {
+	*out = *in
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RoutesByNamespace != nil {
+		in, out := &in.RoutesByNamespace, &out.RoutesByNamespace
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolGatewayStatus.
+func (in *PoolGatewayStatus) DeepCopy() *PoolGatewayStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolGatewayStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityHeaders) DeepCopyInto(out *SecurityHeaders) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityHeaders.
+func (in *SecurityHeaders) DeepCopy() *SecurityHeaders {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityHeaders)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedOwnership) DeepCopyInto(out *SharedOwnership) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedOwnership.
+func (in *SharedOwnership) DeepCopy() *SharedOwnership {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedOwnership)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Stickiness) DeepCopyInto(out *Stickiness) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Stickiness.
+func (in *Stickiness) DeepCopy() *Stickiness {
+	if in == nil {
+		return nil
+	}
+	out := new(Stickiness)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,30 @@
+//go:build faultinject
+
+package main
+
+import (
+	"os"
+	"time"
+
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+// injectAWSFaults wraps the AWS clients with the faults in AWS_FAULTS, in the format of
+// aws.ParseFaults. Only builds with the faultinject tag include it.
+func injectAWSFaults(acmClient aws.ACMClient, route53Client aws.Route53Client) (aws.ACMClient, aws.Route53Client) {
+	spec := os.Getenv("AWS_FAULTS")
+	if spec == "" {
+		return acmClient, route53Client
+	}
+	faults, err := aws.ParseFaults(spec)
+	if err != nil {
+		setupLog.Error(err, "invalid AWS_FAULTS")
+		os.Exit(1)
+	}
+	injector := aws.NewFaultInjector(time.Now().UnixNano())
+	for operation, fault := range faults {
+		injector.Set(operation, fault)
+	}
+	setupLog.Info("WARNING: injecting AWS faults, do not use this build in production", "faults", spec)
+	return aws.NewFaultInjectingACMClient(acmClient, injector), aws.NewFaultInjectingRoute53Client(route53Client, injector)
+}
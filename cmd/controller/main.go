@@ -3,23 +3,43 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
+	"net"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/config"
 	"go.uber.org/zap/zapcore"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+	gwapisxv1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
 
 	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/acme"
 	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
 	"github.com/michelfeldheim/gateway-orchestrator/internal/controller"
 	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/notify"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/policy"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/probe"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/secondarydns"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/webhook"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -32,27 +52,401 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	utilruntime.Must(gatewayv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(gwapiv1.AddToScheme(scheme))
+	utilruntime.Must(gwapiv1beta1.AddToScheme(scheme))
+	utilruntime.Must(gwapisxv1alpha1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
+	var leaderElectionID string
+	var watchNamespaces string
 	var probeAddr string
+	var inventoryAddr string
+	var adminAddr string
+	var adminBulkPerMinute int
 	var gatewayNamespace string
 	var gatewayClassName string
 	var httpPort int
 	var httpsPort int
+	var httpsOnly bool
+	var enableWebhooks bool
+	var profile string
+	var logStepVerbosity string
+	var listenerHostnames bool
+	var listenerMode string
+	var namespaceProtection string
+	var probeInterval time.Duration
+	var probeTimeout time.Duration
+	var probeConcurrency int
+	var canaryHostname string
+	var canaryZoneID string
+	var canaryNamespace string
+	var canaryInterval time.Duration
+	var canaryRecycleAfter time.Duration
+	var canaryProvisionTimeout time.Duration
+	var maxCertificates int
+	var gatewayCreationCooldown time.Duration
+	var rebalanceOverCapacity bool
+	var maxRoutesPerNamespace int
+	var manageDelegatedZones bool
+	var orchestratorConfig string
+	var repairRouteParentRefs bool
+	var strictRouteAccess bool
+	var rebalanceDrainPeriod time.Duration
+	var forceDeleteAfter time.Duration
+	var cleanupOrder string
+	var cleanupStepDelay time.Duration
+	var fullReconcileInterval time.Duration
+	var awsBackend string
+	var shadow bool
+	var awsFakeState string
+	var route53ZoneBudgets string
+	var route53DefaultBudget string
+	var secondaryDNSZones string
+	var dnssecChecks bool
+	var ipAddressType string
+	var namespaceAccess string
+	var gatewayLabelKeys string
+	var internalDomainSuffixes string
+	var overrideTargetGroups string
+	var quarantineApproverGroups string
+	var interopTags string
+	var foreignOwnerTags string
+	var quarantinePatterns string
+	var quarantineExemptDomains string
+	var quarantinePunycode bool
+	var claimStore string
+	var claimTable string
+	var impactConfirmationThreshold int
+	var maxReprovisionsPerMinute int
+	var certificatePollBackoff string
+	var gatewayNamespaceByVisibility string
+	var backendReferenceGrants bool
+	var targetType string
+	var clusterName string
+	var clusterEnvironment string
+	var gatewayNameTemplate string
+	var lbcNameTemplate string
+	var tgcNameTemplate string
+	var loadBalancerNameTemplate string
+	var defaultHSTS string
+	var acmeDirectory string
+	var acmeEmail string
+	var acmeAccountKey string
+	var acmeFallback bool
+	var acmeRenewBefore time.Duration
+	var expandCertificates bool
+	var defaultFrameOptions string
+	var notifyWebhookURL string
+	var notifySlackWebhookURL string
+	var notifySNSTopicArn string
+	var notifyEvents string
+	var policyWebhookURL string
+	var policyWebhookTimeout time.Duration
+	var policyFailurePolicy string
+	var costEstimates bool
+	var costPricing string
+	var duplicateCertificateCheckInterval time.Duration
+	var fleetHealthInterval time.Duration
+	var failedThreshold int
+	var conditionFailureThreshold int
+	var verifyExistingDNS bool
+	var readyObjective float64
+	var sloReportInterval time.Duration
+	var sloWindows string
+	var timeToReadyObjective time.Duration
+	var provisioningObjective float64
+	var sloReportConfigMap string
+	var deleteDuplicateCertificates bool
+	var gatewayFailoverInterval time.Duration
+	var gatewayFailureThreshold int
+	var loadBalancerAlarmsInterval time.Duration
+	var zonalShiftDuration time.Duration
+	var loadBalancerAlarmActions string
+	var alarmErrorRatePercent float64
+	var alarmConnectionErrors float64
+	var accessLogBucket string
+	var accessLogPrefix string
+	var accessLogInterval time.Duration
+	var inventoryExportBucket string
+	var inventoryExportKey string
+	var inventoryExportInterval time.Duration
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.StringVar(&inventoryAddr, "inventory-bind-address", "",
+		"The address the read-only hostname inventory API (/v1/hostnames) binds to, e.g. :8082 (empty disables). "+
+			"If INVENTORY_TOKEN is set, callers must send it as a bearer token.")
+	flag.StringVar(&adminAddr, "admin-bind-address", "",
+		"The address the admin API for manual operations (/v1/operations/) binds to, e.g. :8083 (empty disables). "+
+			"Callers must send ADMIN_TOKEN as a bearer token.")
+	flag.IntVar(&adminBulkPerMinute, "admin-bulk-per-minute", controller.DefaultBulkPerMinute,
+		"How many requests the bulk operations of the admin API change per minute.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&leaderElectionID, "leader-election-id", "gateway-orchestrator.opendi.com",
+		"Name of the leader election lease. Instances with different --watch-namespaces need different IDs.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "",
+		"Comma-separated namespaces whose GatewayHostnameRequests are reconciled (empty watches all namespaces). "+
+			"Only these and the Gateway namespaces are cached, so namespaced RBAC suffices.")
 	flag.StringVar(&gatewayNamespace, "gateway-namespace", "edge", "Namespace where Gateway resources are managed.")
+	flag.StringVar(&gatewayNamespaceByVisibility, "gateway-namespace-by-visibility", "",
+		"Place Gateways of a visibility in their own namespace, as <visibility>=<namespace>, comma-separated "+
+			"(e.g. internal=edge-internal). Others use --gateway-namespace.")
+	flag.BoolVar(&backendReferenceGrants, "backend-reference-grants", false,
+		"Create a ReferenceGrant in each request namespace allowing HTTPRoutes in the Gateway namespace to use its Services as backends.")
+	flag.StringVar(&defaultHSTS, "default-hsts", "",
+		"Strict-Transport-Security value enforced on the HTTPRoutes of every hostname unless spec.securityHeaders overrides it "+
+			"(e.g. \"max-age=31536000; includeSubDomains\"). Empty adds no header.")
+	flag.StringVar(&defaultFrameOptions, "default-frame-options", "",
+		"X-Frame-Options value (DENY or SAMEORIGIN) enforced on the HTTPRoutes of every hostname unless spec.securityHeaders overrides it. Empty adds no header.")
+	flag.StringVar(&acmeDirectory, "acme-directory", "",
+		"ACME directory URL for certificates with certificateIssuer ACME, imported into ACM "+
+			"(e.g. "+acme.LetsEncryptDirectory+"). Empty disables ACME.")
+	flag.StringVar(&acmeEmail, "acme-email", "", "Contact email registered with the ACME account.")
+	flag.StringVar(&acmeAccountKey, "acme-account-key", "",
+		"Path to the PEM-encoded ACME account private key (ECDSA or RSA), e.g. mounted from a Secret.")
+	flag.BoolVar(&acmeFallback, "acme-fallback", false,
+		"Issue certificates through ACME when ACM rejects a request because a certificate quota is exceeded.")
+	flag.DurationVar(&acmeRenewBefore, "acme-renew-before", controller.DefaultACMERenewBefore,
+		"How long before expiry ACME certificates are renewed.")
+	flag.BoolVar(&expandCertificates, "expand-certificates", false,
+		"Add hostnames under another request's hostname in the same namespace to that request's ACM "+
+			"certificate as subject alternative names instead of requesting a certificate for each.")
+	flag.StringVar(&notifyWebhookURL, "notify-webhook-url", "",
+		"URL that lifecycle events are POSTed to as JSON (empty disables).")
+	flag.StringVar(&notifySlackWebhookURL, "notify-slack-webhook-url", "",
+		"Slack incoming webhook URL that lifecycle events are posted to (empty disables).")
+	flag.StringVar(&notifySNSTopicArn, "notify-sns-topic-arn", "",
+		"SNS topic ARN that lifecycle events are published to as JSON (empty disables).")
+	flag.StringVar(&notifyEvents, "notify-events", "",
+		"Comma-separated lifecycle events to notify about: "+strings.Join(notify.EventTypes, ", ")+". Empty sends all.")
+	flag.StringVar(&policyWebhookURL, "policy-webhook-url", "",
+		"URL of a hostname policy webhook that is asked, before a hostname is claimed, whether the request may be provisioned "+
+			"and which annotations to add (empty disables).")
+	flag.DurationVar(&policyWebhookTimeout, "policy-webhook-timeout", policy.DefaultTimeout, "Timeout of a hostname policy webhook call.")
+	flag.StringVar(&policyFailurePolicy, "policy-webhook-failure-policy", policy.FailurePolicyFail,
+		"What happens when the hostname policy webhook fails: Fail keeps requests waiting and retries, Ignore provisions them.")
+	flag.BoolVar(&costEstimates, "cost-estimates", false,
+		"Estimate each request's monthly share of ALB, WAF and Route53 charges in status.costEstimate and as metrics.")
+	flag.StringVar(&costPricing, "cost-pricing", "",
+		"Override the us-east-1 list prices used for cost estimates, as <price>=<USD>, comma-separated "+
+			"(albHourly, lcuHourly, baselineLCUs, wafWebACLMonthly, route53ZoneMonthly; e.g. albHourly=0.0252).")
+	flag.StringVar(&targetType, "target-type", gateway.TargetTypeIP,
+		"ALB target type rendered into TargetGroupConfigurations for HTTPRoute backends: ip, or instance for clusters without VPC-routable pod IPs.")
+	flag.StringVar(&clusterName, "cluster-name", "",
+		"Name of this cluster, available as {{.Cluster}} in the name templates.")
+	flag.StringVar(&clusterEnvironment, "cluster-environment", "",
+		"Environment of this cluster (e.g. prod), available as {{.Environment}} in the name templates.")
+	flag.StringVar(&gatewayNameTemplate, "gateway-name-template", gateway.DefaultGatewayNameTemplate,
+		"Go template for the names of created Gateways; must use {{.Index}}. Existing Gateways keep their names.")
+	flag.StringVar(&lbcNameTemplate, "load-balancer-configuration-name-template", gateway.DefaultLoadBalancerConfigurationNameTemplate,
+		"Go template for LoadBalancerConfiguration names, from {{.Gateway}}. Must not change while Gateways exist.")
+	flag.StringVar(&tgcNameTemplate, "target-group-configuration-name-template", gateway.DefaultTargetGroupConfigurationNameTemplate,
+		"Go template for the names of created TargetGroupConfigurations, from {{.Service}} and {{.Namespace}}.")
+	flag.StringVar(&loadBalancerNameTemplate, "load-balancer-name-template", "",
+		"Go template for the AWS names of new ALBs, from {{.Gateway}} and {{.Namespace}} (at most 32 characters; "+
+			"empty lets the AWS Load Balancer Controller choose), e.g. {{.Cluster}}-{{.Gateway}}.")
+	flag.StringVar(&orchestratorConfig, "orchestrator-config", controller.DefaultOrchestratorConfigName,
+		"Name of the cluster-scoped OrchestratorConfig whose settings override the pool, certificate tag, domain allowlist "+
+			"and requeue flags at runtime (empty disables). A spec.parametersRef of the --gateway-class GatewayClass pointing "+
+			"at an OrchestratorConfig takes precedence.")
 	flag.StringVar(&gatewayClassName, "gateway-class", "aws-alb", "GatewayClass name to use for new Gateways.")
 	flag.IntVar(&httpPort, "http-port", 80, "HTTP listener port for created Gateways.")
 	flag.IntVar(&httpsPort, "https-port", 443, "HTTPS listener port for created Gateways.")
+	flag.BoolVar(&httpsOnly, "https-only", false,
+		"Create Gateways without the HTTP listener, so their ALBs don't open the HTTP port. Requests with tls: disabled "+
+			"are rejected unless their gatewaySelector picks a Gateway with one.")
+	flag.IntVar(&maxCertificates, "max-certificates-per-gateway", gateway.MaxCertificatesPerGateway,
+		"Certificate limit per Gateway. Gateways above it are cordoned (no new hostnames).")
+	flag.DurationVar(&gatewayCreationCooldown, "gateway-creation-cooldown", 0,
+		"Minimum time between two new Gateways (ALBs). Requests needing a new Gateway wait in order of creation (0 disables).")
+	flag.IntVar(&maxRoutesPerNamespace, "max-routes-per-namespace", 0,
+		"HTTPRoutes one namespace may attach to each Gateway, so a single tenant can't use up the ALB's listener rules "+
+			"(0 disables). Enforced at admission with --enable-webhooks; always reported in the GatewayPool summary.")
+	flag.BoolVar(&rebalanceOverCapacity, "rebalance-over-capacity", false,
+		"Move the newest hostnames off Gateways above --max-certificates-per-gateway. "+
+			"Their status.assignedGateway changes, so HTTPRoute parentRefs must follow (see --repair-route-parent-refs).")
+	flag.BoolVar(&manageDelegatedZones, "manage-delegated-zones", false,
+		"Create the hosted zone of a request's spec.delegatedZone when it doesn't exist, and delegate it from spec.zoneId. "+
+			"Requires route53:CreateHostedZone.")
+	flag.BoolVar(&repairRouteParentRefs, "repair-route-parent-refs", false,
+		"Update the parentRefs of HTTPRoutes serving a hostname when it moves to another Gateway.")
+	flag.BoolVar(&strictRouteAccess, "strict-route-access", false,
+		"Don't mark a request Ready while labeling its namespace or configuring the Gateway's allowedRoutes fails, "+
+			"reporting both steps as NamespaceLabeled and AllowedRoutesConfigured conditions. Will become the default.")
+	flag.DurationVar(&rebalanceDrainPeriod, "rebalance-drain-period", controller.DefaultRebalanceDrainPeriod,
+		"How long the old Gateway keeps serving a moved hostname after DNS points at the new one.")
+	flag.DurationVar(&forceDeleteAfter, "force-delete-after", 0,
+		"Remove the finalizer of a request whose AWS cleanup has been failing this long, recording the leftovers in an "+
+			"Orphan for the orphan sweep (0 disables). Requests can override it with gateway.opendi.com/force-delete-after.")
+	flag.StringVar(&cleanupOrder, "cleanup-order", controller.CleanupOrderDNSFirst,
+		"Order in which deletions remove a hostname: dns-first deletes the DNS records before detaching the certificate, "+
+			"certificate-first waits until the ALB released the certificate before deleting the DNS records.")
+	flag.DurationVar(&cleanupStepDelay, "cleanup-step-delay", 0,
+		"How long deletions wait between deleting the DNS records and detaching the certificate, in either order "+
+			"(e.g. the record TTL, so cached answers expire before the ALB stops serving the hostname).")
+	flag.DurationVar(&fullReconcileInterval, "full-reconcile-interval", controller.DefaultFullReconcileInterval,
+		"How long Ready requests whose spec, annotations, Gateway, LoadBalancerConfiguration and HTTPRoutes are unchanged "+
+			"skip reconciles and their AWS calls; drift made outside the controller is corrected after at most this long (0 disables).")
+	flag.StringVar(&certificatePollBackoff, "certificate-poll-backoff", "15s,30s,1m,5m",
+		"Comma-separated delays between ACM checks while a certificate is pending issuance; the last one repeats.")
+	flag.IntVar(&impactConfirmationThreshold, "confirm-gateway-changes-above", 0,
+		"Hold back WAF changes from one request that would reconfigure an ALB shared with more than this many "+
+			"other hostnames until the request is annotated with gateway.opendi.com/confirm-gateway-change=<gateway> (0 disables).")
+	flag.IntVar(&maxReprovisionsPerMinute, "max-reprovisions-per-minute", 0,
+		"Let at most this many requests per minute tear down their resources to re-provision after a spec change; the "+
+			"others keep their previous spec with ReprovisionPending until it is their turn (0 = unlimited).")
+	flag.StringVar(&awsBackend, "aws-backend", "aws",
+		"Where ACM certificates and Route53 records live: aws, or fake for an in-memory backend that needs no AWS "+
+			"access (sandboxes and demos only; certificates are issued once their validation records exist).")
+	flag.StringVar(&awsFakeState, "aws-fake-state", "",
+		"File the fake AWS backend keeps its state in, so it survives restarts (default: memory only).")
+	flag.BoolVar(&shadow, "shadow", false,
+		"Reconcile without changing anything, to validate a new version against the live fleet before it takes over: "+
+			"writes to the cluster, ACM and Route53 are compared with the current state and reported as "+
+			"gateway_orchestrator_shadow_* metrics instead. Uses <leader-election-id>-shadow, so it runs next to the live controller.")
+	flag.StringVar(&route53ZoneBudgets, "route53-zone-budgets", "",
+		"Per-zone Route53 write budgets as <zoneId>=<writes/sec>[:<burst>], comma-separated (e.g. Z123=0.5:3).")
+	flag.StringVar(&route53DefaultBudget, "route53-default-budget", "0",
+		"Route53 write budget for zones not listed in --route53-zone-budgets as <writes/sec>[:<burst>] (0 = unlimited).")
+	flag.StringVar(&secondaryDNSZones, "secondary-dns-zones", "",
+		"Mirror record changes in these hosted zones to a secondary DNS provider, as <zoneId>=<provider>:<zone name>, "+
+			"comma-separated (e.g. Z123=ns1:example.com). Supported providers: ns1 (API key in NS1_API_KEY).")
+	flag.BoolVar(&dnssecChecks, "dnssec-checks", false,
+		"Check the DNSSEC signing status of hosted zones before and after record changes and hold changes while "+
+			"a signed zone is degraded. Requires route53:GetDNSSEC.")
+	flag.StringVar(&ipAddressType, "ip-address-type", "",
+		"ALB IP address type for managed Gateways: ipv4, dualstack or dualstack-without-public-ipv4. "+
+			"AAAA aliases are only created for dualstack ALBs. Empty keeps the load balancer controller default (ipv4).")
+	flag.StringVar(&listenerMode, "listener-mode", controller.ListenerModeShared,
+		"How hostnames get HTTPS listeners: shared (all certificates on the Gateway's https listener), hostname "+
+			"(a dedicated listener per hostname on the Gateway) or listenerset (the dedicated listeners in an "+
+			"XListenerSet per Gateway; needs the experimental Gateway API CRDs).")
+	flag.BoolVar(&listenerHostnames, "listener-hostnames", false,
+		"Deprecated: use --listener-mode=hostname.")
+	flag.StringVar(&namespaceAccess, "namespace-access", controller.NamespaceAccessLabel,
+		"How namespaces allowed to use a Gateway are recorded: label (labels namespaces, needs namespace update permission) "+
+			"or gateway (allowlist annotation on the Gateway, namespaces are read-only).")
+	flag.StringVar(&gatewayLabelKeys, "gateway-label-keys", "",
+		"Comma-separated GatewayHostnameRequest label keys (e.g. team,tier) whose values are propagated to the assigned "+
+			"Gateway as gateway.opendi.com/<key>.<value>=<request count> labels (empty disables).")
+	flag.StringVar(&internalDomainSuffixes, "internal-domain-suffixes", "",
+		"Comma-separated domains (e.g. corp.internal) that requests may not use, in addition to cluster.local and svc. "+
+			"Such requests are rejected at admission (with --enable-webhooks) and fail validation.")
+	flag.StringVar(&overrideTargetGroups, "override-target-groups", strings.Join(webhook.DefaultOverrideTargetGroups, ","),
+		"Comma-separated groups whose members may set spec.overrideTarget, the break-glass DNS override of a hostname. "+
+			"Enforced at admission (with --enable-webhooks); without webhooks spec.overrideTarget is ignored.")
+	flag.StringVar(&quarantineApproverGroups, "quarantine-approver-groups", strings.Join(webhook.DefaultQuarantineApproverGroups, ","),
+		"Comma-separated groups whose members may approve quarantined hostnames with "+controller.AnnotationApproveQuarantine+". "+
+			"Enforced at admission (with --enable-webhooks).")
+	flag.StringVar(&interopTags, "interop-tags", "",
+		"Comma-separated <key>=<value> tags added to the ACM certificates and ALBs the controller creates, so "+
+			"infrastructure-as-code tools can recognize and ignore them (e.g. iac-ignore=true).")
+	flag.StringVar(&foreignOwnerTags, "foreign-owner-tags", controller.DefaultForeignOwnerTags,
+		"Comma-separated <key> or <key>=<value> tags marking ACM certificates owned by other tools (Terraform, Crossplane); "+
+			"the controller never deletes them. Empty disables the check.")
+	flag.StringVar(&quarantinePatterns, "quarantine-patterns", "",
+		"Comma-separated regular expressions (e.g. paypa[l1],micr[o0]s[o0]ft) matched against hostnames; matching requests "+
+			"are held with a Quarantined condition until annotated with "+controller.AnnotationApproveQuarantine+"=<hostname>.")
+	flag.StringVar(&quarantineExemptDomains, "quarantine-exempt-domains", "",
+		"Comma-separated domains (e.g. the brand's own domains) whose hostnames are never quarantined.")
+	flag.BoolVar(&quarantinePunycode, "quarantine-punycode", false,
+		"Quarantine hostnames with punycode labels that imitate Latin letters with Cyrillic or Greek homoglyphs.")
+	flag.StringVar(&claimStore, "claim-store", controller.ClaimStoreCRD,
+		"Where hostname claims are kept: crd (DomainClaim objects) or dynamodb (the --claim-table DynamoDB table, "+
+			"to keep hostnames unique across clusters and other systems sharing it; needs --cluster-name).")
+	flag.StringVar(&claimTable, "claim-table", "",
+		"DynamoDB table of --claim-store=dynamodb, with the string partition key name.")
+	flag.StringVar(&namespaceProtection, "namespace-deletion-protection", webhook.NamespaceProtectionDeny,
+		"How the namespace webhook treats deletion of namespaces with Ready hostnames: deny, warn or off.")
+	flag.DurationVar(&duplicateCertificateCheckInterval, "duplicate-certificate-check-interval", 0,
+		"Interval for checking ACM for unused orchestrator-tagged certificates of hostnames in the cluster "+
+			"(0 disables). Requires --cluster-name, acm:ListCertificates and acm:ListTagsForCertificate.")
+	flag.BoolVar(&deleteDuplicateCertificates, "delete-duplicate-certificates", false,
+		"Delete duplicate certificates found by --duplicate-certificate-check-interval instead of only reporting them.")
+	flag.DurationVar(&gatewayFailoverInterval, "gateway-failover-interval", 0,
+		"Interval for checking the ALBs of managed Gateways through ELBv2 and moving the hostnames of Gateways whose ALB "+
+			"failed or was deleted to a replacement Gateway (0 disables). Requires elasticloadbalancing:DescribeLoadBalancers.")
+	flag.IntVar(&gatewayFailureThreshold, "gateway-failure-threshold", controller.DefaultGatewayFailureThreshold,
+		"Checks in a row that must find a Gateway's ALB failed or missing before its hostnames are moved.")
+	flag.DurationVar(&loadBalancerAlarmsInterval, "load-balancer-alarms-interval", 0,
+		"Interval for syncing CloudWatch alarms (5xx rate, target connection errors, unhealthy hosts) on the ALB of every "+
+			"managed Gateway (0 disables). Requires elasticloadbalancing:DescribeLoadBalancers and cloudwatch:PutMetricAlarm, "+
+			"DescribeAlarms, DeleteAlarms and TagResource.")
+	flag.DurationVar(&zonalShiftDuration, "zonal-shift-duration", 0,
+		"Default duration of the ARC zonal shifts with which the admin API evacuates an availability zone for all ALBs "+
+			"(0 disables; at most 72h). Opts the ALBs into zonal shift. Requires elasticloadbalancing:DescribeLoadBalancers "+
+			"and arc-zonal-shift:StartZonalShift and CancelZonalShift.")
+	flag.StringVar(&loadBalancerAlarmActions, "load-balancer-alarm-actions", "",
+		"Comma-separated ARNs (e.g. SNS topics) notified when a load balancer alarm fires or recovers.")
+	flag.Float64Var(&alarmErrorRatePercent, "load-balancer-alarm-5xx-percent", controller.DefaultAlarmErrorRatePercent,
+		"Share of requests in percent answered with 5xx above which the 5xx rate alarm fires.")
+	flag.Float64Var(&alarmConnectionErrors, "load-balancer-alarm-connection-errors", controller.DefaultAlarmConnectionErrors,
+		"Failed connections to targets per minute above which the target connection error alarm fires.")
+	flag.StringVar(&accessLogBucket, "access-log-bucket", "",
+		"S3 bucket the ALBs of managed Gateways write their access logs to, and requests with spec.accessLogs get the "+
+			"lines of their hostname delivered to. Its bucket policy must allow ELB log delivery. Requires s3:ListBucket, "+
+			"s3:GetObject and s3:PutObject. Empty leaves ALB access logs alone.")
+	flag.StringVar(&accessLogPrefix, "access-log-prefix", "gateway-orchestrator",
+		"Prefix in --access-log-bucket: ALB access logs are written to <prefix>/alb, per-hostname logs to <prefix>/hostnames.")
+	flag.DurationVar(&accessLogInterval, "access-log-interval", 5*time.Minute,
+		"Interval for delivering new ALB access logs to the hostnames that opted in.")
+	flag.StringVar(&inventoryExportBucket, "inventory-export-bucket", "",
+		"S3 bucket the hostname inventory is written to every --inventory-export-interval. Requires s3:PutObject. "+
+			"Empty disables the export.")
+	flag.StringVar(&inventoryExportKey, "inventory-export-key", "gateway-orchestrator/inventory.json",
+		"Key of the exported inventory in --inventory-export-bucket; CSV if it ends in .csv, JSON otherwise.")
+	flag.DurationVar(&inventoryExportInterval, "inventory-export-interval", 15*time.Minute,
+		"Interval for exporting the hostname inventory to --inventory-export-bucket.")
+	flag.DurationVar(&fleetHealthInterval, "fleet-health-interval", time.Minute,
+		"Interval for exporting the number of hostnames per health state and the remaining error budget as metrics (0 disables).")
+	flag.BoolVar(&verifyExistingDNS, "verify-existing-dns", false,
+		"Look hostnames up in public DNS before claiming them, and hold those that already resolve to addresses the "+
+			"controller doesn't manage until the request sets spec.allowTakeover.")
+	flag.IntVar(&conditionFailureThreshold, "condition-failure-threshold", controller.DefaultConditionFailureThreshold,
+		"Times in a row a provisioning step may fail with transient AWS errors (throttling, timeouts, server errors) "+
+			"before its True condition is set to False.")
+	flag.IntVar(&failedThreshold, "failed-threshold", controller.DefaultFailedThreshold,
+		"Failed reconciles in a row after which a hostname that isn't Ready counts as Failed.")
+	flag.Float64Var(&readyObjective, "ready-objective", controller.DefaultReadyObjective,
+		"Target fraction of hostnames that are not Failed; the error budget is the remaining fraction.")
+	flag.DurationVar(&sloReportInterval, "slo-report-interval", 5*time.Minute,
+		"Interval for exporting the provisioning SLO compliance per namespace as metrics and to --slo-report-configmap (0 disables).")
+	flag.StringVar(&sloWindows, "slo-windows", controller.DefaultSLOWindows,
+		"Comma-separated rolling windows of the provisioning SLO report, as durations or days, e.g. 1d,7d,30d.")
+	flag.DurationVar(&timeToReadyObjective, "time-to-ready-objective", controller.DefaultTimeToReadyObjective,
+		"Time within which a hostname should become Ready to count towards the provisioning SLO.")
+	flag.Float64Var(&provisioningObjective, "provisioning-objective", controller.DefaultProvisioningObjective,
+		"Target fraction of provisionings per namespace that become Ready within --time-to-ready-objective.")
+	flag.StringVar(&sloReportConfigMap, "slo-report-configmap", "",
+		"ConfigMap, as <namespace>/<name>, the provisioning SLO report is written to (empty disables).")
+	flag.DurationVar(&probeInterval, "probe-interval", 0,
+		"Interval for HTTPS reachability probes of Ready hostnames, exported as metrics (0 disables probing).")
+	flag.DurationVar(&probeTimeout, "probe-timeout", 10*time.Second, "Timeout for a single hostname probe.")
+	flag.IntVar(&probeConcurrency, "probe-concurrency", probe.DefaultConcurrency,
+		"Maximum number of hostnames probed at once.")
+	flag.StringVar(&canaryHostname, "canary-hostname", "",
+		"Hostname the controller provisions end to end as a continuous self-test, exported as metrics (empty disables the canary).")
+	flag.StringVar(&canaryZoneID, "canary-zone-id", "", "Route53 hosted zone ID for --canary-hostname.")
+	flag.StringVar(&canaryNamespace, "canary-namespace", "gateway-orchestrator-system",
+		"Namespace of the canary GatewayHostnameRequest.")
+	flag.DurationVar(&canaryInterval, "canary-interval", time.Minute, "Interval between canary checks.")
+	flag.DurationVar(&canaryRecycleAfter, "canary-recycle-after", 24*time.Hour,
+		"How long a Ready canary is kept before it is deleted and provisioned again (0 keeps it).")
+	flag.DurationVar(&canaryProvisionTimeout, "canary-provision-timeout", 30*time.Minute,
+		"How long the canary may take to become Ready before the cycle counts as timed out.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", os.Getenv("ENABLE_WEBHOOKS") == "true",
+		"Enable admission webhooks (requires serving certificates, see config/webhook).")
+	flag.StringVar(&profile, "profile", controller.ProfileCustom,
+		"Preset for the optional subsystems: minimal (reconcilers only), full (webhooks, rebalancing, duplicate certificate check, failover, alarms, probes, fleet health, cost estimates) or custom (each subsystem's own flag). Flags given explicitly override the preset.")
+
+	flag.StringVar(&logStepVerbosity, "log-step-verbosity", "",
+		"Comma-separated <step>=<n> shifting the log verbosity of reconcile steps, e.g. dns=1,issuance=-1 (steps: "+
+			strings.Join(controller.LogSteps, ", ")+").")
 
 	opts := zap.Options{
 		Development: true,
@@ -61,7 +455,164 @@ func main() {
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	stepVerbosity, err := controller.ParseLogStepVerbosity(logStepVerbosity)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --log-step-verbosity: %v\n", err)
+		os.Exit(1)
+	}
+	ctrl.SetLogger(controller.NewStepLogger(zap.New(zap.UseFlagOptions(&opts)), stepVerbosity))
+
+	// ENABLE_WEBHOOKS counts as setting --enable-webhooks explicitly, so profiles don't override it
+	webhooksGiven := false
+	flag.Visit(func(f *flag.Flag) { webhooksGiven = webhooksGiven || f.Name == "enable-webhooks" })
+	if value, ok := os.LookupEnv("ENABLE_WEBHOOKS"); ok && !webhooksGiven {
+		_ = flag.Set("enable-webhooks", strconv.FormatBool(value == "true"))
+	}
+	if err := controller.ApplyProfile(flag.CommandLine, profile); err != nil {
+		setupLog.Error(err, "invalid --profile")
+		os.Exit(1)
+	}
+
+	switch ipAddressType {
+	case "", "ipv4", "dualstack", "dualstack-without-public-ipv4":
+	default:
+		setupLog.Error(nil, "invalid --ip-address-type, must be ipv4, dualstack or dualstack-without-public-ipv4", "value", ipAddressType)
+		os.Exit(1)
+	}
+
+	pollBackoff, err := controller.ParseBackoffCurve(certificatePollBackoff)
+	if err != nil {
+		setupLog.Error(err, "invalid --certificate-poll-backoff")
+		os.Exit(1)
+	}
+
+	namespaceByVisibility, err := gateway.ParseNamespaceByVisibility(gatewayNamespaceByVisibility)
+	if err != nil {
+		setupLog.Error(err, "invalid --gateway-namespace-by-visibility")
+		os.Exit(1)
+	}
+
+	if listenerHostnames && listenerMode == controller.ListenerModeShared {
+		listenerMode = controller.ListenerModeHostname
+	}
+	switch listenerMode {
+	case controller.ListenerModeShared, controller.ListenerModeHostname, controller.ListenerModeListenerSet:
+	default:
+		setupLog.Error(nil, "invalid --listener-mode, must be shared, hostname or listenerset", "value", listenerMode)
+		os.Exit(1)
+	}
+
+	switch namespaceAccess {
+	case controller.NamespaceAccessLabel, controller.NamespaceAccessGateway:
+	default:
+		setupLog.Error(nil, "invalid --namespace-access, must be label or gateway", "value", namespaceAccess)
+		os.Exit(1)
+	}
+
+	labelKeys, err := controller.ParseGatewayLabelKeys(gatewayLabelKeys)
+	if err != nil {
+		setupLog.Error(err, "invalid --gateway-label-keys")
+		os.Exit(1)
+	}
+
+	internalSuffixes, err := controller.ParseInternalDomainSuffixes(internalDomainSuffixes)
+	if err != nil {
+		setupLog.Error(err, "invalid --internal-domain-suffixes")
+		os.Exit(1)
+	}
+
+	interop, err := controller.ParseInteropTags(interopTags)
+	if err != nil {
+		setupLog.Error(err, "invalid --interop-tags")
+		os.Exit(1)
+	}
+	foreignOwners, err := controller.ParseForeignOwnerTags(foreignOwnerTags)
+	if err != nil {
+		setupLog.Error(err, "invalid --foreign-owner-tags")
+		os.Exit(1)
+	}
+
+	quarantine, err := controller.NewQuarantine(quarantinePatterns, quarantineExemptDomains, quarantinePunycode)
+	if err != nil {
+		setupLog.Error(err, "invalid --quarantine-patterns")
+		os.Exit(1)
+	}
+
+	var pricing *controller.CostPricing
+	if costEstimates {
+		p, err := controller.ParseCostPricing(costPricing)
+		if err != nil {
+			setupLog.Error(err, "invalid --cost-pricing")
+			os.Exit(1)
+		}
+		pricing = &p
+	}
+
+	if duplicateCertificateCheckInterval > 0 && clusterName == "" {
+		setupLog.Error(nil, "--duplicate-certificate-check-interval requires --cluster-name")
+		os.Exit(1)
+	}
+
+	switch claimStore {
+	case controller.ClaimStoreCRD:
+	case controller.ClaimStoreDynamoDB:
+		if claimTable == "" || clusterName == "" {
+			setupLog.Error(nil, "--claim-store=dynamodb requires --claim-table and --cluster-name")
+			os.Exit(1)
+		}
+	default:
+		setupLog.Error(nil, "invalid --claim-store, must be crd or dynamodb", "value", claimStore)
+		os.Exit(1)
+	}
+
+	switch defaultFrameOptions {
+	case "", "DENY", "SAMEORIGIN":
+	default:
+		setupLog.Error(nil, "invalid --default-frame-options, must be DENY or SAMEORIGIN", "value", defaultFrameOptions)
+		os.Exit(1)
+	}
+
+	switch cleanupOrder {
+	case controller.CleanupOrderDNSFirst, controller.CleanupOrderCertificateFirst:
+	default:
+		setupLog.Error(nil, "invalid --cleanup-order, must be dns-first or certificate-first", "value", cleanupOrder)
+		os.Exit(1)
+	}
+	if cleanupStepDelay < 0 {
+		setupLog.Error(nil, "invalid --cleanup-step-delay, must not be negative", "value", cleanupStepDelay)
+		os.Exit(1)
+	}
+	if fullReconcileInterval < 0 {
+		setupLog.Error(nil, "invalid --full-reconcile-interval, must not be negative", "value", fullReconcileInterval)
+		os.Exit(1)
+	}
+
+	switch namespaceProtection {
+	case webhook.NamespaceProtectionDeny, webhook.NamespaceProtectionWarn, webhook.NamespaceProtectionOff:
+	default:
+		setupLog.Error(nil, "invalid --namespace-deletion-protection, must be deny, warn or off", "value", namespaceProtection)
+		os.Exit(1)
+	}
+
+	if shadow {
+		// Shadow mode only intercepts the cluster, ACM and Route53
+		for name, set := range map[string]bool{
+			"--acme-directory":                acmeDirectory != "",
+			"--claim-store=dynamodb":          claimStore == controller.ClaimStoreDynamoDB,
+			"--notify-webhook-url":            notifyWebhookURL != "",
+			"--notify-slack-webhook-url":      notifySlackWebhookURL != "",
+			"--notify-sns-topic-arn":          notifySNSTopicArn != "",
+			"--access-log-bucket":             accessLogBucket != "",
+			"--inventory-export-bucket":       inventoryExportBucket != "",
+			"--load-balancer-alarms-interval": loadBalancerAlarmsInterval > 0,
+			"--zonal-shift-duration":          zonalShiftDuration > 0,
+		} {
+			if set {
+				setupLog.Error(nil, name+" makes changes outside the cluster and can't be used with --shadow")
+				os.Exit(1)
+			}
+		}
+	}
 
 	// Load AWS configuration
 	awsCfg, err := config.LoadDefaultConfig(context.Background())
@@ -71,44 +622,663 @@ func main() {
 	}
 
 	// Create AWS clients
-	acmClient := aws.NewSDKACMClient(awsCfg)
-	route53Client := aws.NewSDKRoute53Client(awsCfg)
+	var acmClient aws.ACMClient
+	var sdkRoute53Client aws.Route53Client
+	switch awsBackend {
+	case "aws":
+		// Certificates pinned to another region with spec.certificateRegion use that region's ACM
+		acmClient = aws.NewRegionalACMClient(aws.NewSDKACMClient(awsCfg), awsCfg.Region, func(region string) aws.ACMClient {
+			regionCfg := awsCfg.Copy()
+			regionCfg.Region = region
+			return aws.NewSDKACMClient(regionCfg)
+		})
+		sdkRoute53Client = aws.NewSDKRoute53Client(awsCfg)
+	case "fake":
+		// The fake only covers ACM and Route53
+		for name, set := range map[string]bool{
+			"--notify-sns-topic-arn":          notifySNSTopicArn != "",
+			"--access-log-bucket":             accessLogBucket != "",
+			"--inventory-export-bucket":       inventoryExportBucket != "",
+			"--claim-store=dynamodb":          claimStore == controller.ClaimStoreDynamoDB,
+			"--gateway-failover-interval":     gatewayFailoverInterval > 0,
+			"--load-balancer-alarms-interval": loadBalancerAlarmsInterval > 0,
+			"--zonal-shift-duration":          zonalShiftDuration > 0,
+		} {
+			if set {
+				setupLog.Error(nil, name+" needs AWS and can't be used with --aws-backend=fake")
+				os.Exit(1)
+			}
+		}
+		fake, err := aws.NewFake(awsFakeState)
+		if err != nil {
+			setupLog.Error(err, "unable to set up fake AWS backend")
+			os.Exit(1)
+		}
+		acmClient, sdkRoute53Client = fake.ACMClient(), fake.Route53Client()
+		setupLog.Info("WARNING: using the fake AWS backend, no certificates or DNS records are created in AWS", "state", awsFakeState)
+	default:
+		setupLog.Error(nil, "invalid --aws-backend, must be aws or fake", "value", awsBackend)
+		os.Exit(1)
+	}
+	acmClient, sdkRoute53Client = injectAWSFaults(acmClient, sdkRoute53Client)
+	zoneBudgets, err := aws.ParseZoneBudgets(route53ZoneBudgets)
+	if err != nil {
+		setupLog.Error(err, "invalid --route53-zone-budgets")
+		os.Exit(1)
+	}
+	defaultBudget, err := aws.ParseZoneBudget(route53DefaultBudget)
+	if err != nil {
+		setupLog.Error(err, "invalid --route53-default-budget")
+		os.Exit(1)
+	}
+	var route53Client aws.Route53Client = aws.NewRateLimitedRoute53Client(sdkRoute53Client, defaultBudget, zoneBudgets)
+	secondaryProviders := map[string]secondarydns.Provider{}
+	if key := os.Getenv("NS1_API_KEY"); key != "" {
+		secondaryProviders["ns1"] = &secondarydns.NS1{APIKey: key}
+	}
+	secondaryZones, err := secondarydns.ParseZones(secondaryDNSZones, secondaryProviders)
+	if err != nil {
+		setupLog.Error(err, "invalid --secondary-dns-zones")
+		os.Exit(1)
+	}
+	if len(secondaryZones) > 0 {
+		route53Client = secondarydns.NewWriteThroughRoute53Client(route53Client, secondaryZones)
+	}
+	var claims controller.ClaimStore
+	if claimStore == controller.ClaimStoreDynamoDB {
+		claims = &controller.DynamoDBClaimStore{Table: aws.NewSDKItemTable(awsCfg, claimTable), Cluster: clusterName}
+		setupLog.Info("Keeping hostname claims in DynamoDB", "table", claimTable, "cluster", clusterName)
+	}
+
+	var shadowDiffs *controller.ShadowDiffs
+	if shadow {
+		shadowDiffs = controller.NewShadowDiffs()
+		acmClient = aws.NewShadowACMClient(acmClient, shadowDiffs.AWSReport("ACMCertificate"))
+		route53Client = aws.NewShadowRoute53Client(route53Client, shadowDiffs.AWSReport("Route53"))
+		setupLog.Info("Shadow mode: nothing is changed, changes are only reported as metrics")
+	}
+	var dnssecChecker *controller.DNSSECChecker
+	if dnssecChecks {
+		dnssecChecker = controller.NewDNSSECChecker(route53Client)
+	}
 
-	setupLog.Info("AWS clients initialized", "region", awsCfg.Region)
+	setupLog.Info("AWS clients initialized", "region", awsCfg.Region, "route53ZoneBudgets", len(zoneBudgets),
+		"secondaryDNSZones", len(secondaryZones))
+
+	var acmeIssuer acme.Issuer
+	if acmeDirectory != "" {
+		if acmeAccountKey == "" {
+			setupLog.Error(nil, "--acme-account-key is required with --acme-directory")
+			os.Exit(1)
+		}
+		key, err := acme.LoadAccountKey(acmeAccountKey)
+		if err != nil {
+			setupLog.Error(err, "unable to load ACME account key")
+			os.Exit(1)
+		}
+		acmeIssuer = acme.NewClient(acmeDirectory, acmeEmail, key)
+		setupLog.Info("ACME issuer enabled", "directory", acmeDirectory, "fallback", acmeFallback)
+	} else if acmeFallback {
+		setupLog.Error(nil, "--acme-fallback requires --acme-directory")
+		os.Exit(1)
+	}
+
+	notifyEventTypes, err := notify.ParseEventTypes(notifyEvents)
+	if err != nil {
+		setupLog.Error(err, "invalid --notify-events")
+		os.Exit(1)
+	}
+	var sinks []notify.Sink
+	if notifyWebhookURL != "" {
+		sinks = append(sinks, &notify.Webhook{URL: notifyWebhookURL})
+	}
+	if notifySlackWebhookURL != "" {
+		sinks = append(sinks, &notify.Slack{URL: notifySlackWebhookURL})
+	}
+	if notifySNSTopicArn != "" {
+		sinks = append(sinks, notify.NewSNS(awsCfg, notifySNSTopicArn))
+	}
+	var notifier *notify.Dispatcher
+	if len(sinks) > 0 {
+		notifier = notify.NewDispatcher(sinks, notifyEventTypes, notify.DefaultQueueSize)
+	}
+
+	var hostnamePolicy policy.Checker
+	if policyWebhookURL != "" {
+		if policyFailurePolicy != policy.FailurePolicyFail && policyFailurePolicy != policy.FailurePolicyIgnore {
+			setupLog.Error(nil, "--policy-webhook-failure-policy must be Fail or Ignore")
+			os.Exit(1)
+		}
+		hostnamePolicy = &policy.Webhook{URL: policyWebhookURL, Timeout: policyWebhookTimeout}
+		setupLog.Info("Hostname policy webhook enabled", "url", policyWebhookURL, "failurePolicy", policyFailurePolicy)
+	}
+
+	if readyObjective <= 0 || readyObjective >= 1 {
+		setupLog.Error(nil, "--ready-objective must be between 0 and 1")
+		os.Exit(1)
+	}
+	fleetHealth := &controller.FleetHealth{
+		Interval:        fleetHealthInterval,
+		FailedThreshold: int32(failedThreshold),
+		Objective:       readyObjective,
+	}
+
+	if provisioningObjective <= 0 || provisioningObjective >= 1 {
+		setupLog.Error(nil, "--provisioning-objective must be between 0 and 1")
+		os.Exit(1)
+	}
+	if timeToReadyObjective <= 0 {
+		setupLog.Error(nil, "invalid --time-to-ready-objective, must be positive", "value", timeToReadyObjective)
+		os.Exit(1)
+	}
+	windows, err := controller.ParseSLOWindows(sloWindows)
+	if err != nil {
+		setupLog.Error(err, "invalid --slo-windows")
+		os.Exit(1)
+	}
+	sloReporter := &controller.SLOReporter{
+		Interval:             sloReportInterval,
+		Windows:              windows,
+		TimeToReadyObjective: timeToReadyObjective,
+		Objective:            provisioningObjective,
+		FailedThreshold:      int32(failedThreshold),
+	}
+	if sloReportConfigMap != "" {
+		namespace, name, ok := strings.Cut(sloReportConfigMap, "/")
+		if !ok || namespace == "" || name == "" {
+			setupLog.Error(nil, "invalid --slo-report-configmap, must be <namespace>/<name>", "value", sloReportConfigMap)
+			os.Exit(1)
+		}
+		sloReporter.ConfigMap = types.NamespacedName{Namespace: namespace, Name: name}
+	}
+
+	// Restrict the cache to the watched namespaces, plus the namespaces the controller
+	// manages Gateways and the canary in. Cluster-scoped objects are not affected.
+	var cacheOptions cache.Options
+	var watched []string
+	for _, ns := range strings.Split(watchNamespaces, ",") {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			watched = append(watched, ns)
+		}
+	}
+	if len(watched) > 0 {
+		cacheOptions.DefaultNamespaces = map[string]cache.Config{gatewayNamespace: {}}
+		for _, ns := range watched {
+			cacheOptions.DefaultNamespaces[ns] = cache.Config{}
+		}
+		for _, ns := range namespaceByVisibility {
+			cacheOptions.DefaultNamespaces[ns] = cache.Config{}
+		}
+		if canaryHostname != "" {
+			cacheOptions.DefaultNamespaces[canaryNamespace] = cache.Config{}
+		}
+		setupLog.Info("Watching namespaces", "namespaces", watched)
+	}
+
+	var newClient client.NewClientFunc
+	if shadowDiffs != nil {
+		newClient = func(config *rest.Config, options client.Options) (client.Client, error) {
+			c, err := client.New(config, options)
+			if err != nil {
+				return nil, err
+			}
+			return controller.NewShadowClient(c, shadowDiffs), nil
+		}
+		leaderElectionID += "-shadow"
+	}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:                 scheme,
-		Metrics:                metricsserver.Options{BindAddress: metricsAddr},
+		Scheme:    scheme,
+		Cache:     cacheOptions,
+		NewClient: newClient,
+		Metrics: metricsserver.Options{
+			BindAddress: metricsAddr,
+			// Fleet health summary and provisioning SLO report for dashboards, next to the metrics
+			ExtraHandlers: map[string]http.Handler{"/stats": fleetHealth, "/slo": sloReporter},
+		},
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "gateway-orchestrator.opendi.com",
+		LeaderElectionID:       leaderElectionID,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
+	eventRecorder := mgr.GetEventRecorderFor
+	if shadowDiffs != nil {
+		// Events are writes too; a FakeRecorder without a channel drops them
+		eventRecorder = func(string) record.EventRecorder { return &record.FakeRecorder{} }
+	}
 
 	// Create Gateway pool
 	gatewayPool := gateway.NewPool(mgr.GetClient(), gatewayNamespace, gatewayClassName, int32(httpPort), int32(httpsPort))
+	gatewayPool.SetMaxCertificates(maxCertificates)
+	gatewayPool.SetCreationCooldown(gatewayCreationCooldown)
+	gatewayPool.SetHTTPSOnly(httpsOnly)
+	gatewayPool.SetNamespaceByVisibility(namespaceByVisibility)
+	if err := gatewayPool.SetTargetType(targetType); err != nil {
+		setupLog.Error(err, "invalid --target-type")
+		os.Exit(1)
+	}
+	naming, err := gateway.NewNaming(clusterName, clusterEnvironment,
+		gatewayNameTemplate, lbcNameTemplate, tgcNameTemplate, loadBalancerNameTemplate)
+	if err != nil {
+		setupLog.Error(err, "invalid name template")
+		os.Exit(1)
+	}
+	gatewayPool.SetNaming(naming)
+
+	// Settings the OrchestratorConfig can override; the flags are their defaults
+	var settings *controller.SettingsStore
+	defaultSettings := controller.Settings{
+		MaxCertificatesPerGateway: maxCertificates,
+		GatewayCreationCooldown:   gatewayCreationCooldown,
+		HTTPPort:                  int32(httpPort),
+		HTTPSPort:                 int32(httpsPort),
+		HTTPSOnly:                 httpsOnly,
+		CertificatePollBackoff:    pollBackoff,
+	}
+	if orchestratorConfig != "" {
+		settings = controller.NewSettingsStore(defaultSettings)
+	}
+
+	// Discover the served LoadBalancerConfiguration version. Failures are not fatal:
+	// the AWS Load Balancer Controller may be installed after the orchestrator.
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create discovery client")
+		os.Exit(1)
+	}
+	lbcVersion := controller.NewLBCVersionResolver(discoveryClient)
+	if err := lbcVersion.Refresh(context.Background()); err != nil {
+		setupLog.Error(err, "unable to discover LoadBalancerConfiguration version, using default", "version", lbcVersion.GVK().Version)
+	}
+
+	var accessLogs *controller.AccessLogs
+	if accessLogBucket != "" {
+		accessLogs = &controller.AccessLogs{
+			Client:   mgr.GetClient(),
+			Objects:  aws.NewSDKObjectStore(awsCfg, accessLogBucket),
+			Bucket:   accessLogBucket,
+			Prefix:   strings.Trim(accessLogPrefix, "/"),
+			Interval: accessLogInterval,
+		}
+	}
 
 	// Setup GatewayHostnameRequest controller
-	if err = (&controller.GatewayHostnameRequestReconciler{
+	reconciler := &controller.GatewayHostnameRequestReconciler{
 		Client:        mgr.GetClient(),
+		APIReader:     mgr.GetAPIReader(),
 		Scheme:        mgr.GetScheme(),
-		Recorder:      mgr.GetEventRecorderFor("gateway-orchestrator"),
+		Recorder:      eventRecorder("gateway-orchestrator"),
 		ACMClient:     acmClient,
 		Route53Client: route53Client,
 		GatewayPool:   gatewayPool,
-	}).SetupWithManager(mgr); err != nil {
+		LBCVersion:    lbcVersion,
+		DNSSEC:        dnssecChecker,
+
+		ACMEIssuer:      acmeIssuer,
+		ACMEFallback:    acmeFallback,
+		ACMERenewBefore: acmeRenewBefore,
+
+		ExpandCertificates: expandCertificates,
+
+		ListenerMode:           listenerMode,
+		IPAddressType:          ipAddressType,
+		Region:                 awsCfg.Region,
+		NamespaceAccess:        namespaceAccess,
+		GatewayLabelKeys:       labelKeys,
+		InternalDomainSuffixes: internalSuffixes,
+		InteropTags:            interop,
+		Cluster:                clusterName,
+		ForeignOwnerTags:       foreignOwners,
+		Quarantine:             quarantine,
+		ClaimStore:             claims,
+		BackendReferenceGrants: backendReferenceGrants,
+		RebalanceOverCapacity:  rebalanceOverCapacity,
+		RepairRouteParentRefs:  repairRouteParentRefs,
+		StrictRouteAccess:      strictRouteAccess,
+		RebalanceDrainPeriod:   rebalanceDrainPeriod,
+		ForceDeleteAfter:       forceDeleteAfter,
+		CleanupOrder:           cleanupOrder,
+		CleanupStepDelay:       cleanupStepDelay,
+		FullReconcileInterval:  fullReconcileInterval,
+
+		DefaultSecurityHeaders: gatewayv1alpha1.SecurityHeaders{
+			StrictTransportSecurity: defaultHSTS,
+			FrameOptions:            defaultFrameOptions,
+		},
+
+		CertificatePollBackoff:      pollBackoff,
+		Settings:                    settings,
+		ImpactConfirmationThreshold: impactConfirmationThreshold,
+		Reprovisions:                controller.NewReprovisionLimiter(maxReprovisionsPerMinute),
+		ConditionFailureThreshold:   conditionFailureThreshold,
+
+		Policy:         hostnamePolicy,
+		PolicyFailOpen: policyFailurePolicy == policy.FailurePolicyIgnore,
+
+		OverrideTargetAdmission: enableWebhooks,
+
+		ManageDelegatedZones: manageDelegatedZones,
+
+		CostPricing: pricing,
+		Notifier:    notifier,
+		AccessLogs:  accessLogs,
+		ZonalShift:  zonalShiftDuration > 0,
+	}
+	if verifyExistingDNS {
+		reconciler.TakeoverResolver = net.DefaultResolver
+	}
+	if err = reconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "GatewayHostnameRequest")
 		os.Exit(1)
 	}
 
+	if err = (&controller.GatewayPoolReconciler{
+		Client:                mgr.GetClient(),
+		Scheme:                mgr.GetScheme(),
+		GatewayPool:           gatewayPool,
+		MaxRoutesPerNamespace: maxRoutesPerNamespace,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GatewayPool")
+		os.Exit(1)
+	}
+
+	if settings != nil {
+		if err = (&controller.OrchestratorConfigReconciler{
+			Client:       mgr.GetClient(),
+			Scheme:       mgr.GetScheme(),
+			Recorder:     eventRecorder("gateway-orchestrator"),
+			GatewayPool:  gatewayPool,
+			Settings:     settings,
+			Name:         orchestratorConfig,
+			GatewayClass: gatewayClassName,
+			Defaults:     defaultSettings,
+			Requests:     reconciler,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OrchestratorConfig")
+			os.Exit(1)
+		}
+	}
+
 	setupLog.Info("Controller registered",
 		"gatewayNamespace", gatewayNamespace,
+		"gatewayNamespaces", gatewayPool.Namespaces(),
 		"gatewayClassName", gatewayClassName,
 		"httpPort", httpPort,
-		"httpsPort", httpsPort)
+		"httpsPort", httpsPort,
+		"httpsOnly", httpsOnly,
+		"loadBalancerConfigurationVersion", lbcVersion.GVK().Version,
+		"listenerMode", listenerMode,
+		"ipAddressType", ipAddressType,
+		"maxCertificatesPerGateway", gatewayPool.MaxCertificates(),
+		"gatewayCreationCooldown", gatewayCreationCooldown,
+		"targetType", gatewayPool.TargetType(),
+		"rebalanceOverCapacity", rebalanceOverCapacity,
+		"profile", profile)
+
+	if enableWebhooks {
+		if err = (&webhook.GatewayHostnameRequestDefaulter{
+			Client: mgr.GetClient(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "GatewayHostnameRequest")
+			os.Exit(1)
+		}
+		splitGroups := func(value string) []string {
+			groups := []string{}
+			for _, group := range strings.Split(value, ",") {
+				if group = strings.TrimSpace(group); group != "" {
+					groups = append(groups, group)
+				}
+			}
+			return groups
+		}
+		if err = (&webhook.GatewayHostnameRequestValidator{
+			InternalDomainSuffixes:   internalSuffixes,
+			OverrideTargetGroups:     splitGroups(overrideTargetGroups),
+			QuarantineApproverGroups: splitGroups(quarantineApproverGroups),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "GatewayHostnameRequest")
+			os.Exit(1)
+		}
+		if namespaceProtection != webhook.NamespaceProtectionOff {
+			if err = (&webhook.NamespaceDeletionValidator{
+				Client: mgr.GetClient(),
+				Mode:   namespaceProtection,
+			}).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create webhook", "webhook", "Namespace")
+				os.Exit(1)
+			}
+		}
+		if maxRoutesPerNamespace > 0 {
+			if err = (&webhook.HTTPRouteLimitValidator{
+				Client:                mgr.GetClient(),
+				GatewayPool:           gatewayPool,
+				MaxRoutesPerNamespace: maxRoutesPerNamespace,
+			}).SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create webhook", "webhook", "HTTPRoute")
+				os.Exit(1)
+			}
+		}
+		setupLog.Info("Webhooks enabled", "namespaceDeletionProtection", namespaceProtection, "maxRoutesPerNamespace", maxRoutesPerNamespace)
+	}
+
+	if notifier != nil {
+		if err := mgr.Add(notifier); err != nil {
+			setupLog.Error(err, "unable to set up notifications")
+			os.Exit(1)
+		}
+		setupLog.Info("Notifications enabled", "sinks", len(sinks), "events", notifyEventTypes)
+	}
+
+	var duplicates *controller.DuplicateCertificateChecker
+	if duplicateCertificateCheckInterval > 0 {
+		duplicates = &controller.DuplicateCertificateChecker{
+			Client:           mgr.GetClient(),
+			ACMClient:        acmClient,
+			Recorder:         eventRecorder("duplicate-certificates"),
+			Interval:         duplicateCertificateCheckInterval,
+			Delete:           deleteDuplicateCertificates,
+			ForeignOwnerTags: foreignOwners,
+			Cluster:          clusterName,
+		}
+		if err := mgr.Add(duplicates); err != nil {
+			setupLog.Error(err, "unable to set up duplicate certificate check")
+			os.Exit(1)
+		}
+	}
+
+	if gatewayFailoverInterval > 0 {
+		if err := mgr.Add(&controller.GatewayFailover{
+			Client:           mgr.GetClient(),
+			LoadBalancers:    aws.NewSDKLoadBalancerClient(awsCfg),
+			GatewayPool:      gatewayPool,
+			Recorder:         eventRecorder("gateway-failover"),
+			Notifier:         notifier,
+			Interval:         gatewayFailoverInterval,
+			FailureThreshold: gatewayFailureThreshold,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up Gateway failover")
+			os.Exit(1)
+		}
+		setupLog.Info("Gateway failover enabled", "interval", gatewayFailoverInterval, "threshold", gatewayFailureThreshold)
+	}
+
+	if loadBalancerAlarmsInterval > 0 {
+		alarmActions, err := controller.ParseAlarmActions(loadBalancerAlarmActions)
+		if err != nil {
+			setupLog.Error(err, "invalid --load-balancer-alarm-actions")
+			os.Exit(1)
+		}
+		if err := mgr.Add(&controller.LoadBalancerAlarms{
+			GatewayPool:      gatewayPool,
+			LoadBalancers:    aws.NewSDKLoadBalancerClient(awsCfg),
+			Alarms:           aws.NewSDKAlarmClient(awsCfg),
+			Interval:         loadBalancerAlarmsInterval,
+			Cluster:          clusterName,
+			Actions:          alarmActions,
+			ErrorRatePercent: alarmErrorRatePercent,
+			ConnectionErrors: alarmConnectionErrors,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up load balancer alarms")
+			os.Exit(1)
+		}
+		setupLog.Info("Load balancer alarms enabled", "interval", loadBalancerAlarmsInterval, "actions", len(alarmActions))
+	}
+
+	if accessLogs != nil && accessLogInterval > 0 {
+		if err := mgr.Add(accessLogs); err != nil {
+			setupLog.Error(err, "unable to set up access log delivery")
+			os.Exit(1)
+		}
+		setupLog.Info("Per-hostname access logs enabled", "bucket", accessLogBucket, "prefix", accessLogs.Prefix, "interval", accessLogInterval)
+	}
+
+	if namespaceAccess == controller.NamespaceAccessLabel {
+		if err := mgr.Add(&controller.NamespaceLabelMigration{Client: mgr.GetClient()}); err != nil {
+			setupLog.Error(err, "unable to set up namespace label migration")
+			os.Exit(1)
+		}
+	}
+
+	fleetHealth.Client = mgr.GetClient()
+	if fleetHealthInterval > 0 {
+		if err := mgr.Add(fleetHealth); err != nil {
+			setupLog.Error(err, "unable to set up fleet health summary")
+			os.Exit(1)
+		}
+	}
+
+	sloReporter.Client = mgr.GetClient()
+	sloReporter.APIReader = mgr.GetAPIReader()
+	sloReporter.Writer = mgr.GetClient()
+	if sloReportInterval > 0 {
+		if err := mgr.Add(sloReporter); err != nil {
+			setupLog.Error(err, "unable to set up provisioning SLO report")
+			os.Exit(1)
+		}
+	}
+
+	if inventoryAddr != "" {
+		if err := mgr.Add(&controller.Inventory{
+			Client:      mgr.GetClient(),
+			Claims:      claims,
+			BindAddress: inventoryAddr,
+			Token:       os.Getenv("INVENTORY_TOKEN"),
+		}); err != nil {
+			setupLog.Error(err, "unable to set up hostname inventory")
+			os.Exit(1)
+		}
+	}
+
+	if inventoryExportBucket != "" && inventoryExportInterval > 0 {
+		if err := mgr.Add(&controller.InventoryExport{
+			Client:   mgr.GetClient(),
+			Claims:   claims,
+			Objects:  aws.NewSDKObjectStore(awsCfg, inventoryExportBucket),
+			Bucket:   inventoryExportBucket,
+			Key:      strings.TrimPrefix(inventoryExportKey, "/"),
+			Interval: inventoryExportInterval,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up hostname inventory export")
+			os.Exit(1)
+		}
+	}
+
+	var zonalShifts *controller.ZonalShifts
+	if zonalShiftDuration > 0 {
+		if zonalShiftDuration < time.Minute || zonalShiftDuration > 72*time.Hour {
+			setupLog.Error(nil, "--zonal-shift-duration must be between 1m and 72h", "value", zonalShiftDuration)
+			os.Exit(1)
+		}
+		zonalShifts = &controller.ZonalShifts{
+			Client:        mgr.GetClient(),
+			GatewayPool:   gatewayPool,
+			LoadBalancers: aws.NewSDKLoadBalancerClient(awsCfg),
+			Shifts:        aws.NewSDKZonalShiftClient(awsCfg),
+			Recorder:      eventRecorder("zonal-shifts"),
+			Duration:      zonalShiftDuration,
+		}
+		if err := mgr.Add(zonalShifts); err != nil {
+			setupLog.Error(err, "unable to set up zonal shifts")
+			os.Exit(1)
+		}
+	}
+
+	if adminAddr != "" {
+		adminToken := os.Getenv("ADMIN_TOKEN")
+		if adminToken == "" {
+			setupLog.Error(nil, "--admin-bind-address requires ADMIN_TOKEN")
+			os.Exit(1)
+		}
+		if err := mgr.Add(&controller.Admin{
+			Reconciler:    reconciler,
+			Duplicates:    duplicates,
+			BindAddress:   adminAddr,
+			Token:         adminToken,
+			BulkPerMinute: adminBulkPerMinute,
+			ZonalShifts:   zonalShifts,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up admin API")
+			os.Exit(1)
+		}
+	}
+
+	if probeInterval > 0 {
+		if probeConcurrency <= 0 {
+			setupLog.Error(nil, "invalid --probe-concurrency, must be positive", "value", probeConcurrency)
+			os.Exit(1)
+		}
+		if err := mgr.Add(&probe.Prober{
+			Client:      mgr.GetClient(),
+			Interval:    probeInterval,
+			Timeout:     probeTimeout,
+			Concurrency: probeConcurrency,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up hostname prober")
+			os.Exit(1)
+		}
+	}
+
+	if canaryHostname != "" {
+		if canaryZoneID == "" {
+			setupLog.Error(nil, "--canary-zone-id is required with --canary-hostname")
+			os.Exit(1)
+		}
+		if err := mgr.Add(&probe.Canary{
+			Client:           mgr.GetClient(),
+			Namespace:        canaryNamespace,
+			Hostname:         canaryHostname,
+			ZoneID:           canaryZoneID,
+			Interval:         canaryInterval,
+			RecycleAfter:     canaryRecycleAfter,
+			ProvisionTimeout: canaryProvisionTimeout,
+			Timeout:          probeTimeout,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up canary")
+			os.Exit(1)
+		}
+	}
+
+	controller.RecordProfile(profile, map[string]bool{
+		"webhooks":                    enableWebhooks,
+		"rebalancer":                  rebalanceOverCapacity,
+		"duplicate-certificate-check": duplicateCertificateCheckInterval > 0,
+		"gateway-failover":            gatewayFailoverInterval > 0,
+		"load-balancer-alarms":        loadBalancerAlarmsInterval > 0,
+		"access-logs":                 accessLogs != nil && accessLogInterval > 0,
+		"fleet-health":                fleetHealthInterval > 0,
+		"slo-report":                  sloReportInterval > 0,
+		"probes":                      probeInterval > 0,
+		"canary":                      canaryHostname != "",
+		"cost-estimates":              costEstimates,
+		"inventory":                   inventoryAddr != "",
+		"inventory-export":            inventoryExportBucket != "" && inventoryExportInterval > 0,
+		"admin-api":                   adminAddr != "",
+		"notifications":               notifier != nil,
+		"shadow":                      shadow,
+	})
 
 	//+kubebuilder:scaffold:builder
 
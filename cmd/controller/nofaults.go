@@ -0,0 +1,11 @@
+//go:build !faultinject
+
+package main
+
+import "github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+
+// injectAWSFaults returns the clients as they are; builds with the faultinject tag can make
+// them fail
+func injectAWSFaults(acmClient aws.ACMClient, route53Client aws.Route53Client) (aws.ACMClient, aws.Route53Client) {
+	return acmClient, route53Client
+}
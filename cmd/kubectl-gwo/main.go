@@ -0,0 +1,85 @@
+// kubectl-gwo is a kubectl plugin for the gateway orchestrator. Install it on the PATH and
+// run `kubectl gwo pool` to list the managed Gateways.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/plugin"
+)
+
+const usage = `Usage: kubectl gwo <command> [--kubeconfig=<path>]
+
+Commands:
+  pool    List the managed Gateways with visibility, WAF, certificates and rules against
+          their limits, load balancer DNS name and age
+`
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var run func(ctx context.Context, c client.Client) error
+	switch flag.Arg(0) {
+	case "pool":
+		run = pool
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", flag.Arg(0))
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	c, err := newClient()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := run(ctx, c); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// newClient connects to the cluster of the current kubeconfig context
+func newClient() (client.Client, error) {
+	config, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	scheme := runtime.NewScheme()
+	if err := gatewayv1alpha1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := gwapiv1.Install(scheme); err != nil {
+		return nil, err
+	}
+	return client.New(config, client.Options{Scheme: scheme})
+}
+
+func pool(ctx context.Context, c client.Client) error {
+	rows, err := plugin.PoolRows(ctx, c, time.Now())
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		fmt.Fprintln(os.Stderr, "No managed Gateways found.")
+		return nil
+	}
+	return plugin.WritePoolTable(os.Stdout, rows)
+}
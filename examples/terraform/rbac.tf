@@ -32,7 +32,8 @@ resource "kubernetes_cluster_role" "gateway_orchestrator" {
     resources = [
       "gatewayhostnamerequests",
       "domainclaims",
-      "hostnamegrants"
+      "hostnamegrants",
+      "orphans"
     ]
     verbs = ["get", "list", "watch", "create", "update", "patch", "delete"]
   }
@@ -43,7 +44,8 @@ resource "kubernetes_cluster_role" "gateway_orchestrator" {
     resources = [
       "gatewayhostnamerequests/status",
       "gatewayhostnamerequests/finalizers",
-      "hostnamegrants/status"
+      "hostnamegrants/status",
+      "orphans/status"
     ]
     verbs = ["get", "patch", "update"]
   }
@@ -0,0 +1,269 @@
+package acme
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+)
+
+// LetsEncryptDirectory is the Let's Encrypt production ACME directory
+const LetsEncryptDirectory = acme.LetsEncryptURL
+
+var (
+	// ErrOrderPending is returned while the CA is still validating the order's challenges
+	ErrOrderPending = errors.New("ACME order pending validation")
+
+	// ErrOrderFailed is returned for orders that can no longer produce a certificate;
+	// a new order has to be started
+	ErrOrderFailed = errors.New("ACME order failed")
+)
+
+// Challenge is a DNS-01 challenge: the TXT record to publish and the URL to accept once it is
+type Challenge struct {
+	URL         string
+	RecordName  string
+	RecordValue string
+}
+
+// Certificate is an issued certificate with its private key, PEM-encoded for import into ACM
+type Certificate struct {
+	CertificatePEM []byte
+	ChainPEM       []byte
+	PrivateKeyPEM  []byte
+	NotAfter       time.Time
+}
+
+// Issuer defines the interface for issuing certificates through an ACME CA with DNS-01.
+// Orders are referenced by URL so issuance can span several reconciles.
+type Issuer interface {
+	// NewOrder starts an order for the domain and returns its URL
+	NewOrder(ctx context.Context, domain string) (orderURL string, err error)
+
+	// PendingChallenges returns the DNS-01 challenges of the order that still have to be
+	// published and accepted
+	PendingChallenges(ctx context.Context, orderURL string) ([]Challenge, error)
+
+	// Accept tells the CA that the challenge record is published
+	Accept(ctx context.Context, challenge Challenge) error
+
+	// Certificate finalizes the order once its challenges are validated. It returns
+	// ErrOrderPending while validation is in progress and ErrOrderFailed if the order is unusable.
+	Certificate(ctx context.Context, orderURL string) (*Certificate, error)
+}
+
+// Client implements Issuer using golang.org/x/crypto/acme
+type Client struct {
+	client *acme.Client
+	email  string
+
+	mu         sync.Mutex
+	registered bool
+}
+
+// NewClient creates an ACME client for the directory. The account is registered on first use.
+func NewClient(directoryURL, email string, key crypto.Signer) *Client {
+	return &Client{
+		client: &acme.Client{Key: key, DirectoryURL: directoryURL},
+		email:  email,
+	}
+}
+
+// LoadAccountKey reads a PEM-encoded ECDSA or RSA account key (SEC 1, PKCS #1 or PKCS #8)
+func LoadAccountKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ACME account key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data in %s", path)
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ACME account key: %w", err)
+	}
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		return k, nil
+	case *rsa.PrivateKey:
+		return k, nil
+	default:
+		return nil, fmt.Errorf("unsupported ACME account key type %T", key)
+	}
+}
+
+// register creates the ACME account, or looks up the existing one for the key
+func (c *Client) register(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.registered {
+		return nil
+	}
+
+	account := &acme.Account{}
+	if c.email != "" {
+		account.Contact = []string{"mailto:" + c.email}
+	}
+	if _, err := c.client.Register(ctx, account, acme.AcceptTOS); err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return fmt.Errorf("failed to register ACME account: %w", err)
+	}
+	c.registered = true
+	return nil
+}
+
+func (c *Client) NewOrder(ctx context.Context, domain string) (string, error) {
+	if err := c.register(ctx); err != nil {
+		return "", err
+	}
+	order, err := c.client.AuthorizeOrder(ctx, acme.DomainIDs(domain))
+	if err != nil {
+		return "", fmt.Errorf("failed to create ACME order: %w", err)
+	}
+	return order.URI, nil
+}
+
+func (c *Client) PendingChallenges(ctx context.Context, orderURL string) ([]Challenge, error) {
+	if err := c.register(ctx); err != nil {
+		return nil, err
+	}
+	order, err := c.client.GetOrder(ctx, orderURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ACME order: %w", err)
+	}
+
+	var challenges []Challenge
+	for _, authzURL := range order.AuthzURLs {
+		authz, err := c.client.GetAuthorization(ctx, authzURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ACME authorization: %w", err)
+		}
+		if authz.Status != acme.StatusPending {
+			continue
+		}
+		var dns01 *acme.Challenge
+		for _, chal := range authz.Challenges {
+			if chal.Type == "dns-01" {
+				dns01 = chal
+				break
+			}
+		}
+		if dns01 == nil {
+			return nil, fmt.Errorf("%w: no dns-01 challenge offered for %s", ErrOrderFailed, authz.Identifier.Value)
+		}
+		// Accepted challenges are being validated by the CA
+		if dns01.Status != acme.StatusPending {
+			continue
+		}
+		value, err := c.client.DNS01ChallengeRecord(dns01.Token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute dns-01 record: %w", err)
+		}
+		// Wildcard authorizations carry the base domain, which is where the record goes
+		challenges = append(challenges, Challenge{
+			URL:         dns01.URI,
+			RecordName:  "_acme-challenge." + authz.Identifier.Value,
+			RecordValue: value,
+		})
+	}
+	return challenges, nil
+}
+
+func (c *Client) Accept(ctx context.Context, challenge Challenge) error {
+	if err := c.register(ctx); err != nil {
+		return err
+	}
+	if _, err := c.client.Accept(ctx, &acme.Challenge{URI: challenge.URL}); err != nil {
+		return fmt.Errorf("failed to accept ACME challenge: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) Certificate(ctx context.Context, orderURL string) (*Certificate, error) {
+	if err := c.register(ctx); err != nil {
+		return nil, err
+	}
+	order, err := c.client.GetOrder(ctx, orderURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ACME order: %w", err)
+	}
+
+	switch order.Status {
+	case acme.StatusPending:
+		return nil, ErrOrderPending
+	case acme.StatusReady:
+	case acme.StatusInvalid:
+		if order.Error != nil {
+			return nil, fmt.Errorf("%w: %v", ErrOrderFailed, order.Error)
+		}
+		return nil, ErrOrderFailed
+	default:
+		// Processing or valid: finalized before, but the private key of that attempt is gone
+		return nil, fmt.Errorf("%w: order is %s", ErrOrderFailed, order.Status)
+	}
+
+	var names []string
+	for _, id := range order.Identifiers {
+		names = append(names, id.Value)
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate certificate key: %w", err)
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: names[0]},
+		DNSNames: names,
+	}, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CSR: %w", err)
+	}
+
+	der, _, err := c.client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to finalize ACME order: %w", err)
+	}
+	return encodeCertificate(der, key)
+}
+
+// encodeCertificate PEM-encodes the leaf, its chain and the private key
+func encodeCertificate(der [][]byte, key *ecdsa.PrivateKey) (*Certificate, error) {
+	if len(der) == 0 {
+		return nil, fmt.Errorf("CA returned no certificate")
+	}
+	leaf, err := x509.ParseCertificate(der[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse issued certificate: %w", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode certificate key: %w", err)
+	}
+
+	cert := &Certificate{
+		CertificatePEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der[0]}),
+		PrivateKeyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		NotAfter:       leaf.NotAfter,
+	}
+	for _, intermediate := range der[1:] {
+		cert.ChainPEM = append(cert.ChainPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: intermediate})...)
+	}
+	return cert, nil
+}
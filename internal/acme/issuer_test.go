@@ -0,0 +1,106 @@
+package acme
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeKey(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "account.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadAccountKey(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ecDER, _ := x509.MarshalECPrivateKey(ecKey)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	pkcs8DER, _ := x509.MarshalPKCS8PrivateKey(rsaKey)
+
+	tests := []struct {
+		name      string
+		blockType string
+		der       []byte
+		wantErr   bool
+	}{
+		{name: "SEC 1 ECDSA key", blockType: "EC PRIVATE KEY", der: ecDER},
+		{name: "PKCS #1 RSA key", blockType: "RSA PRIVATE KEY", der: x509.MarshalPKCS1PrivateKey(rsaKey)},
+		{name: "PKCS #8 RSA key", blockType: "PRIVATE KEY", der: pkcs8DER},
+		{name: "garbage", blockType: "PRIVATE KEY", der: []byte("not a key"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := LoadAccountKey(writeKey(t, tt.blockType, tt.der))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadAccountKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && key == nil {
+				t.Error("LoadAccountKey() returned no key")
+			}
+		})
+	}
+}
+
+func TestEncodeCertificate(t *testing.T) {
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	leafKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	notAfter := time.Now().Add(90 * 24 * time.Hour).Truncate(time.Second).UTC()
+
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              notAfter.Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, ca, ca, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		DNSNames:     []string{"app.example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     notAfter,
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leaf, ca, &leafKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := encodeCertificate([][]byte{leafDER, caDER}, leafKey)
+	if err != nil {
+		t.Fatalf("encodeCertificate() error = %v", err)
+	}
+	if !cert.NotAfter.Equal(notAfter) {
+		t.Errorf("NotAfter = %v, want %v", cert.NotAfter, notAfter)
+	}
+	if block, _ := pem.Decode(cert.CertificatePEM); block == nil || string(block.Bytes) != string(leafDER) {
+		t.Error("CertificatePEM does not hold the leaf certificate")
+	}
+	if block, _ := pem.Decode(cert.ChainPEM); block == nil || string(block.Bytes) != string(caDER) {
+		t.Error("ChainPEM does not hold the issuer certificate")
+	}
+	if block, _ := pem.Decode(cert.PrivateKeyPEM); block == nil || block.Type != "EC PRIVATE KEY" {
+		t.Error("PrivateKeyPEM is not an EC private key")
+	}
+
+	if _, err := encodeCertificate(nil, leafKey); err == nil {
+		t.Error("encodeCertificate() accepted an empty chain")
+	}
+}
@@ -0,0 +1,77 @@
+package acme
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MockIssuer is a mock implementation for testing. Orders become ready once all their
+// challenges are accepted and Validate is called.
+type MockIssuer struct {
+	Orders map[string]*MockOrder
+}
+
+// MockOrder is an order tracked by MockIssuer
+type MockOrder struct {
+	Domain    string
+	Accepted  bool
+	Validated bool
+	Failed    bool
+}
+
+func NewMockIssuer() *MockIssuer {
+	return &MockIssuer{Orders: make(map[string]*MockOrder)}
+}
+
+func (m *MockIssuer) NewOrder(ctx context.Context, domain string) (string, error) {
+	url := fmt.Sprintf("https://acme.example/order/%d", len(m.Orders)+1)
+	m.Orders[url] = &MockOrder{Domain: domain}
+	return url, nil
+}
+
+func (m *MockIssuer) PendingChallenges(ctx context.Context, orderURL string) ([]Challenge, error) {
+	order, ok := m.Orders[orderURL]
+	if !ok {
+		return nil, fmt.Errorf("order not found: %s", orderURL)
+	}
+	if order.Accepted {
+		return nil, nil
+	}
+	return []Challenge{{
+		URL:         orderURL + "/challenge",
+		RecordName:  "_acme-challenge." + strings.TrimPrefix(order.Domain, "*."),
+		RecordValue: "token-" + order.Domain,
+	}}, nil
+}
+
+func (m *MockIssuer) Accept(ctx context.Context, challenge Challenge) error {
+	order, ok := m.Orders[strings.TrimSuffix(challenge.URL, "/challenge")]
+	if !ok {
+		return fmt.Errorf("challenge not found: %s", challenge.URL)
+	}
+	order.Accepted = true
+	return nil
+}
+
+// Validate marks the order's challenges as validated by the CA (for testing)
+func (m *MockIssuer) Validate(orderURL string) {
+	m.Orders[orderURL].Validated = true
+}
+
+func (m *MockIssuer) Certificate(ctx context.Context, orderURL string) (*Certificate, error) {
+	order, ok := m.Orders[orderURL]
+	if !ok || order.Failed {
+		return nil, ErrOrderFailed
+	}
+	if !order.Accepted || !order.Validated {
+		return nil, ErrOrderPending
+	}
+	return &Certificate{
+		CertificatePEM: []byte("certificate " + order.Domain),
+		ChainPEM:       []byte("chain"),
+		PrivateKeyPEM:  []byte("key"),
+		NotAfter:       time.Now().Add(90 * 24 * time.Hour),
+	}, nil
+}
@@ -2,12 +2,22 @@ package aws
 
 import (
 	"context"
+	"errors"
+	"time"
 )
 
+// ErrCertificateQuotaExceeded is returned when the account has hit an ACM certificate quota
+var ErrCertificateQuotaExceeded = errors.New("ACM certificate quota exceeded")
+
 // ACMClient defines the interface for ACM operations
 type ACMClient interface {
-	// RequestCertificate requests a new ACM certificate for the given domain
-	RequestCertificate(ctx context.Context, domain string, tags map[string]string) (certArn string, err error)
+	// RequestCertificate requests a new ACM certificate for the given domain. Requests with the same
+	// idempotencyToken within an hour return the same certificate instead of a new one.
+	RequestCertificate(ctx context.Context, domain, idempotencyToken string, tags map[string]string) (certArn string, err error)
+
+	// RequestCertificateWithSANs is RequestCertificate for a certificate that also covers the
+	// subject alternative names. Each name is validated with its own DNS record.
+	RequestCertificateWithSANs(ctx context.Context, domain string, subjectAlternativeNames []string, idempotencyToken string, tags map[string]string) (certArn string, err error)
 
 	// DescribeCertificate gets the current status and details of a certificate
 	DescribeCertificate(ctx context.Context, certArn string) (*CertificateDetails, error)
@@ -17,6 +27,28 @@ type ACMClient interface {
 
 	// GetValidationRecords returns the DNS records needed for certificate validation
 	GetValidationRecords(ctx context.Context, certArn string) ([]ValidationRecord, error)
+
+	// ImportCertificate imports a certificate issued elsewhere. With a certArn the certificate
+	// is re-imported in place, so load balancers using it pick up the renewal; tags only
+	// apply to new imports.
+	ImportCertificate(ctx context.Context, certArn string, certificate, privateKey, chain []byte, tags map[string]string) (string, error)
+
+	// ListCertificates returns every certificate in the account and region with its tags.
+	// Tags are read with one call per certificate, so use it sparingly.
+	ListCertificates(ctx context.Context) ([]CertificateSummary, error)
+
+	// GetCertificateTags returns the tags of a certificate
+	GetCertificateTags(ctx context.Context, certArn string) (map[string]string, error)
+}
+
+// CertificateSummary is a certificate as listed by ListCertificates
+type CertificateSummary struct {
+	Arn       string
+	Domain    string
+	Status    string
+	InUse     bool // attached to a resource such as an ALB listener
+	CreatedAt time.Time
+	Tags      map[string]string
 }
 
 // CertificateDetails represents ACM certificate information
@@ -25,6 +57,10 @@ type CertificateDetails struct {
 	Domain  string
 	Status  string   // PENDING_VALIDATION, ISSUED, FAILED, etc.
 	InUseBy []string // ARNs of resources using this certificate (e.g., ALB listeners)
+	// SubjectAlternativeNames are all names the certificate covers, Domain included
+	SubjectAlternativeNames []string
+	// NotAfter is when the certificate expires; zero until it is issued
+	NotAfter time.Time
 }
 
 // ValidationRecord represents a DNS validation record for ACM
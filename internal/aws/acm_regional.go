@@ -0,0 +1,100 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// certificateRegionKey is the context key of WithCertificateRegion
+type certificateRegionKey struct{}
+
+// WithCertificateRegion returns a context in which a RegionalACMClient requests and imports new
+// certificates in the region instead of its default one. An empty region keeps the default.
+func WithCertificateRegion(ctx context.Context, region string) context.Context {
+	if region == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, certificateRegionKey{}, region)
+}
+
+// certificateRegionFromContext returns the region set with WithCertificateRegion
+func certificateRegionFromContext(ctx context.Context) string {
+	region, _ := ctx.Value(certificateRegionKey{}).(string)
+	return region
+}
+
+// CertificateRegion returns the region of an ACM certificate ARN
+// (arn:<partition>:acm:<region>:<account>:certificate/<id>), or "" if it isn't one
+func CertificateRegion(certArn string) string {
+	parts := strings.SplitN(certArn, ":", 6)
+	if len(parts) < 6 || parts[0] != "arn" || parts[2] != "acm" {
+		return ""
+	}
+	return parts[3]
+}
+
+// RegionalACMClient sends each call to the ACM client of the certificate's region: calls with a
+// certificate ARN go to the ARN's region, new certificates to the region of the context.
+// ListCertificates only covers the default region.
+type RegionalACMClient struct {
+	ACMClient
+	region    string
+	newClient func(region string) ACMClient
+
+	mu      sync.Mutex
+	clients map[string]ACMClient
+}
+
+// NewRegionalACMClient wraps the ACM client of the default region; newClient creates the
+// clients of other regions when they are first used
+func NewRegionalACMClient(client ACMClient, region string, newClient func(region string) ACMClient) *RegionalACMClient {
+	return &RegionalACMClient{ACMClient: client, region: region, newClient: newClient, clients: map[string]ACMClient{}}
+}
+
+// clientFor returns the client of the region, the default one for "" or the default region
+func (c *RegionalACMClient) clientFor(region string) ACMClient {
+	if region == "" || region == c.region {
+		return c.ACMClient
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	client, ok := c.clients[region]
+	if !ok {
+		client = c.newClient(region)
+		c.clients[region] = client
+	}
+	return client
+}
+
+func (c *RegionalACMClient) RequestCertificate(ctx context.Context, domain, idempotencyToken string, tags map[string]string) (string, error) {
+	return c.clientFor(certificateRegionFromContext(ctx)).RequestCertificate(ctx, domain, idempotencyToken, tags)
+}
+
+func (c *RegionalACMClient) RequestCertificateWithSANs(ctx context.Context, domain string, subjectAlternativeNames []string, idempotencyToken string, tags map[string]string) (string, error) {
+	return c.clientFor(certificateRegionFromContext(ctx)).RequestCertificateWithSANs(ctx, domain, subjectAlternativeNames, idempotencyToken, tags)
+}
+
+func (c *RegionalACMClient) DescribeCertificate(ctx context.Context, certArn string) (*CertificateDetails, error) {
+	return c.clientFor(CertificateRegion(certArn)).DescribeCertificate(ctx, certArn)
+}
+
+func (c *RegionalACMClient) DeleteCertificate(ctx context.Context, certArn string) error {
+	return c.clientFor(CertificateRegion(certArn)).DeleteCertificate(ctx, certArn)
+}
+
+func (c *RegionalACMClient) GetValidationRecords(ctx context.Context, certArn string) ([]ValidationRecord, error) {
+	return c.clientFor(CertificateRegion(certArn)).GetValidationRecords(ctx, certArn)
+}
+
+func (c *RegionalACMClient) ImportCertificate(ctx context.Context, certArn string, certificate, privateKey, chain []byte, tags map[string]string) (string, error) {
+	region := CertificateRegion(certArn)
+	if certArn == "" {
+		region = certificateRegionFromContext(ctx)
+	}
+	return c.clientFor(region).ImportCertificate(ctx, certArn, certificate, privateKey, chain, tags)
+}
+
+func (c *RegionalACMClient) GetCertificateTags(ctx context.Context, certArn string) (map[string]string, error) {
+	return c.clientFor(CertificateRegion(certArn)).GetCertificateTags(ctx, certArn)
+}
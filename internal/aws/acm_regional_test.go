@@ -0,0 +1,49 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertificateRegion(t *testing.T) {
+	assert.Equal(t, "eu-west-1", CertificateRegion("arn:aws:acm:eu-west-1:123456789012:certificate/abc"))
+	assert.Equal(t, "cn-north-1", CertificateRegion("arn:aws-cn:acm:cn-north-1:123456789012:certificate/abc"))
+	assert.Empty(t, CertificateRegion("arn:aws:elasticloadbalancing:eu-west-1:123456789012:loadbalancer/app/x"))
+	assert.Empty(t, CertificateRegion(""))
+}
+
+func TestRegionalACMClient(t *testing.T) {
+	ctx := context.Background()
+	defaultRegion := NewMockACMClient()
+	others := map[string]*MockACMClient{}
+	c := NewRegionalACMClient(defaultRegion, "eu-west-1", func(region string) ACMClient {
+		others[region] = NewMockACMClient()
+		return others[region]
+	})
+
+	_, err := c.RequestCertificate(ctx, "app.example.com", "", nil)
+	require.NoError(t, err)
+	assert.Len(t, defaultRegion.Certificates, 1)
+	assert.Empty(t, others, "the default region needs no other client")
+
+	_, err = c.RequestCertificate(WithCertificateRegion(ctx, "us-east-1"), "cdn.example.com", "", nil)
+	require.NoError(t, err)
+	require.Contains(t, others, "us-east-1")
+	assert.Len(t, others["us-east-1"].Certificates, 1)
+
+	pinned := "arn:aws:acm:us-east-1:123456789012:certificate/pinned"
+	others["us-east-1"].Certificates[pinned] = &CertificateDetails{Arn: pinned, Status: "ISSUED"}
+	details, err := c.DescribeCertificate(ctx, pinned)
+	require.NoError(t, err)
+	assert.Equal(t, "ISSUED", details.Status, "calls with an ARN go to its region")
+	require.NoError(t, c.DeleteCertificate(ctx, pinned))
+	assert.NotContains(t, others["us-east-1"].Certificates, pinned)
+	assert.Len(t, others, 1, "clients are reused")
+
+	imported, err := c.ImportCertificate(WithCertificateRegion(ctx, "us-east-1"), "", []byte("cert"), []byte("key"), nil, nil)
+	require.NoError(t, err)
+	assert.Contains(t, others["us-east-1"].Imported, imported)
+}
@@ -2,11 +2,13 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/acm"
 	"github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/aws/smithy-go/middleware"
 )
 
 // SDKACMClient implements ACMClient using AWS SDK v2
@@ -21,7 +23,11 @@ func NewSDKACMClient(cfg aws.Config) *SDKACMClient {
 	}
 }
 
-func (c *SDKACMClient) RequestCertificate(ctx context.Context, hostname string, tags map[string]string) (string, error) {
+func (c *SDKACMClient) RequestCertificate(ctx context.Context, hostname, idempotencyToken string, tags map[string]string) (string, error) {
+	return c.RequestCertificateWithSANs(ctx, hostname, nil, idempotencyToken, tags)
+}
+
+func (c *SDKACMClient) RequestCertificateWithSANs(ctx context.Context, hostname string, subjectAlternativeNames []string, idempotencyToken string, tags map[string]string) (string, error) {
 	// Convert tags to ACM format
 	var acmTags []types.Tag
 	for k, v := range tags {
@@ -36,9 +42,27 @@ func (c *SDKACMClient) RequestCertificate(ctx context.Context, hostname string,
 		ValidationMethod: types.ValidationMethodDns,
 		Tags:             acmTags,
 	}
+	if idempotencyToken != "" {
+		input.IdempotencyToken = aws.String(idempotencyToken)
+	}
+	if len(subjectAlternativeNames) > 0 {
+		// ACM expects the domain among the names when any are given
+		input.SubjectAlternativeNames = append([]string{hostname}, subjectAlternativeNames...)
+	}
 
 	result, err := c.client.RequestCertificate(ctx, input)
+	call := Call{Operation: "acm:RequestCertificate", Resource: hostname, Err: err}
+	var metadata middleware.Metadata
+	if err == nil {
+		metadata = result.ResultMetadata
+		call.Resource = aws.ToString(result.CertificateArn)
+	}
+	recordCall(ctx, call, metadata)
 	if err != nil {
+		var limitExceeded *types.LimitExceededException
+		if errors.As(err, &limitExceeded) {
+			return "", fmt.Errorf("%w: %v", ErrCertificateQuotaExceeded, err)
+		}
 		return "", fmt.Errorf("failed to request certificate: %w", err)
 	}
 
@@ -62,10 +86,12 @@ func (c *SDKACMClient) DescribeCertificate(ctx context.Context, arn string) (*Ce
 	}
 
 	return &CertificateDetails{
-		Arn:     arn,
-		Domain:  aws.ToString(result.Certificate.DomainName),
-		Status:  string(result.Certificate.Status),
-		InUseBy: inUseBy,
+		Arn:                     arn,
+		Domain:                  aws.ToString(result.Certificate.DomainName),
+		Status:                  string(result.Certificate.Status),
+		InUseBy:                 inUseBy,
+		SubjectAlternativeNames: result.Certificate.SubjectAlternativeNames,
+		NotAfter:                aws.ToTime(result.Certificate.NotAfter),
 	}, nil
 }
 
@@ -74,7 +100,12 @@ func (c *SDKACMClient) DeleteCertificate(ctx context.Context, arn string) error
 		CertificateArn: aws.String(arn),
 	}
 
-	_, err := c.client.DeleteCertificate(ctx, input)
+	result, err := c.client.DeleteCertificate(ctx, input)
+	var metadata middleware.Metadata
+	if err == nil {
+		metadata = result.ResultMetadata
+	}
+	recordCall(ctx, Call{Operation: "acm:DeleteCertificate", Resource: arn, Err: err}, metadata)
 	if err != nil {
 		return fmt.Errorf("failed to delete certificate: %w", err)
 	}
@@ -105,3 +136,79 @@ func (c *SDKACMClient) GetValidationRecords(ctx context.Context, arn string) ([]
 
 	return records, nil
 }
+
+func (c *SDKACMClient) ImportCertificate(ctx context.Context, arn string, certificate, privateKey, chain []byte, tags map[string]string) (string, error) {
+	input := &acm.ImportCertificateInput{
+		Certificate:      certificate,
+		PrivateKey:       privateKey,
+		CertificateChain: chain,
+	}
+	if arn != "" {
+		input.CertificateArn = aws.String(arn)
+	} else {
+		for k, v := range tags {
+			input.Tags = append(input.Tags, types.Tag{
+				Key:   aws.String(k),
+				Value: aws.String(v),
+			})
+		}
+	}
+
+	result, err := c.client.ImportCertificate(ctx, input)
+	call := Call{Operation: "acm:ImportCertificate", Resource: arn, Err: err}
+	var metadata middleware.Metadata
+	if err == nil {
+		metadata = result.ResultMetadata
+		call.Resource = aws.ToString(result.CertificateArn)
+	}
+	recordCall(ctx, call, metadata)
+	if err != nil {
+		return "", fmt.Errorf("failed to import certificate: %w", err)
+	}
+
+	return aws.ToString(result.CertificateArn), nil
+}
+
+func (c *SDKACMClient) ListCertificates(ctx context.Context) ([]CertificateSummary, error) {
+	var summaries []CertificateSummary
+	paginator := acm.NewListCertificatesPaginator(c.client, &acm.ListCertificatesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list certificates: %w", err)
+		}
+		for _, cert := range page.CertificateSummaryList {
+			summaries = append(summaries, CertificateSummary{
+				Arn:       aws.ToString(cert.CertificateArn),
+				Domain:    aws.ToString(cert.DomainName),
+				Status:    string(cert.Status),
+				InUse:     aws.ToBool(cert.InUse),
+				CreatedAt: aws.ToTime(cert.CreatedAt),
+			})
+		}
+	}
+
+	for i := range summaries {
+		tags, err := c.GetCertificateTags(ctx, summaries[i].Arn)
+		if err != nil {
+			return nil, err
+		}
+		summaries[i].Tags = tags
+	}
+
+	return summaries, nil
+}
+
+func (c *SDKACMClient) GetCertificateTags(ctx context.Context, certArn string) (map[string]string, error) {
+	result, err := c.client.ListTagsForCertificate(ctx, &acm.ListTagsForCertificateInput{
+		CertificateArn: aws.String(certArn),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags of certificate %s: %w", certArn, err)
+	}
+	tags := make(map[string]string, len(result.Tags))
+	for _, tag := range result.Tags {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags, nil
+}
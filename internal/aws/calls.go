@@ -0,0 +1,73 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// Call is a mutating AWS call, with the IDs to find it in CloudTrail or to quote in an AWS
+// support case
+type Call struct {
+	// Operation is the service and API operation, e.g. route53:ChangeResourceRecordSets
+	Operation string
+	// Resource is what the call changed, e.g. a hosted zone and record or a certificate ARN
+	Resource string
+	// RequestID is the AWS request ID, if the call reached AWS
+	RequestID string
+	// ChangeID is the Route53 change ID of record changes
+	ChangeID string
+	// Err is the error the call failed with
+	Err error
+}
+
+// CallLog collects the mutating calls the SDK clients make with a context from WithCallLog.
+// It is safe for concurrent use.
+type CallLog struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+type callLogKey struct{}
+
+// WithCallLog returns a context whose mutating Route53 and ACM calls are recorded in the
+// returned CallLog
+func WithCallLog(ctx context.Context) (context.Context, *CallLog) {
+	calls := &CallLog{}
+	return context.WithValue(ctx, callLogKey{}, calls), calls
+}
+
+// Calls returns the recorded calls, oldest first
+func (l *CallLog) Calls() []Call {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Call(nil), l.calls...)
+}
+
+// recordCall adds a call to the context's CallLog, if it has one. The request ID is read from
+// the result metadata, or from err if the call failed.
+func recordCall(ctx context.Context, call Call, metadata middleware.Metadata) {
+	calls, ok := ctx.Value(callLogKey{}).(*CallLog)
+	if !ok {
+		return
+	}
+	if id, ok := awsmiddleware.GetRequestIDMetadata(metadata); ok {
+		call.RequestID = id
+	}
+	var respErr *awshttp.ResponseError
+	if call.RequestID == "" && errors.As(call.Err, &respErr) {
+		call.RequestID = respErr.ServiceRequestID()
+	}
+	calls.Record(call)
+}
+
+// Record adds a call to the log
+func (l *CallLog) Record(call Call) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls = append(l.calls, call)
+}
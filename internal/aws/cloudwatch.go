@@ -0,0 +1,58 @@
+package aws
+
+import (
+	"context"
+)
+
+// AlarmClient manages the CloudWatch alarms the controller creates for managed ALBs
+type AlarmClient interface {
+	// PutMetricAlarm creates the alarm or replaces the one with the same name. Tags are only
+	// applied when the alarm is created.
+	PutMetricAlarm(ctx context.Context, alarm MetricAlarm) error
+
+	// DescribeAlarmNames returns the names of all alarms starting with the prefix
+	DescribeAlarmNames(ctx context.Context, prefix string) ([]string, error)
+
+	// DeleteAlarms deletes the named alarms; unknown names are ignored
+	DeleteAlarms(ctx context.Context, names []string) error
+}
+
+// MetricAlarm is a CloudWatch alarm on a metric math expression
+type MetricAlarm struct {
+	Name        string
+	Description string
+
+	// Metrics are the queries the alarm evaluates; exactly one must return data
+	Metrics []MetricQuery
+
+	EvaluationPeriods int
+	Threshold         float64
+	// ComparisonOperator is e.g. GreaterThanThreshold
+	ComparisonOperator string
+	// TreatMissingData is missing, notBreaching, breaching or ignore
+	TreatMissingData string
+
+	// AlarmActions are notified when the alarm fires and again when it recovers, e.g. SNS topics
+	AlarmActions []string
+	Tags         map[string]string
+}
+
+// MetricQuery is a metric or an expression over other metrics of an alarm
+type MetricQuery struct {
+	ID         string
+	Label      string
+	ReturnData bool
+
+	// Expression is a metric math expression or a Metrics Insights query.
+	// Period is required for Metrics Insights queries.
+	Expression string
+
+	// Namespace, MetricName, Dimensions and Stat select a single metric if Expression is empty
+	Namespace  string
+	MetricName string
+	Dimensions map[string]string
+	Stat       string
+
+	// Period in seconds
+	Period int
+}
@@ -0,0 +1,125 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// maxDeleteAlarms is how many alarms DeleteAlarms takes per call
+const maxDeleteAlarms = 100
+
+// cloudWatchAPI is the part of the CloudWatch SDK client SDKAlarmClient uses
+type cloudWatchAPI interface {
+	cloudwatch.DescribeAlarmsAPIClient
+	PutMetricAlarm(ctx context.Context, params *cloudwatch.PutMetricAlarmInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.PutMetricAlarmOutput, error)
+	DeleteAlarms(ctx context.Context, params *cloudwatch.DeleteAlarmsInput, optFns ...func(*cloudwatch.Options)) (*cloudwatch.DeleteAlarmsOutput, error)
+}
+
+// SDKAlarmClient implements AlarmClient using AWS SDK v2
+type SDKAlarmClient struct {
+	client cloudWatchAPI
+}
+
+// NewSDKAlarmClient creates an alarm client using the provided AWS config
+func NewSDKAlarmClient(cfg aws.Config) *SDKAlarmClient {
+	return &SDKAlarmClient{
+		client: cloudwatch.NewFromConfig(cfg),
+	}
+}
+
+func (c *SDKAlarmClient) PutMetricAlarm(ctx context.Context, alarm MetricAlarm) error {
+	input := &cloudwatch.PutMetricAlarmInput{
+		AlarmName:          aws.String(alarm.Name),
+		AlarmDescription:   aws.String(alarm.Description),
+		EvaluationPeriods:  aws.Int32(int32(alarm.EvaluationPeriods)),
+		Threshold:          aws.Float64(alarm.Threshold),
+		ComparisonOperator: types.ComparisonOperator(alarm.ComparisonOperator),
+		AlarmActions:       alarm.AlarmActions,
+		OKActions:          alarm.AlarmActions,
+	}
+	if alarm.TreatMissingData != "" {
+		input.TreatMissingData = aws.String(alarm.TreatMissingData)
+	}
+	for _, q := range alarm.Metrics {
+		query := types.MetricDataQuery{
+			Id:         aws.String(q.ID),
+			ReturnData: aws.Bool(q.ReturnData),
+		}
+		if q.Label != "" {
+			query.Label = aws.String(q.Label)
+		}
+		if q.Expression != "" {
+			query.Expression = aws.String(q.Expression)
+			if q.Period > 0 {
+				query.Period = aws.Int32(int32(q.Period))
+			}
+			input.Metrics = append(input.Metrics, query)
+			continue
+		}
+		metric := &types.Metric{
+			Namespace:  aws.String(q.Namespace),
+			MetricName: aws.String(q.MetricName),
+		}
+		for _, name := range sortedKeys(q.Dimensions) {
+			metric.Dimensions = append(metric.Dimensions, types.Dimension{Name: aws.String(name), Value: aws.String(q.Dimensions[name])})
+		}
+		query.MetricStat = &types.MetricStat{
+			Metric: metric,
+			Period: aws.Int32(int32(q.Period)),
+			Stat:   aws.String(q.Stat),
+		}
+		input.Metrics = append(input.Metrics, query)
+	}
+	for _, key := range sortedKeys(alarm.Tags) {
+		input.Tags = append(input.Tags, types.Tag{Key: aws.String(key), Value: aws.String(alarm.Tags[key])})
+	}
+
+	if _, err := c.client.PutMetricAlarm(ctx, input); err != nil {
+		return fmt.Errorf("failed to put alarm %s: %w", alarm.Name, err)
+	}
+	return nil
+}
+
+func (c *SDKAlarmClient) DescribeAlarmNames(ctx context.Context, prefix string) ([]string, error) {
+	var names []string
+	paginator := cloudwatch.NewDescribeAlarmsPaginator(c.client, &cloudwatch.DescribeAlarmsInput{
+		AlarmNamePrefix: aws.String(prefix),
+		AlarmTypes:      []types.AlarmType{types.AlarmTypeMetricAlarm},
+		MaxRecords:      aws.Int32(100),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe alarms: %w", err)
+		}
+		for _, alarm := range page.MetricAlarms {
+			names = append(names, aws.ToString(alarm.AlarmName))
+		}
+	}
+	return names, nil
+}
+
+func (c *SDKAlarmClient) DeleteAlarms(ctx context.Context, names []string) error {
+	for start := 0; start < len(names); start += maxDeleteAlarms {
+		end := min(start+maxDeleteAlarms, len(names))
+		if _, err := c.client.DeleteAlarms(ctx, &cloudwatch.DeleteAlarmsInput{AlarmNames: names[start:end]}); err != nil {
+			return fmt.Errorf("failed to delete alarms: %w", err)
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of m in order, so requests are deterministic
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
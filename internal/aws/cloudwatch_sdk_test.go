@@ -0,0 +1,92 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCloudWatch records the inputs of the CloudWatch calls. DescribeAlarms returns one alarm
+// per page, named after the pages.
+type fakeCloudWatch struct {
+	put     []*cloudwatch.PutMetricAlarmInput
+	deleted [][]string
+	pages   []string
+}
+
+func (f *fakeCloudWatch) PutMetricAlarm(_ context.Context, params *cloudwatch.PutMetricAlarmInput, _ ...func(*cloudwatch.Options)) (*cloudwatch.PutMetricAlarmOutput, error) {
+	f.put = append(f.put, params)
+	return &cloudwatch.PutMetricAlarmOutput{}, nil
+}
+
+func (f *fakeCloudWatch) DescribeAlarms(_ context.Context, params *cloudwatch.DescribeAlarmsInput, _ ...func(*cloudwatch.Options)) (*cloudwatch.DescribeAlarmsOutput, error) {
+	page := 0
+	if params.NextToken != nil {
+		page = 1
+	}
+	out := &cloudwatch.DescribeAlarmsOutput{MetricAlarms: []types.MetricAlarm{{AlarmName: aws.String(f.pages[page])}}}
+	if page+1 < len(f.pages) {
+		out.NextToken = aws.String("next")
+	}
+	return out, nil
+}
+
+func (f *fakeCloudWatch) DeleteAlarms(_ context.Context, params *cloudwatch.DeleteAlarmsInput, _ ...func(*cloudwatch.Options)) (*cloudwatch.DeleteAlarmsOutput, error) {
+	f.deleted = append(f.deleted, params.AlarmNames)
+	return &cloudwatch.DeleteAlarmsOutput{}, nil
+}
+
+func TestSDKAlarmClient_PutMetricAlarm(t *testing.T) {
+	fake := &fakeCloudWatch{}
+	c := &SDKAlarmClient{client: fake}
+
+	err := c.PutMetricAlarm(context.Background(), MetricAlarm{
+		Name:               "gateway-orchestrator/edge/gw-01/target-connection-errors",
+		EvaluationPeriods:  5,
+		Threshold:          0.5,
+		ComparisonOperator: "GreaterThanThreshold",
+		AlarmActions:       []string{"arn:aws:sns:eu-west-1:123456789012:edge"},
+		Metrics: []MetricQuery{{
+			ID: "m1", ReturnData: true, Namespace: "AWS/ApplicationELB", MetricName: "TargetConnectionErrorCount",
+			Dimensions: map[string]string{"LoadBalancer": "app/gw-01/1"}, Stat: "Sum", Period: 60,
+		}},
+		Tags: map[string]string{"managed-by": "gateway-orchestrator"},
+	})
+	require.NoError(t, err)
+	require.Len(t, fake.put, 1)
+	input := fake.put[0]
+	assert.Equal(t, 0.5, aws.ToFloat64(input.Threshold))
+	assert.Equal(t, types.ComparisonOperatorGreaterThanThreshold, input.ComparisonOperator)
+	assert.Equal(t, []string{"arn:aws:sns:eu-west-1:123456789012:edge"}, input.AlarmActions)
+	assert.Equal(t, []string{"arn:aws:sns:eu-west-1:123456789012:edge"}, input.OKActions)
+	require.Len(t, input.Metrics, 1)
+	stat := input.Metrics[0].MetricStat
+	require.NotNil(t, stat)
+	assert.Equal(t, []types.Dimension{{Name: aws.String("LoadBalancer"), Value: aws.String("app/gw-01/1")}}, stat.Metric.Dimensions)
+	assert.Equal(t, "Sum", aws.ToString(stat.Stat))
+	assert.Nil(t, input.Metrics[0].Expression)
+	assert.Equal(t, []types.Tag{{Key: aws.String("managed-by"), Value: aws.String("gateway-orchestrator")}}, input.Tags)
+}
+
+func TestSDKAlarmClient_DescribeAndDelete(t *testing.T) {
+	fake := &fakeCloudWatch{pages: []string{"a", "b"}}
+	c := &SDKAlarmClient{client: fake}
+
+	names, err := c.DescribeAlarmNames(context.Background(), "gateway-orchestrator/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, names)
+
+	many := make([]string, maxDeleteAlarms+1)
+	for i := range many {
+		many[i] = "alarm"
+	}
+	require.NoError(t, c.DeleteAlarms(context.Background(), many))
+	require.Len(t, fake.deleted, 2, "deleted in batches of maxDeleteAlarms")
+	assert.Len(t, fake.deleted[0], maxDeleteAlarms)
+	assert.Len(t, fake.deleted[1], 1)
+}
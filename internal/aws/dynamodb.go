@@ -0,0 +1,31 @@
+package aws
+
+import (
+	"context"
+)
+
+// ItemKeyAttribute is the partition key of the tables an ItemTable works with
+const ItemKeyAttribute = "name"
+
+// ItemTable reads and writes items of string attributes in a DynamoDB table whose partition key
+// is the string attribute ItemKeyAttribute. Reads are strongly consistent.
+type ItemTable interface {
+	// GetItem returns the item with the key, or nil if there is none
+	GetItem(ctx context.Context, key string) (map[string]string, error)
+
+	// PutItemIfAbsent writes the item unless one with the same key exists, and reports whether
+	// it was written
+	PutItemIfAbsent(ctx context.Context, item map[string]string) (bool, error)
+
+	// PutItemIfUnchanged replaces the item with the same key if the existing item's attributes
+	// have the expected values, where an empty value expects the attribute to be absent, and
+	// reports whether it was written. A missing item is not written.
+	PutItemIfUnchanged(ctx context.Context, item map[string]string, expected map[string]string) (bool, error)
+
+	// DeleteItem deletes the item with the key if its attributes have the expected values. A
+	// missing item or one with other values is not an error.
+	DeleteItem(ctx context.Context, key string, expected map[string]string) error
+
+	// ScanItems returns all items of the table
+	ScanItems(ctx context.Context) ([]map[string]string, error)
+}
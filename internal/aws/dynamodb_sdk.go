@@ -0,0 +1,171 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// SDKItemTable implements ItemTable for one table using AWS SDK v2
+type SDKItemTable struct {
+	client *dynamodb.Client
+	table  string
+}
+
+// NewSDKItemTable creates an item table for a table using the provided AWS config
+func NewSDKItemTable(cfg aws.Config, table string) *SDKItemTable {
+	return &SDKItemTable{
+		client: dynamodb.NewFromConfig(cfg),
+		table:  table,
+	}
+}
+
+func toAttributes(item map[string]string) map[string]types.AttributeValue {
+	attributes := make(map[string]types.AttributeValue, len(item))
+	for name, value := range item {
+		attributes[name] = &types.AttributeValueMemberS{Value: value}
+	}
+	return attributes
+}
+
+// fromAttributes returns the string attributes of an item; the tables hold no other types
+func fromAttributes(attributes map[string]types.AttributeValue) map[string]string {
+	item := make(map[string]string, len(attributes))
+	for name, value := range attributes {
+		if s, ok := value.(*types.AttributeValueMemberS); ok {
+			item[name] = s.Value
+		}
+	}
+	return item
+}
+
+// isConditionalCheckFailed reports whether a write failed because its condition didn't hold
+func isConditionalCheckFailed(err error) bool {
+	var conditionFailed *types.ConditionalCheckFailedException
+	return errors.As(err, &conditionFailed)
+}
+
+func (t *SDKItemTable) GetItem(ctx context.Context, key string) (map[string]string, error) {
+	result, err := t.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(t.table),
+		Key:            toAttributes(map[string]string{ItemKeyAttribute: key}),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get item %s: %w", key, err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+	return fromAttributes(result.Item), nil
+}
+
+func (t *SDKItemTable) PutItemIfAbsent(ctx context.Context, item map[string]string) (bool, error) {
+	_, err := t.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                aws.String(t.table),
+		Item:                     toAttributes(item),
+		ConditionExpression:      aws.String("attribute_not_exists(#k)"),
+		ExpressionAttributeNames: map[string]string{"#k": ItemKeyAttribute},
+	})
+	if isConditionalCheckFailed(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to put item %s: %w", item[ItemKeyAttribute], err)
+	}
+	return true, nil
+}
+
+func (t *SDKItemTable) PutItemIfUnchanged(ctx context.Context, item map[string]string, expected map[string]string) (bool, error) {
+	clauses := []string{"attribute_exists(#k)"}
+	attributeNames := map[string]string{"#k": ItemKeyAttribute}
+	attributeValues := map[string]types.AttributeValue{}
+	names := make([]string, 0, len(expected))
+	for name := range expected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		attributeNames[fmt.Sprintf("#a%d", i)] = name
+		if expected[name] == "" {
+			clauses = append(clauses, fmt.Sprintf("attribute_not_exists(#a%d)", i))
+			continue
+		}
+		clauses = append(clauses, fmt.Sprintf("#a%d = :v%d", i, i))
+		attributeValues[fmt.Sprintf(":v%d", i)] = &types.AttributeValueMemberS{Value: expected[name]}
+	}
+	input := &dynamodb.PutItemInput{
+		TableName:                aws.String(t.table),
+		Item:                     toAttributes(item),
+		ConditionExpression:      aws.String(strings.Join(clauses, " AND ")),
+		ExpressionAttributeNames: attributeNames,
+	}
+	if len(attributeValues) > 0 {
+		input.ExpressionAttributeValues = attributeValues
+	}
+	_, err := t.client.PutItem(ctx, input)
+	if isConditionalCheckFailed(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to put item %s: %w", item[ItemKeyAttribute], err)
+	}
+	return true, nil
+}
+
+func (t *SDKItemTable) DeleteItem(ctx context.Context, key string, expected map[string]string) error {
+	input := &dynamodb.DeleteItemInput{
+		TableName: aws.String(t.table),
+		Key:       toAttributes(map[string]string{ItemKeyAttribute: key}),
+	}
+	if len(expected) > 0 {
+		names := make([]string, 0, len(expected))
+		for name := range expected {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var clauses []string
+		attributeNames := map[string]string{}
+		attributeValues := map[string]types.AttributeValue{}
+		for i, name := range names {
+			clauses = append(clauses, fmt.Sprintf("#a%d = :v%d", i, i))
+			attributeNames[fmt.Sprintf("#a%d", i)] = name
+			attributeValues[fmt.Sprintf(":v%d", i)] = &types.AttributeValueMemberS{Value: expected[name]}
+		}
+		input.ConditionExpression = aws.String(strings.Join(clauses, " AND "))
+		input.ExpressionAttributeNames = attributeNames
+		input.ExpressionAttributeValues = attributeValues
+	}
+	_, err := t.client.DeleteItem(ctx, input)
+	if isConditionalCheckFailed(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete item %s: %w", key, err)
+	}
+	return nil
+}
+
+func (t *SDKItemTable) ScanItems(ctx context.Context) ([]map[string]string, error) {
+	var items []map[string]string
+	paginator := dynamodb.NewScanPaginator(t.client, &dynamodb.ScanInput{
+		TableName:      aws.String(t.table),
+		ConsistentRead: aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan table %s: %w", t.table, err)
+		}
+		for _, attributes := range page.Items {
+			items = append(items, fromAttributes(attributes))
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,101 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestItemTable(t *testing.T, handler func(operation string, input map[string]interface{}) (int, string)) *SDKItemTable {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-amz-json-1.0", r.Header.Get("Content-Type"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-west-1/dynamodb/aws4_request")
+		var input map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		assert.Equal(t, "claims", input["TableName"])
+		status, body := handler(r.Header.Get("X-Amz-Target")[len("DynamoDB_20120810."):], input)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return NewSDKItemTable(testConfig(server), "claims")
+}
+
+func TestSDKItemTable_GetItem(t *testing.T) {
+	table := newTestItemTable(t, func(operation string, input map[string]interface{}) (int, string) {
+		assert.Equal(t, "GetItem", operation)
+		assert.Equal(t, true, input["ConsistentRead"])
+		if input["Key"].(map[string]interface{})["name"].(map[string]interface{})["S"] == "missing" {
+			return http.StatusOK, `{}`
+		}
+		return http.StatusOK, `{"Item":{"name":{"S":"z1-app.example.com"},"cluster":{"S":"east"}}}`
+	})
+
+	item, err := table.GetItem(context.Background(), "z1-app.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"name": "z1-app.example.com", "cluster": "east"}, item)
+	item, err = table.GetItem(context.Background(), "missing")
+	require.NoError(t, err)
+	assert.Nil(t, item)
+}
+
+func TestSDKItemTable_ConditionalWrites(t *testing.T) {
+	conditionFailed := `{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`
+	table := newTestItemTable(t, func(operation string, input map[string]interface{}) (int, string) {
+		switch operation {
+		case "PutItem":
+			assert.Equal(t, "attribute_not_exists(#k)", input["ConditionExpression"])
+		case "DeleteItem":
+			assert.Equal(t, "#a0 = :v0", input["ConditionExpression"])
+			assert.Equal(t, map[string]interface{}{"#a0": "ownerUid"}, input["ExpressionAttributeNames"])
+		}
+		return http.StatusBadRequest, conditionFailed
+	})
+
+	written, err := table.PutItemIfAbsent(context.Background(), map[string]string{"name": "z1-app.example.com"})
+	require.NoError(t, err)
+	assert.False(t, written)
+	assert.NoError(t, table.DeleteItem(context.Background(), "z1-app.example.com", map[string]string{"ownerUid": "uid-1"}))
+}
+
+func TestSDKItemTable_PutItemIfUnchanged(t *testing.T) {
+	table := newTestItemTable(t, func(operation string, input map[string]interface{}) (int, string) {
+		assert.Equal(t, "PutItem", operation)
+		assert.Equal(t, "attribute_exists(#k) AND #a0 = :v0 AND attribute_not_exists(#a1)", input["ConditionExpression"])
+		assert.Equal(t, map[string]interface{}{"#k": "name", "#a0": "owners", "#a1": "shared"}, input["ExpressionAttributeNames"])
+		assert.Equal(t, map[string]interface{}{":v0": map[string]interface{}{"S": "[]"}}, input["ExpressionAttributeValues"])
+		return http.StatusOK, `{}`
+	})
+
+	written, err := table.PutItemIfUnchanged(context.Background(), map[string]string{"name": "z1-app.example.com", "owners": "[1]"},
+		map[string]string{"owners": "[]", "shared": ""})
+	require.NoError(t, err)
+	assert.True(t, written)
+}
+
+func TestSDKItemTable_ScanAndErrors(t *testing.T) {
+	table := newTestItemTable(t, func(operation string, input map[string]interface{}) (int, string) {
+		if operation == "DeleteItem" {
+			return http.StatusBadRequest, `{"__type":"com.amazonaws.dynamodb.v20120810#ProvisionedThroughputExceededException","message":"slow down"}`
+		}
+		if input["ExclusiveStartKey"] == nil {
+			return http.StatusOK, `{"Items":[{"name":{"S":"a"}}],"LastEvaluatedKey":{"name":{"S":"a"}}}`
+		}
+		return http.StatusOK, `{"Items":[{"name":{"S":"b"}}]}`
+	})
+
+	items, err := table.ScanItems(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]string{{"name": "a"}, {"name": "b"}}, items)
+
+	err = table.DeleteItem(context.Background(), "a", nil)
+	assert.ErrorContains(t, err, "slow down")
+	assert.True(t, IsTransient(err))
+}
@@ -0,0 +1,29 @@
+package aws
+
+import (
+	"context"
+)
+
+// Load balancer states reported by ELBv2
+const (
+	LoadBalancerStateActive         = "active"
+	LoadBalancerStateProvisioning   = "provisioning"
+	LoadBalancerStateActiveImpaired = "active_impaired"
+	LoadBalancerStateFailed         = "failed"
+)
+
+// LoadBalancerClient reads the state of the ALBs behind managed Gateways. The ALBs themselves
+// are managed by the AWS Load Balancer Controller.
+type LoadBalancerClient interface {
+	// DescribeLoadBalancers returns every load balancer in the account and region
+	DescribeLoadBalancers(ctx context.Context) ([]LoadBalancer, error)
+}
+
+// LoadBalancer is an ELBv2 load balancer
+type LoadBalancer struct {
+	Arn     string
+	DNSName string
+	// State is one of the LoadBalancerState* values
+	State       string
+	StateReason string
+}
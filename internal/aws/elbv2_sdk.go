@@ -0,0 +1,46 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+)
+
+// SDKLoadBalancerClient implements LoadBalancerClient using AWS SDK v2
+type SDKLoadBalancerClient struct {
+	client *elbv2.Client
+}
+
+// NewSDKLoadBalancerClient creates a load balancer client using the provided AWS config
+func NewSDKLoadBalancerClient(cfg aws.Config) *SDKLoadBalancerClient {
+	return &SDKLoadBalancerClient{
+		client: elbv2.NewFromConfig(cfg),
+	}
+}
+
+func (c *SDKLoadBalancerClient) DescribeLoadBalancers(ctx context.Context) ([]LoadBalancer, error) {
+	var loadBalancers []LoadBalancer
+	paginator := elbv2.NewDescribeLoadBalancersPaginator(c.client, &elbv2.DescribeLoadBalancersInput{
+		PageSize: aws.Int32(400),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe load balancers: %w", err)
+		}
+		for _, lb := range page.LoadBalancers {
+			loadBalancer := LoadBalancer{
+				Arn:     aws.ToString(lb.LoadBalancerArn),
+				DNSName: aws.ToString(lb.DNSName),
+			}
+			if lb.State != nil {
+				loadBalancer.State = string(lb.State.Code)
+				loadBalancer.StateReason = aws.ToString(lb.State.Reason)
+			}
+			loadBalancers = append(loadBalancers, loadBalancer)
+		}
+	}
+	return loadBalancers, nil
+}
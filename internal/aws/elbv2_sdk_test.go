@@ -0,0 +1,71 @@
+package aws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testConfig returns an AWS config whose clients send every request to the test server
+func testConfig(server *httptest.Server) aws.Config {
+	return aws.Config{
+		Region:           "eu-west-1",
+		Credentials:      credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		BaseEndpoint:     aws.String(server.URL),
+		RetryMaxAttempts: 1,
+	}
+}
+
+func TestSDKLoadBalancerClient_DescribeLoadBalancers(t *testing.T) {
+	pages := map[string]string{
+		"": `<DescribeLoadBalancersResponse><DescribeLoadBalancersResult>
+  <LoadBalancers><member>
+    <LoadBalancerArn>arn:aws:elasticloadbalancing:eu-west-1:123456789012:loadbalancer/app/gw-01/1</LoadBalancerArn>
+    <DNSName>k8s-edge-gw01-1.eu-west-1.elb.amazonaws.com</DNSName>
+    <State><Code>active</Code></State>
+  </member></LoadBalancers>
+  <NextMarker>page2</NextMarker>
+</DescribeLoadBalancersResult></DescribeLoadBalancersResponse>`,
+		"page2": `<DescribeLoadBalancersResponse><DescribeLoadBalancersResult>
+  <LoadBalancers><member>
+    <LoadBalancerArn>arn:aws:elasticloadbalancing:eu-west-1:123456789012:loadbalancer/app/gw-02/2</LoadBalancerArn>
+    <DNSName>k8s-edge-gw02-2.eu-west-1.elb.amazonaws.com</DNSName>
+    <State><Code>failed</Code><Reason>subnet deleted</Reason></State>
+  </member></LoadBalancers>
+</DescribeLoadBalancersResult></DescribeLoadBalancersResponse>`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "DescribeLoadBalancers", r.Form.Get("Action"))
+		assert.Equal(t, "400", r.Form.Get("PageSize"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-west-1/elasticloadbalancing/aws4_request")
+		_, _ = w.Write([]byte(pages[r.Form.Get("Marker")]))
+	}))
+	defer server.Close()
+
+	lbs, err := NewSDKLoadBalancerClient(testConfig(server)).DescribeLoadBalancers(context.Background())
+	require.NoError(t, err)
+	require.Len(t, lbs, 2)
+	assert.Equal(t, LoadBalancerStateActive, lbs[0].State)
+	assert.Equal(t, "k8s-edge-gw02-2.eu-west-1.elb.amazonaws.com", lbs[1].DNSName)
+	assert.Equal(t, LoadBalancerStateFailed, lbs[1].State)
+	assert.Equal(t, "subnet deleted", lbs[1].StateReason)
+}
+
+func TestSDKLoadBalancerClient_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`<ErrorResponse><Error><Code>AccessDenied</Code><Message>not authorized</Message></Error></ErrorResponse>`))
+	}))
+	defer server.Close()
+
+	_, err := NewSDKLoadBalancerClient(testConfig(server)).DescribeLoadBalancers(context.Background())
+	assert.ErrorContains(t, err, "AccessDenied")
+	assert.ErrorContains(t, err, "not authorized")
+}
@@ -0,0 +1,47 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/aws/smithy-go"
+)
+
+// throttlingCodes are the error codes AWS services use for request throttling
+var throttlingCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"RequestThrottled":                       true,
+	"RequestThrottledException":              true,
+	"TooManyRequestsException":               true,
+	"RequestLimitExceeded":                   true,
+	"SlowDown":                               true,
+	"PriorRequestNotComplete":                true,
+	"ProvisionedThroughputExceededException": true,
+}
+
+// IsTransient reports whether an AWS call failed for a reason that usually goes away on its
+// own: throttling, an exhausted Route53 write budget, a server-side error, a timeout or a
+// network error. Errors such as a missing resource or denied access are not transient.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrWriteBudgetExhausted) {
+		return true
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return throttlingCodes[apiErr.ErrorCode()] || apiErr.ErrorFault() == smithy.FaultServer
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// IsNotFound reports whether an AWS call failed because the resource doesn't exist
+func IsNotFound(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "ResourceNotFoundException"
+}
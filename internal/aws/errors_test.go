@@ -0,0 +1,52 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/smithy-go"
+)
+
+func TestIsTransient(t *testing.T) {
+	apiError := func(code string, fault smithy.ErrorFault) error {
+		return fmt.Errorf("failed to describe certificate: %w", &smithy.OperationError{
+			ServiceID: "ACM", OperationName: "DescribeCertificate",
+			Err: &smithy.GenericAPIError{Code: code, Fault: fault},
+		})
+	}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"throttling", apiError("ThrottlingException", smithy.FaultClient), true},
+		{"server error", apiError("InternalFailure", smithy.FaultServer), true},
+		{"timeout", fmt.Errorf("failed to get record: %w", context.DeadlineExceeded), true},
+		{"not found", apiError("ResourceNotFoundException", smithy.FaultClient), false},
+		{"access denied", apiError("AccessDeniedException", smithy.FaultClient), false},
+		{"plain error", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransient(tt.err); got != tt.want {
+				t.Errorf("IsTransient() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	notFound := fmt.Errorf("failed to describe certificate: %w", &smithy.OperationError{
+		ServiceID: "ACM", OperationName: "DescribeCertificate",
+		Err: &smithy.GenericAPIError{Code: "ResourceNotFoundException", Fault: smithy.FaultClient},
+	})
+	if !IsNotFound(notFound) {
+		t.Error("IsNotFound() = false for ResourceNotFoundException")
+	}
+	if IsNotFound(errors.New("boom")) || IsNotFound(nil) {
+		t.Error("IsNotFound() = true for other errors")
+	}
+}
@@ -0,0 +1,318 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fake is an in-memory ACM and Route53 backend for sandboxes without AWS access, such as demos
+// on kind clusters. It builds on MockACMClient and MockRoute53Client and is safe for concurrent
+// use. Certificates are issued once their validation records exist in one of its zones, like
+// ACM does. With a path, its state is kept in a JSON file there and survives restarts.
+type Fake struct {
+	mu      sync.Mutex
+	path    string
+	acm     *MockACMClient
+	route53 *MockRoute53Client
+}
+
+// fakeCertificateValidity is how long the certificates a Fake issues are valid, like ACM's
+const fakeCertificateValidity = 395 * 24 * time.Hour
+
+// fakeState is what a Fake saves to its file
+type fakeState struct {
+	Certificates      map[string]*CertificateDetails `json:"certificates"`
+	ValidationRecords map[string][]ValidationRecord  `json:"validationRecords"`
+	IdempotencyTokens map[string]string              `json:"idempotencyTokens"`
+	Imported          map[string][]byte              `json:"imported"`
+	Tags              map[string]map[string]string   `json:"tags"`
+	Records           map[string]DNSRecord           `json:"records"`
+	HostedZones       map[string]*HostedZone         `json:"hostedZones"`
+}
+
+// NewFake returns a Fake that saves its state to path, loading it from there if the file
+// exists. An empty path keeps the state in memory only.
+func NewFake(path string) (*Fake, error) {
+	f := &Fake{path: path, acm: NewMockACMClient(), route53: NewMockRoute53Client()}
+	if path == "" {
+		return f, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fake AWS state: %w", err)
+	}
+	var state fakeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse fake AWS state %s: %w", path, err)
+	}
+	for arn, cert := range state.Certificates {
+		f.acm.Certificates[arn] = cert
+	}
+	for arn, records := range state.ValidationRecords {
+		f.acm.ValidationRecords[arn] = records
+	}
+	for token, arn := range state.IdempotencyTokens {
+		f.acm.IdempotencyTokens[token] = arn
+	}
+	for arn, certificate := range state.Imported {
+		f.acm.Imported[arn] = certificate
+	}
+	for arn, tags := range state.Tags {
+		f.acm.Tags[arn] = tags
+	}
+	for key, record := range state.Records {
+		f.route53.Records[key] = record
+	}
+	for name, zone := range state.HostedZones {
+		f.route53.HostedZones[name] = zone
+	}
+	return f, nil
+}
+
+// ACMClient returns the Fake's ACM client
+func (f *Fake) ACMClient() ACMClient {
+	return &fakeACMClient{fake: f}
+}
+
+// Route53Client returns the Fake's Route53 client
+func (f *Fake) Route53Client() Route53Client {
+	return &fakeRoute53Client{fake: f}
+}
+
+// update runs a mutation under the lock and saves the state if it succeeded
+func (f *Fake) update(mutate func() error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := mutate(); err != nil {
+		return err
+	}
+	return f.save()
+}
+
+// save writes the state to the file, through a temporary file so a crash can't truncate it.
+// Callers hold the lock.
+func (f *Fake) save() error {
+	if f.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(fakeState{
+		Certificates:      f.acm.Certificates,
+		ValidationRecords: f.acm.ValidationRecords,
+		IdempotencyTokens: f.acm.IdempotencyTokens,
+		Imported:          f.acm.Imported,
+		Tags:              f.acm.Tags,
+		Records:           f.route53.Records,
+		HostedZones:       f.route53.HostedZones,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save fake AWS state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save fake AWS state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save fake AWS state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), f.path); err != nil {
+		return fmt.Errorf("failed to save fake AWS state: %w", err)
+	}
+	return nil
+}
+
+// validated reports whether every validation record of the certificate exists in some zone.
+// Callers hold the lock.
+func (f *Fake) validated(certArn string) bool {
+	records := f.acm.ValidationRecords[certArn]
+	if len(records) == 0 {
+		return false
+	}
+	for _, want := range records {
+		found := false
+		for _, record := range f.route53.Records {
+			if strings.TrimSuffix(record.Name, ".") == strings.TrimSuffix(want.Name, ".") && record.Type == want.Type {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// fakeACMClient is the ACMClient of a Fake
+type fakeACMClient struct {
+	fake *Fake
+}
+
+func (c *fakeACMClient) RequestCertificate(ctx context.Context, domain, idempotencyToken string, tags map[string]string) (string, error) {
+	var arn string
+	err := c.fake.update(func() error {
+		var err error
+		arn, err = c.fake.acm.RequestCertificate(ctx, domain, idempotencyToken, tags)
+		return err
+	})
+	return arn, err
+}
+
+func (c *fakeACMClient) RequestCertificateWithSANs(ctx context.Context, domain string, subjectAlternativeNames []string, idempotencyToken string, tags map[string]string) (string, error) {
+	var arn string
+	err := c.fake.update(func() error {
+		var err error
+		arn, err = c.fake.acm.RequestCertificateWithSANs(ctx, domain, subjectAlternativeNames, idempotencyToken, tags)
+		return err
+	})
+	return arn, err
+}
+
+// DescribeCertificate issues a pending certificate once its validation records exist
+func (c *fakeACMClient) DescribeCertificate(ctx context.Context, certArn string) (*CertificateDetails, error) {
+	c.fake.mu.Lock()
+	defer c.fake.mu.Unlock()
+	cert, err := c.fake.acm.DescribeCertificate(ctx, certArn)
+	if err != nil {
+		return nil, err
+	}
+	if cert.Status == "PENDING_VALIDATION" && c.fake.validated(certArn) {
+		cert.Status = "ISSUED"
+		cert.NotAfter = time.Now().Add(fakeCertificateValidity).UTC().Truncate(time.Second)
+		if err := c.fake.save(); err != nil {
+			return nil, err
+		}
+	}
+	details := *cert
+	return &details, nil
+}
+
+func (c *fakeACMClient) DeleteCertificate(ctx context.Context, certArn string) error {
+	return c.fake.update(func() error {
+		delete(c.fake.acm.Tags, certArn)
+		return c.fake.acm.DeleteCertificate(ctx, certArn)
+	})
+}
+
+func (c *fakeACMClient) GetValidationRecords(ctx context.Context, certArn string) ([]ValidationRecord, error) {
+	c.fake.mu.Lock()
+	defer c.fake.mu.Unlock()
+	records, err := c.fake.acm.GetValidationRecords(ctx, certArn)
+	return append([]ValidationRecord(nil), records...), err
+}
+
+func (c *fakeACMClient) ImportCertificate(ctx context.Context, certArn string, certificate, privateKey, chain []byte, tags map[string]string) (string, error) {
+	var arn string
+	err := c.fake.update(func() error {
+		var err error
+		arn, err = c.fake.acm.ImportCertificate(ctx, certArn, certificate, privateKey, chain, tags)
+		return err
+	})
+	return arn, err
+}
+
+func (c *fakeACMClient) ListCertificates(ctx context.Context) ([]CertificateSummary, error) {
+	c.fake.mu.Lock()
+	defer c.fake.mu.Unlock()
+	return c.fake.acm.ListCertificates(ctx)
+}
+
+func (c *fakeACMClient) GetCertificateTags(ctx context.Context, certArn string) (map[string]string, error) {
+	c.fake.mu.Lock()
+	defer c.fake.mu.Unlock()
+	return c.fake.acm.GetCertificateTags(ctx, certArn)
+}
+
+// fakeRoute53Client is the Route53Client of a Fake
+type fakeRoute53Client struct {
+	fake *Fake
+}
+
+func (c *fakeRoute53Client) CreateOrUpdateRecord(ctx context.Context, zoneId string, record DNSRecord) error {
+	return c.fake.update(func() error {
+		return c.fake.route53.CreateOrUpdateRecord(ctx, zoneId, record)
+	})
+}
+
+func (c *fakeRoute53Client) DeleteRecord(ctx context.Context, zoneId string, record DNSRecord) error {
+	return c.fake.update(func() error {
+		return c.fake.route53.DeleteRecord(ctx, zoneId, record)
+	})
+}
+
+func (c *fakeRoute53Client) DeleteRecords(ctx context.Context, zoneId string, records []DNSRecord) error {
+	return c.fake.update(func() error {
+		return c.fake.route53.DeleteRecords(ctx, zoneId, records)
+	})
+}
+
+func (c *fakeRoute53Client) GetRecord(ctx context.Context, zoneId string, name, recordType string) (*DNSRecord, error) {
+	c.fake.mu.Lock()
+	defer c.fake.mu.Unlock()
+	return c.fake.route53.GetRecord(ctx, zoneId, name, recordType)
+}
+
+func (c *fakeRoute53Client) GetDNSSEC(ctx context.Context, zoneId string) (*DNSSECStatus, error) {
+	c.fake.mu.Lock()
+	defer c.fake.mu.Unlock()
+	return c.fake.route53.GetDNSSEC(ctx, zoneId)
+}
+
+func (c *fakeRoute53Client) GetHostedZoneByName(ctx context.Context, name string) (*HostedZone, error) {
+	c.fake.mu.Lock()
+	defer c.fake.mu.Unlock()
+	return copyHostedZone(c.fake.route53.GetHostedZoneByName(ctx, name))
+}
+
+func (c *fakeRoute53Client) CreateHostedZone(ctx context.Context, name, callerReference string) (*HostedZone, error) {
+	var zone *HostedZone
+	err := c.fake.update(func() error {
+		var err error
+		zone, err = copyHostedZone(c.fake.route53.CreateHostedZone(ctx, name, callerReference))
+		return err
+	})
+	return zone, err
+}
+
+func (c *fakeRoute53Client) GetHostedZone(ctx context.Context, zoneId string) (*HostedZone, error) {
+	c.fake.mu.Lock()
+	defer c.fake.mu.Unlock()
+	return copyHostedZone(c.fake.route53.GetHostedZone(ctx, zoneId))
+}
+
+func (c *fakeRoute53Client) AssociateVPC(ctx context.Context, zoneId string, vpc VPC) error {
+	return c.fake.update(func() error {
+		return c.fake.route53.AssociateVPC(ctx, zoneId, vpc)
+	})
+}
+
+func (c *fakeRoute53Client) DisassociateVPC(ctx context.Context, zoneId string, vpc VPC) error {
+	return c.fake.update(func() error {
+		return c.fake.route53.DisassociateVPC(ctx, zoneId, vpc)
+	})
+}
+
+// copyHostedZone copies a zone the mock returned, so callers don't share it with the Fake
+func copyHostedZone(zone *HostedZone, err error) (*HostedZone, error) {
+	if zone == nil || err != nil {
+		return nil, err
+	}
+	zoneCopy := *zone
+	zoneCopy.NameServers = append([]string(nil), zone.NameServers...)
+	zoneCopy.VPCs = append([]VPC(nil), zone.VPCs...)
+	return &zoneCopy, nil
+}
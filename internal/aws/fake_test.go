@@ -0,0 +1,69 @@
+package aws
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFake_IssuesValidatedCertificates(t *testing.T) {
+	ctx := context.Background()
+	fake, err := NewFake("")
+	require.NoError(t, err)
+	acm, route53 := fake.ACMClient(), fake.Route53Client()
+
+	arn, err := acm.RequestCertificate(ctx, "app.example.com", "token", nil)
+	require.NoError(t, err)
+	cert, err := acm.DescribeCertificate(ctx, arn)
+	require.NoError(t, err)
+	assert.Equal(t, "PENDING_VALIDATION", cert.Status)
+
+	records, err := acm.GetValidationRecords(ctx, arn)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.NoError(t, route53.CreateOrUpdateRecord(ctx, "Z123", DNSRecord{
+		Name: records[0].Name + ".", Type: records[0].Type, Value: records[0].Value, TTL: 300,
+	}))
+
+	cert, err = acm.DescribeCertificate(ctx, arn)
+	require.NoError(t, err)
+	assert.Equal(t, "ISSUED", cert.Status)
+}
+
+func TestFake_KeepsStateInFile(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "aws.json")
+	fake, err := NewFake(path)
+	require.NoError(t, err)
+
+	arn, err := fake.ACMClient().RequestCertificate(ctx, "app.example.com", "token", map[string]string{"hostname": "app.example.com"})
+	require.NoError(t, err)
+	alias := DNSRecord{Name: "app.example.com", Type: "A", AliasTarget: &AliasTarget{DNSName: "k8s-edge-gw01.eu-west-1.elb.amazonaws.com"}}
+	require.NoError(t, fake.Route53Client().CreateOrUpdateRecord(ctx, "Z123", alias))
+	zone, err := fake.Route53Client().CreateHostedZone(ctx, "team-a.example.com", "ref")
+	require.NoError(t, err)
+
+	restarted, err := NewFake(path)
+	require.NoError(t, err)
+	again, err := restarted.ACMClient().RequestCertificate(ctx, "app.example.com", "token", nil)
+	require.NoError(t, err)
+	assert.Equal(t, arn, again, "idempotency tokens survive restarts")
+	tags, err := restarted.ACMClient().GetCertificateTags(ctx, arn)
+	require.NoError(t, err)
+	assert.Equal(t, "app.example.com", tags["hostname"])
+	record, err := restarted.Route53Client().GetRecord(ctx, "Z123", "app.example.com", "A")
+	require.NoError(t, err)
+	assert.Equal(t, &alias, record)
+	found, err := restarted.Route53Client().GetHostedZoneByName(ctx, "team-a.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, zone, found)
+
+	require.NoError(t, restarted.ACMClient().DeleteCertificate(ctx, arn))
+	restarted, err = NewFake(path)
+	require.NoError(t, err)
+	_, err = restarted.ACMClient().DescribeCertificate(ctx, arn)
+	assert.Error(t, err)
+}
@@ -0,0 +1,385 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/smithy-go"
+)
+
+// AllOperations is the operation name of a fault that applies to every operation without a
+// fault of its own
+const AllOperations = "*"
+
+// Fault is how calls of one AWS operation misbehave
+type Fault struct {
+	// Latency delays every call
+	Latency time.Duration
+
+	// ThrottleRate is the fraction of calls (0 to 1) that fail with a Throttling error without
+	// reaching AWS
+	ThrottleRate float64
+
+	// PartialRate is the fraction of calls (0 to 1) that reach AWS and then fail as if the
+	// response was lost: the change is made but the caller sees an error
+	PartialRate float64
+
+	// FailNext is the number of upcoming calls that fail with a Throttling error
+	FailNext int
+}
+
+// FaultInjector makes AWS calls fail for chaos tests of the controller's retry and cleanup
+// behavior. Operations are named after the client methods, e.g. CreateOrUpdateRecord.
+// It is wired into the controller only in builds with the faultinject tag.
+type FaultInjector struct {
+	mu     sync.Mutex
+	faults map[string]*Fault
+	calls  map[string]int
+	rand   *rand.Rand
+}
+
+// NewFaultInjector returns an injector without faults. The seed makes the random failures
+// of ThrottleRate and PartialRate reproducible.
+func NewFaultInjector(seed int64) *FaultInjector {
+	return &FaultInjector{
+		faults: make(map[string]*Fault),
+		calls:  make(map[string]int),
+		rand:   rand.New(rand.NewSource(seed)),
+	}
+}
+
+// ParseFaults parses semicolon-separated "<operation>:<key>=<value>,..." entries, e.g.
+// "CreateOrUpdateRecord:throttle=0.3,latency=200ms;*:latency=50ms;DeleteCertificate:failnext=2".
+// Keys are latency, throttle, partial and failnext.
+func ParseFaults(s string) (map[string]Fault, error) {
+	faults := make(map[string]Fault)
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		operation, settings, ok := strings.Cut(entry, ":")
+		operation = strings.TrimSpace(operation)
+		if !ok || operation == "" {
+			return nil, fmt.Errorf("invalid fault %q, expected <operation>:<key>=<value>,...", entry)
+		}
+		var fault Fault
+		for _, setting := range strings.Split(settings, ",") {
+			key, value, _ := strings.Cut(strings.TrimSpace(setting), "=")
+			var err error
+			switch key {
+			case "latency":
+				fault.Latency, err = time.ParseDuration(value)
+			case "throttle":
+				fault.ThrottleRate, err = parseRate(value)
+			case "partial":
+				fault.PartialRate, err = parseRate(value)
+			case "failnext":
+				fault.FailNext, err = strconv.Atoi(value)
+			default:
+				err = fmt.Errorf("unknown key %q", key)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid fault %q: %w", entry, err)
+			}
+		}
+		faults[operation] = fault
+	}
+	return faults, nil
+}
+
+func parseRate(s string) (float64, error) {
+	rate, err := strconv.ParseFloat(s, 64)
+	if err != nil || rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("rate %q must be between 0 and 1", s)
+	}
+	return rate, nil
+}
+
+// Set replaces the fault of an operation, or of AllOperations
+func (f *FaultInjector) Set(operation string, fault Fault) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults[operation] = &fault
+}
+
+// FailNext makes the next n calls of the operation fail with a Throttling error
+func (f *FaultInjector) FailNext(operation string, n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fault, ok := f.faults[operation]
+	if !ok {
+		fault = &Fault{}
+		f.faults[operation] = fault
+	}
+	fault.FailNext = n
+}
+
+// Reset removes all faults
+func (f *FaultInjector) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = make(map[string]*Fault)
+}
+
+// Calls returns how often the operation was called, including failed calls
+func (f *FaultInjector) Calls(operation string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[operation]
+}
+
+// decide counts a call and picks its fate
+func (f *FaultInjector) decide(operation string) (latency time.Duration, throttle, partial bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[operation]++
+	fault, ok := f.faults[operation]
+	if !ok {
+		fault, ok = f.faults[AllOperations]
+	}
+	if !ok {
+		return 0, false, false
+	}
+	if fault.FailNext > 0 {
+		fault.FailNext--
+		throttle = true
+	} else if fault.ThrottleRate > 0 && f.rand.Float64() < fault.ThrottleRate {
+		throttle = true
+	}
+	partial = !throttle && fault.PartialRate > 0 && f.rand.Float64() < fault.PartialRate
+	return fault.Latency, throttle, partial
+}
+
+// call runs fn, the AWS call, with the operation's faults
+func (f *FaultInjector) call(ctx context.Context, operation string, fn func() error) error {
+	latency, throttle, partial := f.decide(operation)
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if throttle {
+		return &smithy.OperationError{ServiceID: "injected", OperationName: operation, Err: &smithy.GenericAPIError{
+			Code: "Throttling", Message: "Rate exceeded (injected)", Fault: smithy.FaultClient,
+		}}
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	if partial {
+		return &smithy.OperationError{ServiceID: "injected", OperationName: operation, Err: &smithy.GenericAPIError{
+			Code: "InternalFailure", Message: "response lost after the request was applied (injected)", Fault: smithy.FaultServer,
+		}}
+	}
+	return nil
+}
+
+// FaultInjectingACMClient passes calls to an ACM client through a FaultInjector
+type FaultInjectingACMClient struct {
+	inner  ACMClient
+	faults *FaultInjector
+}
+
+// NewFaultInjectingACMClient wraps inner with the injector's faults
+func NewFaultInjectingACMClient(inner ACMClient, faults *FaultInjector) *FaultInjectingACMClient {
+	return &FaultInjectingACMClient{inner: inner, faults: faults}
+}
+
+func (c *FaultInjectingACMClient) RequestCertificate(ctx context.Context, domain, idempotencyToken string, tags map[string]string) (string, error) {
+	var certArn string
+	err := c.faults.call(ctx, "RequestCertificate", func() (err error) {
+		certArn, err = c.inner.RequestCertificate(ctx, domain, idempotencyToken, tags)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return certArn, nil
+}
+
+func (c *FaultInjectingACMClient) RequestCertificateWithSANs(ctx context.Context, domain string, subjectAlternativeNames []string, idempotencyToken string, tags map[string]string) (string, error) {
+	var certArn string
+	err := c.faults.call(ctx, "RequestCertificate", func() (err error) {
+		certArn, err = c.inner.RequestCertificateWithSANs(ctx, domain, subjectAlternativeNames, idempotencyToken, tags)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return certArn, nil
+}
+
+func (c *FaultInjectingACMClient) DescribeCertificate(ctx context.Context, certArn string) (*CertificateDetails, error) {
+	var details *CertificateDetails
+	err := c.faults.call(ctx, "DescribeCertificate", func() (err error) {
+		details, err = c.inner.DescribeCertificate(ctx, certArn)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+func (c *FaultInjectingACMClient) DeleteCertificate(ctx context.Context, certArn string) error {
+	return c.faults.call(ctx, "DeleteCertificate", func() error {
+		return c.inner.DeleteCertificate(ctx, certArn)
+	})
+}
+
+func (c *FaultInjectingACMClient) GetValidationRecords(ctx context.Context, certArn string) ([]ValidationRecord, error) {
+	var records []ValidationRecord
+	err := c.faults.call(ctx, "GetValidationRecords", func() (err error) {
+		records, err = c.inner.GetValidationRecords(ctx, certArn)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (c *FaultInjectingACMClient) ImportCertificate(ctx context.Context, certArn string, certificate, privateKey, chain []byte, tags map[string]string) (string, error) {
+	var arn string
+	err := c.faults.call(ctx, "ImportCertificate", func() (err error) {
+		arn, err = c.inner.ImportCertificate(ctx, certArn, certificate, privateKey, chain, tags)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return arn, nil
+}
+
+func (c *FaultInjectingACMClient) ListCertificates(ctx context.Context) ([]CertificateSummary, error) {
+	var summaries []CertificateSummary
+	err := c.faults.call(ctx, "ListCertificates", func() (err error) {
+		summaries, err = c.inner.ListCertificates(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+func (c *FaultInjectingACMClient) GetCertificateTags(ctx context.Context, certArn string) (map[string]string, error) {
+	var tags map[string]string
+	err := c.faults.call(ctx, "GetCertificateTags", func() (err error) {
+		tags, err = c.inner.GetCertificateTags(ctx, certArn)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// FaultInjectingRoute53Client passes calls to a Route53 client through a FaultInjector
+type FaultInjectingRoute53Client struct {
+	inner  Route53Client
+	faults *FaultInjector
+}
+
+// NewFaultInjectingRoute53Client wraps inner with the injector's faults
+func NewFaultInjectingRoute53Client(inner Route53Client, faults *FaultInjector) *FaultInjectingRoute53Client {
+	return &FaultInjectingRoute53Client{inner: inner, faults: faults}
+}
+
+func (c *FaultInjectingRoute53Client) CreateOrUpdateRecord(ctx context.Context, zoneId string, record DNSRecord) error {
+	return c.faults.call(ctx, "CreateOrUpdateRecord", func() error {
+		return c.inner.CreateOrUpdateRecord(ctx, zoneId, record)
+	})
+}
+
+func (c *FaultInjectingRoute53Client) DeleteRecord(ctx context.Context, zoneId string, record DNSRecord) error {
+	return c.faults.call(ctx, "DeleteRecord", func() error {
+		return c.inner.DeleteRecord(ctx, zoneId, record)
+	})
+}
+
+func (c *FaultInjectingRoute53Client) DeleteRecords(ctx context.Context, zoneId string, records []DNSRecord) error {
+	return c.faults.call(ctx, "DeleteRecords", func() error {
+		return c.inner.DeleteRecords(ctx, zoneId, records)
+	})
+}
+
+func (c *FaultInjectingRoute53Client) GetRecord(ctx context.Context, zoneId string, name, recordType string) (*DNSRecord, error) {
+	var record *DNSRecord
+	err := c.faults.call(ctx, "GetRecord", func() (err error) {
+		record, err = c.inner.GetRecord(ctx, zoneId, name, recordType)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (c *FaultInjectingRoute53Client) GetDNSSEC(ctx context.Context, zoneId string) (*DNSSECStatus, error) {
+	var status *DNSSECStatus
+	err := c.faults.call(ctx, "GetDNSSEC", func() (err error) {
+		status, err = c.inner.GetDNSSEC(ctx, zoneId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func (c *FaultInjectingRoute53Client) GetHostedZoneByName(ctx context.Context, name string) (*HostedZone, error) {
+	var zone *HostedZone
+	err := c.faults.call(ctx, "GetHostedZoneByName", func() (err error) {
+		zone, err = c.inner.GetHostedZoneByName(ctx, name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return zone, nil
+}
+
+func (c *FaultInjectingRoute53Client) CreateHostedZone(ctx context.Context, name, callerReference string) (*HostedZone, error) {
+	var zone *HostedZone
+	err := c.faults.call(ctx, "CreateHostedZone", func() (err error) {
+		zone, err = c.inner.CreateHostedZone(ctx, name, callerReference)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return zone, nil
+}
+
+func (c *FaultInjectingRoute53Client) GetHostedZone(ctx context.Context, zoneId string) (*HostedZone, error) {
+	var zone *HostedZone
+	err := c.faults.call(ctx, "GetHostedZone", func() (err error) {
+		zone, err = c.inner.GetHostedZone(ctx, zoneId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return zone, nil
+}
+
+func (c *FaultInjectingRoute53Client) AssociateVPC(ctx context.Context, zoneId string, vpc VPC) error {
+	return c.faults.call(ctx, "AssociateVPC", func() error {
+		return c.inner.AssociateVPC(ctx, zoneId, vpc)
+	})
+}
+
+func (c *FaultInjectingRoute53Client) DisassociateVPC(ctx context.Context, zoneId string, vpc VPC) error {
+	return c.faults.call(ctx, "DisassociateVPC", func() error {
+		return c.inner.DisassociateVPC(ctx, zoneId, vpc)
+	})
+}
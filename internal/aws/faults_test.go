@@ -0,0 +1,135 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+)
+
+func TestParseFaults(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      map[string]Fault
+		wantError bool
+	}{
+		{
+			name:  "empty",
+			input: "",
+			want:  map[string]Fault{},
+		},
+		{
+			name:  "several operations",
+			input: "CreateOrUpdateRecord:throttle=0.3,latency=200ms; *:latency=50ms;DeleteCertificate:partial=1,failnext=2",
+			want: map[string]Fault{
+				"CreateOrUpdateRecord": {ThrottleRate: 0.3, Latency: 200 * time.Millisecond},
+				AllOperations:          {Latency: 50 * time.Millisecond},
+				"DeleteCertificate":    {PartialRate: 1, FailNext: 2},
+			},
+		},
+		{
+			name:      "missing operation",
+			input:     ":latency=1s",
+			wantError: true,
+		},
+		{
+			name:      "rate above one",
+			input:     "GetRecord:throttle=2",
+			wantError: true,
+		},
+		{
+			name:      "unknown key",
+			input:     "GetRecord:explode=1",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFaults(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseFaults() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseFaults() = %v, want %v", got, tt.want)
+			}
+			for operation, fault := range tt.want {
+				if got[operation] != fault {
+					t.Errorf("fault for %s = %+v, want %+v", operation, got[operation], fault)
+				}
+			}
+		})
+	}
+}
+
+func TestFaultInjector_FailNext(t *testing.T) {
+	ctx := context.Background()
+	faults := NewFaultInjector(1)
+	client := NewFaultInjectingRoute53Client(NewMockRoute53Client(), faults)
+	record := DNSRecord{Name: "app.example.com", Type: "A"}
+
+	faults.FailNext("CreateOrUpdateRecord", 2)
+	for i := 0; i < 2; i++ {
+		err := client.CreateOrUpdateRecord(ctx, "Z1", record)
+		var apiErr smithy.APIError
+		if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "Throttling" {
+			t.Fatalf("call %d: got %v, want a Throttling error", i+1, err)
+		}
+	}
+	if err := client.CreateOrUpdateRecord(ctx, "Z1", record); err != nil {
+		t.Fatalf("third call: %v", err)
+	}
+	if got, _ := client.GetRecord(ctx, "Z1", "app.example.com", "A"); got == nil {
+		t.Error("the record was not written after the failures")
+	}
+	if got := faults.Calls("CreateOrUpdateRecord"); got != 3 {
+		t.Errorf("Calls() = %d, want 3", got)
+	}
+}
+
+func TestFaultInjector_PartialFailureApplies(t *testing.T) {
+	ctx := context.Background()
+	faults := NewFaultInjector(1)
+	mock := NewMockRoute53Client()
+	client := NewFaultInjectingRoute53Client(mock, faults)
+
+	faults.Set("CreateOrUpdateRecord", Fault{PartialRate: 1})
+	err := client.CreateOrUpdateRecord(ctx, "Z1", DNSRecord{Name: "app.example.com", Type: "A"})
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorFault() != smithy.FaultServer {
+		t.Fatalf("got %v, want a server error", err)
+	}
+	if got, _ := mock.GetRecord(ctx, "Z1", "app.example.com", "A"); got == nil {
+		t.Error("a partial failure must still apply the change")
+	}
+}
+
+func TestFaultInjector_DefaultAndLatency(t *testing.T) {
+	faults := NewFaultInjector(1)
+	client := NewFaultInjectingACMClient(NewMockACMClient(), faults)
+
+	faults.Set(AllOperations, Fault{Latency: time.Hour})
+	faults.Set("ListCertificates", Fault{})
+	if _, err := client.ListCertificates(context.Background()); err != nil {
+		t.Fatalf("an operation's own fault overrides the default: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.DescribeCertificate(ctx, "arn"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want the context's error while waiting out the latency", err)
+	}
+
+	faults.Reset()
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := client.DescribeCertificate(ctx, "arn"); errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Reset() left the latency in place: %v", err)
+	}
+}
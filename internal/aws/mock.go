@@ -1,8 +1,17 @@
 package aws
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/smithy-go"
 )
 
 // MockACMClient is a mock implementation for testing
@@ -10,6 +19,9 @@ type MockACMClient struct {
 	Certificates      map[string]*CertificateDetails
 	ValidationRecords map[string][]ValidationRecord
 	InUseBy           map[string][]string // certArn -> list of resource ARNs using it
+	IdempotencyTokens map[string]string   // idempotency token -> certArn
+	Imported          map[string][]byte   // certArn -> last imported certificate
+	Tags              map[string]map[string]string
 }
 
 func NewMockACMClient() *MockACMClient {
@@ -17,22 +29,40 @@ func NewMockACMClient() *MockACMClient {
 		Certificates:      make(map[string]*CertificateDetails),
 		ValidationRecords: make(map[string][]ValidationRecord),
 		InUseBy:           make(map[string][]string),
+		IdempotencyTokens: make(map[string]string),
+		Imported:          make(map[string][]byte),
+		Tags:              make(map[string]map[string]string),
 	}
 }
 
-func (m *MockACMClient) RequestCertificate(ctx context.Context, domain string, tags map[string]string) (string, error) {
+func (m *MockACMClient) RequestCertificate(ctx context.Context, domain, idempotencyToken string, tags map[string]string) (string, error) {
+	return m.RequestCertificateWithSANs(ctx, domain, nil, idempotencyToken, tags)
+}
+
+func (m *MockACMClient) RequestCertificateWithSANs(ctx context.Context, domain string, subjectAlternativeNames []string, idempotencyToken string, tags map[string]string) (string, error) {
+	if arn, ok := m.IdempotencyTokens[idempotencyToken]; ok && idempotencyToken != "" {
+		return arn, nil
+	}
 	arn := fmt.Sprintf("arn:aws:acm:us-east-1:123456789012:certificate/%s", domain)
+	if idempotencyToken != "" {
+		arn = fmt.Sprintf("%s-%s", arn, idempotencyToken)
+		m.IdempotencyTokens[idempotencyToken] = arn
+	}
+	names := append([]string{domain}, subjectAlternativeNames...)
 	m.Certificates[arn] = &CertificateDetails{
-		Arn:    arn,
-		Domain: domain,
-		Status: "PENDING_VALIDATION",
+		Arn:                     arn,
+		Domain:                  domain,
+		Status:                  "PENDING_VALIDATION",
+		SubjectAlternativeNames: names,
 	}
-	m.ValidationRecords[arn] = []ValidationRecord{
-		{
-			Name:  fmt.Sprintf("_acm-validation.%s", domain),
+	m.Tags[arn] = tags
+	m.ValidationRecords[arn] = nil
+	for _, name := range names {
+		m.ValidationRecords[arn] = append(m.ValidationRecords[arn], ValidationRecord{
+			Name:  fmt.Sprintf("_acm-validation.%s", name),
 			Type:  "CNAME",
 			Value: fmt.Sprintf("_validation-value.acm-validations.aws."),
-		},
+		})
 	}
 	return arn, nil
 }
@@ -71,34 +101,341 @@ func (m *MockACMClient) GetValidationRecords(ctx context.Context, certArn string
 	return records, nil
 }
 
+func (m *MockACMClient) ImportCertificate(ctx context.Context, certArn string, certificate, privateKey, chain []byte, tags map[string]string) (string, error) {
+	if certArn == "" {
+		certArn = fmt.Sprintf("arn:aws:acm:us-east-1:123456789012:certificate/imported-%d", len(m.Imported)+1)
+		m.Certificates[certArn] = &CertificateDetails{
+			Arn:    certArn,
+			Domain: tags["hostname"],
+			Status: "ISSUED",
+		}
+		// Imported certificates have no validation records
+		m.ValidationRecords[certArn] = nil
+		m.Tags[certArn] = tags
+	}
+	m.Imported[certArn] = certificate
+	return certArn, nil
+}
+
+func (m *MockACMClient) ListCertificates(ctx context.Context) ([]CertificateSummary, error) {
+	var summaries []CertificateSummary
+	for arn, cert := range m.Certificates {
+		summaries = append(summaries, CertificateSummary{
+			Arn:    arn,
+			Domain: cert.Domain,
+			Status: cert.Status,
+			InUse:  len(m.InUseBy[arn]) > 0,
+			Tags:   m.Tags[arn],
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Arn < summaries[j].Arn })
+	return summaries, nil
+}
+
+func (m *MockACMClient) GetCertificateTags(ctx context.Context, certArn string) (map[string]string, error) {
+	if _, ok := m.Certificates[certArn]; !ok {
+		return nil, fmt.Errorf("certificate not found: %s", certArn)
+	}
+	return m.Tags[certArn], nil
+}
+
 // MockRoute53Client is a mock implementation for testing
 type MockRoute53Client struct {
-	Records map[string]DNSRecord // key: zoneId:name:type
+	Records     map[string]DNSRecord     // key: zoneId:name:type
+	DNSSEC      map[string]*DNSSECStatus // key: zoneId; zones without an entry are not signed
+	HostedZones map[string]*HostedZone   // key: zone name
 }
 
 func NewMockRoute53Client() *MockRoute53Client {
 	return &MockRoute53Client{
-		Records: make(map[string]DNSRecord),
+		Records:     make(map[string]DNSRecord),
+		DNSSEC:      make(map[string]*DNSSECStatus),
+		HostedZones: make(map[string]*HostedZone),
 	}
 }
 
+// mockRecordKey returns the key of a record in MockRoute53Client.Records; weighted record
+// sets end in their set identifier
+func mockRecordKey(zoneId, name, recordType, setIdentifier string) string {
+	key := fmt.Sprintf("%s:%s:%s", zoneId, name, recordType)
+	if setIdentifier != "" {
+		key += ":" + setIdentifier
+	}
+	return key
+}
+
 func (m *MockRoute53Client) CreateOrUpdateRecord(ctx context.Context, zoneId string, record DNSRecord) error {
-	key := fmt.Sprintf("%s:%s:%s", zoneId, record.Name, record.Type)
-	m.Records[key] = record
+	m.Records[mockRecordKey(zoneId, record.Name, record.Type, record.SetIdentifier)] = record
 	return nil
 }
 
 func (m *MockRoute53Client) DeleteRecord(ctx context.Context, zoneId string, record DNSRecord) error {
-	key := fmt.Sprintf("%s:%s:%s", zoneId, record.Name, record.Type)
-	delete(m.Records, key)
+	delete(m.Records, mockRecordKey(zoneId, record.Name, record.Type, record.SetIdentifier))
+	return nil
+}
+
+func (m *MockRoute53Client) DeleteRecords(ctx context.Context, zoneId string, records []DNSRecord) error {
+	for _, record := range records {
+		if _, ok := m.Records[mockRecordKey(zoneId, record.Name, record.Type, record.SetIdentifier)]; !ok {
+			return fmt.Errorf("record %s %s was not found in zone %s", record.Name, record.Type, zoneId)
+		}
+	}
+	for _, record := range records {
+		delete(m.Records, mockRecordKey(zoneId, record.Name, record.Type, record.SetIdentifier))
+	}
 	return nil
 }
 
 func (m *MockRoute53Client) GetRecord(ctx context.Context, zoneId string, name, recordType string) (*DNSRecord, error) {
-	key := fmt.Sprintf("%s:%s:%s", zoneId, name, recordType)
+	key := mockRecordKey(zoneId, name, recordType, setIdentifierFromContext(ctx))
 	record, ok := m.Records[key]
 	if !ok {
-		return nil, fmt.Errorf("record not found")
+		return nil, nil
 	}
 	return &record, nil
 }
+
+func (m *MockRoute53Client) GetDNSSEC(ctx context.Context, zoneId string) (*DNSSECStatus, error) {
+	if status, ok := m.DNSSEC[zoneId]; ok {
+		return status, nil
+	}
+	return &DNSSECStatus{ServeSignature: "NOT_SIGNING"}, nil
+}
+
+func (m *MockRoute53Client) GetHostedZoneByName(ctx context.Context, name string) (*HostedZone, error) {
+	return m.HostedZones[name], nil
+}
+
+func (m *MockRoute53Client) CreateHostedZone(ctx context.Context, name, callerReference string) (*HostedZone, error) {
+	if _, ok := m.HostedZones[name]; ok {
+		return nil, fmt.Errorf("hosted zone %s already exists", name)
+	}
+	zone := &HostedZone{
+		ID:          fmt.Sprintf("Z%dMOCK", len(m.HostedZones)+1),
+		Name:        name,
+		NameServers: []string{"ns-1.awsdns-01.org", "ns-2.awsdns-02.com"},
+	}
+	m.HostedZones[name] = zone
+	return zone, nil
+}
+
+func (m *MockRoute53Client) GetHostedZone(ctx context.Context, zoneId string) (*HostedZone, error) {
+	for _, zone := range m.HostedZones {
+		if zone.ID == zoneId {
+			return zone, nil
+		}
+	}
+	return nil, fmt.Errorf("no hosted zone %s", zoneId)
+}
+
+func (m *MockRoute53Client) AssociateVPC(ctx context.Context, zoneId string, vpc VPC) error {
+	zone, err := m.GetHostedZone(ctx, zoneId)
+	if err != nil {
+		return err
+	}
+	if !zone.Private {
+		return fmt.Errorf("hosted zone %s is public", zoneId)
+	}
+	if slices.Contains(zone.VPCs, vpc) {
+		return fmt.Errorf("VPC %s is already associated with hosted zone %s", vpc, zoneId)
+	}
+	zone.VPCs = append(zone.VPCs, vpc)
+	return nil
+}
+
+func (m *MockRoute53Client) DisassociateVPC(ctx context.Context, zoneId string, vpc VPC) error {
+	zone, err := m.GetHostedZone(ctx, zoneId)
+	if err != nil {
+		return err
+	}
+	zone.VPCs = slices.DeleteFunc(zone.VPCs, func(v VPC) bool { return v == vpc })
+	return nil
+}
+
+// MockLoadBalancerClient is a mock implementation for testing
+type MockLoadBalancerClient struct {
+	LoadBalancers []LoadBalancer
+	Err           error
+}
+
+func (m *MockLoadBalancerClient) DescribeLoadBalancers(ctx context.Context) ([]LoadBalancer, error) {
+	return m.LoadBalancers, m.Err
+}
+
+// MockZonalShift is a zonal shift started on a MockZonalShiftClient
+type MockZonalShift struct {
+	ResourceArn string
+	AwayFrom    string
+	ExpiresIn   time.Duration
+}
+
+// MockZonalShiftClient is a mock implementation for testing
+type MockZonalShiftClient struct {
+	// Shifts are the active shifts by ID
+	Shifts map[string]MockZonalShift
+	next   int
+}
+
+func (m *MockZonalShiftClient) StartZonalShift(ctx context.Context, resourceArn, awayFrom string, expiresIn time.Duration, comment string) (string, error) {
+	if m.Shifts == nil {
+		m.Shifts = make(map[string]MockZonalShift)
+	}
+	m.next++
+	id := fmt.Sprintf("shift-%d", m.next)
+	m.Shifts[id] = MockZonalShift{ResourceArn: resourceArn, AwayFrom: awayFrom, ExpiresIn: expiresIn}
+	return id, nil
+}
+
+func (m *MockZonalShiftClient) CancelZonalShift(ctx context.Context, zonalShiftID string) error {
+	if _, ok := m.Shifts[zonalShiftID]; !ok {
+		return &smithy.GenericAPIError{Code: "ResourceNotFoundException", Message: "zonal shift " + zonalShiftID + " not found"}
+	}
+	delete(m.Shifts, zonalShiftID)
+	return nil
+}
+
+// MockAlarmClient is a mock implementation for testing
+type MockAlarmClient struct {
+	Alarms map[string]MetricAlarm
+	// Puts counts PutMetricAlarm calls
+	Puts int
+}
+
+func (m *MockAlarmClient) PutMetricAlarm(ctx context.Context, alarm MetricAlarm) error {
+	if m.Alarms == nil {
+		m.Alarms = make(map[string]MetricAlarm)
+	}
+	m.Alarms[alarm.Name] = alarm
+	m.Puts++
+	return nil
+}
+
+func (m *MockAlarmClient) DescribeAlarmNames(ctx context.Context, prefix string) ([]string, error) {
+	var names []string
+	for name := range m.Alarms {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (m *MockAlarmClient) DeleteAlarms(ctx context.Context, names []string) error {
+	for _, name := range names {
+		delete(m.Alarms, name)
+	}
+	return nil
+}
+
+// MockObjectStore is a mock implementation for testing
+type MockObjectStore struct {
+	Objects map[string][]byte
+}
+
+func (m *MockObjectStore) ListObjects(ctx context.Context, prefix, delimiter string) ([]string, []string, error) {
+	var keys, prefixes []string
+	seen := map[string]bool{}
+	for key := range m.Objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if delimiter != "" {
+			if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+				p := key[:len(prefix)+i+len(delimiter)]
+				if !seen[p] {
+					seen[p] = true
+					prefixes = append(prefixes, p)
+				}
+				continue
+			}
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	sort.Strings(prefixes)
+	return keys, prefixes, nil
+}
+
+func (m *MockObjectStore) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	body, ok := m.Objects[key]
+	if !ok {
+		return nil, fmt.Errorf("NoSuchKey: %s", key)
+	}
+	return io.NopCloser(bytes.NewReader(body)), nil
+}
+
+func (m *MockObjectStore) PutObject(ctx context.Context, key string, body []byte) error {
+	if m.Objects == nil {
+		m.Objects = make(map[string][]byte)
+	}
+	m.Objects[key] = body
+	return nil
+}
+
+// MockItemTable is a mock implementation for testing
+type MockItemTable struct {
+	Items map[string]map[string]string
+}
+
+func NewMockItemTable() *MockItemTable {
+	return &MockItemTable{Items: make(map[string]map[string]string)}
+}
+
+func (m *MockItemTable) GetItem(ctx context.Context, key string) (map[string]string, error) {
+	item, ok := m.Items[key]
+	if !ok {
+		return nil, nil
+	}
+	return maps.Clone(item), nil
+}
+
+func (m *MockItemTable) PutItemIfAbsent(ctx context.Context, item map[string]string) (bool, error) {
+	key := item[ItemKeyAttribute]
+	if _, ok := m.Items[key]; ok {
+		return false, nil
+	}
+	m.Items[key] = maps.Clone(item)
+	return true, nil
+}
+
+func (m *MockItemTable) PutItemIfUnchanged(ctx context.Context, item map[string]string, expected map[string]string) (bool, error) {
+	existing, ok := m.Items[item[ItemKeyAttribute]]
+	if !ok {
+		return false, nil
+	}
+	for name, value := range expected {
+		if current, ok := existing[name]; value == "" && ok || value != "" && current != value {
+			return false, nil
+		}
+	}
+	m.Items[item[ItemKeyAttribute]] = maps.Clone(item)
+	return true, nil
+}
+
+func (m *MockItemTable) DeleteItem(ctx context.Context, key string, expected map[string]string) error {
+	item, ok := m.Items[key]
+	if !ok {
+		return nil
+	}
+	for name, value := range expected {
+		if item[name] != value {
+			return nil
+		}
+	}
+	delete(m.Items, key)
+	return nil
+}
+
+func (m *MockItemTable) ScanItems(ctx context.Context) ([]map[string]string, error) {
+	keys := make([]string, 0, len(m.Items))
+	for key := range m.Items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	items := make([]map[string]string, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, maps.Clone(m.Items[key]))
+	}
+	return items, nil
+}
@@ -34,7 +34,7 @@ func TestMockACMClient_RequestCertificate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			arn, err := client.RequestCertificate(ctx, tt.domain, tt.tags)
+			arn, err := client.RequestCertificate(ctx, tt.domain, "", tt.tags)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("RequestCertificate() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -62,7 +62,7 @@ func TestMockACMClient_GetValidationRecords(t *testing.T) {
 	client := NewMockACMClient()
 	ctx := context.Background()
 
-	arn, _ := client.RequestCertificate(ctx, "test.example.com", nil)
+	arn, _ := client.RequestCertificate(ctx, "test.example.com", "", nil)
 
 	records, err := client.GetValidationRecords(ctx, arn)
 	if err != nil {
@@ -89,7 +89,7 @@ func TestMockACMClient_DeleteCertificate(t *testing.T) {
 	client := NewMockACMClient()
 	ctx := context.Background()
 
-	arn, _ := client.RequestCertificate(ctx, "test.example.com", nil)
+	arn, _ := client.RequestCertificate(ctx, "test.example.com", "", nil)
 
 	// Verify exists
 	_, err := client.DescribeCertificate(ctx, arn)
@@ -203,8 +203,11 @@ func TestMockRoute53Client_DeleteRecord(t *testing.T) {
 	}
 
 	// Verify deleted
-	_, err = client.GetRecord(ctx, "Z123", "test.example.com", "CNAME")
-	if err == nil {
+	got, err := client.GetRecord(ctx, "Z123", "test.example.com", "CNAME")
+	if err != nil {
+		t.Fatalf("GetRecord() error = %v", err)
+	}
+	if got != nil {
 		t.Error("record should not exist after delete")
 	}
 }
@@ -58,3 +58,94 @@ func ExtractRegionFromALBDNS(albDNS string) (string, error) {
 
 	return "", fmt.Errorf("could not extract region from ALB DNS: %s", albDNS)
 }
+
+// CloudFrontHostedZoneID is the hosted zone ID of every CloudFront distribution, including the
+// CloudFront names behind edge-optimized API Gateway custom domains
+const CloudFrontHostedZoneID = "Z2FDTNDATAQYW2"
+
+// APIGatewayHostedZoneIDs maps AWS regions to the hosted zone IDs of regional API Gateway
+// custom domain names (d-<id>.execute-api.<region>.amazonaws.com)
+var APIGatewayHostedZoneIDs = map[string]string{
+	"us-east-1":      "Z1UJRXOUMOOFQ8",
+	"us-east-2":      "ZOJJZC49E0EPZ",
+	"us-west-1":      "Z2MUQ32089INYE",
+	"us-west-2":      "Z2OJLYMUO9EFXC",
+	"ca-central-1":   "Z19DQILCV0OWEC",
+	"eu-central-1":   "Z1U9ULNL0V5AJ3",
+	"eu-west-1":      "ZLY8HYME6SFDD",
+	"eu-west-2":      "ZJ5UAJN8Y3Z2Q",
+	"eu-west-3":      "Z3KY65QIEKYHQQ",
+	"eu-north-1":     "Z3UWIKFBOOGXPP",
+	"eu-south-1":     "Z3BT4WSQ9TDYZV",
+	"ap-east-1":      "Z3FD1VL90ND7K5",
+	"ap-northeast-1": "Z1YSHQZHG15GKL",
+	"ap-northeast-2": "Z20JF4UZKIW1U8",
+	"ap-northeast-3": "Z2YQB5RD63NC85",
+	"ap-southeast-1": "ZL327KTPIQFUL",
+	"ap-southeast-2": "Z2RPCDW04V8134",
+	"ap-south-1":     "Z3VO1THU9YC4UR",
+	"sa-east-1":      "ZCMLWB8V5SYIT",
+	"me-south-1":     "Z20ZBPC0SS8806",
+	"af-south-1":     "Z2DHW2332DAMTN",
+}
+
+// S3WebsiteHostedZoneIDs maps AWS regions to the hosted zone IDs of S3 website endpoints
+// (<bucket>.s3-website-<region>.amazonaws.com or <bucket>.s3-website.<region>.amazonaws.com)
+var S3WebsiteHostedZoneIDs = map[string]string{
+	"us-east-1":      "Z3AQBSTGFYJSTF",
+	"us-east-2":      "Z2O1EMRO9K5GLX",
+	"us-west-1":      "Z2F56UZL2M1ACD",
+	"us-west-2":      "Z3BJ6K6RIION7M",
+	"ca-central-1":   "Z1QDHH18159H29",
+	"eu-central-1":   "Z21DNDUVLTQW6Q",
+	"eu-west-1":      "Z1BKCTXD74EZPE",
+	"eu-west-2":      "Z3GKZC51ZF0DB4",
+	"eu-west-3":      "Z3R1K369G5AVDG",
+	"eu-north-1":     "Z3BAZG2TWCNX0D",
+	"eu-south-1":     "Z30OZKI7KPW7MI",
+	"ap-east-1":      "ZNB98KWMFR0R6",
+	"ap-northeast-1": "Z2M4EHUR26P7ZW",
+	"ap-northeast-2": "Z3W03O7B5YMIYP",
+	"ap-northeast-3": "Z2YQB5RD63NC85",
+	"ap-southeast-1": "Z3O0J2DXBE1FTB",
+	"ap-southeast-2": "Z1WCIGYICN2BYD",
+	"ap-south-1":     "Z11RGJOFQNVJUP",
+	"sa-east-1":      "Z7KQH4QJS55SO",
+	"me-south-1":     "Z1MPMWCPA7YB62",
+	"af-south-1":     "Z83WF9RJE8B12",
+}
+
+// AliasTargetHostedZoneID derives the canonical hosted zone ID of an ALIAS target from its DNS
+// name, for CloudFront distributions, regional API Gateway custom domains and S3 website
+// endpoints. Load balancers are not covered: ALB and NLB names can't be told apart.
+func AliasTargetHostedZoneID(dnsName string) (string, error) {
+	name := strings.ToLower(strings.TrimSuffix(dnsName, "."))
+	if strings.HasSuffix(name, ".cloudfront.net") {
+		return CloudFrontHostedZoneID, nil
+	}
+	if !strings.HasSuffix(name, ".amazonaws.com") {
+		return "", fmt.Errorf("cannot derive hosted zone ID for alias target %s, set hostedZoneId", dnsName)
+	}
+
+	// parts: [..., <service>, <region>, amazonaws, com] or [..., s3-website-<region>, amazonaws, com]
+	parts := strings.Split(name, ".")
+	n := len(parts)
+	var service, region string
+	var zoneIDs map[string]string
+	switch {
+	case n >= 5 && parts[n-4] == "execute-api":
+		service, region, zoneIDs = "API Gateway", parts[n-3], APIGatewayHostedZoneIDs
+	case n >= 5 && parts[n-4] == "s3-website":
+		service, region, zoneIDs = "S3 website", parts[n-3], S3WebsiteHostedZoneIDs
+	case n >= 4 && strings.HasPrefix(parts[n-3], "s3-website-"):
+		service, region, zoneIDs = "S3 website", strings.TrimPrefix(parts[n-3], "s3-website-"), S3WebsiteHostedZoneIDs
+	default:
+		return "", fmt.Errorf("cannot derive hosted zone ID for alias target %s, set hostedZoneId", dnsName)
+	}
+
+	zoneID, ok := zoneIDs[region]
+	if !ok {
+		return "", fmt.Errorf("unknown region: %s (%s hosted zone ID not found)", region, service)
+	}
+	return zoneID, nil
+}
@@ -188,3 +188,34 @@ func TestExtractAndGetHostedZone(t *testing.T) {
 		})
 	}
 }
+
+func TestAliasTargetHostedZoneID(t *testing.T) {
+	tests := []struct {
+		name      string
+		dnsName   string
+		want      string
+		wantError bool
+	}{
+		{name: "CloudFront", dnsName: "d111111abcdef8.cloudfront.net", want: "Z2FDTNDATAQYW2"},
+		{name: "CloudFront with trailing dot", dnsName: "d111111abcdef8.cloudfront.net.", want: "Z2FDTNDATAQYW2"},
+		{name: "regional API Gateway", dnsName: "d-abc123xyz.execute-api.eu-west-1.amazonaws.com", want: "ZLY8HYME6SFDD"},
+		{name: "S3 website with dash", dnsName: "static.example.com.s3-website-us-east-1.amazonaws.com", want: "Z3AQBSTGFYJSTF"},
+		{name: "S3 website with dot", dnsName: "static.example.com.s3-website.eu-central-1.amazonaws.com", want: "Z21DNDUVLTQW6Q"},
+		{name: "unknown API Gateway region", dnsName: "d-abc123xyz.execute-api.mars-1.amazonaws.com", wantError: true},
+		{name: "load balancer", dnsName: "my-alb-123.eu-central-1.elb.amazonaws.com", wantError: true},
+		{name: "other target", dnsName: "www.example.com", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AliasTargetHostedZoneID(tt.dnsName)
+			if (err != nil) != tt.wantError {
+				t.Errorf("AliasTargetHostedZoneID() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AliasTargetHostedZoneID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
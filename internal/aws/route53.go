@@ -2,6 +2,7 @@ package aws
 
 import (
 	"context"
+	"strings"
 )
 
 // Route53Client defines the interface for Route53 operations
@@ -12,8 +13,122 @@ type Route53Client interface {
 	// DeleteRecord deletes a DNS record from Route53
 	DeleteRecord(ctx context.Context, zoneId string, record DNSRecord) error
 
-	// GetRecord retrieves a DNS record from Route53
+	// DeleteRecords deletes records from a zone in one change batch, so either all or none of
+	// them are deleted, and returns once the change is in sync on the Route53 name servers.
+	// The records must match the existing ones exactly, as returned by GetRecord.
+	DeleteRecords(ctx context.Context, zoneId string, records []DNSRecord) error
+
+	// GetRecord retrieves a DNS record from Route53, or nil if there is none. It returns the
+	// weighted record set of the context's WithSetIdentifier, and otherwise only simple ones.
 	GetRecord(ctx context.Context, zoneId string, name, recordType string) (*DNSRecord, error)
+
+	// GetDNSSEC retrieves the DNSSEC signing status of a hosted zone
+	GetDNSSEC(ctx context.Context, zoneId string) (*DNSSECStatus, error)
+
+	// GetHostedZoneByName returns the public hosted zone with the given name, or nil if there is none
+	GetHostedZoneByName(ctx context.Context, name string) (*HostedZone, error)
+
+	// CreateHostedZone creates a public hosted zone. Retries with the same callerReference
+	// return an error instead of creating a second zone.
+	CreateHostedZone(ctx context.Context, name, callerReference string) (*HostedZone, error)
+
+	// GetHostedZone returns the hosted zone with the given ID, with its VPCs if it is private
+	GetHostedZone(ctx context.Context, zoneId string) (*HostedZone, error)
+
+	// AssociateVPC associates a private hosted zone with a VPC, so the VPC resolves its records
+	AssociateVPC(ctx context.Context, zoneId string, vpc VPC) error
+
+	// DisassociateVPC removes the association of a private hosted zone with a VPC. A VPC
+	// that isn't associated is not an error.
+	DisassociateVPC(ctx context.Context, zoneId string, vpc VPC) error
+}
+
+// changeCommentKey is the context key of WithChangeComment
+type changeCommentKey struct{}
+
+// WithChangeComment returns a context whose Route53 record changes describe the origin in
+// the change batch comment, e.g. "GatewayHostnameRequest default/app: UPSERT app.example.com A",
+// so the zone's change history in CloudTrail names who made each change
+func WithChangeComment(ctx context.Context, origin string) context.Context {
+	return context.WithValue(ctx, changeCommentKey{}, origin)
+}
+
+// setIdentifierKey is the context key of WithSetIdentifier
+type setIdentifierKey struct{}
+
+// WithSetIdentifier returns a context in which GetRecord looks up the weighted record set with
+// the identifier instead of the simple one. An empty identifier keeps simple record sets.
+func WithSetIdentifier(ctx context.Context, setIdentifier string) context.Context {
+	if setIdentifier == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, setIdentifierKey{}, setIdentifier)
+}
+
+// setIdentifierFromContext returns the identifier set with WithSetIdentifier
+func setIdentifierFromContext(ctx context.Context) string {
+	setIdentifier, _ := ctx.Value(setIdentifierKey{}).(string)
+	return setIdentifier
+}
+
+// maxChangeCommentLength is the Route53 limit for change batch comments
+const maxChangeCommentLength = 256
+
+// changeComment describes a change batch as "<origin>: <action> <name> <type>, ...", with
+// the origin set with WithChangeComment, cut to the Route53 limit
+func changeComment(ctx context.Context, action string, records []DNSRecord) string {
+	names := make([]string, 0, len(records))
+	for _, record := range records {
+		names = append(names, record.Name+" "+record.Type)
+	}
+	comment := action + " " + strings.Join(names, ", ")
+	if origin, _ := ctx.Value(changeCommentKey{}).(string); origin != "" {
+		comment = origin + ": " + comment
+	}
+	if len(comment) > maxChangeCommentLength {
+		comment = comment[:maxChangeCommentLength-3] + "..."
+	}
+	return comment
+}
+
+// HostedZone is a Route53 hosted zone
+type HostedZone struct {
+	ID   string
+	Name string
+	// NameServers are the zone's delegation set, to publish as NS records in the parent zone
+	NameServers []string
+	// Private zones only answer queries from their VPCs
+	Private bool
+	// VPCs are the VPCs a private zone is associated with, as returned by GetHostedZone
+	VPCs []VPC
+}
+
+// VPC identifies a VPC a private hosted zone can be associated with
+type VPC struct {
+	ID     string
+	Region string
+}
+
+// String returns the VPC as <region>/<id>
+func (v VPC) String() string {
+	return v.Region + "/" + v.ID
+}
+
+// DNSSECStatus represents the DNSSEC signing state of a Route53 hosted zone
+type DNSSECStatus struct {
+	// ServeSignature is SIGNING, NOT_SIGNING, DELETING, ACTION_NEEDED or INTERNAL_FAILURE
+	ServeSignature string
+	StatusMessage  string
+
+	KeySigningKeys []KeySigningKey
+}
+
+// KeySigningKey represents a DNSSEC key-signing key of a hosted zone
+type KeySigningKey struct {
+	Name string
+	// Status is ACTIVE, INACTIVE, DELETING, ACTION_NEEDED or INTERNAL_FAILURE
+	Status        string
+	StatusMessage string
 }
 
 // DNSRecord represents a Route53 DNS record
@@ -27,6 +142,17 @@ type DNSRecord struct {
 	// For CNAME records (ACM validation)
 	Value string
 	TTL   int64
+
+	// Values holds every value of the record set, for types with several values (e.g. CAA, NS).
+	// GetRecord sets it with Value as the first of them; CreateOrUpdateRecord writes all of
+	// them if set, and Value otherwise.
+	Values []string
+
+	// SetIdentifier and Weight make the record one of several weighted record sets with the
+	// same name and type, e.g. one per cluster serving the hostname. Route53 answers with
+	// each set in proportion to its weight.
+	SetIdentifier string
+	Weight        *int64
 }
 
 // AliasTarget represents Route53 ALIAS record target
@@ -0,0 +1,185 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// maxWriteWait bounds how long a write waits for its zone's budget, also when the context has
+// no deadline, so a reconcile is requeued instead of blocking its worker
+const maxWriteWait = 30 * time.Second
+
+// ErrWriteBudgetExhausted is returned by writes whose zone's budget doesn't allow them within
+// maxWriteWait or the context deadline. IsTransient reports it, so the write is retried.
+var ErrWriteBudgetExhausted = errors.New("route53 write budget exhausted")
+
+// ZoneBudget limits the rate of Route53 writes (ChangeResourceRecordSets) to a hosted zone
+type ZoneBudget struct {
+	// Rate is the sustained number of writes per second
+	Rate float64
+	// Burst is the number of writes allowed at once before Rate applies
+	Burst int
+}
+
+// RateLimitedRoute53Client smooths Route53 writes per hosted zone so the orchestrator doesn't
+// starve other automation sharing a busy zone. Reads are passed through unchanged.
+// Writes wait for their zone's budget; if the context deadline or maxWriteWait would pass
+// first, the write fails with ErrWriteBudgetExhausted and is retried on the next reconcile.
+type RateLimitedRoute53Client struct {
+	inner         Route53Client
+	defaultBudget ZoneBudget
+	budgets       map[string]ZoneBudget
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// NewRateLimitedRoute53Client wraps inner with per-zone write budgets.
+// Zones without an entry in budgets use defaultBudget; a zero Rate means unlimited.
+func NewRateLimitedRoute53Client(inner Route53Client, defaultBudget ZoneBudget, budgets map[string]ZoneBudget) *RateLimitedRoute53Client {
+	return &RateLimitedRoute53Client{
+		inner:         inner,
+		defaultBudget: defaultBudget,
+		budgets:       budgets,
+		limiters:      make(map[string]*rate.Limiter),
+	}
+}
+
+func (c *RateLimitedRoute53Client) CreateOrUpdateRecord(ctx context.Context, zoneId string, record DNSRecord) error {
+	if err := c.wait(ctx, zoneId); err != nil {
+		return err
+	}
+	return c.inner.CreateOrUpdateRecord(ctx, zoneId, record)
+}
+
+func (c *RateLimitedRoute53Client) DeleteRecord(ctx context.Context, zoneId string, record DNSRecord) error {
+	if err := c.wait(ctx, zoneId); err != nil {
+		return err
+	}
+	return c.inner.DeleteRecord(ctx, zoneId, record)
+}
+
+func (c *RateLimitedRoute53Client) DeleteRecords(ctx context.Context, zoneId string, records []DNSRecord) error {
+	// One change batch is one write
+	if err := c.wait(ctx, zoneId); err != nil {
+		return err
+	}
+	return c.inner.DeleteRecords(ctx, zoneId, records)
+}
+
+func (c *RateLimitedRoute53Client) GetRecord(ctx context.Context, zoneId string, name, recordType string) (*DNSRecord, error) {
+	return c.inner.GetRecord(ctx, zoneId, name, recordType)
+}
+
+func (c *RateLimitedRoute53Client) GetDNSSEC(ctx context.Context, zoneId string) (*DNSSECStatus, error) {
+	return c.inner.GetDNSSEC(ctx, zoneId)
+}
+
+func (c *RateLimitedRoute53Client) GetHostedZoneByName(ctx context.Context, name string) (*HostedZone, error) {
+	return c.inner.GetHostedZoneByName(ctx, name)
+}
+
+func (c *RateLimitedRoute53Client) CreateHostedZone(ctx context.Context, name, callerReference string) (*HostedZone, error) {
+	return c.inner.CreateHostedZone(ctx, name, callerReference)
+}
+
+func (c *RateLimitedRoute53Client) GetHostedZone(ctx context.Context, zoneId string) (*HostedZone, error) {
+	return c.inner.GetHostedZone(ctx, zoneId)
+}
+
+func (c *RateLimitedRoute53Client) AssociateVPC(ctx context.Context, zoneId string, vpc VPC) error {
+	return c.inner.AssociateVPC(ctx, zoneId, vpc)
+}
+
+func (c *RateLimitedRoute53Client) DisassociateVPC(ctx context.Context, zoneId string, vpc VPC) error {
+	return c.inner.DisassociateVPC(ctx, zoneId, vpc)
+}
+
+// wait blocks until the zone's budget allows another write, for at most maxWriteWait. A write
+// that would have to wait longer fails right away without using up the budget.
+func (c *RateLimitedRoute53Client) wait(ctx context.Context, zoneId string) error {
+	limiter := c.limiter(zoneId)
+	if limiter == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, maxWriteWait)
+	defer cancel()
+	if err := limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("%w for zone %s: %w", ErrWriteBudgetExhausted, zoneId, err)
+	}
+	return nil
+}
+
+// limiter returns the zone's limiter, creating it on first use; nil means unlimited
+func (c *RateLimitedRoute53Client) limiter(zoneId string) *rate.Limiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if l, ok := c.limiters[zoneId]; ok {
+		return l
+	}
+
+	budget, ok := c.budgets[zoneId]
+	if !ok {
+		budget = c.defaultBudget
+	}
+	var l *rate.Limiter
+	if budget.Rate > 0 {
+		burst := budget.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		l = rate.NewLimiter(rate.Limit(budget.Rate), burst)
+	}
+	c.limiters[zoneId] = l
+	return l
+}
+
+// ParseZoneBudget parses a budget in the form "<rate>" or "<rate>:<burst>", e.g. "0.5:3"
+func ParseZoneBudget(s string) (ZoneBudget, error) {
+	rateStr, burstStr, hasBurst := strings.Cut(strings.TrimSpace(s), ":")
+
+	r, err := strconv.ParseFloat(rateStr, 64)
+	if err != nil || r < 0 {
+		return ZoneBudget{}, fmt.Errorf("invalid rate %q in budget %q", rateStr, s)
+	}
+
+	budget := ZoneBudget{Rate: r, Burst: 1}
+	if hasBurst {
+		b, err := strconv.Atoi(burstStr)
+		if err != nil || b < 1 {
+			return ZoneBudget{}, fmt.Errorf("invalid burst %q in budget %q", burstStr, s)
+		}
+		budget.Burst = b
+	}
+	return budget, nil
+}
+
+// ParseZoneBudgets parses a comma-separated list of "<zoneId>=<rate>[:<burst>]" entries,
+// e.g. "Z123=1:5,Z456=0.2"
+func ParseZoneBudgets(s string) (map[string]ZoneBudget, error) {
+	budgets := make(map[string]ZoneBudget)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		zoneId, spec, ok := strings.Cut(entry, "=")
+		if !ok || zoneId == "" {
+			return nil, fmt.Errorf("invalid zone budget %q, expected <zoneId>=<rate>[:<burst>]", entry)
+		}
+		budget, err := ParseZoneBudget(spec)
+		if err != nil {
+			return nil, err
+		}
+		budgets[strings.TrimSpace(zoneId)] = budget
+	}
+	return budgets, nil
+}
@@ -0,0 +1,116 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseZoneBudgets(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      map[string]ZoneBudget
+		wantError bool
+	}{
+		{
+			name:  "empty",
+			input: "",
+			want:  map[string]ZoneBudget{},
+		},
+		{
+			name:  "rate and burst",
+			input: "Z123=1:5, Z456=0.2",
+			want: map[string]ZoneBudget{
+				"Z123": {Rate: 1, Burst: 5},
+				"Z456": {Rate: 0.2, Burst: 1},
+			},
+		},
+		{
+			name:      "missing zone",
+			input:     "=1",
+			wantError: true,
+		},
+		{
+			name:      "invalid rate",
+			input:     "Z123=fast",
+			wantError: true,
+		},
+		{
+			name:      "invalid burst",
+			input:     "Z123=1:0",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseZoneBudgets(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseZoneBudgets() error = %v, wantError %v", err, tt.wantError)
+			}
+			if tt.wantError {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseZoneBudgets() = %v, want %v", got, tt.want)
+			}
+			for zone, budget := range tt.want {
+				if got[zone] != budget {
+					t.Errorf("budget for %s = %v, want %v", zone, got[zone], budget)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimitedRoute53Client_LimitsWritesPerZone(t *testing.T) {
+	inner := NewMockRoute53Client()
+	client := NewRateLimitedRoute53Client(inner, ZoneBudget{}, map[string]ZoneBudget{
+		// One write, then one every 100 seconds
+		"ZBUSY": {Rate: 0.01, Burst: 1},
+	})
+
+	record := DNSRecord{Name: "app.example.com", Type: "CNAME", Value: "target.example.com", TTL: 300}
+
+	if err := client.CreateOrUpdateRecord(context.Background(), "ZBUSY", record); err != nil {
+		t.Fatalf("first write should use the burst: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := client.DeleteRecord(ctx, "ZBUSY", record); err == nil {
+		t.Error("second write should fail because the budget can't refill before the deadline")
+	}
+
+	// Zones without a budget and reads are not limited
+	for i := 0; i < 5; i++ {
+		if err := client.CreateOrUpdateRecord(ctx, "ZOTHER", record); err != nil {
+			t.Fatalf("unlimited zone write failed: %v", err)
+		}
+	}
+	if _, err := client.GetRecord(ctx, "ZBUSY", record.Name, record.Type); err != nil {
+		t.Errorf("reads should not be limited: %v", err)
+	}
+}
+
+func TestRateLimitedRoute53Client_BoundsWait(t *testing.T) {
+	client := NewRateLimitedRoute53Client(NewMockRoute53Client(), ZoneBudget{}, map[string]ZoneBudget{
+		"ZBUSY": {Rate: 0.01, Burst: 1},
+	})
+	record := DNSRecord{Name: "app.example.com", Type: "CNAME", Value: "target.example.com", TTL: 300}
+	if err := client.CreateOrUpdateRecord(context.Background(), "ZBUSY", record); err != nil {
+		t.Fatalf("first write should use the burst: %v", err)
+	}
+
+	// Without a deadline the write still fails right away instead of waiting 100 seconds
+	start := time.Now()
+	err := client.CreateOrUpdateRecord(context.Background(), "ZBUSY", record)
+	if !errors.Is(err, ErrWriteBudgetExhausted) || !IsTransient(err) {
+		t.Errorf("second write error = %v, want transient ErrWriteBudgetExhausted", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("second write waited %v", elapsed)
+	}
+}
@@ -2,12 +2,15 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/route53"
 	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/aws/smithy-go/middleware"
 )
 
 // SDKRoute53Client implements Route53Client using AWS SDK v2
@@ -33,6 +36,10 @@ func (c *SDKRoute53Client) CreateOrUpdateRecord(ctx context.Context, zoneId stri
 			HostedZoneId:         aws.String(record.AliasTarget.HostedZoneID),
 			EvaluateTargetHealth: record.AliasTarget.EvaluateTargetHealth,
 		}
+	} else if len(record.Values) > 0 {
+		for _, value := range record.Values {
+			resourceRecords = append(resourceRecords, types.ResourceRecord{Value: aws.String(value)})
+		}
 	} else {
 		resourceRecords = []types.ResourceRecord{
 			{Value: aws.String(record.Value)},
@@ -40,6 +47,7 @@ func (c *SDKRoute53Client) CreateOrUpdateRecord(ctx context.Context, zoneId stri
 	}
 
 	changeBatch := &types.ChangeBatch{
+		Comment: aws.String(changeComment(ctx, string(types.ChangeActionUpsert), []DNSRecord{record})),
 		Changes: []types.Change{
 			{
 				Action: types.ChangeActionUpsert,
@@ -49,6 +57,8 @@ func (c *SDKRoute53Client) CreateOrUpdateRecord(ctx context.Context, zoneId stri
 					TTL:             aws.Int64(record.TTL),
 					ResourceRecords: resourceRecords,
 					AliasTarget:     aliasTarget,
+					SetIdentifier:   setIdentifier(record),
+					Weight:          record.Weight,
 				},
 			},
 		},
@@ -65,7 +75,8 @@ func (c *SDKRoute53Client) CreateOrUpdateRecord(ctx context.Context, zoneId stri
 		ChangeBatch:  changeBatch,
 	}
 
-	_, err := c.client.ChangeResourceRecordSets(ctx, input)
+	result, err := c.client.ChangeResourceRecordSets(ctx, input)
+	recordChange(ctx, zoneId, []DNSRecord{record}, result, err)
 	if err != nil {
 		return fmt.Errorf("failed to create/update record: %w", err)
 	}
@@ -74,57 +85,121 @@ func (c *SDKRoute53Client) CreateOrUpdateRecord(ctx context.Context, zoneId stri
 }
 
 func (c *SDKRoute53Client) DeleteRecord(ctx context.Context, zoneId string, record DNSRecord) error {
-	var resourceRecords []types.ResourceRecord
-	var aliasTarget *types.AliasTarget
+	input := &route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(normalizeZoneId(zoneId)),
+		ChangeBatch: &types.ChangeBatch{
+			Comment: aws.String(changeComment(ctx, string(types.ChangeActionDelete), []DNSRecord{record})),
+			Changes: []types.Change{deleteChange(record)},
+		},
+	}
 
-	if record.AliasTarget != nil {
-		aliasTarget = &types.AliasTarget{
-			DNSName:              aws.String(record.AliasTarget.DNSName),
-			HostedZoneId:         aws.String(record.AliasTarget.HostedZoneID),
-			EvaluateTargetHealth: record.AliasTarget.EvaluateTargetHealth,
-		}
-	} else {
-		resourceRecords = []types.ResourceRecord{
-			{Value: aws.String(record.Value)},
+	result, err := c.client.ChangeResourceRecordSets(ctx, input)
+	recordChange(ctx, zoneId, []DNSRecord{record}, result, err)
+	if err != nil {
+		// Treat "record not found" as success (idempotent deletion)
+		if strings.Contains(err.Error(), "it was not found") {
+			return nil
 		}
+		return fmt.Errorf("failed to delete record: %w", err)
 	}
 
-	changeBatch := &types.ChangeBatch{
-		Changes: []types.Change{
-			{
-				Action: types.ChangeActionDelete,
-				ResourceRecordSet: &types.ResourceRecordSet{
-					Name:            aws.String(record.Name),
-					Type:            types.RRType(record.Type),
-					TTL:             aws.Int64(record.TTL),
-					ResourceRecords: resourceRecords,
-					AliasTarget:     aliasTarget,
-				},
-			},
+	return nil
+}
+
+func (c *SDKRoute53Client) DeleteRecords(ctx context.Context, zoneId string, records []DNSRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	changes := make([]types.Change, 0, len(records))
+	for _, record := range records {
+		changes = append(changes, deleteChange(record))
+	}
+
+	result, err := c.client.ChangeResourceRecordSets(ctx, &route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(normalizeZoneId(zoneId)),
+		ChangeBatch: &types.ChangeBatch{
+			Comment: aws.String(changeComment(ctx, string(types.ChangeActionDelete), records)),
+			Changes: changes,
 		},
+	})
+	recordChange(ctx, zoneId, records, result, err)
+	if err != nil {
+		return fmt.Errorf("failed to delete records: %w", err)
 	}
 
-	// For ALIAS records, TTL should not be set
-	if aliasTarget != nil {
-		changeBatch.Changes[0].ResourceRecordSet.TTL = nil
-		changeBatch.Changes[0].ResourceRecordSet.ResourceRecords = nil
+	// Wait for the name servers as long as the caller allows
+	maxWait := changeSyncMaxWait
+	if deadline, ok := ctx.Deadline(); ok {
+		maxWait = time.Until(deadline)
 	}
+	waiter := route53.NewResourceRecordSetsChangedWaiter(c.client, func(o *route53.ResourceRecordSetsChangedWaiterOptions) {
+		o.MinDelay = 2 * time.Second
+		o.MaxDelay = 10 * time.Second
+	})
+	if err := waiter.Wait(ctx, &route53.GetChangeInput{Id: result.ChangeInfo.Id}, maxWait); err != nil {
+		return fmt.Errorf("record deletion %s not in sync: %w", aws.ToString(result.ChangeInfo.Id), err)
+	}
+	return nil
+}
 
-	input := &route53.ChangeResourceRecordSetsInput{
-		HostedZoneId: aws.String(normalizeZoneId(zoneId)),
-		ChangeBatch:  changeBatch,
+// recordChange records a ChangeResourceRecordSets call in the context's CallLog, with the
+// records as "<zone>: <name> <type>, ..."
+func recordChange(ctx context.Context, zoneId string, records []DNSRecord, result *route53.ChangeResourceRecordSetsOutput, err error) {
+	names := make([]string, 0, len(records))
+	for _, record := range records {
+		names = append(names, record.Name+" "+record.Type)
+	}
+	call := Call{
+		Operation: "route53:ChangeResourceRecordSets",
+		Resource:  normalizeZoneId(zoneId) + ": " + strings.Join(names, ", "),
+		Err:       err,
+	}
+	var metadata middleware.Metadata
+	if result != nil {
+		metadata = result.ResultMetadata
+		if result.ChangeInfo != nil {
+			call.ChangeID = aws.ToString(result.ChangeInfo.Id)
+		}
 	}
+	recordCall(ctx, call, metadata)
+}
 
-	_, err := c.client.ChangeResourceRecordSets(ctx, input)
-	if err != nil {
-		// Treat "record not found" as success (idempotent deletion)
-		if strings.Contains(err.Error(), "it was not found") {
-			return nil
+// changeSyncMaxWait bounds waiting for a change to be in sync when the context has no deadline
+const changeSyncMaxWait = 2 * time.Minute
+
+// deleteChange builds the change deleting a record. Alias records carry no TTL or values.
+func deleteChange(record DNSRecord) types.Change {
+	set := &types.ResourceRecordSet{
+		Name:          aws.String(record.Name),
+		Type:          types.RRType(record.Type),
+		SetIdentifier: setIdentifier(record),
+		Weight:        record.Weight,
+	}
+	if record.AliasTarget != nil {
+		set.AliasTarget = &types.AliasTarget{
+			DNSName:              aws.String(record.AliasTarget.DNSName),
+			HostedZoneId:         aws.String(record.AliasTarget.HostedZoneID),
+			EvaluateTargetHealth: record.AliasTarget.EvaluateTargetHealth,
+		}
+	} else {
+		set.TTL = aws.Int64(record.TTL)
+		values := record.Values
+		if len(values) == 0 {
+			values = []string{record.Value}
+		}
+		for _, value := range values {
+			set.ResourceRecords = append(set.ResourceRecords, types.ResourceRecord{Value: aws.String(value)})
 		}
-		return fmt.Errorf("failed to delete record: %w", err)
 	}
+	return types.Change{Action: types.ChangeActionDelete, ResourceRecordSet: set}
+}
 
-	return nil
+// setIdentifier returns the record's set identifier for the SDK, nil for simple records
+func setIdentifier(record DNSRecord) *string {
+	if record.SetIdentifier == "" {
+		return nil
+	}
+	return aws.String(record.SetIdentifier)
 }
 
 func (c *SDKRoute53Client) GetRecord(ctx context.Context, zoneId, name, recordType string) (*DNSRecord, error) {
@@ -134,6 +209,10 @@ func (c *SDKRoute53Client) GetRecord(ctx context.Context, zoneId, name, recordTy
 		StartRecordType: types.RRType(recordType),
 		MaxItems:        aws.Int32(1),
 	}
+	identifier := setIdentifierFromContext(ctx)
+	if identifier != "" {
+		input.StartRecordIdentifier = aws.String(identifier)
+	}
 
 	result, err := c.client.ListResourceRecordSets(ctx, input)
 	if err != nil {
@@ -143,13 +222,15 @@ func (c *SDKRoute53Client) GetRecord(ctx context.Context, zoneId, name, recordTy
 	for _, rrs := range result.ResourceRecordSets {
 		// Check if name matches (Route53 returns names with trailing dot)
 		recordName := aws.ToString(rrs.Name)
-		if strings.TrimSuffix(recordName, ".") == strings.TrimSuffix(name, ".") &&
-			string(rrs.Type) == recordType {
+		if canonicalRecordName(recordName) == canonicalRecordName(name) &&
+			string(rrs.Type) == recordType && aws.ToString(rrs.SetIdentifier) == identifier {
 
 			record := &DNSRecord{
-				Name: recordName,
-				Type: string(rrs.Type),
-				TTL:  aws.ToInt64(rrs.TTL),
+				Name:          recordName,
+				Type:          string(rrs.Type),
+				TTL:           aws.ToInt64(rrs.TTL),
+				SetIdentifier: aws.ToString(rrs.SetIdentifier),
+				Weight:        rrs.Weight,
 			}
 
 			if rrs.AliasTarget != nil {
@@ -160,6 +241,9 @@ func (c *SDKRoute53Client) GetRecord(ctx context.Context, zoneId, name, recordTy
 				}
 			} else if len(rrs.ResourceRecords) > 0 {
 				record.Value = aws.ToString(rrs.ResourceRecords[0].Value)
+				for _, rr := range rrs.ResourceRecords {
+					record.Values = append(record.Values, aws.ToString(rr.Value))
+				}
 			}
 
 			return record, nil
@@ -169,9 +253,155 @@ func (c *SDKRoute53Client) GetRecord(ctx context.Context, zoneId, name, recordTy
 	return nil, nil // Not found
 }
 
+func (c *SDKRoute53Client) GetDNSSEC(ctx context.Context, zoneId string) (*DNSSECStatus, error) {
+	result, err := c.client.GetDNSSEC(ctx, &route53.GetDNSSECInput{
+		HostedZoneId: aws.String(normalizeZoneId(zoneId)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get DNSSEC status: %w", err)
+	}
+
+	status := &DNSSECStatus{}
+	if result.Status != nil {
+		status.ServeSignature = aws.ToString(result.Status.ServeSignature)
+		status.StatusMessage = aws.ToString(result.Status.StatusMessage)
+	}
+	for _, ksk := range result.KeySigningKeys {
+		status.KeySigningKeys = append(status.KeySigningKeys, KeySigningKey{
+			Name:          aws.ToString(ksk.Name),
+			Status:        aws.ToString(ksk.Status),
+			StatusMessage: aws.ToString(ksk.StatusMessage),
+		})
+	}
+
+	return status, nil
+}
+
+func (c *SDKRoute53Client) GetHostedZoneByName(ctx context.Context, name string) (*HostedZone, error) {
+	fqdn := strings.TrimSuffix(name, ".") + "."
+	result, err := c.client.ListHostedZonesByName(ctx, &route53.ListHostedZonesByNameInput{
+		DNSName:  aws.String(fqdn),
+		MaxItems: aws.Int32(10),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hosted zones: %w", err)
+	}
+
+	// Zones are sorted by name, so the matching ones come first
+	for _, zone := range result.HostedZones {
+		if aws.ToString(zone.Name) != fqdn {
+			break
+		}
+		if zone.Config != nil && zone.Config.PrivateZone {
+			continue
+		}
+		details, err := c.client.GetHostedZone(ctx, &route53.GetHostedZoneInput{Id: zone.Id})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get hosted zone %s: %w", aws.ToString(zone.Id), err)
+		}
+		return hostedZone(details.HostedZone, details.DelegationSet), nil
+	}
+	return nil, nil
+}
+
+func (c *SDKRoute53Client) CreateHostedZone(ctx context.Context, name, callerReference string) (*HostedZone, error) {
+	result, err := c.client.CreateHostedZone(ctx, &route53.CreateHostedZoneInput{
+		Name:            aws.String(name),
+		CallerReference: aws.String(callerReference),
+	})
+	call := Call{Operation: "route53:CreateHostedZone", Resource: name, Err: err}
+	var metadata middleware.Metadata
+	if err == nil {
+		metadata = result.ResultMetadata
+		call.ChangeID = aws.ToString(result.ChangeInfo.Id)
+	}
+	recordCall(ctx, call, metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create hosted zone: %w", err)
+	}
+	return hostedZone(result.HostedZone, result.DelegationSet), nil
+}
+
+func (c *SDKRoute53Client) GetHostedZone(ctx context.Context, zoneId string) (*HostedZone, error) {
+	result, err := c.client.GetHostedZone(ctx, &route53.GetHostedZoneInput{Id: aws.String(normalizeZoneId(zoneId))})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hosted zone %s: %w", zoneId, err)
+	}
+	zone := hostedZone(result.HostedZone, result.DelegationSet)
+	for _, vpc := range result.VPCs {
+		zone.VPCs = append(zone.VPCs, VPC{ID: aws.ToString(vpc.VPCId), Region: string(vpc.VPCRegion)})
+	}
+	return zone, nil
+}
+
+func (c *SDKRoute53Client) AssociateVPC(ctx context.Context, zoneId string, vpc VPC) error {
+	result, err := c.client.AssociateVPCWithHostedZone(ctx, &route53.AssociateVPCWithHostedZoneInput{
+		HostedZoneId: aws.String(normalizeZoneId(zoneId)),
+		VPC:          &types.VPC{VPCId: aws.String(vpc.ID), VPCRegion: types.VPCRegion(vpc.Region)},
+	})
+	call := Call{Operation: "route53:AssociateVPCWithHostedZone", Resource: normalizeZoneId(zoneId) + ": " + vpc.String(), Err: err}
+	var metadata middleware.Metadata
+	if err == nil {
+		metadata = result.ResultMetadata
+		call.ChangeID = aws.ToString(result.ChangeInfo.Id)
+	}
+	recordCall(ctx, call, metadata)
+	if err != nil {
+		return fmt.Errorf("failed to associate VPC %s with hosted zone %s: %w", vpc, zoneId, err)
+	}
+	return nil
+}
+
+func (c *SDKRoute53Client) DisassociateVPC(ctx context.Context, zoneId string, vpc VPC) error {
+	result, err := c.client.DisassociateVPCFromHostedZone(ctx, &route53.DisassociateVPCFromHostedZoneInput{
+		HostedZoneId: aws.String(normalizeZoneId(zoneId)),
+		VPC:          &types.VPC{VPCId: aws.String(vpc.ID), VPCRegion: types.VPCRegion(vpc.Region)},
+	})
+	call := Call{Operation: "route53:DisassociateVPCFromHostedZone", Resource: normalizeZoneId(zoneId) + ": " + vpc.String(), Err: err}
+	var metadata middleware.Metadata
+	if err == nil {
+		metadata = result.ResultMetadata
+		call.ChangeID = aws.ToString(result.ChangeInfo.Id)
+	}
+	recordCall(ctx, call, metadata)
+	if err != nil {
+		var notAssociated *types.VPCAssociationNotFound
+		if errors.As(err, &notAssociated) {
+			return nil
+		}
+		return fmt.Errorf("failed to disassociate VPC %s from hosted zone %s: %w", vpc, zoneId, err)
+	}
+	return nil
+}
+
+// hostedZone converts an SDK hosted zone and its delegation set
+func hostedZone(zone *types.HostedZone, delegationSet *types.DelegationSet) *HostedZone {
+	hz := &HostedZone{
+		ID:   normalizeZoneId(aws.ToString(zone.Id)),
+		Name: strings.TrimSuffix(aws.ToString(zone.Name), "."),
+	}
+	if delegationSet != nil {
+		hz.NameServers = delegationSet.NameServers
+	}
+	if zone.Config != nil {
+		hz.Private = zone.Config.PrivateZone
+	}
+	return hz
+}
+
 // normalizeZoneId ensures the zone ID has the correct format
 func normalizeZoneId(zoneId string) string {
 	// Remove /hostedzone/ prefix if present
 	zoneId = strings.TrimPrefix(zoneId, "/hostedzone/")
 	return zoneId
 }
+
+// canonicalRecordName lowercases a record name and drops the trailing dot. Route53 returns
+// names lowercase with a trailing dot and a leading wildcard escaped as \052.
+func canonicalRecordName(name string) string {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if strings.HasPrefix(name, `\052.`) {
+		name = "*" + strings.TrimPrefix(name, `\052`)
+	}
+	return name
+}
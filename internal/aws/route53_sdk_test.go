@@ -0,0 +1,66 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalRecordName(t *testing.T) {
+	assert.Equal(t, "app.example.com", canonicalRecordName("App.Example.com."))
+	assert.Equal(t, "*.preview.example.com", canonicalRecordName(`\052.preview.example.com.`))
+	assert.Equal(t, canonicalRecordName("*.preview.example.com"), canonicalRecordName(`\052.preview.example.com.`))
+}
+
+func TestRecordChange(t *testing.T) {
+	records := []DNSRecord{{Name: "app.example.com", Type: "A"}, {Name: "app.example.com", Type: "AAAA"}}
+	var metadata middleware.Metadata
+	awsmiddleware.SetRequestIDMetadata(&metadata, "req-1")
+	result := &route53.ChangeResourceRecordSetsOutput{
+		ChangeInfo:     &types.ChangeInfo{Id: aws.String("/change/C1")},
+		ResultMetadata: metadata,
+	}
+
+	// Without a CallLog nothing is recorded
+	recordChange(context.Background(), "/hostedzone/Z123", records, result, nil)
+
+	ctx, calls := WithCallLog(context.Background())
+	recordChange(ctx, "/hostedzone/Z123", records, result, nil)
+	failed := &awshttp.ResponseError{ResponseError: &smithyhttp.ResponseError{Err: errors.New("denied")}, RequestID: "req-2"}
+	recordChange(ctx, "Z123", records[:1], nil, failed)
+
+	assert.Equal(t, []Call{
+		{
+			Operation: "route53:ChangeResourceRecordSets", Resource: "Z123: app.example.com A, app.example.com AAAA",
+			RequestID: "req-1", ChangeID: "/change/C1",
+		},
+		{Operation: "route53:ChangeResourceRecordSets", Resource: "Z123: app.example.com A", RequestID: "req-2", Err: failed},
+	}, calls.Calls())
+}
+
+func TestChangeComment(t *testing.T) {
+	records := []DNSRecord{{Name: "app.example.com", Type: "A"}, {Name: "app.example.com", Type: "AAAA"}}
+	assert.Equal(t, "UPSERT app.example.com A", changeComment(context.Background(), "UPSERT", records[:1]))
+
+	ctx := WithChangeComment(context.Background(), "GatewayHostnameRequest default/app")
+	assert.Equal(t, "GatewayHostnameRequest default/app: DELETE app.example.com A, app.example.com AAAA",
+		changeComment(ctx, "DELETE", records))
+
+	many := make([]DNSRecord, 20)
+	for i := range many {
+		many[i] = DNSRecord{Name: "_acme-challenge.preview.example.com", Type: "TXT"}
+	}
+	comment := changeComment(ctx, "DELETE", many)
+	assert.Len(t, comment, maxChangeCommentLength)
+	assert.True(t, strings.HasSuffix(comment, "..."))
+}
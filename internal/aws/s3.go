@@ -0,0 +1,20 @@
+package aws
+
+import (
+	"context"
+	"io"
+)
+
+// ObjectStore reads and writes the objects of a single S3 bucket
+type ObjectStore interface {
+	// ListObjects returns the keys of the objects starting with the prefix, in key order. If a
+	// delimiter is set, keys containing it after the prefix are rolled up into the returned
+	// common prefixes instead.
+	ListObjects(ctx context.Context, prefix, delimiter string) (keys, prefixes []string, err error)
+
+	// GetObject returns the object's content; the caller closes it
+	GetObject(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// PutObject writes the object, replacing one with the same key
+	PutObject(ctx context.Context, key string, body []byte) error
+}
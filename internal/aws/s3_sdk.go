@@ -0,0 +1,74 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// SDKObjectStore implements ObjectStore for one bucket using AWS SDK v2
+type SDKObjectStore struct {
+	client *s3.Client
+	bucket string
+}
+
+// NewSDKObjectStore creates an object store for a bucket using the provided AWS config
+func NewSDKObjectStore(cfg aws.Config, bucket string) *SDKObjectStore {
+	return &SDKObjectStore{
+		client: s3.NewFromConfig(cfg),
+		bucket: bucket,
+	}
+}
+
+func (s *SDKObjectStore) ListObjects(ctx context.Context, prefix, delimiter string) ([]string, []string, error) {
+	var keys, prefixes []string
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}
+	if delimiter != "" {
+		input.Delimiter = aws.String(delimiter)
+	}
+	paginator := s3.NewListObjectsV2Paginator(s.client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+		for _, object := range page.Contents {
+			keys = append(keys, aws.ToString(object.Key))
+		}
+		for _, p := range page.CommonPrefixes {
+			prefixes = append(prefixes, aws.ToString(p.Prefix))
+		}
+	}
+	return keys, prefixes, nil
+}
+
+func (s *SDKObjectStore) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	result, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object %s: %w", key, err)
+	}
+	return result.Body, nil
+}
+
+func (s *SDKObjectStore) PutObject(ctx context.Context, key string, body []byte) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		Body:          bytes.NewReader(body),
+		ContentLength: aws.Int64(int64(len(body))),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put object %s: %w", key, err)
+	}
+	return nil
+}
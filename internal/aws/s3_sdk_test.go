@@ -0,0 +1,81 @@
+package aws
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestObjectStore(t *testing.T, handler http.HandlerFunc) *SDKObjectStore {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	// The test server can't serve virtual-hosted bucket names
+	client := s3.NewFromConfig(testConfig(server), func(o *s3.Options) { o.UsePathStyle = true })
+	return &SDKObjectStore{client: client, bucket: "logs"}
+}
+
+func TestSDKObjectStore_ListObjects(t *testing.T) {
+	pages := map[string]string{
+		"": `<ListBucketResult>
+  <Contents><Key>alb/a.log.gz</Key></Contents>
+  <CommonPrefixes><Prefix>alb/AWSLogs/</Prefix></CommonPrefixes>
+  <IsTruncated>true</IsTruncated>
+  <NextContinuationToken>page2</NextContinuationToken>
+</ListBucketResult>`,
+		"page2": `<ListBucketResult>
+  <Contents><Key>alb/b.log.gz</Key></Contents>
+  <IsTruncated>false</IsTruncated>
+</ListBucketResult>`,
+	}
+	s := newTestObjectStore(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/logs", r.URL.Path)
+		assert.Equal(t, "2", r.URL.Query().Get("list-type"))
+		assert.Equal(t, "alb/", r.URL.Query().Get("prefix"))
+		assert.Equal(t, "/", r.URL.Query().Get("delimiter"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-west-1/s3/aws4_request")
+		_, _ = w.Write([]byte(pages[r.URL.Query().Get("continuation-token")]))
+	})
+
+	keys, prefixes, err := s.ListObjects(context.Background(), "alb/", "/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alb/a.log.gz", "alb/b.log.gz"}, keys)
+	assert.Equal(t, []string{"alb/AWSLogs/"}, prefixes)
+}
+
+func TestSDKObjectStore_PutAndGetObject(t *testing.T) {
+	objects := map[string]string{}
+	s := newTestObjectStore(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = string(body)
+		case http.MethodGet:
+			body, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`))
+				return
+			}
+			_, _ = w.Write([]byte(body))
+		}
+	})
+
+	key := "logs/hostname=app.opendi.com/year=2026/a.log.gz"
+	require.NoError(t, s.PutObject(context.Background(), key, []byte("data")))
+	assert.Contains(t, objects, "/logs/"+key)
+
+	body, err := s.GetObject(context.Background(), key)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(body)
+	body.Close()
+	assert.Equal(t, "data", string(data))
+
+	_, err = s.GetObject(context.Background(), "missing")
+	assert.ErrorContains(t, err, "NoSuchKey")
+}
@@ -0,0 +1,181 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrShadowed is returned by the shadow clients for calls that would create a resource, since
+// there is no resource to return
+var ErrShadowed = errors.New("not created in shadow mode")
+
+// ShadowReport is told about every mutating call a shadow client skipped, with the resource
+// it would have changed and what would have changed; no changes means the call was a no-op
+// against the current state. Operations are named after the client methods.
+type ShadowReport func(ctx context.Context, operation, resource string, changes []string)
+
+// ShadowACMClient passes reads to an ACM client and reports writes instead of making them
+type ShadowACMClient struct {
+	inner  ACMClient
+	report ShadowReport
+}
+
+// NewShadowACMClient wraps inner so that it never changes ACM
+func NewShadowACMClient(inner ACMClient, report ShadowReport) *ShadowACMClient {
+	return &ShadowACMClient{inner: inner, report: report}
+}
+
+func (c *ShadowACMClient) RequestCertificate(ctx context.Context, domain, idempotencyToken string, tags map[string]string) (string, error) {
+	c.report(ctx, "RequestCertificate", domain, []string{"certificate for " + domain + ": <unset> -> requested"})
+	return "", ErrShadowed
+}
+
+func (c *ShadowACMClient) RequestCertificateWithSANs(ctx context.Context, domain string, subjectAlternativeNames []string, idempotencyToken string, tags map[string]string) (string, error) {
+	names := strings.Join(append([]string{domain}, subjectAlternativeNames...), ",")
+	c.report(ctx, "RequestCertificate", domain, []string{"certificate for " + names + ": <unset> -> requested"})
+	return "", ErrShadowed
+}
+
+func (c *ShadowACMClient) DescribeCertificate(ctx context.Context, certArn string) (*CertificateDetails, error) {
+	return c.inner.DescribeCertificate(ctx, certArn)
+}
+
+func (c *ShadowACMClient) DeleteCertificate(ctx context.Context, certArn string) error {
+	var changes []string
+	if _, err := c.inner.DescribeCertificate(ctx, certArn); !IsNotFound(err) {
+		changes = []string{"certificate: exists -> <unset>"}
+	}
+	c.report(ctx, "DeleteCertificate", certArn, changes)
+	return nil
+}
+
+func (c *ShadowACMClient) GetValidationRecords(ctx context.Context, certArn string) ([]ValidationRecord, error) {
+	return c.inner.GetValidationRecords(ctx, certArn)
+}
+
+func (c *ShadowACMClient) ImportCertificate(ctx context.Context, certArn string, certificate, privateKey, chain []byte, tags map[string]string) (string, error) {
+	if certArn == "" {
+		c.report(ctx, "ImportCertificate", "new certificate", []string{"certificate: <unset> -> imported"})
+		return "", ErrShadowed
+	}
+	c.report(ctx, "ImportCertificate", certArn, []string{"certificate: reimported"})
+	return certArn, nil
+}
+
+func (c *ShadowACMClient) ListCertificates(ctx context.Context) ([]CertificateSummary, error) {
+	return c.inner.ListCertificates(ctx)
+}
+
+func (c *ShadowACMClient) GetCertificateTags(ctx context.Context, certArn string) (map[string]string, error) {
+	return c.inner.GetCertificateTags(ctx, certArn)
+}
+
+// ShadowRoute53Client passes reads to a Route53 client and reports writes instead of making them.
+// Record writes are compared with the live record, so writes that change nothing are reported
+// without changes.
+type ShadowRoute53Client struct {
+	inner  Route53Client
+	report ShadowReport
+}
+
+// NewShadowRoute53Client wraps inner so that it never changes Route53
+func NewShadowRoute53Client(inner Route53Client, report ShadowReport) *ShadowRoute53Client {
+	return &ShadowRoute53Client{inner: inner, report: report}
+}
+
+// describeRecord formats what a record set resolves to, with Values and Value as GetRecord
+// fills them in treated alike
+func describeRecord(record *DNSRecord) string {
+	if record == nil {
+		return "<unset>"
+	}
+	if record.AliasTarget != nil {
+		return fmt.Sprintf("ALIAS %s (zone %s, evaluate health %t)", record.AliasTarget.DNSName,
+			record.AliasTarget.HostedZoneID, record.AliasTarget.EvaluateTargetHealth)
+	}
+	values := record.Values
+	if len(values) == 0 {
+		values = []string{record.Value}
+	}
+	values = slices.Clone(values)
+	slices.Sort(values)
+	return fmt.Sprintf("%s (ttl %d)", strings.Join(values, ","), record.TTL)
+}
+
+// recordChange compares the live record with the desired one, nil for a deletion. Errors
+// reading the live record count as a change, as the write would have been made.
+func (c *ShadowRoute53Client) recordChange(ctx context.Context, zoneId string, record DNSRecord, desired *DNSRecord) []string {
+	live, err := c.inner.GetRecord(ctx, zoneId, record.Name, record.Type)
+	if err != nil {
+		live = &DNSRecord{Value: "<unknown: " + err.Error() + ">"}
+	}
+	before, after := describeRecord(live), describeRecord(desired)
+	if before == after {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s %s: %s -> %s", record.Name, record.Type, before, after)}
+}
+
+func (c *ShadowRoute53Client) CreateOrUpdateRecord(ctx context.Context, zoneId string, record DNSRecord) error {
+	c.report(ctx, "CreateOrUpdateRecord", zoneId+": "+record.Name+" "+record.Type, c.recordChange(ctx, zoneId, record, &record))
+	return nil
+}
+
+func (c *ShadowRoute53Client) DeleteRecord(ctx context.Context, zoneId string, record DNSRecord) error {
+	c.report(ctx, "DeleteRecord", zoneId+": "+record.Name+" "+record.Type, c.recordChange(ctx, zoneId, record, nil))
+	return nil
+}
+
+func (c *ShadowRoute53Client) DeleteRecords(ctx context.Context, zoneId string, records []DNSRecord) error {
+	for _, record := range records {
+		c.report(ctx, "DeleteRecords", zoneId+": "+record.Name+" "+record.Type, c.recordChange(ctx, zoneId, record, nil))
+	}
+	return nil
+}
+
+func (c *ShadowRoute53Client) GetRecord(ctx context.Context, zoneId string, name, recordType string) (*DNSRecord, error) {
+	return c.inner.GetRecord(ctx, zoneId, name, recordType)
+}
+
+func (c *ShadowRoute53Client) GetDNSSEC(ctx context.Context, zoneId string) (*DNSSECStatus, error) {
+	return c.inner.GetDNSSEC(ctx, zoneId)
+}
+
+func (c *ShadowRoute53Client) GetHostedZoneByName(ctx context.Context, name string) (*HostedZone, error) {
+	return c.inner.GetHostedZoneByName(ctx, name)
+}
+
+func (c *ShadowRoute53Client) CreateHostedZone(ctx context.Context, name, callerReference string) (*HostedZone, error) {
+	c.report(ctx, "CreateHostedZone", name, []string{"hosted zone " + name + ": <unset> -> created"})
+	return nil, ErrShadowed
+}
+
+func (c *ShadowRoute53Client) GetHostedZone(ctx context.Context, zoneId string) (*HostedZone, error) {
+	return c.inner.GetHostedZone(ctx, zoneId)
+}
+
+// vpcChange returns the change of associating (or disassociating) the VPC with the zone, or nil
+// if it already is (or isn't)
+func (c *ShadowRoute53Client) vpcChange(ctx context.Context, zoneId string, vpc VPC, associate bool) []string {
+	zone, err := c.inner.GetHostedZone(ctx, zoneId)
+	if err == nil && zone != nil && slices.Contains(zone.VPCs, vpc) == associate {
+		return nil
+	}
+	if associate {
+		return []string{"vpcs[" + vpc.String() + "]: <unset> -> associated"}
+	}
+	return []string{"vpcs[" + vpc.String() + "]: associated -> <unset>"}
+}
+
+func (c *ShadowRoute53Client) AssociateVPC(ctx context.Context, zoneId string, vpc VPC) error {
+	c.report(ctx, "AssociateVPC", zoneId, c.vpcChange(ctx, zoneId, vpc, true))
+	return nil
+}
+
+func (c *ShadowRoute53Client) DisassociateVPC(ctx context.Context, zoneId string, vpc VPC) error {
+	c.report(ctx, "DisassociateVPC", zoneId, c.vpcChange(ctx, zoneId, vpc, false))
+	return nil
+}
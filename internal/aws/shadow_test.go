@@ -0,0 +1,70 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type shadowCall struct {
+	operation string
+	resource  string
+	changes   []string
+}
+
+func shadowRecorder() (*[]shadowCall, ShadowReport) {
+	var calls []shadowCall
+	return &calls, func(ctx context.Context, operation, resource string, changes []string) {
+		calls = append(calls, shadowCall{operation: operation, resource: resource, changes: changes})
+	}
+}
+
+func TestShadowRoute53Client(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMockRoute53Client()
+	inner.Records["Z1:app.example.com:CNAME"] = DNSRecord{Name: "app.example.com", Type: "CNAME", Value: "alb-1.example.com", TTL: 300}
+	calls, report := shadowRecorder()
+	c := NewShadowRoute53Client(inner, report)
+
+	require.NoError(t, c.CreateOrUpdateRecord(ctx, "Z1", DNSRecord{Name: "app.example.com", Type: "CNAME", Values: []string{"alb-1.example.com"}, TTL: 300}))
+	require.NoError(t, c.CreateOrUpdateRecord(ctx, "Z1", DNSRecord{Name: "app.example.com", Type: "CNAME", Value: "alb-2.example.com", TTL: 300}))
+	require.NoError(t, c.DeleteRecord(ctx, "Z1", DNSRecord{Name: "old.example.com", Type: "CNAME"}))
+	require.NoError(t, c.DeleteRecords(ctx, "Z1", []DNSRecord{{Name: "app.example.com", Type: "CNAME"}}))
+
+	assert.Equal(t, []shadowCall{
+		{operation: "CreateOrUpdateRecord", resource: "Z1: app.example.com CNAME"},
+		{operation: "CreateOrUpdateRecord", resource: "Z1: app.example.com CNAME",
+			changes: []string{"app.example.com CNAME: alb-1.example.com (ttl 300) -> alb-2.example.com (ttl 300)"}},
+		{operation: "DeleteRecord", resource: "Z1: old.example.com CNAME"},
+		{operation: "DeleteRecords", resource: "Z1: app.example.com CNAME",
+			changes: []string{"app.example.com CNAME: alb-1.example.com (ttl 300) -> <unset>"}},
+	}, *calls)
+	assert.Equal(t, "alb-1.example.com", inner.Records["Z1:app.example.com:CNAME"].Value, "nothing is written")
+
+	_, err := c.CreateHostedZone(ctx, "team.example.com", "ref")
+	assert.True(t, errors.Is(err, ErrShadowed))
+	assert.Empty(t, inner.HostedZones)
+}
+
+func TestShadowACMClient(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMockACMClient()
+	arn, err := inner.RequestCertificate(ctx, "app.example.com", "token", nil)
+	require.NoError(t, err)
+	calls, report := shadowRecorder()
+	c := NewShadowACMClient(inner, report)
+
+	_, err = c.RequestCertificate(ctx, "new.example.com", "token-2", nil)
+	assert.True(t, errors.Is(err, ErrShadowed))
+	require.NoError(t, c.DeleteCertificate(ctx, arn))
+
+	details, err := c.DescribeCertificate(ctx, arn)
+	require.NoError(t, err, "the certificate is still there")
+	assert.NotNil(t, details)
+	require.Len(t, *calls, 2)
+	assert.Equal(t, "RequestCertificate", (*calls)[0].operation)
+	assert.Equal(t, shadowCall{operation: "DeleteCertificate", resource: arn, changes: []string{"certificate: exists -> <unset>"}}, (*calls)[1])
+}
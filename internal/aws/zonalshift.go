@@ -0,0 +1,19 @@
+package aws
+
+import (
+	"context"
+	"time"
+)
+
+// ZonalShiftClient starts and cancels ARC zonal shifts, which move the traffic of a load
+// balancer away from one availability zone until they expire. Load balancers need the
+// zonal_shift.config.enabled attribute.
+type ZonalShiftClient interface {
+	// StartZonalShift shifts the resource's traffic away from the availability zone (an AZ ID
+	// such as use1-az1) for the duration, at most three days, and returns the shift's ID
+	StartZonalShift(ctx context.Context, resourceArn, awayFrom string, expiresIn time.Duration, comment string) (string, error)
+
+	// CancelZonalShift ends the shift before it expires. A shift that has already ended is
+	// reported by IsNotFound.
+	CancelZonalShift(ctx context.Context, zonalShiftID string) error
+}
@@ -0,0 +1,46 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/arczonalshift"
+)
+
+// SDKZonalShiftClient implements ZonalShiftClient using AWS SDK v2
+type SDKZonalShiftClient struct {
+	client *arczonalshift.Client
+}
+
+// NewSDKZonalShiftClient creates a zonal shift client using the provided AWS config
+func NewSDKZonalShiftClient(cfg aws.Config) *SDKZonalShiftClient {
+	return &SDKZonalShiftClient{
+		client: arczonalshift.NewFromConfig(cfg),
+	}
+}
+
+func (c *SDKZonalShiftClient) StartZonalShift(ctx context.Context, resourceArn, awayFrom string, expiresIn time.Duration, comment string) (string, error) {
+	result, err := c.client.StartZonalShift(ctx, &arczonalshift.StartZonalShiftInput{
+		ResourceIdentifier: aws.String(resourceArn),
+		AwayFrom:           aws.String(awayFrom),
+		// The API takes whole minutes or hours
+		ExpiresIn: aws.String(fmt.Sprintf("%dm", int(expiresIn.Minutes()))),
+		Comment:   aws.String(comment),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to start zonal shift of %s away from %s: %w", resourceArn, awayFrom, err)
+	}
+	return aws.ToString(result.ZonalShiftId), nil
+}
+
+func (c *SDKZonalShiftClient) CancelZonalShift(ctx context.Context, zonalShiftID string) error {
+	_, err := c.client.CancelZonalShift(ctx, &arczonalshift.CancelZonalShiftInput{
+		ZonalShiftId: aws.String(zonalShiftID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cancel zonal shift %s: %w", zonalShiftID, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSDKZonalShiftClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-west-1/arc-zonal-shift/aws4_request")
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/zonalshifts":
+			var input map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+			assert.Equal(t, map[string]string{
+				"resourceIdentifier": "arn:aws:elasticloadbalancing:eu-west-1:123456789012:loadbalancer/app/gw-01/abc",
+				"awayFrom":           "euw1-az1",
+				"expiresIn":          "90m",
+				"comment":            "evacuation",
+			}, input)
+			_, _ = w.Write([]byte(`{"zonalShiftId":"shift-1","status":"ACTIVE"}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/zonalshifts/shift-1":
+			w.Header().Set("X-Amzn-ErrorType", "ResourceNotFoundException:http://internal.amazon.com/coral/")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"zonal shift has expired"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	c := NewSDKZonalShiftClient(testConfig(server))
+
+	id, err := c.StartZonalShift(context.Background(), "arn:aws:elasticloadbalancing:eu-west-1:123456789012:loadbalancer/app/gw-01/abc",
+		"euw1-az1", 90*time.Minute, "evacuation")
+	require.NoError(t, err)
+	assert.Equal(t, "shift-1", id)
+
+	err = c.CancelZonalShift(context.Background(), "shift-1")
+	assert.ErrorContains(t, err, "zonal shift has expired")
+	assert.True(t, IsNotFound(err))
+}
@@ -2,11 +2,13 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -16,6 +18,16 @@ import (
 	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
 )
 
+// dualstackLBC returns the LoadBalancerConfiguration of a Gateway whose ALB is dualstack
+func dualstackLBC(gatewayName, namespace string) *unstructured.Unstructured {
+	lbc := &unstructured.Unstructured{}
+	lbc.SetGroupVersionKind(LoadBalancerConfigurationGVK)
+	lbc.SetName(gatewayName + "-config")
+	lbc.SetNamespace(namespace)
+	lbc.Object["spec"] = map[string]interface{}{"ipAddressType": "dualstack"}
+	return lbc
+}
+
 func TestEnsureRoute53Alias_CreatesBothAAndAAAARecords(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = gatewayv1alpha1.AddToScheme(scheme)
@@ -54,7 +66,7 @@ func TestEnsureRoute53Alias_CreatesBothAAndAAAARecords(t *testing.T) {
 
 	fakeClient := fake.NewClientBuilder().
 		WithScheme(scheme).
-		WithObjects(gateway, ghr).
+		WithObjects(gateway, ghr, dualstackLBC("gw-01", "edge")).
 		Build()
 
 	route53Mock := &MockRoute53Client{
@@ -285,7 +297,7 @@ func TestEnsureRoute53Alias_IdempotentForBothRecordTypes(t *testing.T) {
 
 	fakeClient := fake.NewClientBuilder().
 		WithScheme(scheme).
-		WithObjects(gateway, ghr).
+		WithObjects(gateway, ghr, dualstackLBC("gw-01", "edge")).
 		Build()
 
 	route53Mock := &MockRoute53Client{
@@ -325,3 +337,160 @@ func TestEnsureRoute53Alias_IdempotentForBothRecordTypes(t *testing.T) {
 	assert.Equal(t, 2, aCount, "Expected 2 A records (one per call)")
 	assert.Equal(t, 2, aaaaCount, "Expected 2 AAAA records (one per call)")
 }
+
+func TestDeleteAliasRecords_DeletesExistingRecordsInUnknownRegion(t *testing.T) {
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+			Hostname: "app.opendi.com",
+			ZoneId:   "Z123456",
+		},
+		Status: gatewayv1alpha1.GatewayHostnameRequestStatus{
+			// Region missing from the ALB hosted zone table
+			AssignedLoadBalancer: "k8s-gw01-abcdef1234-1234567890.il-central-1.elb.amazonaws.com",
+		},
+	}
+
+	route53Mock := &MockRoute53Client{
+		records: map[string][]aws.DNSRecord{
+			"Z123456": {
+				{Name: "app.opendi.com", Type: "A", AliasTarget: &aws.AliasTarget{
+					DNSName:      "k8s-gw01-abcdef1234-1234567890.il-central-1.elb.amazonaws.com.",
+					HostedZoneID: "Z09170902867EHPV2DABU",
+				}},
+				// Not created by the orchestrator
+				{Name: "app.opendi.com", Type: "AAAA", Value: "2001:db8::1", TTL: 300},
+			},
+		},
+	}
+
+	reconciler := &GatewayHostnameRequestReconciler{
+		Route53Client: route53Mock,
+	}
+
+	failed := reconciler.deleteAliasRecords(context.Background(), ghr)
+	assert.Empty(t, failed)
+
+	records := route53Mock.records["Z123456"]
+	require.Len(t, records, 1, "Expected only the non-alias record to remain")
+	assert.Equal(t, "AAAA", records[0].Type)
+	assert.Nil(t, records[0].AliasTarget)
+}
+
+func TestEnsureRoute53Alias_IPv4OnlyALBGetsNoAAAARecord(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = gatewayv1alpha1.AddToScheme(scheme)
+	_ = gwapiv1.Install(scheme)
+
+	lbDNS := "k8s-gw01-abcdef1234-1234567890.us-east-1.elb.amazonaws.com"
+	hostnameType := gwapiv1.HostnameAddressType
+	gateway := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw-01", Namespace: "edge"},
+		Status: gwapiv1.GatewayStatus{
+			Addresses: []gwapiv1.GatewayStatusAddress{{Type: &hostnameType, Value: lbDNS}},
+		},
+	}
+
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-request", Namespace: "default"},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+			Hostname: "app.opendi.com",
+			ZoneId:   "Z123456",
+		},
+		Status: gatewayv1alpha1.GatewayHostnameRequestStatus{
+			AssignedGateway:          "gw-01",
+			AssignedGatewayNamespace: "edge",
+		},
+	}
+
+	// AAAA alias left over from when the ALB was dualstack
+	route53Mock := &MockRoute53Client{
+		records: map[string][]aws.DNSRecord{
+			"Z123456": {{Name: "app.opendi.com", Type: "AAAA", AliasTarget: &aws.AliasTarget{DNSName: lbDNS}}},
+		},
+	}
+
+	reconciler := &GatewayHostnameRequestReconciler{
+		// No LoadBalancerConfiguration ipAddressType: the ALB is IPv4-only
+		Client:        fake.NewClientBuilder().WithScheme(scheme).WithObjects(gateway, ghr).Build(),
+		Scheme:        scheme,
+		Recorder:      record.NewFakeRecorder(10),
+		Route53Client: route53Mock,
+	}
+
+	require.NoError(t, reconciler.ensureRoute53Alias(context.Background(), ghr))
+
+	records := route53Mock.records["Z123456"]
+	require.Len(t, records, 1)
+	assert.Equal(t, "A", records[0].Type)
+	assert.Equal(t, []string{"A"}, ghr.Status.AliasRecordTypes)
+	assert.True(t, reconciler.aliasRecordTypesInSync(context.Background(), ghr))
+}
+
+func TestEnsureRoute53Alias_IPv6OnlyHostnameGetsOnlyAAAARecord(t *testing.T) {
+	scheme := getTestScheme()
+	lbDNS := "k8s-gw01-abcdef1234-1234567890.us-east-1.elb.amazonaws.com"
+	hostnameType := gwapiv1.HostnameAddressType
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw-01", Namespace: "edge"},
+		Status: gwapiv1.GatewayStatus{
+			Addresses: []gwapiv1.GatewayStatusAddress{{Type: &hostnameType, Value: lbDNS}},
+		},
+	}
+	ghr := assignedGHR("test-request", "app.opendi.com")
+	ghr.Spec.ZoneId = "Z123456"
+	ghr.Spec.IPv6Only = true
+	ghr.Status.AliasRecordTypes = []string{"A", "AAAA"}
+
+	// A alias left over from before the hostname became IPv6-only
+	route53Mock := &MockRoute53Client{
+		records: map[string][]aws.DNSRecord{
+			ghr.Spec.ZoneId: {{Name: "app.opendi.com", Type: "A", AliasTarget: &aws.AliasTarget{DNSName: lbDNS}}},
+		},
+	}
+	reconciler := &GatewayHostnameRequestReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(scheme).WithObjects(gw, ghr, dualstackLBC("gw-01", "edge")).Build(),
+		Scheme:        scheme,
+		Recorder:      record.NewFakeRecorder(10),
+		Route53Client: route53Mock,
+		IPAddressType: "dualstack",
+	}
+	require.NoError(t, reconciler.validateRequest(ghr))
+	assert.False(t, reconciler.aliasRecordTypesInSync(context.Background(), ghr), "the A record is still published")
+
+	require.NoError(t, reconciler.ensureRoute53Alias(context.Background(), ghr))
+	records := route53Mock.records[ghr.Spec.ZoneId]
+	require.Len(t, records, 1)
+	assert.Equal(t, "AAAA", records[0].Type)
+	assert.Equal(t, []string{"AAAA"}, ghr.Status.AliasRecordTypes)
+	assert.True(t, reconciler.aliasRecordTypesInSync(context.Background(), ghr))
+
+	// An IPv4-only ALB can't serve the hostname
+	reconciler.IPAddressType = ""
+	assert.ErrorContains(t, reconciler.validateRequest(ghr), "ipv6Only needs dualstack ALBs")
+	reconciler.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(gw, ghr).Build()
+	assert.ErrorContains(t, reconciler.ensureRoute53Alias(context.Background(), ghr), "IPv4-only")
+}
+
+// TestEnsureRoute53Alias_WaitsForLoadBalancerAddress verifies that a Gateway without an ALB
+// address yields the ErrLoadBalancerNotReady wait state instead of a plain error.
+func TestEnsureRoute53Alias_WaitsForLoadBalancerAddress(t *testing.T) {
+	scheme := getTestScheme()
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw-01", Namespace: "edge"},
+	}
+	ghr := assignedGHR("test-request", "app.opendi.com")
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gw, ghr).Build()
+
+	route53Mock := &MockRoute53Client{records: make(map[string][]aws.DNSRecord)}
+	reconciler := &GatewayHostnameRequestReconciler{
+		Client:        fakeClient,
+		Scheme:        scheme,
+		Recorder:      record.NewFakeRecorder(10),
+		Route53Client: route53Mock,
+	}
+
+	err := reconciler.ensureRoute53Alias(context.Background(), ghr)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrLoadBalancerNotReady), "got %v", err)
+	assert.Empty(t, route53Mock.records)
+}
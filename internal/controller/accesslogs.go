@@ -0,0 +1,249 @@
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+// AccessLogs delivers per-hostname access logs. The ALBs of all managed Gateways write their
+// access logs to <prefix>/alb in the bucket, readable only by the platform team. For every
+// request with spec.accessLogs, the lines of its hostname are copied to
+// <prefix>/hostnames/hostname=<hostname>/year=<yyyy>/month=<mm>/day=<dd>/, so a team can be
+// granted its own prefix and query it with Athena without seeing other tenants' traffic.
+// It runs as a manager Runnable.
+type AccessLogs struct {
+	Client   client.Client
+	Objects  aws.ObjectStore
+	Bucket   string
+	Prefix   string
+	Interval time.Duration
+
+	// done remembers the ALB log files already split, by key
+	done map[string]bool
+}
+
+// Start implements manager.Runnable
+func (a *AccessLogs) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("access-logs")
+	logger.Info("Starting access log delivery", "bucket", a.Bucket, "interval", a.Interval)
+
+	ticker := time.NewTicker(a.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.Sync(ctx); err != nil {
+			logger.Error(err, "Access log delivery failed")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync splits the ALB log files of yesterday and today (UTC) that weren't split yet. After a
+// restart they are split again, to the same keys.
+func (a *AccessLogs) Sync(ctx context.Context) error {
+	return a.sync(ctx, time.Now().UTC())
+}
+
+func (a *AccessLogs) sync(ctx context.Context, now time.Time) error {
+	logger := log.FromContext(ctx)
+
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := a.Client.List(ctx, &ghrList); err != nil {
+		return fmt.Errorf("failed to list requests: %w", err)
+	}
+	hostnames := map[string]bool{}
+	for _, ghr := range ghrList.Items {
+		if ghr.Spec.AccessLogs && ghr.DeletionTimestamp.IsZero() && ghr.Status.AssignedGateway != "" {
+			hostnames[ghr.Spec.Hostname] = true
+		}
+	}
+	if len(hostnames) == 0 {
+		return nil
+	}
+
+	// <alb>/AWSLogs/<account>/elasticloadbalancing/<region>/<yyyy>/<mm>/<dd>/<file>
+	_, accounts, err := a.Objects.ListObjects(ctx, a.albPrefix()+"/AWSLogs/", "/")
+	if err != nil {
+		return err
+	}
+	var regions []string
+	for _, account := range accounts {
+		_, r, err := a.Objects.ListObjects(ctx, account+"elasticloadbalancing/", "/")
+		if err != nil {
+			return err
+		}
+		regions = append(regions, r...)
+	}
+
+	if a.done == nil {
+		a.done = map[string]bool{}
+	}
+	days := []time.Time{now.AddDate(0, 0, -1), now}
+	current := map[string]bool{}
+	for _, region := range regions {
+		for _, day := range days {
+			keys, _, err := a.Objects.ListObjects(ctx, region+day.Format("2006/01/02/"), "")
+			if err != nil {
+				return err
+			}
+			for _, key := range keys {
+				current[key] = true
+				if a.done[key] {
+					continue
+				}
+				written, err := a.split(ctx, key, day, hostnames)
+				if err != nil {
+					return err
+				}
+				a.done[key] = true
+				if written > 0 {
+					logger.V(1).Info("Delivered access logs", "file", key, "hostnames", written)
+				}
+			}
+		}
+	}
+	// Forget the files of days that are no longer looked at
+	for key := range a.done {
+		if !current[key] {
+			delete(a.done, key)
+		}
+	}
+	return nil
+}
+
+// split copies the lines of an ALB log file to the hostnames they are for, one file per
+// hostname with the name of the ALB log file. Returns the number of files written.
+func (a *AccessLogs) split(ctx context.Context, key string, day time.Time, hostnames map[string]bool) (int, error) {
+	body, err := a.Objects.GetObject(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+	gz, err := gzip.NewReader(body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+
+	type output struct {
+		buf bytes.Buffer
+		gz  *gzip.Writer
+	}
+	outputs := map[string]*output{}
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		hostname := matchHostname(logLineHost(scanner.Text()), hostnames)
+		if hostname == "" {
+			continue
+		}
+		out := outputs[hostname]
+		if out == nil {
+			out = &output{}
+			out.gz = gzip.NewWriter(&out.buf)
+			outputs[hostname] = out
+		}
+		_, _ = out.gz.Write(scanner.Bytes())
+		_, _ = out.gz.Write([]byte{'\n'})
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+
+	sorted := make([]string, 0, len(outputs))
+	for hostname := range outputs {
+		sorted = append(sorted, hostname)
+	}
+	sort.Strings(sorted)
+	for _, hostname := range sorted {
+		out := outputs[hostname]
+		if err := out.gz.Close(); err != nil {
+			return 0, err
+		}
+		target := a.hostnamePrefix(hostname) + day.Format("year=2006/month=01/day=02/") + path.Base(key)
+		if err := a.Objects.PutObject(ctx, target, out.buf.Bytes()); err != nil {
+			return 0, err
+		}
+	}
+	return len(sorted), nil
+}
+
+// albPrefix returns the prefix the ALBs write their access logs to
+func (a *AccessLogs) albPrefix() string {
+	return path.Join(a.Prefix, "alb")
+}
+
+// hostnamePrefix returns the prefix of a hostname's access logs, ending in a slash. The * of a
+// wildcard hostname is written as _.
+func (a *AccessLogs) hostnamePrefix(hostname string) string {
+	return path.Join(a.Prefix, "hostnames", "hostname="+strings.ReplaceAll(hostname, "*", "_")) + "/"
+}
+
+// loadBalancerAttributes returns the ALB attributes that turn on access logs
+func (a *AccessLogs) loadBalancerAttributes() []interface{} {
+	return []interface{}{
+		map[string]interface{}{"key": "access_logs.s3.enabled", "value": "true"},
+		map[string]interface{}{"key": "access_logs.s3.bucket", "value": a.Bucket},
+		map[string]interface{}{"key": "access_logs.s3.prefix", "value": a.albPrefix()},
+	}
+}
+
+// accessLogLocation returns where the request's access logs are delivered, or "" if it didn't
+// opt in or the controller has no access log bucket
+func (r *GatewayHostnameRequestReconciler) accessLogLocation(ghr *gatewayv1alpha1.GatewayHostnameRequest) string {
+	if r.AccessLogs == nil || !ghr.Spec.AccessLogs {
+		return ""
+	}
+	return "s3://" + r.AccessLogs.Bucket + "/" + r.AccessLogs.hostnamePrefix(ghr.Spec.Hostname)
+}
+
+// logLineHost returns the host of the request URL of an ALB access log line, e.g.
+// "GET https://app.opendi.com:443/path HTTP/1.1", or "" if the line has none
+func logLineHost(line string) string {
+	_, rest, ok := strings.Cut(line, `"`)
+	if !ok {
+		return ""
+	}
+	request, _, _ := strings.Cut(rest, `"`)
+	fields := strings.Fields(request)
+	if len(fields) < 2 {
+		return ""
+	}
+	u, err := url.Parse(fields[1])
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// matchHostname returns the opted-in hostname serving host: the host itself or the wildcard
+// covering it. Returns "" if none does.
+func matchHostname(host string, hostnames map[string]bool) string {
+	if host == "" {
+		return ""
+	}
+	if hostnames[host] {
+		return host
+	}
+	if _, parent, ok := strings.Cut(host, "."); ok && hostnames["*."+parent] {
+		return "*." + parent
+	}
+	return ""
+}
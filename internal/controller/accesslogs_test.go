@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+func albLogLine(url string) string {
+	return `https 2026-10-16T10:00:00.000000Z app/k8s-edge-gw01/50dc6c495c0c9188 192.0.2.1:2817 10.0.0.1:80 0.000 0.001 0.000 200 200 34 366 "GET ` +
+		url + ` HTTP/1.1" "curl/8.5.0" ECDHE-RSA-AES128-GCM-SHA256 TLSv1.2`
+}
+
+func gzipLines(t *testing.T, lines ...string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte(strings.Join(lines, "\n") + "\n"))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func gunzip(t *testing.T, data []byte) string {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	out, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	return string(out)
+}
+
+func TestAccessLogs_Sync(t *testing.T) {
+	optedIn := func(name, hostname string, accessLogs bool) *gatewayv1alpha1.GatewayHostnameRequest {
+		return &gatewayv1alpha1.GatewayHostnameRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: hostname, ZoneId: "Z1", AccessLogs: accessLogs},
+			Status:     gatewayv1alpha1.GatewayHostnameRequestStatus{AssignedGateway: "gw-01"},
+		}
+	}
+	k8s := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(
+		optedIn("app", "app.opendi.com", true),
+		optedIn("preview", "*.preview.opendi.com", true),
+		optedIn("other", "other.opendi.com", false),
+	).Build()
+
+	day := "logs/alb/AWSLogs/123456789012/elasticloadbalancing/eu-west-1/2026/10/16/"
+	file := "123456789012_elasticloadbalancing_eu-west-1_app.k8s-edge-gw01.50dc6c495c0c9188_20261016T1000Z_192.0.2.1_abc.log.gz"
+	store := &aws.MockObjectStore{Objects: map[string][]byte{
+		day + file: gzipLines(t,
+			albLogLine("https://app.opendi.com:443/"),
+			albLogLine("https://other.opendi.com:443/"),
+			albLogLine("https://pr-1.preview.opendi.com:443/login"),
+			albLogLine("https://APP.opendi.com:443/health"),
+		),
+	}}
+	a := &AccessLogs{Client: k8s, Objects: store, Bucket: "access-logs", Prefix: "logs"}
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, a.sync(context.Background(), now))
+
+	app := store.Objects["logs/hostnames/hostname=app.opendi.com/year=2026/month=10/day=16/"+file]
+	require.NotNil(t, app)
+	assert.Equal(t, albLogLine("https://app.opendi.com:443/")+"\n"+albLogLine("https://APP.opendi.com:443/health")+"\n", gunzip(t, app))
+	preview := store.Objects["logs/hostnames/hostname=_.preview.opendi.com/year=2026/month=10/day=16/"+file]
+	require.NotNil(t, preview)
+	assert.Equal(t, albLogLine("https://pr-1.preview.opendi.com:443/login")+"\n", gunzip(t, preview))
+	for key := range store.Objects {
+		assert.NotContains(t, key, "other.opendi.com", "hostnames that didn't opt in get no logs")
+	}
+
+	// Files already split aren't read again
+	delete(store.Objects, "logs/hostnames/hostname=app.opendi.com/year=2026/month=10/day=16/"+file)
+	require.NoError(t, a.sync(context.Background(), now))
+	assert.NotContains(t, store.Objects, "logs/hostnames/hostname=app.opendi.com/year=2026/month=10/day=16/"+file)
+}
+
+func TestLogLineHost(t *testing.T) {
+	assert.Equal(t, "app.opendi.com", logLineHost(albLogLine("https://App.opendi.com:443/path?q=1")))
+	assert.Equal(t, "", logLineHost(`http 2026-10-16T10:00:00.000000Z app/x/1 192.0.2.1:1 - -1 -1 -1 400 - 0 0 "- - - " "-" - -`))
+	assert.Equal(t, "", logLineHost("garbage"))
+}
+
+func TestEnsureLoadBalancerConfiguration_AccessLogs(t *testing.T) {
+	k8s := fake.NewClientBuilder().WithScheme(getTestScheme()).Build()
+	r := &GatewayHostnameRequestReconciler{
+		Client:     k8s,
+		AccessLogs: &AccessLogs{Bucket: "access-logs", Prefix: "logs"},
+	}
+	ctx := context.Background()
+	require.NoError(t, r.ensureLoadBalancerConfiguration(ctx, "gw-01", "edge", nil, "internet-facing", ""))
+
+	lbc := &unstructured.Unstructured{}
+	lbc.SetGroupVersionKind(LoadBalancerConfigurationGVK)
+	require.NoError(t, k8s.Get(ctx, types.NamespacedName{Name: "gw-01-config", Namespace: "edge"}, lbc))
+	attributes, _, _ := unstructured.NestedSlice(lbc.Object, "spec", "loadBalancerAttributes")
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"key": "access_logs.s3.enabled", "value": "true"},
+		map[string]interface{}{"key": "access_logs.s3.bucket", "value": "access-logs"},
+		map[string]interface{}{"key": "access_logs.s3.prefix", "value": "logs/alb"},
+	}, attributes)
+
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "app.opendi.com", AccessLogs: true}}
+	assert.Equal(t, "s3://access-logs/logs/hostnames/hostname=app.opendi.com/", r.accessLogLocation(ghr))
+	ghr.Spec.AccessLogs = false
+	assert.Empty(t, r.accessLogLocation(ghr))
+}
@@ -0,0 +1,293 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/acme"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+// Certificate issuers
+const (
+	CertificateIssuerACM  = "ACM"
+	CertificateIssuerACME = "ACME"
+)
+
+// DefaultACMERenewBefore is how long before expiry ACME certificates are renewed
+const DefaultACMERenewBefore = 30 * 24 * time.Hour
+
+// AnnotationRenewCertificateBefore renews the request's ACME certificate right away if it expires
+// before the RFC 3339 time the annotation holds. The renewed certificate expires later, so the
+// annotation can stay.
+const AnnotationRenewCertificateBefore = "gateway.opendi.com/renew-certificate-before"
+
+// challengePropagationDelay is how long a freshly published DNS-01 record is given to reach
+// the Route53 name servers before the challenge is accepted
+const challengePropagationDelay = 30 * time.Second
+
+// certificateIssuer returns the issuer of the request's certificate: the one recorded in status
+// (which may be a fallback), else spec.certificateIssuer, else ACM
+func certificateIssuer(ghr *gatewayv1alpha1.GatewayHostnameRequest) string {
+	if ghr.Status.CertificateIssuer != "" {
+		return ghr.Status.CertificateIssuer
+	}
+	if ghr.Spec.CertificateIssuer != "" {
+		return ghr.Spec.CertificateIssuer
+	}
+	return CertificateIssuerACM
+}
+
+// challengeRecordName returns the DNS-01 TXT record name; wildcards are validated on the base domain
+func challengeRecordName(hostname string) string {
+	return "_acme-challenge." + strings.TrimPrefix(hostname, "*.")
+}
+
+func (r *GatewayHostnameRequestReconciler) acmeRenewBefore() time.Duration {
+	if r.ACMERenewBefore > 0 {
+		return r.ACMERenewBefore
+	}
+	return DefaultACMERenewBefore
+}
+
+// acmeRenewalDue reports whether the request's ACME certificate is close enough to expiry to
+// renew, or expires before the time of AnnotationRenewCertificateBefore
+func (r *GatewayHostnameRequestReconciler) acmeRenewalDue(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	if certificateIssuer(ghr) != CertificateIssuerACME || ghr.Status.CertificateArn == "" || ghr.Status.CertificateNotAfter == nil {
+		return false
+	}
+	deadline := time.Now().Add(r.acmeRenewBefore())
+	if before, err := time.Parse(time.RFC3339, ghr.Annotations[AnnotationRenewCertificateBefore]); err == nil && before.After(deadline) {
+		deadline = before
+	}
+	return ghr.Status.CertificateNotAfter.Time.Before(deadline)
+}
+
+// acmeRequeueAfter returns when a Ready request with an ACME certificate needs to be looked at
+// again: soon while a renewal order is in progress, else when the renewal is due. 0 for ACM.
+func (r *GatewayHostnameRequestReconciler) acmeRequeueAfter(ghr *gatewayv1alpha1.GatewayHostnameRequest) time.Duration {
+	if certificateIssuer(ghr) != CertificateIssuerACME {
+		return 0
+	}
+	if ghr.Status.CertificateOrderURL != "" {
+		return challengePropagationDelay
+	}
+	if ghr.Status.CertificateNotAfter == nil {
+		return 0
+	}
+	return max(time.Until(ghr.Status.CertificateNotAfter.Time)-r.acmeRenewBefore(), time.Minute)
+}
+
+// reconcileACMECertificate runs steps 3-5 for certificates issued through ACME: order the
+// certificate, publish and accept the DNS-01 challenges, then import the certificate into ACM.
+// Renewals run the same steps and re-import into the existing ARN without touching the
+// conditions of the Ready request. It returns done=false when the reconcile should stop
+// with the returned result.
+func (r *GatewayHostnameRequestReconciler) reconcileACMECertificate(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (ctrl.Result, bool, error) {
+	logger := log.FromContext(ctx)
+
+	if r.ACMEIssuer == nil {
+		err := fmt.Errorf("ACME certificates are not enabled on this controller (--acme-directory)")
+		r.setCondition(ghr, ConditionTypeCertificateRequested, metav1.ConditionFalse, conditions.ReasonIssuerNotConfigured, err.Error())
+		_ = r.Status().Update(ctx, ghr)
+		return ctrl.Result{}, false, err
+	}
+
+	renewal := ghr.Status.CertificateArn != ""
+
+	// Step 3: Order the certificate (or its renewal)
+	if ghr.Status.CertificateOrderURL == "" {
+		if renewal && !r.acmeRenewalDue(ghr) {
+			return ctrl.Result{}, true, nil
+		}
+
+		awsCtx, cancel := withAWSTimeout(ctx)
+		orderURL, err := r.ACMEIssuer.NewOrder(awsCtx, ghr.Spec.Hostname)
+		cancel()
+		if err != nil {
+			if !renewal {
+				r.setCondition(ghr, ConditionTypeCertificateRequested, metav1.ConditionFalse, conditions.ReasonRequestFailed, err.Error())
+				_ = r.Status().Update(ctx, ghr)
+			}
+			r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "CertificateRequestFailed", "Failed to order certificate from the ACME CA: %v", err)
+			return ctrl.Result{}, false, err
+		}
+		ghr.Status.CertificateIssuer = CertificateIssuerACME
+		ghr.Status.CertificateOrderURL = orderURL
+		if renewal {
+			r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "CertificateRenewing", "Renewing ACME certificate expiring %s", ghr.Status.CertificateNotAfter.Format(time.RFC3339))
+		} else {
+			r.setCondition(ghr, ConditionTypeCertificateRequested, metav1.ConditionTrue, conditions.ReasonOrdered, "Certificate ordered from the ACME CA")
+			r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "CertificateRequested", "ACME certificate order submitted (%s)", orderURL)
+		}
+		if err := r.Status().Update(ctx, ghr); err != nil {
+			return ctrl.Result{}, false, err
+		}
+	}
+
+	// Step 4: Publish and accept the DNS-01 challenges
+	if err := r.ensureChallengeRecords(ctx, ghr); err != nil {
+		if errors.Is(err, ErrChallengeRecordsPropagating) {
+			if !renewal {
+				r.setCondition(ghr, ConditionTypeDnsValidated, metav1.ConditionFalse, conditions.ReasonChallengeRecordsPropagating, "Waiting for the DNS-01 challenge records to propagate")
+				_ = r.Status().Update(ctx, ghr)
+				return ctrl.Result{RequeueAfter: challengePropagationDelay}, false, nil
+			}
+			return ctrl.Result{}, true, nil
+		}
+		return r.acmeOrderFailed(ctx, ghr, renewal, ConditionTypeDnsValidated, conditions.ReasonChallengeFailed, err)
+	}
+	if !renewal && !meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeDnsValidated) {
+		r.setCondition(ghr, ConditionTypeDnsValidated, metav1.ConditionTrue, conditions.ReasonChallengesAccepted, "DNS-01 challenge records published")
+		r.Recorder.Event(ghr, corev1.EventTypeNormal, "DnsValidationRecordsCreated", "DNS-01 challenge records created in Route53")
+		if err := r.Status().Update(ctx, ghr); err != nil {
+			return ctrl.Result{}, false, err
+		}
+	}
+
+	// Step 5: Wait for the CA, then import the certificate into ACM
+	awsCtx, cancel := withAWSTimeout(ctx)
+	cert, err := r.ACMEIssuer.Certificate(awsCtx, ghr.Status.CertificateOrderURL)
+	cancel()
+	if errors.Is(err, acme.ErrOrderPending) {
+		if renewal {
+			return ctrl.Result{}, true, nil
+		}
+		r.setCondition(ghr, ConditionTypeCertificateIssued, metav1.ConditionFalse, conditions.ReasonPendingIssuance, "Waiting for the ACME CA to validate the challenges")
+		_ = r.Status().Update(ctx, ghr)
+
+		pending := meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeCertificateIssued)
+		requeueAfter := r.certificatePollInterval(time.Since(pending.LastTransitionTime.Time))
+		logger.Info("ACME certificate not yet issued, requeuing", "after", requeueAfter)
+		return ctrl.Result{RequeueAfter: requeueAfter}, false, nil
+	}
+	if err != nil {
+		return r.acmeOrderFailed(ctx, ghr, renewal, ConditionTypeCertificateIssued, conditions.ReasonOrderFailed, err)
+	}
+
+	// An import whose status write failed is repeated in place
+	orderURL, importArn := ghr.Status.CertificateOrderURL, ghr.Status.CertificateArn
+	if importArn == "" {
+		importArn = r.adoptJournaled(ctx, ghr, JournalCertificateImported, orderURL)
+	}
+	awsCtx, cancel = withAWSTimeout(aws.WithCertificateRegion(ctx, ghr.Spec.CertificateRegion))
+	certArn, err := r.ACMClient.ImportCertificate(awsCtx, importArn, cert.CertificatePEM, cert.PrivateKeyPEM, cert.ChainPEM, r.certificateTags(ghr))
+	cancel()
+	if err != nil {
+		r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "CertificateImportFailed", "Failed to import ACME certificate into ACM: %v", err)
+		return ctrl.Result{}, false, fmt.Errorf("failed to import certificate: %w", err)
+	}
+	ghr.Status.CertificateArn = certArn
+	ghr.Status.CertificateNotAfter = &metav1.Time{Time: cert.NotAfter}
+	ghr.Status.CertificateOrderURL = ""
+	if err := r.removeChallengeRecord(ctx, ghr); err != nil {
+		logger.Error(err, "Failed to delete DNS-01 challenge record")
+	}
+
+	if renewal {
+		r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "CertificateRenewed", "ACME certificate renewed, valid until %s", cert.NotAfter.Format(time.RFC3339))
+	} else {
+		r.setCondition(ghr, ConditionTypeCertificateIssued, metav1.ConditionTrue, conditions.ReasonIssued, "Certificate issued by the ACME CA and imported into ACM")
+		r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "CertificateIssued", "ACME certificate imported into ACM (%s)", certArn)
+	}
+	if err := r.updateStatusJournaled(ctx, ghr, JournalCertificateImported, orderURL, certArn); err != nil {
+		return ctrl.Result{}, false, err
+	}
+	return ctrl.Result{}, true, nil
+}
+
+// acmeOrderFailed handles an error from the ACME order. Unusable orders are dropped so the
+// next attempt starts a new one; other errors are retried with the same order.
+func (r *GatewayHostnameRequestReconciler) acmeOrderFailed(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, renewal bool, condType string, reason conditions.Reason, err error) (ctrl.Result, bool, error) {
+	if errors.Is(err, acme.ErrOrderFailed) {
+		if rmErr := r.removeChallengeRecord(ctx, ghr); rmErr != nil {
+			log.FromContext(ctx).Error(rmErr, "Failed to delete DNS-01 challenge record")
+		}
+		ghr.Status.CertificateOrderURL = ""
+	}
+	if !renewal {
+		r.setCondition(ghr, condType, metav1.ConditionFalse, reason, err.Error())
+	}
+	_ = r.Status().Update(ctx, ghr)
+	r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "CertificateOrderFailed", "ACME certificate order failed: %v", err)
+	return ctrl.Result{}, false, err
+}
+
+// ensureChallengeRecords publishes a TXT record for every pending DNS-01 challenge of the order
+// and accepts the challenges whose records are already published. It returns
+// ErrChallengeRecordsPropagating while new records still need time to propagate.
+func (r *GatewayHostnameRequestReconciler) ensureChallengeRecords(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	logger := log.FromContext(ctx)
+
+	awsCtx, cancel := withAWSTimeout(ctx)
+	challenges, err := r.ACMEIssuer.PendingChallenges(awsCtx, ghr.Status.CertificateOrderURL)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	propagating := false
+	for _, challenge := range challenges {
+		record := aws.DNSRecord{
+			Name:  challenge.RecordName,
+			Type:  "TXT",
+			Value: strconv.Quote(challenge.RecordValue),
+			TTL:   60,
+		}
+
+		recordCtx, recordCancel := withAWSTimeout(ctx)
+		existing, err := r.Route53Client.GetRecord(recordCtx, recordZoneID(ghr), record.Name, record.Type)
+		recordCancel()
+		if err != nil {
+			return fmt.Errorf("failed to get challenge record: %w", err)
+		}
+		if existing == nil || existing.Value != record.Value {
+			recordCtx, recordCancel := withAWSTimeout(ctx)
+			err := r.Route53Client.CreateOrUpdateRecord(recordCtx, recordZoneID(ghr), record)
+			recordCancel()
+			if err != nil {
+				return fmt.Errorf("failed to create challenge record: %w", err)
+			}
+			logger.Info("Created DNS-01 challenge record in Route53", "name", record.Name, "zoneId", recordZoneID(ghr))
+			propagating = true
+			continue
+		}
+
+		acceptCtx, acceptCancel := withAWSTimeout(ctx)
+		err = r.ACMEIssuer.Accept(acceptCtx, challenge)
+		acceptCancel()
+		if err != nil {
+			return err
+		}
+		logger.Info("Accepted DNS-01 challenge", "name", record.Name)
+	}
+
+	if propagating {
+		return ErrChallengeRecordsPropagating
+	}
+	return nil
+}
+
+// removeChallengeRecord deletes the hostname's DNS-01 TXT record, if any
+func (r *GatewayHostnameRequestReconciler) removeChallengeRecord(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	awsCtx, cancel := withAWSTimeout(ctx)
+	defer cancel()
+
+	record, err := r.Route53Client.GetRecord(awsCtx, recordZoneID(ghr), challengeRecordName(ghr.Spec.Hostname), "TXT")
+	if err != nil || record == nil {
+		return err
+	}
+	return r.Route53Client.DeleteRecord(awsCtx, recordZoneID(ghr), *record)
+}
@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/acme"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+func acmeTestReconciler(ghr *gatewayv1alpha1.GatewayHostnameRequest, acmClient aws.ACMClient) (*GatewayHostnameRequestReconciler, *acme.MockIssuer, *MockRoute53Client) {
+	issuer := acme.NewMockIssuer()
+	route53Mock := &MockRoute53Client{records: make(map[string][]aws.DNSRecord)}
+	return &GatewayHostnameRequestReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr).WithStatusSubresource(ghr).Build(),
+		Scheme:        getTestScheme(),
+		Recorder:      record.NewFakeRecorder(50),
+		ACMClient:     acmClient,
+		Route53Client: route53Mock,
+		ACMEIssuer:    issuer,
+	}, issuer, route53Mock
+}
+
+func acmeGHR() *gatewayv1alpha1.GatewayHostnameRequest {
+	return &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+			Hostname:          "*.app.example.com",
+			ZoneId:            "Z123456",
+			CertificateIssuer: CertificateIssuerACME,
+		},
+	}
+}
+
+func TestReconcileACMECertificate_IssuesAndImports(t *testing.T) {
+	ctx := context.Background()
+	ghr := acmeGHR()
+	acmMock := &MockACMClient{certificates: make(map[string]string)}
+	r, issuer, route53Mock := acmeTestReconciler(ghr, acmMock)
+
+	// Order and publish the challenge record, then wait for it to propagate
+	result, done, err := r.reconcileACMECertificate(ctx, ghr)
+	require.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, challengePropagationDelay, result.RequeueAfter)
+	require.NotEmpty(t, ghr.Status.CertificateOrderURL)
+	assert.True(t, meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeCertificateRequested))
+
+	records := route53Mock.records["Z123456"]
+	require.Len(t, records, 1)
+	assert.Equal(t, "_acme-challenge.app.example.com", records[0].Name)
+	assert.Equal(t, "TXT", records[0].Type)
+	assert.Equal(t, `"token-*.app.example.com"`, records[0].Value)
+
+	// The published challenge is accepted; the CA is still validating
+	_, done, err = r.reconcileACMECertificate(ctx, ghr)
+	require.NoError(t, err)
+	assert.False(t, done)
+	assert.True(t, issuer.Orders[ghr.Status.CertificateOrderURL].Accepted)
+	assert.True(t, meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeDnsValidated))
+	assert.False(t, meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeCertificateIssued))
+
+	// Once validated, the certificate is imported and the challenge record removed
+	issuer.Validate(ghr.Status.CertificateOrderURL)
+	_, done, err = r.reconcileACMECertificate(ctx, ghr)
+	require.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, "arn:aws:acm:us-east-1:123456789012:certificate/imported-wildcard.app.example.com", ghr.Status.CertificateArn)
+	assert.Equal(t, "ISSUED", acmMock.certificates[ghr.Status.CertificateArn])
+	assert.Equal(t, CertificateIssuerACME, ghr.Status.CertificateIssuer)
+	assert.Empty(t, ghr.Status.CertificateOrderURL)
+	require.NotNil(t, ghr.Status.CertificateNotAfter)
+	assert.Empty(t, route53Mock.records["Z123456"])
+	assert.True(t, meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeCertificateIssued))
+
+	// Not due for renewal: nothing to do
+	_, done, err = r.reconcileACMECertificate(ctx, ghr)
+	require.NoError(t, err)
+	assert.True(t, done)
+	assert.Len(t, issuer.Orders, 1)
+	assert.Greater(t, r.acmeRequeueAfter(ghr), 24*time.Hour)
+}
+
+func TestReconcileACMECertificate_RenewsIntoSameArn(t *testing.T) {
+	ctx := context.Background()
+	ghr := acmeGHR()
+	arn := "arn:aws:acm:us-east-1:123456789012:certificate/imported"
+	expiring := metav1.NewTime(time.Now().Add(24 * time.Hour))
+	ghr.Status.CertificateArn = arn
+	ghr.Status.CertificateIssuer = CertificateIssuerACME
+	ghr.Status.CertificateNotAfter = &expiring
+	ghr.Status.Conditions = []metav1.Condition{
+		{Type: ConditionTypeCertificateIssued, Status: metav1.ConditionTrue, Reason: "Issued"},
+		{Type: ConditionTypeReady, Status: metav1.ConditionTrue, Reason: "Ready"},
+	}
+	acmMock := &MockACMClient{certificates: map[string]string{arn: "ISSUED"}}
+	r, issuer, _ := acmeTestReconciler(ghr, acmMock)
+
+	// The renewal runs alongside the Ready request
+	_, done, err := r.reconcileACMECertificate(ctx, ghr)
+	require.NoError(t, err)
+	assert.True(t, done)
+	require.NotEmpty(t, ghr.Status.CertificateOrderURL)
+	assert.Equal(t, challengePropagationDelay, r.acmeRequeueAfter(ghr))
+
+	_, done, err = r.reconcileACMECertificate(ctx, ghr)
+	require.NoError(t, err)
+	assert.True(t, done)
+	issuer.Validate(ghr.Status.CertificateOrderURL)
+
+	_, done, err = r.reconcileACMECertificate(ctx, ghr)
+	require.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, arn, ghr.Status.CertificateArn)
+	assert.Len(t, acmMock.certificates, 1)
+	assert.True(t, ghr.Status.CertificateNotAfter.After(expiring.Time))
+	assert.Empty(t, ghr.Status.CertificateOrderURL)
+	assert.True(t, meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeReady))
+}
+
+func TestACMERenewalDue_RenewBeforeAnnotation(t *testing.T) {
+	ghr := acmeGHR()
+	notAfter := metav1.NewTime(time.Now().Add(60 * 24 * time.Hour))
+	ghr.Status.CertificateArn = "arn:aws:acm:us-east-1:123456789012:certificate/imported"
+	ghr.Status.CertificateIssuer = CertificateIssuerACME
+	ghr.Status.CertificateNotAfter = &notAfter
+	r := &GatewayHostnameRequestReconciler{}
+	assert.False(t, r.acmeRenewalDue(ghr))
+
+	ghr.Annotations = map[string]string{AnnotationRenewCertificateBefore: time.Now().Add(90 * 24 * time.Hour).Format(time.RFC3339)}
+	assert.True(t, r.acmeRenewalDue(ghr))
+
+	renewed := metav1.NewTime(time.Now().Add(120 * 24 * time.Hour))
+	ghr.Status.CertificateNotAfter = &renewed
+	assert.False(t, r.acmeRenewalDue(ghr), "the renewed certificate expires after the annotation")
+}
+
+func TestReconcileACMECertificate_FailedOrderStartsOver(t *testing.T) {
+	ctx := context.Background()
+	ghr := acmeGHR()
+	r, issuer, route53Mock := acmeTestReconciler(ghr, &MockACMClient{certificates: make(map[string]string)})
+
+	_, _, err := r.reconcileACMECertificate(ctx, ghr)
+	require.NoError(t, err)
+	_, _, err = r.reconcileACMECertificate(ctx, ghr)
+	require.NoError(t, err)
+
+	issuer.Orders[ghr.Status.CertificateOrderURL].Failed = true
+	_, done, err := r.reconcileACMECertificate(ctx, ghr)
+	require.ErrorIs(t, err, acme.ErrOrderFailed)
+	assert.False(t, done)
+	assert.Empty(t, ghr.Status.CertificateOrderURL)
+	assert.Empty(t, route53Mock.records["Z123456"])
+
+	_, _, err = r.reconcileACMECertificate(ctx, ghr)
+	require.NoError(t, err)
+	assert.Len(t, issuer.Orders, 2)
+}
+
+func TestReconcileACMECertificate_NotConfigured(t *testing.T) {
+	ghr := acmeGHR()
+	r, _, _ := acmeTestReconciler(ghr, &MockACMClient{certificates: make(map[string]string)})
+	r.ACMEIssuer = nil
+
+	_, done, err := r.reconcileACMECertificate(context.Background(), ghr)
+	require.Error(t, err)
+	assert.False(t, done)
+	cond := meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeCertificateRequested)
+	require.NotNil(t, cond)
+	assert.Equal(t, "IssuerNotConfigured", cond.Reason)
+}
+
+// quotaACMClient rejects every certificate request with a quota error
+type quotaACMClient struct {
+	MockACMClient
+}
+
+func (m *quotaACMClient) RequestCertificate(ctx context.Context, hostname, idempotencyToken string, tags map[string]string) (string, error) {
+	return "", fmt.Errorf("%w: LimitExceededException", aws.ErrCertificateQuotaExceeded)
+}
+
+func TestReconcileNormal_FallsBackToACMEOnQuota(t *testing.T) {
+	ctx := context.Background()
+	ghr := acmeGHR()
+	ghr.Spec.CertificateIssuer = ""
+	r, issuer, _ := acmeTestReconciler(ghr, &quotaACMClient{MockACMClient{certificates: make(map[string]string)}})
+	r.ACMEFallback = true
+
+	result, err := r.reconcileNormal(ctx, ghr)
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	assert.Equal(t, CertificateIssuerACME, ghr.Status.CertificateIssuer)
+
+	// The next reconcile orders the certificate through ACME
+	_, err = r.reconcileNormal(ctx, ghr)
+	require.NoError(t, err)
+	assert.Len(t, issuer.Orders, 1)
+	assert.NotEmpty(t, ghr.Status.CertificateOrderURL)
+
+	// Without the fallback, the quota error fails the request
+	other := acmeGHR()
+	other.Spec.CertificateIssuer = ""
+	r.ACMEFallback = false
+	_, err = r.requestCertificate(ctx, other)
+	assert.ErrorIs(t, err, aws.ErrCertificateQuotaExceeded)
+}
@@ -0,0 +1,476 @@
+package controller
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// AdminPath is the prefix of the admin API's operations
+const AdminPath = "/v1/operations/"
+
+// Admin operations, the last element of their path
+const (
+	// OperationResyncGateway fully reconciles every request on a Gateway, like setting
+	// AnnotationReconcileNow on each of them
+	OperationResyncGateway = "resync-gateway"
+
+	// OperationRebalance moves the hostnames beyond the certificate limit off a Gateway, or off
+	// every Gateway over the limit, by setting AnnotationRebalance
+	OperationRebalance = "rebalance"
+
+	// OperationOrphanSweep deletes DomainClaims of deleted requests, Gateways without requests,
+	// the AWS resources recorded in Orphans, and duplicate certificates if the duplicate check
+	// deletes them
+	OperationOrphanSweep = "orphan-sweep"
+)
+
+// orphanGatewayGracePeriod protects Gateways created moments ago, whose request may not have
+// written its assignment to status yet
+const orphanGatewayGracePeriod = time.Hour
+
+// AdminResult is the response to an admin operation
+type AdminResult struct {
+	Operation string `json:"operation"`
+
+	// Touched are the objects the operation changed or deleted, as kind/namespace/name
+	Touched []string `json:"touched,omitempty"`
+
+	Message string `json:"message,omitempty"`
+
+	// Items are the outcomes per request of bulk operations
+	Items []AdminItem `json:"items,omitempty"`
+}
+
+// Admin serves authenticated operations that otherwise take editing annotations by hand or
+// restarting the controller: POST AdminPath + OperationResyncGateway?gateway=<namespace>/<name>,
+// OperationRebalance (gateway optional) and OperationOrphanSweep, and the bulk operations
+// OperationReissueExpiring (?within=<duration>), OperationDrainGateway?gateway=<namespace>/<name>
+// and OperationRevalidatePending, and the zone operations OperationEvacuateZone
+// (?zone=<AZ ID>&duration=<duration>) and OperationRestoreZone?zone=<AZ ID>. Bulk operations
+// run in the background: they answer 202 with an AdminJob, whose progress and outcome per
+// request GET AdminJobPath + <job ID> returns from the replica that started it.
+// Operations only write to the API server, apart from the zonal shifts, so it runs its own HTTP
+// server on BindAddress on every replica, and the leader acts on the changes.
+type Admin struct {
+	// Reconciler provides the client, the Gateway pool and the event recorder
+	Reconciler *GatewayHostnameRequestReconciler
+
+	// Duplicates, if set, is run by orphan sweeps
+	Duplicates *DuplicateCertificateChecker
+
+	// BindAddress is the address the API listens on, e.g. :8083
+	BindAddress string
+
+	// Token must be sent as a bearer token; the API refuses every call without it
+	Token string
+
+	// BulkPerMinute is how many requests bulk operations change per minute; 0 means
+	// DefaultBulkPerMinute
+	BulkPerMinute int
+
+	// ZonalShifts, if set, serves OperationEvacuateZone and OperationRestoreZone
+	ZonalShifts *ZonalShifts
+
+	// ctx is the context of Start, which bulk operations run in
+	ctx context.Context
+
+	jobs adminJobs
+}
+
+// Start serves the API until the context is cancelled
+func (a *Admin) Start(ctx context.Context) error {
+	if a.Token == "" {
+		return errors.New("admin API needs a token")
+	}
+	a.ctx = ctx
+	mux := http.NewServeMux()
+	mux.Handle(AdminPath, a)
+	mux.HandleFunc(AdminJobPath, a.serveJob)
+	server := &http.Server{Addr: a.BindAddress, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.FromContext(ctx).Info("Serving admin API", "address", a.BindAddress, "path", AdminPath)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("admin API server failed: %w", err)
+	}
+	return nil
+}
+
+// NeedLeaderElection lets every replica serve the API
+func (a *Admin) NeedLeaderElection() bool {
+	return false
+}
+
+// ServeHTTP runs the operation named by the path
+func (a *Admin) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !a.authorized(req) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := req.Context()
+	operation := strings.TrimPrefix(req.URL.Path, AdminPath)
+	gatewayRef := req.URL.Query().Get("gateway")
+	log.FromContext(ctx).Info("Admin operation requested", "operation", operation, "gateway", gatewayRef, "remote", req.RemoteAddr)
+
+	var result *AdminResult
+	var run func(context.Context) (*AdminResult, error)
+	var err error
+	switch operation {
+	case OperationResyncGateway:
+		if gatewayRef == "" {
+			http.Error(w, "gateway=<namespace>/<name> is required", http.StatusBadRequest)
+			return
+		}
+		result, err = a.ResyncGateway(ctx, gatewayRef)
+	case OperationRebalance:
+		result, err = a.Rebalance(ctx, gatewayRef)
+	case OperationOrphanSweep:
+		result, err = a.SweepOrphans(ctx)
+	case OperationReissueExpiring:
+		within := DefaultReissueWindow
+		if value := req.URL.Query().Get("within"); value != "" {
+			if within, err = time.ParseDuration(value); err != nil || within <= 0 {
+				http.Error(w, fmt.Sprintf("within must be a positive duration, got %q", value), http.StatusBadRequest)
+				return
+			}
+		}
+		run = func(ctx context.Context) (*AdminResult, error) { return a.ReissueExpiring(ctx, within) }
+	case OperationDrainGateway:
+		if gatewayRef == "" {
+			http.Error(w, "gateway=<namespace>/<name> is required", http.StatusBadRequest)
+			return
+		}
+		// Fail fast on a missing or foreign Gateway rather than in the job
+		gw, err := a.getGateway(ctx, gatewayRef)
+		if err == nil {
+			err = foreignOwnership("Gateway", gw)
+		}
+		if err != nil {
+			a.writeError(w, err)
+			return
+		}
+		run = func(ctx context.Context) (*AdminResult, error) { return a.DrainGateway(ctx, gatewayRef) }
+	case OperationRevalidatePending:
+		run = a.RevalidatePending
+	case OperationEvacuateZone, OperationRestoreZone:
+		if a.ZonalShifts == nil {
+			http.Error(w, "zonal shifts are not enabled (--zonal-shift-duration)", http.StatusNotFound)
+			return
+		}
+		zone := req.URL.Query().Get("zone")
+		if err := validateZone(zone); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if operation == OperationRestoreZone {
+			result, err = a.ZonalShifts.Restore(ctx, zone)
+			break
+		}
+		var duration time.Duration
+		if value := req.URL.Query().Get("duration"); value != "" {
+			if duration, err = time.ParseDuration(value); err != nil || duration < time.Minute || duration > maxZonalShiftDuration {
+				http.Error(w, fmt.Sprintf("duration must be between 1m and %s, got %q", maxZonalShiftDuration, value), http.StatusBadRequest)
+				return
+			}
+		}
+		result, err = a.ZonalShifts.Evacuate(ctx, zone, duration)
+	default:
+		http.Error(w, fmt.Sprintf("unknown operation %q", operation), http.StatusNotFound)
+		return
+	}
+	if run != nil {
+		job, err := a.startJob(req, operation, run)
+		if err != nil {
+			a.writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", AdminJobPath+job.ID)
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(job)
+		return
+	}
+	if err != nil {
+		a.writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// authorized reports whether the request carries the bearer token
+func (a *Admin) authorized(req *http.Request) bool {
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	return a.Token != "" && ok && subtle.ConstantTimeCompare([]byte(token), []byte(a.Token)) == 1
+}
+
+// writeError answers with the status that matches the error
+func (a *Admin) writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var foreign *ForeignOwnershipError
+	switch {
+	case apierrors.IsNotFound(err):
+		status = http.StatusNotFound
+	case errors.Is(err, errInvalidGatewayRef):
+		status = http.StatusBadRequest
+	case errors.As(err, &foreign):
+		status = http.StatusConflict
+	}
+	http.Error(w, err.Error(), status)
+}
+
+var errInvalidGatewayRef = errors.New("gateway must be <namespace>/<name>")
+
+// getGateway returns the Gateway of a <namespace>/<name> reference
+func (a *Admin) getGateway(ctx context.Context, ref string) (*gwapiv1.Gateway, error) {
+	namespace, name, ok := strings.Cut(ref, "/")
+	if !ok || namespace == "" || name == "" {
+		return nil, fmt.Errorf("%w, got %q", errInvalidGatewayRef, ref)
+	}
+	var gw gwapiv1.Gateway
+	if err := a.Reconciler.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &gw); err != nil {
+		return nil, err
+	}
+	return &gw, nil
+}
+
+// ResyncGateway sets AnnotationReconcileNow on every request assigned to, or moving off, the
+// Gateway
+func (a *Admin) ResyncGateway(ctx context.Context, gatewayRef string) (*AdminResult, error) {
+	gw, err := a.getGateway(ctx, gatewayRef)
+	if err != nil {
+		return nil, err
+	}
+	result := &AdminResult{Operation: OperationResyncGateway}
+	touched, err := a.reconcileRequestsOn(ctx, gw)
+	result.Touched = touched
+	if err != nil {
+		return nil, err
+	}
+	a.Reconciler.Recorder.Eventf(gw, corev1.EventTypeNormal, "ResyncRequested", "Admin API requested a full reconcile of %d requests", len(touched))
+	return result, nil
+}
+
+// Rebalance sets AnnotationRebalance on the Gateway, or on every pool Gateway over the
+// certificate limit if gatewayRef is empty, and reconciles their requests so excess
+// hostnames move even without --rebalance-over-capacity
+func (a *Admin) Rebalance(ctx context.Context, gatewayRef string) (*AdminResult, error) {
+	var gateways []gwapiv1.Gateway
+	if gatewayRef != "" {
+		gw, err := a.getGateway(ctx, gatewayRef)
+		if err != nil {
+			return nil, err
+		}
+		gateways = append(gateways, *gw)
+	} else {
+		var err error
+		if gateways, err = a.Reconciler.GatewayPool.ListGateways(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &AdminResult{Operation: OperationRebalance}
+	limit := a.Reconciler.maxCertificates()
+	for i := range gateways {
+		gw := &gateways[i]
+		count, _ := strconv.Atoi(gw.Annotations[AnnotationCertificateCount])
+		if count <= limit {
+			continue
+		}
+		if gw.Annotations[AnnotationRebalance] == "" {
+			if err := foreignOwnership("Gateway", gw); err != nil {
+				return nil, err
+			}
+			if gw.Annotations == nil {
+				gw.Annotations = map[string]string{}
+			}
+			gw.Annotations[AnnotationRebalance] = time.Now().UTC().Format(time.RFC3339)
+			if err := a.Reconciler.Update(ctx, gw); err != nil {
+				return nil, fmt.Errorf("failed to annotate Gateway %s: %w", gw.Name, err)
+			}
+			result.Touched = append(result.Touched, "Gateway/"+gw.Namespace+"/"+gw.Name)
+		}
+		touched, err := a.reconcileRequestsOn(ctx, gw)
+		result.Touched = append(result.Touched, touched...)
+		if err != nil {
+			return nil, err
+		}
+		a.Reconciler.Recorder.Eventf(gw, corev1.EventTypeNormal, "RebalanceRequested",
+			"Admin API requested moving the hostnames beyond the limit of %d certificates", limit)
+	}
+	if len(result.Touched) == 0 {
+		result.Message = fmt.Sprintf("No Gateway holds more than %d certificates", limit)
+	}
+	return result, nil
+}
+
+// reconcileRequestsOn sets AnnotationReconcileNow on the requests assigned to, or moving off,
+// the Gateway. Returns the requests changed.
+func (a *Admin) reconcileRequestsOn(ctx context.Context, gw *gwapiv1.Gateway) ([]string, error) {
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := a.Reconciler.List(ctx, &ghrList); err != nil {
+		return nil, fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+
+	value := time.Now().UTC().Format(time.RFC3339Nano)
+	var touched []string
+	for i := range ghrList.Items {
+		ghr := &ghrList.Items[i]
+		assigned := ghr.Status.AssignedGateway == gw.Name && ghr.Status.AssignedGatewayNamespace == gw.Namespace
+		moving := ghr.Status.MigratingFromGateway == gw.Name && migratingFromNamespace(ghr) == gw.Namespace
+		if !assigned && !moving {
+			continue
+		}
+		patch := client.MergeFrom(ghr.DeepCopy())
+		if ghr.Annotations == nil {
+			ghr.Annotations = map[string]string{}
+		}
+		ghr.Annotations[AnnotationReconcileNow] = value
+		if err := a.Reconciler.Patch(ctx, ghr, patch); client.IgnoreNotFound(err) != nil {
+			return touched, fmt.Errorf("failed to annotate %s/%s: %w", ghr.Namespace, ghr.Name, err)
+		}
+		touched = append(touched, "GatewayHostnameRequest/"+ghr.Namespace+"/"+ghr.Name)
+	}
+	return touched, nil
+}
+
+// SweepOrphans deletes DomainClaims whose request no longer exists and pool Gateways that no
+// request uses, cleans up the Orphans of force-deleted requests, and runs the duplicate
+// certificate check if configured. Gateways younger than orphanGatewayGracePeriod are kept.
+func (a *Admin) SweepOrphans(ctx context.Context) (*AdminResult, error) {
+	r := a.Reconciler
+	result := &AdminResult{Operation: OperationOrphanSweep}
+
+	store := r.claimStore()
+	claims, err := store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range claims {
+		claim := &claims[i]
+		if claim.Spec.Shared {
+			swept, err := r.sweepSharedClaim(ctx, store, claim)
+			if err != nil {
+				return nil, err
+			}
+			if swept {
+				result.Touched = append(result.Touched, "DomainClaim/"+claim.Name)
+			}
+			continue
+		}
+		// Claims of other clusters sharing the store have owners this one can't see
+		if claim.Spec.Scope == gatewayv1alpha1.DomainClaimScopeSubtree || !store.Local(claim) {
+			continue
+		}
+		// Read the owner from the API server: a claim must not be dropped for a stale cache
+		var owner gatewayv1alpha1.GatewayHostnameRequest
+		key := types.NamespacedName{Namespace: claim.Spec.OwnerRef.Namespace, Name: claim.Spec.OwnerRef.Name}
+		err := r.apiReader().Get(ctx, key, &owner)
+		if err == nil && string(owner.UID) == claim.Spec.OwnerRef.UID {
+			continue
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get owner of DomainClaim %s: %w", claim.Name, err)
+		}
+		if err := store.Delete(ctx, claim); err != nil {
+			return nil, fmt.Errorf("failed to delete DomainClaim %s: %w", claim.Name, err)
+		}
+		result.Touched = append(result.Touched, "DomainClaim/"+claim.Name)
+	}
+
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList); err != nil {
+		return nil, fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+	used := map[types.NamespacedName]bool{}
+	for _, ghr := range ghrList.Items {
+		used[types.NamespacedName{Namespace: ghr.Status.AssignedGatewayNamespace, Name: ghr.Status.AssignedGateway}] = true
+		if ghr.Status.MigratingFromGateway != "" {
+			used[types.NamespacedName{Namespace: migratingFromNamespace(&ghr), Name: ghr.Status.MigratingFromGateway}] = true
+		}
+	}
+	gateways, err := r.GatewayPool.ListGateways(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range gateways {
+		gw := &gateways[i]
+		if used[client.ObjectKeyFromObject(gw)] || time.Since(gw.CreationTimestamp.Time) < orphanGatewayGracePeriod {
+			continue
+		}
+		// cleanupEmptyGateway counts again and keeps the Gateway if a request was assigned meanwhile
+		if err := r.cleanupEmptyGateway(ctx, gw.Name, gw.Namespace, "", ""); err != nil {
+			return nil, err
+		}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(gw), &gwapiv1.Gateway{}); apierrors.IsNotFound(err) {
+			result.Touched = append(result.Touched, "Gateway/"+gw.Namespace+"/"+gw.Name)
+		}
+	}
+
+	var orphans gatewayv1alpha1.OrphanList
+	if err := r.List(ctx, &orphans); err != nil {
+		return nil, fmt.Errorf("failed to list Orphans: %w", err)
+	}
+	var messages []string
+	failed := 0
+	for i := range orphans.Items {
+		orphan := &orphans.Items[i]
+		if err := r.cleanupOrphan(ctx, orphan); err != nil {
+			// Recorded on the Orphan; the next sweep tries again
+			log.FromContext(ctx).Error(err, "Failed to clean up Orphan", "orphan", orphan.Name, "hostname", orphan.Spec.Hostname)
+			orphan.Status.Attempts++
+			orphan.Status.LastAttemptTime = &metav1.Time{Time: time.Now()}
+			orphan.Status.LastError = err.Error()
+			if err := r.Status().Update(ctx, orphan); err != nil {
+				return nil, fmt.Errorf("failed to update Orphan %s: %w", orphan.Name, err)
+			}
+			failed++
+			continue
+		}
+		if err := r.Delete(ctx, orphan); client.IgnoreNotFound(err) != nil {
+			return nil, fmt.Errorf("failed to delete Orphan %s: %w", orphan.Name, err)
+		}
+		result.Touched = append(result.Touched, "Orphan/"+orphan.Name)
+	}
+	if failed > 0 {
+		messages = append(messages, fmt.Sprintf("%d Orphans could not be cleaned up, see their status", failed))
+	}
+
+	if a.Duplicates != nil {
+		if err := a.Duplicates.CheckAll(ctx); err != nil {
+			return nil, err
+		}
+		messages = append(messages, "Duplicate certificate check ran, see its events")
+	}
+	result.Message = strings.Join(messages, "; ")
+	return result, nil
+}
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+func adminGateway(name string, certificates string, age time.Duration) *gwapiv1.Gateway {
+	return &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "edge",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			Annotations:       map[string]string{AnnotationCertificateCount: certificates},
+		},
+		Spec: gwapiv1.GatewaySpec{GatewayClassName: "aws-alb"},
+	}
+}
+
+func newTestAdmin(objs ...client.Object) *Admin {
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(objs...).Build()
+	pool := gateway.NewPool(c, "edge", "aws-alb", 0, 0)
+	pool.SetMaxCertificates(2)
+	return &Admin{
+		Reconciler: &GatewayHostnameRequestReconciler{
+			Client:      c,
+			Scheme:      getTestScheme(),
+			Recorder:    record.NewFakeRecorder(10),
+			GatewayPool: pool,
+		},
+		Token: "secret",
+	}
+}
+
+func callAdmin(a *Admin, method, target string) (*httptest.ResponseRecorder, AdminResult) {
+	req := httptest.NewRequest(method, target, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, req)
+	var result AdminResult
+	_ = json.Unmarshal(rec.Body.Bytes(), &result)
+	return rec, result
+}
+
+func TestAdmin_RequiresTokenAndPost(t *testing.T) {
+	a := newTestAdmin()
+
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, AdminPath+OperationOrphanSweep, nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec, _ = callAdmin(a, http.MethodGet, AdminPath+OperationOrphanSweep)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec, _ = callAdmin(a, http.MethodPost, AdminPath+"unknown")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec, _ = callAdmin(a, http.MethodPost, AdminPath+OperationResyncGateway)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec, _ = callAdmin(a, http.MethodPost, AdminPath+OperationResyncGateway+"?gateway=edge/missing")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestAdmin_ResyncGateway(t *testing.T) {
+	a := newTestAdmin(adminGateway("gw-01", "2", time.Hour), ghrOnGateway(1, "gw-01"), ghrOnGateway(2, "gw-02"))
+
+	rec, result := callAdmin(a, http.MethodPost, AdminPath+OperationResyncGateway+"?gateway=edge/gw-01")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, []string{"GatewayHostnameRequest/default/ghr-1"}, result.Touched)
+
+	var ghr gatewayv1alpha1.GatewayHostnameRequest
+	require.NoError(t, a.Reconciler.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "ghr-1"}, &ghr))
+	assert.NotEmpty(t, ghr.Annotations[AnnotationReconcileNow])
+	require.NoError(t, a.Reconciler.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "ghr-2"}, &ghr))
+	assert.Empty(t, ghr.Annotations[AnnotationReconcileNow])
+}
+
+func TestAdmin_RebalanceOnlyGatewaysOverLimit(t *testing.T) {
+	a := newTestAdmin(adminGateway("gw-01", "3", time.Hour), adminGateway("gw-02", "1", time.Hour),
+		ghrOnGateway(1, "gw-01"), ghrOnGateway(2, "gw-02"))
+
+	rec, result := callAdmin(a, http.MethodPost, AdminPath+OperationRebalance)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, []string{"Gateway/edge/gw-01", "GatewayHostnameRequest/default/ghr-1"}, result.Touched)
+
+	var gw gwapiv1.Gateway
+	require.NoError(t, a.Reconciler.Get(context.Background(), types.NamespacedName{Namespace: "edge", Name: "gw-01"}, &gw))
+	assert.NotEmpty(t, gw.Annotations[AnnotationRebalance])
+	require.NoError(t, a.Reconciler.Get(context.Background(), types.NamespacedName{Namespace: "edge", Name: "gw-02"}, &gw))
+	assert.Empty(t, gw.Annotations[AnnotationRebalance])
+
+	rec, result = callAdmin(a, http.MethodPost, AdminPath+OperationRebalance+"?gateway=edge/gw-02")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, result.Touched)
+	assert.Contains(t, result.Message, "No Gateway holds more than 2 certificates")
+}
+
+func TestAdmin_SweepOrphans(t *testing.T) {
+	owner := ghrOnGateway(1, "gw-used")
+	owner.UID = "uid-1"
+	claim := func(name, ownerName, uid, scope string) *gatewayv1alpha1.DomainClaim {
+		return &gatewayv1alpha1.DomainClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: gatewayv1alpha1.DomainClaimSpec{
+				ZoneId: "Z123456", Hostname: name + ".opendi.com", Scope: scope,
+				OwnerRef: gatewayv1alpha1.DomainClaimOwnerRef{Namespace: "default", Name: ownerName, UID: uid},
+			},
+		}
+	}
+	a := newTestAdmin(owner,
+		claim("owned", "ghr-1", "uid-1", ""),
+		claim("deleted-owner", "ghr-9", "uid-9", ""),
+		claim("recreated-owner", "ghr-1", "uid-old", ""),
+		claim("subtree", "", "", gatewayv1alpha1.DomainClaimScopeSubtree),
+		adminGateway("gw-used", "1", 2*time.Hour),
+		adminGateway("gw-orphan", "0", 2*time.Hour),
+		adminGateway("gw-new", "0", time.Minute),
+	)
+
+	rec, result := callAdmin(a, http.MethodPost, AdminPath+OperationOrphanSweep)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.ElementsMatch(t, []string{"DomainClaim/deleted-owner", "DomainClaim/recreated-owner", "Gateway/edge/gw-orphan"}, result.Touched)
+
+	ctx := context.Background()
+	for name, kept := range map[string]bool{"owned": true, "subtree": true, "deleted-owner": false} {
+		err := a.Reconciler.Get(ctx, types.NamespacedName{Name: name}, &gatewayv1alpha1.DomainClaim{})
+		assert.Equal(t, kept, err == nil, name)
+	}
+	for name, kept := range map[string]bool{"gw-used": true, "gw-new": true, "gw-orphan": false} {
+		err := a.Reconciler.Get(ctx, types.NamespacedName{Namespace: "edge", Name: name}, &gwapiv1.Gateway{})
+		assert.Equal(t, kept, err == nil, name)
+		if !kept {
+			assert.True(t, apierrors.IsNotFound(err), name)
+		}
+	}
+}
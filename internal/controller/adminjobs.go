@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// AdminJobPath is the prefix of the status of bulk operations, GET AdminJobPath + <job ID>
+const AdminJobPath = "/v1/jobs/"
+
+// States of an admin job
+const (
+	AdminJobRunning   = "Running"
+	AdminJobSucceeded = "Succeeded"
+	AdminJobFailed    = "Failed"
+)
+
+// maxAdminJobs is how many jobs the API remembers; the oldest finished ones are forgotten first
+const maxAdminJobs = 100
+
+// AdminJob is a bulk operation running in the background. Its result fills in as requests are
+// changed, so the status shows the progress.
+type AdminJob struct {
+	ID        string `json:"id"`
+	Operation string `json:"operation"`
+
+	// State is AdminJobRunning, AdminJobSucceeded or AdminJobFailed
+	State string `json:"state"`
+
+	StartedAt  time.Time  `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+
+	// Error is why the job failed, e.g. because the controller shut down
+	Error string `json:"error,omitempty"`
+
+	Result *AdminResult `json:"result,omitempty"`
+}
+
+// adminJobs holds the jobs of one replica in memory; the zero value is ready to use
+type adminJobs struct {
+	mu   sync.Mutex
+	jobs map[string]*AdminJob
+
+	// order holds the job IDs, oldest first
+	order []string
+}
+
+// adminJobKey carries the progress callback of a job in the context of its operation
+type adminJobKey struct{}
+
+// reportProgress publishes the result so far to the job running the operation, if any
+func reportProgress(ctx context.Context, result *AdminResult) {
+	if report, ok := ctx.Value(adminJobKey{}).(func(*AdminResult)); ok {
+		report(result)
+	}
+}
+
+// copyResult returns a copy of the result that later appends to it don't change
+func copyResult(result *AdminResult) *AdminResult {
+	if result == nil {
+		return nil
+	}
+	c := *result
+	c.Touched = slices.Clone(result.Touched)
+	c.Items = slices.Clone(result.Items)
+	return &c
+}
+
+// add stores a new job, forgetting the oldest finished jobs beyond maxAdminJobs
+func (j *adminJobs) add(job *AdminJob) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.jobs == nil {
+		j.jobs = map[string]*AdminJob{}
+	}
+	j.jobs[job.ID] = job
+	j.order = append(j.order, job.ID)
+	for i := 0; len(j.jobs) > maxAdminJobs && i < len(j.order); {
+		if j.jobs[j.order[i]].State == AdminJobRunning {
+			i++
+			continue
+		}
+		delete(j.jobs, j.order[i])
+		j.order = slices.Delete(j.order, i, i+1)
+	}
+}
+
+// update changes a job under the lock
+func (j *adminJobs) update(id string, change func(*AdminJob)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if job, ok := j.jobs[id]; ok {
+		change(job)
+	}
+}
+
+// get returns a copy of a job
+func (j *adminJobs) get(id string) (AdminJob, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	job, ok := j.jobs[id]
+	if !ok {
+		return AdminJob{}, false
+	}
+	c := *job
+	c.Result = copyResult(job.Result)
+	return c, true
+}
+
+// newAdminJobID returns a random job ID
+func newAdminJobID() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// startJob runs the operation in the background and returns its job. The job outlives the
+// request that started it; it stops when the API shuts down.
+func (a *Admin) startJob(req *http.Request, operation string, run func(context.Context) (*AdminResult, error)) (AdminJob, error) {
+	id, err := newAdminJobID()
+	if err != nil {
+		return AdminJob{}, err
+	}
+	job := &AdminJob{ID: id, Operation: operation, State: AdminJobRunning, StartedAt: time.Now().UTC(),
+		Result: &AdminResult{Operation: operation}}
+	a.jobs.add(job)
+	started, _ := a.jobs.get(id)
+
+	ctx := a.ctx
+	if ctx == nil {
+		ctx = context.WithoutCancel(req.Context())
+	}
+	logger := log.FromContext(req.Context()).WithValues("job", id)
+	ctx = log.IntoContext(ctx, logger)
+	ctx = context.WithValue(ctx, adminJobKey{}, func(result *AdminResult) {
+		snapshot := copyResult(result)
+		a.jobs.update(id, func(job *AdminJob) { job.Result = snapshot })
+	})
+
+	go func() {
+		result, err := run(ctx)
+		finished := time.Now().UTC()
+		a.jobs.update(id, func(job *AdminJob) {
+			job.FinishedAt = &finished
+			if err != nil {
+				job.State = AdminJobFailed
+				job.Error = err.Error()
+				return
+			}
+			job.State = AdminJobSucceeded
+			job.Result = copyResult(result)
+		})
+		if err != nil {
+			logger.Error(err, "Admin job failed", "operation", operation)
+			return
+		}
+		logger.Info("Admin job finished", "operation", operation, "message", result.Message)
+	}()
+	return started, nil
+}
+
+// serveJob returns the status of a job started on this replica
+func (a *Admin) serveJob(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !a.authorized(req) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	job, ok := a.jobs.get(strings.TrimPrefix(req.URL.Path, AdminJobPath))
+	if !ok {
+		http.Error(w, "unknown job; jobs are kept in memory by the replica that started them", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(job)
+}
@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+// MaxAWSChanges bounds status.awsChanges; the oldest entries are dropped first.
+// Must not exceed the MaxItems validation on the CRD field.
+const MaxAWSChanges = 10
+
+// recordAWSChanges reports the mutating Route53 and ACM calls of a reconcile with their AWS
+// request and Route53 change IDs, so they can be matched with CloudTrail entries: as an
+// AWSChange event each, and in status.awsChanges unless the request is being deleted.
+func (r *GatewayHostnameRequestReconciler) recordAWSChanges(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, calls *aws.CallLog) {
+	made := calls.Calls()
+	if len(made) == 0 {
+		return
+	}
+	now := metav1.Now()
+	for _, call := range made {
+		change := gatewayv1alpha1.AWSChange{
+			Time:      now,
+			Operation: call.Operation,
+			Resource:  call.Resource,
+			RequestId: call.RequestID,
+			ChangeId:  call.ChangeID,
+		}
+		if call.Err != nil {
+			change.Error = call.Err.Error()
+			r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "AWSChange", "%s failed: %s", describeAWSChange(change), change.Error)
+		} else {
+			r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "AWSChange", "%s", describeAWSChange(change))
+		}
+		ghr.Status.AWSChanges = append(ghr.Status.AWSChanges, change)
+	}
+	if overflow := len(ghr.Status.AWSChanges) - MaxAWSChanges; overflow > 0 {
+		ghr.Status.AWSChanges = append([]gatewayv1alpha1.AWSChange(nil), ghr.Status.AWSChanges[overflow:]...)
+	}
+
+	if !ghr.DeletionTimestamp.IsZero() {
+		return
+	}
+	if err := r.Status().Update(ctx, ghr); err != nil {
+		log.FromContext(ctx).Info("Failed to record AWS changes", "error", err.Error())
+	}
+}
+
+// describeAWSChange formats a change for events, e.g.
+// "route53:ChangeResourceRecordSets Z123: app.example.com A (change /change/C1, request 5f2c...)"
+func describeAWSChange(change gatewayv1alpha1.AWSChange) string {
+	ids := "request " + change.RequestId
+	if change.RequestId == "" {
+		ids = "no request ID"
+	}
+	if change.ChangeId != "" {
+		ids = "change " + change.ChangeId + ", " + ids
+	}
+	return fmt.Sprintf("%s %s (%s)", change.Operation, change.Resource, ids)
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+func TestRecordAWSChanges(t *testing.T) {
+	ctx := context.Background()
+	ghr := assignedGHR("app", "app.example.com")
+	for range MaxAWSChanges {
+		ghr.Status.AWSChanges = append(ghr.Status.AWSChanges, gatewayv1alpha1.AWSChange{Operation: "acm:RequestCertificate"})
+	}
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr).WithStatusSubresource(ghr).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &GatewayHostnameRequestReconciler{Client: c, Recorder: recorder}
+
+	calls := &aws.CallLog{}
+	r.recordAWSChanges(ctx, ghr, calls)
+	assert.Empty(t, recorder.Events, "nothing to report")
+
+	calls.Record(aws.Call{
+		Operation: "route53:ChangeResourceRecordSets", Resource: "Z123: app.example.com A",
+		RequestID: "req-1", ChangeID: "/change/C1",
+	})
+	calls.Record(aws.Call{Operation: "acm:DeleteCertificate", Resource: "arn:aws:acm:eu-west-1:123456789012:certificate/abc", Err: errors.New("in use")})
+	r.recordAWSChanges(ctx, ghr, calls)
+
+	assert.Equal(t, "Normal AWSChange route53:ChangeResourceRecordSets Z123: app.example.com A (change /change/C1, request req-1)", <-recorder.Events)
+	assert.Equal(t, "Warning AWSChange acm:DeleteCertificate arn:aws:acm:eu-west-1:123456789012:certificate/abc (no request ID) failed: in use", <-recorder.Events)
+
+	var stored gatewayv1alpha1.GatewayHostnameRequest
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(ghr), &stored))
+	require.Len(t, stored.Status.AWSChanges, MaxAWSChanges, "the oldest entries are dropped")
+	last := stored.Status.AWSChanges[MaxAWSChanges-2:]
+	assert.Equal(t, "/change/C1", last[0].ChangeId)
+	assert.Equal(t, "req-1", last[0].RequestId)
+	assert.Equal(t, "in use", last[1].Error)
+}
@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/notify"
+)
+
+// AnnotationBootstrap marks a Gateway the pool keeps for an entry of the OrchestratorConfig's
+// spec.pool.gateways, with the entry's name. Such Gateways are not deleted when they are empty.
+const AnnotationBootstrap = "gateway.opendi.com/bootstrap"
+
+// BootstrapGateway is a Gateway the pool keeps whether or not hostnames use it
+type BootstrapGateway struct {
+	Name       string
+	Visibility string
+	WafArn     string
+	Labels     map[string]string
+
+	// HTTPSOnly overrides the pool's HTTPS-only setting if set
+	HTTPSOnly *bool
+}
+
+// bootstrapGateway validates an entry of spec.pool.gateways
+func bootstrapGateway(entry gatewayv1alpha1.OrchestratorConfigGateway) (BootstrapGateway, error) {
+	bg := BootstrapGateway{Name: entry.Name, Visibility: entry.Visibility, WafArn: entry.WafArn, Labels: entry.Labels, HTTPSOnly: entry.HTTPSOnly}
+	if errs := validation.IsDNS1123Label(bg.Name); len(errs) > 0 {
+		return BootstrapGateway{}, fmt.Errorf("invalid name: %s", strings.Join(errs, "; "))
+	}
+	switch bg.Visibility {
+	case "":
+		bg.Visibility = "internet-facing"
+	case "internet-facing", "internal":
+	default:
+		return BootstrapGateway{}, fmt.Errorf("invalid visibility %q, must be internet-facing or internal", bg.Visibility)
+	}
+	if bg.WafArn != "" && !strings.Contains(bg.WafArn, ":regional/webacl/") {
+		return BootstrapGateway{}, fmt.Errorf("wafArn: %q is not a regional WebACL", bg.WafArn)
+	}
+	for key, value := range bg.Labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return BootstrapGateway{}, fmt.Errorf("invalid label key %q: %s", key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return BootstrapGateway{}, fmt.Errorf("invalid value of label %q: %s", key, strings.Join(errs, "; "))
+		}
+	}
+	return bg, nil
+}
+
+// isBootstrapGateway reports whether the pool keeps the Gateway for spec.pool.gateways
+func isBootstrapGateway(gw *gwapiv1.Gateway) bool {
+	return gw.Annotations[AnnotationBootstrap] != ""
+}
+
+// bootstrapPool converges the pool to the bootstrap Gateways: it creates the missing ones with
+// their LoadBalancerConfigurations, adds their labels, and releases Gateways whose entry is
+// gone into the pool, which deletes them once they are empty. Visibility and WAF of an
+// existing Gateway are left alone, as its ALB would have to be replaced; labels removed from an
+// entry stay on the Gateway.
+func (r *GatewayHostnameRequestReconciler) bootstrapPool(ctx context.Context, gateways []BootstrapGateway) error {
+	logger := log.FromContext(ctx)
+
+	existing, err := r.GatewayPool.ListGateways(ctx)
+	if err != nil {
+		return err
+	}
+	byEntry := map[string]*gwapiv1.Gateway{}
+	for i := range existing {
+		gw := &existing[i]
+		if isBootstrapGateway(gw) && gw.DeletionTimestamp.IsZero() {
+			byEntry[gw.Annotations[AnnotationBootstrap]] = gw
+		}
+	}
+
+	wanted := map[string]bool{}
+	for _, bg := range gateways {
+		wanted[bg.Name] = true
+		gw, ok := byEntry[bg.Name]
+		if !ok {
+			if err := r.createBootstrapGateway(ctx, bg); err != nil {
+				return err
+			}
+			continue
+		}
+		if manager := foreignManager(gw); manager != "" {
+			logger.Info("Leaving bootstrap Gateway to its manager", "gateway", gw.Namespace+"/"+gw.Name, "entry", bg.Name, "manager", manager)
+			continue
+		}
+
+		if gw.Annotations[AnnotationVisibility] != bg.Visibility || gw.Annotations["gateway.opendi.com/waf-arn"] != bg.WafArn {
+			logger.Info("Bootstrap Gateway differs from its entry, visibility and WAF are kept until it is recreated",
+				"gateway", gw.Namespace+"/"+gw.Name, "entry", bg.Name, "visibility", gw.Annotations[AnnotationVisibility])
+		}
+		changed := false
+		for key, value := range bg.Labels {
+			if gw.Labels[key] != value {
+				if gw.Labels == nil {
+					gw.Labels = make(map[string]string)
+				}
+				gw.Labels[key] = value
+				changed = true
+			}
+		}
+		if changed {
+			if err := r.Update(ctx, gw); err != nil {
+				return fmt.Errorf("failed to update labels of Gateway %s: %w", gw.Name, err)
+			}
+		}
+	}
+
+	released := make([]string, 0, len(byEntry))
+	for name := range byEntry {
+		if !wanted[name] {
+			released = append(released, name)
+		}
+	}
+	sort.Strings(released)
+	for _, name := range released {
+		gw := byEntry[name]
+		if manager := foreignManager(gw); manager != "" {
+			logger.Info("Leaving Gateway dropped from spec.pool.gateways to its manager", "gateway", gw.Namespace+"/"+gw.Name, "entry", name, "manager", manager)
+			continue
+		}
+		delete(gw.Annotations, AnnotationBootstrap)
+		if err := r.Update(ctx, gw); err != nil {
+			return fmt.Errorf("failed to release Gateway %s: %w", gw.Name, err)
+		}
+		logger.Info("Released Gateway dropped from spec.pool.gateways into the pool", "gateway", gw.Namespace+"/"+gw.Name, "entry", name)
+	}
+	return nil
+}
+
+// createBootstrapGateway creates the Gateway of an entry, like ensureGatewayAssignment does
+// for a request that finds no Gateway with capacity, but without certificates
+func (r *GatewayHostnameRequestReconciler) createBootstrapGateway(ctx context.Context, bg BootstrapGateway) error {
+	logger := log.FromContext(ctx)
+
+	index, err := r.GatewayPool.GetNextGatewayIndex(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get next gateway index: %w", err)
+	}
+	gatewayName, err := r.naming().GatewayName(index)
+	if err != nil {
+		return err
+	}
+	gatewayNamespace := r.GatewayPool.NamespaceFor(bg.Visibility)
+	httpsOnly := r.GatewayPool.HTTPSOnly()
+	if bg.HTTPSOnly != nil {
+		httpsOnly = *bg.HTTPSOnly
+	}
+	if err := r.writeLoadBalancerConfiguration(ctx, gatewayName, gatewayNamespace, nil, bg.Visibility, bg.WafArn, httpsOnly); err != nil {
+		return fmt.Errorf("failed to create LoadBalancerConfiguration of %s: %w", bg.Name, err)
+	}
+	gwInfo, err := r.GatewayPool.CreateGatewayWithMetadata(ctx, bg.Visibility, bg.WafArn, index, bg.Labels,
+		map[string]string{AnnotationBootstrap: bg.Name, gateway.AnnotationHTTPSOnly: strconv.FormatBool(httpsOnly)})
+	if err != nil {
+		return fmt.Errorf("failed to create Gateway of %s: %w", bg.Name, err)
+	}
+	logger.Info("Created bootstrap Gateway", "name", gwInfo.Name, "namespace", gwInfo.Namespace, "entry", bg.Name, "visibility", bg.Visibility)
+	r.notifyGateway(notify.EventGatewayCreated, gwInfo.Name, gwInfo.Namespace, fmt.Sprintf("Created %s Gateway for spec.pool.gateways entry %s", bg.Visibility, bg.Name))
+	return nil
+}
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+func TestOrchestratorConfigReconciler_BootstrapsPool(t *testing.T) {
+	ctx := context.Background()
+	waf := "arn:aws:wafv2:eu-west-1:123456789012:regional/webacl/edge/1"
+	config := &gatewayv1alpha1.OrchestratorConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Generation: 1},
+		Spec: gatewayv1alpha1.OrchestratorConfigSpec{
+			Pool: gatewayv1alpha1.OrchestratorConfigPool{
+				Gateways: []gatewayv1alpha1.OrchestratorConfigGateway{
+					{Name: "public"},
+					{Name: "internal-waf", Visibility: "internal", WafArn: waf, Labels: map[string]string{"tier": "premium"}},
+				},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).
+		WithObjects(config).WithStatusSubresource(config).Build()
+	pool := gateway.NewPool(c, "edge", "aws-alb", 0, 0)
+	pool.SetNamespaceByVisibility(map[string]string{"internal": "edge-internal"})
+	r := &OrchestratorConfigReconciler{
+		Client: c, Recorder: record.NewFakeRecorder(10), GatewayPool: pool,
+		Settings: NewSettingsStore(Settings{}),
+		Requests: &GatewayHostnameRequestReconciler{Client: c, GatewayPool: pool},
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "default"}}
+
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	var gw gwapiv1.Gateway
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "edge", Name: "gw-01"}, &gw))
+	assert.Equal(t, "public", gw.Annotations[AnnotationBootstrap])
+	assert.Equal(t, "internet-facing", gw.Annotations[AnnotationVisibility])
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "edge-internal", Name: "gw-02"}, &gw))
+	assert.Equal(t, "internal-waf", gw.Annotations[AnnotationBootstrap])
+	assert.Equal(t, waf, gw.Annotations["gateway.opendi.com/waf-arn"])
+	assert.Equal(t, "premium", gw.Labels["tier"])
+	lbcName, err := r.Requests.loadBalancerConfigurationName("gw-02")
+	require.NoError(t, err)
+	_, err = r.Requests.getLoadBalancerConfiguration(ctx, types.NamespacedName{Namespace: "edge-internal", Name: lbcName})
+	require.NoError(t, err)
+
+	// Converging again creates nothing new
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	gateways, err := pool.ListGateways(ctx)
+	require.NoError(t, err)
+	assert.Len(t, gateways, 2)
+
+	// Empty bootstrap Gateways are kept
+	require.NoError(t, r.Requests.cleanupEmptyGateway(ctx, "gw-01", "edge", "", ""))
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "edge", Name: "gw-01"}, &gw))
+
+	// Dropped entries rejoin the pool and go away once empty
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(config), config))
+	config.Spec.Pool.Gateways = config.Spec.Pool.Gateways[1:]
+	require.NoError(t, c.Update(ctx, config))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "edge", Name: "gw-01"}, &gw))
+	assert.NotContains(t, gw.Annotations, AnnotationBootstrap)
+	require.NoError(t, r.Requests.cleanupEmptyGateway(ctx, "gw-01", "edge", "", ""))
+	gateways, err = pool.ListGateways(ctx)
+	require.NoError(t, err)
+	assert.Len(t, gateways, 1)
+
+	// Gateways another manager claimed are left to it
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "edge-internal", Name: "gw-02"}, &gw))
+	gw.Annotations[AnnotationManagedBy] = "platform-operator"
+	require.NoError(t, c.Update(ctx, &gw))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(config), config))
+	config.Spec.Pool.Gateways = nil
+	require.NoError(t, c.Update(ctx, config))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "edge-internal", Name: "gw-02"}, &gw))
+	assert.Equal(t, "internal-waf", gw.Annotations[AnnotationBootstrap])
+}
+
+func TestMergeSettings_Gateways(t *testing.T) {
+	for _, entries := range [][]gatewayv1alpha1.OrchestratorConfigGateway{
+		{{Name: "a"}, {Name: "a"}},
+		{{Name: "a", WafArn: "arn:aws:wafv2:us-east-1:123456789012:global/webacl/x/1"}},
+		{{Name: "a", Labels: map[string]string{"tier": "not valid"}}},
+	} {
+		_, err := mergeSettings(Settings{}, &gatewayv1alpha1.OrchestratorConfigSpec{
+			Pool: gatewayv1alpha1.OrchestratorConfigPool{Gateways: entries},
+		})
+		assert.Error(t, err, entries)
+	}
+}
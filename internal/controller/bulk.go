@@ -0,0 +1,249 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+// Bulk admin operations, which act on many requests one by one at Admin.BulkPerMinute
+const (
+	// OperationReissueExpiring renews the certificates expiring within ?within= (default 30
+	// days): ACME certificates are renewed right away through AnnotationRenewCertificateBefore,
+	// and the validation records of ACM certificates are written again, which ACM's managed
+	// renewal needs
+	OperationReissueExpiring = "reissue-expiring"
+
+	// OperationDrainGateway cordons a Gateway and moves its hostnames to other Gateways one by
+	// one, by setting AnnotationDrain on each request
+	OperationDrainGateway = "drain-gateway"
+
+	// OperationRevalidatePending writes the validation records of every certificate that isn't
+	// issued yet again, like setting AnnotationReconcileNow on each of those requests
+	OperationRevalidatePending = "revalidate-pending"
+)
+
+// DefaultBulkPerMinute is how many requests a bulk operation changes per minute, so the
+// reconciles it causes don't hit the AWS rate limits at once
+const DefaultBulkPerMinute = 30
+
+// DefaultReissueWindow is the default ?within= of OperationReissueExpiring
+const DefaultReissueWindow = 30 * 24 * time.Hour
+
+// Outcomes of a bulk operation for one request
+const (
+	AdminItemChanged = "Changed"
+	AdminItemFailed  = "Failed"
+)
+
+// AdminItem is the outcome of a bulk operation for one object
+type AdminItem struct {
+	// Object is the object, as kind/namespace/name
+	Object string `json:"object"`
+
+	// Status is AdminItemChanged or AdminItemFailed
+	Status string `json:"status"`
+
+	Message string `json:"message,omitempty"`
+}
+
+// bulkPerMinute returns the configured bulk rate, defaulting to DefaultBulkPerMinute
+func (a *Admin) bulkPerMinute() int {
+	if a.BulkPerMinute > 0 {
+		return a.BulkPerMinute
+	}
+	return DefaultBulkPerMinute
+}
+
+// runBulk calls change for each request, at most bulkPerMinute times a minute, and reports each
+// outcome in the result; the message sums up all of its items. change returns a message for the
+// item and an error if it failed; a failed request doesn't stop the others. Each outcome is
+// reported to the job running the operation. It stops if the context is cancelled, e.g. because
+// the controller shuts down.
+func (a *Admin) runBulk(ctx context.Context, result *AdminResult, requests []*gatewayv1alpha1.GatewayHostnameRequest,
+	change func(*gatewayv1alpha1.GatewayHostnameRequest) (string, error)) error {
+	logger := log.FromContext(ctx)
+	limiter := rate.NewLimiter(rate.Every(time.Minute/time.Duration(a.bulkPerMinute())), 1)
+	for i, ghr := range requests {
+		if err := limiter.Wait(ctx); err != nil {
+			return fmt.Errorf("stopped after %d of %d requests: %w", i, len(requests), err)
+		}
+		object := "GatewayHostnameRequest/" + ghr.Namespace + "/" + ghr.Name
+		message, err := change(ghr)
+		if err != nil {
+			logger.Info("Bulk operation failed for request", "operation", result.Operation, "request", object, "error", err.Error())
+			result.Items = append(result.Items, AdminItem{Object: object, Status: AdminItemFailed, Message: err.Error()})
+			reportProgress(ctx, result)
+			continue
+		}
+		result.Items = append(result.Items, AdminItem{Object: object, Status: AdminItemChanged, Message: message})
+		result.Touched = append(result.Touched, object)
+		reportProgress(ctx, result)
+	}
+	failed := 0
+	for _, item := range result.Items {
+		if item.Status == AdminItemFailed {
+			failed++
+		}
+	}
+	result.Message = fmt.Sprintf("%d changed", len(result.Items)-failed)
+	if failed > 0 {
+		result.Message += fmt.Sprintf(", %d failed", failed)
+	}
+	return nil
+}
+
+// annotateRequest sets annotations on a request with a merge patch
+func (a *Admin) annotateRequest(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, annotations map[string]string) error {
+	patch := client.MergeFrom(ghr.DeepCopy())
+	if ghr.Annotations == nil {
+		ghr.Annotations = map[string]string{}
+	}
+	for key, value := range annotations {
+		ghr.Annotations[key] = value
+	}
+	if err := a.Reconciler.Patch(ctx, ghr, patch); err != nil {
+		return fmt.Errorf("failed to annotate %s/%s: %w", ghr.Namespace, ghr.Name, err)
+	}
+	return nil
+}
+
+// listRequests returns the requests that aren't being deleted and match the filter
+func (a *Admin) listRequests(ctx context.Context, match func(*gatewayv1alpha1.GatewayHostnameRequest) bool) ([]*gatewayv1alpha1.GatewayHostnameRequest, error) {
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := a.Reconciler.List(ctx, &ghrList); err != nil {
+		return nil, fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+	var requests []*gatewayv1alpha1.GatewayHostnameRequest
+	for i := range ghrList.Items {
+		ghr := &ghrList.Items[i]
+		if ghr.DeletionTimestamp.IsZero() && match(ghr) {
+			requests = append(requests, ghr)
+		}
+	}
+	return requests, nil
+}
+
+// ReissueExpiring renews the certificates that expire within the window. ACME expiries are read
+// from status; ACM certificates are described one by one at the bulk rate, and only the
+// expiring ones are listed in the result.
+func (a *Admin) ReissueExpiring(ctx context.Context, within time.Duration) (*AdminResult, error) {
+	result := &AdminResult{Operation: OperationReissueExpiring}
+	deadline := time.Now().Add(within).UTC()
+	requests, err := a.listRequests(ctx, func(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+		return ghr.Status.CertificateArn != "" && conditions.IsTrue(ghr.Status.Conditions, ConditionTypeCertificateIssued)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var expiring []*gatewayv1alpha1.GatewayHostnameRequest
+	limiter := rate.NewLimiter(rate.Every(time.Minute/time.Duration(a.bulkPerMinute())), 1)
+	for _, ghr := range requests {
+		if certificateIssuer(ghr) == CertificateIssuerACME {
+			if ghr.Status.CertificateNotAfter != nil && ghr.Status.CertificateNotAfter.Time.Before(deadline) {
+				expiring = append(expiring, ghr)
+			}
+			continue
+		}
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		awsCtx, cancel := withAWSTimeout(ctx)
+		details, err := a.Reconciler.ACMClient.DescribeCertificate(awsCtx, ghr.Status.CertificateArn)
+		cancel()
+		if err != nil {
+			result.Items = append(result.Items, AdminItem{
+				Object:  "GatewayHostnameRequest/" + ghr.Namespace + "/" + ghr.Name,
+				Status:  AdminItemFailed,
+				Message: fmt.Sprintf("failed to describe certificate: %v", err),
+			})
+			reportProgress(ctx, result)
+			continue
+		}
+		if !details.NotAfter.IsZero() && details.NotAfter.Before(deadline) {
+			expiring = append(expiring, ghr)
+		}
+	}
+
+	value := time.Now().UTC().Format(time.RFC3339Nano)
+	if err := a.runBulk(ctx, result, expiring, func(ghr *gatewayv1alpha1.GatewayHostnameRequest) (string, error) {
+		if certificateIssuer(ghr) == CertificateIssuerACME {
+			message := "Renewing ACME certificate expiring " + ghr.Status.CertificateNotAfter.UTC().Format(time.RFC3339)
+			return message, a.annotateRequest(ctx, ghr, map[string]string{AnnotationRenewCertificateBefore: deadline.Format(time.RFC3339)})
+		}
+		return "Writing the validation records ACM renews the certificate with", a.annotateRequest(ctx, ghr, map[string]string{AnnotationReconcileNow: value})
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DrainGateway cordons the Gateway, then sets AnnotationDrain on its requests at the bulk rate,
+// so they move to other Gateways a few at a time instead of all at once
+func (a *Admin) DrainGateway(ctx context.Context, gatewayRef string) (*AdminResult, error) {
+	gw, err := a.getGateway(ctx, gatewayRef)
+	if err != nil {
+		return nil, err
+	}
+	if err := foreignOwnership("Gateway", gw); err != nil {
+		return nil, err
+	}
+	result := &AdminResult{Operation: OperationDrainGateway}
+	if !gateway.IsCordoned(gw) {
+		if gw.Annotations == nil {
+			gw.Annotations = map[string]string{}
+		}
+		gw.Annotations[gateway.AnnotationCordoned] = gateway.CordonReasonDrained
+		if err := a.Reconciler.Update(ctx, gw); err != nil {
+			return nil, fmt.Errorf("failed to cordon Gateway %s: %w", gw.Name, err)
+		}
+		result.Touched = append(result.Touched, "Gateway/"+gw.Namespace+"/"+gw.Name)
+	}
+
+	requests, err := a.listRequests(ctx, func(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+		return ghr.Status.AssignedGateway == gw.Name && ghr.Status.AssignedGatewayNamespace == gw.Namespace
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.Reconciler.Recorder.Eventf(gw, corev1.EventTypeNormal, "DrainRequested", "Admin API requested moving %d hostnames to other Gateways", len(requests))
+	gatewayKey := gw.Namespace + "/" + gw.Name
+	value := time.Now().UTC().Format(time.RFC3339Nano)
+	if err := a.runBulk(ctx, result, requests, func(ghr *gatewayv1alpha1.GatewayHostnameRequest) (string, error) {
+		return "Moving " + ghr.Spec.Hostname + " to another Gateway",
+			a.annotateRequest(ctx, ghr, map[string]string{AnnotationDrain: gatewayKey, AnnotationReconcileNow: value})
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RevalidatePending reconciles every request whose certificate is requested but not issued, which
+// writes its validation records again
+func (a *Admin) RevalidatePending(ctx context.Context) (*AdminResult, error) {
+	result := &AdminResult{Operation: OperationRevalidatePending}
+	requests, err := a.listRequests(ctx, func(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+		return (ghr.Status.CertificateArn != "" || ghr.Status.CertificateOrderURL != "") &&
+			!conditions.IsTrue(ghr.Status.Conditions, ConditionTypeCertificateIssued)
+	})
+	if err != nil {
+		return nil, err
+	}
+	value := time.Now().UTC().Format(time.RFC3339Nano)
+	if err := a.runBulk(ctx, result, requests, func(ghr *gatewayv1alpha1.GatewayHostnameRequest) (string, error) {
+		return "Writing the validation records of " + ghr.Spec.Hostname + " again", a.annotateRequest(ctx, ghr, map[string]string{AnnotationReconcileNow: value})
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
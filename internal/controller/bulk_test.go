@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+func issuedOnGateway(i int, gatewayName string) *gatewayv1alpha1.GatewayHostnameRequest {
+	ghr := ghrOnGateway(i, gatewayName)
+	ghr.Status.Conditions = []metav1.Condition{{Type: ConditionTypeCertificateIssued, Status: metav1.ConditionTrue, Reason: "Issued"}}
+	return ghr
+}
+
+func newBulkTestAdmin(objs ...client.Object) *Admin {
+	a := newTestAdmin(objs...)
+	a.BulkPerMinute = 60000
+	return a
+}
+
+func getRequest(t *testing.T, a *Admin, name string) *gatewayv1alpha1.GatewayHostnameRequest {
+	var ghr gatewayv1alpha1.GatewayHostnameRequest
+	require.NoError(t, a.Reconciler.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: name}, &ghr))
+	return &ghr
+}
+
+// runAdminJob starts a bulk operation and waits for its job to finish
+func runAdminJob(t *testing.T, a *Admin, target string) AdminJob {
+	t.Helper()
+	rec, _ := callAdmin(a, http.MethodPost, target)
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	var job AdminJob
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &job))
+	assert.Equal(t, AdminJobPath+job.ID, rec.Header().Get("Location"))
+
+	require.Eventually(t, func() bool {
+		rec := getAdminJob(a, job.ID)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &job))
+		return job.State != AdminJobRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	return job
+}
+
+func getAdminJob(a *Admin, id string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, AdminJobPath+id, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	a.serveJob(rec, req)
+	return rec
+}
+
+func TestAdminReissueExpiring(t *testing.T) {
+	acmeExpiring := issuedOnGateway(1, "gw-01")
+	acmeExpiring.Status.CertificateIssuer = CertificateIssuerACME
+	notAfter := metav1.NewTime(time.Now().Add(10 * 24 * time.Hour))
+	acmeExpiring.Status.CertificateNotAfter = &notAfter
+	acmExpiring := issuedOnGateway(2, "gw-01")
+	acmValid := issuedOnGateway(3, "gw-01")
+	acmMissing := issuedOnGateway(4, "gw-01")
+
+	a := newBulkTestAdmin(acmeExpiring, acmExpiring, acmValid, acmMissing)
+	acmMock := aws.NewMockACMClient()
+	acmMock.Certificates[acmExpiring.Status.CertificateArn] = &aws.CertificateDetails{Status: "ISSUED", NotAfter: time.Now().Add(5 * 24 * time.Hour)}
+	acmMock.Certificates[acmValid.Status.CertificateArn] = &aws.CertificateDetails{Status: "ISSUED", NotAfter: time.Now().Add(200 * 24 * time.Hour)}
+	a.Reconciler.ACMClient = acmMock
+
+	job := runAdminJob(t, a, AdminPath+OperationReissueExpiring)
+	require.Equal(t, AdminJobSucceeded, job.State, job.Error)
+	result := job.Result
+	assert.Equal(t, "2 changed, 1 failed", result.Message)
+	require.Len(t, result.Items, 3)
+	assert.Equal(t, AdminItem{Object: "GatewayHostnameRequest/default/ghr-4", Status: AdminItemFailed, Message: result.Items[0].Message}, result.Items[0])
+	assert.Equal(t, "GatewayHostnameRequest/default/ghr-1", result.Items[1].Object)
+	assert.Equal(t, AdminItemChanged, result.Items[1].Status)
+	assert.Equal(t, "GatewayHostnameRequest/default/ghr-2", result.Items[2].Object)
+
+	assert.NotEmpty(t, getRequest(t, a, "ghr-1").Annotations[AnnotationRenewCertificateBefore])
+	assert.NotEmpty(t, getRequest(t, a, "ghr-2").Annotations[AnnotationReconcileNow])
+	assert.Empty(t, getRequest(t, a, "ghr-3").Annotations)
+
+	rec, _ := callAdmin(a, http.MethodPost, AdminPath+OperationReissueExpiring+"?within=soon")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestAdminDrainGateway(t *testing.T) {
+	a := newBulkTestAdmin(adminGateway("gw-03", "2", 0), issuedOnGateway(1, "gw-03"), issuedOnGateway(2, "gw-03"), issuedOnGateway(3, "gw-01"))
+
+	job := runAdminJob(t, a, AdminPath+OperationDrainGateway+"?gateway=edge/gw-03")
+	require.Equal(t, AdminJobSucceeded, job.State, job.Error)
+	assert.Equal(t, []string{"Gateway/edge/gw-03", "GatewayHostnameRequest/default/ghr-1", "GatewayHostnameRequest/default/ghr-2"}, job.Result.Touched)
+	assert.Len(t, job.Result.Items, 2)
+
+	var gw gwapiv1.Gateway
+	require.NoError(t, a.Reconciler.Get(context.Background(), types.NamespacedName{Namespace: "edge", Name: "gw-03"}, &gw))
+	assert.Equal(t, gateway.CordonReasonDrained, gw.Annotations[gateway.AnnotationCordoned])
+	assert.Empty(t, gw.Annotations[AnnotationDrain], "the drain is gated per request")
+	assert.Equal(t, "edge/gw-03", getRequest(t, a, "ghr-1").Annotations[AnnotationDrain])
+	assert.NotEmpty(t, getRequest(t, a, "ghr-1").Annotations[AnnotationReconcileNow])
+	assert.Empty(t, getRequest(t, a, "ghr-3").Annotations)
+
+	rec, _ := callAdmin(a, http.MethodPost, AdminPath+OperationDrainGateway)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec, _ = callAdmin(a, http.MethodPost, AdminPath+OperationDrainGateway+"?gateway=edge/missing")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestAdminRevalidatePending(t *testing.T) {
+	pending := ghrOnGateway(1, "")
+	a := newBulkTestAdmin(pending, issuedOnGateway(2, "gw-01"))
+
+	job := runAdminJob(t, a, AdminPath+OperationRevalidatePending)
+	require.Equal(t, AdminJobSucceeded, job.State, job.Error)
+	result := job.Result
+	assert.Equal(t, []AdminItem{{Object: "GatewayHostnameRequest/default/ghr-1", Status: AdminItemChanged, Message: "Writing the validation records of app1.opendi.com again"}}, result.Items)
+	assert.Equal(t, "1 changed", result.Message)
+	assert.NotEmpty(t, getRequest(t, a, "ghr-1").Annotations[AnnotationReconcileNow])
+	assert.Empty(t, getRequest(t, a, "ghr-2").Annotations)
+}
+
+func TestAdminJobs(t *testing.T) {
+	a := newBulkTestAdmin()
+
+	rec := getAdminJob(a, "unknown")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	job := runAdminJob(t, a, AdminPath+OperationRevalidatePending)
+	assert.Equal(t, OperationRevalidatePending, job.Operation)
+	assert.NotNil(t, job.FinishedAt)
+
+	rec = httptest.NewRecorder()
+	a.serveJob(rec, httptest.NewRequest(http.MethodGet, AdminJobPath+job.ID, nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	for i := 0; i < maxAdminJobs+5; i++ {
+		a.jobs.add(&AdminJob{ID: fmt.Sprintf("job-%d", i), State: AdminJobSucceeded})
+	}
+	a.jobs.add(&AdminJob{ID: "running", State: AdminJobRunning})
+	_, ok := a.jobs.get(job.ID)
+	assert.False(t, ok, "the oldest finished jobs are forgotten")
+	_, ok = a.jobs.get("running")
+	assert.True(t, ok)
+	assert.Len(t, a.jobs.jobs, maxAdminJobs)
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// caaRecheckInterval is how often CAA records that forbid Amazon are checked again
+const caaRecheckInterval = 5 * time.Minute
+
+// ErrCAAForbidsAmazon means the hostname's CAA records do not authorize Amazon. ACM would
+// keep the certificate in PENDING_VALIDATION until it times out.
+var ErrCAAForbidsAmazon = errors.New("CAA records do not allow Amazon to issue certificates")
+
+// amazonCAADomains are the issuer domains that authorize ACM
+var amazonCAADomains = map[string]bool{
+	"amazon.com":      true,
+	"amazontrust.com": true,
+	"awstrust.com":    true,
+	"amazonaws.com":   true,
+}
+
+// checkCAA looks up the CAA record set relevant for the hostname in its Route53 zone and returns
+// ErrCAAForbidsAmazon if it does not authorize Amazon. As in RFC 8659, the closest record set
+// walking from the hostname towards the zone apex applies; without any, every CA may issue.
+// Record sets above the request's zone are not visible and not checked.
+func (r *GatewayHostnameRequestReconciler) checkCAA(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	wildcard := strings.HasPrefix(ghr.Spec.Hostname, "*.")
+
+	awsCtx, cancel := withAWSTimeout(ctx)
+	defer cancel()
+
+	for _, name := range caaLookupNames(ghr.Spec.Hostname) {
+		record, err := r.Route53Client.GetRecord(awsCtx, recordZoneID(ghr), name, "CAA")
+		if err != nil {
+			return fmt.Errorf("failed to look up CAA records for %s: %w", name, err)
+		}
+		if record == nil {
+			continue
+		}
+		values := record.Values
+		if len(values) == 0 && record.Value != "" {
+			values = []string{record.Value}
+		}
+		if !caaAllowsAmazon(values, wildcard) {
+			return fmt.Errorf("%w: %s has CAA records %s", ErrCAAForbidsAmazon, name, strings.Join(values, ", "))
+		}
+		return nil
+	}
+	return nil
+}
+
+// caaLookupNames returns the names whose CAA records may apply to the hostname, closest first.
+// The top-level domain is left out.
+func caaLookupNames(hostname string) []string {
+	name := strings.TrimPrefix(hostname, "*.")
+	var names []string
+	for strings.Contains(name, ".") {
+		names = append(names, name)
+		name = name[strings.Index(name, ".")+1:]
+	}
+	return names
+}
+
+// caaAllowsAmazon evaluates a CAA record set. Certificates for wildcards are governed by
+// issuewild properties if there are any, otherwise by issue properties. A record set without
+// either (e.g. only iodef) does not restrict issuance.
+func caaAllowsAmazon(values []string, wildcard bool) bool {
+	var issue, issueWild []string
+	for _, v := range values {
+		fields := strings.SplitN(strings.TrimSpace(v), " ", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		value := strings.Trim(strings.TrimSpace(fields[2]), `"`)
+		switch strings.ToLower(fields[1]) {
+		case "issue":
+			issue = append(issue, value)
+		case "issuewild":
+			issueWild = append(issueWild, value)
+		}
+	}
+
+	properties := issue
+	if wildcard && len(issueWild) > 0 {
+		properties = issueWild
+	}
+	if properties == nil {
+		return true
+	}
+	for _, value := range properties {
+		domain, _, _ := strings.Cut(value, ";")
+		if amazonCAADomains[strings.ToLower(strings.TrimSpace(domain))] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+)
+
+func TestCAALookupNames(t *testing.T) {
+	assert.Equal(t, []string{"api.app.example.com", "app.example.com", "example.com"}, caaLookupNames("api.app.example.com"))
+	assert.Equal(t, []string{"app.example.com", "example.com"}, caaLookupNames("*.app.example.com"))
+}
+
+func TestCAAAllowsAmazon(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		wildcard bool
+		want     bool
+	}{
+		{name: "amazon allowed", values: []string{`0 issue "letsencrypt.org"`, `0 issue "amazon.com"`}, want: true},
+		{name: "parameters after the domain", values: []string{`0 issue "amazontrust.com; account=1"`}, want: true},
+		{name: "other CA only", values: []string{`0 issue "letsencrypt.org"`}, want: false},
+		{name: "issuance forbidden", values: []string{`0 issue ";"`}, want: false},
+		{name: "no issue properties", values: []string{`0 iodef "mailto:security@example.com"`}, want: true},
+		{name: "issuewild governs wildcards", values: []string{`0 issue "amazon.com"`, `0 issuewild "letsencrypt.org"`}, wildcard: true, want: false},
+		{name: "issuewild does not apply to other names", values: []string{`0 issue "amazon.com"`, `0 issuewild ";"`}, want: true},
+		{name: "issue applies to wildcards without issuewild", values: []string{`0 issue "letsencrypt.org"`}, wildcard: true, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, caaAllowsAmazon(tt.values, tt.wildcard))
+		})
+	}
+}
+
+func TestReconcileNormal_CAAForbidsAmazon(t *testing.T) {
+	ctx := context.Background()
+	ghr := acmeGHR()
+	ghr.Spec.CertificateIssuer = ""
+	acmMock := &MockACMClient{certificates: make(map[string]string)}
+	r, _, route53Mock := acmeTestReconciler(ghr, acmMock)
+
+	// The closest record set (app.example.com) applies, not the one at the apex
+	route53Mock.records["Z123456"] = []aws.DNSRecord{
+		{Name: "app.example.com", Type: "CAA", Values: []string{`0 issue "letsencrypt.org"`}},
+		{Name: "example.com", Type: "CAA", Values: []string{`0 issue "amazon.com"`}},
+	}
+
+	result, err := r.reconcileNormal(ctx, ghr)
+	require.NoError(t, err)
+	assert.Equal(t, caaRecheckInterval, result.RequeueAfter)
+	assert.Empty(t, ghr.Status.CertificateArn)
+	assert.Empty(t, acmMock.certificates)
+	cond := meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeCertificateRequested)
+	require.NotNil(t, cond)
+	assert.Equal(t, string(conditions.ReasonCAAForbidsAmazon), cond.Reason)
+	assert.Contains(t, cond.Message, "app.example.com")
+
+	// Once the CAA records allow Amazon, the certificate is requested
+	route53Mock.records["Z123456"][0].Values = append(route53Mock.records["Z123456"][0].Values, `0 issuewild "amazon.com"`)
+	_, err = r.reconcileNormal(ctx, ghr)
+	require.NoError(t, err)
+	assert.NotEmpty(t, ghr.Status.CertificateArn)
+}
@@ -2,8 +2,10 @@ package controller
 
 import (
 	"context"
-	"errors"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"maps"
 	"strings"
 	"time"
 
@@ -17,7 +19,50 @@ const (
 	AWSCallTimeout = 30 * time.Second
 )
 
-var ErrValidationRecordsNotReady = errors.New("validation records not ready")
+// DefaultCertificatePollBackoff is the default delay curve for polling ACM while a certificate
+// is pending issuance: quick feedback for certificates that validate fast, fewer calls for slow ones
+var DefaultCertificatePollBackoff = []time.Duration{15 * time.Second, 30 * time.Second, time.Minute, 5 * time.Minute}
+
+// certificatePollInterval returns the delay before the next issuance check, given how long the
+// certificate has been pending. Each step of the curve is used once; the last one repeats.
+func (r *GatewayHostnameRequestReconciler) certificatePollInterval(pendingFor time.Duration) time.Duration {
+	curve := r.CertificatePollBackoff
+	if settings := r.settings(); settings != nil {
+		curve = settings.CertificatePollBackoff
+	}
+	if len(curve) == 0 {
+		curve = DefaultCertificatePollBackoff
+	}
+
+	var elapsed time.Duration
+	for _, step := range curve {
+		elapsed += step
+		if pendingFor < elapsed {
+			return step
+		}
+	}
+	return curve[len(curve)-1]
+}
+
+// ParseBackoffCurve parses a comma-separated list of durations, e.g. "15s,30s,1m,5m"
+func ParseBackoffCurve(s string) ([]time.Duration, error) {
+	var curve []time.Duration
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		d, err := time.ParseDuration(entry)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid backoff step %q, expected a positive duration", entry)
+		}
+		curve = append(curve, d)
+	}
+	if len(curve) == 0 {
+		return nil, fmt.Errorf("backoff curve %q has no steps", s)
+	}
+	return curve, nil
+}
 
 // withAWSTimeout returns a context with the standard AWS call timeout.
 // Always call cancel() after the AWS call completes to release resources.
@@ -31,19 +76,60 @@ func sanitizeTagValue(s string) string {
 	return strings.ReplaceAll(s, "*", "wildcard")
 }
 
-// requestCertificate requests a new ACM certificate for the hostname
-func (r *GatewayHostnameRequestReconciler) requestCertificate(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (string, error) {
-	tags := map[string]string{
-		"managed-by":  "gateway-orchestrator",
-		"hostname":    sanitizeTagValue(ghr.Spec.Hostname),
-		"namespace":   ghr.Namespace,
-		"environment": ghr.Spec.Environment,
+// certificateIdempotencyToken derives the ACM idempotency token for the request's current
+// certificate. Retrying after a failed status write returns the pending certificate instead
+// of leaking a duplicate; a replacement certificate gets a new token.
+// ACM tokens are limited to 32 word characters.
+func certificateIdempotencyToken(ghr *gatewayv1alpha1.GatewayHostnameRequest) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%d", ghr.UID, ghr.Status.CertificateReplacements)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// discardCertificate clears the certificate so the next reconcile requests a new one
+func discardCertificate(ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+	ghr.Status.CertificateIssuer = ""
+	ghr.Status.CertificateOrderURL = ""
+	ghr.Status.CertificateNotAfter = nil
+	ghr.Status.CertificateHost = ""
+	ghr.Status.CertificateSANs = nil
+	ghr.Status.PendingCertificateArn = ""
+	ghr.Status.RetiredCertificateArn = ""
+	if ghr.Status.CertificateArn == "" {
+		return
 	}
+	ghr.Status.CertificateArn = ""
+	ghr.Status.CertificateRegion = ""
+	ghr.Status.CertificateReplacements++
+}
 
-	awsCtx, cancel := withAWSTimeout(ctx)
+// certificateManagedBy is the managed-by tag value of certificates requested or imported by the orchestrator
+const certificateManagedBy = "gateway-orchestrator"
+
+// certificateTags returns the ACM tags of the request's certificate: the OrchestratorConfig's
+// certificate tags and the interop tags, overridden by the orchestrator's own
+func (r *GatewayHostnameRequestReconciler) certificateTags(ghr *gatewayv1alpha1.GatewayHostnameRequest) map[string]string {
+	tags := map[string]string{}
+	if settings := r.settings(); settings != nil {
+		maps.Copy(tags, settings.CertificateTags)
+	}
+	maps.Copy(tags, r.InteropTags)
+	tags["managed-by"] = certificateManagedBy
+	tags["hostname"] = sanitizeTagValue(ghr.Spec.Hostname)
+	tags["namespace"] = ghr.Namespace
+	tags["environment"] = ghr.Spec.Environment
+	if r.Cluster != "" {
+		tags["cluster"] = r.Cluster
+	}
+	return tags
+}
+
+// requestCertificate requests a new ACM certificate for the hostname, in spec.certificateRegion
+// if set
+func (r *GatewayHostnameRequestReconciler) requestCertificate(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (string, error) {
+	awsCtx, cancel := withAWSTimeout(aws.WithCertificateRegion(ctx, ghr.Spec.CertificateRegion))
 	defer cancel()
 
-	certArn, err := r.ACMClient.RequestCertificate(awsCtx, ghr.Spec.Hostname, tags)
+	certArn, err := r.ACMClient.RequestCertificate(awsCtx, CanonicalHostname(ghr.Spec.Hostname), certificateIdempotencyToken(ghr), r.certificateTags(ghr))
 	if err != nil {
 		return "", fmt.Errorf("failed to request certificate: %w", err)
 	}
@@ -53,27 +139,32 @@ func (r *GatewayHostnameRequestReconciler) requestCertificate(ctx context.Contex
 
 // ensureValidationRecords creates DNS validation records in Route53
 func (r *GatewayHostnameRequestReconciler) ensureValidationRecords(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
-	logger := log.FromContext(ctx)
 	if ghr.Status.CertificateArn == "" {
 		return fmt.Errorf("certificate ARN not set")
 	}
+	return r.ensureCertificateValidationRecords(ctx, ghr, ghr.Status.CertificateArn)
+}
+
+// ensureCertificateValidationRecords creates the DNS validation records of one of the request's
+// certificates in the request's record zone
+func (r *GatewayHostnameRequestReconciler) ensureCertificateValidationRecords(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, certArn string) error {
+	logger := log.FromContext(ctx)
 
 	awsCtx, cancel := withAWSTimeout(ctx)
 	defer cancel()
 
 	// Get validation records from ACM
-	validationRecords, err := r.ACMClient.GetValidationRecords(awsCtx, ghr.Status.CertificateArn)
+	validationRecords, err := r.ACMClient.GetValidationRecords(awsCtx, certArn)
 	if err != nil {
 		return fmt.Errorf("failed to get validation records: %w", err)
 	}
 
 	logger.Info("Retrieved validation records from ACM",
 		"count", len(validationRecords),
-		"certificateArn", ghr.Status.CertificateArn,
-		"hostname", ghr.Spec.Hostname)
+		"certificateArn", certArn)
 
 	if len(validationRecords) == 0 {
-		logger.Info("ACM validation records not ready yet", "certificateArn", ghr.Status.CertificateArn, "hostname", ghr.Spec.Hostname)
+		logger.Info("ACM validation records not ready yet", "certificateArn", certArn)
 		return ErrValidationRecordsNotReady
 	}
 
@@ -87,25 +178,23 @@ func (r *GatewayHostnameRequestReconciler) ensureValidationRecords(ctx context.C
 		}
 
 		recordCtx, recordCancel := withAWSTimeout(ctx)
-		err := r.Route53Client.CreateOrUpdateRecord(recordCtx, ghr.Spec.ZoneId, record)
+		err := r.Route53Client.CreateOrUpdateRecord(recordCtx, recordZoneID(ghr), record)
 		recordCancel()
 		if err != nil {
 			logger.Error(err, "Failed to create validation record",
 				"name", record.Name,
-				"zoneId", ghr.Spec.ZoneId,
-				"hostname", ghr.Spec.Hostname)
+				"zoneId", recordZoneID(ghr))
 			return fmt.Errorf("failed to create validation record: %w", err)
 		}
 
 		logger.Info("Created validation record in Route53",
 			"name", record.Name,
 			"type", record.Type,
-			"zoneId", ghr.Spec.ZoneId)
+			"zoneId", recordZoneID(ghr))
 	}
 
 	logger.Info("All validation records created successfully",
-		"count", len(validationRecords),
-		"hostname", ghr.Spec.Hostname)
+		"count", len(validationRecords))
 	return nil
 }
 
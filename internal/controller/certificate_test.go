@@ -3,7 +3,9 @@ package controller
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -73,7 +75,7 @@ func TestReconciler_ensureValidationRecords(t *testing.T) {
 	ctx := context.Background()
 
 	// Request a certificate first
-	arn, _ := acmClient.RequestCertificate(ctx, "test.example.com", nil)
+	arn, _ := acmClient.RequestCertificate(ctx, "test.example.com", "", nil)
 
 	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
 		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
@@ -122,7 +124,7 @@ func TestReconciler_ensureValidationRecords_PendingACMRecords(t *testing.T) {
 	ctx := context.Background()
 
 	// Request a certificate and then simulate ACM returning no validation records yet
-	arn, _ := acmClient.RequestCertificate(ctx, "test.example.com", nil)
+	arn, _ := acmClient.RequestCertificate(ctx, "test.example.com", "", nil)
 	acmClient.ValidationRecords[arn] = []aws.ValidationRecord{}
 
 	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
@@ -201,7 +203,7 @@ func TestReconciler_checkCertificateStatus(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create certificate with specific status
-			arn, _ := acmClient.RequestCertificate(ctx, "test.example.com", nil)
+			arn, _ := acmClient.RequestCertificate(ctx, "test.example.com", "", nil)
 			acmClient.Certificates[arn].Status = tt.certStatus
 
 			ghr := &gatewayv1alpha1.GatewayHostnameRequest{
@@ -224,8 +226,40 @@ func TestReconciler_checkCertificateStatus(t *testing.T) {
 	}
 }
 
+func TestReconciler_requestCertificate_PinnedRegion(t *testing.T) {
+	controllerRegion := aws.NewMockACMClient()
+	pinnedRegion := aws.NewMockACMClient()
+	r := &GatewayHostnameRequestReconciler{
+		ACMClient: aws.NewRegionalACMClient(controllerRegion, "eu-west-1", func(region string) aws.ACMClient {
+			if region != "us-east-1" {
+				t.Fatalf("unexpected region %s", region)
+			}
+			return pinnedRegion
+		}),
+	}
+
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "cdn", Namespace: "default"},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+			Hostname:          "cdn.example.com",
+			CertificateRegion: "us-east-1",
+			AliasTarget:       &gatewayv1alpha1.AliasTarget{DNSName: "d111111abcdef8.cloudfront.net"},
+		},
+	}
+	if _, err := r.requestCertificate(context.Background(), ghr); err != nil {
+		t.Fatalf("requestCertificate() error = %v", err)
+	}
+	if len(pinnedRegion.Certificates) != 1 || len(controllerRegion.Certificates) != 0 {
+		t.Errorf("certificate requested in the wrong region: pinned %d, controller %d", len(pinnedRegion.Certificates), len(controllerRegion.Certificates))
+	}
+
+	if computeSpecHash(&ghr.Spec) == computeSpecHash(&gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "cdn.example.com"}) {
+		t.Error("pinning the certificate region should re-provision the certificate")
+	}
+}
+
 func TestReconciler_validateRequest(t *testing.T) {
-	r := &GatewayHostnameRequestReconciler{}
+	r := &GatewayHostnameRequestReconciler{Region: "eu-west-1"}
 
 	tests := []struct {
 		name    string
@@ -260,6 +294,41 @@ func TestReconciler_validateRequest(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "certificate pinned to the Gateway region",
+			ghr: &gatewayv1alpha1.GatewayHostnameRequest{
+				Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+					ZoneId:            "Z123456",
+					Hostname:          "test.example.com",
+					CertificateRegion: "eu-central-1",
+				},
+				Status: gatewayv1alpha1.GatewayHostnameRequestStatus{GatewayRegion: "eu-central-1"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "certificate pinned to another region than the Gateway",
+			ghr: &gatewayv1alpha1.GatewayHostnameRequest{
+				Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+					ZoneId:            "Z123456",
+					Hostname:          "test.example.com",
+					CertificateRegion: "us-east-1",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "DNS-only certificate pinned to another region",
+			ghr: &gatewayv1alpha1.GatewayHostnameRequest{
+				Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+					ZoneId:            "Z123456",
+					Hostname:          "test.example.com",
+					CertificateRegion: "us-east-1",
+					AliasTarget:       &gatewayv1alpha1.AliasTarget{DNSName: "d111111abcdef8.cloudfront.net"},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "both missing",
 			ghr: &gatewayv1alpha1.GatewayHostnameRequest{
@@ -278,3 +347,86 @@ func TestReconciler_validateRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestReconciler_certificatePollInterval(t *testing.T) {
+	r := &GatewayHostnameRequestReconciler{}
+
+	tests := []struct {
+		pendingFor time.Duration
+		want       time.Duration
+	}{
+		{0, 15 * time.Second},
+		{14 * time.Second, 15 * time.Second},
+		{15 * time.Second, 30 * time.Second},
+		{50 * time.Second, time.Minute},
+		{2 * time.Minute, 5 * time.Minute},
+		{time.Hour, 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := r.certificatePollInterval(tt.pendingFor); got != tt.want {
+			t.Errorf("certificatePollInterval(%v) = %v, want %v", tt.pendingFor, got, tt.want)
+		}
+	}
+
+	r.CertificatePollBackoff = []time.Duration{time.Minute}
+	if got := r.certificatePollInterval(time.Hour); got != time.Minute {
+		t.Errorf("custom curve: certificatePollInterval() = %v, want 1m", got)
+	}
+}
+
+func TestParseBackoffCurve(t *testing.T) {
+	curve, err := ParseBackoffCurve("10s, 1m,10m")
+	if err != nil {
+		t.Fatalf("ParseBackoffCurve() error = %v", err)
+	}
+	want := []time.Duration{10 * time.Second, time.Minute, 10 * time.Minute}
+	if len(curve) != len(want) {
+		t.Fatalf("ParseBackoffCurve() = %v, want %v", curve, want)
+	}
+	for i := range want {
+		if curve[i] != want[i] {
+			t.Errorf("step %d = %v, want %v", i, curve[i], want[i])
+		}
+	}
+
+	for _, invalid := range []string{"", "fast", "10s,-1s", "0s"} {
+		if _, err := ParseBackoffCurve(invalid); err == nil {
+			t.Errorf("ParseBackoffCurve(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestReconciler_requestCertificate_IdempotentRetry(t *testing.T) {
+	acmClient := aws.NewMockACMClient()
+	r := &GatewayHostnameRequestReconciler{ACMClient: acmClient}
+
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-request", Namespace: "default", UID: "3f1c1a9e-6a0b-4d2c-9a3e-2b7f0c4d5e6f"},
+		Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "test.example.com"},
+	}
+	ctx := context.Background()
+
+	token := certificateIdempotencyToken(ghr)
+	if len(token) > 32 || strings.Trim(token, "0123456789abcdef") != "" {
+		t.Fatalf("token %q is not a valid ACM idempotency token", token)
+	}
+
+	// The status write failed after the first request: the retry gets the same certificate
+	first, _ := r.requestCertificate(ctx, ghr)
+	retry, _ := r.requestCertificate(ctx, ghr)
+	if first != retry {
+		t.Errorf("retry requested a new certificate: %s != %s", retry, first)
+	}
+
+	// A discarded certificate is replaced by a new one
+	ghr.Status.CertificateArn = first
+	discardCertificate(ghr)
+	if ghr.Status.CertificateArn != "" || ghr.Status.CertificateReplacements != 1 {
+		t.Fatalf("discardCertificate() left arn=%q replacements=%d", ghr.Status.CertificateArn, ghr.Status.CertificateReplacements)
+	}
+	replacement, _ := r.requestCertificate(ctx, ghr)
+	if replacement == first {
+		t.Error("replacement certificate reused the discarded certificate")
+	}
+}
@@ -0,0 +1,358 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// maxCertificateSANs is how many hostnames a certificate is expanded for at most. ACM's default
+// quota allows 10 names per certificate, the certificate's own domain included.
+const maxCertificateSANs = 9
+
+// certificateExpansionPollInterval is how often an expanded certificate awaiting issuance, or a
+// retired one still in use, is looked at again
+const certificateExpansionPollInterval = 30 * time.Second
+
+// joinsCertificate reports whether the request shares, or may share, the certificate of a
+// request for a parent domain instead of getting its own
+func joinsCertificate(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	if ghr.Status.CertificateHost != "" {
+		return true
+	}
+	return ghr.Status.CertificateArn == "" && certificateIssuer(ghr) == CertificateIssuerACM && !isHTTPOnly(ghr) && !isDNSOnly(ghr)
+}
+
+// canHostCertificate reports whether other requests can join the request's certificate: it
+// has an issued ACM certificate of its own for a hostname that isn't a wildcard
+func canHostCertificate(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	return ghr.DeletionTimestamp.IsZero() && ghr.Status.CertificateHost == "" && ghr.Status.CertificateArn != "" &&
+		certificateIssuer(ghr) == CertificateIssuerACM && !isHTTPOnly(ghr) && !isDNSOnly(ghr) &&
+		!strings.HasPrefix(ghr.Spec.Hostname, "*.") &&
+		meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeCertificateIssued)
+}
+
+// certificateMembers returns the canonical hostnames of the requests that joined the request's
+// certificate, sorted
+func (r *GatewayHostnameRequestReconciler) certificateMembers(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) ([]string, error) {
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList, client.InNamespace(ghr.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+	var members []string
+	for _, other := range ghrList.Items {
+		if other.Status.CertificateHost == ghr.Name && other.DeletionTimestamp.IsZero() {
+			members = append(members, CanonicalHostname(other.Spec.Hostname))
+		}
+	}
+	sort.Strings(members)
+	return slices.Compact(members), nil
+}
+
+// certificateHostFor returns the request whose certificate the request can join: one in the
+// same namespace and record zone for the closest parent domain of its hostname, with room for
+// another name. Returns nil if there is none.
+func (r *GatewayHostnameRequestReconciler) certificateHostFor(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (*gatewayv1alpha1.GatewayHostnameRequest, error) {
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList, client.InNamespace(ghr.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+	members := map[string]int{}
+	for _, other := range ghrList.Items {
+		if other.Status.CertificateHost != "" && other.DeletionTimestamp.IsZero() {
+			members[other.Status.CertificateHost]++
+		}
+	}
+
+	hostname := CanonicalHostname(ghr.Spec.Hostname)
+	var host *gatewayv1alpha1.GatewayHostnameRequest
+	for i := range ghrList.Items {
+		candidate := &ghrList.Items[i]
+		if candidate.Name == ghr.Name || !canHostCertificate(candidate) || recordZoneID(candidate) != recordZoneID(ghr) {
+			continue
+		}
+		parent := CanonicalHostname(candidate.Spec.Hostname)
+		if !strings.HasSuffix(hostname, "."+parent) || members[candidate.Name] >= maxCertificateSANs {
+			continue
+		}
+		if host == nil || len(parent) > len(CanonicalHostname(host.Spec.Hostname)) ||
+			(len(parent) == len(CanonicalHostname(host.Spec.Hostname)) && candidate.Name < host.Name) {
+			host = candidate
+		}
+	}
+	return host, nil
+}
+
+// reconcileSharedCertificate runs instead of Steps 3-5 for a request that joins the certificate
+// of a request for a parent domain. The host re-issues its certificate with the hostname as an
+// additional name; once issued, the request serves the same certificate. If the host goes
+// away, the request keeps the certificate as its own, or requests one if it has none yet.
+// Returns done=true once the request has a certificate covering its hostname, or requests its own.
+func (r *GatewayHostnameRequestReconciler) reconcileSharedCertificate(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (ctrl.Result, bool, error) {
+	if ghr.Status.CertificateHost == "" {
+		host, err := r.certificateHostFor(ctx, ghr)
+		if err != nil {
+			return ctrl.Result{}, false, err
+		}
+		if host == nil {
+			return ctrl.Result{}, true, nil
+		}
+		ghr.Status.CertificateHost = host.Name
+		message := fmt.Sprintf("Joining the certificate of %s for %s", host.Name, host.Spec.Hostname)
+		r.setCondition(ghr, ConditionTypeCertificateRequested, metav1.ConditionTrue, conditions.ReasonSharedCertificate, message)
+		r.Recorder.Event(ghr, corev1.EventTypeNormal, "JoiningCertificate", message)
+		if err := r.Status().Update(ctx, ghr); err != nil {
+			return ctrl.Result{}, false, err
+		}
+	}
+
+	var host gatewayv1alpha1.GatewayHostnameRequest
+	err := r.Get(ctx, types.NamespacedName{Namespace: ghr.Namespace, Name: ghr.Status.CertificateHost}, &host)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, false, fmt.Errorf("failed to get certificate host %s: %w", ghr.Status.CertificateHost, err)
+	}
+	if err != nil || !host.DeletionTimestamp.IsZero() || host.Status.CertificateHost != "" {
+		log.FromContext(ctx).Info("Certificate host is gone, leaving its certificate", "host", ghr.Status.CertificateHost, "certificateArn", ghr.Status.CertificateArn)
+		r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "LeftCertificate", "%s no longer shares its certificate", ghr.Status.CertificateHost)
+		ghr.Status.CertificateHost = ""
+		if ghr.Status.CertificateArn == "" {
+			meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeCertificateRequested)
+			meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeCertificateIssued)
+		}
+		if err := r.Status().Update(ctx, ghr); err != nil {
+			return ctrl.Result{}, false, err
+		}
+		return ctrl.Result{Requeue: true}, false, nil
+	}
+
+	if !slices.Contains(host.Status.CertificateSANs, CanonicalHostname(ghr.Spec.Hostname)) {
+		// A certificate already served keeps serving until the host's covers the hostname
+		// again, e.g. after the host was re-provisioned
+		if ghr.Status.CertificateArn != "" {
+			return ctrl.Result{}, true, nil
+		}
+		r.setCondition(ghr, ConditionTypeCertificateIssued, metav1.ConditionFalse, conditions.ReasonPendingExpansion,
+			fmt.Sprintf("Waiting for %s to re-issue its certificate with the hostname", host.Name))
+		if err := r.Status().Update(ctx, ghr); err != nil {
+			return ctrl.Result{}, false, err
+		}
+		return ctrl.Result{RequeueAfter: certificateExpansionPollInterval}, false, nil
+	}
+
+	// The host swapped in a certificate covering the hostname; serve it too
+	if ghr.Status.CertificateArn != host.Status.CertificateArn {
+		ghr.Status.CertificateArn = host.Status.CertificateArn
+		ghr.Status.CertificateIssuer = CertificateIssuerACM
+		r.setCondition(ghr, ConditionTypeDnsValidated, metav1.ConditionTrue, conditions.ReasonRecordsCreated,
+			fmt.Sprintf("DNS validation records created by %s", host.Name))
+		r.setCondition(ghr, ConditionTypeCertificateIssued, metav1.ConditionTrue, conditions.ReasonIssued,
+			fmt.Sprintf("Certificate of %s issued with the hostname", host.Name))
+		r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "SharedCertificateIssued", "Serving the certificate of %s (%s)", host.Name, ghr.Status.CertificateArn)
+		if err := r.Status().Update(ctx, ghr); err != nil {
+			return ctrl.Result{}, false, err
+		}
+	}
+	return ctrl.Result{}, true, nil
+}
+
+// certificateExpansionToken derives the ACM idempotency token of an expanded certificate from
+// the names it covers, so a retry returns the pending certificate
+func certificateExpansionToken(ghr *gatewayv1alpha1.GatewayHostnameRequest, names []string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%d/%s", ghr.UID, ghr.Status.CertificateReplacements, strings.Join(names, ","))))
+	return hex.EncodeToString(sum[:16])
+}
+
+// reconcileCertificateExpansion re-issues the certificate of a host request with the hostnames
+// of the requests that joined it. The expanded certificate is validated while the current one
+// keeps serving, then replaces it on the listener in one update. The replaced certificate is
+// deleted once no request and no load balancer uses it. Returns when to look again, or 0.
+func (r *GatewayHostnameRequestReconciler) reconcileCertificateExpansion(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (time.Duration, error) {
+	if !canHostCertificate(ghr) {
+		return 0, nil
+	}
+	logger := log.FromContext(ctx)
+
+	if ghr.Status.RetiredCertificateArn != "" {
+		retired, err := r.retireCertificate(ctx, ghr, ghr.Status.RetiredCertificateArn)
+		if err != nil || !retired {
+			return certificateExpansionPollInterval, err
+		}
+		ghr.Status.RetiredCertificateArn = ""
+	}
+
+	if ghr.Status.PendingCertificateArn == "" {
+		members, err := r.certificateMembers(ctx, ghr)
+		if err != nil {
+			return 0, err
+		}
+		missing := slices.DeleteFunc(slices.Clone(members), func(name string) bool {
+			return slices.Contains(ghr.Status.CertificateSANs, name)
+		})
+		if len(missing) == 0 {
+			return 0, nil
+		}
+		token := certificateExpansionToken(ghr, members)
+		arn := r.adoptJournaled(ctx, ghr, JournalCertificateExpansion, token)
+		if arn == "" {
+			awsCtx, cancel := withAWSTimeout(ctx)
+			arn, err = r.ACMClient.RequestCertificateWithSANs(awsCtx, CanonicalHostname(ghr.Spec.Hostname), members, token, r.certificateTags(ghr))
+			cancel()
+			if err != nil {
+				return 0, fmt.Errorf("failed to request expanded certificate: %w", err)
+			}
+		}
+		ghr.Status.PendingCertificateArn = arn
+		r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "CertificateExpansionRequested",
+			"Re-issuing the certificate with %s (%s)", strings.Join(missing, ", "), arn)
+		if err := r.updateStatusJournaled(ctx, ghr, JournalCertificateExpansion, token, arn); err != nil {
+			return 0, err
+		}
+	}
+
+	pending := ghr.Status.PendingCertificateArn
+	if err := r.ensureCertificateValidationRecords(ctx, ghr, pending); err != nil {
+		if errors.Is(err, ErrValidationRecordsNotReady) {
+			return certificateExpansionPollInterval, nil
+		}
+		return 0, err
+	}
+	awsCtx, cancel := withAWSTimeout(ctx)
+	details, err := r.ACMClient.DescribeCertificate(awsCtx, pending)
+	cancel()
+	if err != nil {
+		return 0, fmt.Errorf("failed to describe expanded certificate: %w", err)
+	}
+	switch details.Status {
+	case "ISSUED":
+	case "FAILED", "VALIDATION_TIMED_OUT", "REVOKED":
+		// The next reconcile requests it again; the current certificate keeps serving
+		r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "CertificateExpansionFailed", "Expanded certificate %s is %s", pending, details.Status)
+		ghr.Status.PendingCertificateArn = ""
+		ghr.Status.CertificateReplacements++
+		if _, err := r.retireCertificate(ctx, ghr, pending); err != nil {
+			logger.Error(err, "Failed to delete failed expanded certificate", "arn", pending)
+		}
+		return certificateExpansionPollInterval, nil
+	default:
+		return certificateExpansionPollInterval, nil
+	}
+
+	// Swap the certificate; the Gateway sync that follows puts it on the listener while the
+	// members still hold the replaced one, so both are served until they have switched too
+	hostname := CanonicalHostname(ghr.Spec.Hostname)
+	var sans []string
+	for _, name := range details.SubjectAlternativeNames {
+		if name = CanonicalHostname(name); name != hostname {
+			sans = append(sans, name)
+		}
+	}
+	sort.Strings(sans)
+	ghr.Status.RetiredCertificateArn = ghr.Status.CertificateArn
+	ghr.Status.CertificateArn = pending
+	ghr.Status.CertificateSANs = sans
+	ghr.Status.PendingCertificateArn = ""
+	logger.Info("Swapped in expanded certificate", "arn", pending, "retired", ghr.Status.RetiredCertificateArn, "names", sans)
+	r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "CertificateExpanded", "Serving %s, which also covers %s", pending, strings.Join(sans, ", "))
+	return certificateExpansionPollInterval, nil
+}
+
+// retireCertificate deletes a certificate the request no longer serves, once no other request
+// and no load balancer uses it. Returns true once it is gone.
+func (r *GatewayHostnameRequestReconciler) retireCertificate(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, certArn string) (bool, error) {
+	if shared, err := r.certificateShared(ctx, ghr, certArn); err != nil || shared {
+		return false, err
+	}
+	if inUse, err := r.isCertificateInUse(ctx, certArn); err != nil || inUse {
+		return false, err
+	}
+	awsCtx, cancel := withAWSTimeout(ctx)
+	defer cancel()
+	if _, err := r.deleteCertificate(awsCtx, ghr, certArn); err != nil {
+		return false, err
+	}
+	// Its validation records stay: ACM validates the same names with the same records
+	return true, nil
+}
+
+// discardExpansionCertificates deletes the expanded certificate awaiting issuance and the one it
+// replaced, unless another request uses them, when the request is deleted or re-provisioned
+func (r *GatewayHostnameRequestReconciler) discardExpansionCertificates(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+	for _, certArn := range []string{ghr.Status.PendingCertificateArn, ghr.Status.RetiredCertificateArn} {
+		if shared, err := r.certificateShared(ctx, ghr, certArn); certArn == "" || err != nil || shared {
+			continue
+		}
+		awsCtx, cancel := withAWSTimeout(ctx)
+		_, err := r.deleteCertificate(awsCtx, ghr, certArn)
+		cancel()
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Failed to delete certificate of expansion", "arn", certArn)
+		}
+	}
+	ghr.Status.PendingCertificateArn = ""
+	ghr.Status.RetiredCertificateArn = ""
+}
+
+// certificateShared reports whether a request other than ghr, not being deleted, serves the
+// certificate or is expanding or retiring it. Shared certificates and their validation records
+// are left to the last request using them. Only certificates of expansions can be shared.
+func (r *GatewayHostnameRequestReconciler) certificateShared(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, certArn string) (bool, error) {
+	if certArn == "" || (!r.ExpandCertificates && ghr.Status.CertificateHost == "" && len(ghr.Status.CertificateSANs) == 0) {
+		return false, nil
+	}
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList, client.InNamespace(ghr.Namespace)); err != nil {
+		return false, fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+	for _, other := range ghrList.Items {
+		if other.Name == ghr.Name || !other.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if other.Status.CertificateArn == certArn || other.Status.PendingCertificateArn == certArn || other.Status.RetiredCertificateArn == certArn {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// requestsForCertificateHost maps a request to the request whose certificate it joined, and to
+// the requests that joined its certificate, so expansions and swaps are picked up right away
+func (r *GatewayHostnameRequestReconciler) requestsForCertificateHost(ctx context.Context, obj client.Object) []reconcile.Request {
+	ghr, ok := obj.(*gatewayv1alpha1.GatewayHostnameRequest)
+	if !ok || !r.ExpandCertificates {
+		return nil
+	}
+	var requests []reconcile.Request
+	if ghr.Status.CertificateHost != "" {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: ghr.Namespace, Name: ghr.Status.CertificateHost}})
+	}
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList, client.InNamespace(ghr.Namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list requests sharing a certificate", "request", ghr.Name)
+		return requests
+	}
+	for _, other := range ghrList.Items {
+		if other.Status.CertificateHost == ghr.Name {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&other)})
+		}
+	}
+	return requests
+}
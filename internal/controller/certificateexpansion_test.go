@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+// expansionRequest returns a request in team-a; with a certificate ARN it is issued
+func expansionRequest(name, hostname, certArn string) *gatewayv1alpha1.GatewayHostnameRequest {
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a", UID: types.UID("uid-" + name)},
+		Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{ZoneId: "Z123", Hostname: hostname},
+		Status:     gatewayv1alpha1.GatewayHostnameRequestStatus{CertificateArn: certArn},
+	}
+	if certArn != "" {
+		conditions.Set(&ghr.Status.Conditions, ConditionTypeCertificateIssued, metav1.ConditionTrue, conditions.ReasonIssued, "Certificate issued by ACM", 1)
+	}
+	return ghr
+}
+
+func expansionReconciler(acmClient *aws.MockACMClient, objs ...client.Object) *GatewayHostnameRequestReconciler {
+	return &GatewayHostnameRequestReconciler{
+		Client:             fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(objs...).WithStatusSubresource(objs...).Build(),
+		Recorder:           record.NewFakeRecorder(20),
+		ACMClient:          acmClient,
+		Route53Client:      aws.NewMockRoute53Client(),
+		ExpandCertificates: true,
+	}
+}
+
+func TestCertificateExpansion_JoinAndSwap(t *testing.T) {
+	ctx := context.Background()
+	acmClient := aws.NewMockACMClient()
+	oldArn, err := acmClient.RequestCertificate(ctx, "example.com", "", nil)
+	require.NoError(t, err)
+	acmClient.Certificates[oldArn].Status = "ISSUED"
+
+	host := expansionRequest("apex", "example.com", oldArn)
+	member := expansionRequest("api", "api.example.com", "")
+	r := expansionReconciler(acmClient, host, member)
+
+	// The member joins and waits for the expanded certificate
+	result, done, err := r.reconcileSharedCertificate(ctx, member)
+	require.NoError(t, err)
+	assert.False(t, done)
+	assert.Equal(t, certificateExpansionPollInterval, result.RequeueAfter)
+	assert.Equal(t, "apex", member.Status.CertificateHost)
+	assert.True(t, conditions.HasReason(member.Status.Conditions, ConditionTypeCertificateIssued, metav1.ConditionFalse, conditions.ReasonPendingExpansion))
+
+	// The host requests it; the current certificate keeps serving until it is issued
+	_, err = r.reconcileCertificateExpansion(ctx, host)
+	require.NoError(t, err)
+	pending := host.Status.PendingCertificateArn
+	require.NotEmpty(t, pending)
+	assert.Equal(t, oldArn, host.Status.CertificateArn)
+	assert.Equal(t, []string{"example.com", "api.example.com"}, acmClient.Certificates[pending].SubjectAlternativeNames)
+	validation, err := r.Route53Client.GetRecord(ctx, "Z123", "_acm-validation.api.example.com", "CNAME")
+	require.NoError(t, err)
+	assert.NotNil(t, validation)
+
+	// Once issued it is swapped in and the replaced certificate retired
+	acmClient.Certificates[pending].Status = "ISSUED"
+	_, err = r.reconcileCertificateExpansion(ctx, host)
+	require.NoError(t, err)
+	assert.Equal(t, pending, host.Status.CertificateArn)
+	assert.Equal(t, oldArn, host.Status.RetiredCertificateArn)
+	assert.Equal(t, []string{"api.example.com"}, host.Status.CertificateSANs)
+	require.NoError(t, r.Status().Update(ctx, host))
+
+	_, done, err = r.reconcileSharedCertificate(ctx, member)
+	require.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, pending, member.Status.CertificateArn)
+	assert.True(t, conditions.IsTrue(member.Status.Conditions, ConditionTypeCertificateIssued))
+
+	// The replaced certificate is deleted once no load balancer uses it
+	acmClient.SetCertificateInUse(oldArn, []string{"listener"})
+	_, err = r.reconcileCertificateExpansion(ctx, host)
+	require.NoError(t, err)
+	assert.Contains(t, acmClient.Certificates, oldArn)
+	acmClient.ClearCertificateInUse(oldArn)
+	_, err = r.reconcileCertificateExpansion(ctx, host)
+	require.NoError(t, err)
+	assert.NotContains(t, acmClient.Certificates, oldArn)
+	assert.Empty(t, host.Status.RetiredCertificateArn)
+}
+
+func TestCertificateExpansion_SharedCertificateOutlivesHost(t *testing.T) {
+	ctx := context.Background()
+	acmClient := aws.NewMockACMClient()
+	arn, err := acmClient.RequestCertificateWithSANs(ctx, "example.com", []string{"api.example.com"}, "", nil)
+	require.NoError(t, err)
+
+	host := expansionRequest("apex", "example.com", arn)
+	host.Status.CertificateSANs = []string{"api.example.com"}
+	member := expansionRequest("api", "api.example.com", arn)
+	member.Status.CertificateHost = "apex"
+	r := expansionReconciler(acmClient, host, member)
+
+	shared, err := r.certificateShared(ctx, host, arn)
+	require.NoError(t, err)
+	assert.True(t, shared, "the member serves the host's certificate")
+
+	// Without the host the member keeps the certificate as its own
+	require.NoError(t, r.Delete(ctx, host))
+	_, done, err := r.reconcileSharedCertificate(ctx, member)
+	require.NoError(t, err)
+	assert.False(t, done)
+	assert.Empty(t, member.Status.CertificateHost)
+	assert.Equal(t, arn, member.Status.CertificateArn)
+	assert.False(t, joinsCertificate(member))
+}
+
+func TestCertificateHostFor(t *testing.T) {
+	ctx := context.Background()
+	apex := expansionRequest("apex", "example.com", "arn-apex")
+	shop := expansionRequest("shop", "shop.example.com", "arn-shop")
+	wildcard := expansionRequest("wildcard", "*.example.com", "arn-wildcard")
+	otherZone := expansionRequest("other-zone", "eu.shop.example.com", "arn-other")
+	otherZone.Spec.ZoneId = "Z999"
+	pending := expansionRequest("pending", "api.shop.example.com", "arn-pending")
+	pending.Status.Conditions = nil
+	r := expansionReconciler(aws.NewMockACMClient(), apex, shop, wildcard, otherZone, pending)
+
+	host, err := r.certificateHostFor(ctx, expansionRequest("new", "cart.eu.shop.example.com", ""))
+	require.NoError(t, err)
+	require.NotNil(t, host)
+	assert.Equal(t, "shop", host.Name, "the closest parent with an issued certificate in the zone")
+
+	host, err = r.certificateHostFor(ctx, expansionRequest("new", "example.org", ""))
+	require.NoError(t, err)
+	assert.Nil(t, host)
+}
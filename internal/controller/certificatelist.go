@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// AnnotationCertificates lists the certificates the Gateway's HTTPS listener serves as JSON, in
+// listener order: the default certificate first, then the SNI certificates. Lets scanners audit
+// which certificates are live on which ALB without AWS access.
+const AnnotationCertificates = "gateway.opendi.com/certificates"
+
+// ServedCertificate is an entry of AnnotationCertificates
+type ServedCertificate struct {
+	Arn string `json:"arn"`
+
+	// Default is set on the certificate served to clients without SNI or an unknown name
+	Default bool `json:"default,omitempty"`
+
+	// Hostnames are the requests' hostnames the certificate was issued for
+	Hostnames []string `json:"hostnames,omitempty"`
+}
+
+// listenerCertificates orders certificate ARNs the way the HTTPS listener gets them: sorted,
+// so the default certificate (the first) is the same on every reconcile
+func listenerCertificates(arns []string) []string {
+	sorted := slices.Clone(arns)
+	sort.Strings(sorted)
+	return slices.Compact(sorted)
+}
+
+// syncCertificateList records the certificates served from arns in AnnotationCertificates on a
+// Gateway in memory, with the hostnames of the requests holding them. Returns true if the
+// annotation changed.
+func (r *GatewayHostnameRequestReconciler) syncCertificateList(ctx context.Context, gw *gwapiv1.Gateway, arns []string) (bool, error) {
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList); err != nil {
+		return false, fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+	hostnames := map[string][]string{}
+	for _, ghr := range ghrList.Items {
+		if ghr.Status.CertificateArn != "" && !slices.Contains(hostnames[ghr.Status.CertificateArn], ghr.Spec.Hostname) {
+			hostnames[ghr.Status.CertificateArn] = append(hostnames[ghr.Status.CertificateArn], ghr.Spec.Hostname)
+		}
+	}
+
+	served := []ServedCertificate{}
+	for i, arn := range listenerCertificates(arns) {
+		names := hostnames[arn]
+		sort.Strings(names)
+		served = append(served, ServedCertificate{Arn: arn, Default: i == 0, Hostnames: names})
+	}
+	data, err := json.Marshal(served)
+	if err != nil {
+		return false, err
+	}
+	value := string(data)
+
+	if current, ok := gw.Annotations[AnnotationCertificates]; ok && current == value {
+		return false, nil
+	}
+	if gw.Annotations == nil {
+		gw.Annotations = make(map[string]string)
+	}
+	gw.Annotations[AnnotationCertificates] = value
+	return true, nil
+}
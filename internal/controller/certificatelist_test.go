@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestSyncCertificateList(t *testing.T) {
+	ctx := context.Background()
+	gw := adminGateway("gw-01", "3", 0)
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(gw).Build()
+	r := &GatewayHostnameRequestReconciler{Client: c, Recorder: record.NewFakeRecorder(10)}
+	var arns []string
+	for _, i := range []int{3, 1, 2} {
+		ghr := ghrOnGateway(i, "gw-01")
+		require.NoError(t, c.Create(ctx, ghr))
+		arns = append(arns, ghr.Status.CertificateArn)
+	}
+
+	changed, err := r.syncCertificateList(ctx, gw, arns)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	var served []ServedCertificate
+	require.NoError(t, json.Unmarshal([]byte(gw.Annotations[AnnotationCertificates]), &served))
+	assert.Equal(t, []ServedCertificate{
+		{Arn: "arn:aws:acm:eu-west-1:123456789012:certificate/cert-1", Default: true, Hostnames: []string{"app1.opendi.com"}},
+		{Arn: "arn:aws:acm:eu-west-1:123456789012:certificate/cert-2", Hostnames: []string{"app2.opendi.com"}},
+		{Arn: "arn:aws:acm:eu-west-1:123456789012:certificate/cert-3", Hostnames: []string{"app3.opendi.com"}},
+	}, served, "sorted like the listener, default first")
+
+	changed, err = r.syncCertificateList(ctx, gw, []string{arns[2], arns[0], arns[1]})
+	require.NoError(t, err)
+	assert.False(t, changed, "the order of the input doesn't matter")
+
+	// A request leaving the Gateway is dropped from the list
+	require.NoError(t, c.Update(ctx, gw))
+	leaving := ghrOnGateway(1, "gw-01")
+	require.NoError(t, r.removeFromGatewaySpec(ctx, gw, leaving))
+	var updated gwapiv1.Gateway
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "edge", Name: "gw-01"}, &updated))
+	require.NoError(t, json.Unmarshal([]byte(updated.Annotations[AnnotationCertificates]), &served))
+	require.Len(t, served, 2)
+	assert.True(t, served[0].Default)
+	assert.Equal(t, []string{"app2.opendi.com"}, served[0].Hostnames)
+}
@@ -0,0 +1,281 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+// Claim stores
+const (
+	// ClaimStoreCRD keeps claims as DomainClaim objects in the cluster
+	ClaimStoreCRD = "crd"
+
+	// ClaimStoreDynamoDB keeps claims in a DynamoDB table shared with other clusters and systems
+	ClaimStoreDynamoDB = "dynamodb"
+)
+
+// ClaimStore keeps the claims that make hostnames unique. Claims are DomainClaims whatever the
+// store; stores other than the CRD only fill in their name, spec and claimedAt.
+type ClaimStore interface {
+	// Get returns the claim with the name, or nil if there is none
+	Get(ctx context.Context, name string) (*gatewayv1alpha1.DomainClaim, error)
+
+	// List returns all claims, Hostname and Subtree claims alike
+	List(ctx context.Context) ([]gatewayv1alpha1.DomainClaim, error)
+
+	// Create stores the claim unless one with its name exists, and reports whether it did
+	Create(ctx context.Context, claim *gatewayv1alpha1.DomainClaim) (bool, error)
+
+	// Update replaces the claim as it was read (previous) with claim, unless it changed in the
+	// meantime, and reports whether it did. Owners of shared claims join and leave this way.
+	Update(ctx context.Context, previous, claim *gatewayv1alpha1.DomainClaim) (bool, error)
+
+	// Delete removes the claim if it still has the same owner. A missing claim is not an error.
+	Delete(ctx context.Context, claim *gatewayv1alpha1.DomainClaim) error
+
+	// Local reports whether the claim was taken in this cluster, so its owner can be looked up
+	Local(claim *gatewayv1alpha1.DomainClaim) bool
+
+	// LocalOwner reports whether an owner of a shared claim is a request in this cluster
+	LocalOwner(owner gatewayv1alpha1.DomainClaimOwner) bool
+}
+
+// claimStore returns the configured ClaimStore, defaulting to DomainClaim objects
+func (r *GatewayHostnameRequestReconciler) claimStore() ClaimStore {
+	if r.ClaimStore != nil {
+		return r.ClaimStore
+	}
+	return &CRDClaimStore{Client: r.Client}
+}
+
+// CRDClaimStore keeps claims as DomainClaim objects
+type CRDClaimStore struct {
+	Client client.Client
+}
+
+func (s *CRDClaimStore) Get(ctx context.Context, name string) (*gatewayv1alpha1.DomainClaim, error) {
+	var claim gatewayv1alpha1.DomainClaim
+	if err := s.Client.Get(ctx, types.NamespacedName{Name: name}, &claim); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get domain claim: %w", err)
+	}
+	return &claim, nil
+}
+
+func (s *CRDClaimStore) List(ctx context.Context) ([]gatewayv1alpha1.DomainClaim, error) {
+	var claims gatewayv1alpha1.DomainClaimList
+	if err := s.Client.List(ctx, &claims); err != nil {
+		return nil, fmt.Errorf("failed to list domain claims: %w", err)
+	}
+	return claims.Items, nil
+}
+
+func (s *CRDClaimStore) Create(ctx context.Context, claim *gatewayv1alpha1.DomainClaim) (bool, error) {
+	if err := s.Client.Create(ctx, claim); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to create domain claim: %w", err)
+	}
+	return true, nil
+}
+
+func (s *CRDClaimStore) Update(ctx context.Context, previous, claim *gatewayv1alpha1.DomainClaim) (bool, error) {
+	claim.ResourceVersion = previous.ResourceVersion
+	if err := s.Client.Update(ctx, claim); err != nil {
+		if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to update domain claim: %w", err)
+	}
+	return true, nil
+}
+
+func (s *CRDClaimStore) Delete(ctx context.Context, claim *gatewayv1alpha1.DomainClaim) error {
+	return client.IgnoreNotFound(s.Client.Delete(ctx, claim))
+}
+
+func (s *CRDClaimStore) Local(claim *gatewayv1alpha1.DomainClaim) bool {
+	return true
+}
+
+func (s *CRDClaimStore) LocalOwner(owner gatewayv1alpha1.DomainClaimOwner) bool {
+	return true
+}
+
+// Attributes of the claim items in a DynamoDB claim table, keyed by the claim name in
+// aws.ItemKeyAttribute. Systems outside Kubernetes take a hostname by writing an item with
+// their own cluster, a scope of Hostname, and an owner they recognize.
+const (
+	claimItemZoneId         = "zoneId"
+	claimItemHostname       = "hostname"
+	claimItemEnvironment    = "environment"
+	claimItemScope          = "scope"
+	claimItemOwnerNamespace = "ownerNamespace"
+	claimItemOwnerName      = "ownerName"
+	claimItemOwnerUID       = "ownerUid"
+	claimItemClaimedAt      = "claimedAt"
+	claimItemCluster        = "cluster"
+	// claimItemShared is "true" for shared claims, whose owners are listed in claimItemOwners
+	// as a JSON array of objects with namespace, name, uid, cluster and setIdentifier
+	claimItemShared = "shared"
+	claimItemOwners = "owners"
+	// claimItemVersion counts the writes of a claim, so updates only apply to the claim as read
+	claimItemVersion = "version"
+)
+
+// LabelClaimCluster is set on claims read from a shared claim store to the cluster or system
+// that took them
+const LabelClaimCluster = "gateway.opendi.com/claim-cluster"
+
+// DynamoDBClaimStore keeps claims in a DynamoDB table, so hostnames are unique across all
+// clusters and systems sharing the table. Writes are conditional, so two clusters can't take
+// the same claim, and a cluster only deletes claims of owners it holds.
+type DynamoDBClaimStore struct {
+	Table aws.ItemTable
+
+	// Cluster names the cluster in the claims it takes
+	Cluster string
+}
+
+func (s *DynamoDBClaimStore) claimFromItem(item map[string]string) gatewayv1alpha1.DomainClaim {
+	claim := gatewayv1alpha1.DomainClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: item[aws.ItemKeyAttribute]},
+		Spec: gatewayv1alpha1.DomainClaimSpec{
+			ZoneId:      item[claimItemZoneId],
+			Hostname:    item[claimItemHostname],
+			Environment: item[claimItemEnvironment],
+			Scope:       item[claimItemScope],
+			OwnerRef: gatewayv1alpha1.DomainClaimOwnerRef{
+				Namespace: item[claimItemOwnerNamespace],
+				Name:      item[claimItemOwnerName],
+				UID:       item[claimItemOwnerUID],
+			},
+		},
+	}
+	if claimedAt, err := time.Parse(time.RFC3339, item[claimItemClaimedAt]); err == nil {
+		claim.Status.ClaimedAt = &metav1.Time{Time: claimedAt}
+	}
+	if cluster := item[claimItemCluster]; cluster != "" {
+		claim.Labels = map[string]string{LabelClaimCluster: cluster}
+	}
+	claim.ResourceVersion = item[claimItemVersion]
+	if item[claimItemShared] == "true" {
+		claim.Spec.Shared = true
+		// Owners that can't be read leave a claim no other request can join
+		_ = json.Unmarshal([]byte(item[claimItemOwners]), &claim.Spec.Owners)
+	}
+	return claim
+}
+
+// itemFromClaim returns the item of a claim at a version. Owners without a cluster are this
+// cluster's; the claim's cluster is the one of its first owner.
+func (s *DynamoDBClaimStore) itemFromClaim(claim *gatewayv1alpha1.DomainClaim, version int) (map[string]string, error) {
+	item := map[string]string{
+		aws.ItemKeyAttribute:    claim.Name,
+		claimItemZoneId:         claim.Spec.ZoneId,
+		claimItemHostname:       claim.Spec.Hostname,
+		claimItemOwnerNamespace: claim.Spec.OwnerRef.Namespace,
+		claimItemOwnerName:      claim.Spec.OwnerRef.Name,
+		claimItemOwnerUID:       claim.Spec.OwnerRef.UID,
+		claimItemCluster:        s.Cluster,
+		claimItemVersion:        strconv.Itoa(version),
+	}
+	if cluster := claim.Labels[LabelClaimCluster]; cluster != "" {
+		item[claimItemCluster] = cluster
+	}
+	if claim.Spec.Environment != "" {
+		item[claimItemEnvironment] = claim.Spec.Environment
+	}
+	if claim.Spec.Scope != "" {
+		item[claimItemScope] = claim.Spec.Scope
+	}
+	if claim.Status.ClaimedAt != nil {
+		item[claimItemClaimedAt] = claim.Status.ClaimedAt.UTC().Format(time.RFC3339)
+	}
+	if claim.Spec.Shared {
+		for i := range claim.Spec.Owners {
+			if claim.Spec.Owners[i].Cluster == "" {
+				claim.Spec.Owners[i].Cluster = s.Cluster
+			}
+		}
+		owners, err := json.Marshal(claim.Spec.Owners)
+		if err != nil {
+			return nil, err
+		}
+		item[claimItemShared] = "true"
+		item[claimItemOwners] = string(owners)
+		if len(claim.Spec.Owners) > 0 {
+			item[claimItemCluster] = claim.Spec.Owners[0].Cluster
+		}
+	}
+	return item, nil
+}
+
+func (s *DynamoDBClaimStore) Get(ctx context.Context, name string) (*gatewayv1alpha1.DomainClaim, error) {
+	item, err := s.Table.GetItem(ctx, name)
+	if err != nil || item == nil {
+		return nil, err
+	}
+	claim := s.claimFromItem(item)
+	return &claim, nil
+}
+
+func (s *DynamoDBClaimStore) List(ctx context.Context) ([]gatewayv1alpha1.DomainClaim, error) {
+	items, err := s.Table.ScanItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims := make([]gatewayv1alpha1.DomainClaim, 0, len(items))
+	for _, item := range items {
+		claims = append(claims, s.claimFromItem(item))
+	}
+	return claims, nil
+}
+
+func (s *DynamoDBClaimStore) Create(ctx context.Context, claim *gatewayv1alpha1.DomainClaim) (bool, error) {
+	item, err := s.itemFromClaim(claim, 1)
+	if err != nil {
+		return false, err
+	}
+	return s.Table.PutItemIfAbsent(ctx, item)
+}
+
+func (s *DynamoDBClaimStore) Update(ctx context.Context, previous, claim *gatewayv1alpha1.DomainClaim) (bool, error) {
+	// Items written without a version are at version 0
+	version, _ := strconv.Atoi(previous.ResourceVersion)
+	item, err := s.itemFromClaim(claim, version+1)
+	if err != nil {
+		return false, err
+	}
+	return s.Table.PutItemIfUnchanged(ctx, item, map[string]string{claimItemVersion: previous.ResourceVersion})
+}
+
+func (s *DynamoDBClaimStore) Delete(ctx context.Context, claim *gatewayv1alpha1.DomainClaim) error {
+	return s.Table.DeleteItem(ctx, claim.Name, map[string]string{
+		claimItemOwnerNamespace: claim.Spec.OwnerRef.Namespace,
+		claimItemOwnerName:      claim.Spec.OwnerRef.Name,
+		claimItemOwnerUID:       claim.Spec.OwnerRef.UID,
+	})
+}
+
+func (s *DynamoDBClaimStore) Local(claim *gatewayv1alpha1.DomainClaim) bool {
+	return claim.Labels[LabelClaimCluster] == s.Cluster
+}
+
+func (s *DynamoDBClaimStore) LocalOwner(owner gatewayv1alpha1.DomainClaimOwner) bool {
+	return owner.Cluster == s.Cluster
+}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+// clusterReconciler returns a reconciler of a cluster sharing the claim table with others
+func clusterReconciler(table aws.ItemTable, cluster string) *GatewayHostnameRequestReconciler {
+	scheme := getTestScheme()
+	return &GatewayHostnameRequestReconciler{
+		Client:     fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Scheme:     scheme,
+		Recorder:   record.NewFakeRecorder(10),
+		ClaimStore: &DynamoDBClaimStore{Table: table, Cluster: cluster},
+	}
+}
+
+func TestDynamoDBClaimStore_UniqueAcrossClusters(t *testing.T) {
+	ctx := context.Background()
+	table := aws.NewMockItemTable()
+	east, west := clusterReconciler(table, "east"), clusterReconciler(table, "west")
+
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team-a", UID: types.UID("uid-east")},
+		Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{ZoneId: "Z123", Hostname: "app.example.com"},
+	}
+	claimed, err := east.ensureDomainClaim(ctx, ghr)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	claimed, err = east.ensureDomainClaim(ctx, ghr)
+	require.NoError(t, err)
+	assert.True(t, claimed, "the request's own claim")
+
+	item := table.Items["z123-app.example.com"]
+	assert.Equal(t, "east", item["cluster"])
+	assert.Equal(t, "uid-east", item["ownerUid"])
+	assert.NotEmpty(t, item["claimedAt"])
+
+	other := ghr.DeepCopy()
+	other.UID = "uid-west"
+	claimed, err = west.ensureDomainClaim(ctx, other)
+	require.NoError(t, err)
+	assert.False(t, claimed, "taken by the other cluster")
+	other.Spec.Hostname = "App.Example.com."
+	claimed, err = west.ensureDomainClaim(ctx, other)
+	require.NoError(t, err)
+	assert.False(t, claimed, "another spelling of the same hostname")
+
+	// Releasing a claim only removes the requester's own
+	require.NoError(t, west.deleteDomainClaim(ctx, other))
+	assert.Contains(t, table.Items, "z123-app.example.com")
+	require.NoError(t, east.deleteDomainClaim(ctx, ghr))
+	assert.Empty(t, table.Items)
+}
+
+func TestDynamoDBClaimStore_SweepSkipsOtherClusters(t *testing.T) {
+	table := aws.NewMockItemTable()
+	for name, cluster := range map[string]string{"z123-east.example.com": "east", "z123-west.example.com": "west"} {
+		table.Items[name] = map[string]string{aws.ItemKeyAttribute: name, "zoneId": "Z123", "cluster": cluster,
+			"ownerNamespace": "team-a", "ownerName": "gone", "ownerUid": "uid-" + cluster}
+	}
+	a := newTestAdmin()
+	a.Reconciler.ClaimStore = &DynamoDBClaimStore{Table: table, Cluster: "east"}
+
+	result, err := a.SweepOrphans(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, result.Touched, "DomainClaim/z123-east.example.com")
+	assert.NotContains(t, table.Items, "z123-east.example.com")
+	assert.Contains(t, table.Items, "z123-west.example.com", "its owner is in another cluster")
+}
+
+func TestInventory_ClaimStore(t *testing.T) {
+	table := aws.NewMockItemTable()
+	table.Items["z123-legacy.example.com"] = map[string]string{aws.ItemKeyAttribute: "z123-legacy.example.com",
+		"zoneId": "Z123", "hostname": "legacy.example.com", "cluster": "datacenter", "ownerNamespace": "billing", "ownerName": "legacy"}
+	i := &Inventory{
+		Client: fake.NewClientBuilder().WithScheme(getTestScheme()).Build(),
+		Claims: &DynamoDBClaimStore{Table: table, Cluster: "east"},
+	}
+
+	hostnames, err := i.Collect(context.Background())
+	require.NoError(t, err)
+	require.Len(t, hostnames, 1)
+	assert.Equal(t, "legacy.example.com", hostnames[0].Hostname)
+	require.Len(t, hostnames[0].Claims, 1)
+	assert.Equal(t, "billing", hostnames[0].Claims[0].Namespace)
+}
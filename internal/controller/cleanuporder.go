@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// Cleanup orders: which of the DNS records and the certificate a deletion removes first
+const (
+	// CleanupOrderDNSFirst deletes the DNS records, then detaches and deletes the certificate,
+	// so clients stop resolving the hostname while the ALB still serves it
+	CleanupOrderDNSFirst = "dns-first"
+
+	// CleanupOrderCertificateFirst detaches the certificate and waits until the ALB released
+	// it, then deletes the DNS records and the certificate, so the DNS records stay until the
+	// ALB no longer serves the hostname
+	CleanupOrderCertificateFirst = "certificate-first"
+)
+
+// cleanupOrder returns the cleanup order of new deletions
+func (r *GatewayHostnameRequestReconciler) cleanupOrder() string {
+	if r.CleanupOrder == "" {
+		return CleanupOrderDNSFirst
+	}
+	return r.CleanupOrder
+}
+
+// certificateFirst reports whether the request's deletion detaches the certificate before
+// deleting the DNS records
+func certificateFirst(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	return ghr.Status.CleanupOrder == CleanupOrderCertificateFirst
+}
+
+// waitDuringDeletion records in the Deleting condition which step the deletion waits at, so
+// the next reconcile continues from there, and requeues after the delay. The condition's
+// transition time marks the start of the wait.
+func (r *GatewayHostnameRequestReconciler) waitDuringDeletion(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, reason conditions.Reason, message string, after time.Duration) (ctrl.Result, error) {
+	order := ghr.Status.CleanupOrder
+	// Re-fetch to get latest resourceVersion before status update.
+	if err := r.Get(ctx, client.ObjectKeyFromObject(ghr), ghr); err != nil {
+		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+	}
+	ghr.Status.CleanupOrder = order
+	meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeDeleting)
+	r.setCondition(ghr, ConditionTypeDeleting, metav1.ConditionTrue, reason, message)
+	if err := r.Status().Update(ctx, ghr); err != nil {
+		// Status update failed — the condition won't be set, so next reconcile
+		// will re-run cleanup (Phase 1). This is safe because all steps are idempotent.
+		log.FromContext(ctx).Error(err, "Failed to record the deletion step, will retry cleanup", "reason", reason)
+	}
+	return ctrl.Result{RequeueAfter: after}, nil
+}
+
+// releaseCertificateFirst runs the first cleanup steps of a certificate-first deletion: it
+// detaches the certificate and releases the Kubernetes resources, while the DNS records keep
+// pointing at the ALB until the ALB released the certificate
+func (r *GatewayHostnameRequestReconciler) releaseCertificateFirst(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	r.releaseRequestResources(ctx, ghr)
+
+	// Certificates shared with other requests stay on the ALB for them
+	if shared, err := r.certificateShared(ctx, ghr, ghr.Status.CertificateArn); ghr.Status.CertificateArn != "" && (err != nil || !shared) {
+		inUse, err := r.isCertificateInUse(ctx, ghr.Status.CertificateArn)
+		if err != nil {
+			logger.Error(err, "Failed to check certificate usage, continuing anyway",
+				"arn", ghr.Status.CertificateArn)
+		} else if inUse {
+			logger.Info("Certificate still in use by ALB, will poll for detachment before deleting the DNS records",
+				"arn", ghr.Status.CertificateArn)
+			return r.waitDuringDeletion(ctx, ghr, conditions.ReasonWaitingForCertDetachment,
+				"Waiting for ALB to detach certificate before deleting the DNS records", 15*time.Second)
+		}
+	}
+	return r.certificateDetached(ctx, ghr)
+}
+
+// certificateDetached continues a certificate-first deletion once the ALB released the
+// certificate, after the step delay
+func (r *GatewayHostnameRequestReconciler) certificateDetached(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (ctrl.Result, error) {
+	if r.CleanupStepDelay > 0 {
+		return r.waitDuringDeletion(ctx, ghr, conditions.ReasonWaitingBetweenSteps,
+			fmt.Sprintf("Certificate detached; deleting the DNS records after %s", r.CleanupStepDelay), r.CleanupStepDelay)
+	}
+	return r.deleteRecordsAndCertificate(ctx, ghr)
+}
+
+// deleteRecordsAndCertificate runs the last cleanup steps of a certificate-first deletion:
+// the DNS records, then the certificate
+func (r *GatewayHostnameRequestReconciler) deleteRecordsAndCertificate(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if err := r.teardownRecords(ctx, ghr); err != nil {
+		if !r.forceDeleteDue(ctx, ghr) {
+			r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "DNSTeardownFailed",
+				"Keeping the finalizer until the DNS records are deleted: %v", err)
+			return ctrl.Result{}, err
+		}
+		// The records and the certificate they validate are left to the orphan sweep
+		return r.abandonDeletion(ctx, ghr, true, err)
+	}
+
+	// Certificates shared with other requests are left to them
+	r.discardExpansionCertificates(ctx, ghr)
+	if shared, err := r.certificateShared(ctx, ghr, ghr.Status.CertificateArn); err == nil && shared {
+		logger.Info("Certificate shared with other requests, leaving it in place", "arn", ghr.Status.CertificateArn)
+		return r.finalizeDeletion(ctx, ghr)
+	}
+	if ghr.Status.CertificateArn != "" {
+		r.deleteRequestCertificate(ctx, ghr)
+	}
+	return r.finalizeDeletion(ctx, ghr)
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+func TestReconcileDelete_CertificateFirst(t *testing.T) {
+	ctx := context.Background()
+	ghr, route53Mock, _ := teardownFixture()
+	ghr.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	acmMock := aws.NewMockACMClient()
+	acmMock.Certificates[teardownCertArn] = &aws.CertificateDetails{Arn: teardownCertArn, Status: "ISSUED"}
+	acmMock.ValidationRecords[teardownCertArn] = []aws.ValidationRecord{{Name: "_test.example.com", Type: "CNAME", Value: "_validation.acm.aws."}}
+	acmMock.InUseBy[teardownCertArn] = []string{"arn:aws:elasticloadbalancing:us-east-1:123456789012:listener/app/gw-01/1/2"}
+	scheme := getTestScheme()
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ghr).WithStatusSubresource(ghr).Build()
+	r := &GatewayHostnameRequestReconciler{
+		Client:        c,
+		Scheme:        scheme,
+		Recorder:      record.NewFakeRecorder(10),
+		Route53Client: route53Mock,
+		ACMClient:     acmMock,
+		CleanupOrder:  CleanupOrderCertificateFirst,
+	}
+
+	result, err := r.reconcileDelete(ctx, ghr)
+	require.NoError(t, err)
+	assert.Equal(t, 15*time.Second, result.RequeueAfter)
+	assert.Equal(t, CleanupOrderCertificateFirst, ghr.Status.CleanupOrder)
+	assert.True(t, conditions.HasReason(ghr.Status.Conditions, ConditionTypeDeleting, metav1.ConditionTrue, conditions.ReasonWaitingForCertDetachment))
+	assert.Len(t, route53Mock.records["ZPRIMARY"], 4, "the records stay while the ALB holds the certificate")
+
+	// Changing the configuration doesn't affect a deletion in progress
+	r.CleanupOrder = CleanupOrderDNSFirst
+	r.CleanupStepDelay = time.Minute
+	delete(acmMock.InUseBy, teardownCertArn)
+	result, err = r.reconcileDelete(ctx, ghr)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, result.RequeueAfter)
+	cond := meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeDeleting)
+	require.NotNil(t, cond)
+	assert.Equal(t, string(conditions.ReasonWaitingBetweenSteps), cond.Reason)
+	assert.Equal(t, "Certificate detached; deleting the DNS records after 1m0s", cond.Message)
+	assert.Len(t, route53Mock.records["ZPRIMARY"], 4)
+
+	// The step delay passed
+	cond.LastTransitionTime = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	_, err = r.reconcileDelete(ctx, ghr)
+	require.NoError(t, err)
+	require.Len(t, route53Mock.records["ZPRIMARY"], 1)
+	assert.Empty(t, route53Mock.records["ZLEGACY"])
+	assert.NotContains(t, acmMock.Certificates, teardownCertArn)
+	assert.NoError(t, client.IgnoreNotFound(c.Get(ctx, client.ObjectKeyFromObject(ghr), ghr)))
+	assert.NotContains(t, ghr.Finalizers, FinalizerName)
+}
+
+func TestReconcileDelete_DNSFirstWaitsBetweenSteps(t *testing.T) {
+	ctx := context.Background()
+	ghr, route53Mock, _ := teardownFixture()
+	ghr.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	acmMock := aws.NewMockACMClient()
+	acmMock.Certificates[teardownCertArn] = &aws.CertificateDetails{Arn: teardownCertArn, Status: "ISSUED"}
+	acmMock.ValidationRecords[teardownCertArn] = []aws.ValidationRecord{{Name: "_test.example.com", Type: "CNAME", Value: "_validation.acm.aws."}}
+	scheme := getTestScheme()
+	r := &GatewayHostnameRequestReconciler{
+		Client:           fake.NewClientBuilder().WithScheme(scheme).WithObjects(ghr).WithStatusSubresource(ghr).Build(),
+		Scheme:           scheme,
+		Recorder:         record.NewFakeRecorder(10),
+		Route53Client:    route53Mock,
+		ACMClient:        acmMock,
+		CleanupStepDelay: 30 * time.Second,
+	}
+
+	result, err := r.reconcileDelete(ctx, ghr)
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, result.RequeueAfter)
+	assert.Equal(t, CleanupOrderDNSFirst, ghr.Status.CleanupOrder)
+	assert.True(t, conditions.HasReason(ghr.Status.Conditions, ConditionTypeDeleting, metav1.ConditionTrue, conditions.ReasonWaitingBetweenSteps))
+	assert.Len(t, route53Mock.records["ZPRIMARY"], 1, "the records go first")
+	assert.Contains(t, acmMock.Certificates, teardownCertArn)
+
+	// Still within the delay
+	result, err = r.reconcileDelete(ctx, ghr)
+	require.NoError(t, err)
+	assert.Positive(t, result.RequeueAfter)
+	assert.LessOrEqual(t, result.RequeueAfter, 30*time.Second)
+	assert.Contains(t, acmMock.Certificates, teardownCertArn)
+}
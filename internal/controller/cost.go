@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// hoursPerMonth is the average number of hours in a month, as used by AWS pricing
+const hoursPerMonth = 730
+
+// Cost components used as the "component" label on costEstimate
+const (
+	CostComponentLoadBalancer = "load_balancer"
+	CostComponentWAF          = "waf"
+	CostComponentRoute53      = "route53"
+)
+
+// CostPricing holds the AWS prices cost estimates are based on, in USD
+type CostPricing struct {
+	// ALBHourly is the charge per ALB-hour
+	ALBHourly float64
+	// LCUHourly is the charge per LCU-hour
+	LCUHourly float64
+	// BaselineLCUs is the number of LCUs assumed per ALB; traffic is not measured
+	BaselineLCUs float64
+	// WAFWebACLMonthly is the monthly charge per web ACL
+	WAFWebACLMonthly float64
+	// Route53ZoneMonthly is the monthly charge per hosted zone
+	Route53ZoneMonthly float64
+}
+
+// DefaultCostPricing are the us-east-1 list prices
+var DefaultCostPricing = CostPricing{
+	ALBHourly:          0.0225,
+	LCUHourly:          0.008,
+	BaselineLCUs:       1,
+	WAFWebACLMonthly:   5,
+	Route53ZoneMonthly: 0.50,
+}
+
+// ParseCostPricing parses a comma-separated list of "<price>=<value>" overrides of
+// DefaultCostPricing, e.g. "albHourly=0.0252,lcuHourly=0.0088". Prices: albHourly, lcuHourly,
+// baselineLCUs, wafWebACLMonthly, route53ZoneMonthly.
+func ParseCostPricing(s string) (CostPricing, error) {
+	pricing := DefaultCostPricing
+	fields := map[string]*float64{
+		"albHourly":          &pricing.ALBHourly,
+		"lcuHourly":          &pricing.LCUHourly,
+		"baselineLCUs":       &pricing.BaselineLCUs,
+		"wafWebACLMonthly":   &pricing.WAFWebACLMonthly,
+		"route53ZoneMonthly": &pricing.Route53ZoneMonthly,
+	}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(entry, "=")
+		field, known := fields[strings.TrimSpace(key)]
+		if !ok || !known {
+			return CostPricing{}, fmt.Errorf("invalid price %q, expected <price>=<value> with price one of "+
+				"albHourly, lcuHourly, baselineLCUs, wafWebACLMonthly, route53ZoneMonthly", entry)
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || v < 0 {
+			return CostPricing{}, fmt.Errorf("invalid value %q for %s", value, key)
+		}
+		*field = v
+	}
+	return pricing, nil
+}
+
+var costEstimate = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "gateway_orchestrator_hostname_estimated_monthly_cost_dollars",
+	Help: "Rough monthly cost estimate in USD of a hostname's share of the managed AWS resources, by component.",
+}, []string{"namespace", "name", "hostname", "component"})
+
+func init() {
+	metrics.Registry.MustRegister(costEstimate)
+}
+
+// updateCostEstimate sets the request's monthly cost estimate in status and as metrics. The ALB
+// and WAF charges are split evenly across the hostnames on the Gateway, the hosted zone charge
+// across the requests in the zone.
+func (r *GatewayHostnameRequestReconciler) updateCostEstimate(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	if r.CostPricing == nil {
+		return nil
+	}
+	pricing := r.CostPricing
+
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList); err != nil {
+		return fmt.Errorf("failed to list requests: %w", err)
+	}
+
+	// Count this request explicitly: the cache may not show its assignment yet
+	sharedGateway, sharedZone := 1, 1
+	for _, other := range ghrList.Items {
+		if other.UID == ghr.UID || !other.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if ghr.Status.AssignedGateway != "" &&
+			other.Status.AssignedGateway == ghr.Status.AssignedGateway &&
+			other.Status.AssignedGatewayNamespace == ghr.Status.AssignedGatewayNamespace {
+			sharedGateway++
+		}
+		if other.Spec.ZoneId == ghr.Spec.ZoneId {
+			sharedZone++
+		}
+	}
+
+	var loadBalancer, waf float64
+	estimate := &gatewayv1alpha1.CostEstimate{}
+	if ghr.Status.AssignedGateway != "" {
+		loadBalancer = (pricing.ALBHourly + pricing.LCUHourly*pricing.BaselineLCUs) * hoursPerMonth / float64(sharedGateway)
+		estimate.LoadBalancer = formatCost(loadBalancer)
+		estimate.SharedWith = int32(sharedGateway)
+		if r.wafArn(ghr) != "" {
+			waf = pricing.WAFWebACLMonthly / float64(sharedGateway)
+			estimate.WAF = formatCost(waf)
+		}
+	}
+	route53 := pricing.Route53ZoneMonthly / float64(sharedZone)
+	estimate.Route53 = formatCost(route53)
+	estimate.Total = formatCost(loadBalancer + waf + route53)
+	ghr.Status.CostEstimate = estimate
+
+	// Drop series with a previous hostname
+	forgetCostEstimate(ghr)
+	costEstimate.WithLabelValues(ghr.Namespace, ghr.Name, ghr.Spec.Hostname, CostComponentLoadBalancer).Set(loadBalancer)
+	costEstimate.WithLabelValues(ghr.Namespace, ghr.Name, ghr.Spec.Hostname, CostComponentWAF).Set(waf)
+	costEstimate.WithLabelValues(ghr.Namespace, ghr.Name, ghr.Spec.Hostname, CostComponentRoute53).Set(route53)
+	return nil
+}
+
+// forgetCostEstimate removes the request's cost metrics
+func forgetCostEstimate(ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+	costEstimate.DeletePartialMatch(prometheus.Labels{"namespace": ghr.Namespace, "name": ghr.Name})
+}
+
+// formatCost formats a USD amount with two fractional digits
+func formatCost(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
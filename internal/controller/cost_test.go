@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestParseCostPricing(t *testing.T) {
+	pricing, err := ParseCostPricing("")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultCostPricing, pricing)
+
+	pricing, err = ParseCostPricing("albHourly=0.0252, baselineLCUs=2")
+	require.NoError(t, err)
+	assert.Equal(t, 0.0252, pricing.ALBHourly)
+	assert.Equal(t, 2.0, pricing.BaselineLCUs)
+	assert.Equal(t, DefaultCostPricing.LCUHourly, pricing.LCUHourly)
+
+	_, err = ParseCostPricing("nlbHourly=1")
+	assert.Error(t, err)
+	_, err = ParseCostPricing("albHourly=-1")
+	assert.Error(t, err)
+}
+
+func TestUpdateCostEstimate(t *testing.T) {
+	ctx := context.Background()
+	app := assignedGHR("app", "app.example.com")
+	app.UID = types.UID("uid-app")
+	app.Spec.ZoneId = "Z123"
+	app.Spec.WafArn = "arn:aws:wafv2:us-east-1:123456789012:regional/webacl/edge/1"
+	other := assignedGHR("other", "other.example.com")
+	other.UID = types.UID("uid-other")
+	other.Spec.ZoneId = "Z123"
+	elsewhere := assignedGHR("elsewhere", "elsewhere.example.org")
+	elsewhere.UID = types.UID("uid-elsewhere")
+	elsewhere.Spec.ZoneId = "Z456"
+	elsewhere.Status.AssignedGateway = "gw-02"
+
+	pricing := DefaultCostPricing
+	r := &GatewayHostnameRequestReconciler{
+		Client:      fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(app, other, elsewhere).Build(),
+		CostPricing: &pricing,
+	}
+
+	require.NoError(t, r.updateCostEstimate(ctx, app))
+	estimate := app.Status.CostEstimate
+	require.NotNil(t, estimate)
+	// (0.0225 + 0.008) * 730 / 2 hostnames on gw-01
+	assert.Equal(t, "11.13", estimate.LoadBalancer)
+	assert.Equal(t, "2.50", estimate.WAF)
+	assert.Equal(t, "0.25", estimate.Route53)
+	assert.Equal(t, "13.88", estimate.Total)
+	assert.Equal(t, int32(2), estimate.SharedWith)
+	assert.InDelta(t, 2.5, testutil.ToFloat64(costEstimate.WithLabelValues("default", "app", "app.example.com", CostComponentWAF)), 0.001)
+
+	// DNS-only requests only pay for their share of the zone
+	elsewhere.Status.AssignedGateway = ""
+	require.NoError(t, r.updateCostEstimate(ctx, elsewhere))
+	assert.Empty(t, elsewhere.Status.CostEstimate.LoadBalancer)
+	assert.Equal(t, "0.50", elsewhere.Status.CostEstimate.Total)
+
+	// Only the series of the remaining request are left
+	forgetCostEstimate(app)
+	assert.Equal(t, 3, testutil.CollectAndCount(costEstimate))
+
+	// Disabled without pricing
+	r.CostPricing = nil
+	other.Status.CostEstimate = nil
+	require.NoError(t, r.updateCostEstimate(ctx, other))
+	assert.Nil(t, other.Status.CostEstimate)
+}
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+// delegationTTL is the TTL of the NS records delegating a sub-zone, as Route53 uses for the
+// NS records of a new zone
+const delegationTTL = 172800
+
+// delegatedZoneRecheckInterval is how often a request whose delegated zone doesn't exist looks
+// for it again
+const delegatedZoneRecheckInterval = 5 * time.Minute
+
+// errDelegatedZoneNotFound means the delegated zone doesn't exist and may not be created
+var errDelegatedZoneNotFound = errors.New("delegated zone not found")
+
+// recordZoneID returns the hosted zone the hostname's records (ALIAS, certificate validation,
+// DNS-01 challenges) live in: the delegated zone once it is set up, spec.zoneId otherwise
+func recordZoneID(ghr *gatewayv1alpha1.GatewayHostnameRequest) string {
+	if ghr.Spec.DelegatedZone != "" && ghr.Status.DelegatedZoneId != "" {
+		return ghr.Status.DelegatedZoneId
+	}
+	return ghr.Spec.ZoneId
+}
+
+// delegatedZoneCallerReference makes CreateHostedZone calls for the same zone under the same
+// parent fail instead of creating a second zone, whichever request makes them. Route53 limits
+// caller references to 128 characters.
+func delegatedZoneCallerReference(parentZoneID, name string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%s", parentZoneID, strings.ToLower(strings.TrimSuffix(name, ".")))))
+	return "gateway-orchestrator-" + hex.EncodeToString(sum[:16])
+}
+
+// ensureDelegatedZone looks up the hosted zone of spec.delegatedZone, creates it if it doesn't
+// exist and ManageDelegatedZones is on, and keeps its NS records in the parent zone. Zones are
+// never deleted by the controller: other records may live in them.
+// Returns errDelegatedZoneNotFound if the zone is missing and may not be created.
+func (r *GatewayHostnameRequestReconciler) ensureDelegatedZone(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	logger := log.FromContext(ctx)
+	name := ghr.Spec.DelegatedZone
+
+	awsCtx, cancel := withAWSTimeout(ctx)
+	defer cancel()
+
+	zone, err := r.Route53Client.GetHostedZoneByName(awsCtx, name)
+	if err != nil {
+		r.failCondition(ghr, ConditionTypeZoneDelegated, conditions.ReasonDelegationFailed, err)
+		return fmt.Errorf("failed to look up delegated zone %s: %w", name, err)
+	}
+	if zone == nil {
+		if !r.ManageDelegatedZones {
+			r.setCondition(ghr, ConditionTypeZoneDelegated, metav1.ConditionFalse, conditions.ReasonZoneNotFound,
+				fmt.Sprintf("No public hosted zone %s exists; create it or start the controller with --manage-delegated-zones", name))
+			return errDelegatedZoneNotFound
+		}
+		zone, err = r.Route53Client.CreateHostedZone(awsCtx, name, delegatedZoneCallerReference(ghr.Spec.ZoneId, name))
+		if err != nil {
+			// Another request for the same zone may have created it since the lookup; its
+			// create shares the caller reference, so this one failed. Use its zone.
+			existing, lookupErr := r.Route53Client.GetHostedZoneByName(awsCtx, name)
+			if lookupErr != nil || existing == nil {
+				r.setCondition(ghr, ConditionTypeZoneDelegated, metav1.ConditionFalse, conditions.ReasonDelegationFailed, err.Error())
+				r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "DelegationFailed", "Failed to create hosted zone %s: %v", name, err)
+				return fmt.Errorf("failed to create delegated zone %s: %w", name, err)
+			}
+			logger.Info("Delegated hosted zone was created concurrently", "zone", name, "zoneId", existing.ID)
+			zone = existing
+		} else {
+			logger.Info("Created delegated hosted zone", "zone", name, "zoneId", zone.ID)
+			r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "ZoneCreated", "Created hosted zone %s (%s)", name, zone.ID)
+		}
+	}
+
+	if err := r.ensureDelegation(awsCtx, ghr.Spec.ZoneId, zone); err != nil {
+		if r.failCondition(ghr, ConditionTypeZoneDelegated, conditions.ReasonDelegationFailed, err) {
+			r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "DelegationFailed", "Failed to delegate zone %s: %v", name, err)
+		}
+		return err
+	}
+
+	ghr.Status.DelegatedZoneId = zone.ID
+	r.setCondition(ghr, ConditionTypeZoneDelegated, metav1.ConditionTrue, conditions.ReasonDelegated,
+		fmt.Sprintf("Zone %s (%s) is delegated from %s", name, zone.ID, ghr.Spec.ZoneId))
+	return nil
+}
+
+// ensureDelegation publishes the zone's name servers as NS records in the parent zone, unless
+// they are already there
+func (r *GatewayHostnameRequestReconciler) ensureDelegation(ctx context.Context, parentZoneID string, zone *aws.HostedZone) error {
+	if len(zone.NameServers) == 0 {
+		return fmt.Errorf("hosted zone %s has no name servers", zone.Name)
+	}
+	nameServers := slices.Clone(zone.NameServers)
+	slices.Sort(nameServers)
+
+	existing, err := r.Route53Client.GetRecord(ctx, parentZoneID, zone.Name, "NS")
+	if err != nil {
+		return fmt.Errorf("failed to look up NS records of %s: %w", zone.Name, err)
+	}
+	if existing != nil {
+		values := existing.Values
+		if len(values) == 0 && existing.Value != "" {
+			values = []string{existing.Value}
+		}
+		current := make([]string, 0, len(values))
+		for _, value := range values {
+			current = append(current, strings.TrimSuffix(value, "."))
+		}
+		slices.Sort(current)
+		if slices.Equal(current, nameServers) {
+			return nil
+		}
+	}
+
+	record := aws.DNSRecord{Name: zone.Name, Type: "NS", TTL: delegationTTL, Values: nameServers, Value: nameServers[0]}
+	if err := r.Route53Client.CreateOrUpdateRecord(ctx, parentZoneID, record); err != nil {
+		return fmt.Errorf("failed to create NS records of %s in zone %s: %w", zone.Name, parentZoneID, err)
+	}
+	log.FromContext(ctx).Info("Delegated hosted zone", "zone", zone.Name, "zoneId", zone.ID, "parentZoneId", parentZoneID)
+	return nil
+}
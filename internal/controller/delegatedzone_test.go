@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+func delegatedGHR() *gatewayv1alpha1.GatewayHostnameRequest {
+	return &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "team-a", UID: "uid-1"},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+			Hostname:      "api.team-a.example.com",
+			ZoneId:        "ZPARENT",
+			DelegatedZone: "team-a.example.com",
+		},
+	}
+}
+
+func TestEnsureDelegatedZone_NotManaged(t *testing.T) {
+	ghr := delegatedGHR()
+	r := &GatewayHostnameRequestReconciler{Route53Client: &MockRoute53Client{}, Recorder: record.NewFakeRecorder(10)}
+
+	err := r.ensureDelegatedZone(context.Background(), ghr)
+	assert.True(t, errors.Is(err, errDelegatedZoneNotFound))
+	assert.True(t, conditions.HasReason(ghr.Status.Conditions, ConditionTypeZoneDelegated, metav1.ConditionFalse, conditions.ReasonZoneNotFound))
+	assert.Empty(t, ghr.Status.DelegatedZoneId)
+	assert.Equal(t, "ZPARENT", recordZoneID(ghr))
+}
+
+func TestEnsureDelegatedZone_CreatesAndDelegates(t *testing.T) {
+	ctx := context.Background()
+	ghr := delegatedGHR()
+	route53Mock := &MockRoute53Client{}
+	r := &GatewayHostnameRequestReconciler{Route53Client: route53Mock, Recorder: record.NewFakeRecorder(10), ManageDelegatedZones: true}
+
+	require.NoError(t, r.ensureDelegatedZone(ctx, ghr))
+	assert.Equal(t, "ZDELEGATED1", ghr.Status.DelegatedZoneId)
+	assert.Equal(t, "ZDELEGATED1", recordZoneID(ghr))
+	assert.Equal(t, []string{"ZDELEGATED1"}, aliasZoneIds(ghr))
+	assert.True(t, conditions.IsTrue(ghr.Status.Conditions, ConditionTypeZoneDelegated))
+
+	ns, err := route53Mock.GetRecord(ctx, "ZPARENT", "team-a.example.com", "NS")
+	require.NoError(t, err)
+	require.NotNil(t, ns)
+	assert.Equal(t, []string{"ns-1.awsdns-01.org", "ns-2.awsdns-02.com"}, ns.Values)
+	assert.EqualValues(t, delegationTTL, ns.TTL)
+
+	// The zone exists and is delegated now: nothing is created or written again
+	require.NoError(t, r.ensureDelegatedZone(ctx, ghr))
+	assert.Len(t, route53Mock.zones, 1)
+	assert.Len(t, route53Mock.records["ZPARENT"], 1)
+}
+
+func TestEnsureDelegatedZone_ExistingZone(t *testing.T) {
+	ghr := delegatedGHR()
+	route53Mock := &MockRoute53Client{zones: map[string]*aws.HostedZone{
+		"team-a.example.com": {ID: "ZEXISTING", Name: "team-a.example.com", NameServers: []string{"ns-9.awsdns-09.net"}},
+	}}
+	r := &GatewayHostnameRequestReconciler{Route53Client: route53Mock, Recorder: record.NewFakeRecorder(10)}
+
+	require.NoError(t, r.ensureDelegatedZone(context.Background(), ghr))
+	assert.Equal(t, "ZEXISTING", ghr.Status.DelegatedZoneId)
+	require.Len(t, route53Mock.records["ZPARENT"], 1)
+	assert.Equal(t, "NS", route53Mock.records["ZPARENT"][0].Type)
+}
+
+// racingRoute53Client misses the zone on the first lookup, as if another request created it
+// between the lookup and the create
+type racingRoute53Client struct {
+	*MockRoute53Client
+	lookups int
+}
+
+func (m *racingRoute53Client) GetHostedZoneByName(ctx context.Context, name string) (*aws.HostedZone, error) {
+	m.lookups++
+	if m.lookups == 1 {
+		return nil, nil
+	}
+	return m.MockRoute53Client.GetHostedZoneByName(ctx, name)
+}
+
+func TestEnsureDelegatedZone_CreatedConcurrently(t *testing.T) {
+	ghr := delegatedGHR()
+	route53Mock := &racingRoute53Client{MockRoute53Client: &MockRoute53Client{zones: map[string]*aws.HostedZone{
+		"team-a.example.com": {ID: "ZOTHER", Name: "team-a.example.com", NameServers: []string{"ns-9.awsdns-09.net"}},
+	}}}
+	r := &GatewayHostnameRequestReconciler{Route53Client: route53Mock, Recorder: record.NewFakeRecorder(10), ManageDelegatedZones: true}
+
+	require.NoError(t, r.ensureDelegatedZone(context.Background(), ghr))
+	assert.Equal(t, "ZOTHER", ghr.Status.DelegatedZoneId, "the zone the other request created is used")
+	assert.Len(t, route53Mock.zones, 1)
+	assert.True(t, conditions.IsTrue(ghr.Status.Conditions, ConditionTypeZoneDelegated))
+}
+
+func TestDelegatedZoneCallerReference(t *testing.T) {
+	ref := delegatedZoneCallerReference("ZPARENT", "team-a.example.com")
+	assert.Equal(t, ref, delegatedZoneCallerReference("ZPARENT", "Team-A.example.com."), "requests for the same zone share it")
+	assert.NotEqual(t, ref, delegatedZoneCallerReference("ZOTHER", "team-a.example.com"))
+	assert.LessOrEqual(t, len(ref), 128)
+}
@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+// isDNSOnly reports whether the request points at an external alias target instead of a Gateway
+func isDNSOnly(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	return ghr.Spec.AliasTarget != nil
+}
+
+// externalAliasRecordTypes returns the alias record types to publish for spec.aliasTarget
+func externalAliasRecordTypes(target *gatewayv1alpha1.AliasTarget) []string {
+	if target.IPv6 {
+		return []string{"A", "AAAA"}
+	}
+	return []string{"A"}
+}
+
+// externalAliasInSync reports whether the published ALIAS records match spec.aliasTarget
+func externalAliasInSync(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	target := ghr.Spec.AliasTarget
+	return ghr.Status.AssignedLoadBalancer == target.DNSName &&
+		slices.Equal(ghr.Status.AliasRecordTypes, requestedRecordTypes(ghr, externalAliasRecordTypes(target)))
+}
+
+// externalAliasTarget returns the Route53 ALIAS target of an external target, deriving its
+// hosted zone ID from the DNS name if unset
+func externalAliasTarget(target *gatewayv1alpha1.AliasTarget) (*aws.AliasTarget, error) {
+	hostedZoneID := target.HostedZoneId
+	if hostedZoneID == "" {
+		var err error
+		if hostedZoneID, err = aws.AliasTargetHostedZoneID(target.DNSName); err != nil {
+			return nil, err
+		}
+	}
+	return &aws.AliasTarget{
+		DNSName:              target.DNSName,
+		HostedZoneID:         hostedZoneID,
+		EvaluateTargetHealth: target.EvaluateTargetHealth,
+	}, nil
+}
+
+// ensureExternalAlias publishes the ALIAS records pointing at spec.aliasTarget
+func (r *GatewayHostnameRequestReconciler) ensureExternalAlias(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	logger := log.FromContext(ctx)
+	target := ghr.Spec.AliasTarget
+
+	aliasTarget, err := externalAliasTarget(target)
+	if err != nil {
+		return err
+	}
+	recordTypes := requestedRecordTypes(ghr, externalAliasRecordTypes(target))
+	if err := r.publishAliasRecords(ctx, ghr, aliasTarget, recordTypes); err != nil {
+		return err
+	}
+	ghr.Status.AssignedLoadBalancer = target.DNSName
+
+	logger.Info("Created Route53 ALIAS records for external target",
+		"types", recordTypes,
+		"target", target.DNSName,
+		"hostedZoneId", aliasTarget.HostedZoneID,
+		"zoneIds", ghr.Status.AliasZoneIds)
+	return nil
+}
+
+// reconcileDNSOnly finishes a DNS-only request once its certificate is issued: the ALIAS records
+// point at spec.aliasTarget and no Gateway, namespace label or LoadBalancerConfiguration is touched.
+// The certificate ARN in status is for the owner of the target to attach.
+func (r *GatewayHostnameRequestReconciler) reconcileDNSOnly(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if !meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeDnsAliasReady) || !aliasZonesInSync(ghr) ||
+		!externalAliasInSync(ghr) || !aliasWeightInSync(ghr) || reconcileNowPending(ghr) {
+		if err := r.checkDNSSEC(ctx, ghr, false); errors.Is(err, ErrDNSSECDegraded) {
+			return r.holdRecordChanges(ctx, ghr, ConditionTypeDnsAliasReady, err)
+		}
+		if err := r.ensureExternalAlias(ctx, ghr); err != nil {
+			if r.failCondition(ghr, ConditionTypeDnsAliasReady, conditions.ReasonAliasFailed, err) {
+				_ = r.Status().Update(ctx, ghr)
+				r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "DnsAliasFailed", "Failed to create Route53 ALIAS record: %v", err)
+			}
+			return ctrl.Result{}, err
+		}
+		_ = r.checkDNSSEC(ctx, ghr, true)
+		r.setCondition(ghr, ConditionTypeDnsAliasReady, metav1.ConditionTrue, conditions.ReasonCreated, "Route53 ALIAS record created")
+		r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "DnsAliasReady", "Route53 ALIAS record created pointing to %s", ghr.Spec.AliasTarget.DNSName)
+		if err := r.Status().Update(ctx, ghr); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Keep DNSSECDegraded current; records already in place are left alone
+	_ = r.checkDNSSEC(ctx, ghr, reconcileNowPending(ghr))
+
+	if err := r.updateCostEstimate(ctx, ghr); err != nil {
+		logger.Info("Failed to update cost estimate", "error", err.Error())
+	}
+
+	ghr.Status.ObservedGeneration = ghr.Generation
+	ghr.Status.ObservedSpecHash = computeSpecHash(&ghr.Spec)
+	markReconcileNowHandled(ghr)
+	r.setCondition(ghr, ConditionTypeReady, metav1.ConditionTrue, conditions.ReasonReady, "Certificate issued and DNS alias published (DNS-only)")
+	requeueAfter := r.recordFullReconcile(ctx, ghr, r.acmeRequeueAfter(ghr))
+	r.Recorder.Event(ghr, corev1.EventTypeNormal, "Ready", "Hostname fully provisioned")
+	if err := r.Status().Update(ctx, ghr); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	logger.Info("Successfully reconciled DNS-only GatewayHostnameRequest")
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+func TestReconcileDNSOnly_PublishesAliasToExternalTarget(t *testing.T) {
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "cdn", Namespace: "default"},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+			Hostname: "cdn.opendi.com",
+			ZoneId:   "Z123456",
+			AliasTarget: &gatewayv1alpha1.AliasTarget{
+				DNSName:      "d111111abcdef8.cloudfront.net",
+				HostedZoneId: "Z2FDTNDATAQYW2",
+			},
+		},
+		Status: gatewayv1alpha1.GatewayHostnameRequestStatus{
+			CertificateArn: "arn:aws:acm:us-east-1:123456789012:certificate/cdn",
+		},
+	}
+
+	route53Mock := &MockRoute53Client{records: make(map[string][]aws.DNSRecord)}
+	r := &GatewayHostnameRequestReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr).WithStatusSubresource(ghr).Build(),
+		Scheme:        getTestScheme(),
+		Recorder:      record.NewFakeRecorder(10),
+		Route53Client: route53Mock,
+	}
+
+	_, err := r.reconcileDNSOnly(context.Background(), ghr)
+	require.NoError(t, err)
+
+	records := route53Mock.records["Z123456"]
+	require.Len(t, records, 1)
+	assert.Equal(t, "A", records[0].Type)
+	assert.Equal(t, "d111111abcdef8.cloudfront.net", records[0].AliasTarget.DNSName)
+	assert.Equal(t, "Z2FDTNDATAQYW2", records[0].AliasTarget.HostedZoneID)
+	assert.False(t, records[0].AliasTarget.EvaluateTargetHealth)
+
+	assert.Empty(t, ghr.Status.AssignedGateway)
+	assert.Equal(t, "d111111abcdef8.cloudfront.net", ghr.Status.AssignedLoadBalancer)
+	assert.True(t, meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeReady))
+
+	// Enabling IPv6 adds the AAAA alias
+	ghr.Spec.AliasTarget.IPv6 = true
+	assert.False(t, externalAliasInSync(ghr))
+	route53Mock.records["Z123456"] = nil
+
+	_, err = r.reconcileDNSOnly(context.Background(), ghr)
+	require.NoError(t, err)
+	assert.Len(t, route53Mock.records["Z123456"], 2)
+	assert.Equal(t, []string{"A", "AAAA"}, ghr.Status.AliasRecordTypes)
+}
+
+func TestEnsureExternalAlias_DerivesHostedZoneID(t *testing.T) {
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+			Hostname: "api.opendi.com",
+			ZoneId:   "Z123456",
+			AliasTarget: &gatewayv1alpha1.AliasTarget{
+				DNSName:              "d-abc123xyz.execute-api.eu-west-1.amazonaws.com",
+				EvaluateTargetHealth: true,
+			},
+		},
+	}
+
+	route53Mock := &MockRoute53Client{records: make(map[string][]aws.DNSRecord)}
+	r := &GatewayHostnameRequestReconciler{Route53Client: route53Mock}
+
+	require.NoError(t, r.ensureExternalAlias(context.Background(), ghr))
+	records := route53Mock.records["Z123456"]
+	require.Len(t, records, 1)
+	assert.Equal(t, "ZLY8HYME6SFDD", records[0].AliasTarget.HostedZoneID)
+
+	// Targets the zone can't be derived for need hostedZoneId
+	ghr.Spec.AliasTarget.DNSName = "my-nlb-123.elb.eu-west-1.amazonaws.com"
+	assert.Error(t, r.ensureExternalAlias(context.Background(), ghr))
+}
+
+func TestComputeSpecHash_DNSOnlyModeChangesHash(t *testing.T) {
+	spec := gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "cdn.opendi.com", ZoneId: "Z123456"}
+	gatewayHash := computeSpecHash(&spec)
+
+	spec.AliasTarget = &gatewayv1alpha1.AliasTarget{DNSName: "d111111abcdef8.cloudfront.net", HostedZoneId: "Z2FDTNDATAQYW2"}
+	assert.NotEqual(t, gatewayHash, computeSpecHash(&spec))
+
+	// Moving the target does not re-provision the certificate
+	dnsOnlyHash := computeSpecHash(&spec)
+	spec.AliasTarget.DNSName = "d222222abcdef8.cloudfront.net"
+	assert.Equal(t, dnsOnlyHash, computeSpecHash(&spec))
+}
@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+const (
+	// dnssecStatusTTL is how long a zone's DNSSEC status is cached between record changes
+	dnssecStatusTTL = 5 * time.Minute
+
+	// dnssecRecheckInterval is how often held record changes are retried
+	dnssecRecheckInterval = 5 * time.Minute
+)
+
+// ErrDNSSECDegraded means a zone is DNSSEC-signed but its signing status or key-signing keys
+// need attention. Records changed now may not be signed, and validating resolvers would
+// reject the answers.
+var ErrDNSSECDegraded = errors.New("DNSSEC signing of the hosted zone is degraded")
+
+// DNSSECChecker reads the DNSSEC signing status of hosted zones. Statuses are cached per zone,
+// so every reconcile doesn't cost a Route53 API call against the account-wide rate limit.
+type DNSSECChecker struct {
+	client aws.Route53Client
+	ttl    time.Duration
+
+	mu    sync.Mutex
+	cache map[string]dnssecCacheEntry
+}
+
+type dnssecCacheEntry struct {
+	status  *aws.DNSSECStatus
+	fetched time.Time
+}
+
+// NewDNSSECChecker returns a checker that caches zone statuses for dnssecStatusTTL
+func NewDNSSECChecker(client aws.Route53Client) *DNSSECChecker {
+	return &DNSSECChecker{
+		client: client,
+		ttl:    dnssecStatusTTL,
+		cache:  make(map[string]dnssecCacheEntry),
+	}
+}
+
+// Status returns the zone's DNSSEC status. refresh bypasses the cache, e.g. right after
+// records in the zone were changed.
+func (c *DNSSECChecker) Status(ctx context.Context, zoneId string, refresh bool) (*aws.DNSSECStatus, error) {
+	c.mu.Lock()
+	entry, ok := c.cache[zoneId]
+	c.mu.Unlock()
+	if ok && !refresh && time.Since(entry.fetched) < c.ttl {
+		return entry.status, nil
+	}
+
+	status, err := c.client.GetDNSSEC(ctx, zoneId)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.cache[zoneId] = dnssecCacheEntry{status: status, fetched: time.Now()}
+	c.mu.Unlock()
+	return status, nil
+}
+
+// dnssecProblems returns what would make validating resolvers fail for a signed zone.
+// Zones that are not signed have no problems.
+func dnssecProblems(status *aws.DNSSECStatus) []string {
+	var problems []string
+	switch status.ServeSignature {
+	case "SIGNING":
+	case "ACTION_NEEDED", "INTERNAL_FAILURE":
+		problems = append(problems, withStatusMessage("signing status "+status.ServeSignature, status.StatusMessage))
+	default:
+		// NOT_SIGNING or DELETING
+		return nil
+	}
+
+	active := false
+	for _, ksk := range status.KeySigningKeys {
+		switch ksk.Status {
+		case "ACTIVE":
+			active = true
+		case "ACTION_NEEDED", "INTERNAL_FAILURE":
+			problems = append(problems, withStatusMessage(fmt.Sprintf("key-signing key %s is %s", ksk.Name, ksk.Status), ksk.StatusMessage))
+		}
+	}
+	if !active {
+		problems = append(problems, "no active key-signing key")
+	}
+	return problems
+}
+
+// withStatusMessage appends Route53's explanation, if any, to a problem
+func withStatusMessage(problem, statusMessage string) string {
+	if statusMessage == "" {
+		return problem
+	}
+	return problem + ": " + statusMessage
+}
+
+// checkDNSSEC updates the DNSSECDegraded condition from the DNSSEC status of the zones the
+// request publishes records in, and returns ErrDNSSECDegraded if any of them is degraded.
+// Zones whose status can't be read are logged and skipped. No-op without r.DNSSEC.
+func (r *GatewayHostnameRequestReconciler) checkDNSSEC(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, refresh bool) error {
+	if r.DNSSEC == nil {
+		return nil
+	}
+	logger := log.FromContext(ctx)
+
+	awsCtx, cancel := withAWSTimeout(ctx)
+	defer cancel()
+
+	var problems []string
+	for _, zoneID := range aliasZoneIds(ghr) {
+		status, err := r.DNSSEC.Status(awsCtx, zoneID, refresh)
+		if err != nil {
+			logger.Info("Failed to check DNSSEC status", "zoneId", zoneID, "error", err.Error())
+			continue
+		}
+		for _, problem := range dnssecProblems(status) {
+			problems = append(problems, fmt.Sprintf("zone %s: %s", zoneID, problem))
+		}
+	}
+
+	if len(problems) == 0 {
+		meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeDNSSECDegraded)
+		return nil
+	}
+
+	msg := strings.Join(problems, "; ")
+	if !meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeDNSSECDegraded) {
+		r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "DNSSECDegraded", "DNSSEC signing is degraded, validating resolvers may fail: %s", msg)
+	}
+	r.setCondition(ghr, ConditionTypeDNSSECDegraded, metav1.ConditionTrue, conditions.ReasonSigningDegraded, msg)
+	return fmt.Errorf("%w: %s", ErrDNSSECDegraded, msg)
+}
+
+// holdRecordChanges leaves the request's records as they are while DNSSEC signing is degraded,
+// so changes aren't published unsigned, and retries after dnssecRecheckInterval
+func (r *GatewayHostnameRequestReconciler) holdRecordChanges(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, condType string, err error) (ctrl.Result, error) {
+	log.FromContext(ctx).Info("Holding Route53 record changes", "reason", err.Error())
+	r.setCondition(ghr, condType, metav1.ConditionFalse, conditions.ReasonDNSSECDegraded, "Record changes held: "+err.Error())
+	if err := r.Status().Update(ctx, ghr); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: dnssecRecheckInterval}, nil
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+func TestDNSSECProblems(t *testing.T) {
+	activeKSK := aws.KeySigningKey{Name: "ksk1", Status: "ACTIVE"}
+	tests := []struct {
+		name   string
+		status aws.DNSSECStatus
+		want   []string
+	}{
+		{name: "not signing", status: aws.DNSSECStatus{ServeSignature: "NOT_SIGNING"}},
+		{name: "deleting", status: aws.DNSSECStatus{ServeSignature: "DELETING"}},
+		{name: "healthy", status: aws.DNSSECStatus{ServeSignature: "SIGNING", KeySigningKeys: []aws.KeySigningKey{activeKSK}}},
+		{
+			name:   "no active key",
+			status: aws.DNSSECStatus{ServeSignature: "SIGNING", KeySigningKeys: []aws.KeySigningKey{{Name: "ksk1", Status: "INACTIVE"}}},
+			want:   []string{"no active key-signing key"},
+		},
+		{
+			name: "key needs action",
+			status: aws.DNSSECStatus{ServeSignature: "SIGNING", KeySigningKeys: []aws.KeySigningKey{
+				activeKSK,
+				{Name: "ksk2", Status: "ACTION_NEEDED", StatusMessage: "KMS key is disabled"},
+			}},
+			want: []string{"key-signing key ksk2 is ACTION_NEEDED: KMS key is disabled"},
+		},
+		{
+			name:   "zone needs action",
+			status: aws.DNSSECStatus{ServeSignature: "ACTION_NEEDED", KeySigningKeys: []aws.KeySigningKey{activeKSK}},
+			want:   []string{"signing status ACTION_NEEDED"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, dnssecProblems(&tt.status))
+		})
+	}
+}
+
+func TestReconcileDNSOnly_HoldsRecordChangesWhileDNSSECDegraded(t *testing.T) {
+	ctx := context.Background()
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "cdn", Namespace: "default"},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+			Hostname: "cdn.opendi.com",
+			ZoneId:   "Z123456",
+			AliasTarget: &gatewayv1alpha1.AliasTarget{
+				DNSName:      "d111111abcdef8.cloudfront.net",
+				HostedZoneId: "Z2FDTNDATAQYW2",
+			},
+		},
+		Status: gatewayv1alpha1.GatewayHostnameRequestStatus{
+			CertificateArn: "arn:aws:acm:us-east-1:123456789012:certificate/cdn",
+		},
+	}
+
+	route53Mock := &MockRoute53Client{
+		records: make(map[string][]aws.DNSRecord),
+		dnssec: map[string]*aws.DNSSECStatus{
+			"Z123456": {ServeSignature: "INTERNAL_FAILURE", KeySigningKeys: []aws.KeySigningKey{{Name: "ksk1", Status: "ACTIVE"}}},
+		},
+	}
+	r := &GatewayHostnameRequestReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr).WithStatusSubresource(ghr).Build(),
+		Scheme:        getTestScheme(),
+		Recorder:      record.NewFakeRecorder(10),
+		Route53Client: route53Mock,
+		DNSSEC:        NewDNSSECChecker(route53Mock),
+	}
+
+	result, err := r.reconcileDNSOnly(ctx, ghr)
+	require.NoError(t, err)
+	assert.Equal(t, dnssecRecheckInterval, result.RequeueAfter)
+	assert.Empty(t, route53Mock.records["Z123456"])
+	cond := meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeDnsAliasReady)
+	require.NotNil(t, cond)
+	assert.Equal(t, string(conditions.ReasonDNSSECDegraded), cond.Reason)
+	degraded := meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeDNSSECDegraded)
+	require.NotNil(t, degraded)
+	assert.Contains(t, degraded.Message, "zone Z123456: signing status INTERNAL_FAILURE")
+
+	// The cached status is refreshed after the TTL; once signing recovers, records are published
+	route53Mock.dnssec["Z123456"].ServeSignature = "SIGNING"
+	r.DNSSEC.ttl = 0
+	_, err = r.reconcileDNSOnly(ctx, ghr)
+	require.NoError(t, err)
+	assert.Len(t, route53Mock.records["Z123456"], 1)
+	assert.Nil(t, meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeDNSSECDegraded))
+	assert.True(t, meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeReady))
+}
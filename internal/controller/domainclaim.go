@@ -5,46 +5,152 @@ import (
 	"fmt"
 	"strings"
 
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/types"
-	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
 	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
 )
 
+// claimName returns the name of the request's DomainClaim. A claim is exclusive on the zone
+// ID and hostname; the request's environment is recorded on the claim but not part of its name.
+func (r *GatewayHostnameRequestReconciler) claimName(ghr *gatewayv1alpha1.GatewayHostnameRequest) string {
+	return generateClaimName(ghr.Spec.ZoneId, ghr.Spec.Hostname)
+}
+
+// ownsClaim reports whether the claim belongs to the request, alone or as one of the owners
+// of a shared claim
+func ownsClaim(claim *gatewayv1alpha1.DomainClaim, ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	if claim.Spec.Shared {
+		return ownerIndex(claim, ghr) >= 0
+	}
+	return claim.Spec.OwnerRef.Namespace == ghr.Namespace &&
+		claim.Spec.OwnerRef.Name == ghr.Name &&
+		claim.Spec.OwnerRef.UID == string(ghr.UID)
+}
+
+// subtreeClaim returns the most specific Subtree DomainClaim covering the request's hostname in
+// its zone, or nil. Subtree claims with an environment only cover requests for that environment.
+func (r *GatewayHostnameRequestReconciler) subtreeClaim(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (*gatewayv1alpha1.DomainClaim, error) {
+	claims, err := r.claimStore().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hostname := CanonicalHostname(ghr.Spec.Hostname)
+	var found *gatewayv1alpha1.DomainClaim
+	for i := range claims {
+		claim := &claims[i]
+		if claim.Spec.Scope != gatewayv1alpha1.DomainClaimScopeSubtree || claim.Spec.ZoneId != ghr.Spec.ZoneId {
+			continue
+		}
+		if claim.Spec.Environment != "" && !strings.EqualFold(claim.Spec.Environment, ghr.Spec.Environment) {
+			continue
+		}
+		root := CanonicalHostname(claim.Spec.Hostname)
+		if hostname != root && !strings.HasSuffix(hostname, "."+root) {
+			continue
+		}
+		// Nested trees hand part of a tree to another namespace
+		if found == nil || len(root) > len(CanonicalHostname(found.Spec.Hostname)) {
+			found = claim
+		}
+	}
+	return found, nil
+}
+
+// checkSubtreeClaim reports whether the request may claim its hostname: false if a Subtree
+// DomainClaim reserves it for another namespace, in which case Claimed is set False
+func (r *GatewayHostnameRequestReconciler) checkSubtreeClaim(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (bool, error) {
+	subtree, err := r.subtreeClaim(ctx, ghr)
+	if err != nil {
+		return false, err
+	}
+	if subtree == nil || subtree.Spec.OwnerRef.Namespace == ghr.Namespace {
+		return true, nil
+	}
+
+	message := fmt.Sprintf("Hostname is in %s, reserved for namespace %s by DomainClaim %s",
+		subtree.Spec.Hostname, subtree.Spec.OwnerRef.Namespace, subtree.Name)
+	if !conditions.HasReason(ghr.Status.Conditions, ConditionTypeClaimed, metav1.ConditionFalse, conditions.ReasonReservedForNamespace) {
+		r.Recorder.Event(ghr, corev1.EventTypeWarning, "ReservedForNamespace", message)
+	}
+	r.setCondition(ghr, ConditionTypeClaimed, metav1.ConditionFalse, conditions.ReasonReservedForNamespace, message)
+	if err := r.Status().Update(ctx, ghr); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// CanonicalHostname lowercases a hostname and drops surrounding space and a trailing dot, so
+// App.Example.com. and app.example.com share one claim, certificate and set of records
+func CanonicalHostname(hostname string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(hostname), "."))
+}
+
+// conflictingClaim returns a Hostname DomainClaim of another request for the same hostname
+// and zone whose name differs from the request's claim name, e.g. one taken before claim
+// names were canonical or one with the environment in its name, or nil
+func (r *GatewayHostnameRequestReconciler) conflictingClaim(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (*gatewayv1alpha1.DomainClaim, error) {
+	claims, err := r.claimStore().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hostname := CanonicalHostname(ghr.Spec.Hostname)
+	for i := range claims {
+		claim := &claims[i]
+		if claim.Spec.Scope == gatewayv1alpha1.DomainClaimScopeSubtree || ownsClaim(claim, ghr) ||
+			claim.Spec.ZoneId != ghr.Spec.ZoneId || CanonicalHostname(claim.Spec.Hostname) != hostname {
+			continue
+		}
+		return claim, nil
+	}
+	return nil, nil
+}
+
 // ensureDomainClaim ensures a DomainClaim exists for this hostname
 // Returns true if claim is owned by this request, false if claimed by another
 func (r *GatewayHostnameRequestReconciler) ensureDomainClaim(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (bool, error) {
-	claimName := generateClaimName(ghr.Spec.ZoneId, ghr.Spec.Hostname)
+	claimName := r.claimName(ghr)
 
-	var claim gatewayv1alpha1.DomainClaim
-	err := r.Get(ctx, types.NamespacedName{Name: claimName}, &claim)
-
-	if err == nil {
+	existing, err := r.claimStore().Get(ctx, claimName)
+	if err != nil {
+		return false, err
+	}
+	if existing != nil {
 		// Claim exists, check if it's owned by this request
-		if claim.Spec.OwnerRef.Namespace == ghr.Namespace &&
-			claim.Spec.OwnerRef.Name == ghr.Name &&
-			claim.Spec.OwnerRef.UID == string(ghr.UID) {
-			return true, nil // Already owned by this request
+		if ownsClaim(existing, ghr) {
+			return true, r.updateClaimEnvironment(ctx, existing, ghr) // Already owned by this request
+		}
+		if existing.Spec.Shared || ghr.Spec.SharedOwnership != nil {
+			return r.joinSharedClaim(ctx, existing, ghr)
 		}
 		// Claimed by someone else
 		return false, nil
 	}
 
-	if !apierrors.IsNotFound(err) {
-		return false, fmt.Errorf("failed to get domain claim: %w", err)
+	// A claim for another spelling of the hostname counts as taken
+	conflict, err := r.conflictingClaim(ctx, ghr)
+	if err != nil {
+		return false, err
+	}
+	if conflict != nil {
+		log.FromContext(ctx).Info("Hostname claimed under another spelling", "claim", conflict.Name)
+		return false, nil
 	}
 
 	// Claim doesn't exist, create it
 	now := metav1.Now()
-	claim = gatewayv1alpha1.DomainClaim{
+	claim := &gatewayv1alpha1.DomainClaim{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: claimName,
 		},
 		Spec: gatewayv1alpha1.DomainClaimSpec{
-			ZoneId:   ghr.Spec.ZoneId,
-			Hostname: ghr.Spec.Hostname,
+			ZoneId:      ghr.Spec.ZoneId,
+			Hostname:    CanonicalHostname(ghr.Spec.Hostname),
+			Environment: ghr.Spec.Environment,
 			OwnerRef: gatewayv1alpha1.DomainClaimOwnerRef{
 				Namespace: ghr.Namespace,
 				Name:      ghr.Name,
@@ -55,46 +161,84 @@ func (r *GatewayHostnameRequestReconciler) ensureDomainClaim(ctx context.Context
 			ClaimedAt: &now,
 		},
 	}
+	if ghr.Spec.SharedOwnership != nil {
+		claim.Spec.Shared = true
+		claim.Spec.Owners = []gatewayv1alpha1.DomainClaimOwner{claimOwner(ghr)}
+	}
 
-	if err := r.Create(ctx, &claim); err != nil {
-		if apierrors.IsAlreadyExists(err) {
-			// Race condition: someone else created it between our Get and Create
-			return false, nil
-		}
-		return false, fmt.Errorf("failed to create domain claim: %w", err)
+	created, err := r.claimStore().Create(ctx, claim)
+	if err != nil {
+		return false, err
+	}
+	if !created {
+		// Race condition: someone else created it between our Get and Create
+		return false, nil
+	}
+
+	// Release claims held under a previous name, e.g. one with the environment in it. Best
+	// effort: leftovers are released when the request is deleted.
+	if err := r.deleteDomainClaims(ctx, ghr, claimName); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to release previous domain claims")
 	}
 
 	return true, nil
 }
 
-// deleteDomainClaim deletes the DomainClaim owned by this request
+// updateClaimEnvironment records the request's current environment on its claim. Shared
+// claims keep the environment of the request that took them.
+func (r *GatewayHostnameRequestReconciler) updateClaimEnvironment(ctx context.Context, claim *gatewayv1alpha1.DomainClaim, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	if claim.Spec.Shared || claim.Spec.Environment == ghr.Spec.Environment {
+		return nil
+	}
+	updated := claim.DeepCopy()
+	updated.Spec.Environment = ghr.Spec.Environment
+	// A claim changed in the meantime is updated on the next reconcile
+	_, err := r.claimStore().Update(ctx, claim, updated)
+	return err
+}
+
+// deleteDomainClaim deletes the DomainClaims owned by this request
 func (r *GatewayHostnameRequestReconciler) deleteDomainClaim(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
-	claimName := generateClaimName(ghr.Spec.ZoneId, ghr.Spec.Hostname)
+	return r.deleteDomainClaims(ctx, ghr, "")
+}
 
-	var claim gatewayv1alpha1.DomainClaim
-	err := r.Get(ctx, types.NamespacedName{Name: claimName}, &claim)
+// deleteDomainClaims deletes the DomainClaims owned by this request except keep. Claims are
+// listed rather than looked up by name, so claims taken under a previous name are found too. The request leaves shared claims, which are deleted with their last owner.
+func (r *GatewayHostnameRequestReconciler) deleteDomainClaims(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, keep string) error {
+	store := r.claimStore()
+	claims, err := store.List(ctx)
 	if err != nil {
-		if apierrors.IsNotFound(err) {
-			return nil // Already deleted
-		}
 		return err
 	}
 
-	// Only delete if owned by this request
-	if claim.Spec.OwnerRef.Namespace == ghr.Namespace &&
-		claim.Spec.OwnerRef.Name == ghr.Name &&
-		claim.Spec.OwnerRef.UID == string(ghr.UID) {
-		return client.IgnoreNotFound(r.Delete(ctx, &claim))
+	for i := range claims {
+		claim := &claims[i]
+		// Only delete if owned by this request; Subtree claims are managed by platform teams
+		if claim.Name == keep || !ownsClaim(claim, ghr) || claim.Spec.Scope == gatewayv1alpha1.DomainClaimScopeSubtree {
+			continue
+		}
+		if claim.Spec.Shared {
+			if err := leaveSharedClaim(ctx, store, claim, func(owner gatewayv1alpha1.DomainClaimOwner) bool {
+				return isOwner(owner, ghr)
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := store.Delete(ctx, claim); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-// generateClaimName creates a deterministic name for a DomainClaim
+// generateClaimName creates a deterministic name for a DomainClaim. Spellings of a hostname
+// that differ only in case or a trailing dot get the same name. Names too long for Kubernetes
+// end in a hash instead.
 func generateClaimName(zoneId, hostname string) string {
 	// Sanitize hostname: replace * with 'wildcard' for valid K8s name
-	sanitized := strings.ReplaceAll(hostname, "*", "wildcard")
+	sanitized := strings.ReplaceAll(CanonicalHostname(hostname), "*", "wildcard")
 	// Use a simple naming scheme: zone-hostname
-	// In production, might want to hash long names
-	return fmt.Sprintf("%s-%s", strings.ToLower(zoneId), strings.ToLower(sanitized))
+	return gateway.LimitName(fmt.Sprintf("%s-%s", strings.ToLower(zoneId), strings.ToLower(sanitized)))
 }
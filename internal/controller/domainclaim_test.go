@@ -2,13 +2,17 @@ package controller
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
 	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
 )
 
@@ -37,6 +41,12 @@ func TestGenerateClaimName(t *testing.T) {
 			hostname: "TeSt.OpEnDi.CoM",
 			want:     "z2nrhx85uvtudq-test.opendi.com",
 		},
+		{
+			name:     "trailing dot is dropped",
+			zoneId:   "Z123456",
+			hostname: "App.Example.com.",
+			want:     "z123456-app.example.com",
+		},
 	}
 
 	for _, tt := range tests {
@@ -49,6 +59,18 @@ func TestGenerateClaimName(t *testing.T) {
 	}
 }
 
+func TestGenerateClaimName_LongHostname(t *testing.T) {
+	// A valid 253 character hostname doesn't fit next to the zone ID
+	hostname := strings.Repeat(strings.Repeat("a", 63)+".", 3) + strings.Repeat("b", 57) + ".com"
+	name := generateClaimName("Z2NRHX85UVTUDQ", hostname)
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		t.Errorf("generateClaimName() = %q is not a valid name: %v", name, errs)
+	}
+	if other := generateClaimName("Z2NRHX85UVTUDQ", strings.Replace(hostname, "bbb.com", "bbb.net", 1)); other == name {
+		t.Error("generateClaimName() gives long hostnames the same name")
+	}
+}
+
 func TestReconciler_ensureDomainClaim(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = gatewayv1alpha1.AddToScheme(scheme)
@@ -244,3 +266,249 @@ func TestReconciler_deleteDomainClaim(t *testing.T) {
 		t.Error("claim should be deleted but still exists")
 	}
 }
+
+func TestReconciler_ensureDomainClaim_Environment(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = gatewayv1alpha1.AddToScheme(scheme)
+
+	newGHR := func(name, uid, zoneId, env string) *gatewayv1alpha1.GatewayHostnameRequest {
+		return &gatewayv1alpha1.GatewayHostnameRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(uid)},
+			Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+				ZoneId:      zoneId,
+				Hostname:    "test.example.com",
+				Environment: env,
+			},
+		}
+	}
+	dev := newGHR("dev", "uid-dev", "Z123456", "dev")
+	// A claim with the environment in its name
+	legacyClaim := &gatewayv1alpha1.DomainClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "dev-z123456-test.example.com"},
+		Spec: gatewayv1alpha1.DomainClaimSpec{
+			ZoneId:      "Z123456",
+			Hostname:    "test.example.com",
+			Environment: "dev",
+			OwnerRef:    gatewayv1alpha1.DomainClaimOwnerRef{Namespace: "default", Name: "dev", UID: "uid-dev"},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(legacyClaim).Build()
+	r := &GatewayHostnameRequestReconciler{Client: c, Scheme: scheme}
+
+	// The request moves to a claim named after the zone and hostname only
+	claimed, err := r.ensureDomainClaim(ctx, dev)
+	if err != nil || !claimed {
+		t.Fatalf("ensureDomainClaim(dev) = %v, %v", claimed, err)
+	}
+	var claims gatewayv1alpha1.DomainClaimList
+	if err := c.List(ctx, &claims); err != nil {
+		t.Fatal(err)
+	}
+	if len(claims.Items) != 1 || claims.Items[0].Name != "z123456-test.example.com" || claims.Items[0].Spec.Environment != "dev" {
+		t.Fatalf("claims = %+v, want only z123456-test.example.com for dev", claims.Items)
+	}
+
+	// Another environment can't claim the hostname in the same zone
+	if claimed, _ := r.ensureDomainClaim(ctx, newGHR("prod", "uid-prod", "Z123456", "prod")); claimed {
+		t.Error("prod request claimed a hostname held by dev in the same zone")
+	}
+
+	// An isolated zone of the same name has its own ID and claim
+	if claimed, err := r.ensureDomainClaim(ctx, newGHR("prod", "uid-prod", "Z654321", "prod")); err != nil || !claimed {
+		t.Errorf("ensureDomainClaim(prod in Z654321) = %v, %v", claimed, err)
+	}
+
+	// The claim follows the request's environment
+	dev.Spec.Environment = "staging"
+	if claimed, err := r.ensureDomainClaim(ctx, dev); err != nil || !claimed {
+		t.Fatalf("ensureDomainClaim(dev) = %v, %v", claimed, err)
+	}
+	var claim gatewayv1alpha1.DomainClaim
+	if err := c.Get(ctx, types.NamespacedName{Name: "z123456-test.example.com"}, &claim); err != nil {
+		t.Fatal(err)
+	}
+	if claim.Spec.Environment != "staging" {
+		t.Errorf("claim environment = %q, want staging", claim.Spec.Environment)
+	}
+}
+
+func TestReconciler_ensureDomainClaim_HostnameSpellings(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	_ = gatewayv1alpha1.AddToScheme(scheme)
+
+	newGHR := func(name, uid, hostname string) *gatewayv1alpha1.GatewayHostnameRequest {
+		return &gatewayv1alpha1.GatewayHostnameRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(uid)},
+			Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{ZoneId: "Z123456", Hostname: hostname},
+		}
+	}
+	legacy := newGHR("legacy", "uid-legacy", "App.Example.com.")
+	// A claim taken before claim names were canonical
+	legacyClaim := &gatewayv1alpha1.DomainClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "z123456-app.example.com."},
+		Spec: gatewayv1alpha1.DomainClaimSpec{
+			ZoneId:   "Z123456",
+			Hostname: "App.Example.com.",
+			OwnerRef: gatewayv1alpha1.DomainClaimOwnerRef{Namespace: "default", Name: "legacy", UID: "uid-legacy"},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(legacyClaim).Build()
+	r := &GatewayHostnameRequestReconciler{Client: c, Scheme: scheme}
+
+	if claimed, err := r.ensureDomainClaim(ctx, newGHR("app", "uid-app", "app.example.com")); err != nil || claimed {
+		t.Fatalf("ensureDomainClaim(app) = %v, %v; want blocked by the claim for App.Example.com.", claimed, err)
+	}
+
+	// The owner moves to the canonical claim name
+	if claimed, err := r.ensureDomainClaim(ctx, legacy); err != nil || !claimed {
+		t.Fatalf("ensureDomainClaim(legacy) = %v, %v", claimed, err)
+	}
+	var claims gatewayv1alpha1.DomainClaimList
+	if err := c.List(ctx, &claims); err != nil {
+		t.Fatal(err)
+	}
+	if len(claims.Items) != 1 || claims.Items[0].Name != "z123456-app.example.com" || claims.Items[0].Spec.Hostname != "app.example.com" {
+		t.Errorf("claims = %+v, want only z123456-app.example.com for app.example.com", claims.Items)
+	}
+	if claimed, _ := r.ensureDomainClaim(ctx, newGHR("app", "uid-app", "app.example.com")); claimed {
+		t.Error("app.example.com claimed a hostname held as App.Example.com.")
+	}
+}
+
+func TestReconciler_checkSubtreeClaim(t *testing.T) {
+	subtree := func(name, zoneId, hostname, env, namespace string) *gatewayv1alpha1.DomainClaim {
+		return &gatewayv1alpha1.DomainClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: gatewayv1alpha1.DomainClaimSpec{
+				ZoneId:      zoneId,
+				Hostname:    hostname,
+				Environment: env,
+				Scope:       gatewayv1alpha1.DomainClaimScopeSubtree,
+				OwnerRef:    gatewayv1alpha1.DomainClaimOwnerRef{Namespace: namespace},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		hostname    string
+		environment string
+		claims      []*gatewayv1alpha1.DomainClaim
+		wantAllowed bool
+	}{
+		{
+			name:        "no subtree claims",
+			hostname:    "api.team.example.com",
+			wantAllowed: true,
+		},
+		{
+			name:        "subtree of the request's namespace",
+			hostname:    "api.team.example.com",
+			claims:      []*gatewayv1alpha1.DomainClaim{subtree("team", "Z123456", "team.example.com", "", "default")},
+			wantAllowed: true,
+		},
+		{
+			name:        "subtree of another namespace",
+			hostname:    "api.team.example.com",
+			claims:      []*gatewayv1alpha1.DomainClaim{subtree("team", "Z123456", "team.example.com", "", "team")},
+			wantAllowed: false,
+		},
+		{
+			name:        "root of the subtree",
+			hostname:    "Team.Example.com",
+			claims:      []*gatewayv1alpha1.DomainClaim{subtree("team", "Z123456", "team.example.com", "", "team")},
+			wantAllowed: false,
+		},
+		{
+			name:        "wildcard in the subtree",
+			hostname:    "*.team.example.com",
+			claims:      []*gatewayv1alpha1.DomainClaim{subtree("team", "Z123456", "team.example.com", "", "team")},
+			wantAllowed: false,
+		},
+		{
+			name:        "name sharing only a suffix",
+			hostname:    "myteam.example.com",
+			claims:      []*gatewayv1alpha1.DomainClaim{subtree("team", "Z123456", "team.example.com", "", "team")},
+			wantAllowed: true,
+		},
+		{
+			name:     "nested subtree handed to the request's namespace",
+			hostname: "api.team.example.com",
+			claims: []*gatewayv1alpha1.DomainClaim{
+				subtree("platform", "Z123456", "example.com", "", "platform"),
+				subtree("team", "Z123456", "team.example.com", "", "default"),
+			},
+			wantAllowed: true,
+		},
+		{
+			name:        "subtree in another zone",
+			hostname:    "api.team.example.com",
+			claims:      []*gatewayv1alpha1.DomainClaim{subtree("team", "Z999999", "team.example.com", "", "team")},
+			wantAllowed: true,
+		},
+		{
+			name:        "subtree for another environment",
+			hostname:    "api.team.example.com",
+			environment: "dev",
+			claims:      []*gatewayv1alpha1.DomainClaim{subtree("team", "Z123456", "team.example.com", "prod", "team")},
+			wantAllowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default", UID: "uid-api"},
+				Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+					ZoneId:      "Z123456",
+					Hostname:    tt.hostname,
+					Environment: tt.environment,
+				},
+			}
+			builder := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr).WithStatusSubresource(ghr)
+			for _, claim := range tt.claims {
+				builder = builder.WithObjects(claim)
+			}
+			r := &GatewayHostnameRequestReconciler{Client: builder.Build(), Recorder: record.NewFakeRecorder(10)}
+
+			allowed, err := r.checkSubtreeClaim(context.Background(), ghr)
+			if err != nil {
+				t.Fatalf("checkSubtreeClaim() error = %v", err)
+			}
+			if allowed != tt.wantAllowed {
+				t.Errorf("checkSubtreeClaim() = %v, want %v", allowed, tt.wantAllowed)
+			}
+			reserved := conditions.HasReason(ghr.Status.Conditions, ConditionTypeClaimed, metav1.ConditionFalse, conditions.ReasonReservedForNamespace)
+			if reserved == allowed {
+				t.Errorf("ReservedForNamespace condition set = %v, want %v", reserved, !allowed)
+			}
+		})
+	}
+}
+
+func TestReconciler_deleteDomainClaim_KeepsSubtreeClaims(t *testing.T) {
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "team", UID: "uid-api"},
+	}
+	tree := &gatewayv1alpha1.DomainClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-tree"},
+		Spec: gatewayv1alpha1.DomainClaimSpec{
+			ZoneId:   "Z123456",
+			Hostname: "team.example.com",
+			Scope:    gatewayv1alpha1.DomainClaimScopeSubtree,
+			OwnerRef: gatewayv1alpha1.DomainClaimOwnerRef{Namespace: "team", Name: "api", UID: "uid-api"},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(tree).Build()
+	r := &GatewayHostnameRequestReconciler{Client: c}
+
+	if err := r.deleteDomainClaim(context.Background(), ghr); err != nil {
+		t.Fatalf("deleteDomainClaim() error = %v", err)
+	}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "team-tree"}, &gatewayv1alpha1.DomainClaim{}); err != nil {
+		t.Errorf("subtree claim was deleted with the request: %v", err)
+	}
+}
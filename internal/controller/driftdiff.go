@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// AnnotationAppliedSpec holds the spec the controller last wrote to a LoadBalancerConfiguration,
+// as JSON, so changes made by others can be told apart from the controller's own
+const AnnotationAppliedSpec = "gateway.opendi.com/applied-spec"
+
+// maxDriftChanges is how many changes a drift event lists before summarizing the rest
+const maxDriftChanges = 5
+
+// driftedGatewayAnnotations are the Gateway annotations the drift correction rewrites
+var driftedGatewayAnnotations = []string{
+	"gateway.k8s.aws/loadbalancer-configuration",
+	AnnotationVisibility,
+	"gateway.opendi.com/waf-arn",
+}
+
+// flattenSpec maps the leaves of a spec to their JSON values by dotted path. Lists are leaves.
+func flattenSpec(prefix string, value interface{}, out map[string]string) {
+	if m, ok := value.(map[string]interface{}); ok {
+		for k, v := range m {
+			path := k
+			if prefix != "" {
+				path = prefix + "." + k
+			}
+			flattenSpec(path, v, out)
+		}
+		return
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		data = []byte(fmt.Sprint(value))
+	}
+	out[prefix] = string(data)
+}
+
+// specDiff lists the fields that differ between two specs as "path: old -> new", sorted by path.
+// Fields only one side has show as <unset> on the other.
+func specDiff(from, to map[string]interface{}) []string {
+	before, after := map[string]string{}, map[string]string{}
+	flattenSpec("", from, before)
+	flattenSpec("", to, after)
+
+	paths := map[string]bool{}
+	for path := range before {
+		paths[path] = true
+	}
+	for path := range after {
+		paths[path] = true
+	}
+	var changes []string
+	for path := range paths {
+		old, ok := before[path]
+		if !ok {
+			old = "<unset>"
+		}
+		updated, ok := after[path]
+		if !ok {
+			updated = "<unset>"
+		}
+		if old != updated {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", path, old, updated))
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+// compactDiff joins changes for an event message, listing at most maxDriftChanges
+func compactDiff(changes []string) string {
+	if len(changes) <= maxDriftChanges {
+		return strings.Join(changes, "; ")
+	}
+	return fmt.Sprintf("%s; and %d more", strings.Join(changes[:maxDriftChanges], "; "), len(changes)-maxDriftChanges)
+}
+
+// appliedSpec returns the spec recorded in AnnotationAppliedSpec, or false if there is none
+func appliedSpec(obj *unstructured.Unstructured) (map[string]interface{}, bool) {
+	data, ok := obj.GetAnnotations()[AnnotationAppliedSpec]
+	if !ok {
+		return nil, false
+	}
+	var spec map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &spec); err != nil {
+		return nil, false
+	}
+	return spec, true
+}
+
+// setAppliedSpec records the spec the controller writes in AnnotationAppliedSpec
+func setAppliedSpec(obj *unstructured.Unstructured, spec map[string]interface{}) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AnnotationAppliedSpec] = string(data)
+	obj.SetAnnotations(annotations)
+}
+
+// modifiedLoadBalancerConfiguration returns the live spec of a Gateway's LoadBalancerConfiguration
+// if someone other than the controller changed it since it was last written, or nil
+func (r *GatewayHostnameRequestReconciler) modifiedLoadBalancerConfiguration(ctx context.Context, gatewayName, gatewayNamespace string) map[string]interface{} {
+	name, err := r.loadBalancerConfigurationName(gatewayName)
+	if err != nil {
+		return nil
+	}
+	lbc, err := r.getLoadBalancerConfiguration(ctx, types.NamespacedName{Name: name, Namespace: gatewayNamespace})
+	if err != nil {
+		return nil
+	}
+	applied, ok := appliedSpec(lbc)
+	if !ok {
+		return nil
+	}
+	live, _, _ := unstructured.NestedMap(lbc.Object, "spec")
+	if len(specDiff(applied, live)) == 0 {
+		return nil
+	}
+	return live
+}
+
+// reportRevertedLoadBalancerConfiguration tells operators which manual changes to a Gateway's
+// LoadBalancerConfiguration the request's reconcile has overwritten
+func (r *GatewayHostnameRequestReconciler) reportRevertedLoadBalancerConfiguration(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, gatewayName, gatewayNamespace string, before map[string]interface{}) {
+	if before == nil {
+		return
+	}
+	name, err := r.loadBalancerConfigurationName(gatewayName)
+	if err != nil {
+		return
+	}
+	lbc, err := r.getLoadBalancerConfiguration(ctx, types.NamespacedName{Name: name, Namespace: gatewayNamespace})
+	if err != nil {
+		return
+	}
+	after, _, _ := unstructured.NestedMap(lbc.Object, "spec")
+	r.reportDriftReverted(ctx, ghr, "LoadBalancerConfiguration "+gatewayNamespace+"/"+name, specDiff(before, after))
+}
+
+// gatewayAnnotationDrift lists the drift-corrected annotations of the Gateway that differ from
+// the desired values. Annotations not set yet are not drift.
+func gatewayAnnotationDrift(gw *gwapiv1.Gateway, desired map[string]string) []string {
+	var changes []string
+	for _, key := range driftedGatewayAnnotations {
+		current, ok := gw.Annotations[key]
+		if !ok || current == desired[key] {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("metadata.annotations[%s]: %q -> %q", key, current, desired[key]))
+	}
+	return changes
+}
+
+// reportDriftReverted records a DriftReverted event on the request with the reverted changes
+func (r *GatewayHostnameRequestReconciler) reportDriftReverted(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, object string, changes []string) {
+	if len(changes) == 0 {
+		return
+	}
+	log.FromContext(ctx).Info("Reverted changes made outside the controller", "object", object, "changes", changes)
+	r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "DriftReverted", "Reverted changes to %s: %s", object, compactDiff(changes))
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+func TestSpecDiff(t *testing.T) {
+	before := map[string]interface{}{
+		"scheme": "internal",
+		"wafV2":  map[string]interface{}{"webACL": "arn:aws:wafv2:eu-west-1:1:regional/webacl/a/1"},
+	}
+	after := map[string]interface{}{
+		"scheme": "internet-facing",
+		"tags":   map[string]interface{}{"team": "a"},
+	}
+	assert.Equal(t, []string{
+		`scheme: "internal" -> "internet-facing"`,
+		`tags.team: <unset> -> "a"`,
+		`wafV2.webACL: "arn:aws:wafv2:eu-west-1:1:regional/webacl/a/1" -> <unset>`,
+	}, specDiff(before, after))
+	assert.Empty(t, specDiff(before, before))
+
+	changes := []string{"a", "b", "c", "d", "e", "f", "g"}
+	assert.Equal(t, "a; b; c; d; e; and 2 more", compactDiff(changes))
+}
+
+func TestReportRevertedLoadBalancerConfiguration(t *testing.T) {
+	ctx := context.Background()
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default"}}
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &GatewayHostnameRequestReconciler{Client: c, Recorder: recorder}
+	certs := []string{"arn:aws:acm:eu-west-1:123456789012:certificate/a"}
+
+	require.NoError(t, r.ensureLoadBalancerConfiguration(ctx, "gw-01", "edge", certs, "internet-facing", ""))
+	assert.Nil(t, r.modifiedLoadBalancerConfiguration(ctx, "gw-01", "edge"), "the controller's own writes are not drift")
+
+	// Someone switches the ALB to internal by hand
+	lbc := &unstructured.Unstructured{}
+	lbc.SetGroupVersionKind(LoadBalancerConfigurationGVK)
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "gw-01-config", Namespace: "edge"}, lbc))
+	require.NoError(t, unstructured.SetNestedField(lbc.Object, "internal", "spec", "scheme"))
+	require.NoError(t, c.Update(ctx, lbc))
+
+	modified := r.modifiedLoadBalancerConfiguration(ctx, "gw-01", "edge")
+	require.NotNil(t, modified)
+	require.NoError(t, r.ensureLoadBalancerConfiguration(ctx, "gw-01", "edge", certs, "internet-facing", ""))
+	r.reportRevertedLoadBalancerConfiguration(ctx, ghr, "gw-01", "edge", modified)
+
+	require.Len(t, recorder.Events, 1)
+	assert.Equal(t, `Warning DriftReverted Reverted changes to LoadBalancerConfiguration edge/gw-01-config: scheme: "internal" -> "internet-facing"`, <-recorder.Events)
+	assert.Nil(t, r.modifiedLoadBalancerConfiguration(ctx, "gw-01", "edge"))
+}
+
+func TestGatewayAnnotationDrift(t *testing.T) {
+	gw := &gwapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		AnnotationVisibility:         "internal",
+		"gateway.opendi.com/waf-arn": "",
+	}}}
+	desired := map[string]string{
+		"gateway.k8s.aws/loadbalancer-configuration": "gw-01-config",
+		AnnotationVisibility:                         "internet-facing",
+		"gateway.opendi.com/waf-arn":                 "",
+	}
+	// The missing loadbalancer-configuration annotation is being set for the first time
+	assert.Equal(t, []string{`metadata.annotations[gateway.opendi.com/visibility]: "internal" -> "internet-facing"`}, gatewayAnnotationDrift(gw, desired))
+}
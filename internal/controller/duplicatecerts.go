@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+// duplicateCertificateGracePeriod protects certificates requested moments ago, whose ARN may
+// not have been written to the request's status yet
+const duplicateCertificateGracePeriod = time.Hour
+
+var duplicateCertificates = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "gateway_orchestrator_duplicate_certificates",
+	Help: "Orchestrator-tagged ACM certificates for a hostname that no request uses, found by the last duplicate check.",
+}, []string{"hostname"})
+
+func init() {
+	metrics.Registry.MustRegister(duplicateCertificates)
+}
+
+// DuplicateCertificateChecker periodically looks for ACM certificates tagged by the orchestrator
+// for a hostname besides the one the hostname's request uses. Such duplicates are left behind by
+// races or failed status writes and count against the ACM quota. They are reported, and deleted
+// if Delete is set. It runs as a manager Runnable and only on the leader.
+type DuplicateCertificateChecker struct {
+	Client    client.Reader
+	ACMClient aws.ACMClient
+	Recorder  record.EventRecorder
+	Interval  time.Duration
+
+	// Delete removes duplicates instead of only reporting them
+	Delete bool
+
+	// ForeignOwnerTags mark certificates owned by other tools; they are never duplicates
+	ForeignOwnerTags ForeignOwnerTags
+
+	// Cluster is --cluster-name. Only certificates with this cluster tag are considered, so
+	// without it nothing is a duplicate.
+	Cluster string
+}
+
+// Start implements manager.Runnable
+func (c *DuplicateCertificateChecker) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("duplicate-certificates")
+	logger.Info("Starting duplicate certificate check", "interval", c.Interval, "delete", c.Delete)
+
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.CheckAll(log.IntoContext(ctx, logger)); err != nil {
+			logger.Error(err, "Duplicate certificate check failed")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckAll finds duplicate certificates for every hostname with a request in the cluster.
+// A certificate is a duplicate if it carries the orchestrator's tags for the hostname and the
+// cluster, namespace and environment of the hostname's request, no request references it, it
+// isn't attached to a load balancer and it is older than the grace period. Hostnames without a
+// request in this cluster are skipped: their certificates may belong to another cluster
+// sharing the account.
+func (c *DuplicateCertificateChecker) CheckAll(ctx context.Context) error {
+	logger := log.FromContext(ctx)
+
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := c.Client.List(ctx, &ghrList); err != nil {
+		return fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+	owners := map[string]*gatewayv1alpha1.GatewayHostnameRequest{}
+	referenced := map[string]bool{}
+	for i := range ghrList.Items {
+		ghr := &ghrList.Items[i]
+		// Certificates of an expansion in progress are in use too
+		for _, arn := range []string{ghr.Status.PendingCertificateArn, ghr.Status.RetiredCertificateArn} {
+			if arn != "" {
+				referenced[arn] = true
+			}
+		}
+		if ghr.Status.CertificateArn == "" {
+			continue
+		}
+		referenced[ghr.Status.CertificateArn] = true
+		owners[CanonicalHostname(ghr.Spec.Hostname)] = ghr
+	}
+
+	// No AWS call timeout: listing makes one tag call per certificate in the account
+	certs, err := c.ACMClient.ListCertificates(ctx)
+	if err != nil {
+		return err
+	}
+
+	duplicates := map[string][]string{}
+	for _, cert := range certs {
+		if cert.Tags["managed-by"] != certificateManagedBy {
+			continue
+		}
+		if _, foreign := c.ForeignOwnerTags.Match(cert.Tags); foreign {
+			continue
+		}
+		domain := CanonicalHostname(cert.Domain)
+		owner := owners[domain]
+		if owner == nil || !c.ownedBy(cert.Tags, owner) || referenced[cert.Arn] || cert.InUse ||
+			time.Since(cert.CreatedAt) < duplicateCertificateGracePeriod {
+			continue
+		}
+		duplicates[domain] = append(duplicates[domain], cert.Arn)
+	}
+
+	duplicateCertificates.Reset()
+	hostnames := make([]string, 0, len(duplicates))
+	for hostname := range duplicates {
+		hostnames = append(hostnames, hostname)
+	}
+	sort.Strings(hostnames)
+
+	for _, hostname := range hostnames {
+		arns := duplicates[hostname]
+		owner := owners[hostname]
+		logger.Info("Found duplicate certificates", "hostname", hostname, "inUse", owner.Status.CertificateArn, "duplicates", arns)
+
+		if !c.Delete {
+			duplicateCertificates.WithLabelValues(hostname).Set(float64(len(arns)))
+			c.Recorder.Eventf(owner, corev1.EventTypeWarning, "DuplicateCertificates",
+				"Found %d unused ACM certificates for %s besides %s: %s", len(arns), hostname, owner.Status.CertificateArn, strings.Join(arns, ", "))
+			continue
+		}
+
+		var remaining []string
+		for _, arn := range arns {
+			awsCtx, cancel := withAWSTimeout(ctx)
+			err := c.ACMClient.DeleteCertificate(awsCtx, arn)
+			cancel()
+			if err != nil {
+				logger.Error(err, "Failed to delete duplicate certificate", "hostname", hostname, "arn", arn)
+				remaining = append(remaining, arn)
+				continue
+			}
+			c.Recorder.Eventf(owner, corev1.EventTypeNormal, "DuplicateCertificateDeleted", "Deleted unused ACM certificate %s", arn)
+		}
+		if len(remaining) > 0 {
+			duplicateCertificates.WithLabelValues(hostname).Set(float64(len(remaining)))
+		}
+	}
+
+	return nil
+}
+
+// ownedBy reports whether the certificate's tags name this cluster and the owner's namespace
+// and environment. Another cluster or namespace may request a certificate for the same hostname
+// in the same account; its certificates are never this owner's duplicates.
+func (c *DuplicateCertificateChecker) ownedBy(tags map[string]string, owner *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	return c.Cluster != "" && tags["cluster"] == c.Cluster &&
+		tags["namespace"] == owner.Namespace && tags["environment"] == owner.Spec.Environment
+}
@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+func setupDuplicateCertificates(t *testing.T) (*aws.MockACMClient, *DuplicateCertificateChecker, map[string]string) {
+	ctx := context.Background()
+	acmMock := aws.NewMockACMClient()
+	tags := map[string]string{
+		"managed-by": certificateManagedBy, "hostname": "app.example.com",
+		"cluster": "eu-1", "namespace": "default", "environment": "",
+	}
+
+	arns := map[string]string{}
+	for _, name := range []string{"owned", "duplicate", "attached"} {
+		arn, err := acmMock.RequestCertificate(ctx, "app.example.com", name, tags)
+		require.NoError(t, err)
+		arns[name] = arn
+	}
+	acmMock.SetCertificateInUse(arns["attached"], []string{"arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/k8s-gw01/abc"})
+
+	// Not tagged by the orchestrator
+	arn, err := acmMock.RequestCertificate(ctx, "app.example.com", "manual", nil)
+	require.NoError(t, err)
+	arns["manual"] = arn
+
+	// Requested by another cluster, namespace or environment for the same hostname
+	for name, key := range map[string]string{"otherCluster": "cluster", "otherNamespace": "namespace", "otherEnvironment": "environment"} {
+		otherTags := maps.Clone(tags)
+		otherTags[key] = "other"
+		arn, err := acmMock.RequestCertificate(ctx, "app.example.com", name, otherTags)
+		require.NoError(t, err)
+		arns[name] = arn
+	}
+
+	// Hostname without a request in this cluster
+	arn, err = acmMock.RequestCertificate(ctx, "other.example.com", "foreign",
+		map[string]string{"managed-by": certificateManagedBy, "hostname": "other.example.com"})
+	require.NoError(t, err)
+	arns["foreign"] = arn
+
+	ghr := assignedGHR("app", "app.example.com")
+	ghr.Status.CertificateArn = arns["owned"]
+
+	checker := &DuplicateCertificateChecker{
+		Client:    fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr).Build(),
+		ACMClient: acmMock,
+		Recorder:  record.NewFakeRecorder(10),
+		Cluster:   "eu-1",
+	}
+	return acmMock, checker, arns
+}
+
+func TestDuplicateCertificateChecker_Reports(t *testing.T) {
+	acmMock, checker, arns := setupDuplicateCertificates(t)
+
+	require.NoError(t, checker.CheckAll(context.Background()))
+	assert.Len(t, acmMock.Certificates, 8, "nothing is deleted in report mode")
+
+	recorder := checker.Recorder.(*record.FakeRecorder)
+	require.Len(t, recorder.Events, 1)
+	event := <-recorder.Events
+	assert.Contains(t, event, "DuplicateCertificates")
+	assert.Contains(t, event, arns["duplicate"])
+	assert.NotContains(t, event, arns["attached"])
+}
+
+func TestDuplicateCertificateChecker_Deletes(t *testing.T) {
+	acmMock, checker, arns := setupDuplicateCertificates(t)
+	checker.Delete = true
+
+	require.NoError(t, checker.CheckAll(context.Background()))
+	assert.NotContains(t, acmMock.Certificates, arns["duplicate"])
+	for _, name := range []string{"owned", "attached", "manual", "foreign", "otherCluster", "otherNamespace", "otherEnvironment"} {
+		assert.Contains(t, acmMock.Certificates, arns[name], name)
+	}
+}
+
+func TestDuplicateCertificateChecker_SkipsForeignOwned(t *testing.T) {
+	acmMock, checker, arns := setupDuplicateCertificates(t)
+	checker.Delete = true
+	checker.ForeignOwnerTags = ForeignOwnerTags{{Key: "crossplane-kind"}}
+	acmMock.Tags[arns["duplicate"]] = map[string]string{
+		"managed-by": certificateManagedBy, "crossplane-kind": "certificate.acm.aws.upbound.io",
+	}
+
+	require.NoError(t, checker.CheckAll(context.Background()))
+	assert.Contains(t, acmMock.Certificates, arns["duplicate"])
+}
+
+func TestDuplicateCertificateChecker_RequiresCluster(t *testing.T) {
+	acmMock, checker, arns := setupDuplicateCertificates(t)
+	checker.Delete = true
+	checker.Cluster = ""
+
+	require.NoError(t, checker.CheckAll(context.Background()))
+	assert.Contains(t, acmMock.Certificates, arns["duplicate"], "without --cluster-name nothing is a duplicate")
+}
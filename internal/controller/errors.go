@@ -0,0 +1,26 @@
+package controller
+
+import "errors"
+
+// Wait states: the step cannot finish yet because something outside the controller is still
+// in progress. Callers branch on them with errors.Is and requeue instead of failing the request,
+// so wrap them with %w when adding context.
+var (
+	// ErrValidationRecordsNotReady means ACM has not published the DNS validation records yet
+	ErrValidationRecordsNotReady = errors.New("validation records not ready")
+
+	// ErrLoadBalancerNotReady means the AWS Load Balancer Controller has not published the
+	// ALB address on the Gateway yet
+	ErrLoadBalancerNotReady = errors.New("LoadBalancer address not available yet")
+
+	// ErrChallengeRecordsPropagating means DNS-01 challenge records were just published and
+	// are given time to reach the Route53 name servers before the challenges are accepted
+	ErrChallengeRecordsPropagating = errors.New("challenge records propagating")
+
+	// ErrGatewayCreationThrottled means a new Gateway is needed but the creation cooldown has
+	// not passed yet, or older requests are queued for the next creation
+	ErrGatewayCreationThrottled = errors.New("gateway creation throttled")
+
+	// ErrNoGatewayAssigned means the request has not been assigned to a Gateway yet
+	ErrNoGatewayAssigned = errors.New("no gateway assigned yet")
+)
@@ -0,0 +1,246 @@
+package controller
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// explainStatus composes status.message: a single sentence telling application developers what
+// the request is waiting for or why it is stuck, so they don't have to read the conditions.
+// It looks at the first provisioning step that isn't done, after the states that override it
+// (deletion, validate-only requests, claim conflicts, validation errors, quarantine, policy
+// denials, moves).
+// The message only depends on the status, never on the current time: writing it must not
+// trigger another reconcile that writes it again.
+func explainStatus(ghr *gatewayv1alpha1.GatewayHostnameRequest) string {
+	conds := ghr.Status.Conditions
+	if cond := conditions.Get(conds, ConditionTypeDeleting); cond != nil {
+		return "Deleting: " + withAge(cond.Message, cond)
+	}
+	if conditions.IsReady(conds) {
+		return "Ready" + readyNotes(ghr)
+	}
+	if cond := conditions.Get(conds, ConditionTypeValidated); cond != nil {
+		if cond.Status == metav1.ConditionTrue {
+			return fmt.Sprintf("Valid; nothing is provisioned while %s is set", AnnotationValidateOnly)
+		}
+		return "The request is invalid: " + cond.Message
+	}
+
+	if cond := conditions.Get(conds, ConditionTypeClaimed); cond != nil && cond.Status == metav1.ConditionFalse {
+		if cond.Reason == string(conditions.ReasonAlreadyClaimed) {
+			return fmt.Sprintf("Hostname %s is already claimed by another request; delete that request or choose another hostname", ghr.Spec.Hostname)
+		}
+		if cond.Reason == string(conditions.ReasonReservedForNamespace) {
+			return cond.Message + "; request it from that namespace or choose another hostname"
+		}
+		if cond.Reason == string(conditions.ReasonResolvesElsewhere) {
+			return cond.Message + "; set spec.allowTakeover: true to replace those records"
+		}
+		if cond.Reason == string(conditions.ReasonOwnershipMismatch) {
+			return cond.Message
+		}
+		if cond.Reason == string(conditions.ReasonSetIdentifierTaken) {
+			return cond.Message + "; recreate the request with another sharedOwnership.setIdentifier"
+		}
+		return "Could not claim the hostname: " + cond.Message
+	}
+	if conditions.HasReason(conds, ConditionTypeReady, metav1.ConditionFalse, conditions.ReasonValidationFailed) {
+		return "The request is invalid: " + conditions.Get(conds, ConditionTypeReady).Message
+	}
+	if conditions.IsTrue(conds, ConditionTypeQuarantined) {
+		return "Quarantined pending approval: " + conditions.Get(conds, ConditionTypeQuarantined).Message
+	}
+	if conditions.HasReason(conds, ConditionTypeReady, metav1.ConditionFalse, conditions.ReasonPolicyDenied) {
+		return "Denied by the hostname policy: " + conditions.Get(conds, ConditionTypeReady).Message
+	}
+	if conditions.IsTrue(conds, ConditionTypeMigrating) {
+		cond := conditions.Get(conds, ConditionTypeMigrating)
+		return fmt.Sprintf("Moving to Gateway %s (%s): %s", ghr.Status.AssignedGateway, cond.Reason, withAge(cond.Message, cond))
+	}
+
+	if step := pendingStep(ghr); step != "" {
+		return explainStep(ghr, step, conditions.Get(conds, step)) + failureNote(ghr)
+	}
+	return "Finishing provisioning" + failureNote(ghr)
+}
+
+// provisioningSteps returns the conditions a request passes through in order; HTTP-only
+// requests have no certificate and DNS-only requests no Gateway. The route access steps only
+// count once set, as they are only set in strict route access mode.
+func provisioningSteps(ghr *gatewayv1alpha1.GatewayHostnameRequest) []string {
+	var steps []string
+	steps = append(steps, ConditionTypeClaimed)
+	if ghr.Spec.DelegatedZone != "" {
+		steps = append(steps, ConditionTypeZoneDelegated)
+	}
+	if !isHTTPOnly(ghr) {
+		steps = append(steps, ConditionTypeCertificateRequested, ConditionTypeDnsValidated, ConditionTypeCertificateIssued)
+	}
+	if !isDNSOnly(ghr) {
+		steps = append(steps, ConditionTypeListenerAttached)
+	}
+	steps = append(steps, ConditionTypeDnsAliasReady)
+	for _, step := range []string{ConditionTypeNamespaceLabeled, ConditionTypeAllowedRoutesConfigured} {
+		if conditions.Get(ghr.Status.Conditions, step) != nil {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+// pendingStep returns the first provisioning step that isn't done, or "" if all are
+func pendingStep(ghr *gatewayv1alpha1.GatewayHostnameRequest) string {
+	for _, step := range provisioningSteps(ghr) {
+		if !conditions.IsTrue(ghr.Status.Conditions, step) {
+			return step
+		}
+	}
+	return ""
+}
+
+// explainStep explains the first provisioning step that isn't done; cond is nil if the step
+// hasn't started
+func explainStep(ghr *gatewayv1alpha1.GatewayHostnameRequest, step string, cond *metav1.Condition) string {
+	reason := ""
+	if cond != nil {
+		reason = cond.Reason
+	}
+	if conditions.Reason(reason) == conditions.ReasonDNSSECDegraded {
+		return "DNS changes are on hold because DNSSEC signing of the zone is degraded: " + withAge(cond.Message, cond)
+	}
+
+	switch step {
+	case ConditionTypeClaimed:
+		return "Claiming the hostname"
+
+	case ConditionTypeZoneDelegated:
+		switch conditions.Reason(reason) {
+		case "":
+			return fmt.Sprintf("Delegating zone %s", ghr.Spec.DelegatedZone)
+		case conditions.ReasonZoneNotFound:
+			return cond.Message
+		}
+		return fmt.Sprintf("Could not delegate zone %s: %s", ghr.Spec.DelegatedZone, withAge(cond.Message, cond))
+
+	case ConditionTypeCertificateRequested:
+		switch conditions.Reason(reason) {
+		case "":
+			return "Requesting a certificate"
+		case conditions.ReasonCAAForbidsAmazon, conditions.ReasonIssuerNotConfigured:
+			return "The certificate can't be issued: " + cond.Message
+		}
+		return "The certificate request failed: " + withAge(cond.Message, cond)
+
+	case ConditionTypeDnsValidated:
+		switch conditions.Reason(reason) {
+		case "":
+			return "Creating the DNS validation records"
+		case conditions.ReasonPendingValidationRecords:
+			return "Waiting for ACM to provide the DNS validation record; " + age(cond)
+		case conditions.ReasonChallengeRecordsPropagating:
+			return "Waiting for the DNS-01 challenge record to propagate; " + age(cond)
+		}
+		return "Could not create the DNS validation record: " + withAge(cond.Message, cond)
+
+	case ConditionTypeCertificateIssued:
+		switch conditions.Reason(reason) {
+		case "":
+			return "Waiting for the certificate to be issued"
+		case conditions.ReasonPendingIssuance:
+			if certificateIssuer(ghr) == CertificateIssuerACME {
+				return "Waiting for the ACME CA to validate the DNS-01 challenge; " + age(cond)
+			}
+			return fmt.Sprintf("Waiting for ACM validation: the validation record in zone %s is not resolvable yet; %s",
+				recordZoneID(ghr), age(cond))
+		}
+		return "Certificate issuance failed: " + withAge(cond.Message, cond)
+
+	case ConditionTypeListenerAttached:
+		switch conditions.Reason(reason) {
+		case "":
+			return "Assigning the hostname to a Gateway"
+		case conditions.ReasonGatewayCreationThrottled:
+			return "Waiting for a new Gateway: " + withAge(cond.Message, cond)
+		case conditions.ReasonPendingAcceptance:
+			return withAge(cond.Message, cond)
+		case conditions.ReasonAttachmentRejected:
+			return "The AWS Load Balancer Controller rejected the Gateway: " + withAge(cond.Message, cond)
+		}
+		return "Could not attach the hostname to a Gateway: " + withAge(cond.Message, cond)
+
+	case ConditionTypeDnsAliasReady:
+		if cond == nil {
+			if isDNSOnly(ghr) {
+				return "Creating the DNS records for the alias target"
+			}
+			return fmt.Sprintf("Waiting for the load balancer of Gateway %s to be provisioned", ghr.Status.AssignedGateway)
+		}
+		return "Could not create the DNS records: " + withAge(cond.Message, cond)
+
+	case ConditionTypeNamespaceLabeled:
+		return fmt.Sprintf("HTTPRoutes can't attach yet, labeling namespace %s failed: %s", ghr.Namespace, withAge(cond.Message, cond))
+
+	case ConditionTypeAllowedRoutesConfigured:
+		return fmt.Sprintf("HTTPRoutes can't attach yet, configuring allowedRoutes of Gateway %s failed: %s", ghr.Status.AssignedGateway, withAge(cond.Message, cond))
+	}
+
+	if cond == nil {
+		return "Waiting for " + step
+	}
+	return step + ": " + withAge(cond.Message, cond)
+}
+
+// readyNotes lists what a Ready request should know about, e.g. "; DNSSEC signing degraded"
+func readyNotes(ghr *gatewayv1alpha1.GatewayHostnameRequest) string {
+	notes := ""
+	for _, t := range []string{ConditionTypeGatewayOverCapacity, ConditionTypeGatewayChangePending, ConditionTypeReprovisionPending, ConditionTypeDNSSECDegraded, ConditionTypeForeignOwnership} {
+		if conditions.IsTrue(ghr.Status.Conditions, t) {
+			notes += "; " + conditions.Get(ghr.Status.Conditions, t).Message
+		}
+	}
+	if ghr.Status.OverrideTarget != "" {
+		notes += fmt.Sprintf("; DNS overridden to %s", ghr.Status.OverrideTarget)
+	}
+	if detached := detachedRoutes(ghr); detached != "" {
+		notes += fmt.Sprintf("; HTTPRoutes %s don't reference Gateway %s", detached, ghr.Status.AssignedGateway)
+	}
+	if ghr.Status.ConsecutiveFailures > 0 {
+		notes += fmt.Sprintf("; the last %d reconciles failed: %s", ghr.Status.ConsecutiveFailures, ghr.Status.LastFailure)
+	}
+	return notes
+}
+
+// failureNote mentions reconciles failing in a row, which point at a problem the conditions
+// may not show yet
+func failureNote(ghr *gatewayv1alpha1.GatewayHostnameRequest) string {
+	if ghr.Status.ConsecutiveFailures < 2 {
+		return ""
+	}
+	return fmt.Sprintf(" (failed %d times in a row: %s)", ghr.Status.ConsecutiveFailures, ghr.Status.LastFailure)
+}
+
+// age says since when the condition has been pending, e.g. "pending since 2026-01-02 15:04 UTC"
+func age(cond *metav1.Condition) string {
+	if cond.LastTransitionTime.IsZero() {
+		return "pending"
+	}
+	return "pending since " + since(cond)
+}
+
+// withAge appends since when the condition has been in its state to a message
+func withAge(message string, cond *metav1.Condition) string {
+	if cond == nil || cond.LastTransitionTime.IsZero() {
+		return message
+	}
+	return fmt.Sprintf("%s (since %s)", message, since(cond))
+}
+
+// since formats the condition's last transition; absolute so the message stays stable
+func since(cond *metav1.Condition) string {
+	return cond.LastTransitionTime.UTC().Format("2006-01-02 15:04 UTC")
+}
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+)
+
+func TestExplainStatus(t *testing.T) {
+	since := metav1.NewTime(time.Date(2026, 1, 2, 15, 4, 0, 0, time.UTC))
+	cond := func(condType string, status metav1.ConditionStatus, reason conditions.Reason, message string) metav1.Condition {
+		return metav1.Condition{Type: condType, Status: status, Reason: string(reason), Message: message, LastTransitionTime: since}
+	}
+	claimed := cond(ConditionTypeClaimed, metav1.ConditionTrue, conditions.ReasonClaimed, "")
+	requested := cond(ConditionTypeCertificateRequested, metav1.ConditionTrue, conditions.ReasonRequested, "")
+	validated := cond(ConditionTypeDnsValidated, metav1.ConditionTrue, conditions.ReasonRecordsCreated, "")
+	issued := cond(ConditionTypeCertificateIssued, metav1.ConditionTrue, conditions.ReasonIssued, "")
+	attached := cond(ConditionTypeListenerAttached, metav1.ConditionTrue, conditions.ReasonAttached, "")
+
+	ghr := assignedGHR("app", "app.example.com")
+	ghr.Spec.ZoneId = "Z123"
+	assert.Equal(t, "Claiming the hostname", explainStatus(ghr))
+
+	ghr.Status.Conditions = []metav1.Condition{claimed, requested, validated,
+		cond(ConditionTypeCertificateIssued, metav1.ConditionFalse, conditions.ReasonPendingIssuance, "Certificate status: PENDING_VALIDATION")}
+	assert.Equal(t, "Waiting for ACM validation: the validation record in zone Z123 is not resolvable yet; pending since 2026-01-02 15:04 UTC",
+		explainStatus(ghr))
+
+	ghr.Status.ConsecutiveFailures = 3
+	ghr.Status.LastFailure = "throttled"
+	assert.Contains(t, explainStatus(ghr), "(failed 3 times in a row: throttled)")
+	ghr.Status.ConsecutiveFailures = 0
+
+	ghr.Status.Conditions = []metav1.Condition{claimed, requested, validated, issued, attached}
+	assert.Equal(t, "Waiting for the load balancer of Gateway gw-01 to be provisioned", explainStatus(ghr))
+
+	ghr.Status.Conditions = append(ghr.Status.Conditions,
+		cond(ConditionTypeReady, metav1.ConditionTrue, conditions.ReasonReady, ""),
+		cond(ConditionTypeDNSSECDegraded, metav1.ConditionTrue, conditions.ReasonSigningDegraded, "DNSSEC signing of zone Z123 is degraded"))
+	assert.Equal(t, "Ready; DNSSEC signing of zone Z123 is degraded", explainStatus(ghr))
+
+	ghr.Status.Conditions = []metav1.Condition{cond(ConditionTypeClaimed, metav1.ConditionFalse, conditions.ReasonAlreadyClaimed, "")}
+	assert.Contains(t, explainStatus(ghr), "already claimed by another request")
+}
+
+func TestExplainStatus_HTTPOnlySkipsCertificate(t *testing.T) {
+	ghr := assignedGHR("app", "app.example.com")
+	ghr.Spec.TLS = TLSDisabled
+	ghr.Status.Conditions = []metav1.Condition{{Type: ConditionTypeClaimed, Status: metav1.ConditionTrue, Reason: string(conditions.ReasonClaimed)}}
+	assert.Equal(t, "Assigning the hostname to a Gateway", explainStatus(ghr))
+}
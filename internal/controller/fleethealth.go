@@ -0,0 +1,282 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// Health states of a hostname, used as the "state" label on hostnamesByState
+const (
+	HealthReady       = "Ready"
+	HealthProgressing = "Progressing"
+	HealthDegraded    = "Degraded"
+	HealthFailed      = "Failed"
+)
+
+// Reconcile results used as the "result" label on reconcileTotal
+const (
+	ReconcileSuccess = "success"
+	ReconcileError   = "error"
+	// ReconcileSkipped counts reconciles skipped because the desired state was unchanged
+	ReconcileSkipped = "skipped"
+)
+
+// Defaults for FleetHealth
+const (
+	DefaultFailedThreshold = 5
+	DefaultReadyObjective  = 0.99
+)
+
+var (
+	reconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gateway_orchestrator_reconcile_total",
+		Help: "GatewayHostnameRequest reconciles by result.",
+	}, []string{"result"})
+
+	reconcileConsecutiveFailures = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gateway_orchestrator_reconcile_consecutive_failures",
+		Help: "Reconciles of a GatewayHostnameRequest that failed in a row; only exported while non-zero.",
+	}, []string{"namespace", "name", "hostname"})
+
+	hostnamesByState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gateway_orchestrator_hostnames",
+		Help: "GatewayHostnameRequests by health state: Ready, Progressing, Degraded or Failed.",
+	}, []string{"state"})
+
+	errorBudgetRemaining = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gateway_orchestrator_error_budget_remaining",
+		Help: "Fraction of the error budget of the ready objective left; negative when overspent.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcileTotal, reconcileConsecutiveFailures, hostnamesByState, errorBudgetRemaining)
+}
+
+// recordReconcileResult counts the reconcile and keeps status.consecutiveFailures and
+// status.message current. The status is only written when either changes, so successful
+// reconciles of a healthy request cost no extra write.
+func (r *GatewayHostnameRequestReconciler) recordReconcileResult(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, reconcileErr error) {
+	failuresBefore, messageBefore := ghr.Status.ConsecutiveFailures, ghr.Status.Message
+	if reconcileErr == nil {
+		reconcileTotal.WithLabelValues(ReconcileSuccess).Inc()
+		recordSuccessfulReconcile(ghr)
+		forgetReconcileFailures(ghr)
+		ghr.Status.ConsecutiveFailures = 0
+	} else {
+		reconcileTotal.WithLabelValues(ReconcileError).Inc()
+		ghr.Status.ConsecutiveFailures++
+		ghr.Status.LastFailure = reconcileErr.Error()
+		reconcileConsecutiveFailures.WithLabelValues(ghr.Namespace, ghr.Name, ghr.Spec.Hostname).Set(float64(ghr.Status.ConsecutiveFailures))
+	}
+	ghr.Status.Message = explainStatus(ghr)
+	if ghr.Status.ConsecutiveFailures == failuresBefore && ghr.Status.Message == messageBefore {
+		return
+	}
+	if err := r.Status().Update(ctx, ghr); err != nil {
+		log.FromContext(ctx).Info("Failed to record reconcile result", "error", err.Error())
+	}
+}
+
+// forgetReconcileFailures removes the request's failure metrics
+func forgetReconcileFailures(ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+	reconcileConsecutiveFailures.DeletePartialMatch(prometheus.Labels{"namespace": ghr.Namespace, "name": ghr.Name})
+}
+
+// hostnameHealth classifies a request for the fleet overview:
+//   - Failed: not Ready and either failed failedThreshold reconciles in a row or lost the claim
+//     to another request; it needs attention
+//   - Degraded: Ready, but reconciles are failing or a negative condition is set
+//   - Ready: Ready and nothing to report
+//   - Progressing: everything else, e.g. waiting for certificate issuance or a load balancer
+func hostnameHealth(ghr *gatewayv1alpha1.GatewayHostnameRequest, failedThreshold int32) string {
+	if meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeReady) {
+		if ghr.Status.ConsecutiveFailures > 0 ||
+			meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeDNSSECDegraded) ||
+			meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeGatewayOverCapacity) {
+			return HealthDegraded
+		}
+		return HealthReady
+	}
+	if ghr.Status.ConsecutiveFailures >= failedThreshold ||
+		conditions.HasReason(ghr.Status.Conditions, ConditionTypeClaimed, metav1.ConditionFalse, conditions.ReasonAlreadyClaimed) ||
+		conditions.HasReason(ghr.Status.Conditions, ConditionTypeClaimed, metav1.ConditionFalse, conditions.ReasonReservedForNamespace) {
+		return HealthFailed
+	}
+	return HealthProgressing
+}
+
+// FleetStats summarizes the health of all hostnames, as served on /stats
+type FleetStats struct {
+	Total       int `json:"total"`
+	Ready       int `json:"ready"`
+	Progressing int `json:"progressing"`
+	Degraded    int `json:"degraded"`
+	Failed      int `json:"failed"`
+
+	// Objective is the target fraction of hostnames that are not Failed
+	Objective float64 `json:"objective"`
+	// ErrorBudgetRemaining is the fraction of the allowed Failed hostnames not used up;
+	// negative when overspent
+	ErrorBudgetRemaining float64 `json:"errorBudgetRemaining"`
+
+	// Unhealthy lists the Degraded and Failed hostnames, most consecutive failures first
+	Unhealthy []UnhealthyHostname `json:"unhealthy,omitempty"`
+
+	// PendingByReason counts the hostnames that aren't Ready or are being deleted by what they
+	// wait for, e.g. CertificateIssued/PendingIssuance
+	PendingByReason map[string]int `json:"pendingByReason,omitempty"`
+	// OldestPendingSeconds is how long the longest-waiting of them has been waiting
+	OldestPendingSeconds float64 `json:"oldestPendingSeconds,omitempty"`
+}
+
+// UnhealthyHostname is a Degraded or Failed hostname in FleetStats
+type UnhealthyHostname struct {
+	Namespace           string `json:"namespace"`
+	Name                string `json:"name"`
+	Hostname            string `json:"hostname"`
+	State               string `json:"state"`
+	ConsecutiveFailures int32  `json:"consecutiveFailures,omitempty"`
+	LastFailure         string `json:"lastFailure,omitempty"`
+}
+
+// FleetHealth summarizes the health of all GatewayHostnameRequests for dashboards. It exports
+// the counts per state and the remaining error budget as metrics, as a manager Runnable on the
+// leader, and serves the same summary as JSON, on every replica.
+type FleetHealth struct {
+	Client   client.Reader
+	Interval time.Duration
+
+	// FailedThreshold is the number of failed reconciles in a row after which a request that
+	// isn't Ready counts as Failed (default DefaultFailedThreshold)
+	FailedThreshold int32
+	// Objective is the target fraction of hostnames that are not Failed
+	// (default DefaultReadyObjective)
+	Objective float64
+}
+
+// Start implements manager.Runnable
+func (f *FleetHealth) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("fleet-health")
+	logger.Info("Starting fleet health summary", "interval", f.Interval)
+
+	ticker := time.NewTicker(f.Interval)
+	defer ticker.Stop()
+
+	for {
+		if stats, err := f.Collect(ctx); err != nil {
+			logger.Error(err, "Fleet health summary failed")
+		} else {
+			for state, count := range map[string]int{
+				HealthReady:       stats.Ready,
+				HealthProgressing: stats.Progressing,
+				HealthDegraded:    stats.Degraded,
+				HealthFailed:      stats.Failed,
+			} {
+				hostnamesByState.WithLabelValues(state).Set(float64(count))
+			}
+			errorBudgetRemaining.Set(stats.ErrorBudgetRemaining)
+			pendingHostnames.Reset()
+			for reason, count := range stats.PendingByReason {
+				pendingHostnames.WithLabelValues(reason).Set(float64(count))
+			}
+			oldestPendingSeconds.Set(stats.OldestPendingSeconds)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Collect classifies every request and computes the error budget
+func (f *FleetHealth) Collect(ctx context.Context) (*FleetStats, error) {
+	failedThreshold := f.FailedThreshold
+	if failedThreshold <= 0 {
+		failedThreshold = DefaultFailedThreshold
+	}
+	objective := f.Objective
+	if objective <= 0 {
+		objective = DefaultReadyObjective
+	}
+
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := f.Client.List(ctx, &ghrList); err != nil {
+		return nil, fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+
+	now := time.Now()
+	stats := &FleetStats{Total: len(ghrList.Items), Objective: objective}
+	for i := range ghrList.Items {
+		ghr := &ghrList.Items[i]
+		if !meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeReady) || !ghr.DeletionTimestamp.IsZero() {
+			if stats.PendingByReason == nil {
+				stats.PendingByReason = map[string]int{}
+			}
+			stats.PendingByReason[pendingReason(ghr)]++
+			if since := pendingSince(ghr); !since.IsZero() && now.Sub(since).Seconds() > stats.OldestPendingSeconds {
+				stats.OldestPendingSeconds = now.Sub(since).Seconds()
+			}
+		}
+
+		state := hostnameHealth(ghr, failedThreshold)
+		switch state {
+		case HealthReady:
+			stats.Ready++
+			continue
+		case HealthProgressing:
+			stats.Progressing++
+			continue
+		case HealthDegraded:
+			stats.Degraded++
+		case HealthFailed:
+			stats.Failed++
+		}
+		stats.Unhealthy = append(stats.Unhealthy, UnhealthyHostname{
+			Namespace:           ghr.Namespace,
+			Name:                ghr.Name,
+			Hostname:            ghr.Spec.Hostname,
+			State:               state,
+			ConsecutiveFailures: ghr.Status.ConsecutiveFailures,
+			LastFailure:         ghr.Status.LastFailure,
+		})
+	}
+	sort.SliceStable(stats.Unhealthy, func(i, j int) bool {
+		return stats.Unhealthy[i].ConsecutiveFailures > stats.Unhealthy[j].ConsecutiveFailures
+	})
+
+	// The budget is the number of hostnames allowed to be Failed under the objective
+	stats.ErrorBudgetRemaining = 1
+	if budget := (1 - objective) * float64(stats.Total); budget > 0 {
+		stats.ErrorBudgetRemaining = 1 - float64(stats.Failed)/budget
+	} else if stats.Failed > 0 {
+		stats.ErrorBudgetRemaining = 0
+	}
+	return stats, nil
+}
+
+// ServeHTTP serves the current FleetStats as JSON
+func (f *FleetHealth) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	stats, err := f.Collect(req.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(stats)
+}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestHostnameHealth(t *testing.T) {
+	ready := metav1.Condition{Type: ConditionTypeReady, Status: metav1.ConditionTrue, Reason: "Ready"}
+
+	ghr := assignedGHR("app", "app.example.com")
+	assert.Equal(t, HealthProgressing, hostnameHealth(ghr, 5))
+
+	ghr.Status.ConsecutiveFailures = 5
+	assert.Equal(t, HealthFailed, hostnameHealth(ghr, 5))
+
+	ghr.Status.Conditions = []metav1.Condition{ready}
+	assert.Equal(t, HealthDegraded, hostnameHealth(ghr, 5), "serving, but reconciles keep failing")
+
+	ghr.Status.ConsecutiveFailures = 0
+	assert.Equal(t, HealthReady, hostnameHealth(ghr, 5))
+
+	ghr.Status.Conditions = append(ghr.Status.Conditions,
+		metav1.Condition{Type: ConditionTypeDNSSECDegraded, Status: metav1.ConditionTrue, Reason: "SigningKeyInactive"})
+	assert.Equal(t, HealthDegraded, hostnameHealth(ghr, 5))
+
+	ghr.Status.Conditions = []metav1.Condition{{Type: ConditionTypeClaimed, Status: metav1.ConditionFalse, Reason: "AlreadyClaimed"}}
+	assert.Equal(t, HealthFailed, hostnameHealth(ghr, 5))
+}
+
+func TestRecordReconcileResult(t *testing.T) {
+	ctx := context.Background()
+	ghr := assignedGHR("app", "app.example.com")
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr).WithStatusSubresource(ghr).Build()
+	r := &GatewayHostnameRequestReconciler{Client: c, Recorder: record.NewFakeRecorder(10)}
+
+	r.recordReconcileResult(ctx, ghr, errors.New("throttled"))
+	r.recordReconcileResult(ctx, ghr, errors.New("access denied"))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(ghr), ghr))
+	assert.Equal(t, int32(2), ghr.Status.ConsecutiveFailures)
+	assert.Equal(t, "access denied", ghr.Status.LastFailure)
+
+	r.recordReconcileResult(ctx, ghr, nil)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(ghr), ghr))
+	assert.Zero(t, ghr.Status.ConsecutiveFailures)
+	assert.Equal(t, "access denied", ghr.Status.LastFailure, "the last failure is kept for reference")
+}
+
+func TestFleetHealth_Stats(t *testing.T) {
+	ready := []metav1.Condition{{Type: ConditionTypeReady, Status: metav1.ConditionTrue, Reason: "Ready"}}
+	var objects []client.Object
+	for _, name := range []string{"a", "b", "c"} {
+		ghr := assignedGHR(name, name+".example.com")
+		ghr.Status.Conditions = ready
+		objects = append(objects, ghr)
+	}
+	pending := assignedGHR("pending", "pending.example.com")
+	pending.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	failing := assignedGHR("failing", "failing.example.com")
+	failing.Status.ConsecutiveFailures = 7
+	failing.Status.LastFailure = "AccessDenied"
+	objects = append(objects, pending, failing)
+
+	f := &FleetHealth{
+		Client:    fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(objects...).Build(),
+		Objective: 0.5,
+	}
+
+	rec := httptest.NewRecorder()
+	f.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var stats FleetStats
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	assert.Equal(t, 5, stats.Total)
+	assert.Equal(t, 3, stats.Ready)
+	assert.Equal(t, 1, stats.Progressing)
+	assert.Equal(t, 1, stats.Failed)
+	// 2.5 hostnames may fail under the objective, one does
+	assert.InDelta(t, 0.6, stats.ErrorBudgetRemaining, 0.001)
+	require.Len(t, stats.Unhealthy, 1)
+	assert.Equal(t, "failing.example.com", stats.Unhealthy[0].Hostname)
+	assert.Equal(t, "AccessDenied", stats.Unhealthy[0].LastFailure)
+	assert.Equal(t, map[string]int{ConditionTypeClaimed: 2}, stats.PendingByReason)
+	assert.InDelta(t, time.Hour.Seconds(), stats.OldestPendingSeconds, 60)
+}
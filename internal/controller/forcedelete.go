@@ -0,0 +1,209 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+// AnnotationForceDeleteAfter overrides --force-delete-after for one request, as a Go duration
+// ("0s" keeps the finalizer until the cleanup succeeds)
+const AnnotationForceDeleteAfter = "gateway.opendi.com/force-delete-after"
+
+//+kubebuilder:rbac:groups=gateway.opendi.com,resources=orphans,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=gateway.opendi.com,resources=orphans/status,verbs=get;update;patch
+
+// forceDeleteAfter returns how long the request's deletion may fail before its finalizer is
+// removed anyway; 0 means never
+func (r *GatewayHostnameRequestReconciler) forceDeleteAfter(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) time.Duration {
+	if value, ok := ghr.Annotations[AnnotationForceDeleteAfter]; ok {
+		d, err := time.ParseDuration(value)
+		if err == nil && d >= 0 {
+			return d
+		}
+		log.FromContext(ctx).Info("Ignoring invalid annotation", "annotation", AnnotationForceDeleteAfter, "value", value)
+	}
+	return r.ForceDeleteAfter
+}
+
+// forceDeleteDue reports whether the request has been deleting for longer than forceDeleteAfter
+func (r *GatewayHostnameRequestReconciler) forceDeleteDue(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	after := r.forceDeleteAfter(ctx, ghr)
+	return after > 0 && ghr.DeletionTimestamp != nil && time.Since(ghr.DeletionTimestamp.Time) >= after
+}
+
+// orphanName names the Orphan of a request after its UID, so a recreated request gets its own
+func orphanName(ghr *gatewayv1alpha1.GatewayHostnameRequest) string {
+	return string(ghr.UID)
+}
+
+// newOrphan records the certificate of a request, and its alias records unless they were
+// deleted already
+func newOrphan(ghr *gatewayv1alpha1.GatewayHostnameRequest, withRecords bool, reason string) *gatewayv1alpha1.Orphan {
+	orphan := &gatewayv1alpha1.Orphan{
+		ObjectMeta: metav1.ObjectMeta{Name: orphanName(ghr)},
+		Spec: gatewayv1alpha1.OrphanSpec{
+			Request: gatewayv1alpha1.DomainClaimOwnerRef{
+				Namespace: ghr.Namespace,
+				Name:      ghr.Name,
+				UID:       string(ghr.UID),
+			},
+			Hostname:       ghr.Spec.Hostname,
+			Reason:         reason,
+			CertificateArn: ghr.Status.CertificateArn,
+		},
+	}
+	if ghr.Status.CertificateArn != "" {
+		orphan.Spec.CertificateZoneId = recordZoneID(ghr)
+	}
+	if withRecords && ghr.Status.AssignedLoadBalancer != "" {
+		zoneIDs := ghr.Status.AliasZoneIds
+		if len(zoneIDs) == 0 {
+			zoneIDs = aliasZoneIds(ghr)
+		}
+		for _, zoneID := range zoneIDs {
+			for _, recordType := range []string{"A", "AAAA"} {
+				orphan.Spec.Records = append(orphan.Spec.Records, gatewayv1alpha1.OrphanRecord{
+					ZoneId:        zoneID,
+					Name:          ghr.Spec.Hostname,
+					Type:          recordType,
+					AliasTarget:   publishedAliasTarget(ghr),
+					SetIdentifier: setIdentifier(ghr),
+				})
+			}
+		}
+	}
+	return orphan
+}
+
+// abandonDeletion gives up on the request's failing cleanup: it records what may be left in AWS
+// in an Orphan for the orphan sweep and removes the finalizer, so the request (and its
+// namespace) can go away
+func (r *GatewayHostnameRequestReconciler) abandonDeletion(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, withRecords bool, cause error) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	orphan := newOrphan(ghr, withRecords, cause.Error())
+	if len(orphan.Spec.Records) > 0 || orphan.Spec.CertificateArn != "" {
+		if err := r.Create(ctx, orphan); err != nil && !apierrors.IsAlreadyExists(err) {
+			return ctrl.Result{}, fmt.Errorf("failed to record Orphan for %s: %w", ghr.Spec.Hostname, err)
+		}
+	}
+
+	after := r.forceDeleteAfter(ctx, ghr)
+	logger.Info("Removing finalizer although the cleanup failed",
+		"forceDeleteAfter", after,
+		"orphan", orphan.Name,
+		"error", cause.Error())
+	r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "ForceDeleted",
+		"Removing the finalizer after %s of failed cleanup: %v; Orphan %s records what was left behind",
+		after, cause, orphan.Name)
+	return r.finalizeDeletion(ctx, ghr)
+}
+
+// cleanupOrphan deletes what an Orphan recorded: its alias records that still point at the
+// request's load balancer, and its certificate with its validation records. Records are left
+// alone while another request holds the hostname, since they are its records now.
+func (r *GatewayHostnameRequestReconciler) cleanupOrphan(ctx context.Context, orphan *gatewayv1alpha1.Orphan) error {
+	logger := log.FromContext(ctx)
+
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.apiReader().List(ctx, &ghrList); err != nil {
+		return fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+	hostname := CanonicalHostname(orphan.Spec.Hostname)
+	for _, ghr := range ghrList.Items {
+		if CanonicalHostname(ghr.Spec.Hostname) == hostname && string(ghr.UID) != orphan.Spec.Request.UID {
+			logger.Info("Hostname is requested again, leaving its records in place",
+				"hostname", hostname, "request", ghr.Namespace+"/"+ghr.Name)
+			return r.deleteOrphanCertificate(ctx, orphan, nil)
+		}
+	}
+
+	byZone := map[string][]aws.DNSRecord{}
+	for _, record := range orphan.Spec.Records {
+		existing, err := r.getRecord(aws.WithSetIdentifier(ctx, record.SetIdentifier), record.ZoneId, record.Name, record.Type)
+		if err != nil {
+			return fmt.Errorf("failed to look up %s record %s in zone %s: %w", record.Type, record.Name, record.ZoneId, err)
+		}
+		if existing == nil || existing.AliasTarget == nil ||
+			!strings.EqualFold(strings.TrimSuffix(existing.AliasTarget.DNSName, "."), strings.TrimSuffix(record.AliasTarget, ".")) {
+			continue
+		}
+		byZone[record.ZoneId] = append(byZone[record.ZoneId], *existing)
+	}
+	return r.deleteOrphanCertificate(ctx, orphan, byZone)
+}
+
+// deleteOrphanCertificate deletes the records in byZone and, if the Orphan has a certificate,
+// its validation records (unless byZone is nil or the hostname was shared with other owners,
+// whose certificates are validated through them) and the certificate once no ALB uses it
+func (r *GatewayHostnameRequestReconciler) deleteOrphanCertificate(ctx context.Context, orphan *gatewayv1alpha1.Orphan, byZone map[string][]aws.DNSRecord) error {
+	certArn := orphan.Spec.CertificateArn
+	shared := slices.ContainsFunc(orphan.Spec.Records, func(record gatewayv1alpha1.OrphanRecord) bool {
+		return record.SetIdentifier != ""
+	})
+	if certArn != "" && byZone != nil && !shared {
+		awsCtx, cancel := withAWSTimeout(ctx)
+		validationRecords, err := r.ACMClient.GetValidationRecords(awsCtx, certArn)
+		cancel()
+		if aws.IsNotFound(err) {
+			// Deleted meanwhile; its validation records can't be found without it
+			certArn = ""
+		} else if err != nil {
+			return fmt.Errorf("failed to get validation records of %s: %w", certArn, err)
+		}
+		existing, err := r.existingValidationRecords(ctx, orphan.Spec.CertificateZoneId, validationRecords)
+		if err != nil {
+			return err
+		}
+		byZone[orphan.Spec.CertificateZoneId] = append(byZone[orphan.Spec.CertificateZoneId], existing...)
+	}
+
+	zoneIDs := make([]string, 0, len(byZone))
+	for zoneID, records := range byZone {
+		if len(records) > 0 {
+			zoneIDs = append(zoneIDs, zoneID)
+		}
+	}
+	sort.Strings(zoneIDs)
+	for _, zoneID := range zoneIDs {
+		awsCtx, cancel := context.WithTimeout(ctx, 4*AWSCallTimeout)
+		err := r.Route53Client.DeleteRecords(awsCtx, zoneID, byZone[zoneID])
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to delete %d records of %s in zone %s: %w", len(byZone[zoneID]), orphan.Spec.Hostname, zoneID, err)
+		}
+	}
+
+	if certArn == "" {
+		return nil
+	}
+	inUse, err := r.isCertificateInUse(ctx, certArn)
+	if aws.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return fmt.Errorf("certificate %s is still in use", certArn)
+	}
+	awsCtx, cancel := withAWSTimeout(ctx)
+	defer cancel()
+	if _, err := r.deleteCertificate(awsCtx, orphan, certArn); err != nil {
+		return fmt.Errorf("failed to delete certificate %s: %w", certArn, err)
+	}
+	return nil
+}
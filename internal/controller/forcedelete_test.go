@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+func TestForceDeleteAfter(t *testing.T) {
+	r := &GatewayHostnameRequestReconciler{ForceDeleteAfter: time.Hour}
+	for annotation, want := range map[string]time.Duration{
+		"":        time.Hour,
+		"30m":     30 * time.Minute,
+		"0s":      0,
+		"invalid": time.Hour,
+		"-5m":     time.Hour,
+	} {
+		ghr := &gatewayv1alpha1.GatewayHostnameRequest{}
+		if annotation != "" {
+			ghr.Annotations = map[string]string{AnnotationForceDeleteAfter: annotation}
+		}
+		assert.Equal(t, want, r.forceDeleteAfter(context.Background(), ghr), annotation)
+	}
+}
+
+func TestReconcileDelete_ForceDeleteAfter(t *testing.T) {
+	ctx := context.Background()
+	ghr, route53Mock, acmMock := teardownFixture()
+	ghr.UID = "uid-1"
+	ghr.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-30 * time.Minute)}
+	route53Mock.batchDeleteErr = errors.New("throttled")
+	scheme := getTestScheme()
+	recorder := record.NewFakeRecorder(10)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ghr).
+		WithStatusSubresource(ghr, &gatewayv1alpha1.Orphan{}).Build()
+	r := &GatewayHostnameRequestReconciler{
+		Client:           c,
+		Scheme:           scheme,
+		GatewayPool:      gateway.NewPool(c, "edge", "aws-alb", 0, 0),
+		Recorder:         recorder,
+		Route53Client:    route53Mock,
+		ACMClient:        acmMock,
+		ForceDeleteAfter: time.Hour,
+	}
+
+	_, err := r.reconcileDelete(ctx, ghr)
+	require.Error(t, err, "the finalizer is kept before --force-delete-after")
+	assert.Contains(t, <-recorder.Events, "DNSTeardownFailed")
+
+	ghr.DeletionTimestamp = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+	_, err = r.reconcileDelete(ctx, ghr)
+	require.NoError(t, err)
+	assert.Contains(t, <-recorder.Events, "ForceDeleted")
+	err = r.Get(ctx, client.ObjectKeyFromObject(ghr), &gatewayv1alpha1.GatewayHostnameRequest{})
+	assert.True(t, apierrors.IsNotFound(err), "the request is gone once the finalizer is removed")
+	assert.Contains(t, acmMock.certificates, teardownCertArn, "the certificate is left with its validation records")
+
+	var orphan gatewayv1alpha1.Orphan
+	require.NoError(t, r.Get(ctx, types.NamespacedName{Name: "uid-1"}, &orphan))
+	assert.Equal(t, "app.opendi.com", orphan.Spec.Hostname)
+	assert.Equal(t, teardownCertArn, orphan.Spec.CertificateArn)
+	assert.Len(t, orphan.Spec.Records, 4, "A and AAAA in both alias zones")
+	assert.Contains(t, orphan.Spec.Reason, "throttled")
+
+	// The sweep keeps the Orphan while AWS still fails
+	a := &Admin{Reconciler: r}
+	result, err := a.SweepOrphans(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, result.Touched)
+	require.NoError(t, r.Get(ctx, types.NamespacedName{Name: "uid-1"}, &orphan))
+	assert.Equal(t, int32(1), orphan.Status.Attempts)
+	assert.Contains(t, orphan.Status.LastError, "throttled")
+
+	route53Mock.batchDeleteErr = nil
+	result, err = a.SweepOrphans(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Orphan/uid-1"}, result.Touched)
+	assert.Empty(t, route53Mock.records["ZLEGACY"])
+	require.Len(t, route53Mock.records["ZPRIMARY"], 1)
+	assert.Equal(t, "other.opendi.com", route53Mock.records["ZPRIMARY"][0].Name)
+	assert.NotContains(t, acmMock.certificates, teardownCertArn)
+}
+
+func TestCleanupOrphan_HostnameRequestedAgain(t *testing.T) {
+	ctx := context.Background()
+	ghr, route53Mock, acmMock := teardownFixture()
+	ghr.UID = "uid-1"
+	orphan := newOrphan(ghr, true, "throttled")
+	successor := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "successor", Namespace: "other", UID: "uid-2"},
+		Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "App.opendi.com.", ZoneId: "ZPRIMARY"},
+	}
+	r := &GatewayHostnameRequestReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(successor).Build(),
+		Recorder:      record.NewFakeRecorder(10),
+		Route53Client: route53Mock,
+		ACMClient:     acmMock,
+	}
+
+	require.NoError(t, r.cleanupOrphan(ctx, orphan))
+	assert.Empty(t, route53Mock.batches, "the records belong to the new request")
+	assert.Len(t, route53Mock.records["ZPRIMARY"], 4)
+	assert.NotContains(t, acmMock.certificates, teardownCertArn)
+}
@@ -4,15 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
 	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/notify"
 )
 
 // Annotations we use for tracking
@@ -21,8 +29,36 @@ const (
 	AnnotationRuleCount        = "gateway.opendi.com/rule-count"
 	AnnotationVisibility       = "gateway.opendi.com/visibility"
 
-	// LabelGatewayAccess is applied to namespaces that are allowed to create HTTPRoutes for a Gateway
+	// LabelGatewayAccess named the one Gateway a namespace was allowed to create HTTPRoutes
+	// for. Replaced by the per-Gateway LabelGatewayAccessPrefix labels; removed on sight.
 	LabelGatewayAccess = "gateway.opendi.com/access"
+
+	// AnnotationAllowedNamespaces lists (comma-separated, sorted) the namespaces with hostnames
+	// on a Gateway. It replaces LabelGatewayAccess in NamespaceAccessGateway mode.
+	AnnotationAllowedNamespaces = "gateway.opendi.com/allowed-namespaces"
+)
+
+// Namespace access modes: how the namespaces allowed to use a Gateway are recorded
+const (
+	// NamespaceAccessLabel labels each requesting namespace (requires namespace update permission)
+	NamespaceAccessLabel = "label"
+
+	// NamespaceAccessGateway keeps an allowlist annotation on the Gateway instead, so the
+	// controller needs no write access to namespaces
+	NamespaceAccessGateway = "gateway"
+)
+
+// Listener modes: how hostnames get HTTPS listeners on their Gateway
+const (
+	// ListenerModeShared serves every hostname from the Gateway's catch-all "https" listener
+	ListenerModeShared = "shared"
+
+	// ListenerModeHostname adds a dedicated HTTPS listener per hostname to the Gateway
+	ListenerModeHostname = "hostname"
+
+	// ListenerModeListenerSet puts the per-hostname listeners into an XListenerSet attached to
+	// the Gateway, so the Gateway spec stays the same as hostnames come and go
+	ListenerModeListenerSet = "listenerset"
 )
 
 // ensureGatewayAssignment assigns the request to a Gateway and attaches the certificate
@@ -50,7 +86,11 @@ func (r *GatewayHostnameRequestReconciler) ensureGatewayAssignment(ctx context.C
 		visibility = "internet-facing"
 	}
 
-	gwInfo, err := r.GatewayPool.SelectGateway(ctx, visibility, ghr.Spec.WafArn, ghr.Spec.GatewaySelector)
+	selectGateway := r.GatewayPool.SelectGateway
+	if isHTTPOnly(ghr) {
+		selectGateway = r.GatewayPool.SelectHTTPGateway
+	}
+	gwInfo, err := selectGateway(ctx, visibility, r.wafArn(ghr), ghr.Spec.GatewaySelector)
 	if err != nil {
 		return fmt.Errorf("failed to select gateway: %w", err)
 	}
@@ -60,33 +100,44 @@ func (r *GatewayHostnameRequestReconciler) ensureGatewayAssignment(ctx context.C
 		if ghr.Spec.GatewaySelector != nil {
 			return fmt.Errorf("no Gateway matching selector with available capacity")
 		}
+		if err := r.checkGatewayCreationSlot(ctx, ghr); err != nil {
+			return err
+		}
 		logger.Info("No Gateway with capacity found, creating new Gateway")
 		index, err := r.GatewayPool.GetNextGatewayIndex(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to get next gateway index: %w", err)
 		}
 
-		gatewayName := fmt.Sprintf("gw-%02d", index)
-		gatewayNamespace := r.GatewayPool.Namespace()
+		gatewayName, err := r.naming().GatewayName(index)
+		if err != nil {
+			return err
+		}
+		gatewayNamespace := r.GatewayPool.NamespaceFor(visibility)
 
-		// Create LoadBalancerConfiguration FIRST with the initial certificate
-		initialCerts := []string{ghr.Status.CertificateArn}
-		if err := r.ensureLoadBalancerConfiguration(ctx, gatewayName, gatewayNamespace, initialCerts, visibility, ghr.Spec.WafArn); err != nil {
+		// Create LoadBalancerConfiguration FIRST with the initial certificate (none for HTTP-only
+		// requests; the ALB then only gets the HTTP listener until a certificate is added)
+		var initialCerts []string
+		if ghr.Status.CertificateArn != "" {
+			initialCerts = append(initialCerts, ghr.Status.CertificateArn)
+		}
+		if err := r.ensureLoadBalancerConfiguration(ctx, gatewayName, gatewayNamespace, initialCerts, visibility, r.wafArn(ghr)); err != nil {
 			return fmt.Errorf("failed to create LoadBalancerConfiguration: %w", err)
 		}
 
 		// Now create Gateway referencing the LoadBalancerConfiguration
-		gwInfo, err = r.GatewayPool.CreateGateway(ctx, visibility, ghr.Spec.WafArn, index)
+		gwInfo, err = r.GatewayPool.CreateGateway(ctx, visibility, r.wafArn(ghr), index)
 		if err != nil {
 			return fmt.Errorf("failed to create new gateway: %w", err)
 		}
 		logger.Info("Created new Gateway with LoadBalancerConfiguration", "name", gwInfo.Name, "index", index)
+		r.notifyGateway(notify.EventGatewayCreated, gwInfo.Name, gwInfo.Namespace, fmt.Sprintf("Created %s Gateway for %s", visibility, ghr.Spec.Hostname))
 
 		// Update status with assigned Gateway
 		ghr.Status.AssignedGateway = gwInfo.Name
 		ghr.Status.AssignedGatewayNamespace = gwInfo.Namespace
 
-		logger.Info("Successfully assigned to Gateway", "gateway", gwInfo.Name, "hostname", ghr.Spec.Hostname)
+		logger.Info("Successfully assigned to Gateway", "gateway", gwInfo.Name)
 		return nil
 	}
 
@@ -95,14 +146,26 @@ func (r *GatewayHostnameRequestReconciler) ensureGatewayAssignment(ctx context.C
 	ghr.Status.AssignedGatewayNamespace = gwInfo.Namespace
 
 	// Sync LoadBalancerConfiguration to add this certificate to existing Gateway
-	if err := r.syncLoadBalancerConfiguration(ctx, gwInfo.Name, gwInfo.Namespace, visibility, ghr.Spec.WafArn, ghr.Status.CertificateArn); err != nil {
+	if err := r.syncLoadBalancerConfiguration(ctx, gwInfo.Name, gwInfo.Namespace, visibility, r.wafArn(ghr), ghr.Status.CertificateArn); err != nil {
 		return fmt.Errorf("failed to sync LoadBalancerConfiguration: %w", err)
 	}
 
-	logger.Info("Successfully assigned to Gateway", "gateway", gwInfo.Name, "hostname", ghr.Spec.Hostname)
+	logger.Info("Successfully assigned to Gateway", "gateway", gwInfo.Name)
 	return nil
 }
 
+// wafArn returns the WAF of the request's ALB: spec.wafArn, or the OrchestratorConfig's
+// defaultWafArn if it sets none. DNS-only requests have no ALB.
+func (r *GatewayHostnameRequestReconciler) wafArn(ghr *gatewayv1alpha1.GatewayHostnameRequest) string {
+	if ghr.Spec.WafArn != "" || ghr.Spec.AliasTarget != nil {
+		return ghr.Spec.WafArn
+	}
+	if settings := r.settings(); settings != nil {
+		return settings.DefaultWafArn
+	}
+	return ""
+}
+
 // syncLoadBalancerConfiguration collects all certificate ARNs for a Gateway and updates its LoadBalancerConfiguration
 // If newCertARN is provided, it's included even if the GHR isn't assigned yet
 func (r *GatewayHostnameRequestReconciler) syncLoadBalancerConfiguration(ctx context.Context, gatewayName, gatewayNamespace, visibility, wafArn, newCertARN string) error {
@@ -169,6 +232,10 @@ func (r *GatewayHostnameRequestReconciler) removeCertificateFromGateway(ctx cont
 		return fmt.Errorf("failed to sync LoadBalancerConfiguration after certificate removal: %w", err)
 	}
 
+	if err := r.removeFromGatewaySpec(ctx, &gw, ghr); err != nil {
+		return err
+	}
+
 	// NOTE: WAF Orphan Scenario
 	// If this is the last GHR deleted and it had a custom WAF, the Gateway's WAF annotation remains.
 	// The WAF is no longer in use but not cleared from the annotation. This is acceptable because:
@@ -225,6 +292,9 @@ func (r *GatewayHostnameRequestReconciler) ensureAllowedRoutes(ctx context.Conte
 	}
 
 	if updated {
+		if err := foreignOwnership("Gateway", &gw); err != nil {
+			return err
+		}
 		if err := r.Update(ctx, &gw); err != nil {
 			return fmt.Errorf("failed to update gateway allowedRoutes: %w", err)
 		}
@@ -263,7 +333,7 @@ func (r *GatewayHostnameRequestReconciler) ensureRoute53Alias(ctx context.Contex
 
 	if lbDNS == "" {
 		// LoadBalancer not yet provisioned by AWS Load Balancer Controller
-		return fmt.Errorf("gateway %s does not have LoadBalancer address yet", gw.Name)
+		return fmt.Errorf("gateway %s: %w", gw.Name, ErrLoadBalancerNotReady)
 	}
 
 	// Extract region from ALB DNS name and get the canonical hosted zone ID
@@ -279,26 +349,72 @@ func (r *GatewayHostnameRequestReconciler) ensureRoute53Alias(ctx context.Contex
 
 	// Update status with LoadBalancer info
 	ghr.Status.AssignedLoadBalancer = lbDNS
+	ghr.Status.GatewayRegion = region
+	if target := r.overrideTarget(ghr); target != nil {
+		return r.publishOverrideTarget(ctx, ghr, target)
+	}
+	if ghr.Spec.OverrideTarget != nil {
+		r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "TargetOverrideIgnored",
+			"spec.overrideTarget is ignored: it is only honored with the admission webhooks enabled, which restrict it to the override target groups")
+	}
+
+	// Create Route53 ALIAS records: A always, AAAA only if the ALB is dualstack.
+	// An AAAA alias to an IPv4-only ALB resolves to nothing, which confuses IPv6-preferring clients.
+	recordTypes, err := r.aliasRecordTypes(ctx, gw.Name, gw.Namespace)
+	if err != nil {
+		return err
+	}
+	if ghr.Spec.IPv6Only && !slices.Contains(recordTypes, "AAAA") {
+		return fmt.Errorf("ipv6Only needs a dualstack ALB, but the ALB of Gateway %s is IPv4-only", gw.Name)
+	}
+	recordTypes = requestedRecordTypes(ghr, recordTypes)
 
-	// Create Route53 ALIAS records for both A (IPv4) and AAAA (IPv6)
-	// ALBs are dual-stack, so we create both record types pointing to the same ALB
 	aliasTarget := &aws.AliasTarget{
 		DNSName:              lbDNS,
 		HostedZoneID:         hostedZoneID,
 		EvaluateTargetHealth: true,
 	}
+	if err := r.publishAliasRecords(ctx, ghr, aliasTarget, recordTypes); err != nil {
+		return err
+	}
+	if ghr.Status.OverrideTarget != "" {
+		r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "TargetOverrideCleared", "DNS points at the ALB %s again instead of %s", lbDNS, ghr.Status.OverrideTarget)
+		ghr.Status.OverrideTarget = ""
+	}
 
-	// Try both record types independently so partial progress is made even if one fails
-	var errs []error
-	for _, recordType := range []string{"A", "AAAA"} {
-		record := aws.DNSRecord{
-			Name:        ghr.Spec.Hostname,
-			Type:        recordType,
-			AliasTarget: aliasTarget,
-		}
+	logger.Info("Created Route53 ALIAS records",
+		"types", recordTypes,
+		"target", lbDNS,
+		"region", region,
+		"hostedZoneId", hostedZoneID,
+		"zoneIds", ghr.Status.AliasZoneIds)
+
+	return nil
+}
+
+// publishAliasRecords upserts the ALIAS records of the given types in every zone of the request,
+// then removes records from zones dropped from the spec and AAAA records that are no longer wanted.
+// Records the published zones and types in status.
+func (r *GatewayHostnameRequestReconciler) publishAliasRecords(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, aliasTarget *aws.AliasTarget, recordTypes []string) error {
+	logger := log.FromContext(ctx)
 
-		if err := r.Route53Client.CreateOrUpdateRecord(ctx, ghr.Spec.ZoneId, record); err != nil {
-			errs = append(errs, fmt.Errorf("%s: %w", recordType, err))
+	// Try every zone and record type independently so partial progress is made even if one fails
+	zoneIDs := aliasZoneIds(ghr)
+	var errs []error
+	for _, zoneID := range zoneIDs {
+		for _, recordType := range recordTypes {
+			record := ownAliasRecord(ghr, aws.DNSRecord{
+				Name:        ghr.Spec.Hostname,
+				Type:        recordType,
+				AliasTarget: aliasTarget,
+			})
+
+			awsCtx, cancel := withAWSTimeout(ctx)
+			err := r.Route53Client.CreateOrUpdateRecord(awsCtx, zoneID, record)
+			cancel()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s in %s: %w", recordType, zoneID, err))
+			}
 		}
 	}
 
@@ -306,50 +422,209 @@ func (r *GatewayHostnameRequestReconciler) ensureRoute53Alias(ctx context.Contex
 		return fmt.Errorf("failed to create Route53 ALIAS records: %v", errors.Join(errs...))
 	}
 
-	logger.Info("Created Route53 ALIAS records (A + AAAA)",
-		"hostname", ghr.Spec.Hostname,
-		"target", lbDNS,
-		"region", region,
-		"hostedZoneId", hostedZoneID,
-		"zoneId", ghr.Spec.ZoneId)
+	// Remove the alias from zones that were dropped from spec.additionalZoneIds
+	for _, zoneID := range ghr.Status.AliasZoneIds {
+		if slices.Contains(zoneIDs, zoneID) {
+			continue
+		}
+		if failed := r.deleteAliasRecordsInZone(ctx, ghr, zoneID, "A", "AAAA"); len(failed) > 0 {
+			return fmt.Errorf("failed to delete Route53 ALIAS records %v from removed zone %s", failed, zoneID)
+		}
+		logger.Info("Deleted Route53 ALIAS records from removed zone", "zoneId", zoneID)
+	}
+	ghr.Status.AliasZoneIds = zoneIDs
+
+	// Remove aliases of types no longer published: AAAA left from when the target served IPv6,
+	// or A when the hostname became IPv6-only
+	stale := slices.DeleteFunc([]string{"A", "AAAA"}, func(recordType string) bool { return slices.Contains(recordTypes, recordType) })
+	if len(stale) > 0 {
+		for _, zoneID := range zoneIDs {
+			if failed := r.deleteAliasRecordsInZone(ctx, ghr, zoneID, stale...); len(failed) > 0 {
+				return fmt.Errorf("failed to delete %v ALIAS records that are no longer published in zone %s", failed, zoneID)
+			}
+		}
+	}
+	ghr.Status.AliasRecordTypes = recordTypes
+	ghr.Status.AliasWeight = nil
+	if shared := ghr.Spec.SharedOwnership; shared != nil {
+		weight := shared.Weight
+		ghr.Status.AliasWeight = &weight
+	}
 
 	return nil
 }
 
-// ensureNamespaceLabel labels the requesting namespace to allow HTTPRoute creation for the assigned Gateway
+// aliasZoneIds returns the hosted zones the ALIAS records should be published in:
+// spec.zoneId followed by spec.additionalZoneIds, without duplicates.
+func aliasZoneIds(ghr *gatewayv1alpha1.GatewayHostnameRequest) []string {
+	zoneIDs := []string{recordZoneID(ghr)}
+	for _, zoneID := range ghr.Spec.AdditionalZoneIds {
+		if zoneID != "" && !slices.Contains(zoneIDs, zoneID) {
+			zoneIDs = append(zoneIDs, zoneID)
+		}
+	}
+	return zoneIDs
+}
+
+// aliasRecordTypes returns the alias record types to publish for a Gateway's ALB, based on the
+// ipAddressType of its LoadBalancerConfiguration: A for IPv4-only ALBs, A and AAAA for dualstack.
+func (r *GatewayHostnameRequestReconciler) aliasRecordTypes(ctx context.Context, gatewayName, gatewayNamespace string) ([]string, error) {
+	lbcName, err := r.loadBalancerConfigurationName(gatewayName)
+	if err != nil {
+		return nil, err
+	}
+	lbc, err := r.getLoadBalancerConfiguration(ctx, types.NamespacedName{Name: lbcName, Namespace: gatewayNamespace})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get LoadBalancerConfiguration: %w", err)
+	}
+
+	ipAddressType, _, _ := unstructured.NestedString(lbc.Object, "spec", "ipAddressType")
+	if strings.HasPrefix(ipAddressType, "dualstack") {
+		return []string{"A", "AAAA"}, nil
+	}
+	return []string{"A"}, nil
+}
+
+// aliasRecordTypesInSync reports whether the published alias record types match the ALB's
+// current IP address type, so switching an ALB to or from dualstack re-runs ensureRoute53Alias.
+func (r *GatewayHostnameRequestReconciler) aliasRecordTypesInSync(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	if target := r.overrideTarget(ghr); target != nil {
+		return slices.Equal(ghr.Status.AliasRecordTypes, requestedRecordTypes(ghr, externalAliasRecordTypes(target)))
+	}
+	if ghr.Status.AssignedGateway == "" {
+		return true
+	}
+	want, err := r.aliasRecordTypes(ctx, ghr.Status.AssignedGateway, ghr.Status.AssignedGatewayNamespace)
+	if err != nil {
+		// Don't churn alias records on a transient read error
+		return true
+	}
+	return slices.Equal(ghr.Status.AliasRecordTypes, requestedRecordTypes(ghr, want))
+}
+
+// requestedRecordTypes returns the alias record types the request publishes out of those its
+// target serves: only AAAA for IPv6-only hostnames
+func requestedRecordTypes(ghr *gatewayv1alpha1.GatewayHostnameRequest, recordTypes []string) []string {
+	if !ghr.Spec.IPv6Only {
+		return recordTypes
+	}
+	return slices.DeleteFunc(slices.Clone(recordTypes), func(recordType string) bool { return recordType != "AAAA" })
+}
+
+// aliasZonesInSync reports whether the ALIAS records are published in exactly the zones
+// the spec asks for, so adding or removing an additional zone re-runs ensureRoute53Alias.
+func aliasZonesInSync(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	return slices.Equal(ghr.Status.AliasZoneIds, aliasZoneIds(ghr))
+}
+
+// deleteAliasRecords removes the A and AAAA alias records for the hostname from every zone
+// they were published in (status.aliasZoneIds, or the spec zones for requests provisioned
+// before that field existed).
+// Each record is read from Route53 first and deleted exactly as it exists, so deletion
+// doesn't depend on re-deriving the ALB hosted zone ID (which fails for regions missing
+// from the lookup table) and never issues a DeleteRecord for a record that isn't there.
+// Non-alias records at the hostname were not created by us and are left alone.
+// Only call this for requests that own the hostname (status.assignedLoadBalancer set),
+// otherwise a request that lost the claim would delete the owner's records.
+// Returns the records that could not be deleted as "<zoneId>/<type>".
+func (r *GatewayHostnameRequestReconciler) deleteAliasRecords(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) []string {
+	zoneIDs := ghr.Status.AliasZoneIds
+	if len(zoneIDs) == 0 {
+		zoneIDs = aliasZoneIds(ghr)
+	}
+
+	var failed []string
+	for _, zoneID := range zoneIDs {
+		for _, recordType := range r.deleteAliasRecordsInZone(ctx, ghr, zoneID, "A", "AAAA") {
+			failed = append(failed, zoneID+"/"+recordType)
+		}
+	}
+	return failed
+}
+
+// deleteAliasRecordsInZone removes the hostname's alias records of the given types from a single zone.
+// Returns the record types that could not be deleted.
+func (r *GatewayHostnameRequestReconciler) deleteAliasRecordsInZone(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, zoneID string, recordTypes ...string) []string {
+	logger := log.FromContext(ctx)
+
+	var failedTypes []string
+	for _, recordType := range recordTypes {
+		awsCtx, cancel := withAWSTimeout(ctx)
+		existing, err := r.Route53Client.GetRecord(recordSetContext(awsCtx, ghr), zoneID, ghr.Spec.Hostname, recordType)
+		cancel()
+		if err != nil {
+			failedTypes = append(failedTypes, recordType)
+			logger.Error(err, "Failed to look up Route53 alias record",
+				"type", recordType,
+				"zoneId", zoneID)
+			continue
+		}
+		if existing == nil {
+			continue
+		}
+		if existing.AliasTarget == nil {
+			logger.Info("Skipping non-alias record at hostname",
+				"type", recordType,
+				"zoneId", zoneID)
+			continue
+		}
+
+		awsCtx, cancel = withAWSTimeout(ctx)
+		err = r.Route53Client.DeleteRecord(awsCtx, zoneID, *existing)
+		cancel()
+		if err != nil {
+			failedTypes = append(failedTypes, recordType)
+			logger.Error(err, "Failed to delete Route53 alias record",
+				"type", recordType,
+				"zoneId", zoneID,
+				"target", existing.AliasTarget.DNSName)
+		}
+	}
+
+	return failedTypes
+}
+
+// ensureNamespaceLabel labels the requesting namespace to allow HTTPRoute creation for the
+// Gateways of its requests (see syncNamespaceAccessLabels).
+// No-op in NamespaceAccessGateway mode, where the allowlist lives on the Gateway.
 func (r *GatewayHostnameRequestReconciler) ensureNamespaceLabel(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	if r.NamespaceAccess == NamespaceAccessGateway {
+		return nil
+	}
+
 	logger := log.FromContext(ctx)
 
+	if ghr.Status.AssignedGateway == "" {
+		return ErrNoGatewayAssigned
+	}
+
 	// Get the namespace
 	var ns corev1.Namespace
 	if err := r.Get(ctx, types.NamespacedName{Name: ghr.Namespace}, &ns); err != nil {
 		return fmt.Errorf("failed to get namespace %s: %w", ghr.Namespace, err)
 	}
 
-	// Check if label already exists
-	if ns.Labels == nil {
-		ns.Labels = make(map[string]string)
-	}
-
-	gatewayName := ghr.Status.AssignedGateway
-	if gatewayName == "" {
-		return fmt.Errorf("no gateway assigned yet")
+	changed, err := syncNamespaceAccessLabels(ctx, r.Client, &ns, ghr, true)
+	if err != nil {
+		return err
 	}
-
-	// Add or update the label
-	if ns.Labels[LabelGatewayAccess] != gatewayName {
-		ns.Labels[LabelGatewayAccess] = gatewayName
+	if changed {
 		if err := r.Update(ctx, &ns); err != nil {
 			return fmt.Errorf("failed to update namespace label: %w", err)
 		}
-		logger.Info("Added gateway access label to namespace", "namespace", ghr.Namespace, "gateway", gatewayName)
+		logger.Info("Updated gateway access labels of namespace", "namespace", ghr.Namespace, "gateway", ghr.Status.AssignedGateway)
 	}
 
 	return nil
 }
 
-// removeNamespaceLabel removes the gateway access label from the namespace
+// removeNamespaceLabel removes the access labels of Gateways that no other request in the
+// namespace uses. No-op in NamespaceAccessGateway mode.
 func (r *GatewayHostnameRequestReconciler) removeNamespaceLabel(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	if r.NamespaceAccess == NamespaceAccessGateway {
+		return nil
+	}
+
 	logger := log.FromContext(ctx)
 
 	// Get the namespace
@@ -359,17 +634,15 @@ func (r *GatewayHostnameRequestReconciler) removeNamespaceLabel(ctx context.Cont
 		return nil
 	}
 
-	if ns.Labels == nil {
-		return nil
+	changed, err := syncNamespaceAccessLabels(ctx, r.Client, &ns, ghr, false)
+	if err != nil {
+		return err
 	}
-
-	// Remove the label if it exists
-	if _, exists := ns.Labels[LabelGatewayAccess]; exists {
-		delete(ns.Labels, LabelGatewayAccess)
+	if changed {
 		if err := r.Update(ctx, &ns); err != nil {
 			return fmt.Errorf("failed to remove namespace label: %w", err)
 		}
-		logger.Info("Removed gateway access label from namespace", "namespace", ghr.Namespace)
+		logger.Info("Removed gateway access labels from namespace", "namespace", ghr.Namespace)
 	}
 
 	return nil
@@ -379,46 +652,96 @@ func (r *GatewayHostnameRequestReconciler) removeNamespaceLabel(ctx context.Cont
 // If not, it deletes the Gateway and its LoadBalancerConfiguration.
 // excludeGHRNamespace and excludeGHRName identify the currently-deleting GHR to exclude from the count.
 // This implements idempotent cleanup: if the gateway is already deleted, it returns nil.
+//
+// Requests being deleted don't count, so the last two requests deleting at once can't keep the
+// Gateway alive for each other. Before deleting, the Gateway is leased with
+// gateway.AnnotationDeletionLease (an update, so concurrent cleanups conflict) and the count is
+// repeated against the API server, as the cache may not show assignments made meanwhile. The
+// Gateway is then deleted only at the leased resourceVersion.
 func (r *GatewayHostnameRequestReconciler) cleanupEmptyGateway(ctx context.Context, gatewayName, gatewayNamespace, excludeGHRNamespace, excludeGHRName string) error {
 	logger := log.FromContext(ctx)
 
 	// Count how many GatewayHostnameRequests are still assigned to this Gateway
 	// (excluding the one currently being deleted)
-	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
-	if err := r.List(ctx, &ghrList); err != nil {
+	assignmentCount, err := countGatewayAssignments(ctx, r.Client, gatewayName, gatewayNamespace, excludeGHRNamespace, excludeGHRName)
+	if err != nil {
 		logger.Error(err, "Failed to list GatewayHostnameRequests while checking if Gateway is empty")
 		return err
 	}
 
-	assignmentCount := 0
-	for _, ghr := range ghrList.Items {
-		// Skip the GHR that's currently being deleted
-		if ghr.Namespace == excludeGHRNamespace && ghr.Name == excludeGHRName {
-			continue
-		}
-		// Check both gateway name AND namespace to avoid cross-namespace confusion
-		if ghr.Status.AssignedGateway == gatewayName &&
-			ghr.Status.AssignedGatewayNamespace == gatewayNamespace {
-			assignmentCount++
-		}
-	}
-
 	// If there are still assignments, don't delete the Gateway
 	if assignmentCount > 0 {
 		logger.Info("Gateway still has assignments, not cleaning up", "gateway", gatewayName, "assignments", assignmentCount)
 		return nil
 	}
 
+	// Step 1: Lease the Gateway, so no new hostname is assigned to it, and confirm it is empty
+	var gw gwapiv1.Gateway
+	gwKey := types.NamespacedName{Name: gatewayName, Namespace: gatewayNamespace}
+	err = r.Get(ctx, gwKey, &gw)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get Gateway: %w", err)
+	}
+	gatewayExists := err == nil
+	if gatewayExists && isBootstrapGateway(&gw) {
+		logger.Info("Keeping empty Gateway listed in spec.pool.gateways", "gateway", gatewayName)
+		return nil
+	}
+	if gatewayExists && foreignManager(&gw) != "" {
+		logger.Info("Keeping empty Gateway managed by another manager", "gateway", gatewayName, "manager", foreignManager(&gw))
+		return nil
+	}
+	if gatewayExists {
+		if gw.Annotations[gateway.AnnotationDeletionLease] == "" {
+			if gw.Annotations == nil {
+				gw.Annotations = map[string]string{}
+			}
+			gw.Annotations[gateway.AnnotationDeletionLease] = excludeGHRNamespace + "/" + excludeGHRName
+			if err := r.Update(ctx, &gw); err != nil {
+				// A conflict means another cleanup or assignment got there first; count again
+				return fmt.Errorf("failed to lease Gateway for deletion: %w", err)
+			}
+		}
+
+		assignmentCount, err = countGatewayAssignments(ctx, r.apiReader(), gatewayName, gatewayNamespace, excludeGHRNamespace, excludeGHRName)
+		if err != nil {
+			return fmt.Errorf("failed to confirm Gateway is empty: %w", err)
+		}
+		if assignmentCount > 0 {
+			logger.Info("Gateway got new assignments, not cleaning up", "gateway", gatewayName, "assignments", assignmentCount)
+			delete(gw.Annotations, gateway.AnnotationDeletionLease)
+			if err := r.Update(ctx, &gw); err != nil {
+				return fmt.Errorf("failed to release Gateway deletion lease: %w", err)
+			}
+			return nil
+		}
+	}
+
 	logger.Info("Gateway has no remaining assignments, cleaning up", "gateway", gatewayName)
 
-	// Step 1: Delete LoadBalancerConfiguration
-	lbcName := fmt.Sprintf("%s-config", gatewayName)
+	// Step 2: Delete Gateway, unless it changed since it was leased
+	if gatewayExists {
+		err := r.Delete(ctx, &gw, client.Preconditions{UID: &gw.UID, ResourceVersion: &gw.ResourceVersion})
+		if err != nil && !apierrors.IsNotFound(err) {
+			logger.Error(err, "Failed to delete Gateway", "name", gatewayName)
+			return fmt.Errorf("failed to delete Gateway: %w", err)
+		}
+		// If not found, a concurrent cleanup deleted it
+		if err == nil {
+			logger.Info("Deleted Gateway", "name", gatewayName)
+			r.notifyGateway(notify.EventGatewayDeleted, gatewayName, gatewayNamespace, "Deleted Gateway without remaining hostnames")
+		}
+	}
+
+	// Step 3: Delete LoadBalancerConfiguration
+	lbcName, err := r.loadBalancerConfigurationName(gatewayName)
+	if err != nil {
+		return err
+	}
 	lbcKey := types.NamespacedName{Name: lbcName, Namespace: gatewayNamespace}
-	lbc := &unstructured.Unstructured{}
-	lbc.SetGroupVersionKind(LoadBalancerConfigurationGVK)
-	if err := r.Get(ctx, lbcKey, lbc); err == nil {
+	if lbc, err := r.getLoadBalancerConfiguration(ctx, lbcKey); err == nil {
 		// LoadBalancerConfiguration exists, delete it
-		if err := r.Delete(ctx, lbc); err != nil {
+		if err := r.Delete(ctx, lbc); err != nil && !apierrors.IsNotFound(err) {
 			logger.Error(err, "Failed to delete LoadBalancerConfiguration", "name", lbcName)
 			return fmt.Errorf("failed to delete LoadBalancerConfiguration: %w", err)
 		}
@@ -426,38 +749,232 @@ func (r *GatewayHostnameRequestReconciler) cleanupEmptyGateway(ctx context.Conte
 	}
 	// If not found, that's fine - it may have been manually deleted
 
-	// Step 2: Delete Gateway
-	var gw gwapiv1.Gateway
-	gwKey := types.NamespacedName{Name: gatewayName, Namespace: gatewayNamespace}
-	if err := r.Get(ctx, gwKey, &gw); err == nil {
-		// Gateway exists, delete it
-		if err := r.Delete(ctx, &gw); err != nil {
-			logger.Error(err, "Failed to delete Gateway", "name", gatewayName)
-			return fmt.Errorf("failed to delete Gateway: %w", err)
-		}
-		logger.Info("Deleted Gateway", "name", gatewayName)
-	}
-	// If not found, gateway is already deleted - success
-
 	return nil
 }
 
 // isGatewayEmpty checks whether a Gateway has any GHR assignments remaining,
 // excluding the specified GHR (which is being deleted).
 func (r *GatewayHostnameRequestReconciler) isGatewayEmpty(ctx context.Context, gatewayName, gatewayNamespace, excludeGHRNamespace, excludeGHRName string) (bool, error) {
-	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
-	if err := r.List(ctx, &ghrList); err != nil {
+	count, err := countGatewayAssignments(ctx, r.Client, gatewayName, gatewayNamespace, excludeGHRNamespace, excludeGHRName)
+	if err != nil {
 		return false, err
 	}
+	return count == 0, nil
+}
 
+// countGatewayAssignments counts the requests assigned to a Gateway, except the excluded one
+// and requests being deleted
+func countGatewayAssignments(ctx context.Context, reader client.Reader, gatewayName, gatewayNamespace, excludeGHRNamespace, excludeGHRName string) (int, error) {
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := reader.List(ctx, &ghrList); err != nil {
+		return 0, err
+	}
+
+	count := 0
 	for _, ghr := range ghrList.Items {
+		// Skip the GHR that's currently being deleted
 		if ghr.Namespace == excludeGHRNamespace && ghr.Name == excludeGHRName {
 			continue
 		}
+		if !ghr.DeletionTimestamp.IsZero() {
+			continue
+		}
+		// Check both gateway name AND namespace to avoid cross-namespace confusion
 		if ghr.Status.AssignedGateway == gatewayName &&
 			ghr.Status.AssignedGatewayNamespace == gatewayNamespace {
-			return false, nil
+			count++
+		}
+	}
+	return count, nil
+}
+
+// apiReader returns APIReader, or the client if it is not set
+func (r *GatewayHostnameRequestReconciler) apiReader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+// perHostnameListeners reports whether hostnames get their own listener
+func (r *GatewayHostnameRequestReconciler) perHostnameListeners() bool {
+	return r.GatewayPool != nil && (r.ListenerMode == ListenerModeHostname || r.ListenerMode == ListenerModeListenerSet)
+}
+
+// desiredHostnameListeners returns the hostnames on a Gateway that need their own listener.
+// self is handled like in syncHostnameListeners. The listeners' certificates are the ones
+// getGatewayCertificateARNs puts into the LoadBalancerConfiguration.
+func (r *GatewayHostnameRequestReconciler) desiredHostnameListeners(ctx context.Context, gw *gwapiv1.Gateway, self *gatewayv1alpha1.GatewayHostnameRequest, keepSelf bool) (map[string]bool, error) {
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList); err != nil {
+		return nil, fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+
+	// HTTP-only hostnames are served by the shared HTTP listener
+	desired := map[string]bool{}
+	if keepSelf && !isHTTPOnly(self) {
+		desired[self.Spec.Hostname] = true
+	}
+	for _, ghr := range ghrList.Items {
+		if ghr.Namespace == self.Namespace && ghr.Name == self.Name {
+			continue
+		}
+		// Hostnames being deleted must lose their listener so routes stop attaching
+		if !ghr.DeletionTimestamp.IsZero() || isHTTPOnly(&ghr) {
+			continue
+		}
+		if ghr.Status.AssignedGateway == gw.Name && ghr.Status.AssignedGatewayNamespace == gw.Namespace {
+			desired[ghr.Spec.Hostname] = true
+		}
+	}
+	return desired, nil
+}
+
+// syncHostnameListeners reconciles the dedicated per-hostname listeners on a Gateway in memory.
+// The listed status of self is ignored: its hostname is kept if keepSelf is true (it may not be
+// persisted as assigned yet) and dropped otherwise (it is being removed or re-provisioned).
+// In ListenerModeListenerSet the listeners are written to the Gateway's XListenerSet instead,
+// and the Gateway only needs to accept ListenerSets.
+// Returns true if gw.Spec changed and the Gateway needs an update.
+func (r *GatewayHostnameRequestReconciler) syncHostnameListeners(ctx context.Context, gw *gwapiv1.Gateway, self *gatewayv1alpha1.GatewayHostnameRequest, keepSelf bool) (bool, error) {
+	desired, err := r.desiredHostnameListeners(ctx, gw, self, keepSelf)
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	if r.ListenerMode == ListenerModeListenerSet {
+		if err := r.syncListenerSet(ctx, gw, desired); err != nil {
+			return false, err
+		}
+		changed = allowListenerSets(gw)
+		// Listeners left on the Gateway from ListenerModeHostname are dropped
+		desired = nil
+	}
+
+	var listeners []gwapiv1.Listener
+	existing := map[string]bool{}
+	for _, l := range gw.Spec.Listeners {
+		if gateway.IsHostnameListener(l) {
+			hostname := string(*l.Hostname)
+			if !desired[hostname] || existing[hostname] {
+				changed = true
+				continue
+			}
+			existing[hostname] = true
+			// Drops TLS options listeners carried before their certificates moved to the
+			// LoadBalancerConfiguration
+			if want := r.GatewayPool.HostnameListener(hostname); !equality.Semantic.DeepEqual(l.TLS, want.TLS) {
+				l.TLS = want.TLS
+				changed = true
+			}
+		}
+		listeners = append(listeners, l)
+	}
+
+	// Sort new hostnames so listener order is deterministic across reconciles
+	var missing []string
+	for hostname := range desired {
+		if !existing[hostname] {
+			missing = append(missing, hostname)
+		}
+	}
+	sort.Strings(missing)
+	for _, hostname := range missing {
+		listeners = append(listeners, r.GatewayPool.HostnameListener(hostname))
+		changed = true
+	}
+
+	if changed {
+		gw.Spec.Listeners = listeners
+	}
+	return changed, nil
+}
+
+// syncAllowedNamespaces reconciles the AnnotationAllowedNamespaces allowlist on a Gateway in memory.
+// self is handled like in syncHostnameListeners. Returns true if the annotation changed.
+func (r *GatewayHostnameRequestReconciler) syncAllowedNamespaces(ctx context.Context, gw *gwapiv1.Gateway, self *gatewayv1alpha1.GatewayHostnameRequest, keepSelf bool) (bool, error) {
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList); err != nil {
+		return false, fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+
+	desired := map[string]bool{}
+	if keepSelf {
+		desired[self.Namespace] = true
+	}
+	for _, ghr := range ghrList.Items {
+		if ghr.Namespace == self.Namespace && ghr.Name == self.Name {
+			continue
+		}
+		if !ghr.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if ghr.Status.AssignedGateway == gw.Name && ghr.Status.AssignedGatewayNamespace == gw.Namespace {
+			desired[ghr.Namespace] = true
 		}
 	}
+
+	namespaces := make([]string, 0, len(desired))
+	for ns := range desired {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	value := strings.Join(namespaces, ",")
+
+	if gw.Annotations[AnnotationAllowedNamespaces] == value {
+		return false, nil
+	}
+	if gw.Annotations == nil {
+		gw.Annotations = make(map[string]string)
+	}
+	gw.Annotations[AnnotationAllowedNamespaces] = value
 	return true, nil
 }
+
+// removeFromGatewaySpec drops the request's hostname listener, namespace allowlist entry,
+// propagated labels and certificate list entry from a Gateway it no longer uses, updating the
+// Gateway if anything changed.
+func (r *GatewayHostnameRequestReconciler) removeFromGatewaySpec(ctx context.Context, gw *gwapiv1.Gateway, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	logger := log.FromContext(ctx)
+	changed := false
+
+	if r.perHostnameListeners() {
+		if c, err := r.syncHostnameListeners(ctx, gw, ghr, false); err != nil {
+			logger.Error(err, "Failed to compute hostname listeners", "gateway", gw.Name)
+		} else {
+			changed = changed || c
+		}
+	}
+	if r.NamespaceAccess == NamespaceAccessGateway {
+		if c, err := r.syncAllowedNamespaces(ctx, gw, ghr, false); err != nil {
+			logger.Error(err, "Failed to compute allowed namespaces", "gateway", gw.Name)
+		} else {
+			changed = changed || c
+		}
+	}
+	if r.propagatesGatewayLabels(gw) {
+		if c, err := r.syncGatewayLabels(ctx, gw, ghr, false); err != nil {
+			logger.Error(err, "Failed to compute propagated labels", "gateway", gw.Name)
+		} else {
+			changed = changed || c
+		}
+	}
+	if remaining, err := r.gatewayCertificateARNsWithout(ctx, gw.Name, gw.Namespace, ghr); err != nil {
+		logger.Error(err, "Failed to compute served certificates", "gateway", gw.Name)
+	} else if c, err := r.syncCertificateList(ctx, gw, remaining); err != nil {
+		logger.Error(err, "Failed to compute served certificates", "gateway", gw.Name)
+	} else {
+		changed = changed || c
+	}
+
+	if changed {
+		if err := foreignOwnership("Gateway", gw); err != nil {
+			return err
+		}
+		if err := r.Update(ctx, gw); err != nil {
+			return fmt.Errorf("failed to update gateway %s: %w", gw.Name, err)
+		}
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -12,8 +13,10 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapisxv1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
 
 	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
 )
 
 // getTestScheme returns a scheme with necessary types for testing
@@ -21,6 +24,7 @@ func getTestScheme() *runtime.Scheme {
 	scheme := runtime.NewScheme()
 	_ = gatewayv1alpha1.AddToScheme(scheme)
 	_ = gwapiv1.AddToScheme(scheme)
+	_ = gwapisxv1alpha1.AddToScheme(scheme)
 	return scheme
 }
 
@@ -296,3 +300,83 @@ func TestCleanupEmptyGateway_WithLoadBalancerConfig_DeletesBoth(t *testing.T) {
 	err = client.Get(context.Background(), types.NamespacedName{Name: "gw-01-config", Namespace: "edge"}, &deletedLBC)
 	assert.Error(t, err)
 }
+
+func TestCleanupEmptyGateway_LastTwoDeletingAtOnce_DeletesGateway(t *testing.T) {
+	// Setup - both remaining GHRs are being deleted; each must not keep the Gateway for the other
+	scheme := getTestScheme()
+	now := metav1.Now()
+	deleting := func(name string) *gatewayv1alpha1.GatewayHostnameRequest {
+		return &gatewayv1alpha1.GatewayHostnameRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				DeletionTimestamp: &now,
+				Finalizers:        []string{FinalizerName},
+			},
+			Status: gatewayv1alpha1.GatewayHostnameRequestStatus{
+				AssignedGateway:          "gw-01",
+				AssignedGatewayNamespace: "edge",
+			},
+		}
+	}
+	gateway := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gw-01",
+			Namespace: "edge",
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(deleting("ghr-1"), deleting("ghr-2"), gateway).
+		Build()
+
+	reconciler := &GatewayHostnameRequestReconciler{
+		Client: client,
+	}
+
+	// Execute - the first cleanup deletes the Gateway, the second finds it gone
+	assert.NoError(t, reconciler.cleanupEmptyGateway(context.Background(), "gw-01", "edge", "default", "ghr-1"))
+	assert.NoError(t, reconciler.cleanupEmptyGateway(context.Background(), "gw-01", "edge", "default", "ghr-2"))
+
+	// Assert
+	var deletedGateway gwapiv1.Gateway
+	err := client.Get(context.Background(), types.NamespacedName{Name: "gw-01", Namespace: "edge"}, &deletedGateway)
+	assert.True(t, apierrors.IsNotFound(err), "the Gateway is deleted")
+}
+
+func TestCleanupEmptyGateway_AssignmentMissingFromCache_KeepsGateway(t *testing.T) {
+	// Setup - the cache shows no assignments, but the API server has a new one
+	scheme := getTestScheme()
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gw-01",
+			Namespace: "edge",
+		},
+	}
+	newcomer := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "newcomer",
+			Namespace: "default",
+		},
+		Status: gatewayv1alpha1.GatewayHostnameRequestStatus{
+			AssignedGateway:          "gw-01",
+			AssignedGatewayNamespace: "edge",
+		},
+	}
+
+	cached := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gw).Build()
+	reconciler := &GatewayHostnameRequestReconciler{
+		Client:    cached,
+		APIReader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(newcomer).Build(),
+	}
+
+	// Execute
+	err := reconciler.cleanupEmptyGateway(context.Background(), "gw-01", "edge", "default", "leaving")
+
+	// Assert - the Gateway is kept and its deletion lease released
+	assert.NoError(t, err)
+	var kept gwapiv1.Gateway
+	assert.NoError(t, cached.Get(context.Background(), types.NamespacedName{Name: "gw-01", Namespace: "edge"}, &kept))
+	assert.NotContains(t, kept.Annotations, gateway.AnnotationDeletionLease)
+}
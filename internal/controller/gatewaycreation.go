@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// gatewayCreationPollInterval is how often requests waiting for a Gateway creation check again
+const gatewayCreationPollInterval = 30 * time.Second
+
+// checkGatewayCreationSlot returns ErrGatewayCreationThrottled unless the request may create a
+// new Gateway now: the creation cooldown has passed and no older request is waiting for one.
+// Waiting requests are served oldest first.
+func (r *GatewayHostnameRequestReconciler) checkGatewayCreationSlot(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	if r.GatewayPool.CreationCooldown() <= 0 {
+		return nil
+	}
+
+	next, err := r.GatewayPool.NextCreation(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check gateway creation cooldown: %w", err)
+	}
+	if wait := time.Until(next); wait > 0 {
+		return fmt.Errorf("%w: next Gateway can be created in %s", ErrGatewayCreationThrottled, wait.Round(time.Second))
+	}
+
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList); err != nil {
+		return fmt.Errorf("failed to list requests waiting for a Gateway: %w", err)
+	}
+	ahead := 0
+	for i := range ghrList.Items {
+		other := &ghrList.Items[i]
+		if other.UID == ghr.UID || !other.DeletionTimestamp.IsZero() || !waitingForGatewayCreation(other) {
+			continue
+		}
+		if queuedBefore(other, ghr) {
+			ahead++
+		}
+	}
+	if ahead > 0 {
+		return fmt.Errorf("%w: %d older requests are waiting for a new Gateway", ErrGatewayCreationThrottled, ahead)
+	}
+	return nil
+}
+
+// waitingForGatewayCreation reports whether the request is in the Gateway creation queue
+func waitingForGatewayCreation(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	return conditions.HasReason(ghr.Status.Conditions, ConditionTypeListenerAttached, metav1.ConditionFalse, conditions.ReasonGatewayCreationThrottled)
+}
+
+// queuedBefore orders the creation queue by request age, then namespace and name
+func queuedBefore(a, b *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+func creationQueueGHR(name string, age time.Duration, waiting bool) *gatewayv1alpha1.GatewayHostnameRequest {
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			UID:               types.UID("uid-" + name),
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+		},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: name + ".example.com"},
+	}
+	if waiting {
+		ghr.Status.Conditions = []metav1.Condition{{
+			Type:   ConditionTypeListenerAttached,
+			Status: metav1.ConditionFalse,
+			Reason: string(conditions.ReasonGatewayCreationThrottled),
+		}}
+	}
+	return ghr
+}
+
+func TestCheckGatewayCreationSlot(t *testing.T) {
+	ctx := context.Background()
+	older := creationQueueGHR("older", time.Hour, true)
+	newer := creationQueueGHR("newer", time.Minute, false)
+	recent := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw-01", Namespace: "edge", CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Minute))},
+		Spec:       gwapiv1.GatewaySpec{GatewayClassName: "aws-alb"},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(older, newer, recent).Build()
+	pool := gateway.NewPool(c, "edge", "aws-alb", 0, 0)
+	r := &GatewayHostnameRequestReconciler{Client: c, GatewayPool: pool}
+
+	// No cooldown: creations are never throttled
+	require.NoError(t, r.checkGatewayCreationSlot(ctx, newer))
+
+	// Within the cooldown of the last creation
+	pool.SetCreationCooldown(10 * time.Minute)
+	err := r.checkGatewayCreationSlot(ctx, older)
+	require.ErrorIs(t, err, ErrGatewayCreationThrottled)
+	assert.Contains(t, err.Error(), "next Gateway can be created in")
+
+	// Cooldown passed: the oldest waiting request goes first
+	pool.SetCreationCooldown(30 * time.Second)
+	err = r.checkGatewayCreationSlot(ctx, newer)
+	require.ErrorIs(t, err, ErrGatewayCreationThrottled)
+	assert.Contains(t, err.Error(), "1 older requests are waiting")
+	require.NoError(t, r.checkGatewayCreationSlot(ctx, older))
+}
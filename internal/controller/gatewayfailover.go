@@ -0,0 +1,245 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/notify"
+)
+
+// DefaultGatewayFailureThreshold is how many checks in a row must find a Gateway's ALB failed
+// or missing before its hostnames are moved, so a single inconsistent ELBv2 read doesn't
+// trigger a failover
+const DefaultGatewayFailureThreshold = 3
+
+// AnnotationLoadBalancerFailure records on a failed Gateway why its ALB was considered failed
+const AnnotationLoadBalancerFailure = "gateway.opendi.com/load-balancer-failure"
+
+var gatewayFailoversTotal = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "gateway_orchestrator_gateway_failovers_total",
+	Help: "Gateways whose ALB failed or was deleted and whose hostnames were moved to a replacement Gateway.",
+})
+
+func init() {
+	metrics.Registry.MustRegister(gatewayFailoversTotal)
+}
+
+// GatewayFailover periodically checks the ALBs of the pool's Gateways through ELBv2. When an
+// ALB is in the failed state or no longer exists for FailureThreshold checks in a row, the
+// Gateway is cordoned with CordonReasonLoadBalancerFailed and its requests are marked, so the
+// reconciler moves them to a replacement Gateway. It runs as a manager Runnable.
+type GatewayFailover struct {
+	Client        client.Client
+	LoadBalancers aws.LoadBalancerClient
+	GatewayPool   *gateway.Pool
+	Recorder      record.EventRecorder
+	Notifier      *notify.Dispatcher
+	Interval      time.Duration
+
+	// FailureThreshold defaults to DefaultGatewayFailureThreshold
+	FailureThreshold int
+
+	// failures counts the consecutive failed checks per Gateway (namespace/name)
+	failures map[string]int
+}
+
+// Start implements manager.Runnable
+func (f *GatewayFailover) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("gateway-failover")
+	logger.Info("Starting Gateway failover checks", "interval", f.Interval)
+
+	ticker := time.NewTicker(f.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := f.Check(ctx); err != nil {
+			logger.Error(err, "Gateway failover check failed")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check looks up the ALB of every Gateway and fails over Gateways whose ALB stayed failed or
+// missing for the threshold
+func (f *GatewayFailover) Check(ctx context.Context) error {
+	gateways, err := f.GatewayPool.ListGateways(ctx)
+	if err != nil {
+		return err
+	}
+	loadBalancers, err := f.LoadBalancers.DescribeLoadBalancers(ctx)
+	if err != nil {
+		return err
+	}
+	byDNSName := make(map[string]aws.LoadBalancer, len(loadBalancers))
+	for _, lb := range loadBalancers {
+		byDNSName[strings.ToLower(lb.DNSName)] = lb
+	}
+
+	threshold := f.FailureThreshold
+	if threshold <= 0 {
+		threshold = DefaultGatewayFailureThreshold
+	}
+	if f.failures == nil {
+		f.failures = map[string]int{}
+	}
+	seen := map[string]bool{}
+	for i := range gateways {
+		gw := &gateways[i]
+		key := gw.Namespace + "/" + gw.Name
+		seen[key] = true
+		if !gw.DeletionTimestamp.IsZero() || gw.Annotations[gateway.AnnotationCordoned] == gateway.CordonReasonLoadBalancerFailed {
+			delete(f.failures, key)
+			continue
+		}
+
+		failure := loadBalancerFailure(f.GatewayPool.Info(gw).LoadBalancerDNS, byDNSName)
+		if failure == "" {
+			delete(f.failures, key)
+			continue
+		}
+		f.failures[key]++
+		log.FromContext(ctx).Info("Gateway load balancer unhealthy", "gateway", key, "reason", failure,
+			"checks", f.failures[key], "threshold", threshold)
+		if f.failures[key] < threshold {
+			continue
+		}
+		if err := f.failGateway(ctx, gw, failure); err != nil {
+			return err
+		}
+		delete(f.failures, key)
+	}
+	for key := range f.failures {
+		if !seen[key] {
+			delete(f.failures, key)
+		}
+	}
+	return nil
+}
+
+// loadBalancerFailure describes why the ALB with the given DNS name is failed, or returns ""
+// if it is healthy or the Gateway has no ALB yet
+func loadBalancerFailure(dnsName string, byDNSName map[string]aws.LoadBalancer) string {
+	if dnsName == "" {
+		return ""
+	}
+	lb, ok := byDNSName[strings.ToLower(dnsName)]
+	if !ok {
+		return fmt.Sprintf("load balancer %s no longer exists", dnsName)
+	}
+	if lb.State != aws.LoadBalancerStateFailed {
+		return ""
+	}
+	if lb.StateReason != "" {
+		return fmt.Sprintf("load balancer %s failed: %s", dnsName, lb.StateReason)
+	}
+	return fmt.Sprintf("load balancer %s failed", dnsName)
+}
+
+// failGateway cordons the Gateway and marks the requests assigned to it, which triggers their
+// reconcile and the move to a replacement Gateway
+func (f *GatewayFailover) failGateway(ctx context.Context, gw *gwapiv1.Gateway, failure string) error {
+	logger := log.FromContext(ctx)
+	logger.Info("Failing over Gateway", "gateway", gw.Name, "namespace", gw.Namespace, "reason", failure)
+
+	if gw.Annotations == nil {
+		gw.Annotations = map[string]string{}
+	}
+	gw.Annotations[gateway.AnnotationCordoned] = gateway.CordonReasonLoadBalancerFailed
+	gw.Annotations[AnnotationLoadBalancerFailure] = failure
+	if err := f.Client.Update(ctx, gw); err != nil {
+		return fmt.Errorf("failed to cordon gateway %s: %w", gw.Name, err)
+	}
+	gatewayFailoversTotal.Inc()
+	f.Recorder.Eventf(gw, corev1.EventTypeWarning, "LoadBalancerFailed", "%s; moving its hostnames to a replacement Gateway", failure)
+	f.Notifier.Notify(notify.Event{
+		Type:    notify.EventGatewayFailed,
+		Gateway: gw.Namespace + "/" + gw.Name,
+		Message: failure,
+	})
+
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := f.Client.List(ctx, &ghrList); err != nil {
+		return fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+	for i := range ghrList.Items {
+		ghr := &ghrList.Items[i]
+		if !ghr.DeletionTimestamp.IsZero() || ghr.Status.AssignedGateway != gw.Name || ghr.Status.AssignedGatewayNamespace != gw.Namespace {
+			continue
+		}
+		conditions.Set(&ghr.Status.Conditions, ConditionTypeReady, metav1.ConditionFalse, conditions.ReasonLoadBalancerFailed,
+			fmt.Sprintf("Gateway %s: %s; moving to a replacement Gateway", gw.Name, failure), ghr.Generation)
+		if err := f.Client.Status().Update(ctx, ghr); err != nil {
+			logger.Info("Failed to mark request for failover", "request", ghr.Namespace+"/"+ghr.Name, "error", err.Error())
+		}
+	}
+	return nil
+}
+
+// gatewayLoadBalancerFailed reports whether the Gateway was cordoned because its ALB failed
+func (r *GatewayHostnameRequestReconciler) gatewayLoadBalancerFailed(ctx context.Context, name, namespace string) (bool, error) {
+	var gw gwapiv1.Gateway
+	if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &gw); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get gateway %s: %w", name, err)
+	}
+	return gw.Annotations[gateway.AnnotationCordoned] == gateway.CordonReasonLoadBalancerFailed, nil
+}
+
+// reconcileFailover starts moving the request off its Gateway once the Gateway's ALB failed.
+// The move works like a placement move, but the old Gateway can't serve the hostname meanwhile,
+// so there is no drain period. Returns true if a move was started (the caller must persist
+// status and requeue).
+func (r *GatewayHostnameRequestReconciler) reconcileFailover(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (bool, error) {
+	if ghr.Status.AssignedGateway == "" || ghr.Status.MigratingFromGateway != "" || isDNSOnly(ghr) {
+		return false, nil
+	}
+	failed, err := r.gatewayLoadBalancerFailed(ctx, ghr.Status.AssignedGateway, ghr.Status.AssignedGatewayNamespace)
+	if err != nil || !failed {
+		return false, err
+	}
+
+	msg := fmt.Sprintf("The load balancer of Gateway %s failed", ghr.Status.AssignedGateway)
+	log.FromContext(ctx).Info("Moving hostname off Gateway with failed load balancer", "gateway", ghr.Status.AssignedGateway)
+	r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "LoadBalancerFailed", "%s; moving hostname to a replacement Gateway", msg)
+
+	ghr.Status.MigratingFromGateway = ghr.Status.AssignedGateway
+	ghr.Status.MigratingFromGatewayNamespace = ghr.Status.AssignedGatewayNamespace
+	ghr.Status.AssignedGateway = ""
+	meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeListenerAttached)
+	meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeDnsAliasReady)
+	r.setCondition(ghr, ConditionTypeMigrating, metav1.ConditionTrue, conditions.ReasonProvisioning,
+		msg+"; assigning a replacement Gateway")
+	r.setCondition(ghr, ConditionTypeReady, metav1.ConditionFalse, conditions.ReasonLoadBalancerFailed,
+		msg+"; moving to a replacement Gateway")
+	return true, nil
+}
+
+// failoverInProgress reports whether the request is moving off a Gateway whose ALB failed
+func failoverInProgress(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	return ghr.Status.MigratingFromGateway != "" &&
+		conditions.HasReason(ghr.Status.Conditions, ConditionTypeReady, metav1.ConditionFalse, conditions.ReasonLoadBalancerFailed)
+}
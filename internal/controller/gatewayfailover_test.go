@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+// gatewayWithLoadBalancer returns a pool Gateway whose status points at an ALB
+func gatewayWithLoadBalancer(name, dnsName string) *gwapiv1.Gateway {
+	hostnameType := gwapiv1.HostnameAddressType
+	return &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "edge", Annotations: map[string]string{}},
+		Spec:       gwapiv1.GatewaySpec{GatewayClassName: "aws-alb"},
+		Status: gwapiv1.GatewayStatus{
+			Addresses: []gwapiv1.GatewayStatusAddress{{Type: &hostnameType, Value: dnsName}},
+		},
+	}
+}
+
+func TestGatewayFailover_Check(t *testing.T) {
+	ctx := context.Background()
+	failed := gatewayWithLoadBalancer("gw-01", "k8s-edge-gw01-1.eu-west-1.elb.amazonaws.com")
+	healthy := gatewayWithLoadBalancer("gw-02", "k8s-edge-gw02-2.eu-west-1.elb.amazonaws.com")
+	onFailed := ghrOnGateway(1, "gw-01")
+	onHealthy := ghrOnGateway(2, "gw-02")
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).
+		WithObjects(failed, healthy, onFailed, onHealthy).WithStatusSubresource(onFailed, onHealthy).Build()
+
+	f := &GatewayFailover{
+		Client: c,
+		LoadBalancers: &aws.MockLoadBalancerClient{LoadBalancers: []aws.LoadBalancer{
+			{DNSName: "k8s-edge-gw02-2.eu-west-1.elb.amazonaws.com", State: aws.LoadBalancerStateActive},
+		}},
+		GatewayPool:      gateway.NewPool(c, "edge", "aws-alb", 0, 0),
+		Recorder:         record.NewFakeRecorder(10),
+		FailureThreshold: 2,
+	}
+
+	require.NoError(t, f.Check(ctx))
+	var gw gwapiv1.Gateway
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(failed), &gw))
+	assert.False(t, gateway.IsCordoned(&gw), "a single failed check is not enough")
+
+	require.NoError(t, f.Check(ctx))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(failed), &gw))
+	assert.Equal(t, gateway.CordonReasonLoadBalancerFailed, gw.Annotations[gateway.AnnotationCordoned])
+	assert.Contains(t, gw.Annotations[AnnotationLoadBalancerFailure], "no longer exists")
+
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(onFailed), onFailed))
+	assert.True(t, conditions.HasReason(onFailed.Status.Conditions, ConditionTypeReady, metav1.ConditionFalse, conditions.ReasonLoadBalancerFailed))
+
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(healthy), &gw))
+	assert.False(t, gateway.IsCordoned(&gw))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(onHealthy), onHealthy))
+	assert.Empty(t, onHealthy.Status.Conditions)
+}
+
+func TestLoadBalancerFailure(t *testing.T) {
+	byDNSName := map[string]aws.LoadBalancer{
+		"ok.elb.amazonaws.com":     {State: aws.LoadBalancerStateActive},
+		"broken.elb.amazonaws.com": {State: aws.LoadBalancerStateFailed, StateReason: "subnet deleted"},
+	}
+	assert.Empty(t, loadBalancerFailure("", byDNSName), "no ALB provisioned yet")
+	assert.Empty(t, loadBalancerFailure("OK.elb.amazonaws.com", byDNSName))
+	assert.Equal(t, "load balancer broken.elb.amazonaws.com failed: subnet deleted", loadBalancerFailure("broken.elb.amazonaws.com", byDNSName))
+	assert.Equal(t, "load balancer gone.elb.amazonaws.com no longer exists", loadBalancerFailure("gone.elb.amazonaws.com", byDNSName))
+}
+
+func TestReconcileFailover_MovesWithoutDrain(t *testing.T) {
+	ctx := context.Background()
+	failed := gatewayWithLoadBalancer("gw-01", "k8s-edge-gw01-1.eu-west-1.elb.amazonaws.com")
+	failed.Annotations[gateway.AnnotationCordoned] = gateway.CordonReasonLoadBalancerFailed
+	ghr := ghrOnGateway(1, "gw-01")
+	r := newRebalanceReconciler(t, 2, ghr)
+	r.RebalanceDrainPeriod = time.Hour
+	require.NoError(t, r.Create(ctx, failed))
+
+	moving, err := r.reconcileFailover(ctx, ghr)
+	require.NoError(t, err)
+	require.True(t, moving)
+	assert.Equal(t, "gw-01", ghr.Status.MigratingFromGateway)
+	assert.Empty(t, ghr.Status.AssignedGateway)
+	assert.True(t, failoverInProgress(ghr))
+
+	// Assigned to a replacement Gateway and DNS switched just now
+	ghr.Status.AssignedGateway = "gw-02"
+	ghr.Status.Conditions = append(ghr.Status.Conditions, metav1.Condition{
+		Type: ConditionTypeDnsAliasReady, Status: metav1.ConditionTrue, LastTransitionTime: metav1.Now(),
+	})
+	require.NoError(t, r.Update(ctx, ghr))
+
+	remaining, _, err := r.finishRebalance(ctx, ghr)
+	require.NoError(t, err)
+	assert.Zero(t, remaining, "a failed Gateway is not drained")
+	assert.Empty(t, ghr.Status.MigratingFromGateway)
+	assert.Equal(t, "FailedOver", ghr.Status.History[len(ghr.Status.History)-1].Type)
+
+	err = r.Get(ctx, types.NamespacedName{Name: "gw-01", Namespace: "edge"}, &gwapiv1.Gateway{})
+	assert.True(t, apierrors.IsNotFound(err), "the failed Gateway is deleted once empty")
+}
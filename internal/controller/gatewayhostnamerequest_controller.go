@@ -6,41 +6,64 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
 	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/acme"
 	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
 	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/notify"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/policy"
 )
 
 const (
 	FinalizerName = "gateway-orchestrator.opendi.com/finalizer"
 )
 
-// Condition types
+// Condition types; see api/conditions for their meaning and reasons
 const (
-	ConditionTypeClaimed              = "Claimed"
-	ConditionTypeCertificateRequested = "CertificateRequested"
-	ConditionTypeDnsValidated         = "DnsValidated"
-	ConditionTypeCertificateIssued    = "CertificateIssued"
-	ConditionTypeListenerAttached     = "ListenerAttached"
-	ConditionTypeDnsAliasReady        = "DnsAliasReady"
-	ConditionTypeReady                = "Ready"
-	ConditionTypeDeleting             = "Deleting"
+	ConditionTypeClaimed                 = conditions.TypeClaimed
+	ConditionTypeZoneDelegated           = conditions.TypeZoneDelegated
+	ConditionTypeCertificateRequested    = conditions.TypeCertificateRequested
+	ConditionTypeDnsValidated            = conditions.TypeDnsValidated
+	ConditionTypeCertificateIssued       = conditions.TypeCertificateIssued
+	ConditionTypeListenerAttached        = conditions.TypeListenerAttached
+	ConditionTypeDnsAliasReady           = conditions.TypeDnsAliasReady
+	ConditionTypeNamespaceLabeled        = conditions.TypeNamespaceLabeled
+	ConditionTypeAllowedRoutesConfigured = conditions.TypeAllowedRoutesConfigured
+	ConditionTypeReady                   = conditions.TypeReady
+	ConditionTypeDeleting                = conditions.TypeDeleting
+
+	ConditionTypeGatewayOverCapacity     = conditions.TypeGatewayOverCapacity
+	ConditionTypeGatewayChangePending    = conditions.TypeGatewayChangePending
+	ConditionTypeReprovisionPending      = conditions.TypeReprovisionPending
+	ConditionTypeMigrating               = conditions.TypeMigrating
+	ConditionTypeDNSSECDegraded          = conditions.TypeDNSSECDegraded
+	ConditionTypeResourceValidationError = conditions.TypeResourceValidationError
+	ConditionTypeQuarantined             = conditions.TypeQuarantined
+	ConditionTypeForeignOwnership        = conditions.TypeForeignOwnership
+
+	ConditionTypeValidated = conditions.TypeValidated
 )
 
 // GatewayHostnameRequestReconciler reconciles a GatewayHostnameRequest object
@@ -49,9 +72,193 @@ type GatewayHostnameRequestReconciler struct {
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
 
+	// APIReader reads from the API server instead of the cache, where a stale read is unsafe,
+	// like confirming a Gateway is empty before deleting it. If nil, the client is used.
+	APIReader client.Reader
+
 	ACMClient     aws.ACMClient
 	Route53Client aws.Route53Client
 	GatewayPool   *gateway.Pool
+
+	// ACMEIssuer issues certificates for requests with certificateIssuer ACME; they are imported
+	// into ACM. If nil, such requests fail with IssuerNotConfigured.
+	ACMEIssuer acme.Issuer
+
+	// ACMEFallback issues certificates through ACMEIssuer when ACM rejects a request with a quota error
+	ACMEFallback bool
+
+	// ACMERenewBefore is how long before expiry ACME certificates are renewed
+	// (default DefaultACMERenewBefore)
+	ACMERenewBefore time.Duration
+
+	// LBCVersion resolves the LoadBalancerConfiguration version served by the cluster.
+	// If nil, LoadBalancerConfigurationGVK is used.
+	LBCVersion *LBCVersionResolver
+
+	// ListenerMode selects how hostnames get HTTPS listeners: ListenerModeShared (default),
+	// ListenerModeHostname or ListenerModeListenerSet
+	ListenerMode string
+
+	// BackendReferenceGrants creates a ReferenceGrant in each request namespace that lets
+	// HTTPRoutes in the Gateway namespace use the namespace's Services as backends
+	BackendReferenceGrants bool
+
+	// DefaultSecurityHeaders are enforced on the HTTPRoutes of every hostname unless the
+	// request's spec.securityHeaders overrides them. Empty fields add no header.
+	DefaultSecurityHeaders gatewayv1alpha1.SecurityHeaders
+
+	// ClaimStore keeps the DomainClaims; nil keeps them as DomainClaim objects in the cluster
+	ClaimStore ClaimStore
+
+	// ExpandCertificates lets a request join the ACM certificate of a request for a parent
+	// domain in the same namespace, which is re-issued with the hostname as an additional name
+	ExpandCertificates bool
+
+	// NamespaceAccess selects how namespaces allowed to use a Gateway are recorded:
+	// NamespaceAccessLabel (default) or NamespaceAccessGateway
+	NamespaceAccess string
+
+	// IPAddressType is set as spec.ipAddressType on managed LoadBalancerConfigurations
+	// (ipv4, dualstack or dualstack-without-public-ipv4). AAAA aliases are only published
+	// for dualstack ALBs. Empty leaves the AWS Load Balancer Controller default (ipv4).
+	IPAddressType string
+
+	// Region is the controller's AWS region, where ALBs and certificates without a
+	// spec.certificateRegion live
+	Region string
+
+	// CertificatePollBackoff is the delay curve for checking certificates pending issuance
+	// (default DefaultCertificatePollBackoff)
+	CertificatePollBackoff []time.Duration
+
+	// ImpactConfirmationThreshold holds back visibility/WAF changes requested by one request that
+	// would reconfigure an ALB shared with more than this many other hostnames, until the request
+	// is annotated with AnnotationConfirmGatewayChange. 0 applies changes without confirmation.
+	ImpactConfirmationThreshold int
+
+	// Reprovisions limits how many requests per minute may tear down their resources to
+	// re-provision after a spec change; nil is unlimited
+	Reprovisions *ReprovisionLimiter
+
+	// StrictRouteAccess makes labeling the namespace and configuring the Gateway's
+	// allowedRoutes provisioning steps with their own conditions: the request isn't Ready while
+	// they fail, as its HTTPRoutes would be rejected. Otherwise their failures are only logged.
+	StrictRouteAccess bool
+
+	// RepairRouteParentRefs updates the parentRefs of HTTPRoutes serving a hostname when it is
+	// assigned to another Gateway, so routes follow the hostname
+	RepairRouteParentRefs bool
+
+	// RebalanceOverCapacity moves hostnames off Gateways holding more certificates than the
+	// pool limit. When false, affected requests only get a GatewayOverCapacity condition.
+	RebalanceOverCapacity bool
+
+	// RebalanceDrainPeriod is how long the old Gateway keeps a moved hostname's certificate
+	// after DNS switched (default DefaultRebalanceDrainPeriod)
+	RebalanceDrainPeriod time.Duration
+
+	// DNSSEC checks the signing status of the request's zones before and after record changes.
+	// While a signed zone is degraded, record changes are held and requests get a
+	// DNSSECDegraded condition. If nil, DNSSEC is not checked.
+	DNSSEC *DNSSECChecker
+
+	// CostPricing enables monthly cost estimates per request in status and metrics, based on
+	// these prices. If nil, no estimates are made.
+	CostPricing *CostPricing
+
+	// Quarantine holds requests for suspicious hostnames with a Quarantined condition until
+	// they are annotated with AnnotationApproveQuarantine. If nil, no hostname is quarantined.
+	Quarantine *Quarantine
+
+	// Policy is asked whether a request may be provisioned before its hostname is claimed, and
+	// may annotate it. Denied requests are not provisioned and are checked again every
+	// policyRecheckInterval. If nil, all requests are allowed.
+	Policy policy.Checker
+
+	// PolicyFailOpen provisions requests when the policy webhook fails; by default they wait
+	// and the call is retried
+	PolicyFailOpen bool
+
+	// OverrideTargetAdmission is set when the validating webhook restricts spec.overrideTarget
+	// to the override target groups and fails closed. Without it spec.overrideTarget is
+	// ignored, so anyone who can edit a request can't re-point its hostname.
+	OverrideTargetAdmission bool
+
+	// ManageDelegatedZones creates the hosted zone of spec.delegatedZone when it doesn't exist.
+	// Existing delegated zones are always used (and their NS records kept in the parent zone).
+	ManageDelegatedZones bool
+
+	// GatewayLabelKeys are request label keys whose values are propagated to the assigned
+	// Gateway as gateway.opendi.com/<key name>.<value> labels, counting the requests
+	GatewayLabelKeys []string
+
+	// InteropTags are added to the AWS resources the controller creates (certificates and,
+	// through their LoadBalancerConfiguration, ALBs) so other tools can recognize them
+	InteropTags map[string]string
+
+	// Cluster is --cluster-name, set as the cluster tag of requested certificates so the
+	// duplicate certificate check can tell clusters sharing an account apart
+	Cluster string
+
+	// ForeignOwnerTags mark certificates owned by other tools (Terraform, Crossplane), which
+	// the controller never deletes
+	ForeignOwnerTags ForeignOwnerTags
+
+	// InternalDomainSuffixes are domains requests may not use (see ParseInternalDomainSuffixes);
+	// nil means DefaultInternalDomainSuffixes
+	InternalDomainSuffixes []string
+
+	// Settings are the settings of the OrchestratorConfig: allowed domains, certificate tags and
+	// the certificate poll backoff (which replaces CertificatePollBackoff). If nil, no domain
+	// allowlist applies.
+	Settings *SettingsStore
+
+	// Notifier sends lifecycle events (hostname ready, certificate failures, claim conflicts,
+	// Gateway creation and deletion) to external sinks. If nil, no notifications are sent.
+	Notifier *notify.Dispatcher
+
+	// TakeoverResolver, if set, looks hostnames up in public DNS before they are claimed. Hostnames
+	// that already resolve to addresses the controller doesn't manage are held until the request
+	// sets spec.allowTakeover.
+	TakeoverResolver DNSResolver
+
+	// ConditionFailureThreshold is how many times in a row a step may fail with transient AWS
+	// errors before its True condition is set to False; 0 means DefaultConditionFailureThreshold
+	ConditionFailureThreshold int
+
+	// AccessLogs turns on the access logs of the ALBs and tells requests opting into access logs
+	// where theirs are delivered. If nil, ALB access logs are left alone.
+	AccessLogs *AccessLogs
+
+	// ZonalShift opts the ALBs into ARC zonal shift, so the admin API can evacuate an
+	// availability zone (see ZonalShifts)
+	ZonalShift bool
+
+	// ForceDeleteAfter is how long a deletion may keep failing to clean up AWS resources before
+	// the finalizer is removed anyway and the leftovers are recorded in an Orphan for the orphan
+	// sweep. Requests can override it with AnnotationForceDeleteAfter; 0 keeps the finalizer.
+	ForceDeleteAfter time.Duration
+
+	// CleanupOrder is the order in which deletions remove the DNS records and the certificate:
+	// CleanupOrderDNSFirst (default) or CleanupOrderCertificateFirst
+	CleanupOrder string
+
+	// CleanupStepDelay is how long a deletion waits between removing the DNS records and
+	// detaching the certificate, in either order
+	CleanupStepDelay time.Duration
+
+	// FullReconcileInterval is how long Ready requests whose desired state is unchanged skip
+	// reconciles, and with them all AWS calls; 0 reconciles them fully every time
+	FullReconcileInterval time.Duration
+
+	conditionFailures conditionFailures
+
+	// privateZones caches whether hosted zones are private, by zone ID
+	privateZones sync.Map
+
+	// unmanagedZoneVPCs caches the private zone associations that existed without the
+	// controller, which are left alone
+	unmanagedZoneVPCs sync.Map
 }
 
 //+kubebuilder:rbac:groups=gateway.opendi.com,resources=gatewayhostnamerequests,verbs=get;list;watch;create;update;patch;delete
@@ -60,6 +267,9 @@ type GatewayHostnameRequestReconciler struct {
 //+kubebuilder:rbac:groups=gateway.opendi.com,resources=domainclaims,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get;list;watch;create;update;patch
 //+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=referencegrants,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;update
+//+kubebuilder:rbac:groups=gateway.k8s.aws,resources=targetgroupconfigurations,verbs=get;list;watch;create;update;delete
 
 // Reconcile implements the reconciliation loop
 func (r *GatewayHostnameRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -71,11 +281,34 @@ func (r *GatewayHostnameRequestReconciler) Reconcile(ctx context.Context, req ct
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// Every message of the reconcile names the request, its hostname and the attempt since the
+	// last successful reconcile
+	logger = logger.WithValues("ghr", req.NamespacedName.String(), "hostname", ghr.Spec.Hostname,
+		"attempt", ghr.Status.ConsecutiveFailures+1)
+	ctx = withLogStepBase(log.IntoContext(ctx, logger))
+
+	// Report the Route53 and ACM changes made along the way with their AWS IDs
+	ctx, awsCalls := aws.WithCallLog(ctx)
+	defer r.recordAWSChanges(ctx, &ghr, awsCalls)
+	// and name the request in the comment of its Route53 changes
+	ctx = aws.WithChangeComment(ctx, "GatewayHostnameRequest "+req.NamespacedName.String())
+
 	// Handle deletion
 	if !ghr.DeletionTimestamp.IsZero() {
 		return r.reconcileDelete(ctx, &ghr)
 	}
 
+	// Requests that only ask to be validated get no finalizer, as they never hold anything
+	if validateOnly(&ghr) && !controllerutil.ContainsFinalizer(&ghr, FinalizerName) {
+		return r.reconcileValidateOnly(ctx, &ghr)
+	}
+	if conditions.Get(ghr.Status.Conditions, ConditionTypeValidated) != nil {
+		conditions.Remove(&ghr.Status.Conditions, ConditionTypeValidated)
+		if conditions.HasReason(ghr.Status.Conditions, ConditionTypeReady, metav1.ConditionFalse, conditions.ReasonValidateOnly) {
+			conditions.Remove(&ghr.Status.Conditions, ConditionTypeReady)
+		}
+	}
+
 	// Add finalizer if not present
 	if !controllerutil.ContainsFinalizer(&ghr, FinalizerName) {
 		controllerutil.AddFinalizer(&ghr, FinalizerName)
@@ -84,29 +317,70 @@ func (r *GatewayHostnameRequestReconciler) Reconcile(ctx context.Context, req ct
 		}
 	}
 
-	logger.Info("Reconciling GatewayHostnameRequest", "hostname", ghr.Spec.Hostname, "zoneId", ghr.Spec.ZoneId)
+	// Mirror assignment and zone into labels. The status update that changes the assignment
+	// triggers another reconcile, which brings the labels up to date.
+	if syncRequestLabels(&ghr) {
+		if err := r.Update(ctx, &ghr); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Delete requests whose TTL expired; the finalizer deprovisions them
+	if deleted, err := r.reconcileTTL(ctx, &ghr); err != nil || deleted {
+		return ctrl.Result{}, err
+	}
+
+	// Nothing to do if nothing changed since the last full reconcile
+	if remaining, skip := r.skipReconcile(ctx, &ghr); skip {
+		logger.V(1).Info("Desired state unchanged, skipping reconcile", "nextFullReconcile", remaining)
+		reconcileTotal.WithLabelValues(ReconcileSkipped).Inc()
+		recordSuccessfulReconcile(&ghr)
+		return requeueForTTL(&ghr, ctrl.Result{RequeueAfter: remaining}), nil
+	}
+
+	logger.Info("Reconciling GatewayHostnameRequest", "zoneId", ghr.Spec.ZoneId)
+	if reconcileNowPending(&ghr) {
+		logger.Info("Full reconcile requested", "annotation", AnnotationReconcileNow, "value", ghr.Annotations[AnnotationReconcileNow])
+		r.Recorder.Eventf(&ghr, corev1.EventTypeNormal, "ReconcileRequested", "Full reconcile requested (%s=%s)",
+			AnnotationReconcileNow, ghr.Annotations[AnnotationReconcileNow])
+	}
 
 	// Reconciliation state machine
 	result, err := r.reconcileNormal(ctx, &ghr)
+	r.recordReconcileResult(ctx, &ghr, err)
 	if err != nil {
 		logger.Error(err, "reconciliation failed")
 		return result, err
 	}
 
-	return result, nil
+	return requeueForTTL(&ghr, result), nil
 }
 
 // reconcileNormal handles the normal reconciliation flow
 func (r *GatewayHostnameRequestReconciler) reconcileNormal(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (ctrl.Result, error) {
-	logger := log.FromContext(ctx)
+	ctx, logger := logStep(ctx, StepDrift)
 
 	// Detect spec drift - if spec changed, cleanup and re-provision
 	currentHash := computeSpecHash(&ghr.Spec)
+	if ghr.Status.ObservedSpecHash == currentHash {
+		if err := r.clearReprovisionPending(ctx, ghr); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	// A visibility change alone moves the hostname to another Gateway instead (see reconcilePlacement)
+	// and a zone change alone moves its records to the new zone
 	if ghr.Status.ObservedSpecHash != "" && ghr.Status.ObservedSpecHash != currentHash {
+		if from := zoneMoveSource(ghr); from != "" {
+			return r.moveZone(ctx, ghr, from)
+		}
+	}
+	if ghr.Status.ObservedSpecHash != "" && ghr.Status.ObservedSpecHash != currentHash && !onlyVisibilityChanged(ghr) {
+		if allowed, wait := r.Reprovisions.Allow(ctx); !allowed {
+			return r.holdReprovisioning(ctx, ghr, wait)
+		}
 		logger.Info("Spec changed, triggering re-provisioning",
 			"oldHash", ghr.Status.ObservedSpecHash,
-			"newHash", currentHash,
-			"hostname", ghr.Spec.Hostname)
+			"newHash", currentHash)
 		r.Recorder.Event(ghr, corev1.EventTypeNormal, "SpecChanged", "Spec changed, cleaning up for re-provisioning")
 
 		// Clean up old resources
@@ -115,11 +389,19 @@ func (r *GatewayHostnameRequestReconciler) reconcileNormal(ctx context.Context,
 			// Continue anyway - best effort cleanup
 		}
 
-		// Clear status fields to trigger full re-reconciliation
-		ghr.Status.CertificateArn = ""
+		// Clear status fields to trigger full re-reconciliation (history is kept on purpose)
+		recordHistory(ghr, "SpecChanged", "", "Reprovisioning", "Spec changed, cleaning up for re-provisioning")
+		discardCertificate(ghr)
 		ghr.Status.AssignedGateway = ""
 		ghr.Status.AssignedGatewayNamespace = ""
 		ghr.Status.AssignedLoadBalancer = ""
+		ghr.Status.GatewayRegion = ""
+		ghr.Status.AliasZoneIds = nil
+		ghr.Status.AliasRecordTypes = nil
+		ghr.Status.MigratingFromZoneId = ""
+		ghr.Status.MigratingFromGateway = ""
+		ghr.Status.MigratingFromGatewayNamespace = ""
+		ghr.Status.CostEstimate = nil
 		ghr.Status.Conditions = nil
 		ghr.Status.ObservedSpecHash = ""
 		ghr.Status.ObservedGeneration = 0
@@ -136,7 +418,7 @@ func (r *GatewayHostnameRequestReconciler) reconcileNormal(ctx context.Context,
 	if err := r.validateAssignedResources(ctx, ghr); err != nil {
 		logger.Error(err, "Resource validation failed")
 		// Set condition so user knows validation had issues, but continue reconciliation
-		r.setCondition(ghr, "ResourceValidationError", metav1.ConditionTrue, "ValidationFailed",
+		r.setCondition(ghr, ConditionTypeResourceValidationError, metav1.ConditionTrue, conditions.ReasonValidationFailed,
 			fmt.Sprintf("Validation error (will auto-correct): %v", err))
 		if err := r.Status().Update(ctx, ghr); err != nil {
 			logger.Error(err, "Failed to update validation error condition")
@@ -145,61 +427,176 @@ func (r *GatewayHostnameRequestReconciler) reconcileNormal(ctx context.Context,
 	}
 
 	// Step 1: Validate request
+	ctx, logger = logStep(ctx, StepValidate)
 	if err := r.validateRequest(ghr); err != nil {
-		r.setCondition(ghr, ConditionTypeReady, metav1.ConditionFalse, "ValidationFailed", err.Error())
+		r.setCondition(ghr, ConditionTypeReady, metav1.ConditionFalse, conditions.ReasonValidationFailed, err.Error())
 		_ = r.Status().Update(ctx, ghr)
 		r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "ValidationFailed", "Request validation failed: %v", err)
 		return ctrl.Result{}, err
 	}
 
-	// Step 2: Claim domain (first-come-first-serve)
+	// Step 1b: Hold suspicious hostnames for approval, until the hostname is claimed
+	if r.Quarantine != nil && !conditions.IsTrue(ghr.Status.Conditions, ConditionTypeClaimed) {
+		released, err := r.checkQuarantine(ctx, ghr)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !released {
+			// Approving the request changes its annotations, which reconciles it again
+			return ctrl.Result{}, nil
+		}
+	}
+
+	// Step 1c: Ask the hostname policy, until the hostname is claimed
+	if r.Policy != nil && !conditions.IsTrue(ghr.Status.Conditions, ConditionTypeClaimed) {
+		allowed, err := r.checkPolicy(ctx, ghr)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !allowed {
+			return ctrl.Result{RequeueAfter: policyRecheckInterval}, nil
+		}
+	}
+
+	// Step 2: Claim domain (first-come-first-serve), unless a subdomain tree is reserved for
+	// another namespace. Checked until the hostname is claimed, so existing claims are kept.
+	ctx, logger = logStep(ctx, StepClaim)
+	if !conditions.IsTrue(ghr.Status.Conditions, ConditionTypeClaimed) {
+		allowed, err := r.checkSubtreeClaim(ctx, ghr)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !allowed {
+			return ctrl.Result{}, nil // Don't requeue, claim conflict
+		}
+		// Don't take over a hostname that is live elsewhere unless the request says so
+		if r.TakeoverResolver != nil {
+			allowed, err := r.checkTakeover(ctx, ghr)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if !allowed {
+				return ctrl.Result{RequeueAfter: takeoverRecheckInterval}, nil
+			}
+		}
+	}
 	claimed, err := r.ensureDomainClaim(ctx, ghr)
 	if err != nil {
-		r.setCondition(ghr, ConditionTypeClaimed, metav1.ConditionFalse, "ClaimFailed", err.Error())
+		r.setCondition(ghr, ConditionTypeClaimed, metav1.ConditionFalse, conditions.ReasonClaimFailed, err.Error())
 		_ = r.Status().Update(ctx, ghr)
 		r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "ClaimFailed", "Failed to claim domain: %v", err)
 		return ctrl.Result{}, err
 	}
 	if !claimed {
-		r.setCondition(ghr, ConditionTypeClaimed, metav1.ConditionFalse, "AlreadyClaimed", "Hostname already claimed by another request")
+		if !r.recordSharedClaimConflict(ctx, ghr) {
+			r.setCondition(ghr, ConditionTypeClaimed, metav1.ConditionFalse, conditions.ReasonAlreadyClaimed, "Hostname already claimed by another request")
+			r.Recorder.Event(ghr, corev1.EventTypeWarning, "AlreadyClaimed", "Hostname already claimed by another request")
+		}
 		_ = r.Status().Update(ctx, ghr)
-		r.Recorder.Event(ghr, corev1.EventTypeWarning, "AlreadyClaimed", "Hostname already claimed by another request")
 		return ctrl.Result{}, nil // Don't requeue, claim conflict
 	}
-	r.setCondition(ghr, ConditionTypeClaimed, metav1.ConditionTrue, "Claimed", "Domain successfully claimed")
+	r.setCondition(ghr, ConditionTypeClaimed, metav1.ConditionTrue, conditions.ReasonClaimed, "Domain successfully claimed")
 	r.Recorder.Event(ghr, corev1.EventTypeNormal, "Claimed", "Domain successfully claimed")
 
-	// Step 3: Request ACM certificate
-	if ghr.Status.CertificateArn == "" {
-		certArn, err := r.requestCertificate(ctx, ghr)
+	// Step 2b: Find or create the delegated zone the hostname's records live in (a forced
+	// reconcile checks the NS records in the parent zone again)
+	if ghr.Spec.DelegatedZone != "" && (!meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeZoneDelegated) ||
+		ghr.Status.DelegatedZoneId == "" || reconcileNowPending(ghr)) {
+		if err := r.ensureDelegatedZone(ctx, ghr); err != nil {
+			_ = r.Status().Update(ctx, ghr)
+			if errors.Is(err, errDelegatedZoneNotFound) {
+				return ctrl.Result{RequeueAfter: delegatedZoneRecheckInterval}, nil
+			}
+			return ctrl.Result{}, err
+		}
+		if err := r.Status().Update(ctx, ghr); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Steps 3-5 for certificates issued through ACME and imported into ACM; once imported,
+	// the ACM steps below find the certificate issued
+	ctx, logger = logStep(ctx, StepCertificate)
+	if certificateIssuer(ghr) == CertificateIssuerACME && !isHTTPOnly(ghr) {
+		if result, done, err := r.reconcileACMECertificate(ctx, ghr); !done {
+			return result, err
+		}
+	}
+
+	// Steps 3-5 for hostnames that join the certificate of a request for a parent domain
+	if r.ExpandCertificates && joinsCertificate(ghr) {
+		if result, done, err := r.reconcileSharedCertificate(ctx, ghr); !done {
+			return result, err
+		}
+	}
+
+	// Step 3: Request ACM certificate (HTTP-only requests skip Steps 3-5)
+	if ghr.Status.CertificateArn == "" && !isHTTPOnly(ghr) {
+		// ACM keeps certificates that CAA records forbid pending until validation times out
+		if err := r.checkCAA(ctx, ghr); errors.Is(err, ErrCAAForbidsAmazon) {
+			r.setCondition(ghr, ConditionTypeCertificateRequested, metav1.ConditionFalse, conditions.ReasonCAAForbidsAmazon,
+				err.Error()+"; add a CAA record for amazon.com or use certificateIssuer ACME")
+			_ = r.Status().Update(ctx, ghr)
+			r.Recorder.Event(ghr, corev1.EventTypeWarning, string(conditions.ReasonCAAForbidsAmazon), err.Error())
+			return ctrl.Result{RequeueAfter: caaRecheckInterval}, nil
+		} else if err != nil {
+			logger.Info("CAA check failed, requesting the certificate anyway", "error", err.Error())
+		}
+
+		token := certificateIdempotencyToken(ghr)
+		certArn := r.adoptJournaled(ctx, ghr, JournalCertificateRequested, token)
+		var err error
+		if certArn == "" {
+			certArn, err = r.requestCertificate(ctx, ghr)
+		}
+		if errors.Is(err, aws.ErrCertificateQuotaExceeded) && r.ACMEFallback && r.ACMEIssuer != nil {
+			logger.Info("ACM certificate quota exceeded, falling back to ACME")
+			r.Recorder.Event(ghr, corev1.EventTypeWarning, "ACMEFallback", "ACM certificate quota exceeded, issuing the certificate through ACME instead")
+			ghr.Status.CertificateIssuer = CertificateIssuerACME
+			if err := r.Status().Update(ctx, ghr); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{Requeue: true}, nil
+		}
 		if err != nil {
-			r.setCondition(ghr, ConditionTypeCertificateRequested, metav1.ConditionFalse, "RequestFailed", err.Error())
+			r.setCondition(ghr, ConditionTypeCertificateRequested, metav1.ConditionFalse, conditions.ReasonRequestFailed, err.Error())
 			_ = r.Status().Update(ctx, ghr)
 			r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "CertificateRequestFailed", "Failed to request certificate: %v", err)
 			return ctrl.Result{}, err
 		}
 		ghr.Status.CertificateArn = certArn
-		r.setCondition(ghr, ConditionTypeCertificateRequested, metav1.ConditionTrue, "Requested", "Certificate requested from ACM")
+		ghr.Status.CertificateIssuer = CertificateIssuerACM
+		r.setCondition(ghr, ConditionTypeCertificateRequested, metav1.ConditionTrue, conditions.ReasonRequested, "Certificate requested from ACM")
 		r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "CertificateRequested", "ACM certificate request submitted (%s)", certArn)
-		if err := r.Status().Update(ctx, ghr); err != nil {
+		if err := r.updateStatusJournaled(ctx, ghr, JournalCertificateRequested, token, certArn); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
 
-	// Step 4: Ensure DNS validation records
-	if !meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeDnsValidated) {
+	ghr.Status.CertificateRegion = aws.CertificateRegion(ghr.Status.CertificateArn)
+
+	// Step 4: Ensure DNS validation records (ACM also needs them for renewal, so a forced
+	// reconcile writes them again)
+	ctx, logger = logStep(ctx, StepDNSValidation)
+	if !isHTTPOnly(ghr) && (!meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeDnsValidated) ||
+		(reconcileNowPending(ghr) && certificateIssuer(ghr) == CertificateIssuerACM)) {
+		if err := r.checkDNSSEC(ctx, ghr, false); errors.Is(err, ErrDNSSECDegraded) {
+			return r.holdRecordChanges(ctx, ghr, ConditionTypeDnsValidated, err)
+		}
 		if err := r.ensureValidationRecords(ctx, ghr); err != nil {
 			if errors.Is(err, ErrValidationRecordsNotReady) {
-				r.setCondition(ghr, ConditionTypeDnsValidated, metav1.ConditionFalse, "PendingValidationRecords", "Waiting for ACM to provide DNS validation records")
+				r.setCondition(ghr, ConditionTypeDnsValidated, metav1.ConditionFalse, conditions.ReasonPendingValidationRecords, "Waiting for ACM to provide DNS validation records")
 				_ = r.Status().Update(ctx, ghr)
 				return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 			}
-			r.setCondition(ghr, ConditionTypeDnsValidated, metav1.ConditionFalse, "ValidationRecordFailed", err.Error())
-			_ = r.Status().Update(ctx, ghr)
-			r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "DnsValidationFailed", "Failed to create DNS validation records: %v", err)
+			if r.failCondition(ghr, ConditionTypeDnsValidated, conditions.ReasonValidationRecordFailed, err) {
+				_ = r.Status().Update(ctx, ghr)
+				r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "DnsValidationFailed", "Failed to create DNS validation records: %v", err)
+			}
 			return ctrl.Result{}, err
 		}
-		r.setCondition(ghr, ConditionTypeDnsValidated, metav1.ConditionTrue, "RecordsCreated", "DNS validation records created")
+		_ = r.checkDNSSEC(ctx, ghr, true)
+		r.setCondition(ghr, ConditionTypeDnsValidated, metav1.ConditionTrue, conditions.ReasonRecordsCreated, "DNS validation records created")
 		r.Recorder.Event(ghr, corev1.EventTypeNormal, "DnsValidationRecordsCreated", "DNS validation records created in Route53")
 		if err := r.Status().Update(ctx, ghr); err != nil {
 			return ctrl.Result{}, err
@@ -207,58 +604,152 @@ func (r *GatewayHostnameRequestReconciler) reconcileNormal(ctx context.Context,
 	}
 
 	// Step 5: Wait for certificate issuance
-	if !meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeCertificateIssued) {
+	ctx, logger = logStep(ctx, StepIssuance)
+	if !meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeCertificateIssued) && !isHTTPOnly(ghr) {
 		issued, err := r.checkCertificateStatus(ctx, ghr)
 		if err != nil {
-			r.setCondition(ghr, ConditionTypeCertificateIssued, metav1.ConditionFalse, "CheckFailed", err.Error())
+			r.setCondition(ghr, ConditionTypeCertificateIssued, metav1.ConditionFalse, conditions.ReasonCheckFailed, err.Error())
 			_ = r.Status().Update(ctx, ghr)
 			r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "CertificateCheckFailed", "Failed to check certificate status: %v", err)
 			return ctrl.Result{}, err
 		}
 		if !issued {
-			logger.Info("Certificate not yet issued, requeuing", "hostname", ghr.Spec.Hostname)
-			r.setCondition(ghr, ConditionTypeCertificateIssued, metav1.ConditionFalse, "PendingIssuance", "Waiting for ACM to issue certificate")
+			r.setCondition(ghr, ConditionTypeCertificateIssued, metav1.ConditionFalse, conditions.ReasonPendingIssuance, "Waiting for ACM to issue certificate")
 			_ = r.Status().Update(ctx, ghr)
-			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+
+			// Back off based on how long the certificate has been pending (condition transition time)
+			pending := meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeCertificateIssued)
+			requeueAfter := r.certificatePollInterval(time.Since(pending.LastTransitionTime.Time))
+			logger.Info("Certificate not yet issued, requeuing", "after", requeueAfter)
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
 		}
-		r.setCondition(ghr, ConditionTypeCertificateIssued, metav1.ConditionTrue, "Issued", "Certificate issued by ACM")
+		r.setCondition(ghr, ConditionTypeCertificateIssued, metav1.ConditionTrue, conditions.ReasonIssued, "Certificate issued by ACM")
 		r.Recorder.Event(ghr, corev1.EventTypeNormal, "CertificateIssued", "ACM certificate issued")
 		if err := r.Status().Update(ctx, ghr); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
 
+	// DNS-only requests point at an external target and skip the Gateway pool entirely
+	if isDNSOnly(ghr) {
+		ctx, _ = logStep(ctx, StepDNS)
+		return r.reconcileDNSOnly(ctx, ghr)
+	}
+
+	ctx, logger = logStep(ctx, StepGateway)
+
+	// Move to a replacement Gateway if the assigned one's load balancer failed
+	moving, err := r.reconcileFailover(ctx, ghr)
+	if err != nil {
+		logger.Info("Failed to check Gateway load balancer", "error", err.Error())
+		// Don't fail reconciliation, the request stays on its Gateway
+	}
+	if moving {
+		if err := r.Status().Update(ctx, ghr); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// Move to another Gateway if the assigned one no longer matches gatewaySelector or visibility
+	moving, err = r.reconcilePlacement(ctx, ghr)
+	if err != nil {
+		logger.Info("Failed to check Gateway placement", "error", err.Error())
+		// Don't fail reconciliation, the request stays on its Gateway
+	}
+	if moving {
+		if err := r.Status().Update(ctx, ghr); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
 	// Step 6: Assign to Gateway and attach certificate
 	if !meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeListenerAttached) {
 		if err := r.ensureGatewayAssignment(ctx, ghr); err != nil {
-			r.setCondition(ghr, ConditionTypeListenerAttached, metav1.ConditionFalse, "AttachmentFailed", err.Error())
+			// A new Gateway is needed but creations are rate limited; wait in the queue
+			if errors.Is(err, ErrGatewayCreationThrottled) {
+				logger.Info("Waiting for Gateway creation slot", "reason", err.Error())
+				if !waitingForGatewayCreation(ghr) {
+					r.Recorder.Event(ghr, corev1.EventTypeNormal, "WaitingForGatewayCreation", err.Error())
+				}
+				r.setCondition(ghr, ConditionTypeListenerAttached, metav1.ConditionFalse, conditions.ReasonGatewayCreationThrottled, err.Error())
+				if err := r.Status().Update(ctx, ghr); err != nil {
+					return ctrl.Result{}, err
+				}
+				return ctrl.Result{RequeueAfter: gatewayCreationPollInterval}, nil
+			}
+			if placementMoveInProgress(ghr) && !failoverInProgress(ghr) {
+				return r.rollbackGatewayMove(ctx, ghr, err)
+			}
+			r.setCondition(ghr, ConditionTypeListenerAttached, metav1.ConditionFalse, conditions.ReasonAttachmentFailed, err.Error())
 			_ = r.Status().Update(ctx, ghr)
 			r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "GatewayAssignmentFailed", "Failed to assign gateway: %v", err)
 			return ctrl.Result{}, err
 		}
-		r.setCondition(ghr, ConditionTypeListenerAttached, metav1.ConditionTrue, "Attached", "Certificate attached to Gateway")
+		// The certificate only serves once the AWS Load Balancer Controller applied it
+		acceptance, err := r.listenerAccepted(ctx, ghr)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !acceptance.Accepted {
+			reason, eventType := conditions.ReasonPendingAcceptance, corev1.EventTypeNormal
+			if acceptance.Rejected {
+				reason, eventType = conditions.ReasonAttachmentRejected, corev1.EventTypeWarning
+			}
+			logger.Info("Gateway not programmed by the AWS Load Balancer Controller yet", "gateway", ghr.Status.AssignedGateway, "reason", acceptance.Message)
+			if !conditions.HasReason(ghr.Status.Conditions, ConditionTypeListenerAttached, metav1.ConditionFalse, reason) {
+				r.Recorder.Event(ghr, eventType, string(reason), acceptance.Message)
+			}
+			r.setCondition(ghr, ConditionTypeListenerAttached, metav1.ConditionFalse, reason, acceptance.Message)
+			if err := r.Status().Update(ctx, ghr); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: listenerAcceptancePollInterval}, nil
+		}
+		r.setCondition(ghr, ConditionTypeListenerAttached, metav1.ConditionTrue, conditions.ReasonAttached, "Certificate attached to Gateway")
 		r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "GatewayAssigned", "Assigned to gateway %s", ghr.Status.AssignedGateway)
+		r.updateMigrationPhase(ghr)
 		if err := r.Status().Update(ctx, ghr); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
 
+	// Point HTTPRoutes at the assigned Gateway before DNS switches to it
+	if r.RepairRouteParentRefs {
+		if err := r.ensureRouteParentRefs(ctx, ghr); err != nil {
+			logger.Info("Failed to repair HTTPRoute parentRefs", "error", err.Error())
+			// Don't fail reconciliation for this, just log it
+		}
+	}
+
 	// Step 7: Create Route53 ALIAS record
-	if !meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeDnsAliasReady) {
+	ctx, logger = logStep(ctx, StepDNS)
+	if !meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeDnsAliasReady) || !aliasZonesInSync(ghr) ||
+		!r.aliasRecordTypesInSync(ctx, ghr) || !r.overrideTargetInSync(ghr) || !aliasWeightInSync(ghr) || reconcileNowPending(ghr) {
+		if err := r.checkDNSSEC(ctx, ghr, false); errors.Is(err, ErrDNSSECDegraded) {
+			return r.holdRecordChanges(ctx, ghr, ConditionTypeDnsAliasReady, err)
+		}
 		if err := r.ensureRoute53Alias(ctx, ghr); err != nil {
 			// If LoadBalancer not ready yet, requeue
-			if err.Error() == "gateway "+ghr.Status.AssignedGateway+" does not have LoadBalancer address yet" {
+			if errors.Is(err, ErrLoadBalancerNotReady) {
 				logger.Info("Waiting for LoadBalancer to be provisioned", "gateway", ghr.Status.AssignedGateway)
 				r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "WaitingForLoadBalancer", "Waiting for ALB provisioning (gateway: %s)", ghr.Status.AssignedGateway)
 				return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
 			}
-			r.setCondition(ghr, ConditionTypeDnsAliasReady, metav1.ConditionFalse, "AliasFailed", err.Error())
-			_ = r.Status().Update(ctx, ghr)
-			r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "DnsAliasFailed", "Failed to create Route53 ALIAS record: %v", err)
+			if placementMoveInProgress(ghr) && !failoverInProgress(ghr) {
+				return r.rollbackGatewayMove(ctx, ghr, err)
+			}
+			if r.failCondition(ghr, ConditionTypeDnsAliasReady, conditions.ReasonAliasFailed, err) {
+				_ = r.Status().Update(ctx, ghr)
+				r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "DnsAliasFailed", "Failed to create Route53 ALIAS record: %v", err)
+			}
 			return ctrl.Result{}, err
 		}
-		r.setCondition(ghr, ConditionTypeDnsAliasReady, metav1.ConditionTrue, "Created", "Route53 ALIAS record created")
-		r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "DnsAliasReady", "Route53 ALIAS record created pointing to %s", ghr.Status.AssignedLoadBalancer)
+		_ = r.checkDNSSEC(ctx, ghr, true)
+		r.setCondition(ghr, ConditionTypeDnsAliasReady, metav1.ConditionTrue, conditions.ReasonCreated, "Route53 ALIAS record created")
+		r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "DnsAliasReady", "Route53 ALIAS record created pointing to %s", publishedAliasTarget(ghr))
+		r.updateMigrationPhase(ghr)
 		if err := r.Status().Update(ctx, ghr); err != nil {
 			return ctrl.Result{}, err
 		}
@@ -266,140 +757,181 @@ func (r *GatewayHostnameRequestReconciler) reconcileNormal(ctx context.Context,
 
 	// Step 8: Label namespace for gateway access and configure allowedRoutes
 	// These run every reconciliation to ensure configuration stays correct (idempotent)
-	if err := r.ensureNamespaceLabel(ctx, ghr); err != nil {
-		logger.Info("Failed to label namespace for gateway access", "error", err.Error())
+	ctx, logger = logStep(ctx, StepRoutes)
+	if err := r.reconcileRouteAccess(ctx, ghr); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.ensureReferenceGrant(ctx, ghr); err != nil {
+		logger.Info("Failed to ensure ReferenceGrant for HTTPRoute backends", "error", err.Error())
+		// Don't fail reconciliation for this, just log it
+	}
+	if err := r.ensureTargetGroupConfigurations(ctx, ghr); err != nil {
+		logger.Info("Failed to ensure TargetGroupConfigurations for HTTPRoute backends", "error", err.Error())
 		// Don't fail reconciliation for this, just log it
 	}
-	if err := r.ensureAllowedRoutes(ctx, ghr); err != nil {
-		logger.Info("Failed to configure allowedRoutes, continuing anyway", "error", err.Error())
+	if err := r.ensureSecurityHeaders(ctx, ghr); err != nil {
+		logger.Info("Failed to ensure security headers on HTTPRoutes", "error", err.Error())
 		// Don't fail reconciliation for this, just log it
 	}
+	if err := r.reconcilePrivateZoneVPCs(ctx, ghr); err != nil {
+		logger.Info("Failed to associate private hosted zones with VPCs", "error", err.Error())
+		// Don't fail reconciliation for this, just log it
+	}
+	if routes, err := r.hostnameRoutes(ctx, ghr); err != nil {
+		logger.Info("Failed to list the HTTPRoutes of the hostname", "error", err.Error())
+		// Don't fail reconciliation, the last known routes stay in the status
+	} else {
+		ghr.Status.Routes = routes
+	}
+
+	// Keep DNSSECDegraded current; records already in place are left alone
+	_ = r.checkDNSSEC(ctx, ghr, reconcileNowPending(ghr))
+
+	// Re-issue the certificate for the hostnames that joined it; a swapped-in certificate goes
+	// onto the listener with the Gateway sync below
+	expansionRequeue, err := r.reconcileCertificateExpansion(ctx, ghr)
+	if err != nil {
+		logger.Info("Failed to expand certificate", "error", err.Error())
+		// Don't fail reconciliation, the current certificate keeps serving
+	}
 
 	// Continuously sync Gateway configuration (idempotent drift correction)
 	if ghr.Status.AssignedGateway != "" {
-		if err := r.ensureGatewayConfiguration(ctx, ghr); err != nil {
+		err := r.ensureGatewayConfiguration(ctx, ghr)
+		if err != nil {
 			logger.Info("Failed to sync Gateway configuration", "error", err.Error())
 			// Don't fail reconciliation, will retry on next reconcile
 		}
+		r.reportForeignOwnership(ghr, err)
 	}
 
-	// Step 9: Mark as Ready and update observed generation/hash
+	// Step 9: Move off the Gateway if it is over capacity, or finish an in-flight move
+	ctx, logger = logStep(ctx, StepCapacity)
+	requeueAfter, moving, err := r.reconcileCapacity(ctx, ghr)
+	if err != nil {
+		logger.Info("Failed to reconcile Gateway capacity", "error", err.Error())
+		// Don't fail reconciliation, will retry on next reconcile
+	}
+	if moving {
+		if err := r.Status().Update(ctx, ghr); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// ACME certificates are renewed by the controller
+	if d := r.acmeRequeueAfter(ghr); d > 0 && (requeueAfter == 0 || d < requeueAfter) {
+		requeueAfter = d
+	}
+	if d := expansionRequeue; d > 0 && (requeueAfter == 0 || d < requeueAfter) {
+		requeueAfter = d
+	}
+
+	if err := r.updateCostEstimate(ctx, ghr); err != nil {
+		logger.Info("Failed to update cost estimate", "error", err.Error())
+		// Don't fail reconciliation, will retry on next reconcile
+	}
+	ghr.Status.AccessLogLocation = r.accessLogLocation(ghr)
+
+	// Step 10: Mark as Ready and update observed generation/hash
+	ctx, logger = logStep(ctx, StepReady)
 	ghr.Status.ObservedGeneration = ghr.Generation
 	ghr.Status.ObservedSpecHash = computeSpecHash(&ghr.Spec)
-	r.setCondition(ghr, ConditionTypeReady, metav1.ConditionTrue, "Ready", "Hostname request fully provisioned")
+	markReconcileNowHandled(ghr)
+	r.setCondition(ghr, ConditionTypeReady, metav1.ConditionTrue, conditions.ReasonReady, "Hostname request fully provisioned")
+	requeueAfter = r.recordFullReconcile(ctx, ghr, requeueAfter)
 	r.Recorder.Event(ghr, corev1.EventTypeNormal, "Ready", "Hostname fully provisioned")
 	if err := r.Status().Update(ctx, ghr); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	logger.Info("Successfully reconciled GatewayHostnameRequest", "hostname", ghr.Spec.Hostname)
-	return ctrl.Result{}, nil
+	logger.Info("Successfully reconciled GatewayHostnameRequest")
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // reconcileDelete handles cleanup when GatewayHostnameRequest is deleted.
 //
 // Deletion follows a two-phase approach to avoid tight reconcile loops:
 //
-// Phase 1 (first reconcile): Run all cleanup steps, then check cert-in-use.
-// Phase 2 (subsequent reconciles): If already waiting for cert detachment,
-// skip all cleanup (already done) and only poll cert status.
+// Phase 1 (first reconcile): Run the cleanup steps in the request's cleanup order, up to
+// the first one that has to wait (cert detachment or the step delay).
+// Phase 2 (subsequent reconciles): If already waiting, skip the cleanup that was done and
+// continue from the step the Deleting condition records.
 //
 // This prevents repeated AWS API calls and K8s object modifications on every
 // reconcile while waiting for the ALB to release the certificate.
 func (r *GatewayHostnameRequestReconciler) reconcileDelete(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (ctrl.Result, error) {
-	logger := log.FromContext(ctx)
+	ctx, logger := logStep(ctx, StepDelete)
 
 	if !controllerutil.ContainsFinalizer(ghr, FinalizerName) {
 		return ctrl.Result{}, nil
 	}
-
-	// Phase 2: If we're already waiting for cert detachment, skip cleanup and just poll.
-	// Cleanup was already performed in the first reconcile — re-running it would make
-	// unnecessary AWS calls and K8s object updates on every poll cycle.
-	existingCond := meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeDeleting)
-	if existingCond != nil && existingCond.Reason == "WaitingForCertDetachment" {
-		return r.pollCertificateDetachment(ctx, ghr)
+	// The order is fixed when the deletion starts, so a configuration change can't mix orders
+	if ghr.Status.CleanupOrder == "" {
+		ghr.Status.CleanupOrder = r.cleanupOrder()
+		if err := r.Status().Update(ctx, ghr); err != nil {
+			return ctrl.Result{}, err
+		}
 	}
 
-	// Phase 1: First reconcile — perform all cleanup steps
-	logger.Info("Deleting GatewayHostnameRequest", "hostname", ghr.Spec.Hostname)
-
-	// Step 1: Remove Route53 alias records (A + AAAA, independent of cert, can happen anytime)
-	if ghr.Status.AssignedLoadBalancer != "" {
-		aliasTarget := &aws.AliasTarget{
-			DNSName:              ghr.Status.AssignedLoadBalancer,
-			HostedZoneID:         r.getALBHostedZoneId(ghr.Status.AssignedLoadBalancer),
-			EvaluateTargetHealth: true,
-		}
-		var deleteErrors []string
-		for _, recordType := range []string{"A", "AAAA"} {
-			aliasRecord := aws.DNSRecord{
-				Name:        ghr.Spec.Hostname,
-				Type:        recordType,
-				AliasTarget: aliasTarget,
+	// Phase 2: Cleanup was already performed up to the step we're waiting at — re-running it
+	// would make unnecessary AWS calls and K8s object updates on every poll cycle.
+	if existingCond := meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeDeleting); existingCond != nil {
+		switch conditions.Reason(existingCond.Reason) {
+		case conditions.ReasonWaitingForCertDetachment:
+			return r.pollCertificateDetachment(ctx, ghr)
+		case conditions.ReasonWaitingBetweenSteps:
+			if remaining := r.CleanupStepDelay - time.Since(existingCond.LastTransitionTime.Time); remaining > 0 {
+				return ctrl.Result{RequeueAfter: remaining}, nil
 			}
-			awsCtx, cancel := withAWSTimeout(ctx)
-			err := r.Route53Client.DeleteRecord(awsCtx, ghr.Spec.ZoneId, aliasRecord)
-			cancel()
-			if err != nil {
-				deleteErrors = append(deleteErrors, recordType)
-				logger.Error(err, "Failed to delete Route53 alias record",
-					"type", recordType,
-					"hostname", ghr.Spec.Hostname,
-					"zoneId", ghr.Spec.ZoneId)
+			if certificateFirst(ghr) {
+				return r.deleteRecordsAndCertificate(ctx, ghr)
 			}
-		}
-		if len(deleteErrors) == 0 {
-			logger.Info("Deleted Route53 alias records (A + AAAA)", "hostname", ghr.Spec.Hostname)
-		} else {
-			logger.Info("Attempted deletion of Route53 alias records (A + AAAA); some failed",
-				"hostname", ghr.Spec.Hostname,
-				"failedTypes", deleteErrors)
+			return r.releaseAndDeleteCertificate(ctx, ghr, nil)
 		}
 	}
 
-	// Step 2: Remove certificate ARN from Gateway annotation (triggers AWS LBC to update ALB)
-	if ghr.Status.AssignedGateway != "" && ghr.Status.CertificateArn != "" {
-		if err := r.removeCertificateFromGateway(ctx, ghr); err != nil {
-			logger.Error(err, "Failed to remove certificate from gateway",
-				"gateway", ghr.Status.AssignedGateway,
-				"hostname", ghr.Spec.Hostname)
-		} else {
-			logger.Info("Removed certificate from gateway", "gateway", ghr.Status.AssignedGateway)
-		}
+	// Phase 1: First reconcile — perform the cleanup steps
+	logger.Info("Deleting GatewayHostnameRequest", "cleanupOrder", ghr.Status.CleanupOrder)
+	if certificateFirst(ghr) {
+		return r.releaseCertificateFirst(ctx, ghr)
 	}
 
-	// Step 3: Remove namespace label for gateway access
-	if err := r.removeNamespaceLabel(ctx, ghr); err != nil {
-		logger.Error(err, "Failed to remove namespace label",
-			"namespace", ghr.Namespace,
-			"hostname", ghr.Spec.Hostname)
+	// Step 1: Remove the Route53 alias records (A + AAAA) and the certificate's validation
+	// records, one change batch per zone. Nothing else is torn down until they are gone,
+	// unless the deletion has been failing for longer than forceDeleteAfter.
+	teardownErr := r.teardownRecords(ctx, ghr)
+	if teardownErr != nil && !r.forceDeleteDue(ctx, ghr) {
+		r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "DNSTeardownFailed",
+			"Keeping the finalizer until the DNS records are deleted: %v", teardownErr)
+		return ctrl.Result{}, teardownErr
 	}
+	if teardownErr == nil && r.CleanupStepDelay > 0 {
+		return r.waitDuringDeletion(ctx, ghr, conditions.ReasonWaitingBetweenSteps,
+			fmt.Sprintf("DNS records deleted; detaching the certificate after %s", r.CleanupStepDelay), r.CleanupStepDelay)
+	}
+	return r.releaseAndDeleteCertificate(ctx, ghr, teardownErr)
+}
 
-	// Step 4: Delete DNS validation records
-	if ghr.Status.CertificateArn != "" {
-		awsCtx, cancel := withAWSTimeout(ctx)
-		validationRecords, err := r.ACMClient.GetValidationRecords(awsCtx, ghr.Status.CertificateArn)
-		cancel()
-		if err == nil {
-			for _, vr := range validationRecords {
-				record := aws.DNSRecord{
-					Name:  vr.Name,
-					Type:  vr.Type,
-					Value: vr.Value,
-					TTL:   300,
-				}
-				recordCtx, recordCancel := withAWSTimeout(ctx)
-				err := r.Route53Client.DeleteRecord(recordCtx, ghr.Spec.ZoneId, record)
-				recordCancel()
-				if err != nil {
-					logger.Error(err, "Failed to delete validation record",
-						"name", vr.Name,
-						"hostname", ghr.Spec.Hostname)
-				}
-			}
-			logger.Info("Deleted DNS validation records", "hostname", ghr.Spec.Hostname)
-		}
+// releaseAndDeleteCertificate runs the cleanup steps after the DNS records of a dns-first
+// deletion: it releases the Gateway and Kubernetes resources and deletes the certificate once
+// the ALB detached it. A teardownErr means the records couldn't be deleted and the deletion
+// is abandoned after the release.
+func (r *GatewayHostnameRequestReconciler) releaseAndDeleteCertificate(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, teardownErr error) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	r.releaseRequestResources(ctx, ghr)
+
+	// The records and the certificate they validate are left to the orphan sweep
+	if teardownErr != nil {
+		return r.abandonDeletion(ctx, ghr, true, teardownErr)
+	}
+
+	// Certificates shared with other requests are left to them
+	r.discardExpansionCertificates(ctx, ghr)
+	if shared, err := r.certificateShared(ctx, ghr, ghr.Status.CertificateArn); err == nil && shared {
+		logger.Info("Certificate shared with other requests, leaving it in place", "arn", ghr.Status.CertificateArn)
+		return r.finalizeDeletion(ctx, ghr)
 	}
 
 	// Step 5: Check if certificate is still in use by ALB
@@ -407,97 +939,139 @@ func (r *GatewayHostnameRequestReconciler) reconcileDelete(ctx context.Context,
 		inUse, err := r.isCertificateInUse(ctx, ghr.Status.CertificateArn)
 		if err != nil {
 			logger.Error(err, "Failed to check certificate usage, continuing anyway",
-				"arn", ghr.Status.CertificateArn,
-				"hostname", ghr.Spec.Hostname)
+				"arn", ghr.Status.CertificateArn)
 			// Continue with deletion attempt - best effort
 		} else if inUse {
 			logger.Info("Certificate still in use by ALB, will poll for detachment",
-				"arn", ghr.Status.CertificateArn,
-				"hostname", ghr.Spec.Hostname)
-
-			// Set the WaitingForCertDetachment condition so subsequent reconciles
-			// enter Phase 2 (poll-only) instead of re-running cleanup.
-			// Re-fetch to get latest resourceVersion before status update.
-			if err := r.Get(ctx, client.ObjectKeyFromObject(ghr), ghr); err != nil {
-				return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
-			}
-			r.setCondition(ghr, ConditionTypeDeleting, metav1.ConditionTrue, "WaitingForCertDetachment",
-				"Waiting for ALB to detach certificate")
-			if err := r.Status().Update(ctx, ghr); err != nil {
-				// Status update failed — the condition won't be set, so next reconcile
-				// will re-run cleanup (Phase 1). This is safe because all steps are idempotent.
-				logger.Error(err, "Failed to set WaitingForCertDetachment condition, will retry cleanup")
-			}
-			return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+				"arn", ghr.Status.CertificateArn)
+			return r.waitDuringDeletion(ctx, ghr, conditions.ReasonWaitingForCertDetachment,
+				"Waiting for ALB to detach certificate", 15*time.Second)
 		}
 
 		// Step 6: Delete ACM certificate (only after confirmed not in use)
-		awsCtx, cancel := withAWSTimeout(ctx)
-		err = r.ACMClient.DeleteCertificate(awsCtx, ghr.Status.CertificateArn)
-		cancel()
-		if err != nil {
-			logger.Error(err, "Failed to delete ACM certificate",
-				"arn", ghr.Status.CertificateArn,
-				"hostname", ghr.Spec.Hostname)
-		} else {
-			logger.Info("Deleted ACM certificate", "arn", ghr.Status.CertificateArn)
-		}
+		r.deleteRequestCertificate(ctx, ghr)
 	}
 
 	return r.finalizeDeletion(ctx, ghr)
 }
 
-// pollCertificateDetachment checks if the ALB has released the certificate.
-// Called on subsequent reconciles after cleanup is already done (Phase 2).
-func (r *GatewayHostnameRequestReconciler) pollCertificateDetachment(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (ctrl.Result, error) {
+// releaseRequestResources removes the request's certificate from its Gateways and releases
+// the Kubernetes resources set up for it. Failures are logged; the cleanup goes on.
+func (r *GatewayHostnameRequestReconciler) releaseRequestResources(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) {
 	logger := log.FromContext(ctx)
 
-	if ghr.Status.CertificateArn == "" {
-		// No certificate to wait for — proceed to finalize
-		return r.finalizeDeletion(ctx, ghr)
+	// Step 2: Remove certificate ARN from Gateway annotation (triggers AWS LBC to update ALB)
+	if ghr.Status.AssignedGateway != "" && (ghr.Status.CertificateArn != "" || isHTTPOnly(ghr)) {
+		if err := r.removeCertificateFromGateway(ctx, ghr); err != nil {
+			logger.Error(err, "Failed to remove certificate from gateway",
+				"gateway", ghr.Status.AssignedGateway)
+		} else {
+			logger.Info("Removed certificate from gateway", "gateway", ghr.Status.AssignedGateway)
+		}
+	}
+	if ghr.Status.MigratingFromGateway != "" {
+		if err := r.releaseGateway(ctx, ghr, ghr.Status.MigratingFromGateway, migratingFromNamespace(ghr)); err != nil {
+			logger.Error(err, "Failed to remove certificate from previous gateway",
+				"gateway", ghr.Status.MigratingFromGateway)
+		}
 	}
 
-	inUse, err := r.isCertificateInUse(ctx, ghr.Status.CertificateArn)
+	// Step 3: Remove namespace label for gateway access
+	if err := r.removeNamespaceLabel(ctx, ghr); err != nil {
+		logger.Error(err, "Failed to remove namespace label",
+			"namespace", ghr.Namespace)
+	}
+	if err := r.removeReferenceGrant(ctx, ghr); err != nil {
+		logger.Error(err, "Failed to remove ReferenceGrant",
+			"namespace", ghr.Namespace)
+	}
+	if err := r.removeTargetGroupConfigurations(ctx, ghr); err != nil {
+		logger.Error(err, "Failed to remove TargetGroupConfigurations",
+			"namespace", ghr.Namespace)
+	}
+	if err := r.removeSecurityHeaders(ctx, ghr); err != nil {
+		logger.Error(err, "Failed to remove security headers from HTTPRoutes",
+			"namespace", ghr.Namespace)
+	}
+	if err := r.syncPrivateZoneVPCs(ctx, ghr, nil); err != nil {
+		logger.Error(err, "Failed to disassociate private hosted zones from VPCs")
+	}
+
+	// Step 4: Delete the DNS-01 challenge record
+	if certificateIssuer(ghr) == CertificateIssuerACME {
+		if err := r.removeChallengeRecord(ctx, ghr); err != nil {
+			logger.Error(err, "Failed to delete DNS-01 challenge record")
+		}
+	}
+}
+
+// deleteRequestCertificate deletes the request's ACM certificate, logging failures
+func (r *GatewayHostnameRequestReconciler) deleteRequestCertificate(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+	logger := log.FromContext(ctx)
+
+	awsCtx, cancel := withAWSTimeout(ctx)
+	deleted, err := r.deleteCertificate(awsCtx, ghr, ghr.Status.CertificateArn)
+	cancel()
 	if err != nil {
-		logger.Error(err, "Failed to check certificate usage, attempting deletion anyway",
+		logger.Error(err, "Failed to delete ACM certificate",
 			"arn", ghr.Status.CertificateArn)
-		// Fall through to attempt cert deletion
-	} else if inUse {
-		// If this is the last GHR on the Gateway, the ALB still holds the cert as its
-		// default certificate. The cert will never detach while the ALB exists.
-		// Proactively delete the Gateway to trigger ALB teardown.
-		if ghr.Status.AssignedGateway != "" && ghr.Status.AssignedGatewayNamespace != "" {
-			if empty, _ := r.isGatewayEmpty(ctx, ghr.Status.AssignedGateway, ghr.Status.AssignedGatewayNamespace, ghr.Namespace, ghr.Name); empty {
-				logger.Info("Certificate stuck on ALB and Gateway is empty, deleting Gateway to trigger ALB teardown",
-					"gateway", ghr.Status.AssignedGateway,
-					"arn", ghr.Status.CertificateArn)
-				if err := r.cleanupEmptyGateway(ctx, ghr.Status.AssignedGateway, ghr.Status.AssignedGatewayNamespace, ghr.Namespace, ghr.Name); err != nil {
-					logger.Error(err, "Failed to cleanup empty gateway during cert detach wait")
+	} else if deleted {
+		logger.Info("Deleted ACM certificate", "arn", ghr.Status.CertificateArn)
+	}
+}
+
+// pollCertificateDetachment checks if the ALB has released the certificate.
+// Called on subsequent reconciles after cleanup is already done (Phase 2).
+func (r *GatewayHostnameRequestReconciler) pollCertificateDetachment(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if ghr.Status.CertificateArn != "" {
+		inUse, err := r.isCertificateInUse(ctx, ghr.Status.CertificateArn)
+		if err != nil {
+			logger.Error(err, "Failed to check certificate usage, attempting deletion anyway",
+				"arn", ghr.Status.CertificateArn)
+			// Fall through to attempt cert deletion
+		} else if inUse {
+			// If this is the last GHR on the Gateway, the ALB still holds the cert as its
+			// default certificate. The cert will never detach while the ALB exists.
+			// Proactively delete the Gateway to trigger ALB teardown.
+			if ghr.Status.AssignedGateway != "" && ghr.Status.AssignedGatewayNamespace != "" {
+				if empty, _ := r.isGatewayEmpty(ctx, ghr.Status.AssignedGateway, ghr.Status.AssignedGatewayNamespace, ghr.Namespace, ghr.Name); empty {
+					logger.Info("Certificate stuck on ALB and Gateway is empty, deleting Gateway to trigger ALB teardown",
+						"gateway", ghr.Status.AssignedGateway,
+						"arn", ghr.Status.CertificateArn)
+					if err := r.cleanupEmptyGateway(ctx, ghr.Status.AssignedGateway, ghr.Status.AssignedGatewayNamespace, ghr.Namespace, ghr.Name); err != nil {
+						logger.Error(err, "Failed to cleanup empty gateway during cert detach wait")
+					}
 				}
 			}
+
+			if r.forceDeleteDue(ctx, ghr) {
+				// The records of a certificate-first deletion are still in place
+				return r.abandonDeletion(ctx, ghr, certificateFirst(ghr), fmt.Errorf("certificate %s is still in use", ghr.Status.CertificateArn))
+			}
+
+			logger.Info("Certificate still in use by ALB, requeuing",
+				"arn", ghr.Status.CertificateArn)
+			// No status update needed — condition is already set. Just wait.
+			return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
 		}
+	}
 
-		logger.Info("Certificate still in use by ALB, requeuing",
-			"arn", ghr.Status.CertificateArn,
-			"hostname", ghr.Spec.Hostname)
-		// No status update needed — condition is already set. Just wait.
-		return ctrl.Result{RequeueAfter: 15 * time.Second}, nil
+	if certificateFirst(ghr) {
+		logger.Info("Certificate detached from ALB, deleting the DNS records",
+			"arn", ghr.Status.CertificateArn)
+		return r.certificateDetached(ctx, ghr)
+	}
+	if ghr.Status.CertificateArn == "" {
+		// No certificate to wait for — proceed to finalize
+		return r.finalizeDeletion(ctx, ghr)
 	}
 
 	// Certificate is no longer in use — delete it
 	logger.Info("Certificate detached from ALB, deleting",
-		"arn", ghr.Status.CertificateArn,
-		"hostname", ghr.Spec.Hostname)
-	awsCtx, cancel := withAWSTimeout(ctx)
-	err = r.ACMClient.DeleteCertificate(awsCtx, ghr.Status.CertificateArn)
-	cancel()
-	if err != nil {
-		logger.Error(err, "Failed to delete ACM certificate",
-			"arn", ghr.Status.CertificateArn,
-			"hostname", ghr.Spec.Hostname)
-	} else {
-		logger.Info("Deleted ACM certificate", "arn", ghr.Status.CertificateArn)
-	}
+		"arn", ghr.Status.CertificateArn)
+	r.deleteRequestCertificate(ctx, ghr)
 
 	return r.finalizeDeletion(ctx, ghr)
 }
@@ -519,6 +1093,11 @@ func (r *GatewayHostnameRequestReconciler) finalizeDeletion(ctx context.Context,
 		}
 	}
 
+	forgetCostEstimate(ghr)
+	forgetReconcileFailures(ghr)
+	forgetReconcileLag(ghr)
+	r.forgetConditionFailures(ghr)
+
 	// Step 9: Remove finalizer
 	if err := r.Get(ctx, client.ObjectKeyFromObject(ghr), ghr); err != nil {
 		return ctrl.Result{}, err
@@ -528,7 +1107,7 @@ func (r *GatewayHostnameRequestReconciler) finalizeDeletion(ctx context.Context,
 		return ctrl.Result{}, err
 	}
 
-	logger.Info("Successfully deleted GatewayHostnameRequest", "hostname", ghr.Spec.Hostname)
+	logger.Info("Successfully deleted GatewayHostnameRequest")
 	return ctrl.Result{}, nil
 }
 
@@ -544,16 +1123,6 @@ func (r *GatewayHostnameRequestReconciler) isCertificateInUse(ctx context.Contex
 	return len(details.InUseBy) > 0, nil
 }
 
-// getALBHostedZoneId extracts the ALB hosted zone ID from the load balancer DNS name
-func (r *GatewayHostnameRequestReconciler) getALBHostedZoneId(albDNS string) string {
-	region, err := aws.ExtractRegionFromALBDNS(albDNS)
-	if err != nil {
-		return ""
-	}
-	zoneId, _ := aws.GetALBHostedZoneID(region)
-	return zoneId
-}
-
 // validateRequest validates the GatewayHostnameRequest spec
 func (r *GatewayHostnameRequestReconciler) validateRequest(ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
 	if ghr.Spec.ZoneId == "" {
@@ -562,32 +1131,130 @@ func (r *GatewayHostnameRequestReconciler) validateRequest(ghr *gatewayv1alpha1.
 	if ghr.Spec.Hostname == "" {
 		return fmt.Errorf("hostname is required")
 	}
-	// TODO: Add domain allowlist validation
+	if err := ValidateHostname(ghr.Spec.Hostname); err != nil {
+		return err
+	}
+	if suffix, internal := InternalDomainSuffix(ghr.Spec.Hostname, r.internalDomainSuffixes()); internal {
+		return fmt.Errorf("hostname %s is in the cluster-internal domain %s and can't get public DNS or certificates", ghr.Spec.Hostname, suffix)
+	}
+	// Hostnames claimed before the allowlist changed keep working
+	if settings := r.settings(); settings != nil && !conditions.IsTrue(ghr.Status.Conditions, ConditionTypeClaimed) &&
+		!hostnameAllowed(ghr.Spec.Hostname, settings.AllowedDomains) {
+		return fmt.Errorf("hostname %s is not in the allowed domains %s", ghr.Spec.Hostname, strings.Join(settings.AllowedDomains, ", "))
+	}
+	// New Gateways of an HTTPS-only pool can't serve plain HTTP; a selector may still pick an
+	// existing Gateway with an HTTP listener. Assigned requests keep their Gateway.
+	if isHTTPOnly(ghr) && ghr.Status.AssignedGateway == "" && ghr.Spec.GatewaySelector == nil &&
+		r.GatewayPool != nil && r.GatewayPool.HTTPSOnly() {
+		return fmt.Errorf("tls: %s needs an HTTP listener, but the Gateway pool is HTTPS-only", TLSDisabled)
+	}
+	// ALBs only use certificates of their own region
+	if gatewayRegion := r.gatewayRegion(ghr); ghr.Spec.CertificateRegion != "" && !isDNSOnly(ghr) &&
+		gatewayRegion != "" && ghr.Spec.CertificateRegion != gatewayRegion {
+		return fmt.Errorf("certificateRegion %s differs from the Gateway region %s; only DNS-only requests can pin another region", ghr.Spec.CertificateRegion, gatewayRegion)
+	}
+	// Propagated labels end up on the Gateway, readable by everyone who can read Gateways
+	for _, key := range r.GatewayLabelKeys {
+		if kind := secretKind(ghr.Labels[key]); kind != "" {
+			return fmt.Errorf("label %s looks like %s and would be copied to the Gateway", key, kind)
+		}
+	}
+	// IPv6-only hostnames need AAAA aliases, which only dualstack ALBs answer
+	if ghr.Spec.IPv6Only && !isDNSOnly(ghr) && !strings.HasPrefix(r.IPAddressType, "dualstack") {
+		return fmt.Errorf("ipv6Only needs dualstack ALBs, but the controller creates IPv4-only ALBs (--ip-address-type)")
+	}
 	return nil
 }
 
-// setCondition sets a condition on the GatewayHostnameRequest status
-func (r *GatewayHostnameRequestReconciler) setCondition(ghr *gatewayv1alpha1.GatewayHostnameRequest, condType string, status metav1.ConditionStatus, reason, message string) {
-	meta.SetStatusCondition(&ghr.Status.Conditions, metav1.Condition{
-		Type:               condType,
-		Status:             status,
-		Reason:             reason,
-		Message:            message,
-		ObservedGeneration: ghr.Generation,
-	})
+// gatewayRegion returns the region of the request's ALB, or the controller's region before one
+// is assigned
+func (r *GatewayHostnameRequestReconciler) gatewayRegion(ghr *gatewayv1alpha1.GatewayHostnameRequest) string {
+	if ghr.Status.GatewayRegion != "" {
+		return ghr.Status.GatewayRegion
+	}
+	return r.Region
+}
+
+// internalDomainSuffixes returns the domains requests may not use
+func (r *GatewayHostnameRequestReconciler) internalDomainSuffixes() []string {
+	if r.InternalDomainSuffixes == nil {
+		return DefaultInternalDomainSuffixes
+	}
+	return r.InternalDomainSuffixes
+}
+
+// settings returns the settings of the OrchestratorConfig, or nil if the controller runs
+// without one
+func (r *GatewayHostnameRequestReconciler) settings() *Settings {
+	if r.Settings == nil {
+		return nil
+	}
+	return r.Settings.Load()
+}
+
+// setCondition sets a condition on the GatewayHostnameRequest status and refreshes status.message.
+// Changes in status or reason are also recorded in status.history.
+func (r *GatewayHostnameRequestReconciler) setCondition(ghr *gatewayv1alpha1.GatewayHostnameRequest, condType string, status metav1.ConditionStatus, reason conditions.Reason, message string) {
+	r.conditionFailures.reset(conditionFailureKey(ghr, condType))
+	if condType == ConditionTypeReady && status == metav1.ConditionTrue && !conditions.IsTrue(ghr.Status.Conditions, ConditionTypeReady) {
+		recordTimeToReady(ghr)
+	}
+	if conditions.Set(&ghr.Status.Conditions, condType, status, reason, message, ghr.Generation) {
+		recordHistory(ghr, condType, status, string(reason), message)
+		r.notifyTransition(ghr, condType, status, reason, message)
+	}
+	ghr.Status.Message = explainStatus(ghr)
 }
 
 // SetupWithManager sets up the controller with the Manager
+// HTTPRoute changes requeue the requests in the route namespace whose hostname the route serves,
+// so backend TargetGroupConfigurations follow the routes.
 func (r *GatewayHostnameRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&gatewayv1alpha1.GatewayHostnameRequest{}).
+		Watches(&gwapiv1.HTTPRoute{}, handler.EnqueueRequestsFromMapFunc(r.requestsForRoute)).
+		Watches(&gatewayv1alpha1.GatewayHostnameRequest{}, handler.EnqueueRequestsFromMapFunc(r.requestsForCertificateHost)).
 		Complete(r)
 }
 
+// requestsForRoute maps an HTTPRoute to the requests in its namespace for the hostnames it serves
+func (r *GatewayHostnameRequestReconciler) requestsForRoute(ctx context.Context, obj client.Object) []reconcile.Request {
+	route, ok := obj.(*gwapiv1.HTTPRoute)
+	if !ok {
+		return nil
+	}
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList, client.InNamespace(route.Namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list GatewayHostnameRequests for HTTPRoute", "route", route.Name)
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, ghr := range ghrList.Items {
+		if routeServesHostname(route, ghr.Spec.Hostname) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: ghr.Name, Namespace: ghr.Namespace}})
+		}
+	}
+	return requests
+}
+
 // computeSpecHash computes a hash of the spec fields that require re-provisioning when changed
 func computeSpecHash(spec *gatewayv1alpha1.GatewayHostnameRequestSpec) string {
 	// Hash hostname + zoneId + visibility + gatewayClass
 	data := fmt.Sprintf("%s|%s|%s|%s", spec.Hostname, spec.ZoneId, spec.Visibility, spec.GatewayClass)
+	// Switching between Gateway and DNS-only mode re-provisions; the suffix is only added
+	// for DNS-only requests so hashes of existing requests don't change
+	if spec.AliasTarget != nil {
+		data += "|dns-only"
+	}
+	if spec.CertificateIssuer == CertificateIssuerACME {
+		data += "|acme"
+	}
+	if spec.TLS == TLSDisabled {
+		data += "|http-only"
+	}
+	if spec.CertificateRegion != "" {
+		data += "|region=" + spec.CertificateRegion
+	}
 	hash := sha256.Sum256([]byte(data))
 	return hex.EncodeToString(hash[:8]) // First 8 bytes is enough
 }
@@ -596,43 +1263,21 @@ func computeSpecHash(spec *gatewayv1alpha1.GatewayHostnameRequestSpec) string {
 // This is called when spec drift is detected to clean up before re-provisioning with new settings
 func (r *GatewayHostnameRequestReconciler) cleanupForReprovisioning(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
 	logger := log.FromContext(ctx)
-	logger.Info("Cleaning up resources for reprovisioning", "hostname", ghr.Spec.Hostname)
+	logger.Info("Cleaning up resources for reprovisioning")
 
 	// Step 1: Remove Route53 alias records (A + AAAA)
 	if ghr.Status.AssignedLoadBalancer != "" {
-		aliasTarget := &aws.AliasTarget{
-			DNSName:              ghr.Status.AssignedLoadBalancer,
-			HostedZoneID:         r.getALBHostedZoneId(ghr.Status.AssignedLoadBalancer),
-			EvaluateTargetHealth: true,
-		}
-		var deleteErrors []string
-		for _, recordType := range []string{"A", "AAAA"} {
-			aliasRecord := aws.DNSRecord{
-				Name:        ghr.Spec.Hostname,
-				Type:        recordType,
-				AliasTarget: aliasTarget,
-			}
-			awsCtx, cancel := withAWSTimeout(ctx)
-			err := r.Route53Client.DeleteRecord(awsCtx, ghr.Spec.ZoneId, aliasRecord)
-			cancel()
-			if err != nil {
-				deleteErrors = append(deleteErrors, recordType)
-				logger.Error(err, "Failed to delete Route53 alias record during reprovisioning",
-					"type", recordType,
-					"hostname", ghr.Spec.Hostname)
-			}
-		}
+		deleteErrors := r.deleteAliasRecords(ctx, ghr)
 		if len(deleteErrors) == 0 {
-			logger.Info("Deleted Route53 alias records (A + AAAA) during reprovisioning", "hostname", ghr.Spec.Hostname)
+			logger.Info("Deleted Route53 alias records (A + AAAA) during reprovisioning")
 		} else {
 			logger.Info("Attempted deletion of Route53 alias records (A + AAAA) during reprovisioning; some failed",
-				"hostname", ghr.Spec.Hostname,
-				"failedTypes", deleteErrors)
+				"failed", deleteErrors)
 		}
 	}
 
 	// Step 2: Remove certificate ARN from Gateway annotation
-	if ghr.Status.AssignedGateway != "" && ghr.Status.CertificateArn != "" {
+	if ghr.Status.AssignedGateway != "" && (ghr.Status.CertificateArn != "" || isHTTPOnly(ghr)) {
 		if err := r.removeCertificateFromGateway(ctx, ghr); err != nil {
 			logger.Error(err, "Failed to remove certificate from gateway during reprovisioning",
 				"gateway", ghr.Status.AssignedGateway)
@@ -640,15 +1285,45 @@ func (r *GatewayHostnameRequestReconciler) cleanupForReprovisioning(ctx context.
 			logger.Info("Removed certificate from gateway during reprovisioning", "gateway", ghr.Status.AssignedGateway)
 		}
 	}
+	if ghr.Status.MigratingFromGateway != "" {
+		if err := r.releaseGateway(ctx, ghr, ghr.Status.MigratingFromGateway, migratingFromNamespace(ghr)); err != nil {
+			logger.Error(err, "Failed to remove certificate from previous gateway during reprovisioning",
+				"gateway", ghr.Status.MigratingFromGateway)
+		}
+	}
 
 	// Step 3: Remove namespace label for gateway access
 	if err := r.removeNamespaceLabel(ctx, ghr); err != nil {
 		logger.Error(err, "Failed to remove namespace label during reprovisioning",
 			"namespace", ghr.Namespace)
 	}
+	if err := r.removeReferenceGrant(ctx, ghr); err != nil {
+		logger.Error(err, "Failed to remove ReferenceGrant during reprovisioning",
+			"namespace", ghr.Namespace)
+	}
+	if err := r.removeTargetGroupConfigurations(ctx, ghr); err != nil {
+		logger.Error(err, "Failed to remove TargetGroupConfigurations during reprovisioning",
+			"namespace", ghr.Namespace)
+	}
+	if err := r.removeSecurityHeaders(ctx, ghr); err != nil {
+		logger.Error(err, "Failed to remove security headers during reprovisioning",
+			"namespace", ghr.Namespace)
+	}
 
-	// Step 4: Delete DNS validation records
-	if ghr.Status.CertificateArn != "" {
+	// Step 4: Delete DNS validation records, unless the certificate is shared with other requests
+	// or the hostname with other owners
+	shared, err := r.certificateShared(ctx, ghr, ghr.Status.CertificateArn)
+	if err != nil {
+		logger.Error(err, "Failed to check whether the certificate is shared, keeping it")
+		shared = true
+	}
+	if !shared {
+		if shared, err = r.hasCoOwners(ctx, ghr); err != nil {
+			logger.Error(err, "Failed to check whether the hostname is shared, keeping its validation records")
+			shared = true
+		}
+	}
+	if ghr.Status.CertificateArn != "" && !shared {
 		awsCtx, cancel := withAWSTimeout(ctx)
 		validationRecords, err := r.ACMClient.GetValidationRecords(awsCtx, ghr.Status.CertificateArn)
 		cancel()
@@ -661,26 +1336,32 @@ func (r *GatewayHostnameRequestReconciler) cleanupForReprovisioning(ctx context.
 					TTL:   300,
 				}
 				recordCtx, recordCancel := withAWSTimeout(ctx)
-				err := r.Route53Client.DeleteRecord(recordCtx, ghr.Spec.ZoneId, record)
+				err := r.Route53Client.DeleteRecord(recordCtx, recordZoneID(ghr), record)
 				recordCancel()
 				if err != nil {
 					logger.Error(err, "Failed to delete validation record during reprovisioning",
 						"name", vr.Name)
 				}
 			}
-			logger.Info("Deleted DNS validation records during reprovisioning", "hostname", ghr.Spec.Hostname)
+			logger.Info("Deleted DNS validation records during reprovisioning")
+		}
+	}
+	if certificateIssuer(ghr) == CertificateIssuerACME {
+		if err := r.removeChallengeRecord(ctx, ghr); err != nil {
+			logger.Error(err, "Failed to delete DNS-01 challenge record during reprovisioning")
 		}
 	}
 
 	// Step 5: Delete ACM certificate (best effort, may fail if still in use)
-	if ghr.Status.CertificateArn != "" {
+	r.discardExpansionCertificates(ctx, ghr)
+	if ghr.Status.CertificateArn != "" && !shared {
 		awsCtx, cancel := withAWSTimeout(ctx)
-		err := r.ACMClient.DeleteCertificate(awsCtx, ghr.Status.CertificateArn)
+		deleted, err := r.deleteCertificate(awsCtx, ghr, ghr.Status.CertificateArn)
 		cancel()
 		if err != nil {
 			logger.Error(err, "Failed to delete ACM certificate during reprovisioning (may still be in use)",
 				"arn", ghr.Status.CertificateArn)
-		} else {
+		} else if deleted {
 			logger.Info("Deleted ACM certificate during reprovisioning", "arn", ghr.Status.CertificateArn)
 		}
 	}
@@ -700,18 +1381,6 @@ func (r *GatewayHostnameRequestReconciler) cleanupForReprovisioning(ctx context.
 func (r *GatewayHostnameRequestReconciler) ensureGatewayConfiguration(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
 	logger := log.FromContext(ctx)
 
-	// Ensure LoadBalancerConfiguration is synced with current certificate list
-	visibility := ghr.Spec.Visibility
-	if visibility == "" {
-		visibility = "internet-facing"
-	}
-
-	if err := r.syncLoadBalancerConfiguration(ctx, ghr.Status.AssignedGateway, ghr.Status.AssignedGatewayNamespace, visibility, ghr.Spec.WafArn, ghr.Status.CertificateArn); err != nil {
-		logger.Info("Failed to sync LoadBalancerConfiguration", "error", err)
-		return err
-	}
-
-	// Ensure Gateway has correct annotations
 	var gw gwapiv1.Gateway
 	if err := r.Get(ctx, types.NamespacedName{
 		Name:      ghr.Status.AssignedGateway,
@@ -719,14 +1388,99 @@ func (r *GatewayHostnameRequestReconciler) ensureGatewayConfiguration(ctx contex
 	}, &gw); err != nil {
 		return fmt.Errorf("failed to get gateway: %w", err)
 	}
-
-	needsUpdate := false
+	if err := foreignOwnership("Gateway", &gw); err != nil {
+		return err
+	}
 	if gw.Annotations == nil {
 		gw.Annotations = make(map[string]string)
 	}
+	specBefore := gw.Spec.DeepCopy()
+
+	// The Gateway keeps its visibility; reconcilePlacement moves requests asking for another
+	visibility := r.gatewayVisibility(ctx, ghr, &gw)
+	wafArn := r.wafArn(ghr)
+
+	// The WAF is shared by every hostname on the ALB; report the blast radius and keep the
+	// Gateway's current WAF while a large change awaits confirmation
+	allowed, err := r.checkGatewayChangeImpact(ctx, ghr, &gw, wafArn)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		wafArn = gw.Annotations["gateway.opendi.com/waf-arn"]
+	}
+
+	// Ensure LoadBalancerConfiguration is synced with current certificate list, and report
+	// changes made to it outside the controller that this overwrites
+	modified := r.modifiedLoadBalancerConfiguration(ctx, gw.Name, gw.Namespace)
+	if err := r.syncLoadBalancerConfiguration(ctx, gw.Name, gw.Namespace, visibility, wafArn, ghr.Status.CertificateArn); err != nil {
+		logger.Info("Failed to sync LoadBalancerConfiguration", "error", err)
+		return err
+	}
+	r.reportRevertedLoadBalancerConfiguration(ctx, ghr, gw.Name, gw.Namespace, modified)
+
+	// Ensure Gateway has correct annotations
+	needsUpdate := false
+
+	if r.perHostnameListeners() {
+		changed, err := r.syncHostnameListeners(ctx, &gw, ghr, true)
+		if err != nil {
+			return err
+		}
+		needsUpdate = needsUpdate || changed
+	}
+
+	if r.NamespaceAccess == NamespaceAccessGateway {
+		changed, err := r.syncAllowedNamespaces(ctx, &gw, ghr, true)
+		if err != nil {
+			return err
+		}
+		needsUpdate = needsUpdate || changed
+	}
+
+	if r.propagatesGatewayLabels(&gw) {
+		changed, err := r.syncGatewayLabels(ctx, &gw, ghr, true)
+		if err != nil {
+			return err
+		}
+		needsUpdate = needsUpdate || changed
+	}
+
+	// Keep the certificate count current and cordon the Gateway while it is over capacity
+	arns, err := r.getGatewayCertificateARNs(ctx, gw.Name, gw.Namespace)
+	if err != nil {
+		return err
+	}
+	if r.syncGatewayCapacity(ctx, &gw, len(arns)) {
+		needsUpdate = true
+	}
+
+	// Publish the certificates the listener serves, including this request's like the
+	// LoadBalancerConfiguration sync above
+	served := arns
+	if ghr.Status.CertificateArn != "" && !slices.Contains(arns, ghr.Status.CertificateArn) {
+		served = append(slices.Clone(arns), ghr.Status.CertificateArn)
+	}
+	changed, err := r.syncCertificateList(ctx, &gw, served)
+	if err != nil {
+		return err
+	}
+	needsUpdate = needsUpdate || changed
 
 	// Ensure loadbalancer-configuration annotation
-	configName := fmt.Sprintf("%s-config", ghr.Status.AssignedGateway)
+	configName, err := r.loadBalancerConfigurationName(ghr.Status.AssignedGateway)
+	if err != nil {
+		return err
+	}
+
+	// While the request's spec is unchanged, differing annotations were edited by hand
+	if ghr.Status.ObservedGeneration == ghr.Generation {
+		r.reportDriftReverted(ctx, ghr, "Gateway "+gw.Namespace+"/"+gw.Name, gatewayAnnotationDrift(&gw, map[string]string{
+			"gateway.k8s.aws/loadbalancer-configuration": configName,
+			AnnotationVisibility:                         visibility,
+			"gateway.opendi.com/waf-arn":                 wafArn,
+		}))
+	}
 	if gw.Annotations["gateway.k8s.aws/loadbalancer-configuration"] != configName {
 		gw.Annotations["gateway.k8s.aws/loadbalancer-configuration"] = configName
 		needsUpdate = true
@@ -739,13 +1493,22 @@ func (r *GatewayHostnameRequestReconciler) ensureGatewayConfiguration(ctx contex
 	}
 
 	// Ensure WAF annotation matches spec
-	wafArn := ghr.Spec.WafArn
 	if gw.Annotations["gateway.opendi.com/waf-arn"] != wafArn {
 		gw.Annotations["gateway.opendi.com/waf-arn"] = wafArn
 		needsUpdate = true
 	}
 
+	// Stamp the ownership annotations, also on Gateways written before they existed
+	managed := managedGatewayAnnotations(gw.Annotations)
+	if gw.Annotations[AnnotationManagedBy] != ManagerName || gw.Annotations[AnnotationManagedChecksum] != managedChecksum(managed) {
+		needsUpdate = true
+	}
 	if needsUpdate {
+		generation := gw.Generation
+		if !equality.Semantic.DeepEqual(specBefore, &gw.Spec) {
+			generation++
+		}
+		stampManaged(&gw, managed, generation)
 		if err := r.Update(ctx, &gw); err != nil {
 			return fmt.Errorf("failed to update gateway annotations: %w", err)
 		}
@@ -779,17 +1542,19 @@ func (r *GatewayHostnameRequestReconciler) validateAssignedResources(ctx context
 				ghr.Status.AssignedGateway = ""
 				ghr.Status.AssignedGatewayNamespace = ""
 				ghr.Status.AssignedLoadBalancer = ""
+				ghr.Status.GatewayRegion = ""
 				driftDetected = true
 			}
 		} else {
 			// Gateway exists, check if LoadBalancerConfiguration exists
-			lbcName := fmt.Sprintf("%s-config", ghr.Status.AssignedGateway)
-			lbc := &unstructured.Unstructured{}
-			lbc.SetGroupVersionKind(LoadBalancerConfigurationGVK)
-			err = r.Get(ctx, types.NamespacedName{
+			lbcName, err := r.loadBalancerConfigurationName(ghr.Status.AssignedGateway)
+			if err != nil {
+				return err
+			}
+			_, err = r.getLoadBalancerConfiguration(ctx, types.NamespacedName{
 				Name:      lbcName,
 				Namespace: ghr.Status.AssignedGatewayNamespace,
-			}, lbc)
+			})
 			if err != nil && apierrors.IsNotFound(err) {
 				logger.Info("Drift detected: LoadBalancerConfiguration no longer exists", "name", lbcName)
 				r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "DriftDetected", "LoadBalancerConfiguration %s no longer exists", lbcName)
@@ -807,11 +1572,16 @@ func (r *GatewayHostnameRequestReconciler) validateAssignedResources(ctx context
 		awsCtx, cancel := withAWSTimeout(ctx)
 		certDetails, err := r.ACMClient.DescribeCertificate(awsCtx, ghr.Status.CertificateArn)
 		cancel()
-		if err != nil {
+		if aws.IsTransient(err) {
+			// A throttled or timed out read says nothing about the certificate; check again on
+			// the next reconcile instead of re-provisioning
+			logger.Info("Failed to check ACM certificate, not treating as drift",
+				"arn", ghr.Status.CertificateArn,
+				"error", err.Error())
+		} else if err != nil {
 			logger.Info("Drift detected: ACM certificate no longer exists or is inaccessible",
 				"arn", ghr.Status.CertificateArn,
-				"error", err,
-				"hostname", ghr.Spec.Hostname)
+				"error", err)
 			r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "DriftDetected", "ACM certificate %s no longer exists", ghr.Status.CertificateArn)
 			// Clear conditions to trigger recreation
 			meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeCertificateIssued)
@@ -819,24 +1589,26 @@ func (r *GatewayHostnameRequestReconciler) validateAssignedResources(ctx context
 			meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeListenerAttached)
 			meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeDnsAliasReady)
 			meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeReady)
-			ghr.Status.CertificateArn = ""
+			discardCertificate(ghr)
 			driftDetected = true
 		} else if certDetails.Status == "FAILED" || certDetails.Status == "REVOKED" {
 			logger.Info("Drift detected: ACM certificate in bad state", "arn", ghr.Status.CertificateArn, "status", certDetails.Status)
 			r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "CertificateFailed", "ACM certificate is in %s state", certDetails.Status)
+			r.notify(ghr, notify.EventCertificateFailed, fmt.Sprintf("ACM certificate is in %s state", certDetails.Status))
 			// Clear conditions to trigger recreation
 			meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeCertificateIssued)
 			meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeDnsValidated)
 			meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeListenerAttached)
 			meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeDnsAliasReady)
 			meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeReady)
-			ghr.Status.CertificateArn = ""
+			discardCertificate(ghr)
 			driftDetected = true
 		}
 	}
 
 	// If drift detected, update status to trigger re-reconciliation
 	if driftDetected {
+		recordHistory(ghr, "DriftDetected", "", "ResourcesMissing", "Assigned resources changed outside the controller, re-provisioning")
 		if err := r.Status().Update(ctx, ghr); err != nil {
 			return fmt.Errorf("failed to update status after drift detection: %w", err)
 		}
@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// AnnotationPropagatedLabels lists (comma-separated, sorted) the labels the orchestrator set
+// on a Gateway from the labels of its requests, so stale ones can be removed without touching
+// labels set by others
+const AnnotationPropagatedLabels = "gateway.opendi.com/propagated-labels"
+
+// ParseGatewayLabelKeys parses a comma-separated list of request label keys to propagate to
+// Gateways, e.g. "team,app.kubernetes.io/part-of". Keys are used without their prefix on the
+// Gateway, so two keys may not share a name.
+func ParseGatewayLabelKeys(s string) ([]string, error) {
+	var keys []string
+	names := map[string]string{}
+	for _, key := range strings.Split(s, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid label key %q: %s", key, strings.Join(errs, "; "))
+		}
+		name := labelKeyName(key)
+		if other, ok := names[name]; ok {
+			return nil, fmt.Errorf("label keys %q and %q have the same name", other, key)
+		}
+		names[name] = key
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// labelKeyName returns the name of a label key without its prefix
+func labelKeyName(key string) string {
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		return key[i+1:]
+	}
+	return key
+}
+
+// propagatedLabel returns the Gateway label for a request label, e.g.
+// gateway.opendi.com/team.payments for team=payments, or false if the result is not a valid
+// label key
+func propagatedLabel(key, value string) (string, bool) {
+	label := "gateway.opendi.com/" + labelKeyName(key) + "." + value
+	return label, len(validation.IsQualifiedName(label)) == 0
+}
+
+// desiredGatewayLabels counts the requests per propagated label. self is handled like in
+// syncHostnameListeners.
+func (r *GatewayHostnameRequestReconciler) desiredGatewayLabels(ctx context.Context, gw *gwapiv1.Gateway, self *gatewayv1alpha1.GatewayHostnameRequest, keepSelf bool) (map[string]int, error) {
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList); err != nil {
+		return nil, fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+
+	counts := map[string]int{}
+	count := func(ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+		for _, key := range r.GatewayLabelKeys {
+			if value := ghr.Labels[key]; value != "" {
+				if label, ok := propagatedLabel(key, value); ok {
+					counts[label]++
+				}
+			}
+		}
+	}
+	if keepSelf {
+		count(self)
+	}
+	for i := range ghrList.Items {
+		ghr := &ghrList.Items[i]
+		if (ghr.Namespace == self.Namespace && ghr.Name == self.Name) || !ghr.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if ghr.Status.AssignedGateway == gw.Name && ghr.Status.AssignedGatewayNamespace == gw.Namespace {
+			count(ghr)
+		}
+	}
+	return counts, nil
+}
+
+// syncGatewayLabels sets a label on the Gateway for every value of GatewayLabelKeys among
+// the requests assigned to it, with the number of those requests as value
+// (e.g. gateway.opendi.com/team.payments=3), in memory. Returns true if the labels changed.
+func (r *GatewayHostnameRequestReconciler) syncGatewayLabels(ctx context.Context, gw *gwapiv1.Gateway, self *gatewayv1alpha1.GatewayHostnameRequest, keepSelf bool) (bool, error) {
+	desired, err := r.desiredGatewayLabels(ctx, gw, self, keepSelf)
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	if previous := gw.Annotations[AnnotationPropagatedLabels]; previous != "" {
+		for _, label := range strings.Split(previous, ",") {
+			if _, ok := desired[label]; !ok {
+				if _, exists := gw.Labels[label]; exists {
+					delete(gw.Labels, label)
+					changed = true
+				}
+			}
+		}
+	}
+
+	labels := make([]string, 0, len(desired))
+	for label, n := range desired {
+		labels = append(labels, label)
+		if value := strconv.Itoa(n); gw.Labels[label] != value {
+			if gw.Labels == nil {
+				gw.Labels = make(map[string]string)
+			}
+			gw.Labels[label] = value
+			changed = true
+		}
+	}
+	sort.Strings(labels)
+
+	annotation := strings.Join(labels, ",")
+	if gw.Annotations[AnnotationPropagatedLabels] != annotation {
+		if gw.Annotations == nil {
+			gw.Annotations = make(map[string]string)
+		}
+		if annotation == "" {
+			delete(gw.Annotations, AnnotationPropagatedLabels)
+		} else {
+			gw.Annotations[AnnotationPropagatedLabels] = annotation
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// propagatesGatewayLabels reports whether request labels are propagated to Gateways, or were
+// propagated to this one before
+func (r *GatewayHostnameRequestReconciler) propagatesGatewayLabels(gw *gwapiv1.Gateway) bool {
+	return len(r.GatewayLabelKeys) > 0 || gw.Annotations[AnnotationPropagatedLabels] != ""
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestParseGatewayLabelKeys(t *testing.T) {
+	keys, err := ParseGatewayLabelKeys(" team, example.com/tier ,")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team", "example.com/tier"}, keys)
+
+	_, err = ParseGatewayLabelKeys("team,example.com/team")
+	assert.EqualError(t, err, `label keys "team" and "example.com/team" have the same name`)
+
+	_, err = ParseGatewayLabelKeys("not a key")
+	assert.Error(t, err)
+}
+
+func TestSyncGatewayLabels_CountsAndRemoves(t *testing.T) {
+	other := assignedGHR("a", "a.example.com")
+	other.Labels = map[string]string{"team": "payments", "tier": "gold"}
+	current := assignedGHR("b", "b.example.com")
+	current.Labels = map[string]string{"team": "payments", "tier": "silver"}
+	elsewhere := assignedGHR("c", "c.example.com")
+	elsewhere.Labels = map[string]string{"team": "search"}
+	elsewhere.Status.AssignedGateway = "gw-02"
+
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(other, elsewhere).Build()
+	r := &GatewayHostnameRequestReconciler{Client: c, GatewayLabelKeys: []string{"team", "tier"}}
+
+	gw := &gwapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{
+		Name: "gw-01", Namespace: "edge", Labels: map[string]string{"owner": "platform"},
+	}}
+
+	changed, err := r.syncGatewayLabels(context.Background(), gw, current, true)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, map[string]string{
+		"owner":                            "platform",
+		"gateway.opendi.com/team.payments": "2",
+		"gateway.opendi.com/tier.gold":     "1",
+		"gateway.opendi.com/tier.silver":   "1",
+	}, gw.Labels)
+	assert.Equal(t, "gateway.opendi.com/team.payments,gateway.opendi.com/tier.gold,gateway.opendi.com/tier.silver",
+		gw.Annotations[AnnotationPropagatedLabels])
+
+	// Second pass is a no-op
+	changed, err = r.syncGatewayLabels(context.Background(), gw, current, true)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// Dropping self lowers the count and removes labels only it contributed
+	changed, err = r.syncGatewayLabels(context.Background(), gw, current, false)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, map[string]string{
+		"owner":                            "platform",
+		"gateway.opendi.com/team.payments": "1",
+		"gateway.opendi.com/tier.gold":     "1",
+	}, gw.Labels)
+
+	// Turning propagation off removes the labels and the annotation
+	r.GatewayLabelKeys = nil
+	assert.True(t, r.propagatesGatewayLabels(gw))
+	changed, err = r.syncGatewayLabels(context.Background(), gw, current, true)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, map[string]string{"owner": "platform"}, gw.Labels)
+	assert.NotContains(t, gw.Annotations, AnnotationPropagatedLabels)
+	assert.False(t, r.propagatesGatewayLabels(gw))
+}
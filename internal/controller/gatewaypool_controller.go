@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+// GatewayPoolReconciler maintains the GatewayPool summary object for the Gateway pool.
+// The object is cluster-scoped and named after the default pool namespace.
+type GatewayPoolReconciler struct {
+	client.Client
+	Scheme      *runtime.Scheme
+	GatewayPool *gateway.Pool
+
+	// MaxRoutesPerNamespace is reported in the summary; the HTTPRoute webhook enforces it
+	MaxRoutesPerNamespace int
+}
+
+//+kubebuilder:rbac:groups=gateway.opendi.com,resources=gatewaypools,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=gateway.opendi.com,resources=gatewaypools/status,verbs=get;update;patch
+
+// Reconcile rebuilds the pool summary from the Gateways and the requests assigned to them
+func (r *GatewayPoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if req.Name != r.GatewayPool.Namespace() {
+		return ctrl.Result{}, nil
+	}
+
+	status, err := r.buildStatus(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var pool gatewayv1alpha1.GatewayPool
+	if err := r.Get(ctx, types.NamespacedName{Name: req.Name}, &pool); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("failed to get GatewayPool: %w", err)
+		}
+		pool = gatewayv1alpha1.GatewayPool{ObjectMeta: metav1.ObjectMeta{Name: req.Name}}
+		if err := r.Create(ctx, &pool); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to create GatewayPool: %w", err)
+		}
+		logger.Info("Created GatewayPool summary", "name", req.Name)
+	}
+
+	// Only write when the summary changed, so LastUpdated tracks real changes
+	status.LastUpdated = pool.Status.LastUpdated
+	if equality.Semantic.DeepEqual(pool.Status, *status) {
+		return ctrl.Result{}, nil
+	}
+	now := metav1.Now()
+	status.LastUpdated = &now
+	pool.Status = *status
+	if err := r.Status().Update(ctx, &pool); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update GatewayPool status: %w", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// buildStatus summarizes every Gateway of the pool
+func (r *GatewayPoolReconciler) buildStatus(ctx context.Context) (*gatewayv1alpha1.GatewayPoolStatus, error) {
+	gateways, err := r.GatewayPool.ListGateways(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList); err != nil {
+		return nil, fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+	hostnames := make(map[types.NamespacedName][]string)
+	for _, ghr := range ghrList.Items {
+		if !ghr.DeletionTimestamp.IsZero() || ghr.Status.AssignedGateway == "" {
+			continue
+		}
+		key := types.NamespacedName{Name: ghr.Status.AssignedGateway, Namespace: ghr.Status.AssignedGatewayNamespace}
+		hostnames[key] = append(hostnames[key], ghr.Spec.Hostname)
+	}
+
+	var routes gwapiv1.HTTPRouteList
+	if err := r.List(ctx, &routes); err != nil {
+		return nil, fmt.Errorf("failed to list HTTPRoutes: %w", err)
+	}
+	routeCounts := gateway.CountRoutes(routes.Items)
+
+	status := &gatewayv1alpha1.GatewayPoolStatus{
+		GatewayNamespace:          r.GatewayPool.Namespace(),
+		GatewayClassName:          r.GatewayPool.GatewayClass(),
+		MaxCertificatesPerGateway: r.GatewayPool.MaxCertificates(),
+		MaxRoutesPerNamespace:     r.MaxRoutesPerNamespace,
+		GatewayCount:              len(gateways),
+	}
+	for i := range gateways {
+		gw := &gateways[i]
+		info := r.GatewayPool.Info(gw)
+		names := hostnames[types.NamespacedName{Name: gw.Name, Namespace: gw.Namespace}]
+		sort.Strings(names)
+
+		status.HostnameCount += len(names)
+		status.Gateways = append(status.Gateways, gatewayv1alpha1.PoolGatewayStatus{
+			Name:              gw.Name,
+			Namespace:         gw.Namespace,
+			Visibility:        gw.Annotations[AnnotationVisibility],
+			WafArn:            gw.Annotations["gateway.opendi.com/waf-arn"],
+			Hostnames:         names,
+			CertificateCount:  info.CertificateCount,
+			RuleCount:         info.RuleCount,
+			RoutesByNamespace: routeCounts[types.NamespacedName{Name: gw.Name, Namespace: gw.Namespace}],
+			LoadBalancerDNS:   info.LoadBalancerDNS,
+			Cordoned:          gw.Annotations[gateway.AnnotationCordoned],
+			Health:            gatewayHealth(gw),
+		})
+	}
+	sort.Slice(status.Gateways, func(i, j int) bool {
+		return status.Gateways[i].Name < status.Gateways[j].Name
+	})
+	return status, nil
+}
+
+// gatewayHealth maps the Gateway's Programmed condition to a health value
+func gatewayHealth(gw *gwapiv1.Gateway) string {
+	cond := meta.FindStatusCondition(gw.Status.Conditions, string(gwapiv1.GatewayConditionProgrammed))
+	switch {
+	case cond == nil || cond.Status == metav1.ConditionUnknown:
+		return gatewayv1alpha1.GatewayHealthPending
+	case cond.Status == metav1.ConditionTrue:
+		return gatewayv1alpha1.GatewayHealthHealthy
+	default:
+		return gatewayv1alpha1.GatewayHealthUnhealthy
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Changes to Gateways, requests and HTTPRoutes all map to the single pool summary object.
+func (r *GatewayPoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	toPool := handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: r.GatewayPool.Namespace()}}}
+	})
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&gatewayv1alpha1.GatewayPool{}).
+		Watches(&gwapiv1.Gateway{}, toPool).
+		Watches(&gatewayv1alpha1.GatewayHostnameRequest{}, toPool).
+		Watches(&gwapiv1.HTTPRoute{}, toPool).
+		Complete(r)
+}
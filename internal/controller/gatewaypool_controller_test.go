@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+func TestGatewayPoolReconciler_SummarizesGateways(t *testing.T) {
+	hostnameType := gwapiv1.HostnameAddressType
+	programmed := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gw-01",
+			Namespace: "edge",
+			Annotations: map[string]string{
+				AnnotationVisibility:       "internet-facing",
+				AnnotationCertificateCount: "2",
+				gateway.AnnotationCordoned: gateway.CordonReasonOverCapacity,
+			},
+		},
+		Spec: gwapiv1.GatewaySpec{GatewayClassName: "aws-alb"},
+		Status: gwapiv1.GatewayStatus{
+			Addresses:  []gwapiv1.GatewayStatusAddress{{Type: &hostnameType, Value: "gw-01.elb.amazonaws.com"}},
+			Conditions: []metav1.Condition{{Type: "Programmed", Status: metav1.ConditionTrue}},
+		},
+	}
+	pending := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw-02", Namespace: "edge"},
+		Spec:       gwapiv1.GatewaySpec{GatewayClassName: "aws-alb"},
+	}
+	otherClass := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "istio", Namespace: "edge"},
+		Spec:       gwapiv1.GatewaySpec{GatewayClassName: "istio"},
+	}
+	edge := gwapiv1.Namespace("edge")
+	route := func(namespace, name string) *gwapiv1.HTTPRoute {
+		return &gwapiv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: gwapiv1.HTTPRouteSpec{CommonRouteSpec: gwapiv1.CommonRouteSpec{
+				ParentRefs: []gwapiv1.ParentReference{{Name: "gw-01", Namespace: &edge}},
+			}},
+		}
+	}
+
+	c := fake.NewClientBuilder().
+		WithScheme(getTestScheme()).
+		WithStatusSubresource(&gatewayv1alpha1.GatewayPool{}).
+		WithObjects(programmed, pending, otherClass,
+			assignedGHR("b", "b.example.com"), assignedGHR("a", "a.example.com"),
+			route("team-a", "a1"), route("team-a", "a2"), route("team-b", "b1")).
+		Build()
+
+	r := &GatewayPoolReconciler{Client: c, GatewayPool: gateway.NewPool(c, "edge", "aws-alb", 0, 0), MaxRoutesPerNamespace: 50}
+
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "edge"}})
+	require.NoError(t, err)
+
+	var pool gatewayv1alpha1.GatewayPool
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "edge"}, &pool))
+
+	assert.Equal(t, 2, pool.Status.GatewayCount)
+	assert.Equal(t, 2, pool.Status.HostnameCount)
+	assert.Equal(t, 50, pool.Status.MaxRoutesPerNamespace)
+	require.Len(t, pool.Status.Gateways, 2)
+
+	gw01 := pool.Status.Gateways[0]
+	assert.Equal(t, "gw-01", gw01.Name)
+	assert.Equal(t, []string{"a.example.com", "b.example.com"}, gw01.Hostnames)
+	assert.Equal(t, 2, gw01.CertificateCount)
+	assert.Equal(t, "gw-01.elb.amazonaws.com", gw01.LoadBalancerDNS)
+	assert.Equal(t, gateway.CordonReasonOverCapacity, gw01.Cordoned)
+	assert.Equal(t, gatewayv1alpha1.GatewayHealthHealthy, gw01.Health)
+	assert.Equal(t, map[string]int{"team-a": 2, "team-b": 1}, gw01.RoutesByNamespace)
+
+	assert.Equal(t, gatewayv1alpha1.GatewayHealthPending, pool.Status.Gateways[1].Health)
+	require.NotNil(t, pool.Status.LastUpdated)
+
+	// Unchanged pool is not rewritten
+	resourceVersion := pool.ResourceVersion
+	_, err = r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "edge"}})
+	require.NoError(t, err)
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "edge"}, &pool))
+	assert.Equal(t, resourceVersion, pool.ResourceVersion)
+}
@@ -0,0 +1,35 @@
+package controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// MaxHistoryEntries bounds status.history; the oldest entries are dropped first.
+// Must not exceed the MaxItems validation on the CRD field.
+const MaxHistoryEntries = 20
+
+// recordHistory appends a transition to status.history, dropping the oldest entries
+// once MaxHistoryEntries is reached. Consecutive duplicates (same type, status and reason)
+// are collapsed so a request stuck in a polling loop doesn't flush useful history.
+func recordHistory(ghr *gatewayv1alpha1.GatewayHostnameRequest, entryType string, status metav1.ConditionStatus, reason, message string) {
+	if n := len(ghr.Status.History); n > 0 {
+		last := ghr.Status.History[n-1]
+		if last.Type == entryType && last.Status == status && last.Reason == reason {
+			return
+		}
+	}
+
+	ghr.Status.History = append(ghr.Status.History, gatewayv1alpha1.HistoryEntry{
+		Time:    metav1.Now(),
+		Type:    entryType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+
+	if overflow := len(ghr.Status.History) - MaxHistoryEntries; overflow > 0 {
+		ghr.Status.History = append([]gatewayv1alpha1.HistoryEntry(nil), ghr.Status.History[overflow:]...)
+	}
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+func TestSetCondition_RecordsTransitionsInHistory(t *testing.T) {
+	r := &GatewayHostnameRequestReconciler{}
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{}
+
+	r.setCondition(ghr, ConditionTypeCertificateIssued, metav1.ConditionFalse, "PendingIssuance", "Waiting")
+	// Same status and reason with a different message is not a transition
+	r.setCondition(ghr, ConditionTypeCertificateIssued, metav1.ConditionFalse, "PendingIssuance", "Still waiting")
+	r.setCondition(ghr, ConditionTypeCertificateIssued, metav1.ConditionTrue, "Issued", "Certificate issued by ACM")
+
+	assert.Len(t, ghr.Status.History, 2)
+	assert.Equal(t, "PendingIssuance", ghr.Status.History[0].Reason)
+	assert.Equal(t, ConditionTypeCertificateIssued, ghr.Status.History[1].Type)
+	assert.Equal(t, metav1.ConditionTrue, ghr.Status.History[1].Status)
+	assert.Equal(t, "Issued", ghr.Status.History[1].Reason)
+}
+
+func TestRecordHistory_BoundedOldestFirst(t *testing.T) {
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{}
+
+	for i := 0; i < MaxHistoryEntries+5; i++ {
+		recordHistory(ghr, "Ready", metav1.ConditionFalse, fmt.Sprintf("Reason%d", i), "")
+	}
+
+	assert.Len(t, ghr.Status.History, MaxHistoryEntries)
+	assert.Equal(t, "Reason5", ghr.Status.History[0].Reason)
+	assert.Equal(t, fmt.Sprintf("Reason%d", MaxHistoryEntries+4), ghr.Status.History[MaxHistoryEntries-1].Reason)
+}
+
+func TestRecordHistory_CollapsesConsecutiveDuplicates(t *testing.T) {
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{}
+
+	recordHistory(ghr, "DriftDetected", "", "ResourcesMissing", "first")
+	recordHistory(ghr, "DriftDetected", "", "ResourcesMissing", "second")
+
+	assert.Len(t, ghr.Status.History, 1)
+	assert.Equal(t, "first", ghr.Status.History[0].Message)
+}
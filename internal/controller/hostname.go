@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DNS limits of RFC 1035
+const (
+	maxHostnameLength = 253
+	maxLabelLength    = 63
+)
+
+// ValidateHostname checks the DNS rules the CRD pattern can't express: at most 253 characters,
+// labels of 1 to 63 letters, digits and hyphens that don't start or end with a hyphen, and a
+// wildcard only as the whole first label. The hostname is checked as CanonicalHostname spells it.
+func ValidateHostname(hostname string) error {
+	name := CanonicalHostname(hostname)
+	if name == "" {
+		return fmt.Errorf("hostname is empty")
+	}
+	if len(name) > maxHostnameLength {
+		return fmt.Errorf("hostname %s is %d characters long, DNS allows at most %d", hostname, len(name), maxHostnameLength)
+	}
+	labels := strings.Split(name, ".")
+	if len(labels) < 2 {
+		return fmt.Errorf("hostname %s must have at least two labels", hostname)
+	}
+	for i, label := range labels {
+		if label == "*" && i == 0 {
+			continue
+		}
+		if err := validateLabel(label); err != nil {
+			return fmt.Errorf("hostname %s: %w", hostname, err)
+		}
+	}
+	return nil
+}
+
+// validateLabel checks a single DNS label
+func validateLabel(label string) error {
+	switch {
+	case label == "":
+		return fmt.Errorf("empty label")
+	case len(label) > maxLabelLength:
+		return fmt.Errorf("label %s is %d characters long, DNS allows at most %d", label, len(label), maxLabelLength)
+	case strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-"):
+		return fmt.Errorf("label %s must not start or end with a hyphen", label)
+	}
+	for _, c := range label {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return fmt.Errorf("label %s contains %q; only letters, digits and hyphens are allowed", label, c)
+		}
+	}
+	return nil
+}
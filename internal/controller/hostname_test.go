@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateHostname(t *testing.T) {
+	label63 := strings.Repeat("a", 63)
+	for hostname, wantErr := range map[string]bool{
+		"api.example.com":                      false,
+		"*.example.com":                        false,
+		"App.Example.com.":                     false,
+		label63 + ".example.com":               false,
+		label63 + "a.example.com":              true,
+		strings.Repeat(label63+".", 4) + "com": true,
+		strings.Repeat(label63+".", 3) + strings.Repeat("b", 57) + ".com": false,
+		"-api.example.com":   true,
+		"api-.example.com":   true,
+		"api..example.com":   true,
+		"api.*.example.com":  true,
+		"api_v2.example.com": true,
+		"localhost":          true,
+		"":                   true,
+	} {
+		err := ValidateHostname(hostname)
+		assert.Equal(t, wantErr, err != nil, "%q: %v", hostname, err)
+	}
+}
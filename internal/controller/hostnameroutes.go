@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+// hostnameRoutes returns the HTTPRoutes in the request namespace that match the hostname, by
+// name. Routes without hostnames match every hostname, so they only count when they reference
+// a pool Gateway.
+func (r *GatewayHostnameRequestReconciler) hostnameRoutes(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) ([]gatewayv1alpha1.HostnameRoute, error) {
+	var routes gwapiv1.HTTPRouteList
+	if err := r.List(ctx, &routes, client.InNamespace(ghr.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list HTTPRoutes: %w", err)
+	}
+
+	var matching []gatewayv1alpha1.HostnameRoute
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		if !route.DeletionTimestamp.IsZero() || !routeServesHostname(route, ghr.Spec.Hostname) {
+			continue
+		}
+		var gateways []string
+		pool := false
+		for _, parent := range gateway.RouteParents(route) {
+			gateways = append(gateways, parent.String())
+			pool = pool || r.GatewayPool.IsPoolNamespace(parent.Namespace)
+		}
+		if len(route.Spec.Hostnames) == 0 && !pool {
+			continue
+		}
+		matching = append(matching, gatewayv1alpha1.HostnameRoute{
+			Name:     route.Name,
+			Gateways: gateways,
+			Attached: ghr.Status.AssignedGateway != "" && routeAttachesTo(route, ghr.Status.AssignedGateway, ghr.Status.AssignedGatewayNamespace),
+		})
+	}
+	sort.Slice(matching, func(i, j int) bool { return matching[i].Name < matching[j].Name })
+	return matching, nil
+}
+
+// detachedRoutes returns the names of the request's routes that don't reference its Gateway
+func detachedRoutes(ghr *gatewayv1alpha1.GatewayHostnameRequest) string {
+	var names []string
+	for _, route := range ghr.Status.Routes {
+		if !route.Attached {
+			names = append(names, route.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+func TestHostnameRoutes(t *testing.T) {
+	ghr := assignedGHR("app", "app.example.com")
+	ghr.Status.AssignedGateway = "gw-02"
+
+	moved := backendRoute("app", "app.example.com", "app")
+	catchAll := backendRoute("catch-all", "", "web")
+	catchAll.Spec.Hostnames = nil
+	catchAll.Spec.ParentRefs[0].Name = "gw-02"
+	mesh := backendRoute("mesh", "", "web")
+	mesh.Spec.Hostnames = nil
+	meshNamespace := gwapiv1.Namespace("mesh")
+	mesh.Spec.ParentRefs[0].Namespace = &meshNamespace
+	other := backendRoute("other", "other.example.com", "other")
+
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr, moved, catchAll, mesh, other).Build()
+	r := &GatewayHostnameRequestReconciler{Client: c, GatewayPool: gateway.NewPool(c, "edge", "aws-alb", 0, 0)}
+
+	routes, err := r.hostnameRoutes(context.Background(), ghr)
+	require.NoError(t, err)
+	assert.Equal(t, []gatewayv1alpha1.HostnameRoute{
+		{Name: "app", Gateways: []string{"edge/gw-01"}},
+		{Name: "catch-all", Gateways: []string{"edge/gw-02"}, Attached: true},
+	}, routes, "routes without hostnames only count on pool Gateways")
+
+	ghr.Status.Routes = routes
+	conditions.Set(&ghr.Status.Conditions, ConditionTypeReady, metav1.ConditionTrue, conditions.ReasonReady, "Hostname request fully provisioned", 1)
+	assert.Equal(t, "Ready; HTTPRoutes app don't reference Gateway gw-02", explainStatus(ghr))
+}
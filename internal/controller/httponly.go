@@ -0,0 +1,18 @@
+package controller
+
+import (
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// Values of spec.tls
+const (
+	TLSEnabled  = "enabled"
+	TLSDisabled = "disabled"
+)
+
+// isHTTPOnly reports whether the request is served over plain HTTP without a certificate.
+// The certificate steps are skipped; the hostname is assigned to a Gateway and aliased as usual,
+// but gets no dedicated HTTPS listener and doesn't count against the certificate limit.
+func isHTTPOnly(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	return ghr.Spec.TLS == TLSDisabled
+}
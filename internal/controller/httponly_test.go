@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+func TestReconcileNormal_HTTPOnlySkipsCertificate(t *testing.T) {
+	ctx := context.Background()
+	hostnameType := gwapiv1.HostnameAddressType
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "gw-01", Namespace: "edge",
+			Annotations: map[string]string{AnnotationVisibility: "internet-facing"},
+		},
+		Spec: gwapiv1.GatewaySpec{GatewayClassName: "aws-alb"},
+		Status: gwapiv1.GatewayStatus{
+			Addresses: []gwapiv1.GatewayStatusAddress{
+				{Type: &hostnameType, Value: "k8s-gw01-abcdef1234-1234567890.us-east-1.elb.amazonaws.com"},
+			},
+			Conditions: []metav1.Condition{{Type: string(gwapiv1.GatewayConditionProgrammed), Status: metav1.ConditionTrue}},
+		},
+	}
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "redirect", Namespace: "default"},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+			Hostname: "old-brand.example.com",
+			ZoneId:   "Z123456",
+			TLS:      TLSDisabled,
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(gw, ghr).WithStatusSubresource(ghr).Build()
+	acmMock := &MockACMClient{certificates: make(map[string]string)}
+	route53Mock := &MockRoute53Client{records: make(map[string][]aws.DNSRecord)}
+	r := &GatewayHostnameRequestReconciler{
+		Client:        c,
+		Scheme:        getTestScheme(),
+		Recorder:      record.NewFakeRecorder(50),
+		ACMClient:     acmMock,
+		Route53Client: route53Mock,
+		GatewayPool:   gateway.NewPool(c, "edge", "aws-alb", 0, 0),
+		ListenerMode:  ListenerModeHostname,
+	}
+
+	_, err := r.reconcileNormal(ctx, ghr)
+	require.NoError(t, err)
+	assert.Empty(t, acmMock.certificates)
+	assert.Empty(t, ghr.Status.CertificateArn)
+	assert.Equal(t, "gw-01", ghr.Status.AssignedGateway)
+	assert.True(t, meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeDnsAliasReady))
+	assert.True(t, meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeReady))
+	require.Len(t, route53Mock.records["Z123456"], 1)
+	assert.Equal(t, "old-brand.example.com", route53Mock.records["Z123456"][0].Name)
+
+	// No dedicated HTTPS listener for a hostname without certificate
+	var updated gwapiv1.Gateway
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(gw), &updated))
+	assert.Empty(t, listenerNames(&updated))
+}
+
+func TestComputeSpecHash_HTTPOnlyChangesHash(t *testing.T) {
+	spec := gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "app.opendi.com", ZoneId: "Z123456"}
+	httpsHash := computeSpecHash(&spec)
+
+	spec.TLS = TLSEnabled
+	assert.Equal(t, httpsHash, computeSpecHash(&spec))
+
+	spec.TLS = TLSDisabled
+	assert.NotEqual(t, httpsHash, computeSpecHash(&spec))
+}
+
+func TestHTTPSOnly_LoadBalancerConfigurationOmitsHTTP(t *testing.T) {
+	ctx := context.Background()
+	legacy := &gwapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gw-01", Namespace: "edge"}}
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(legacy).Build()
+	pool := gateway.NewPool(c, "edge", "aws-alb", 0, 0)
+	pool.SetHTTPSOnly(true)
+	r := &GatewayHostnameRequestReconciler{Client: c, GatewayPool: pool}
+
+	protocolPorts := func(gatewayName string) []string {
+		lbc := r.newLoadBalancerConfiguration()
+		require.NoError(t, c.Get(ctx, client.ObjectKey{Name: gatewayName + "-config", Namespace: "edge"}, lbc))
+		listeners, _, _ := unstructured.NestedSlice(lbc.Object, "spec", "listenerConfigurations")
+		var ports []string
+		for _, l := range listeners {
+			ports = append(ports, l.(map[string]interface{})["protocolPort"].(string))
+		}
+		return ports
+	}
+
+	// Gateways created before keep their HTTP listener; new ones follow the pool
+	require.NoError(t, r.ensureLoadBalancerConfiguration(ctx, "gw-01", "edge", []string{"arn:cert"}, "internet-facing", ""))
+	assert.Equal(t, []string{"HTTPS:443", "HTTP:80"}, protocolPorts("gw-01"))
+	require.NoError(t, r.ensureLoadBalancerConfiguration(ctx, "gw-02", "edge", []string{"arn:cert"}, "internet-facing", ""))
+	assert.Equal(t, []string{"HTTPS:443"}, protocolPorts("gw-02"))
+
+	// A Gateway marked HTTPS-only drops it even if the pool no longer is
+	pool.SetHTTPSOnly(false)
+	_, err := pool.CreateGatewayWithMetadata(ctx, "internet-facing", "", 3, nil, map[string]string{gateway.AnnotationHTTPSOnly: "true"})
+	require.NoError(t, err)
+	require.NoError(t, r.ensureLoadBalancerConfiguration(ctx, "gw-03", "edge", []string{"arn:cert"}, "internet-facing", ""))
+	assert.Equal(t, []string{"HTTPS:443"}, protocolPorts("gw-03"))
+}
+
+func TestValidateRequest_HTTPOnlyOnHTTPSOnlyPool(t *testing.T) {
+	pool := gateway.NewPool(nil, "edge", "aws-alb", 0, 0)
+	pool.SetHTTPSOnly(true)
+	r := &GatewayHostnameRequestReconciler{GatewayPool: pool}
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "old-brand.example.com", ZoneId: "Z123456", TLS: TLSDisabled},
+	}
+
+	assert.ErrorContains(t, r.validateRequest(ghr), "HTTPS-only")
+
+	// A selector may pick a Gateway that kept its HTTP listener
+	selected := ghr.DeepCopy()
+	selected.Spec.GatewaySelector = &metav1.LabelSelector{MatchLabels: map[string]string{"plain-http": "true"}}
+	assert.NoError(t, r.validateRequest(selected))
+
+	// Requests already served keep working
+	assigned := ghr.DeepCopy()
+	assigned.Status.AssignedGateway = "gw-01"
+	assert.NoError(t, r.validateRequest(assigned))
+
+	ghr.Spec.TLS = TLSEnabled
+	assert.NoError(t, r.validateRequest(ghr))
+}
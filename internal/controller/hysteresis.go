@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+// DefaultConditionFailureThreshold is how many times in a row a step of a provisioned request
+// may fail with transient AWS errors before its condition is set to False
+const DefaultConditionFailureThreshold = 3
+
+// conditionFailures counts the transient failures in a row of the steps whose True condition
+// was kept, by request UID and condition type. Counts are lost on restart, which only delays
+// a condition going False.
+type conditionFailures struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// add counts a failure and returns the failures in a row
+func (f *conditionFailures) add(key string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.counts == nil {
+		f.counts = map[string]int{}
+	}
+	f.counts[key]++
+	return f.counts[key]
+}
+
+// reset forgets the failures of a key
+func (f *conditionFailures) reset(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.counts, key)
+}
+
+// resetPrefix forgets the failures of all keys starting with the prefix
+func (f *conditionFailures) resetPrefix(prefix string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for key := range f.counts {
+		if strings.HasPrefix(key, prefix) {
+			delete(f.counts, key)
+		}
+	}
+}
+
+// conditionFailureKey identifies a condition of a request in conditionFailures
+func conditionFailureKey(ghr *gatewayv1alpha1.GatewayHostnameRequest, condType string) string {
+	return string(ghr.UID) + "/" + condType
+}
+
+// failCondition sets a condition to False after its step failed with err. A True condition is
+// kept while the step fails with transient AWS errors (throttling, timeouts, server errors),
+// until it failed ConditionFailureThreshold times in a row, so a single bad read doesn't flap
+// the status and the alerts on it. Other errors set it to False right away. Returns whether
+// the condition is False now; callers only report the failure then.
+func (r *GatewayHostnameRequestReconciler) failCondition(ghr *gatewayv1alpha1.GatewayHostnameRequest, condType string, reason conditions.Reason, err error) bool {
+	if conditions.IsTrue(ghr.Status.Conditions, condType) && aws.IsTransient(err) &&
+		r.conditionFailures.add(conditionFailureKey(ghr, condType)) < r.conditionFailureThreshold() {
+		return false
+	}
+	r.setCondition(ghr, condType, metav1.ConditionFalse, reason, err.Error())
+	return true
+}
+
+// conditionFailureThreshold returns ConditionFailureThreshold or its default
+func (r *GatewayHostnameRequestReconciler) conditionFailureThreshold() int {
+	if r.ConditionFailureThreshold <= 0 {
+		return DefaultConditionFailureThreshold
+	}
+	return r.ConditionFailureThreshold
+}
+
+// forgetConditionFailures drops the failure counts of a deleted request
+func (r *GatewayHostnameRequestReconciler) forgetConditionFailures(ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+	r.conditionFailures.resetPrefix(string(ghr.UID) + "/")
+}
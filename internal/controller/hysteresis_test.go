@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+func TestFailCondition_KeepsTrueConditionOnTransientErrors(t *testing.T) {
+	throttled := fmt.Errorf("failed to upsert record: %w", &smithy.GenericAPIError{Code: "Throttling", Fault: smithy.FaultClient})
+	r := &GatewayHostnameRequestReconciler{Recorder: record.NewFakeRecorder(10)}
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "uid-1"}}
+	r.setCondition(ghr, ConditionTypeDnsAliasReady, metav1.ConditionTrue, conditions.ReasonCreated, "Route53 ALIAS record created")
+
+	assert.False(t, r.failCondition(ghr, ConditionTypeDnsAliasReady, conditions.ReasonAliasFailed, throttled))
+	assert.False(t, r.failCondition(ghr, ConditionTypeDnsAliasReady, conditions.ReasonAliasFailed, throttled))
+	assert.True(t, conditions.IsTrue(ghr.Status.Conditions, ConditionTypeDnsAliasReady))
+
+	// A success in between starts the count again
+	r.setCondition(ghr, ConditionTypeDnsAliasReady, metav1.ConditionTrue, conditions.ReasonCreated, "Route53 ALIAS record created")
+	assert.False(t, r.failCondition(ghr, ConditionTypeDnsAliasReady, conditions.ReasonAliasFailed, throttled))
+	assert.False(t, r.failCondition(ghr, ConditionTypeDnsAliasReady, conditions.ReasonAliasFailed, throttled))
+	assert.True(t, r.failCondition(ghr, ConditionTypeDnsAliasReady, conditions.ReasonAliasFailed, throttled))
+	assert.True(t, conditions.HasReason(ghr.Status.Conditions, ConditionTypeDnsAliasReady, metav1.ConditionFalse, conditions.ReasonAliasFailed))
+}
+
+func TestFailCondition_SetsFalseRightAway(t *testing.T) {
+	r := &GatewayHostnameRequestReconciler{Recorder: record.NewFakeRecorder(10)}
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: "uid-1"}}
+
+	// Conditions that aren't True yet have nothing to protect
+	throttled := &smithy.GenericAPIError{Code: "Throttling", Fault: smithy.FaultClient}
+	assert.True(t, r.failCondition(ghr, ConditionTypeZoneDelegated, conditions.ReasonDelegationFailed, throttled))
+
+	// Errors that don't go away on their own aren't held back
+	r.setCondition(ghr, ConditionTypeDnsAliasReady, metav1.ConditionTrue, conditions.ReasonCreated, "Route53 ALIAS record created")
+	assert.True(t, r.failCondition(ghr, ConditionTypeDnsAliasReady, conditions.ReasonAliasFailed, errors.New("InvalidChangeBatch")))
+	assert.True(t, conditions.IsFalse(ghr.Status.Conditions, ConditionTypeDnsAliasReady))
+}
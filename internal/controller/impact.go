@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+const (
+	// AnnotationConfirmGatewayChange confirms a shared Gateway change requested by a
+	// GatewayHostnameRequest. The value must be the name of the assigned Gateway.
+	AnnotationConfirmGatewayChange = "gateway.opendi.com/confirm-gateway-change"
+
+	// maxListedHostnames bounds how many affected hostnames are listed in events and conditions
+	maxListedHostnames = 10
+)
+
+// gatewaySettingsChanges describes the shared ALB settings that applying the request's WAF
+// would change on the Gateway. Visibility is never changed for a request (see gatewayVisibility).
+func gatewaySettingsChanges(gw *gwapiv1.Gateway, wafArn string) []string {
+	var changes []string
+	if current := gw.Annotations["gateway.opendi.com/waf-arn"]; current != wafArn {
+		changes = append(changes, fmt.Sprintf("WAF %q -> %q", current, wafArn))
+	}
+	return changes
+}
+
+// sharedHostnames returns the sorted hostnames of other requests assigned to the Gateway
+func (r *GatewayHostnameRequestReconciler) sharedHostnames(ctx context.Context, gw *gwapiv1.Gateway, self *gatewayv1alpha1.GatewayHostnameRequest) ([]string, error) {
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList); err != nil {
+		return nil, fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+
+	var hostnames []string
+	for _, ghr := range ghrList.Items {
+		if ghr.Namespace == self.Namespace && ghr.Name == self.Name {
+			continue
+		}
+		if !ghr.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if ghr.Status.AssignedGateway == gw.Name && ghr.Status.AssignedGatewayNamespace == gw.Namespace {
+			hostnames = append(hostnames, ghr.Spec.Hostname)
+		}
+	}
+	sort.Strings(hostnames)
+	return hostnames, nil
+}
+
+// checkGatewayChangeImpact reports which other hostnames share the ALB before the request
+// rewrites shared Gateway settings. Changes affecting more than ImpactConfirmationThreshold
+// hostnames are held back until the request carries AnnotationConfirmGatewayChange.
+// Returns false if the change must not be applied yet.
+func (r *GatewayHostnameRequestReconciler) checkGatewayChangeImpact(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, gw *gwapiv1.Gateway, wafArn string) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	changes := gatewaySettingsChanges(gw, wafArn)
+	if len(changes) == 0 {
+		meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeGatewayChangePending)
+		return true, nil
+	}
+
+	affected, err := r.sharedHostnames(ctx, gw, ghr)
+	if err != nil {
+		return false, err
+	}
+
+	summary := fmt.Sprintf("Changing %s on Gateway %s affects %d other hostname(s)%s",
+		strings.Join(changes, ", "), gw.Name, len(affected), formatHostnames(affected))
+
+	if r.ImpactConfirmationThreshold > 0 && len(affected) > r.ImpactConfirmationThreshold &&
+		ghr.Annotations[AnnotationConfirmGatewayChange] != gw.Name {
+		logger.Info("Shared Gateway change requires confirmation", "gateway", gw.Name,
+			"changes", changes, "affectedHostnames", affected)
+		r.setCondition(ghr, ConditionTypeGatewayChangePending, metav1.ConditionTrue, conditions.ReasonConfirmationRequired,
+			fmt.Sprintf("%s; annotate the request with %s=%s to apply", summary, AnnotationConfirmGatewayChange, gw.Name))
+		r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "GatewayChangePending", "%s", summary)
+		return false, nil
+	}
+
+	logger.Info("Applying shared Gateway change", "gateway", gw.Name, "changes", changes, "affectedHostnames", affected)
+	r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "SharedGatewayChange", "%s", summary)
+	r.Recorder.Eventf(gw, corev1.EventTypeWarning, "SharedGatewayChange", "%s (requested by %s/%s)", summary, ghr.Namespace, ghr.Name)
+	meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeGatewayChangePending)
+	return true, nil
+}
+
+// formatHostnames renders a bounded hostname list for events and conditions
+func formatHostnames(hostnames []string) string {
+	if len(hostnames) == 0 {
+		return ""
+	}
+	if len(hostnames) > maxListedHostnames {
+		return fmt.Sprintf(": %s and %d more", strings.Join(hostnames[:maxListedHostnames], ", "), len(hostnames)-maxListedHostnames)
+	}
+	return ": " + strings.Join(hostnames, ", ")
+}
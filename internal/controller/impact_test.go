@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestCheckGatewayChangeImpact_RequiresConfirmationAboveThreshold(t *testing.T) {
+	self := assignedGHR("self", "self.example.com")
+	c := fake.NewClientBuilder().
+		WithScheme(getTestScheme()).
+		WithObjects(self, assignedGHR("a", "a.example.com"), assignedGHR("b", "b.example.com")).
+		Build()
+
+	r := &GatewayHostnameRequestReconciler{
+		Client:                      c,
+		Recorder:                    record.NewFakeRecorder(10),
+		ImpactConfirmationThreshold: 1,
+	}
+
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gw-01",
+			Namespace: "edge",
+			Annotations: map[string]string{
+				AnnotationVisibility:         "internet-facing",
+				"gateway.opendi.com/waf-arn": "",
+			},
+		},
+	}
+
+	// No shared setting changes
+	allowed, err := r.checkGatewayChangeImpact(context.Background(), self, gw, "")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	// Two other hostnames share the ALB, above the threshold of one
+	allowed, err = r.checkGatewayChangeImpact(context.Background(), self, gw, "arn:aws:wafv2:eu-west-1:123456789012:regional/webacl/strict/1")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	cond := meta.FindStatusCondition(self.Status.Conditions, ConditionTypeGatewayChangePending)
+	require.NotNil(t, cond)
+	assert.Contains(t, cond.Message, "a.example.com, b.example.com")
+
+	// Confirmed for this Gateway
+	self.Annotations = map[string]string{AnnotationConfirmGatewayChange: "gw-01"}
+	allowed, err = r.checkGatewayChangeImpact(context.Background(), self, gw, "arn:aws:wafv2:eu-west-1:123456789012:regional/webacl/strict/1")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Nil(t, meta.FindStatusCondition(self.Status.Conditions, ConditionTypeGatewayChangePending))
+}
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DefaultInternalDomainSuffixes are the cluster-internal domains requests may never use:
+// they can't get public certificates and don't belong in public DNS
+var DefaultInternalDomainSuffixes = []string{"cluster.local", "svc"}
+
+// ParseInternalDomainSuffixes parses a comma-separated list of internal domain suffixes,
+// e.g. "cluster.local,svc,corp.internal". The result always contains the defaults.
+func ParseInternalDomainSuffixes(s string) ([]string, error) {
+	suffixes := append([]string(nil), DefaultInternalDomainSuffixes...)
+	for _, suffix := range strings.Split(s, ",") {
+		suffix = strings.Trim(strings.ToLower(strings.TrimSpace(suffix)), ".")
+		if suffix == "" {
+			continue
+		}
+		if strings.ContainsAny(suffix, "* ") {
+			return nil, fmt.Errorf("invalid internal domain suffix %q", suffix)
+		}
+		if !slices.Contains(suffixes, suffix) {
+			suffixes = append(suffixes, suffix)
+		}
+	}
+	return suffixes, nil
+}
+
+// InternalDomainSuffix returns the suffix the hostname (or the domain a wildcard covers)
+// falls under, if any
+func InternalDomainSuffix(hostname string, suffixes []string) (string, bool) {
+	hostname = strings.TrimSuffix(strings.TrimPrefix(strings.ToLower(hostname), "*."), ".")
+	for _, suffix := range suffixes {
+		if hostname == suffix || strings.HasSuffix(hostname, "."+suffix) {
+			return suffix, true
+		}
+	}
+	return "", false
+}
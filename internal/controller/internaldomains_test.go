@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+func TestParseInternalDomainSuffixes(t *testing.T) {
+	suffixes, err := ParseInternalDomainSuffixes(" Corp.Internal. ,svc,")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cluster.local", "svc", "corp.internal"}, suffixes)
+
+	_, err = ParseInternalDomainSuffixes("*.internal")
+	assert.Error(t, err)
+}
+
+func TestInternalDomainSuffix(t *testing.T) {
+	suffixes := []string{"cluster.local", "svc", "corp.internal"}
+	for hostname, want := range map[string]string{
+		"api.default.svc":               "svc",
+		"api.default.svc.cluster.local": "cluster.local",
+		"*.corp.internal":               "corp.internal",
+		"corp.internal":                 "corp.internal",
+		"api.example.com":               "",
+		"svc.example.com":               "",
+		"mycorp.internal":               "",
+	} {
+		suffix, internal := InternalDomainSuffix(hostname, suffixes)
+		assert.Equal(t, want != "", internal, hostname)
+		assert.Equal(t, want, suffix, hostname)
+	}
+}
+
+func TestValidateRequest_InternalDomain(t *testing.T) {
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "api.default.svc", ZoneId: "Z123"},
+	}
+	r := &GatewayHostnameRequestReconciler{}
+	assert.EqualError(t, r.validateRequest(ghr),
+		"hostname api.default.svc is in the cluster-internal domain svc and can't get public DNS or certificates")
+
+	ghr.Spec.Hostname = "api.corp.internal"
+	assert.NoError(t, r.validateRequest(ghr))
+	r.InternalDomainSuffixes = []string{"cluster.local", "svc", "corp.internal"}
+	assert.Error(t, r.validateRequest(ghr))
+}
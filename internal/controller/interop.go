@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// DefaultForeignOwnerTags mark AWS resources managed by infrastructure-as-code tools:
+// Crossplane tags every resource with crossplane-kind; Terraform setups commonly tag
+// managed-by=terraform through default_tags
+const DefaultForeignOwnerTags = "crossplane-kind,managed-by=terraform,ManagedBy=terraform"
+
+// ParseInteropTags parses comma-separated key=value tags added to the AWS resources the
+// orchestrator creates, e.g. "iac-ignore=true,owner=platform"
+func ParseInteropTags(s string) (map[string]string, error) {
+	tags := map[string]string{}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid tag %q, expected <key>=<value>", entry)
+		}
+		if strings.HasPrefix(key, "aws:") {
+			return nil, fmt.Errorf("tag key %q is reserved", key)
+		}
+		value = strings.TrimSpace(value)
+		if kind := secretKind(value); kind != "" {
+			return nil, fmt.Errorf("tag %q looks like %s; tags are readable by anyone who can list the resources", key, kind)
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
+
+// ForeignOwnerTag is a tag marking a resource as owned by another tool. An empty Value
+// matches any value.
+type ForeignOwnerTag struct {
+	Key   string
+	Value string
+}
+
+// ForeignOwnerTags recognize AWS resources owned by other tools, which the orchestrator
+// must not delete
+type ForeignOwnerTags []ForeignOwnerTag
+
+// ParseForeignOwnerTags parses a comma-separated list of <key> or <key>=<value> entries
+func ParseForeignOwnerTags(s string) (ForeignOwnerTags, error) {
+	var tags ForeignOwnerTags
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, fmt.Errorf("invalid foreign owner tag %q, expected <key> or <key>=<value>", entry)
+		}
+		tags = append(tags, ForeignOwnerTag{Key: key, Value: strings.TrimSpace(value)})
+	}
+	return tags, nil
+}
+
+// Match returns the first tag the resource tags match, as key or key=value. Values are
+// compared case-insensitively.
+func (f ForeignOwnerTags) Match(tags map[string]string) (string, bool) {
+	for _, owner := range f {
+		value, ok := tags[owner.Key]
+		if !ok {
+			continue
+		}
+		if owner.Value == "" {
+			return owner.Key, true
+		}
+		if strings.EqualFold(value, owner.Value) {
+			return owner.Key + "=" + owner.Value, true
+		}
+	}
+	return "", false
+}
+
+// deleteCertificate deletes the ACM certificate of a request (or of an Orphan it left behind)
+// unless its tags show it is owned by another tool, in which case it is left in place with a
+// ForeignOwnedCertificate event on obj. Returns true if the certificate was deleted.
+func (r *GatewayHostnameRequestReconciler) deleteCertificate(ctx context.Context, obj runtime.Object, certArn string) (bool, error) {
+	if len(r.ForeignOwnerTags) > 0 {
+		tags, err := r.ACMClient.GetCertificateTags(ctx, certArn)
+		if err != nil {
+			return false, err
+		}
+		if owner, foreign := r.ForeignOwnerTags.Match(tags); foreign {
+			log.FromContext(ctx).Info("Not deleting ACM certificate owned by another tool", "arn", certArn, "tag", owner)
+			r.Recorder.Eventf(obj, corev1.EventTypeNormal, "ForeignOwnedCertificate",
+				"Left ACM certificate %s in place: tagged %s", certArn, owner)
+			return false, nil
+		}
+	}
+	if err := r.ACMClient.DeleteCertificate(ctx, certArn); err != nil {
+		return false, err
+	}
+	return true, nil
+}
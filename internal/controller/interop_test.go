@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+func TestParseInteropTags(t *testing.T) {
+	tags, err := ParseInteropTags(" iac-ignore=true, owner = platform ,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"iac-ignore": "true", "owner": "platform"}, tags)
+
+	_, err = ParseInteropTags("iac-ignore")
+	assert.Error(t, err)
+	_, err = ParseInteropTags("aws:cloudformation:stack-name=x")
+	assert.Error(t, err)
+}
+
+func TestForeignOwnerTags_Match(t *testing.T) {
+	owners, err := ParseForeignOwnerTags(DefaultForeignOwnerTags)
+	require.NoError(t, err)
+
+	owner, foreign := owners.Match(map[string]string{"crossplane-kind": "certificate.acm.aws.upbound.io"})
+	assert.True(t, foreign)
+	assert.Equal(t, "crossplane-kind", owner)
+
+	owner, foreign = owners.Match(map[string]string{"managed-by": "Terraform"})
+	assert.True(t, foreign)
+	assert.Equal(t, "managed-by=terraform", owner)
+
+	_, foreign = owners.Match(map[string]string{"managed-by": certificateManagedBy})
+	assert.False(t, foreign)
+}
+
+func TestDeleteCertificate_SkipsForeignOwned(t *testing.T) {
+	ctx := context.Background()
+	acmMock := aws.NewMockACMClient()
+	arn, err := acmMock.RequestCertificate(ctx, "app.example.com", "tf", map[string]string{"managed-by": "terraform"})
+	require.NoError(t, err)
+	owners, _ := ParseForeignOwnerTags(DefaultForeignOwnerTags)
+	recorder := record.NewFakeRecorder(10)
+	r := &GatewayHostnameRequestReconciler{ACMClient: acmMock, Recorder: recorder, ForeignOwnerTags: owners}
+	ghr := assignedGHR("app", "app.example.com")
+
+	deleted, err := r.deleteCertificate(ctx, ghr, arn)
+	require.NoError(t, err)
+	assert.False(t, deleted)
+	assert.Contains(t, acmMock.Certificates, arn)
+	assert.Contains(t, <-recorder.Events, "ForeignOwnedCertificate")
+
+	// Without foreign owner tags every certificate is deleted
+	r.ForeignOwnerTags = nil
+	deleted, err = r.deleteCertificate(ctx, ghr, arn)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	assert.NotContains(t, acmMock.Certificates, arn)
+}
+
+func TestCertificateTags_Interop(t *testing.T) {
+	r := &GatewayHostnameRequestReconciler{InteropTags: map[string]string{"iac-ignore": "true", "managed-by": "someone-else"}}
+	tags := r.certificateTags(assignedGHR("app", "app.example.com"))
+	assert.Equal(t, "true", tags["iac-ignore"])
+	assert.Equal(t, certificateManagedBy, tags["managed-by"], "the orchestrator's own tags win")
+	assert.NotContains(t, tags, "cluster")
+
+	r.Cluster = "eu-1"
+	tags = r.certificateTags(assignedGHR("app", "app.example.com"))
+	assert.Equal(t, "eu-1", tags["cluster"])
+	assert.Equal(t, "default", tags["namespace"])
+}
@@ -0,0 +1,226 @@
+package controller
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// InventoryPath is where Inventory serves the hostname list; a single hostname is served
+// under InventoryPath + "/<hostname>"
+const InventoryPath = "/v1/hostnames"
+
+// InventoryHostname is everything the cluster knows about one hostname
+type InventoryHostname struct {
+	Hostname string `json:"hostname"`
+
+	// Requests are the GatewayHostnameRequests for the hostname
+	Requests []InventoryRequest `json:"requests,omitempty"`
+
+	// Claims are the DomainClaims holding the hostname
+	Claims []InventoryClaim `json:"claims,omitempty"`
+
+	// GrantedNamespaces are the namespaces a HostnameGrant allows to use the hostname
+	GrantedNamespaces []string `json:"grantedNamespaces,omitempty"`
+}
+
+// InventoryRequest is a GatewayHostnameRequest as served by Inventory
+type InventoryRequest struct {
+	Namespace           string            `json:"namespace"`
+	Name                string            `json:"name"`
+	ZoneId              string            `json:"zoneId"`
+	Environment         string            `json:"environment,omitempty"`
+	Labels              map[string]string `json:"labels,omitempty"`
+	Ready               bool              `json:"ready"`
+	Gateway             string            `json:"gateway,omitempty"`
+	LoadBalancer        string            `json:"loadBalancer,omitempty"`
+	CertificateArn      string            `json:"certificateArn,omitempty"`
+	CertificateIssuer   string            `json:"certificateIssuer,omitempty"`
+	CertificateNotAfter *metav1.Time      `json:"certificateNotAfter,omitempty"`
+}
+
+// InventoryClaim is a DomainClaim as served by Inventory
+type InventoryClaim struct {
+	ZoneId      string `json:"zoneId"`
+	Environment string `json:"environment,omitempty"`
+	// Scope is Subtree for claims reserving the hostname and all names beneath it
+	Scope string `json:"scope,omitempty"`
+	// Namespace and Name of the owning request; Subtree claims have only a namespace
+	Namespace string `json:"namespace"`
+	Name      string `json:"name,omitempty"`
+}
+
+// Inventory serves a read-only JSON view of the hostnames in the cluster (requests, claims
+// and grants) for systems such as CMDBs and developer portals that shouldn't need RBAC on the
+// CRDs. It runs its own HTTP server on BindAddress, on every replica.
+type Inventory struct {
+	Client client.Reader
+
+	// Claims is the ClaimStore of the controller; nil lists DomainClaim objects
+	Claims ClaimStore
+
+	// BindAddress is the address the API listens on, e.g. :8082
+	BindAddress string
+
+	// Token, if set, must be sent as a bearer token
+	Token string
+}
+
+// Start serves the API until the context is cancelled
+func (i *Inventory) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(InventoryPath, i)
+	mux.Handle(InventoryPath+"/", i)
+	server := &http.Server{Addr: i.BindAddress, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.FromContext(ctx).Info("Serving hostname inventory", "address", i.BindAddress, "path", InventoryPath)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("hostname inventory server failed: %w", err)
+	}
+	return nil
+}
+
+// NeedLeaderElection lets every replica serve the API
+func (i *Inventory) NeedLeaderElection() bool {
+	return false
+}
+
+// ServeHTTP serves all hostnames on InventoryPath, and one hostname (or 404) below it
+func (i *Inventory) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if i.Token != "" {
+		token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(i.Token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	hostnames, err := i.Collect(req.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if hostname := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(req.URL.Path, InventoryPath), "/")); hostname != "" {
+		idx := slices.IndexFunc(hostnames, func(h InventoryHostname) bool { return h.Hostname == hostname })
+		if idx < 0 {
+			http.Error(w, fmt.Sprintf("hostname %s not found", hostname), http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(hostnames[idx])
+		return
+	}
+	_ = json.NewEncoder(w).Encode(hostnames)
+}
+
+// Collect returns the hostnames of all requests, claims and grants, sorted by hostname
+func (i *Inventory) Collect(ctx context.Context) ([]InventoryHostname, error) {
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := i.Client.List(ctx, &ghrList); err != nil {
+		return nil, fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+	var claims []gatewayv1alpha1.DomainClaim
+	if i.Claims != nil {
+		var err error
+		if claims, err = i.Claims.List(ctx); err != nil {
+			return nil, err
+		}
+	} else {
+		var claimList gatewayv1alpha1.DomainClaimList
+		if err := i.Client.List(ctx, &claimList); err != nil {
+			return nil, fmt.Errorf("failed to list DomainClaims: %w", err)
+		}
+		claims = claimList.Items
+	}
+	var grantList gatewayv1alpha1.HostnameGrantList
+	if err := i.Client.List(ctx, &grantList); err != nil {
+		return nil, fmt.Errorf("failed to list HostnameGrants: %w", err)
+	}
+
+	byHostname := map[string]*InventoryHostname{}
+	entry := func(hostname string) *InventoryHostname {
+		hostname = strings.ToLower(hostname)
+		if _, ok := byHostname[hostname]; !ok {
+			byHostname[hostname] = &InventoryHostname{Hostname: hostname}
+		}
+		return byHostname[hostname]
+	}
+
+	for _, ghr := range ghrList.Items {
+		request := InventoryRequest{
+			Namespace:           ghr.Namespace,
+			Name:                ghr.Name,
+			ZoneId:              ghr.Spec.ZoneId,
+			Environment:         ghr.Spec.Environment,
+			Labels:              ghr.Labels,
+			Ready:               conditions.IsReady(ghr.Status.Conditions),
+			LoadBalancer:        ghr.Status.AssignedLoadBalancer,
+			CertificateArn:      ghr.Status.CertificateArn,
+			CertificateIssuer:   ghr.Status.CertificateIssuer,
+			CertificateNotAfter: ghr.Status.CertificateNotAfter,
+		}
+		if ghr.Status.AssignedGateway != "" {
+			request.Gateway = ghr.Status.AssignedGatewayNamespace + "/" + ghr.Status.AssignedGateway
+		}
+		e := entry(ghr.Spec.Hostname)
+		e.Requests = append(e.Requests, request)
+	}
+	for _, claim := range claims {
+		e := entry(claim.Spec.Hostname)
+		e.Claims = append(e.Claims, InventoryClaim{
+			ZoneId:      claim.Spec.ZoneId,
+			Environment: claim.Spec.Environment,
+			Scope:       claim.Spec.Scope,
+			Namespace:   claim.Spec.OwnerRef.Namespace,
+			Name:        claim.Spec.OwnerRef.Name,
+		})
+	}
+	for _, grant := range grantList.Items {
+		for _, hostname := range grant.Spec.Hostnames {
+			e := entry(hostname)
+			if !slices.Contains(e.GrantedNamespaces, grant.Spec.Namespace) {
+				e.GrantedNamespaces = append(e.GrantedNamespaces, grant.Spec.Namespace)
+			}
+		}
+	}
+
+	hostnames := make([]InventoryHostname, 0, len(byHostname))
+	for _, e := range byHostname {
+		sort.Slice(e.Requests, func(a, b int) bool {
+			return e.Requests[a].Namespace+"/"+e.Requests[a].Name < e.Requests[b].Namespace+"/"+e.Requests[b].Name
+		})
+		sort.Slice(e.Claims, func(a, b int) bool {
+			return e.Claims[a].ZoneId+"/"+e.Claims[a].Environment < e.Claims[b].ZoneId+"/"+e.Claims[b].Environment
+		})
+		sort.Strings(e.GrantedNamespaces)
+		hostnames = append(hostnames, *e)
+	}
+	sort.Slice(hostnames, func(a, b int) bool { return hostnames[a].Hostname < hostnames[b].Hostname })
+	return hostnames, nil
+}
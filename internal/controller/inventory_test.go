@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+func TestInventory_ServesRequestsClaimsAndGrants(t *testing.T) {
+	ghr := assignedGHR("api", "api.example.com")
+	ghr.Status.CertificateArn = "arn:aws:acm:eu-west-1:123456789012:certificate/abc"
+	ghr.Status.Conditions = []metav1.Condition{{Type: ConditionTypeReady, Status: metav1.ConditionTrue, Reason: "Ready"}}
+	claim := &gatewayv1alpha1.DomainClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "claim-api"},
+		Spec: gatewayv1alpha1.DomainClaimSpec{
+			ZoneId: "Z123", Hostname: "api.example.com",
+			OwnerRef: gatewayv1alpha1.DomainClaimOwnerRef{Namespace: ghr.Namespace, Name: ghr.Name},
+		},
+	}
+	grant := &gatewayv1alpha1.HostnameGrant{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-b", Namespace: "platform"},
+		Spec:       gatewayv1alpha1.HostnameGrantSpec{Namespace: "team-b", Hostnames: []string{"api.example.com", "shop.example.com"}},
+	}
+	i := &Inventory{
+		Client: fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr, claim, grant).Build(),
+		Token:  "secret",
+	}
+
+	rec := httptest.NewRecorder()
+	i.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, InventoryPath, nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, InventoryPath, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	i.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var hostnames []InventoryHostname
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &hostnames))
+	require.Len(t, hostnames, 2)
+	api := hostnames[0]
+	assert.Equal(t, "api.example.com", api.Hostname)
+	require.Len(t, api.Requests, 1)
+	assert.True(t, api.Requests[0].Ready)
+	assert.Equal(t, "edge/gw-01", api.Requests[0].Gateway)
+	assert.Equal(t, ghr.Status.CertificateArn, api.Requests[0].CertificateArn)
+	assert.Equal(t, []InventoryClaim{{ZoneId: "Z123", Namespace: ghr.Namespace, Name: ghr.Name}}, api.Claims)
+	assert.Equal(t, []string{"team-b"}, api.GrantedNamespaces)
+	assert.Equal(t, "shop.example.com", hostnames[1].Hostname, "granted hostnames without requests are listed too")
+
+	req = httptest.NewRequest(http.MethodGet, InventoryPath+"/unknown.example.com", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	i.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
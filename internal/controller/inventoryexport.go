@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+// Kinds of InventoryExportRow
+const (
+	InventoryKindRequest = "request"
+
+	// InventoryKindClaim rows are DomainClaims without a request in the export, such as the
+	// Subtree claims of a namespace
+	InventoryKindClaim = "claim"
+)
+
+// InventoryExportRow is a row of the exported inventory: a request, or a claim held without one
+type InventoryExportRow struct {
+	Hostname            string `json:"hostname"`
+	Kind                string `json:"kind"`
+	Namespace           string `json:"namespace"`
+	Name                string `json:"name,omitempty"`
+	ZoneId              string `json:"zoneId"`
+	Environment         string `json:"environment,omitempty"`
+	Scope               string `json:"scope,omitempty"`
+	Ready               bool   `json:"ready"`
+	Gateway             string `json:"gateway,omitempty"`
+	LoadBalancer        string `json:"loadBalancer,omitempty"`
+	WafArn              string `json:"wafArn,omitempty"`
+	CertificateArn      string `json:"certificateArn,omitempty"`
+	CertificateIssuer   string `json:"certificateIssuer,omitempty"`
+	CertificateNotAfter string `json:"certificateNotAfter,omitempty"`
+}
+
+// inventoryCSVHeader are the CSV columns, in InventoryExportRow order
+var inventoryCSVHeader = []string{
+	"hostname", "kind", "namespace", "name", "zoneId", "environment", "scope", "ready",
+	"gateway", "loadBalancer", "wafArn", "certificateArn", "certificateIssuer", "certificateNotAfter",
+}
+
+// InventoryExport writes the hostname inventory to an S3 object every Interval, for security
+// inventories and certificate management systems without access to the cluster. The object
+// is CSV if Key ends in .csv and JSON otherwise, and is only rewritten when it changed.
+// It runs as a manager Runnable on the leader.
+type InventoryExport struct {
+	Client   client.Reader
+	Claims   ClaimStore
+	Objects  aws.ObjectStore
+	Bucket   string
+	Key      string
+	Interval time.Duration
+
+	// last is the content written last, so an unchanged inventory isn't uploaded again
+	last []byte
+}
+
+// Start implements manager.Runnable
+func (e *InventoryExport) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("inventory-export")
+	logger.Info("Starting hostname inventory export", "bucket", e.Bucket, "key", e.Key, "interval", e.Interval)
+
+	ticker := time.NewTicker(e.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.Export(ctx); err != nil {
+			logger.Error(err, "Hostname inventory export failed")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Export writes the inventory unless it is the same as the last one written
+func (e *InventoryExport) Export(ctx context.Context) error {
+	rows, err := e.Rows(ctx)
+	if err != nil {
+		return err
+	}
+	var body []byte
+	if strings.HasSuffix(strings.ToLower(e.Key), ".csv") {
+		body, err = inventoryCSV(rows)
+	} else {
+		body, err = json.Marshal(rows)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode inventory: %w", err)
+	}
+	if bytes.Equal(body, e.last) {
+		return nil
+	}
+	if err := e.Objects.PutObject(ctx, e.Key, body); err != nil {
+		return fmt.Errorf("failed to write inventory to s3://%s/%s: %w", e.Bucket, e.Key, err)
+	}
+	e.last = body
+	log.FromContext(ctx).V(1).Info("Exported hostname inventory", "key", e.Key, "rows", len(rows))
+	return nil
+}
+
+// Rows flattens the inventory Inventory serves into one row per request, and one per claim
+// whose request is missing, sorted by hostname. WafArn is the WAF of the request's Gateway.
+func (e *InventoryExport) Rows(ctx context.Context) ([]InventoryExportRow, error) {
+	hostnames, err := (&Inventory{Client: e.Client, Claims: e.Claims}).Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	wafArns := map[string]string{}
+	wafArn := func(gateway string) (string, error) {
+		if arn, ok := wafArns[gateway]; ok {
+			return arn, nil
+		}
+		namespace, name, _ := strings.Cut(gateway, "/")
+		var gw gwapiv1.Gateway
+		if err := e.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &gw); client.IgnoreNotFound(err) != nil {
+			return "", fmt.Errorf("failed to get Gateway %s: %w", gateway, err)
+		}
+		wafArns[gateway] = gw.Annotations["gateway.opendi.com/waf-arn"]
+		return wafArns[gateway], nil
+	}
+
+	rows := []InventoryExportRow{}
+	for _, h := range hostnames {
+		requests := map[string]bool{}
+		for _, request := range h.Requests {
+			requests[request.Namespace+"/"+request.Name] = true
+			row := InventoryExportRow{
+				Hostname:          h.Hostname,
+				Kind:              InventoryKindRequest,
+				Namespace:         request.Namespace,
+				Name:              request.Name,
+				ZoneId:            request.ZoneId,
+				Environment:       request.Environment,
+				Ready:             request.Ready,
+				Gateway:           request.Gateway,
+				LoadBalancer:      request.LoadBalancer,
+				CertificateArn:    request.CertificateArn,
+				CertificateIssuer: request.CertificateIssuer,
+			}
+			if request.CertificateNotAfter != nil {
+				row.CertificateNotAfter = request.CertificateNotAfter.UTC().Format(time.RFC3339)
+			}
+			if request.Gateway != "" {
+				if row.WafArn, err = wafArn(request.Gateway); err != nil {
+					return nil, err
+				}
+			}
+			rows = append(rows, row)
+		}
+		for _, claim := range h.Claims {
+			if claim.Name != "" && requests[claim.Namespace+"/"+claim.Name] {
+				continue
+			}
+			rows = append(rows, InventoryExportRow{
+				Hostname:    h.Hostname,
+				Kind:        InventoryKindClaim,
+				Namespace:   claim.Namespace,
+				Name:        claim.Name,
+				ZoneId:      claim.ZoneId,
+				Environment: claim.Environment,
+				Scope:       claim.Scope,
+			})
+		}
+	}
+	return rows, nil
+}
+
+// inventoryCSV encodes rows as CSV with inventoryCSVHeader
+func inventoryCSV(rows []InventoryExportRow) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(inventoryCSVHeader); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		record := []string{
+			row.Hostname, row.Kind, row.Namespace, row.Name, row.ZoneId, row.Environment, row.Scope,
+			strconv.FormatBool(row.Ready), row.Gateway, row.LoadBalancer, row.WafArn,
+			row.CertificateArn, row.CertificateIssuer, row.CertificateNotAfter,
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+func TestInventoryExport(t *testing.T) {
+	ctx := context.Background()
+	waf := "arn:aws:wafv2:eu-west-1:123456789012:regional/webacl/edge/1"
+	notAfter := metav1.NewTime(time.Date(2027, 1, 2, 3, 4, 5, 0, time.UTC))
+	ghr := assignedGHR("api", "api.example.com")
+	ghr.Spec.ZoneId = "Z123"
+	ghr.Status.CertificateArn = "arn:aws:acm:eu-west-1:123456789012:certificate/abc"
+	ghr.Status.CertificateNotAfter = &notAfter
+	ghr.Status.AssignedLoadBalancer = "k8s-edge-gw01.eu-west-1.elb.amazonaws.com"
+	gw := &gwapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{
+		Name: "gw-01", Namespace: "edge", Annotations: map[string]string{"gateway.opendi.com/waf-arn": waf},
+	}}
+	claim := &gatewayv1alpha1.DomainClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "claim-api"},
+		Spec: gatewayv1alpha1.DomainClaimSpec{
+			ZoneId: "Z123", Hostname: "api.example.com",
+			OwnerRef: gatewayv1alpha1.DomainClaimOwnerRef{Namespace: ghr.Namespace, Name: ghr.Name},
+		},
+	}
+	subtree := &gatewayv1alpha1.DomainClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "claim-team"},
+		Spec: gatewayv1alpha1.DomainClaimSpec{
+			ZoneId: "Z123", Hostname: "team.example.com", Scope: "Subtree",
+			OwnerRef: gatewayv1alpha1.DomainClaimOwnerRef{Namespace: "team-a"},
+		},
+	}
+	objects := &aws.MockObjectStore{}
+	e := &InventoryExport{
+		Client:  fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr, gw, claim, subtree).Build(),
+		Objects: objects,
+		Key:     "inventory/hostnames.json",
+	}
+
+	require.NoError(t, e.Export(ctx))
+	var rows []InventoryExportRow
+	require.NoError(t, json.Unmarshal(objects.Objects["inventory/hostnames.json"], &rows))
+	assert.Equal(t, []InventoryExportRow{
+		{
+			Hostname: "api.example.com", Kind: InventoryKindRequest, Namespace: "default", Name: "api", ZoneId: "Z123",
+			Gateway: "edge/gw-01", LoadBalancer: ghr.Status.AssignedLoadBalancer, WafArn: waf,
+			CertificateArn: ghr.Status.CertificateArn, CertificateNotAfter: "2027-01-02T03:04:05Z",
+		},
+		{Hostname: "team.example.com", Kind: InventoryKindClaim, Namespace: "team-a", ZoneId: "Z123", Scope: "Subtree"},
+	}, rows, "the request's own claim is folded into its row")
+
+	// Unchanged inventories aren't uploaded again
+	delete(objects.Objects, "inventory/hostnames.json")
+	require.NoError(t, e.Export(ctx))
+	assert.Empty(t, objects.Objects)
+
+	e.Key = "inventory/hostnames.csv"
+	require.NoError(t, e.Export(ctx))
+	records, err := csv.NewReader(strings.NewReader(string(objects.Objects["inventory/hostnames.csv"]))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, inventoryCSVHeader, records[0])
+	assert.Equal(t, []string{"api.example.com", "request", "default", "api", "Z123", "", "", "false",
+		"edge/gw-01", ghr.Status.AssignedLoadBalancer, waf, ghr.Status.CertificateArn, "", "2027-01-02T03:04:05Z"}, records[1])
+}
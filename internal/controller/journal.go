@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// Steps that journal the AWS resources they create
+const (
+	// JournalCertificateRequested is an ACM certificate requested for the hostname, keyed by
+	// the idempotency token
+	JournalCertificateRequested = "CertificateRequested"
+
+	// JournalCertificateImported is an ACME certificate imported into ACM, keyed by the order
+	JournalCertificateImported = "CertificateImported"
+
+	// JournalCertificateExpansion is an expanded certificate, keyed by the idempotency token
+	JournalCertificateExpansion = "CertificateExpansionRequested"
+)
+
+// MaxJournalEntries bounds status.journal; the oldest entries are dropped first.
+// Must not exceed the MaxItems validation on the CRD field.
+const MaxJournalEntries = 10
+
+// journaled returns the resource a previous attempt of the step created, or "" if there is none.
+// ACM only deduplicates requests with the same idempotency token for an hour, and imports not
+// at all, so a resource whose status write failed would otherwise be created again.
+func journaled(ghr *gatewayv1alpha1.GatewayHostnameRequest, step, key string) string {
+	for _, entry := range ghr.Status.Journal {
+		if entry.Step == step && entry.Key == key {
+			return entry.Resource
+		}
+	}
+	return ""
+}
+
+// resolveJournal removes the step's entry, as the status about to be written reflects it
+func resolveJournal(ghr *gatewayv1alpha1.GatewayHostnameRequest, step, key string) {
+	journal := ghr.Status.Journal[:0]
+	for _, entry := range ghr.Status.Journal {
+		if entry.Step != step || entry.Key != key {
+			journal = append(journal, entry)
+		}
+	}
+	if len(journal) == 0 {
+		journal = nil
+	}
+	ghr.Status.Journal = journal
+}
+
+// updateStatusJournaled writes the status after the step created the resource. If the write
+// fails, e.g. on a conflict, the resource is recorded in status.journal with a merge patch,
+// which doesn't need the current resourceVersion, and the next attempt of the step adopts it.
+// Returns the error of the status write.
+func (r *GatewayHostnameRequestReconciler) updateStatusJournaled(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, step, key, resource string) error {
+	resolveJournal(ghr, step, key)
+	err := r.Status().Update(ctx, ghr)
+	if err == nil {
+		return nil
+	}
+
+	journal := ghr.DeepCopy()
+	patch := client.MergeFrom(journal.DeepCopy())
+	journal.Status.Journal = append(journal.Status.Journal, gatewayv1alpha1.JournalEntry{
+		Time: metav1.Now(), Step: step, Key: key, Resource: resource,
+	})
+	if overflow := len(journal.Status.Journal) - MaxJournalEntries; overflow > 0 {
+		journal.Status.Journal = journal.Status.Journal[overflow:]
+	}
+	if patchErr := r.Status().Patch(ctx, journal, patch); patchErr != nil {
+		log.FromContext(ctx).Error(patchErr, "Failed to journal AWS resource, it may be created again", "step", step, "resource", resource)
+		return err
+	}
+	log.FromContext(ctx).Info("Status write failed, journaled AWS resource for adoption", "step", step, "resource", resource, "error", err.Error())
+	return err
+}
+
+// adoptJournaled returns the resource a previous attempt of the step created and reports
+// its adoption, or "" if there is none
+func (r *GatewayHostnameRequestReconciler) adoptJournaled(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, step, key string) string {
+	resource := journaled(ghr, step, key)
+	if resource != "" {
+		log.FromContext(ctx).Info("Adopting journaled AWS resource", "step", step, "resource", resource)
+		r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "ResourceAdopted", "Adopted %s from an earlier attempt whose status write failed (%s)", resource, step)
+	}
+	return resource
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+func TestUpdateStatusJournaled_AdoptsAfterFailedWrite(t *testing.T) {
+	ctx := context.Background()
+	ghr := assignedGHR("app", "app.example.com")
+	conflict := true
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr).WithStatusSubresource(ghr).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				if conflict {
+					return apierrors.NewConflict(schema.GroupResource{Resource: "gatewayhostnamerequests"}, obj.GetName(), nil)
+				}
+				return c.SubResource(subResource).Update(ctx, obj, opts...)
+			},
+		}).Build()
+	r := &GatewayHostnameRequestReconciler{Client: c, Recorder: record.NewFakeRecorder(10)}
+
+	// The certificate is journaled although the status write failed
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(ghr), ghr))
+	ghr.Status.CertificateArn = "arn:cert-1"
+	err := r.updateStatusJournaled(ctx, ghr, JournalCertificateRequested, "token-1", "arn:cert-1")
+	assert.True(t, apierrors.IsConflict(err))
+
+	var retried gatewayv1alpha1.GatewayHostnameRequest
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(ghr), &retried))
+	assert.Empty(t, retried.Status.CertificateArn)
+	assert.Empty(t, r.adoptJournaled(ctx, &retried, JournalCertificateRequested, "token-2"), "another attempt")
+	assert.Equal(t, "arn:cert-1", r.adoptJournaled(ctx, &retried, JournalCertificateRequested, "token-1"))
+
+	// Once the status holds it, the entry is removed
+	conflict = false
+	retried.Status.CertificateArn = "arn:cert-1"
+	require.NoError(t, r.updateStatusJournaled(ctx, &retried, JournalCertificateRequested, "token-1", "arn:cert-1"))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(ghr), &retried))
+	assert.Equal(t, "arn:cert-1", retried.Status.CertificateArn)
+	assert.Empty(t, retried.Status.Journal)
+}
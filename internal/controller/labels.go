@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// Labels mirrored onto GatewayHostnameRequests, so requests can be filtered with label
+// selectors instead of scanning status fields client-side
+const (
+	LabelAssignedGateway          = "gateway.opendi.com/assigned-gateway"
+	LabelAssignedGatewayNamespace = "gateway.opendi.com/assigned-gateway-namespace"
+	LabelZoneID                   = "gateway.opendi.com/zone-id"
+)
+
+// requestLabels returns the desired value of each mirrored label; empty values mean the
+// label is removed
+func requestLabels(ghr *gatewayv1alpha1.GatewayHostnameRequest) map[string]string {
+	return map[string]string{
+		LabelAssignedGateway:          ghr.Status.AssignedGateway,
+		LabelAssignedGatewayNamespace: ghr.Status.AssignedGatewayNamespace,
+		LabelZoneID:                   ghr.Spec.ZoneId,
+	}
+}
+
+// syncRequestLabels updates the mirrored labels on the request and reports whether they changed.
+// Values that are not valid label values are left out.
+func syncRequestLabels(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	changed := false
+	for key, value := range requestLabels(ghr) {
+		if len(validation.IsValidLabelValue(value)) > 0 {
+			value = ""
+		}
+		current, exists := ghr.Labels[key]
+		switch {
+		case value == "" && exists:
+			delete(ghr.Labels, key)
+			changed = true
+		case value != "" && current != value:
+			if ghr.Labels == nil {
+				ghr.Labels = make(map[string]string)
+			}
+			ghr.Labels[key] = value
+			changed = true
+		}
+	}
+	return changed
+}
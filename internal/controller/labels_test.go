@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncRequestLabels(t *testing.T) {
+	ghr := assignedGHR("app", "app.example.com")
+	ghr.Spec.ZoneId = "Z123456"
+	ghr.Labels = map[string]string{"team": "a"}
+
+	assert.True(t, syncRequestLabels(ghr))
+	assert.Equal(t, map[string]string{
+		"team":                        "a",
+		LabelAssignedGateway:          "gw-01",
+		LabelAssignedGatewayNamespace: "edge",
+		LabelZoneID:                   "Z123456",
+	}, ghr.Labels)
+	assert.False(t, syncRequestLabels(ghr), "labels already in sync")
+
+	// Moved to another Gateway
+	ghr.Status.AssignedGateway = "gw-02"
+	assert.True(t, syncRequestLabels(ghr))
+	assert.Equal(t, "gw-02", ghr.Labels[LabelAssignedGateway])
+
+	// Unassigned (e.g. during re-provisioning): the assignment labels are removed
+	ghr.Status.AssignedGateway = ""
+	ghr.Status.AssignedGatewayNamespace = ""
+	assert.True(t, syncRequestLabels(ghr))
+	assert.Equal(t, map[string]string{"team": "a", LabelZoneID: "Z123456"}, ghr.Labels)
+
+	// No labels needed: nothing to do
+	empty := assignedGHR("empty", "empty.example.com")
+	empty.Status.AssignedGateway = ""
+	empty.Status.AssignedGatewayNamespace = ""
+	assert.False(t, syncRequestLabels(empty))
+	assert.Nil(t, empty.Labels)
+}
@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// lbcRediscoveryInterval bounds how often a failing LoadBalancerConfiguration call
+// triggers API discovery, so a broken LBC install doesn't flood the API server
+const lbcRediscoveryInterval = 30 * time.Second
+
+// LBCVersionResolver tracks which version of the LoadBalancerConfiguration API the cluster
+// serves, so upgrades of the AWS Load Balancer Controller don't need an orchestrator release
+type LBCVersionResolver struct {
+	discovery discovery.DiscoveryInterface
+
+	mu           sync.Mutex
+	gvk          schema.GroupVersionKind
+	lastRefresh  time.Time
+	refreshAfter time.Duration
+}
+
+// NewLBCVersionResolver returns a resolver that starts out with LoadBalancerConfigurationGVK
+func NewLBCVersionResolver(dc discovery.DiscoveryInterface) *LBCVersionResolver {
+	return &LBCVersionResolver{
+		discovery:    dc,
+		gvk:          LoadBalancerConfigurationGVK,
+		refreshAfter: lbcRediscoveryInterval,
+	}
+}
+
+// GVK returns the LoadBalancerConfiguration GVK to render
+func (v *LBCVersionResolver) GVK() schema.GroupVersionKind {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.gvk
+}
+
+// Refresh discovers the served LoadBalancerConfiguration version. The group's preferred version
+// wins; other served versions are tried in the server's priority order. If the group is not
+// served at all, the current version is kept.
+func (v *LBCVersionResolver) Refresh(ctx context.Context) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.lastRefresh = time.Now()
+
+	groups, err := v.discovery.ServerGroups()
+	if err != nil {
+		return fmt.Errorf("failed to discover API groups: %w", err)
+	}
+
+	for _, group := range groups.Groups {
+		if group.Name != LoadBalancerConfigurationGVK.Group {
+			continue
+		}
+		versions := []string{group.PreferredVersion.Version}
+		for _, gv := range group.Versions {
+			if gv.Version != group.PreferredVersion.Version {
+				versions = append(versions, gv.Version)
+			}
+		}
+		for _, version := range versions {
+			gv := schema.GroupVersion{Group: group.Name, Version: version}
+			resources, err := v.discovery.ServerResourcesForGroupVersion(gv.String())
+			if err != nil {
+				return fmt.Errorf("failed to discover %s: %w", gv, err)
+			}
+			for _, resource := range resources.APIResources {
+				if resource.Kind != LoadBalancerConfigurationGVK.Kind {
+					continue
+				}
+				if v.gvk.Version != version {
+					log.FromContext(ctx).Info("Using discovered LoadBalancerConfiguration version",
+						"previous", v.gvk.Version, "version", version)
+				}
+				v.gvk = gv.WithKind(LoadBalancerConfigurationGVK.Kind)
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("%s is not served by the cluster", LoadBalancerConfigurationGVK.GroupKind())
+}
+
+// refreshOnNoMatch re-runs discovery when err shows the rendered version is no longer served,
+// at most once per rediscovery interval. The failing reconcile is retried with the controller's
+// backoff and picks up the new version.
+func (v *LBCVersionResolver) refreshOnNoMatch(ctx context.Context, err error) {
+	if !meta.IsNoMatchError(err) {
+		return
+	}
+	v.mu.Lock()
+	due := time.Since(v.lastRefresh) >= v.refreshAfter
+	v.mu.Unlock()
+	if !due {
+		return
+	}
+	if err := v.Refresh(ctx); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to rediscover LoadBalancerConfiguration version")
+	}
+}
+
+// loadBalancerConfigurationGVK returns the LoadBalancerConfiguration GVK served by the cluster
+func (r *GatewayHostnameRequestReconciler) loadBalancerConfigurationGVK() schema.GroupVersionKind {
+	if r.LBCVersion == nil {
+		return LoadBalancerConfigurationGVK
+	}
+	return r.LBCVersion.GVK()
+}
+
+// newLoadBalancerConfiguration returns an empty LoadBalancerConfiguration of the served version
+func (r *GatewayHostnameRequestReconciler) newLoadBalancerConfiguration() *unstructured.Unstructured {
+	lbc := &unstructured.Unstructured{}
+	lbc.SetGroupVersionKind(r.loadBalancerConfigurationGVK())
+	return lbc
+}
+
+// getLoadBalancerConfiguration fetches a LoadBalancerConfiguration, rediscovering the
+// served version if the one in use has gone away
+func (r *GatewayHostnameRequestReconciler) getLoadBalancerConfiguration(ctx context.Context, key types.NamespacedName) (*unstructured.Unstructured, error) {
+	lbc := r.newLoadBalancerConfiguration()
+	err := r.Get(ctx, key, lbc)
+	if err != nil && r.LBCVersion != nil {
+		r.LBCVersion.refreshOnNoMatch(ctx, err)
+	}
+	return lbc, err
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func lbcResources(versions ...string) []*metav1.APIResourceList {
+	var lists []*metav1.APIResourceList
+	for _, version := range versions {
+		lists = append(lists, &metav1.APIResourceList{
+			GroupVersion: "gateway.k8s.aws/" + version,
+			APIResources: []metav1.APIResource{
+				{Name: "targetgroupconfigurations", Kind: "TargetGroupConfiguration", Namespaced: true},
+				{Name: "loadbalancerconfigurations", Kind: "LoadBalancerConfiguration", Namespaced: true},
+			},
+		})
+	}
+	return lists
+}
+
+func TestLBCVersionResolver_Refresh(t *testing.T) {
+	tests := []struct {
+		name        string
+		versions    []string
+		wantVersion string
+		wantErr     bool
+	}{
+		{name: "v1beta1 only", versions: []string{"v1beta1"}, wantVersion: "v1beta1"},
+		{name: "preferred newer version", versions: []string{"v1", "v1beta1"}, wantVersion: "v1"},
+		{name: "LBC not installed keeps default", wantVersion: "v1beta1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dc := &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: lbcResources(tt.versions...)}}
+			resolver := NewLBCVersionResolver(dc)
+
+			err := resolver.Refresh(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantVersion, resolver.GVK().Version)
+			assert.Equal(t, "LoadBalancerConfiguration", resolver.GVK().Kind)
+		})
+	}
+}
+
+func TestLBCVersionResolver_RefreshOnNoMatch(t *testing.T) {
+	fake := &k8stesting.Fake{Resources: lbcResources("v1beta1")}
+	resolver := NewLBCVersionResolver(&fakediscovery.FakeDiscovery{Fake: fake})
+	require.NoError(t, resolver.Refresh(context.Background()))
+
+	// The LBC upgrade replaces v1beta1 with v1
+	fake.Resources = lbcResources("v1")
+	noMatch := &meta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "gateway.k8s.aws", Kind: "LoadBalancerConfiguration"}, SearchedVersions: []string{"v1beta1"}}
+
+	// Rediscovery is rate limited
+	resolver.refreshOnNoMatch(context.Background(), noMatch)
+	assert.Equal(t, "v1beta1", resolver.GVK().Version)
+
+	resolver.refreshAfter = 0
+	resolver.refreshOnNoMatch(context.Background(), assert.AnError)
+	assert.Equal(t, "v1beta1", resolver.GVK().Version, "other errors must not trigger discovery")
+
+	resolver.refreshOnNoMatch(context.Background(), noMatch)
+	assert.Equal(t, "v1", resolver.GVK().Version)
+}
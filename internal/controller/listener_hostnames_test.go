@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapisxv1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+func assignedGHR(name, hostname string) *gatewayv1alpha1.GatewayHostnameRequest {
+	return &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: hostname},
+		Status: gatewayv1alpha1.GatewayHostnameRequestStatus{
+			AssignedGateway:          "gw-01",
+			AssignedGatewayNamespace: "edge",
+		},
+	}
+}
+
+func listenerNames(gw *gwapiv1.Gateway) []string {
+	var names []string
+	for _, l := range gw.Spec.Listeners {
+		names = append(names, string(l.Name))
+	}
+	return names
+}
+
+func TestSyncHostnameListeners_AddsAndRemoves(t *testing.T) {
+	scheme := getTestScheme()
+	pool := gateway.NewPool(nil, "edge", "aws-alb", 0, 0)
+
+	current := assignedGHR("b", "b.example.com")
+	deleting := assignedGHR("c", "c.example.com")
+	now := metav1.NewTime(time.Now())
+	deleting.DeletionTimestamp = &now
+	deleting.Finalizers = []string{FinalizerName}
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(assignedGHR("a", "a.example.com"), deleting).
+		Build()
+
+	r := &GatewayHostnameRequestReconciler{Client: c, GatewayPool: pool, ListenerMode: ListenerModeHostname}
+
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw-01", Namespace: "edge"},
+		Spec: gwapiv1.GatewaySpec{
+			Listeners: []gwapiv1.Listener{
+				{Name: "https", Port: 443, Protocol: gwapiv1.HTTPSProtocolType},
+				{Name: "http", Port: 80, Protocol: gwapiv1.HTTPProtocolType},
+				pool.HostnameListener("c.example.com"),
+			},
+		},
+	}
+
+	changed, err := r.syncHostnameListeners(context.Background(), gw, current, true)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, []string{"https", "http", "https-a.example.com", "https-b.example.com"}, listenerNames(gw))
+
+	// Second pass is a no-op
+	changed, err = r.syncHostnameListeners(context.Background(), gw, current, true)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	// Dropping self removes only its listener
+	changed, err = r.syncHostnameListeners(context.Background(), gw, current, false)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, []string{"https", "http", "https-a.example.com"}, listenerNames(gw))
+}
+
+func TestSyncHostnameListeners_CertificateFromLoadBalancerConfiguration(t *testing.T) {
+	ctx := context.Background()
+	pool := gateway.NewPool(nil, "edge", "aws-alb", 0, 0)
+	current := assignedGHR("a", "a.example.com")
+	current.Status.CertificateArn = "arn:aws:acm:eu-west-1:123456789012:certificate/a"
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(current).Build()
+	r := &GatewayHostnameRequestReconciler{Client: c, GatewayPool: pool, ListenerMode: ListenerModeHostname}
+
+	// A listener that carried its certificate as a TLS option loses it
+	legacy := pool.HostnameListener("a.example.com")
+	legacy.TLS.Options = map[gwapiv1.AnnotationKey]gwapiv1.AnnotationValue{
+		"gateway.opendi.com/acm-managed":     "true",
+		"gateway.opendi.com/certificate-arn": gwapiv1.AnnotationValue(current.Status.CertificateArn),
+	}
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw-01", Namespace: "edge"},
+		Spec:       gwapiv1.GatewaySpec{Listeners: []gwapiv1.Listener{legacy}},
+	}
+	changed, err := r.syncHostnameListeners(ctx, gw, current, true)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	require.Len(t, gw.Spec.Listeners, 1)
+	assert.Equal(t, pool.HostnameListener("a.example.com"), gw.Spec.Listeners[0])
+
+	// The hostname's certificate is served through the LoadBalancerConfiguration instead
+	arns, err := r.getGatewayCertificateARNs(ctx, "gw-01", "edge")
+	require.NoError(t, err)
+	assert.Equal(t, []string{current.Status.CertificateArn}, arns)
+}
+
+func TestSyncHostnameListeners_ListenerSetMode(t *testing.T) {
+	pool := gateway.NewPool(nil, "edge", "aws-alb", 0, 0)
+	other := assignedGHR("a", "a.example.com")
+	other.Status.CertificateArn = "arn:aws:acm:eu-west-1:123456789012:certificate/a"
+	current := assignedGHR("b", "b.example.com")
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(other).Build()
+	r := &GatewayHostnameRequestReconciler{Client: c, GatewayPool: pool, ListenerMode: ListenerModeListenerSet}
+	ctx := context.Background()
+
+	// Listeners left over from hostname mode move to the ListenerSet
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw-01", Namespace: "edge", UID: "gw-uid"},
+		Spec: gwapiv1.GatewaySpec{
+			Listeners: []gwapiv1.Listener{
+				{Name: "https", Port: 443, Protocol: gwapiv1.HTTPSProtocolType},
+				pool.HostnameListener("a.example.com"),
+			},
+		},
+	}
+	changed, err := r.syncHostnameListeners(ctx, gw, current, true)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, []string{"https"}, listenerNames(gw))
+	require.NotNil(t, gw.Spec.AllowedListeners)
+	assert.Equal(t, gwapiv1.NamespacesFromSame, *gw.Spec.AllowedListeners.Namespaces.From)
+
+	var ls gwapisxv1alpha1.XListenerSet
+	key := types.NamespacedName{Namespace: "edge", Name: "gw-01-hostnames"}
+	require.NoError(t, c.Get(ctx, key, &ls))
+	assert.Equal(t, gwapisxv1alpha1.ObjectName("gw-01"), ls.Spec.ParentRef.Name)
+	assert.Equal(t, "gw-uid", string(ls.OwnerReferences[0].UID))
+	require.Len(t, ls.Spec.Listeners, 2)
+	assert.Equal(t, gwapisxv1alpha1.SectionName("https-a.example.com"), ls.Spec.Listeners[0].Name)
+	assert.Equal(t, pool.HostnameListenerEntry("a.example.com"), ls.Spec.Listeners[0])
+	assert.Equal(t, gwapisxv1alpha1.SectionName("https-b.example.com"), ls.Spec.Listeners[1].Name)
+
+	// The Gateway is not touched again
+	changed, err = r.syncHostnameListeners(ctx, gw, current, true)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// Without hostnames the ListenerSet is deleted
+	require.NoError(t, c.Delete(ctx, other))
+	_, err = r.syncHostnameListeners(ctx, gw, current, false)
+	require.NoError(t, err)
+	assert.True(t, apierrors.IsNotFound(c.Get(ctx, key, &ls)))
+}
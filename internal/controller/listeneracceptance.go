@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// listenerAcceptancePollInterval is how often a request checks whether the AWS Load Balancer
+// Controller accepted its Gateway's configuration
+const listenerAcceptancePollInterval = 15 * time.Second
+
+// listenerAcceptance is what the AWS Load Balancer Controller made of a Gateway and its
+// LoadBalancerConfiguration
+type listenerAcceptance struct {
+	// Accepted is true once the LoadBalancerConfiguration and the Gateway are programmed
+	Accepted bool
+	// Rejected is true if the AWS Load Balancer Controller reported them invalid; waiting
+	// won't help
+	Rejected bool
+	// Message says what is missing or wrong
+	Message string
+}
+
+// listenerAccepted reports whether the AWS Load Balancer Controller processed the request's
+// Gateway: the LoadBalancerConfiguration, if it reports a status, must have its current
+// generation accepted, and the Gateway must be Programmed for its current generation. Writing
+// the certificate into the LoadBalancerConfiguration alone doesn't mean the ALB serves it.
+func (r *GatewayHostnameRequestReconciler) listenerAccepted(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (listenerAcceptance, error) {
+	lbcName, err := r.loadBalancerConfigurationName(ghr.Status.AssignedGateway)
+	if err != nil {
+		return listenerAcceptance{}, err
+	}
+	lbc := &unstructured.Unstructured{}
+	lbc.SetGroupVersionKind(LoadBalancerConfigurationGVK)
+	err = r.Get(ctx, types.NamespacedName{
+		Name:      lbcName,
+		Namespace: ghr.Status.AssignedGatewayNamespace,
+	}, lbc)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return listenerAcceptance{}, fmt.Errorf("failed to get LoadBalancerConfiguration: %w", err)
+	}
+	if err == nil {
+		if acceptance, reported := loadBalancerConfigurationAcceptance(lbc); reported && !acceptance.Accepted {
+			return acceptance, nil
+		}
+	}
+
+	var gw gwapiv1.Gateway
+	if err := r.Get(ctx, types.NamespacedName{Name: ghr.Status.AssignedGateway, Namespace: ghr.Status.AssignedGatewayNamespace}, &gw); err != nil {
+		return listenerAcceptance{}, fmt.Errorf("failed to get Gateway: %w", err)
+	}
+	return gatewayAcceptance(&gw), nil
+}
+
+// gatewayAcceptance reads the Gateway's Programmed condition. Pending or a condition of an
+// older generation means the AWS Load Balancer Controller hasn't caught up yet.
+func gatewayAcceptance(gw *gwapiv1.Gateway) listenerAcceptance {
+	cond := meta.FindStatusCondition(gw.Status.Conditions, string(gwapiv1.GatewayConditionProgrammed))
+	switch {
+	case cond == nil || cond.ObservedGeneration < gw.Generation:
+		return listenerAcceptance{Message: fmt.Sprintf("Waiting for the AWS Load Balancer Controller to program Gateway %s", gw.Name)}
+	case cond.Status == metav1.ConditionTrue:
+		return listenerAcceptance{Accepted: true}
+	case cond.Reason == string(gwapiv1.GatewayReasonPending):
+		return listenerAcceptance{Message: fmt.Sprintf("Gateway %s is not programmed yet: %s", gw.Name, cond.Message)}
+	default:
+		return listenerAcceptance{Rejected: true, Message: fmt.Sprintf("Gateway %s is not programmed (%s): %s", gw.Name, cond.Reason, cond.Message)}
+	}
+}
+
+// loadBalancerConfigurationAcceptance reads the status of a LoadBalancerConfiguration.
+// Versions of the AWS Load Balancer Controller that don't report one return false, and the
+// Gateway's status decides alone.
+func loadBalancerConfigurationAcceptance(lbc *unstructured.Unstructured) (listenerAcceptance, bool) {
+	status, ok := lbc.Object["status"].(map[string]interface{})
+	if !ok {
+		return listenerAcceptance{}, false
+	}
+	observed, hasObserved, _ := unstructured.NestedInt64(status, "observedGeneration")
+	var conds []metav1.Condition
+	if raw, found, _ := unstructured.NestedSlice(status, "conditions"); found {
+		for _, item := range raw {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			var cond metav1.Condition
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &cond); err == nil {
+				conds = append(conds, cond)
+			}
+		}
+	}
+	cond := meta.FindStatusCondition(conds, "Accepted")
+	if !hasObserved && cond == nil {
+		return listenerAcceptance{}, false
+	}
+
+	if hasObserved && observed < lbc.GetGeneration() ||
+		cond != nil && cond.ObservedGeneration != 0 && cond.ObservedGeneration < lbc.GetGeneration() {
+		return listenerAcceptance{Message: fmt.Sprintf("Waiting for the AWS Load Balancer Controller to process LoadBalancerConfiguration %s", lbc.GetName())}, true
+	}
+	if cond != nil && cond.Status != metav1.ConditionTrue {
+		return listenerAcceptance{Rejected: cond.Status == metav1.ConditionFalse,
+			Message: fmt.Sprintf("LoadBalancerConfiguration %s is not accepted (%s): %s", lbc.GetName(), cond.Reason, cond.Message)}, true
+	}
+	return listenerAcceptance{Accepted: true}, true
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+func TestReconcileNormal_ListenerAttachedWaitsForLoadBalancerController(t *testing.T) {
+	ctx := context.Background()
+	hostnameType := gwapiv1.HostnameAddressType
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "gw-01", Namespace: "edge", Generation: 2,
+			Annotations: map[string]string{AnnotationVisibility: "internet-facing"},
+		},
+		Spec: gwapiv1.GatewaySpec{GatewayClassName: "aws-alb"},
+		Status: gwapiv1.GatewayStatus{
+			Addresses: []gwapiv1.GatewayStatusAddress{
+				{Type: &hostnameType, Value: "k8s-gw01-abcdef1234-1234567890.us-east-1.elb.amazonaws.com"},
+			},
+			// Programmed for the previous generation only
+			Conditions: []metav1.Condition{{Type: string(gwapiv1.GatewayConditionProgrammed), Status: metav1.ConditionTrue, ObservedGeneration: 1}},
+		},
+	}
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "redirect", Namespace: "default"},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+			Hostname: "old-brand.example.com",
+			ZoneId:   "Z123456",
+			TLS:      TLSDisabled,
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(gw, ghr).WithStatusSubresource(ghr, gw).Build()
+	route53Mock := &MockRoute53Client{records: make(map[string][]aws.DNSRecord)}
+	r := &GatewayHostnameRequestReconciler{
+		Client:        c,
+		Scheme:        getTestScheme(),
+		Recorder:      record.NewFakeRecorder(50),
+		ACMClient:     &MockACMClient{certificates: make(map[string]string)},
+		Route53Client: route53Mock,
+		GatewayPool:   gateway.NewPool(c, "edge", "aws-alb", 0, 0),
+		ListenerMode:  ListenerModeHostname,
+	}
+
+	result, err := r.reconcileNormal(ctx, ghr)
+	require.NoError(t, err)
+	assert.Equal(t, listenerAcceptancePollInterval, result.RequeueAfter)
+	assert.Equal(t, "gw-01", ghr.Status.AssignedGateway)
+	assert.True(t, conditions.HasReason(ghr.Status.Conditions, ConditionTypeListenerAttached, metav1.ConditionFalse, conditions.ReasonPendingAcceptance))
+	assert.Empty(t, route53Mock.records["Z123456"], "DNS waits for the ALB to serve the hostname")
+
+	// The LoadBalancerConfiguration reports its current generation invalid
+	lbc := &unstructured.Unstructured{}
+	lbc.SetGroupVersionKind(LoadBalancerConfigurationGVK)
+	lbcName, err := r.loadBalancerConfigurationName("gw-01")
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Name: lbcName, Namespace: "edge"}, lbc))
+	lbc.Object["status"] = map[string]interface{}{
+		"observedGeneration": lbc.GetGeneration(),
+		"conditions": []interface{}{map[string]interface{}{
+			"type": "Accepted", "status": "False", "reason": "InvalidConfiguration", "message": "certificate not found",
+			"lastTransitionTime": "2026-01-01T00:00:00Z",
+		}},
+	}
+	require.NoError(t, c.Update(ctx, lbc))
+
+	_, err = r.reconcileNormal(ctx, ghr)
+	require.NoError(t, err)
+	cond := meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeListenerAttached)
+	require.NotNil(t, cond)
+	assert.Equal(t, string(conditions.ReasonAttachmentRejected), cond.Reason)
+	assert.Contains(t, cond.Message, "(InvalidConfiguration): certificate not found")
+
+	// Both are processed
+	lbc.Object["status"].(map[string]interface{})["conditions"].([]interface{})[0].(map[string]interface{})["status"] = "True"
+	require.NoError(t, c.Update(ctx, lbc))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(gw), gw))
+	meta.SetStatusCondition(&gw.Status.Conditions, metav1.Condition{
+		Type: string(gwapiv1.GatewayConditionProgrammed), Status: metav1.ConditionTrue,
+		Reason: string(gwapiv1.GatewayReasonProgrammed), ObservedGeneration: gw.Generation,
+	})
+	require.NoError(t, c.Status().Update(ctx, gw))
+
+	_, err = r.reconcileNormal(ctx, ghr)
+	require.NoError(t, err)
+	assert.True(t, meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeListenerAttached))
+	assert.True(t, meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeDnsAliasReady))
+	require.Len(t, route53Mock.records["Z123456"], 1)
+}
+
+func TestGatewayAcceptance(t *testing.T) {
+	gw := &gwapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gw-01", Generation: 3}}
+	assert.Equal(t, listenerAcceptance{Message: "Waiting for the AWS Load Balancer Controller to program Gateway gw-01"}, gatewayAcceptance(gw))
+
+	gw.Status.Conditions = []metav1.Condition{{Type: string(gwapiv1.GatewayConditionProgrammed), Status: metav1.ConditionFalse,
+		Reason: string(gwapiv1.GatewayReasonPending), Message: "provisioning", ObservedGeneration: 3}}
+	assert.False(t, gatewayAcceptance(gw).Rejected)
+
+	gw.Status.Conditions[0].Reason = string(gwapiv1.GatewayReasonInvalid)
+	assert.True(t, gatewayAcceptance(gw).Rejected)
+
+	gw.Status.Conditions[0].Status = metav1.ConditionTrue
+	assert.True(t, gatewayAcceptance(gw).Accepted)
+}
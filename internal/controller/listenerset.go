@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapisxv1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+//+kubebuilder:rbac:groups=gateway.networking.x-k8s.io,resources=xlistenersets,verbs=get;list;watch;create;update;delete
+
+// allowListenerSets lets ListenerSets in the Gateway's own namespace attach to it.
+// Returns true if gw.Spec changed.
+func allowListenerSets(gw *gwapiv1.Gateway) bool {
+	if a := gw.Spec.AllowedListeners; a != nil && a.Namespaces != nil && a.Namespaces.From != nil &&
+		*a.Namespaces.From == gwapiv1.NamespacesFromSame {
+		return false
+	}
+	same := gwapiv1.NamespacesFromSame
+	gw.Spec.AllowedListeners = &gwapiv1.AllowedListeners{
+		Namespaces: &gwapiv1.ListenerNamespaces{From: &same},
+	}
+	return true
+}
+
+// syncListenerSet writes the Gateway's per-hostname listeners for the desired hostnames to its
+// XListenerSet. A ListenerSet needs at least one listener, so it is deleted once the last
+// hostname leaves the Gateway.
+func (r *GatewayHostnameRequestReconciler) syncListenerSet(ctx context.Context, gw *gwapiv1.Gateway, desired map[string]bool) error {
+	logger := log.FromContext(ctx)
+	key := types.NamespacedName{Name: gateway.ListenerSetName(gw.Name), Namespace: gw.Namespace}
+
+	hostnames := make([]string, 0, len(desired))
+	for hostname := range desired {
+		hostnames = append(hostnames, hostname)
+	}
+	sort.Strings(hostnames)
+	listeners := make([]gwapisxv1alpha1.ListenerEntry, 0, len(hostnames))
+	for _, hostname := range hostnames {
+		listeners = append(listeners, r.GatewayPool.HostnameListenerEntry(hostname))
+	}
+
+	var ls gwapisxv1alpha1.XListenerSet
+	err := r.Get(ctx, key, &ls)
+	if apierrors.IsNotFound(err) {
+		if len(listeners) == 0 {
+			return nil
+		}
+		group := gwapisxv1alpha1.Group(gwapiv1.GroupName)
+		kind := gwapisxv1alpha1.Kind("Gateway")
+		ls = gwapisxv1alpha1.XListenerSet{}
+		ls.Name = key.Name
+		ls.Namespace = key.Namespace
+		ls.Labels = map[string]string{LabelManagedBy: "gateway-orchestrator"}
+		// Owned by the Gateway so it goes away with it
+		ls.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: gwapiv1.GroupVersion.String(),
+			Kind:       "Gateway",
+			Name:       gw.Name,
+			UID:        gw.UID,
+		}}
+		ls.Spec = gwapisxv1alpha1.ListenerSetSpec{
+			ParentRef: gwapisxv1alpha1.ParentGatewayReference{Group: &group, Kind: &kind, Name: gwapisxv1alpha1.ObjectName(gw.Name)},
+			Listeners: listeners,
+		}
+		if err := r.Create(ctx, &ls); err != nil {
+			return fmt.Errorf("failed to create XListenerSet: %w", err)
+		}
+		logger.Info("Created XListenerSet for hostname listeners", "name", key.Name, "gateway", gw.Name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get XListenerSet: %w", err)
+	}
+
+	if len(listeners) == 0 {
+		if err := r.Delete(ctx, &ls); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete XListenerSet: %w", err)
+		}
+		logger.Info("Deleted XListenerSet without hostnames", "name", key.Name, "gateway", gw.Name)
+		return nil
+	}
+	if equality.Semantic.DeepEqual(ls.Spec.Listeners, listeners) {
+		return nil
+	}
+	ls.Spec.Listeners = listeners
+	if err := r.Update(ctx, &ls); err != nil {
+		return fmt.Errorf("failed to update XListenerSet: %w", err)
+	}
+	return nil
+}
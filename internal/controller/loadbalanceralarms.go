@@ -0,0 +1,284 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+// Alarm thresholds used unless configured
+const (
+	// DefaultAlarmErrorRatePercent is the share of requests answered with a 5xx, by the ALB or
+	// the targets, above which the 5xx alarm fires
+	DefaultAlarmErrorRatePercent = 5.0
+
+	// DefaultAlarmConnectionErrors is the number of failed connections to targets per minute
+	// above which the connection error alarm fires
+	DefaultAlarmConnectionErrors = 10.0
+)
+
+// Alarms created per Gateway; the kind is the last segment of the alarm name
+const (
+	alarmKind5xxRate          = "5xx-rate"
+	alarmKindConnectionErrors = "target-connection-errors"
+	alarmKindUnhealthyHosts   = "unhealthy-hosts"
+)
+
+// alarmNamePrefix starts the names of all alarms created by the controller
+const alarmNamePrefix = "gateway-orchestrator/"
+
+// LoadBalancerAlarms keeps a baseline set of CloudWatch alarms on the ALB of every managed
+// Gateway: 5xx rate, target connection errors and unhealthy hosts. Alarms are named
+// gateway-orchestrator/[<cluster>/]<namespace>/<gateway>/<kind>, and those of Gateways that no
+// longer exist are deleted. It runs as a manager Runnable.
+type LoadBalancerAlarms struct {
+	GatewayPool   *gateway.Pool
+	LoadBalancers aws.LoadBalancerClient
+	Alarms        aws.AlarmClient
+	Interval      time.Duration
+
+	// Cluster tells the alarms of controllers sharing an AWS account apart; set it to
+	// --cluster-name
+	Cluster string
+
+	// Actions are notified when an alarm fires and when it recovers, e.g. SNS topic ARNs
+	Actions []string
+
+	// ErrorRatePercent defaults to DefaultAlarmErrorRatePercent
+	ErrorRatePercent float64
+
+	// ConnectionErrors defaults to DefaultAlarmConnectionErrors
+	ConnectionErrors float64
+
+	// written remembers the alarms last written, by name, so unchanged alarms aren't written
+	// on every check
+	written map[string]string
+}
+
+// ParseAlarmActions parses a comma-separated list of alarm action ARNs, e.g. SNS topics
+func ParseAlarmActions(s string) ([]string, error) {
+	var actions []string
+	for _, action := range strings.Split(s, ",") {
+		action = strings.TrimSpace(action)
+		if action == "" {
+			continue
+		}
+		if !strings.HasPrefix(action, "arn:") {
+			return nil, fmt.Errorf("alarm action %q is not an ARN", action)
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// Start implements manager.Runnable
+func (a *LoadBalancerAlarms) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("load-balancer-alarms")
+	logger.Info("Starting load balancer alarms", "interval", a.Interval)
+
+	ticker := time.NewTicker(a.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := a.Sync(ctx); err != nil {
+			logger.Error(err, "Load balancer alarm sync failed")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync writes the alarms of every Gateway with an ALB and deletes the alarms of Gateways that
+// are gone or being deleted. Gateways whose ALB isn't provisioned yet keep their alarms.
+func (a *LoadBalancerAlarms) Sync(ctx context.Context) error {
+	logger := log.FromContext(ctx)
+
+	gateways, err := a.GatewayPool.ListGateways(ctx)
+	if err != nil {
+		return err
+	}
+	loadBalancers, err := a.LoadBalancers.DescribeLoadBalancers(ctx)
+	if err != nil {
+		return err
+	}
+	byDNSName := make(map[string]aws.LoadBalancer, len(loadBalancers))
+	for _, lb := range loadBalancers {
+		byDNSName[strings.ToLower(lb.DNSName)] = lb
+	}
+	if a.written == nil {
+		a.written = map[string]string{}
+	}
+
+	live := map[string]bool{}
+	for i := range gateways {
+		gw := &gateways[i]
+		if !gw.DeletionTimestamp.IsZero() {
+			continue
+		}
+		key := gw.Namespace + "/" + gw.Name
+		live[key] = true
+
+		lb, ok := byDNSName[strings.ToLower(a.GatewayPool.Info(gw).LoadBalancerDNS)]
+		dimension := loadBalancerDimension(lb.Arn)
+		if !ok || dimension == "" {
+			continue
+		}
+		for _, alarm := range a.desiredAlarms(gw.Namespace, gw.Name, dimension) {
+			spec, _ := json.Marshal(alarm)
+			if a.written[alarm.Name] == string(spec) {
+				continue
+			}
+			if err := a.Alarms.PutMetricAlarm(ctx, alarm); err != nil {
+				return err
+			}
+			a.written[alarm.Name] = string(spec)
+			logger.Info("Wrote load balancer alarm", "alarm", alarm.Name, "gateway", key)
+		}
+	}
+
+	names, err := a.Alarms.DescribeAlarmNames(ctx, a.prefix())
+	if err != nil {
+		return err
+	}
+	var orphaned []string
+	for _, name := range names {
+		if key, ok := a.alarmGateway(name); ok && !live[key] {
+			orphaned = append(orphaned, name)
+		}
+	}
+	if len(orphaned) == 0 {
+		return nil
+	}
+	sort.Strings(orphaned)
+	if err := a.Alarms.DeleteAlarms(ctx, orphaned); err != nil {
+		return err
+	}
+	for _, name := range orphaned {
+		delete(a.written, name)
+	}
+	logger.Info("Deleted alarms of removed Gateways", "alarms", orphaned)
+	return nil
+}
+
+// prefix returns the name prefix of this controller's alarms
+func (a *LoadBalancerAlarms) prefix() string {
+	if a.Cluster == "" {
+		return alarmNamePrefix
+	}
+	return alarmNamePrefix + a.Cluster + "/"
+}
+
+// alarmName returns the name of a Gateway's alarm of the given kind
+func (a *LoadBalancerAlarms) alarmName(namespace, name, kind string) string {
+	return a.prefix() + namespace + "/" + name + "/" + kind
+}
+
+// alarmGateway returns the Gateway (namespace/name) an alarm of this controller belongs to.
+// Without a cluster name the prefix also matches other clusters' alarms, which have one
+// segment more and are skipped.
+func (a *LoadBalancerAlarms) alarmGateway(alarmName string) (string, bool) {
+	parts := strings.Split(strings.TrimPrefix(alarmName, a.prefix()), "/")
+	if len(parts) != 3 {
+		return "", false
+	}
+	return parts[0] + "/" + parts[1], true
+}
+
+// loadBalancerDimension returns the CloudWatch LoadBalancer dimension (app/<name>/<id>) of an
+// ALB ARN, or "" for other load balancer types
+func loadBalancerDimension(arn string) string {
+	_, dimension, ok := strings.Cut(arn, ":loadbalancer/")
+	if !ok || !strings.HasPrefix(dimension, "app/") {
+		return ""
+	}
+	return dimension
+}
+
+// desiredAlarms returns the alarms of the Gateway whose ALB has the given dimension
+func (a *LoadBalancerAlarms) desiredAlarms(namespace, name, dimension string) []aws.MetricAlarm {
+	errorRate := a.ErrorRatePercent
+	if errorRate <= 0 {
+		errorRate = DefaultAlarmErrorRatePercent
+	}
+	connectionErrors := a.ConnectionErrors
+	if connectionErrors <= 0 {
+		connectionErrors = DefaultAlarmConnectionErrors
+	}
+	key := namespace + "/" + name
+	tags := map[string]string{
+		"managed-by": certificateManagedBy,
+		"gateway":    key,
+	}
+	if a.Cluster != "" {
+		tags["cluster"] = a.Cluster
+	}
+	metric := func(id, metricName string) aws.MetricQuery {
+		return aws.MetricQuery{
+			ID:         id,
+			Namespace:  "AWS/ApplicationELB",
+			MetricName: metricName,
+			Dimensions: map[string]string{"LoadBalancer": dimension},
+			Stat:       "Sum",
+			Period:     60,
+		}
+	}
+	connections := metric("m1", "TargetConnectionErrorCount")
+	connections.ReturnData = true
+
+	return []aws.MetricAlarm{
+		{
+			Name:        a.alarmName(namespace, name, alarmKind5xxRate),
+			Description: fmt.Sprintf("More than %g%% of the requests to Gateway %s answered with 5xx", errorRate, key),
+			Metrics: []aws.MetricQuery{
+				metric("m1", "HTTPCode_ELB_5XX_Count"),
+				metric("m2", "HTTPCode_Target_5XX_Count"),
+				metric("m3", "RequestCount"),
+				{ID: "e1", Label: "5xx %", ReturnData: true, Expression: "IF(m3 > 0, 100 * (FILL(m1, 0) + FILL(m2, 0)) / m3, 0)"},
+			},
+			EvaluationPeriods:  5,
+			Threshold:          errorRate,
+			ComparisonOperator: "GreaterThanThreshold",
+			TreatMissingData:   "notBreaching",
+			AlarmActions:       a.Actions,
+			Tags:               tags,
+		},
+		{
+			Name:               a.alarmName(namespace, name, alarmKindConnectionErrors),
+			Description:        fmt.Sprintf("Gateway %s fails more than %g connections to targets per minute", key, connectionErrors),
+			Metrics:            []aws.MetricQuery{connections},
+			EvaluationPeriods:  5,
+			Threshold:          connectionErrors,
+			ComparisonOperator: "GreaterThanThreshold",
+			TreatMissingData:   "notBreaching",
+			AlarmActions:       a.Actions,
+			Tags:               tags,
+		},
+		{
+			Name:        a.alarmName(namespace, name, alarmKindUnhealthyHosts),
+			Description: fmt.Sprintf("A target group of Gateway %s has unhealthy targets", key),
+			Metrics: []aws.MetricQuery{{
+				ID:         "q1",
+				ReturnData: true,
+				Expression: fmt.Sprintf(`SELECT MAX(UnHealthyHostCount) FROM SCHEMA("AWS/ApplicationELB", LoadBalancer, TargetGroup) WHERE LoadBalancer = '%s'`, dimension),
+				Period:     60,
+			}},
+			EvaluationPeriods:  3,
+			Threshold:          0,
+			ComparisonOperator: "GreaterThanThreshold",
+			TreatMissingData:   "notBreaching",
+			AlarmActions:       a.Actions,
+			Tags:               tags,
+		},
+	}
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+func TestLoadBalancerAlarms_Sync(t *testing.T) {
+	ctx := context.Background()
+	withALB := gatewayWithLoadBalancer("gw-01", "k8s-edge-gw01-1.eu-west-1.elb.amazonaws.com")
+	provisioning := gatewayWithLoadBalancer("gw-02", "")
+	deleting := gatewayWithLoadBalancer("gw-03", "k8s-edge-gw03-3.eu-west-1.elb.amazonaws.com")
+	deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	deleting.Finalizers = []string{"test"}
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(withALB, provisioning, deleting).Build()
+
+	alarms := &aws.MockAlarmClient{Alarms: map[string]aws.MetricAlarm{
+		"gateway-orchestrator/prod/edge/gw-02/5xx-rate":        {},
+		"gateway-orchestrator/prod/edge/gw-03/5xx-rate":        {},
+		"gateway-orchestrator/prod/edge/gw-09/unhealthy-hosts": {},
+		"gateway-orchestrator/staging/edge/gw-09/5xx-rate":     {},
+	}}
+	a := &LoadBalancerAlarms{
+		GatewayPool: gateway.NewPool(c, "edge", "aws-alb", 0, 0),
+		LoadBalancers: &aws.MockLoadBalancerClient{LoadBalancers: []aws.LoadBalancer{
+			{Arn: "arn:aws:elasticloadbalancing:eu-west-1:123456789012:loadbalancer/app/gw01/1", DNSName: "k8s-edge-gw01-1.eu-west-1.elb.amazonaws.com"},
+			{Arn: "arn:aws:elasticloadbalancing:eu-west-1:123456789012:loadbalancer/app/gw03/3", DNSName: "k8s-edge-gw03-3.eu-west-1.elb.amazonaws.com"},
+		}},
+		Alarms:  alarms,
+		Cluster: "prod",
+		Actions: []string{"arn:aws:sns:eu-west-1:123456789012:edge"},
+	}
+
+	require.NoError(t, a.Sync(ctx))
+	var names []string
+	for name := range alarms.Alarms {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{
+		"gateway-orchestrator/prod/edge/gw-01/5xx-rate",
+		"gateway-orchestrator/prod/edge/gw-01/target-connection-errors",
+		"gateway-orchestrator/prod/edge/gw-01/unhealthy-hosts",
+		// The ALB isn't provisioned yet: alarms are kept
+		"gateway-orchestrator/prod/edge/gw-02/5xx-rate",
+		// Another cluster's alarms are not touched
+		"gateway-orchestrator/staging/edge/gw-09/5xx-rate",
+	}, names)
+
+	rate := alarms.Alarms["gateway-orchestrator/prod/edge/gw-01/5xx-rate"]
+	assert.Equal(t, DefaultAlarmErrorRatePercent, rate.Threshold)
+	assert.Equal(t, "app/gw01/1", rate.Metrics[0].Dimensions["LoadBalancer"])
+	assert.Equal(t, "edge/gw-01", rate.Tags["gateway"])
+	assert.Equal(t, a.Actions, rate.AlarmActions)
+	assert.Contains(t, alarms.Alarms["gateway-orchestrator/prod/edge/gw-01/unhealthy-hosts"].Metrics[0].Expression, "LoadBalancer = 'app/gw01/1'")
+
+	// Unchanged alarms are not written again
+	puts := alarms.Puts
+	require.NoError(t, a.Sync(ctx))
+	assert.Equal(t, puts, alarms.Puts)
+}
+
+func TestLoadBalancerAlarms_AlarmGatewayWithoutCluster(t *testing.T) {
+	a := &LoadBalancerAlarms{}
+	key, ok := a.alarmGateway("gateway-orchestrator/edge/gw-01/5xx-rate")
+	assert.True(t, ok)
+	assert.Equal(t, "edge/gw-01", key)
+
+	_, ok = a.alarmGateway("gateway-orchestrator/prod/edge/gw-01/5xx-rate")
+	assert.False(t, ok, "alarms of a named cluster belong to another controller")
+}
+
+func TestLoadBalancerDimension(t *testing.T) {
+	assert.Equal(t, "app/gw01/50dc6c495c0c9188", loadBalancerDimension("arn:aws:elasticloadbalancing:eu-west-1:123456789012:loadbalancer/app/gw01/50dc6c495c0c9188"))
+	assert.Empty(t, loadBalancerDimension("arn:aws:elasticloadbalancing:eu-west-1:123456789012:loadbalancer/net/nlb/1"))
+	assert.Empty(t, loadBalancerDimension(""))
+}
+
+func TestParseAlarmActions(t *testing.T) {
+	actions, err := ParseAlarmActions(" arn:aws:sns:eu-west-1:123456789012:edge, ,arn:aws:sns:eu-west-1:123456789012:oncall")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"arn:aws:sns:eu-west-1:123456789012:edge", "arn:aws:sns:eu-west-1:123456789012:oncall"}, actions)
+
+	_, err = ParseAlarmActions("edge-topic")
+	assert.Error(t, err)
+}
@@ -3,15 +3,17 @@ package controller
 import (
 	"context"
 	"fmt"
-	"sort"
+	"slices"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
 )
 
 // LoadBalancerConfigurationGVK is the GVK for AWS LoadBalancerConfiguration
@@ -31,35 +33,68 @@ func (r *GatewayHostnameRequestReconciler) ensureLoadBalancerConfiguration(
 	certificateARNs []string,
 	visibility string,
 	wafArn string,
+) error {
+	httpsOnly, err := r.gatewayHTTPSOnly(ctx, gatewayName, gatewayNamespace)
+	if err != nil {
+		return err
+	}
+	return r.writeLoadBalancerConfiguration(ctx, gatewayName, gatewayNamespace, certificateARNs, visibility, wafArn, httpsOnly)
+}
+
+// gatewayHTTPSOnly reports whether the Gateway has no HTTP listener. Gateways that don't exist
+// yet follow the pool's setting, which CreateGateway applies to them.
+func (r *GatewayHostnameRequestReconciler) gatewayHTTPSOnly(ctx context.Context, gatewayName, gatewayNamespace string) (bool, error) {
+	var gw gwapiv1.Gateway
+	if err := r.Get(ctx, types.NamespacedName{Name: gatewayName, Namespace: gatewayNamespace}, &gw); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.GatewayPool != nil && r.GatewayPool.HTTPSOnly(), nil
+		}
+		return false, fmt.Errorf("failed to get Gateway %s: %w", gatewayName, err)
+	}
+	return gateway.IsHTTPSOnly(&gw), nil
+}
+
+// writeLoadBalancerConfiguration creates or updates the LoadBalancerConfiguration like
+// ensureLoadBalancerConfiguration, leaving out the HTTP listener if httpsOnly is set
+func (r *GatewayHostnameRequestReconciler) writeLoadBalancerConfiguration(
+	ctx context.Context,
+	gatewayName string,
+	gatewayNamespace string,
+	certificateARNs []string,
+	visibility string,
+	wafArn string,
+	httpsOnly bool,
 ) error {
 	logger := log.FromContext(ctx)
 
-	configName := fmt.Sprintf("%s-config", gatewayName)
+	configName, err := r.loadBalancerConfigurationName(gatewayName)
+	if err != nil {
+		return err
+	}
 
 	// Build the LoadBalancerConfiguration
-	lbConfig := &unstructured.Unstructured{}
-	lbConfig.SetGroupVersionKind(LoadBalancerConfigurationGVK)
+	lbConfig := r.newLoadBalancerConfiguration()
 	lbConfig.SetName(configName)
 	lbConfig.SetNamespace(gatewayNamespace)
 
 	// Try to get existing config
-	existingConfig := &unstructured.Unstructured{}
-	existingConfig.SetGroupVersionKind(LoadBalancerConfigurationGVK)
-	err := r.Get(ctx, types.NamespacedName{Name: configName, Namespace: gatewayNamespace}, existingConfig)
+	existingConfig, err := r.getLoadBalancerConfiguration(ctx, types.NamespacedName{Name: configName, Namespace: gatewayNamespace})
 	if err != nil && !apierrors.IsNotFound(err) {
 		return fmt.Errorf("failed to get LoadBalancerConfiguration %s: %w", configName, err)
 	}
 	notFound := apierrors.IsNotFound(err)
+	if !notFound {
+		if err := foreignOwnership("LoadBalancerConfiguration", existingConfig); err != nil {
+			return err
+		}
+	}
 
 	// Build listener configuration with certificates
 	listenerConfigs := []interface{}{}
 
 	if len(certificateARNs) > 0 {
 		// Sort certificates for deterministic ordering (ensures same default cert on each reconcile)
-		// Make a copy to avoid mutating the input slice
-		sortedCerts := make([]string, len(certificateARNs))
-		copy(sortedCerts, certificateARNs)
-		sort.Strings(sortedCerts)
+		sortedCerts := listenerCertificates(certificateARNs)
 
 		// HTTPS listener with certificates
 		httpsListener := map[string]interface{}{
@@ -78,11 +113,13 @@ func (r *GatewayHostnameRequestReconciler) ensureLoadBalancerConfiguration(
 		listenerConfigs = append(listenerConfigs, httpsListener)
 	}
 
-	// HTTP listener (no certs needed)
-	httpListener := map[string]interface{}{
-		"protocolPort": fmt.Sprintf("HTTP:%d", r.httpPort()),
+	// HTTP listener (no certs needed), unless the Gateway is HTTPS-only
+	if !httpsOnly {
+		httpListener := map[string]interface{}{
+			"protocolPort": fmt.Sprintf("HTTP:%d", r.httpPort()),
+		}
+		listenerConfigs = append(listenerConfigs, httpListener)
 	}
-	listenerConfigs = append(listenerConfigs, httpListener)
 
 	// Build spec
 	spec := map[string]interface{}{
@@ -90,6 +127,42 @@ func (r *GatewayHostnameRequestReconciler) ensureLoadBalancerConfiguration(
 		"listenerConfigurations": listenerConfigs,
 	}
 
+	// Only set ipAddressType when configured, so the AWS Load Balancer Controller default applies otherwise
+	if r.IPAddressType != "" {
+		spec["ipAddressType"] = r.IPAddressType
+	}
+
+	// Tag the ALB so other tools recognize it
+	if len(r.InteropTags) > 0 {
+		tags := make(map[string]interface{}, len(r.InteropTags))
+		for k, v := range r.InteropTags {
+			tags[k] = v
+		}
+		spec["tags"] = tags
+	}
+
+	// Subnets only apply when the OrchestratorConfig lists them; otherwise the AWS Load Balancer
+	// Controller discovers them from the subnet tags
+	if settings := r.settings(); settings != nil && len(settings.Subnets[visibility]) > 0 {
+		subnets := make([]interface{}, len(settings.Subnets[visibility]))
+		for i, id := range settings.Subnets[visibility] {
+			subnets[i] = map[string]interface{}{"identifier": id}
+		}
+		spec["loadBalancerSubnets"] = subnets
+	}
+
+	var attributes []interface{}
+	if r.AccessLogs != nil {
+		attributes = append(attributes, r.AccessLogs.loadBalancerAttributes()...)
+	}
+	// ARC only shifts load balancers that opted in
+	if r.ZonalShift {
+		attributes = append(attributes, map[string]interface{}{"key": "zonal_shift.config.enabled", "value": "true"})
+	}
+	if len(attributes) > 0 {
+		spec["loadBalancerAttributes"] = attributes
+	}
+
 	// Add WAF if specified
 	if wafArn != "" {
 		spec["wafV2"] = map[string]interface{}{
@@ -98,16 +171,34 @@ func (r *GatewayHostnameRequestReconciler) ensureLoadBalancerConfiguration(
 	}
 
 	if notFound {
-		// Create new config
+		// Create new config. The ALB name is only set here: renaming an ALB replaces it.
+		name, err := r.naming().LoadBalancerName(gatewayName, gatewayNamespace)
+		if err != nil {
+			return err
+		}
+		if name != "" {
+			spec["loadBalancerName"] = name
+		}
 		lbConfig.Object["spec"] = spec
+		setAppliedSpec(lbConfig, spec)
+		stampManaged(lbConfig, spec, 1)
 
 		if err := r.Create(ctx, lbConfig); err != nil {
 			return fmt.Errorf("failed to create LoadBalancerConfiguration %s: %w", configName, err)
 		}
 		logger.Info("Created LoadBalancerConfiguration", "name", configName, "certificates", len(certificateARNs))
 	} else {
-		// Update existing config
+		// Update existing config, keeping the name of its ALB
+		if name, ok, _ := unstructured.NestedString(existingConfig.Object, "spec", "loadBalancerName"); ok {
+			spec["loadBalancerName"] = name
+		}
+		generation := existingConfig.GetGeneration()
+		if current, _, _ := unstructured.NestedMap(existingConfig.Object, "spec"); managedChecksum(current) != managedChecksum(spec) {
+			generation++
+		}
 		existingConfig.Object["spec"] = spec
+		setAppliedSpec(existingConfig, spec)
+		stampManaged(existingConfig, spec, generation)
 		if err := r.Update(ctx, existingConfig); err != nil {
 			return fmt.Errorf("failed to update LoadBalancerConfiguration %s: %w", configName, err)
 		}
@@ -119,6 +210,13 @@ func (r *GatewayHostnameRequestReconciler) ensureLoadBalancerConfiguration(
 
 // getGatewayCertificateARNs collects all certificate ARNs from GatewayHostnameRequests assigned to a Gateway
 func (r *GatewayHostnameRequestReconciler) getGatewayCertificateARNs(ctx context.Context, gatewayName, gatewayNamespace string) ([]string, error) {
+	return r.gatewayCertificateARNsWithout(ctx, gatewayName, gatewayNamespace, nil)
+}
+
+// gatewayCertificateARNsWithout collects the certificate ARNs of the requests on a Gateway
+// other than leaving, which may still be listed on it in the cache. A certificate leaving
+// shares with another request, such as an expanded certificate, stays on the Gateway.
+func (r *GatewayHostnameRequestReconciler) gatewayCertificateARNsWithout(ctx context.Context, gatewayName, gatewayNamespace string, leaving *gatewayv1alpha1.GatewayHostnameRequest) ([]string, error) {
 	// List all GatewayHostnameRequests
 	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
 	if err := r.List(ctx, &ghrList); err != nil {
@@ -132,16 +230,37 @@ func (r *GatewayHostnameRequestReconciler) getGatewayCertificateARNs(ctx context
 		if !ghr.DeletionTimestamp.IsZero() {
 			continue
 		}
-		if ghr.Status.AssignedGateway == gatewayName &&
-			ghr.Status.AssignedGatewayNamespace == gatewayNamespace &&
-			ghr.Status.CertificateArn != "" {
-			arns = append(arns, ghr.Status.CertificateArn)
+		if leaving != nil && ghr.Namespace == leaving.Namespace && ghr.Name == leaving.Name {
+			continue
+		}
+		if ghr.Status.CertificateArn == "" {
+			continue
+		}
+		// Hostnames being moved off this Gateway keep their certificate until DNS has switched
+		if (ghr.Status.AssignedGateway == gatewayName && ghr.Status.AssignedGatewayNamespace == gatewayNamespace) ||
+			(ghr.Status.MigratingFromGateway == gatewayName && migratingFromNamespace(&ghr) == gatewayNamespace) {
+			if !slices.Contains(arns, ghr.Status.CertificateArn) {
+				arns = append(arns, ghr.Status.CertificateArn)
+			}
 		}
 	}
 
 	return arns, nil
 }
 
+// naming returns how the pool names Gateways and the resources created for them
+func (r *GatewayHostnameRequestReconciler) naming() *gateway.Naming {
+	if r.GatewayPool != nil {
+		return r.GatewayPool.Naming()
+	}
+	return gateway.DefaultNaming()
+}
+
+// loadBalancerConfigurationName returns the name of the Gateway's LoadBalancerConfiguration
+func (r *GatewayHostnameRequestReconciler) loadBalancerConfigurationName(gatewayName string) (string, error) {
+	return r.naming().LoadBalancerConfigurationName(gatewayName)
+}
+
 // httpPort returns the configured HTTP listener port, defaulting to 80
 func (r *GatewayHostnameRequestReconciler) httpPort() int32 {
 	if r.GatewayPool != nil {
@@ -161,10 +280,12 @@ func (r *GatewayHostnameRequestReconciler) httpsPort() int32 {
 // deleteLoadBalancerConfiguration removes the LoadBalancerConfiguration for a Gateway
 func (r *GatewayHostnameRequestReconciler) deleteLoadBalancerConfiguration(ctx context.Context, gatewayName, gatewayNamespace string) error {
 	logger := log.FromContext(ctx)
-	configName := fmt.Sprintf("%s-config", gatewayName)
+	configName, err := r.loadBalancerConfigurationName(gatewayName)
+	if err != nil {
+		return err
+	}
 
-	config := &unstructured.Unstructured{}
-	config.SetGroupVersionKind(LoadBalancerConfigurationGVK)
+	config := r.newLoadBalancerConfiguration()
 	config.SetName(configName)
 	config.SetNamespace(gatewayNamespace)
 
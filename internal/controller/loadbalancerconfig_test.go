@@ -8,6 +8,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
 	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
@@ -19,6 +20,7 @@ import (
 func TestEnsureLoadBalancerConfiguration_DoesNotIncludeTargetGroupConfiguration(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = gatewayv1alpha1.AddToScheme(scheme)
+	_ = gwapiv1.AddToScheme(scheme)
 
 	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
 
@@ -61,6 +63,7 @@ func TestEnsureLoadBalancerConfiguration_DoesNotIncludeTargetGroupConfiguration(
 func TestEnsureLoadBalancerConfiguration_CustomPorts(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = gatewayv1alpha1.AddToScheme(scheme)
+	_ = gwapiv1.AddToScheme(scheme)
 
 	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
 
@@ -121,6 +124,7 @@ func TestEnsureLoadBalancerConfiguration_CustomPorts(t *testing.T) {
 func TestEnsureLoadBalancerConfiguration_DefaultPorts(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = gatewayv1alpha1.AddToScheme(scheme)
+	_ = gwapiv1.AddToScheme(scheme)
 
 	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
 
@@ -165,6 +169,7 @@ func TestEnsureLoadBalancerConfiguration_DefaultPorts(t *testing.T) {
 func TestEnsureLoadBalancerConfiguration_SortsCertificatesForDeterminism(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = gatewayv1alpha1.AddToScheme(scheme)
+	_ = gwapiv1.AddToScheme(scheme)
 
 	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
 
@@ -268,3 +273,127 @@ func TestEnsureLoadBalancerConfiguration_SortsCertificatesForDeterminism(t *test
 	}
 }
 
+
+// TestEnsureLoadBalancerConfiguration_IPAddressType verifies that ipAddressType is only set when configured
+func TestEnsureLoadBalancerConfiguration_IPAddressType(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = gatewayv1alpha1.AddToScheme(scheme)
+	_ = gwapiv1.AddToScheme(scheme)
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	reconciler := &GatewayHostnameRequestReconciler{
+		Client: fakeClient,
+	}
+
+	ctx := context.Background()
+	certs := []string{"arn:aws:acm:eu-west-1:123456789012:certificate/test-cert"}
+
+	getIPAddressType := func() (string, bool) {
+		lbc := &unstructured.Unstructured{}
+		lbc.SetGroupVersionKind(LoadBalancerConfigurationGVK)
+		if err := fakeClient.Get(ctx, types.NamespacedName{Name: "gw-01-config", Namespace: "edge"}, lbc); err != nil {
+			t.Fatalf("LoadBalancerConfiguration not found: %v", err)
+		}
+		v, found, _ := unstructured.NestedString(lbc.Object, "spec", "ipAddressType")
+		return v, found
+	}
+
+	if err := reconciler.ensureLoadBalancerConfiguration(ctx, "gw-01", "edge", certs, "internet-facing", ""); err != nil {
+		t.Fatalf("ensureLoadBalancerConfiguration() error = %v", err)
+	}
+	if v, found := getIPAddressType(); found {
+		t.Errorf("ipAddressType = %q, want unset", v)
+	}
+
+	reconciler.IPAddressType = "dualstack"
+	if err := reconciler.ensureLoadBalancerConfiguration(ctx, "gw-01", "edge", certs, "internet-facing", ""); err != nil {
+		t.Fatalf("ensureLoadBalancerConfiguration() error = %v", err)
+	}
+	if v, _ := getIPAddressType(); v != "dualstack" {
+		t.Errorf("ipAddressType = %q, want dualstack", v)
+	}
+}
+
+// TestEnsureLoadBalancerConfiguration_Subnets verifies that the OrchestratorConfig's subnets of
+// the visibility are set, and nothing otherwise
+func TestEnsureLoadBalancerConfiguration_Subnets(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = gatewayv1alpha1.AddToScheme(scheme)
+	_ = gwapiv1.AddToScheme(scheme)
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	reconciler := &GatewayHostnameRequestReconciler{
+		Client:   fakeClient,
+		Settings: NewSettingsStore(Settings{Subnets: map[string][]string{"internal": {"subnet-0a1", "subnet-0b2"}}}),
+	}
+	ctx := context.Background()
+
+	getSubnets := func(name string) ([]interface{}, bool) {
+		lbc := &unstructured.Unstructured{}
+		lbc.SetGroupVersionKind(LoadBalancerConfigurationGVK)
+		if err := fakeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "edge"}, lbc); err != nil {
+			t.Fatalf("LoadBalancerConfiguration not found: %v", err)
+		}
+		v, found, _ := unstructured.NestedSlice(lbc.Object, "spec", "loadBalancerSubnets")
+		return v, found
+	}
+
+	if err := reconciler.ensureLoadBalancerConfiguration(ctx, "gw-01", "edge", nil, "internal", ""); err != nil {
+		t.Fatalf("ensureLoadBalancerConfiguration() error = %v", err)
+	}
+	subnets, _ := getSubnets("gw-01-config")
+	if len(subnets) != 2 || subnets[0].(map[string]interface{})["identifier"] != "subnet-0a1" {
+		t.Errorf("loadBalancerSubnets = %v, want subnet-0a1 and subnet-0b2", subnets)
+	}
+
+	if err := reconciler.ensureLoadBalancerConfiguration(ctx, "gw-02", "edge", nil, "internet-facing", ""); err != nil {
+		t.Fatalf("ensureLoadBalancerConfiguration() error = %v", err)
+	}
+	if v, found := getSubnets("gw-02-config"); found {
+		t.Errorf("loadBalancerSubnets = %v, want unset", v)
+	}
+}
+
+// TestEnsureLoadBalancerConfiguration_Naming verifies that the LBC is named by the pool's
+// naming, and that the ALB name is set on creation and never changed afterwards.
+func TestEnsureLoadBalancerConfiguration_Naming(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = gatewayv1alpha1.AddToScheme(scheme)
+	_ = gwapiv1.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	naming, err := gateway.NewNaming("eu1", "", "", "{{.Gateway}}-lbc", "", "{{.Cluster}}-{{.Gateway}}")
+	if err != nil {
+		t.Fatalf("NewNaming() error = %v", err)
+	}
+	pool := gateway.NewPool(fakeClient, "edge", "aws-alb", 0, 0)
+	pool.SetNaming(naming)
+	reconciler := &GatewayHostnameRequestReconciler{Client: fakeClient, GatewayPool: pool}
+
+	ctx := context.Background()
+	if err := reconciler.ensureLoadBalancerConfiguration(ctx, "gw-01", "edge", nil, "internet-facing", ""); err != nil {
+		t.Fatalf("ensureLoadBalancerConfiguration() error = %v", err)
+	}
+	lbc := &unstructured.Unstructured{}
+	lbc.SetGroupVersionKind(LoadBalancerConfigurationGVK)
+	key := types.NamespacedName{Name: "gw-01-lbc", Namespace: "edge"}
+	if err := fakeClient.Get(ctx, key, lbc); err != nil {
+		t.Fatalf("LoadBalancerConfiguration not found: %v", err)
+	}
+	if name, _, _ := unstructured.NestedString(lbc.Object, "spec", "loadBalancerName"); name != "eu1-gw-01" {
+		t.Errorf("loadBalancerName = %q, want eu1-gw-01", name)
+	}
+
+	// A later template change must not rename, and so replace, the existing ALB
+	naming, _ = gateway.NewNaming("eu2", "", "", "{{.Gateway}}-lbc", "", "{{.Cluster}}-{{.Gateway}}")
+	pool.SetNaming(naming)
+	if err := reconciler.ensureLoadBalancerConfiguration(ctx, "gw-01", "edge", nil, "internet-facing", ""); err != nil {
+		t.Fatalf("ensureLoadBalancerConfiguration() error = %v", err)
+	}
+	if err := fakeClient.Get(ctx, key, lbc); err != nil {
+		t.Fatalf("LoadBalancerConfiguration not found: %v", err)
+	}
+	if name, _, _ := unstructured.NestedString(lbc.Object, "spec", "loadBalancerName"); name != "eu1-gw-01" {
+		t.Errorf("loadBalancerName = %q after update, want eu1-gw-01", name)
+	}
+}
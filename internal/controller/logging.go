@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Reconcile steps of a GatewayHostnameRequest, logged as the "step" field. Their log
+// verbosity can be set with --log-step-verbosity.
+const (
+	StepDrift         = "drift"
+	StepValidate      = "validate"
+	StepClaim         = "claim"
+	StepCertificate   = "certificate"
+	StepDNSValidation = "dns-validation"
+	StepIssuance      = "issuance"
+	StepGateway       = "gateway"
+	StepDNS           = "dns"
+	StepRoutes        = "routes"
+	StepCapacity      = "capacity"
+	StepReady         = "ready"
+	StepDelete        = "delete"
+)
+
+// logStepFieldName is the log field naming the reconcile step
+const logStepFieldName = "step"
+
+// maxStepLogVerbosity bounds the verbosity of --log-step-verbosity
+const maxStepLogVerbosity = 10
+
+// LogSteps are the reconcile steps in the order they run
+var LogSteps = []string{StepDrift, StepValidate, StepClaim, StepCertificate, StepDNSValidation, StepIssuance,
+	StepGateway, StepDNS, StepRoutes, StepCapacity, StepReady, StepDelete}
+
+// logStepBaseKey holds the logger that logStep derives the logger of each step from
+type logStepBaseKey struct{}
+
+// withLogStepBase stores the context's logger, without a step, for logStep; Reconcile calls it
+// once, so each step replaces the step field of the one before instead of adding another
+func withLogStepBase(ctx context.Context) context.Context {
+	return context.WithValue(ctx, logStepBaseKey{}, log.FromContext(ctx))
+}
+
+// logStep returns the context and logger of a reconcile step, which log the step as a field
+func logStep(ctx context.Context, step string) (context.Context, logr.Logger) {
+	base, ok := ctx.Value(logStepBaseKey{}).(logr.Logger)
+	if !ok {
+		base = log.FromContext(ctx)
+	}
+	logger := base.WithValues(logStepFieldName, step)
+	return log.IntoContext(ctx, logger), logger
+}
+
+// ParseLogStepVerbosity parses --log-step-verbosity, a comma-separated list of <step>=<n>. A
+// positive n also logs the step's debug messages up to V(n); a negative n hides its messages
+// below V(-n), e.g. issuance=-1 hides the certificate polling.
+func ParseLogStepVerbosity(value string) (map[string]int, error) {
+	verbosity := map[string]int{}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		step, level, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q is not <step>=<verbosity>", entry)
+		}
+		step = strings.TrimSpace(step)
+		if !slices.Contains(LogSteps, step) {
+			return nil, fmt.Errorf("unknown step %q, must be one of %s", step, strings.Join(LogSteps, ", "))
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(level))
+		if err != nil || n < -maxStepLogVerbosity || n > maxStepLogVerbosity {
+			return nil, fmt.Errorf("verbosity of step %s must be a number between -%d and %d", step, maxStepLogVerbosity, maxStepLogVerbosity)
+		}
+		verbosity[step] = n
+	}
+	return verbosity, nil
+}
+
+// NewStepLogger wraps the logger so the messages of each reconcile step are logged with the
+// step's verbosity: a message at V(level) is passed on at V(level - verbosity[step]). Errors
+// are always logged.
+func NewStepLogger(logger logr.Logger, verbosity map[string]int) logr.Logger {
+	if len(verbosity) == 0 {
+		return logger
+	}
+	return logr.New(&stepLogSink{sink: logger.GetSink(), verbosity: verbosity})
+}
+
+// stepLogSink shifts the verbosity of messages by the step in their fields
+type stepLogSink struct {
+	sink      logr.LogSink
+	verbosity map[string]int
+	shift     int
+}
+
+var (
+	_ logr.LogSink          = &stepLogSink{}
+	_ logr.CallDepthLogSink = &stepLogSink{}
+)
+
+// level returns the level a message at V(level) is passed on at
+func (s *stepLogSink) level(level int) int {
+	return max(level-s.shift, 0)
+}
+
+// Init implements logr.LogSink; the wrapper adds a frame between the caller and the sink
+func (s *stepLogSink) Init(info logr.RuntimeInfo) {
+	info.CallDepth++
+	s.sink.Init(info)
+}
+
+// Enabled implements logr.LogSink
+func (s *stepLogSink) Enabled(level int) bool {
+	return s.sink.Enabled(s.level(level))
+}
+
+// Info implements logr.LogSink
+func (s *stepLogSink) Info(level int, msg string, keysAndValues ...any) {
+	s.sink.Info(s.level(level), msg, keysAndValues...)
+}
+
+// Error implements logr.LogSink
+func (s *stepLogSink) Error(err error, msg string, keysAndValues ...any) {
+	s.sink.Error(err, msg, keysAndValues...)
+}
+
+// WithValues implements logr.LogSink; a step field sets the verbosity of the returned sink
+func (s *stepLogSink) WithValues(keysAndValues ...any) logr.LogSink {
+	out := *s
+	out.sink = s.sink.WithValues(keysAndValues...)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		if key, ok := keysAndValues[i].(string); ok && key == logStepFieldName {
+			step, _ := keysAndValues[i+1].(string)
+			out.shift = s.verbosity[step]
+		}
+	}
+	return &out
+}
+
+// WithName implements logr.LogSink
+func (s *stepLogSink) WithName(name string) logr.LogSink {
+	out := *s
+	out.sink = s.sink.WithName(name)
+	return &out
+}
+
+// WithCallDepth implements logr.CallDepthLogSink
+func (s *stepLogSink) WithCallDepth(depth int) logr.LogSink {
+	sink, ok := s.sink.(logr.CallDepthLogSink)
+	if !ok {
+		return s
+	}
+	out := *s
+	out.sink = sink.WithCallDepth(depth)
+	return &out
+}
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestParseLogStepVerbosity(t *testing.T) {
+	verbosity, err := ParseLogStepVerbosity(" dns=2, issuance=-1,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{StepDNS: 2, StepIssuance: -1}, verbosity)
+
+	for _, value := range []string{"dns", "acme=1", "dns=high", "dns=11"} {
+		_, err := ParseLogStepVerbosity(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestNewStepLogger(t *testing.T) {
+	var logged []string
+	base := funcr.New(func(prefix, args string) { logged = append(logged, args) }, funcr.Options{Verbosity: 0})
+	ctx := withLogStepBase(log.IntoContext(context.Background(), NewStepLogger(base, map[string]int{StepDNS: 1, StepIssuance: -1})))
+
+	_, dns := logStep(ctx, StepDNS)
+	dns.V(1).Info("debug")
+	_, issuance := logStep(ctx, StepIssuance)
+	issuance.Info("polling")
+	issuance.Error(nil, "failed")
+	_, gateway := logStep(ctx, StepGateway)
+	gateway.Info("assigned")
+	gateway.V(1).Info("hidden")
+
+	// Steps run one after the other on the context of the step before
+	chained, _ := logStep(ctx, StepClaim)
+	chained, _ = logStep(chained, StepCertificate)
+	_, dnsAfter := logStep(chained, StepDNS)
+	dnsAfter.V(1).Info("chained")
+
+	require.Len(t, logged, 4)
+	assert.Contains(t, logged[0], `"step"="dns"`)
+	assert.Contains(t, logged[0], `"msg"="debug"`)
+	assert.Contains(t, logged[1], `"msg"="failed"`, "errors ignore the step verbosity")
+	assert.Contains(t, logged[2], `"msg"="assigned"`)
+	assert.Contains(t, logged[3], `"msg"="chained"`, "the verbosity of the current step applies")
+	assert.Equal(t, 1, strings.Count(logged[3], `"step"=`), "each step replaces the one before")
+	assert.Contains(t, logged[3], `"step"="dns"`)
+
+	assert.Equal(t, base, NewStepLogger(base, nil), "no verbosity leaves the logger unwrapped")
+}
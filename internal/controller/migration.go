@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// migratingFromNamespace returns the namespace of the Gateway the request is moving off
+func migratingFromNamespace(ghr *gatewayv1alpha1.GatewayHostnameRequest) string {
+	if ghr.Status.MigratingFromGatewayNamespace != "" {
+		return ghr.Status.MigratingFromGatewayNamespace
+	}
+	return ghr.Status.AssignedGatewayNamespace
+}
+
+// onlyVisibilityChanged reports whether spec.visibility is the only change since the last
+// provisioning. Such changes move the hostname to another Gateway instead of re-provisioning.
+func onlyVisibilityChanged(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	for _, visibility := range []string{"", "internet-facing", "internal"} {
+		spec := ghr.Spec
+		spec.Visibility = visibility
+		if computeSpecHash(&spec) == ghr.Status.ObservedSpecHash {
+			return true
+		}
+	}
+	return false
+}
+
+// gatewayVisibility returns the visibility of the request's Gateway. Visibility is shared by
+// every hostname on the ALB and changing it replaces the ALB, so a request never changes it:
+// one asking for another visibility is moved to a matching Gateway instead. The scheme the
+// controller last applied to the Gateway's LoadBalancerConfiguration wins over a hand-edited
+// annotation. Gateways from before the annotation keep the scheme of their
+// LoadBalancerConfiguration; only a Gateway without either takes the request's visibility.
+func (r *GatewayHostnameRequestReconciler) gatewayVisibility(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, gw *gwapiv1.Gateway) string {
+	var lbc *unstructured.Unstructured
+	if lbcName, err := r.loadBalancerConfigurationName(gw.Name); err == nil {
+		if found, err := r.getLoadBalancerConfiguration(ctx, types.NamespacedName{Name: lbcName, Namespace: gw.Namespace}); err == nil {
+			lbc = found
+		}
+	}
+	if lbc != nil {
+		if applied, ok := appliedSpec(lbc); ok {
+			if scheme, _ := applied["scheme"].(string); scheme != "" {
+				return scheme
+			}
+		}
+	}
+	if current := gw.Annotations[AnnotationVisibility]; current != "" {
+		return current
+	}
+	if lbc != nil {
+		if scheme, _, _ := unstructured.NestedString(lbc.Object, "spec", "scheme"); scheme != "" {
+			return scheme
+		}
+	}
+	if ghr.Spec.Visibility == "" {
+		return "internet-facing"
+	}
+	return ghr.Spec.Visibility
+}
+
+// placementMismatch describes why the Gateway no longer fits the request, or returns "" if it does.
+// Gateways without a visibility annotation are not moved off for visibility until
+// ensureGatewayConfiguration recorded theirs.
+func placementMismatch(ghr *gatewayv1alpha1.GatewayHostnameRequest, gw *gwapiv1.Gateway) (string, error) {
+	if ghr.Spec.GatewaySelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(ghr.Spec.GatewaySelector)
+		if err != nil {
+			return "", fmt.Errorf("invalid gateway selector: %w", err)
+		}
+		if !selector.Matches(labels.Set(gw.Labels)) {
+			return fmt.Sprintf("Gateway %s does not match gatewaySelector", gw.Name), nil
+		}
+	}
+
+	visibility := ghr.Spec.Visibility
+	if visibility == "" {
+		visibility = "internet-facing"
+	}
+	if current := gw.Annotations[AnnotationVisibility]; current != "" && current != visibility {
+		return fmt.Sprintf("Gateway %s is %s, the request asks for %s", gw.Name, current, visibility), nil
+	}
+	return "", nil
+}
+
+// placementMoveInProgress reports whether the request is moving because its placement changed,
+// as opposed to a rebalance off an over-capacity Gateway
+func placementMoveInProgress(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	return ghr.Status.MigratingFromGateway != "" && meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeMigrating)
+}
+
+// migrationRolledBack reports whether the last move for the current spec failed, leaving the
+// request on a Gateway that doesn't match it
+func migrationRolledBack(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	return conditions.HasReason(ghr.Status.Conditions, ConditionTypeMigrating, metav1.ConditionFalse, conditions.ReasonRolledBack)
+}
+
+// reconcilePlacement starts moving the request to another Gateway when its assigned Gateway no
+// longer matches spec.gatewaySelector or spec.visibility. Like a rebalance, the old Gateway keeps
+// serving the hostname until DNS points at the new one and the drain period passed.
+// After a rolled back move, it is only retried once the spec changes again or a full reconcile
+// is requested. Returns true if a move was started (the caller must persist status and requeue).
+func (r *GatewayHostnameRequestReconciler) reconcilePlacement(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (bool, error) {
+	if ghr.Status.AssignedGateway == "" || ghr.Status.MigratingFromGateway != "" || isDNSOnly(ghr) ||
+		!meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeListenerAttached) {
+		return false, nil
+	}
+
+	var gw gwapiv1.Gateway
+	if err := r.Get(ctx, types.NamespacedName{Name: ghr.Status.AssignedGateway, Namespace: ghr.Status.AssignedGatewayNamespace}, &gw); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Drift detection reassigns the request
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get gateway: %w", err)
+	}
+
+	mismatch, err := placementMismatch(ghr, &gw)
+	if err != nil {
+		return false, err
+	}
+	if mismatch == "" {
+		if migrationRolledBack(ghr) {
+			meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeMigrating)
+		}
+		return false, nil
+	}
+	if cond := meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeMigrating); migrationRolledBack(ghr) &&
+		cond.ObservedGeneration == ghr.Generation && !reconcileNowPending(ghr) {
+		return false, nil
+	}
+
+	log.FromContext(ctx).Info("Moving hostname to a matching Gateway", "gateway", gw.Name, "reason", mismatch)
+	r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "Migrating", "%s; moving hostname to a matching Gateway", mismatch)
+
+	ghr.Status.MigratingFromGateway = ghr.Status.AssignedGateway
+	ghr.Status.MigratingFromGatewayNamespace = ghr.Status.AssignedGatewayNamespace
+	ghr.Status.AssignedGateway = ""
+	meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeListenerAttached)
+	meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeDnsAliasReady)
+	r.setCondition(ghr, ConditionTypeMigrating, metav1.ConditionTrue, conditions.ReasonProvisioning,
+		mismatch+"; assigning a matching Gateway")
+	r.setCondition(ghr, ConditionTypeReady, metav1.ConditionFalse, conditions.ReasonMigrating,
+		fmt.Sprintf("Moving to another Gateway; %s keeps serving the hostname meanwhile", ghr.Status.MigratingFromGateway))
+
+	return true, nil
+}
+
+// updateMigrationPhase advances the Migrating condition of a placement move after a step completed
+func (r *GatewayHostnameRequestReconciler) updateMigrationPhase(ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+	if !placementMoveInProgress(ghr) {
+		return
+	}
+	if !meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeDnsAliasReady) {
+		r.setCondition(ghr, ConditionTypeMigrating, metav1.ConditionTrue, conditions.ReasonSwitchingDNS,
+			fmt.Sprintf("Assigned to Gateway %s; pointing DNS at it", ghr.Status.AssignedGateway))
+		return
+	}
+	r.setCondition(ghr, ConditionTypeMigrating, metav1.ConditionTrue, conditions.ReasonDraining,
+		fmt.Sprintf("DNS points at Gateway %s; %s keeps the certificate for the drain period", ghr.Status.AssignedGateway, ghr.Status.MigratingFromGateway))
+}
+
+// rollbackGatewayMove returns the request to the Gateway it was moving off after assigning the
+// new Gateway or pointing DNS at it failed. The certificate is released from the new Gateway
+// and the ALIAS records are rewritten for the old one, which kept serving the hostname.
+func (r *GatewayHostnameRequestReconciler) rollbackGatewayMove(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, cause error) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	oldGateway, oldNamespace := ghr.Status.MigratingFromGateway, migratingFromNamespace(ghr)
+	logger.Info("Moving to another Gateway failed, rolling back", "gateway", oldGateway, "error", cause.Error())
+
+	if newGateway := ghr.Status.AssignedGateway; newGateway != "" &&
+		(newGateway != oldGateway || ghr.Status.AssignedGatewayNamespace != oldNamespace) {
+		if err := r.releaseGateway(ctx, ghr, newGateway, ghr.Status.AssignedGatewayNamespace); err != nil {
+			logger.Info("Failed to release certificate from new Gateway during rollback", "gateway", newGateway, "error", err.Error())
+		}
+	}
+
+	ghr.Status.AssignedGateway = oldGateway
+	ghr.Status.AssignedGatewayNamespace = oldNamespace
+	ghr.Status.MigratingFromGateway = ""
+	ghr.Status.MigratingFromGatewayNamespace = ""
+	meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeDnsAliasReady)
+	r.setCondition(ghr, ConditionTypeListenerAttached, metav1.ConditionTrue, conditions.ReasonAttached, "Certificate attached to Gateway")
+	msg := fmt.Sprintf("Moving off Gateway %s failed, staying on it: %v", oldGateway, cause)
+	r.setCondition(ghr, ConditionTypeMigrating, metav1.ConditionFalse, conditions.ReasonRolledBack, msg)
+	r.Recorder.Event(ghr, corev1.EventTypeWarning, "RolledBack", msg)
+
+	if err := r.Status().Update(ctx, ghr); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{Requeue: true}, nil
+}
@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+)
+
+func TestOnlyVisibilityChanged(t *testing.T) {
+	ghr := assignedGHR("app", "app.example.com")
+	ghr.Spec.ZoneId = "Z123456"
+	ghr.Status.ObservedSpecHash = computeSpecHash(&ghr.Spec)
+
+	ghr.Spec.Visibility = "internal"
+	assert.True(t, onlyVisibilityChanged(ghr))
+
+	ghr.Spec.ZoneId = "Z999999"
+	assert.False(t, onlyVisibilityChanged(ghr))
+}
+
+func TestReconcilePlacement_StartsMoveOnSelectorMismatch(t *testing.T) {
+	ctx := context.Background()
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "gw-01", Namespace: "edge",
+			Labels:      map[string]string{"tier": "shared"},
+			Annotations: map[string]string{AnnotationVisibility: "internet-facing"},
+		},
+	}
+	ghr := assignedGHR("app", "app.example.com")
+	ghr.Generation = 2
+	ghr.Status.Conditions = []metav1.Condition{{Type: ConditionTypeListenerAttached, Status: metav1.ConditionTrue, Reason: "Attached"}}
+
+	r := &GatewayHostnameRequestReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(gw).Build(),
+		Recorder: record.NewFakeRecorder(10),
+	}
+
+	// Still matching: nothing to do
+	ghr.Spec.GatewaySelector = &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "shared"}}
+	moving, err := r.reconcilePlacement(ctx, ghr)
+	require.NoError(t, err)
+	assert.False(t, moving)
+
+	ghr.Spec.GatewaySelector = &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "dedicated"}}
+	moving, err = r.reconcilePlacement(ctx, ghr)
+	require.NoError(t, err)
+	assert.True(t, moving)
+	assert.Empty(t, ghr.Status.AssignedGateway)
+	assert.Equal(t, "gw-01", ghr.Status.MigratingFromGateway)
+	assert.Equal(t, "edge", ghr.Status.MigratingFromGatewayNamespace)
+	assert.Nil(t, meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeListenerAttached))
+	cond := meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeMigrating)
+	require.NotNil(t, cond)
+	assert.Equal(t, string(conditions.ReasonProvisioning), cond.Reason)
+	assert.True(t, placementMoveInProgress(ghr))
+
+	// Phases follow the steps of the move
+	ghr.Status.AssignedGateway, ghr.Status.AssignedGatewayNamespace = "gw-02", "edge"
+	r.updateMigrationPhase(ghr)
+	assert.Equal(t, string(conditions.ReasonSwitchingDNS), meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeMigrating).Reason)
+	r.setCondition(ghr, ConditionTypeDnsAliasReady, metav1.ConditionTrue, "Created", "")
+	r.updateMigrationPhase(ghr)
+	assert.Equal(t, string(conditions.ReasonDraining), meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeMigrating).Reason)
+}
+
+func TestEnsureGatewayConfiguration_KeepsSharedVisibility(t *testing.T) {
+	ctx := context.Background()
+	gw := &gwapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{
+		Name: "gw-01", Namespace: "edge",
+		Annotations: map[string]string{AnnotationVisibility: "internet-facing"},
+	}}
+	legacy := &gwapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gw-02", Namespace: "edge"}}
+	legacyConfig := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"scheme": "internal"}}}
+	legacyConfig.SetGroupVersionKind(LoadBalancerConfigurationGVK)
+	legacyConfig.SetName("gw-02-config")
+	legacyConfig.SetNamespace("edge")
+
+	ghr := assignedGHR("app", "app.example.com")
+	ghr.Spec.Visibility = "internal"
+	ghr.Status.Conditions = []metav1.Condition{{Type: ConditionTypeListenerAttached, Status: metav1.ConditionTrue, Reason: "Attached"}}
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).
+		WithObjects(gw, legacy, legacyConfig, ghr, assignedGHR("other", "other.example.com")).Build()
+	r := &GatewayHostnameRequestReconciler{Client: c, Recorder: record.NewFakeRecorder(10)}
+
+	// The shared Gateway keeps its visibility and the request moves instead
+	require.NoError(t, r.ensureGatewayConfiguration(ctx, ghr))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(gw), gw))
+	assert.Equal(t, "internet-facing", gw.Annotations[AnnotationVisibility])
+	lbc := r.newLoadBalancerConfiguration()
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "edge", Name: "gw-01-config"}, lbc))
+	scheme, _, _ := unstructured.NestedString(lbc.Object, "spec", "scheme")
+	assert.Equal(t, "internet-facing", scheme)
+	moving, err := r.reconcilePlacement(ctx, ghr)
+	require.NoError(t, err)
+	assert.True(t, moving)
+
+	// A hand-edited annotation is reverted to the applied scheme
+	gw.Annotations[AnnotationVisibility] = "internal"
+	require.NoError(t, c.Update(ctx, gw))
+	require.NoError(t, r.ensureGatewayConfiguration(ctx, assignedGHR("other", "other.example.com")))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(gw), gw))
+	assert.Equal(t, "internet-facing", gw.Annotations[AnnotationVisibility])
+
+	// A Gateway from before the annotation keeps the scheme of its LoadBalancerConfiguration
+	other := assignedGHR("other", "other.example.com")
+	other.Status.AssignedGateway = "gw-02"
+	require.NoError(t, r.ensureGatewayConfiguration(ctx, other))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(legacy), legacy))
+	assert.Equal(t, "internal", legacy.Annotations[AnnotationVisibility])
+}
+
+func TestRollbackGatewayMove(t *testing.T) {
+	ctx := context.Background()
+	ghr := assignedGHR("app", "app.example.com")
+	ghr.Generation = 3
+	ghr.Spec.Visibility = "internal"
+	ghr.Status.AssignedGateway = "gw-internal-01"
+	ghr.Status.AssignedGatewayNamespace = "edge-internal"
+	ghr.Status.MigratingFromGateway = "gw-01"
+	ghr.Status.MigratingFromGatewayNamespace = "edge"
+	ghr.Status.Conditions = []metav1.Condition{{Type: ConditionTypeMigrating, Status: metav1.ConditionTrue, Reason: string(conditions.ReasonSwitchingDNS)}}
+
+	r := &GatewayHostnameRequestReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr).WithStatusSubresource(ghr).Build(),
+		Recorder: record.NewFakeRecorder(10),
+	}
+
+	result, err := r.rollbackGatewayMove(ctx, ghr, errors.New("hosted zone not found"))
+	require.NoError(t, err)
+	assert.True(t, result.Requeue)
+	assert.Equal(t, "gw-01", ghr.Status.AssignedGateway)
+	assert.Equal(t, "edge", ghr.Status.AssignedGatewayNamespace)
+	assert.Empty(t, ghr.Status.MigratingFromGateway)
+	assert.Empty(t, ghr.Status.MigratingFromGatewayNamespace)
+	assert.True(t, meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeListenerAttached))
+	assert.Nil(t, meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeDnsAliasReady), "DNS is pointed back at the old Gateway")
+	assert.True(t, migrationRolledBack(ghr))
+
+	// The move is not retried for the same generation
+	gw := &gwapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{
+		Name: "gw-01", Namespace: "edge",
+		Annotations: map[string]string{AnnotationVisibility: "internet-facing"},
+	}}
+	require.NoError(t, r.Create(ctx, gw))
+	moving, err := r.reconcilePlacement(ctx, ghr)
+	require.NoError(t, err)
+	assert.False(t, moving)
+
+	ghr.Generation = 4
+	moving, err = r.reconcilePlacement(ctx, ghr)
+	require.NoError(t, err)
+	assert.True(t, moving)
+}
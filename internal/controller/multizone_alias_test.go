@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+func TestEnsureRoute53Alias_PublishesInAllZonesAndRemovesDroppedZones(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = gatewayv1alpha1.AddToScheme(scheme)
+	_ = gwapiv1.Install(scheme)
+
+	lbDNS := "k8s-gw01-abcdef1234-1234567890.us-east-1.elb.amazonaws.com"
+	hostnameType := gwapiv1.HostnameAddressType
+	gateway := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw-01", Namespace: "edge"},
+		Status: gwapiv1.GatewayStatus{
+			Addresses: []gwapiv1.GatewayStatusAddress{{Type: &hostnameType, Value: lbDNS}},
+		},
+	}
+
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-request", Namespace: "default"},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+			Hostname:          "app.opendi.com",
+			ZoneId:            "ZPRIMARY",
+			AdditionalZoneIds: []string{"ZLEGACY", "ZPRIMARY"},
+		},
+		Status: gatewayv1alpha1.GatewayHostnameRequestStatus{
+			AssignedGateway:          "gw-01",
+			AssignedGatewayNamespace: "edge",
+		},
+	}
+
+	route53Mock := &MockRoute53Client{records: make(map[string][]aws.DNSRecord)}
+	reconciler := &GatewayHostnameRequestReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(scheme).WithObjects(gateway, ghr, dualstackLBC("gw-01", "edge")).Build(),
+		Scheme:        scheme,
+		Recorder:      record.NewFakeRecorder(10),
+		Route53Client: route53Mock,
+	}
+
+	require.NoError(t, reconciler.ensureRoute53Alias(context.Background(), ghr))
+
+	assert.Len(t, route53Mock.records["ZPRIMARY"], 2)
+	assert.Len(t, route53Mock.records["ZLEGACY"], 2)
+	assert.Equal(t, []string{"ZPRIMARY", "ZLEGACY"}, ghr.Status.AliasZoneIds)
+	assert.True(t, aliasZonesInSync(ghr))
+
+	// Migration finished: the legacy zone is dropped from the spec.
+	// The mock appends instead of upserting, so reset the primary zone first.
+	ghr.Spec.AdditionalZoneIds = nil
+	assert.False(t, aliasZonesInSync(ghr))
+
+	route53Mock.records["ZPRIMARY"] = nil
+	require.NoError(t, reconciler.ensureRoute53Alias(context.Background(), ghr))
+
+	assert.Len(t, route53Mock.records["ZPRIMARY"], 2)
+	assert.Empty(t, route53Mock.records["ZLEGACY"])
+	assert.Equal(t, []string{"ZPRIMARY"}, ghr.Status.AliasZoneIds)
+}
+
+func TestDeleteAliasRecords_RemovesFromAllPublishedZones(t *testing.T) {
+	alias := &aws.AliasTarget{
+		DNSName:      "k8s-gw01-abcdef1234-1234567890.us-east-1.elb.amazonaws.com.",
+		HostedZoneID: "Z35SXDOTRQ7X7K",
+	}
+	route53Mock := &MockRoute53Client{
+		records: map[string][]aws.DNSRecord{
+			"ZPRIMARY": {
+				{Name: "app.opendi.com", Type: "A", AliasTarget: alias},
+				{Name: "app.opendi.com", Type: "AAAA", AliasTarget: alias},
+			},
+			"ZLEGACY": {
+				{Name: "app.opendi.com", Type: "A", AliasTarget: alias},
+				{Name: "app.opendi.com", Type: "AAAA", AliasTarget: alias},
+			},
+		},
+	}
+
+	// The spec no longer lists the legacy zone, but the records are still published there
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+			Hostname: "app.opendi.com",
+			ZoneId:   "ZPRIMARY",
+		},
+		Status: gatewayv1alpha1.GatewayHostnameRequestStatus{
+			AssignedLoadBalancer: "k8s-gw01-abcdef1234-1234567890.us-east-1.elb.amazonaws.com",
+			AliasZoneIds:         []string{"ZPRIMARY", "ZLEGACY"},
+		},
+	}
+
+	reconciler := &GatewayHostnameRequestReconciler{Route53Client: route53Mock}
+
+	failed := reconciler.deleteAliasRecords(context.Background(), ghr)
+	assert.Empty(t, failed)
+	assert.Empty(t, route53Mock.records["ZPRIMARY"])
+	assert.Empty(t, route53Mock.records["ZLEGACY"])
+}
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestSyncAllowedNamespaces_AddsAndRemoves(t *testing.T) {
+	other := assignedGHR("a", "a.example.com")
+	other.Namespace = "team-b"
+	current := assignedGHR("b", "b.example.com")
+	current.Namespace = "team-a"
+	elsewhere := assignedGHR("c", "c.example.com")
+	elsewhere.Namespace = "team-c"
+	elsewhere.Status.AssignedGateway = "gw-02"
+
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(other, elsewhere).Build()
+	r := &GatewayHostnameRequestReconciler{Client: c, NamespaceAccess: NamespaceAccessGateway}
+
+	gw := &gwapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gw-01", Namespace: "edge"}}
+
+	changed, err := r.syncAllowedNamespaces(context.Background(), gw, current, true)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "team-a,team-b", gw.Annotations[AnnotationAllowedNamespaces])
+
+	// Second pass is a no-op
+	changed, err = r.syncAllowedNamespaces(context.Background(), gw, current, true)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// Dropping self removes only its namespace
+	changed, err = r.syncAllowedNamespaces(context.Background(), gw, current, false)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "team-b", gw.Annotations[AnnotationAllowedNamespaces])
+}
+
+func TestNamespaceLabel_SkippedInGatewayMode(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	scheme := getTestScheme()
+	_ = corev1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ns).Build()
+	r := &GatewayHostnameRequestReconciler{Client: c, NamespaceAccess: NamespaceAccessGateway}
+
+	require.NoError(t, r.ensureNamespaceLabel(context.Background(), assignedGHR("a", "a.example.com")))
+
+	var got corev1.Namespace
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "default"}, &got))
+	assert.NotContains(t, got.Labels, LabelGatewayAccess)
+}
+
+func TestNamespaceLabel_PerGateway(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+	other := assignedGHR("a", "a.example.com")
+	other.Status.AssignedGateway = "gw-02"
+	current := assignedGHR("b", "b.example.com")
+	scheme := getTestScheme()
+	_ = corev1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(ns, other, current).Build()
+	r := &GatewayHostnameRequestReconciler{Client: c}
+	ctx := context.Background()
+
+	require.NoError(t, r.ensureNamespaceLabel(ctx, current))
+	var got corev1.Namespace
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "default"}, &got))
+	assert.Equal(t, map[string]string{
+		"gateway.opendi.com/access.gw-01": "true",
+		"gateway.opendi.com/access.gw-02": "true",
+	}, got.Labels)
+
+	// Removing one request keeps the access the other still needs
+	require.NoError(t, r.removeNamespaceLabel(ctx, current))
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "default"}, &got))
+	assert.Equal(t, map[string]string{"gateway.opendi.com/access.gw-02": "true"}, got.Labels)
+}
+
+func TestNamespaceLabelMigration(t *testing.T) {
+	legacy := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "default",
+		Labels: map[string]string{LabelGatewayAccess: "gw-01", "team": "shop"},
+	}}
+	abandoned := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "old-team",
+		Labels: map[string]string{LabelGatewayAccess: "gw-03"},
+	}}
+	untouched := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "unrelated"}}
+	scheme := getTestScheme()
+	_ = corev1.AddToScheme(scheme)
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(legacy, abandoned, untouched, assignedGHR("a", "a.example.com")).Build()
+	ctx := context.Background()
+
+	require.NoError(t, (&NamespaceLabelMigration{Client: c}).Start(ctx))
+
+	var got corev1.Namespace
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "default"}, &got))
+	assert.Equal(t, map[string]string{"gateway.opendi.com/access.gw-01": "true", "team": "shop"}, got.Labels)
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "old-team"}, &got))
+	assert.Empty(t, got.Labels, "stale legacy labels are removed")
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Name: "unrelated"}, &got))
+	assert.Empty(t, got.Labels)
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// LabelGatewayAccessPrefix is the prefix of the per-Gateway access labels on namespaces:
+// gateway.opendi.com/access.<gateway>=true for every Gateway serving a hostname of the
+// namespace. They replace LabelGatewayAccess, which could only name one Gateway.
+const LabelGatewayAccessPrefix = "gateway.opendi.com/access."
+
+// gatewayAccessLabel returns the access label of a Gateway
+func gatewayAccessLabel(gatewayName string) string {
+	return LabelGatewayAccessPrefix + gatewayName
+}
+
+// syncNamespaceAccessLabels sets an access label on the namespace for every Gateway its
+// requests are assigned to or moving off, removes the labels of other Gateways and the
+// legacy LabelGatewayAccess, in memory. self is counted from memory if keepSelf and skipped
+// in the list otherwise, like in syncHostnameListeners. Returns true if the labels changed.
+func syncNamespaceAccessLabels(ctx context.Context, c client.Reader, ns *corev1.Namespace, self *gatewayv1alpha1.GatewayHostnameRequest, keepSelf bool) (bool, error) {
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := c.List(ctx, &ghrList, client.InNamespace(ns.Name)); err != nil {
+		return false, fmt.Errorf("failed to list GatewayHostnameRequests in %s: %w", ns.Name, err)
+	}
+
+	desired := map[string]bool{}
+	add := func(ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+		for _, gatewayName := range []string{ghr.Status.AssignedGateway, ghr.Status.MigratingFromGateway} {
+			if gatewayName != "" {
+				desired[gatewayAccessLabel(gatewayName)] = true
+			}
+		}
+	}
+	if self != nil && keepSelf {
+		add(self)
+	}
+	for i := range ghrList.Items {
+		ghr := &ghrList.Items[i]
+		if (self != nil && ghr.Name == self.Name) || !ghr.DeletionTimestamp.IsZero() {
+			continue
+		}
+		add(ghr)
+	}
+
+	changed := false
+	for label := range ns.Labels {
+		if (label == LabelGatewayAccess || strings.HasPrefix(label, LabelGatewayAccessPrefix)) && !desired[label] {
+			delete(ns.Labels, label)
+			changed = true
+		}
+	}
+	for label := range desired {
+		if ns.Labels[label] != "true" {
+			if ns.Labels == nil {
+				ns.Labels = make(map[string]string)
+			}
+			ns.Labels[label] = "true"
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+// NamespaceLabelMigration rewrites namespaces labeled with the legacy single
+// LabelGatewayAccess to the per-Gateway labels once at startup, so namespaces whose requests
+// aren't reconciled soon neither lose access nor keep the stale label
+type NamespaceLabelMigration struct {
+	Client client.Client
+}
+
+// Start migrates the namespaces and returns
+func (m *NamespaceLabelMigration) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("namespace-label-migration")
+
+	var nsList corev1.NamespaceList
+	if err := m.Client.List(ctx, &nsList, client.HasLabels{LabelGatewayAccess}); err != nil {
+		// Requests still migrate their namespace when reconciled
+		logger.Error(err, "Failed to list namespaces with the legacy gateway access label")
+		return nil
+	}
+
+	migrated := 0
+	for i := range nsList.Items {
+		ns := &nsList.Items[i]
+		changed, err := syncNamespaceAccessLabels(ctx, m.Client, ns, nil, false)
+		if err != nil {
+			logger.Error(err, "Failed to compute gateway access labels", "namespace", ns.Name)
+			continue
+		}
+		if !changed {
+			continue
+		}
+		if err := m.Client.Update(ctx, ns); err != nil {
+			logger.Error(err, "Failed to migrate gateway access label", "namespace", ns.Name)
+			continue
+		}
+		migrated++
+	}
+	if migrated > 0 {
+		logger.Info("Migrated namespaces to per-Gateway access labels", "namespaces", migrated)
+	}
+	return nil
+}
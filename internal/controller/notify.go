@@ -0,0 +1,61 @@
+package controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/notify"
+)
+
+// certificateFailureReasons are the condition reasons on CertificateRequested, DnsValidated and
+// CertificateIssued that mean issuance failed, as opposed to still being in progress
+var certificateFailureReasons = map[conditions.Reason]bool{
+	conditions.ReasonIssuerNotConfigured: true,
+	conditions.ReasonCAAForbidsAmazon:    true,
+	conditions.ReasonRequestFailed:       true,
+	conditions.ReasonCheckFailed:         true,
+	conditions.ReasonChallengeFailed:     true,
+	conditions.ReasonOrderFailed:         true,
+}
+
+// notifyTransition sends the lifecycle event, if any, for a condition transition
+func (r *GatewayHostnameRequestReconciler) notifyTransition(ghr *gatewayv1alpha1.GatewayHostnameRequest, condType string, status metav1.ConditionStatus, reason conditions.Reason, message string) {
+	var eventType string
+	switch {
+	case condType == ConditionTypeReady && status == metav1.ConditionTrue:
+		eventType = notify.EventHostnameReady
+	case condType == ConditionTypeClaimed && (reason == conditions.ReasonAlreadyClaimed || reason == conditions.ReasonReservedForNamespace):
+		eventType = notify.EventClaimConflict
+	case (condType == ConditionTypeCertificateRequested || condType == ConditionTypeDnsValidated || condType == ConditionTypeCertificateIssued) &&
+		status == metav1.ConditionFalse && certificateFailureReasons[reason]:
+		eventType = notify.EventCertificateFailed
+	default:
+		return
+	}
+	r.notify(ghr, eventType, message)
+}
+
+// notify sends a lifecycle event about the request
+func (r *GatewayHostnameRequestReconciler) notify(ghr *gatewayv1alpha1.GatewayHostnameRequest, eventType, message string) {
+	event := notify.Event{
+		Type:      eventType,
+		Hostname:  ghr.Spec.Hostname,
+		Namespace: ghr.Namespace,
+		Name:      ghr.Name,
+		Message:   message,
+	}
+	if ghr.Status.AssignedGateway != "" {
+		event.Gateway = ghr.Status.AssignedGatewayNamespace + "/" + ghr.Status.AssignedGateway
+	}
+	r.Notifier.Notify(event)
+}
+
+// notifyGateway sends a lifecycle event about a managed Gateway
+func (r *GatewayHostnameRequestReconciler) notifyGateway(eventType, name, namespace, message string) {
+	r.Notifier.Notify(notify.Event{
+		Type:    eventType,
+		Gateway: namespace + "/" + name,
+		Message: message,
+	})
+}
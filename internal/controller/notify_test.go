@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/internal/notify"
+)
+
+// channelSink forwards delivered events to a channel
+type channelSink chan notify.Event
+
+func (c channelSink) Name() string { return "channel" }
+
+func (c channelSink) Send(ctx context.Context, event notify.Event) error {
+	c <- event
+	return nil
+}
+
+func TestSetCondition_NotifiesOnTransitions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(channelSink, 10)
+	dispatcher := notify.NewDispatcher([]notify.Sink{events}, nil, 0)
+	go func() { _ = dispatcher.Start(ctx) }()
+
+	r := &GatewayHostnameRequestReconciler{Notifier: dispatcher}
+	ghr := assignedGHR("app", "app.example.com")
+
+	next := func() notify.Event {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(time.Second):
+			t.Fatal("no notification delivered")
+			return notify.Event{}
+		}
+	}
+
+	r.setCondition(ghr, ConditionTypeCertificateIssued, metav1.ConditionFalse, "PendingIssuance", "Waiting for ACM")
+	r.setCondition(ghr, ConditionTypeCertificateIssued, metav1.ConditionFalse, "CheckFailed", "describe failed")
+	event := next()
+	assert.Equal(t, notify.EventCertificateFailed, event.Type)
+	assert.Equal(t, "describe failed", event.Message)
+
+	r.setCondition(ghr, ConditionTypeReady, metav1.ConditionTrue, "Ready", "Hostname is ready")
+	// Setting the same condition again is not a transition
+	r.setCondition(ghr, ConditionTypeReady, metav1.ConditionTrue, "Ready", "Hostname is ready")
+	r.setCondition(ghr, ConditionTypeClaimed, metav1.ConditionFalse, "AlreadyClaimed", "Hostname already claimed by another request")
+
+	event = next()
+	assert.Equal(t, notify.EventHostnameReady, event.Type)
+	assert.Equal(t, "app.example.com", event.Hostname)
+	assert.Equal(t, "default", event.Namespace)
+	assert.Equal(t, "app", event.Name)
+	assert.Equal(t, "edge/gw-01", event.Gateway)
+	require.Equal(t, notify.EventClaimConflict, next().Type)
+
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected notification %s", event.Type)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,389 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+// DefaultOrchestratorConfigName is the name of the OrchestratorConfig the controller reads
+const DefaultOrchestratorConfigName = "default"
+
+// ConditionTypeApplied is True on an OrchestratorConfig once its spec is in effect
+const ConditionTypeApplied = "Applied"
+
+// Reasons of Applied
+const (
+	ReasonConfigApplied conditions.Reason = "Applied"
+	ReasonConfigInvalid conditions.Reason = "Invalid"
+)
+
+// Settings are the controller settings an OrchestratorConfig can change at runtime. The
+// command-line flags provide the defaults for fields the OrchestratorConfig leaves unset.
+type Settings struct {
+	MaxCertificatesPerGateway int
+	GatewayCreationCooldown   time.Duration
+	HTTPPort                  int32
+	HTTPSPort                 int32
+
+	// HTTPSOnly leaves the HTTP listener off new Gateways
+	HTTPSOnly bool
+
+	// CertificateTags are added to the certificates' ACM tags
+	CertificateTags map[string]string
+
+	// AllowedDomains restricts request hostnames to these domains and their subdomains;
+	// empty allows all
+	AllowedDomains []string
+
+	// CertificatePollBackoff is the delay curve for checking certificates pending issuance
+	CertificatePollBackoff []time.Duration
+
+	// DefaultWafArn is the WAF of requests that set none
+	DefaultWafArn string
+
+	// Subnets are the ALB subnets by visibility; none lets the AWS Load Balancer Controller
+	// discover them
+	Subnets map[string][]string
+
+	// Gateways are the Gateways the pool keeps whether or not hostnames use them
+	Gateways []BootstrapGateway
+
+	// PrivateZoneVPCs are associated with the private hosted zones of internal requests
+	PrivateZoneVPCs []aws.VPC
+
+	// DeregistrationDelay is the deregistration delay of the backend target groups; nil keeps
+	// the AWS default
+	DeregistrationDelay *time.Duration
+}
+
+// SettingsStore holds the settings in effect, shared between the OrchestratorConfig
+// reconciler that updates them and the reconcilers reading them
+type SettingsStore struct {
+	current atomic.Pointer[Settings]
+}
+
+// NewSettingsStore creates a store holding the given settings
+func NewSettingsStore(settings Settings) *SettingsStore {
+	s := &SettingsStore{}
+	s.Store(settings)
+	return s
+}
+
+// Load returns the settings in effect. The result must not be modified.
+func (s *SettingsStore) Load() *Settings {
+	return s.current.Load()
+}
+
+// Store replaces the settings in effect
+func (s *SettingsStore) Store(settings Settings) {
+	s.current.Store(&settings)
+}
+
+// OrchestratorConfigReconciler applies the OrchestratorConfig the spec.parametersRef of the
+// pool's GatewayClass points at, or else the one named Name: its settings override Defaults
+// and go into Settings and the Gateway pool. If the object doesn't exist, Defaults apply. An
+// invalid spec is rejected and the previous settings stay in effect.
+//
+// There is one set of settings per controller instance. Requests are provisioned on the
+// pool's GatewayClass whatever their spec.gatewayClass, so classes with different settings
+// need an instance each.
+type OrchestratorConfigReconciler struct {
+	client.Client
+	Scheme      *runtime.Scheme
+	Recorder    record.EventRecorder
+	GatewayPool *gateway.Pool
+	Settings    *SettingsStore
+
+	// Name of the OrchestratorConfig to apply (default DefaultOrchestratorConfigName)
+	Name string
+
+	// GatewayClass is the GatewayClass of the pool. If its spec.parametersRef points at an
+	// OrchestratorConfig, that one is applied instead of Name.
+	GatewayClass string
+
+	// Defaults are the settings from the command-line flags
+	Defaults Settings
+
+	// Requests creates the Gateways of spec.pool.gateways; nil leaves the pool alone
+	Requests *GatewayHostnameRequestReconciler
+}
+
+//+kubebuilder:rbac:groups=gateway.opendi.com,resources=orchestratorconfigs,verbs=get;list;watch
+//+kubebuilder:rbac:groups=gateway.opendi.com,resources=orchestratorconfigs/status,verbs=get;update;patch
+
+func (r *OrchestratorConfigReconciler) name() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return DefaultOrchestratorConfigName
+}
+
+// configName returns the name of the OrchestratorConfig to apply: the one the GatewayClass's
+// parametersRef points at, or Name. References to other kinds, such as the AWS Load Balancer
+// Controller's LoadBalancerConfiguration, are ignored.
+func (r *OrchestratorConfigReconciler) configName(ctx context.Context) (string, error) {
+	if r.GatewayClass == "" {
+		return r.name(), nil
+	}
+	var gc gwapiv1.GatewayClass
+	if err := r.Get(ctx, types.NamespacedName{Name: r.GatewayClass}, &gc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.name(), nil
+		}
+		return "", fmt.Errorf("failed to get GatewayClass %s: %w", r.GatewayClass, err)
+	}
+	if ref := gc.Spec.ParametersRef; ref != nil && string(ref.Group) == gatewayv1alpha1.GroupVersion.Group && ref.Kind == "OrchestratorConfig" {
+		return ref.Name, nil
+	}
+	return r.name(), nil
+}
+
+// Reconcile applies the OrchestratorConfig, or the defaults once it is deleted
+func (r *OrchestratorConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	name, err := r.configName(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if req.Name != name {
+		return ctrl.Result{}, nil
+	}
+
+	var config gatewayv1alpha1.OrchestratorConfig
+	if err := r.Get(ctx, types.NamespacedName{Name: req.Name}, &config); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("failed to get OrchestratorConfig: %w", err)
+		}
+		r.apply(r.Defaults)
+		logger.Info("OrchestratorConfig not found, using command-line settings", "name", req.Name)
+		return ctrl.Result{}, r.bootstrapPool(ctx, nil, r.Defaults.Gateways)
+	}
+
+	settings, err := mergeSettings(r.Defaults, &config.Spec)
+	if err != nil {
+		if conditions.Set(&config.Status.Conditions, ConditionTypeApplied, metav1.ConditionFalse, ReasonConfigInvalid, err.Error(), config.Generation) {
+			r.Recorder.Eventf(&config, corev1.EventTypeWarning, string(ReasonConfigInvalid), "Settings not applied: %v", err)
+		}
+		logger.Info("Rejected invalid OrchestratorConfig, previous settings stay in effect", "name", req.Name, "error", err.Error())
+		return ctrl.Result{}, r.Status().Update(ctx, &config)
+	}
+
+	r.apply(settings)
+	if err := r.bootstrapPool(ctx, &config, settings.Gateways); err != nil {
+		return ctrl.Result{}, err
+	}
+	if config.Status.ObservedGeneration != config.Generation {
+		logger.Info("Applied OrchestratorConfig", "name", req.Name, "generation", config.Generation,
+			"maxCertificatesPerGateway", settings.MaxCertificatesPerGateway, "allowedDomains", settings.AllowedDomains)
+		r.Recorder.Eventf(&config, corev1.EventTypeNormal, string(ReasonConfigApplied), "Settings of generation %d applied", config.Generation)
+	}
+	changed := conditions.Set(&config.Status.Conditions, ConditionTypeApplied, metav1.ConditionTrue, ReasonConfigApplied,
+		"Settings are in effect", config.Generation)
+	if !changed && config.Status.ObservedGeneration == config.Generation {
+		return ctrl.Result{}, nil
+	}
+	config.Status.ObservedGeneration = config.Generation
+	if err := r.Status().Update(ctx, &config); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update OrchestratorConfig status: %w", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// apply puts the settings into effect
+func (r *OrchestratorConfigReconciler) apply(settings Settings) {
+	r.Settings.Store(settings)
+	if r.GatewayPool != nil {
+		r.GatewayPool.SetMaxCertificates(settings.MaxCertificatesPerGateway)
+		r.GatewayPool.SetCreationCooldown(settings.GatewayCreationCooldown)
+		r.GatewayPool.SetPorts(settings.HTTPPort, settings.HTTPSPort)
+		r.GatewayPool.SetHTTPSOnly(settings.HTTPSOnly)
+	}
+}
+
+// bootstrapPool converges the pool to the Gateways of the settings in effect, reporting failures
+// on the OrchestratorConfig (nil if it doesn't exist)
+func (r *OrchestratorConfigReconciler) bootstrapPool(ctx context.Context, config *gatewayv1alpha1.OrchestratorConfig, gateways []BootstrapGateway) error {
+	if r.Requests == nil {
+		return nil
+	}
+	if err := r.Requests.bootstrapPool(ctx, gateways); err != nil {
+		if config != nil {
+			r.Recorder.Eventf(config, corev1.EventTypeWarning, "BootstrapFailed", "Failed to create the Gateways of spec.pool.gateways: %v", err)
+		}
+		return err
+	}
+	return nil
+}
+
+// mergeSettings overrides the defaults with the fields set in the spec
+func mergeSettings(defaults Settings, spec *gatewayv1alpha1.OrchestratorConfigSpec) (Settings, error) {
+	settings := defaults
+
+	if spec.Pool.MaxCertificatesPerGateway != nil {
+		settings.MaxCertificatesPerGateway = int(*spec.Pool.MaxCertificatesPerGateway)
+	}
+	if spec.Pool.GatewayCreationCooldown != nil {
+		if spec.Pool.GatewayCreationCooldown.Duration < 0 {
+			return Settings{}, fmt.Errorf("pool.gatewayCreationCooldown must not be negative")
+		}
+		settings.GatewayCreationCooldown = spec.Pool.GatewayCreationCooldown.Duration
+	}
+	if spec.Pool.HTTPPort != nil {
+		settings.HTTPPort = *spec.Pool.HTTPPort
+	}
+	if spec.Pool.HTTPSPort != nil {
+		settings.HTTPSPort = *spec.Pool.HTTPSPort
+	}
+	if settings.HTTPPort != 0 && settings.HTTPPort == settings.HTTPSPort {
+		return Settings{}, fmt.Errorf("pool.httpPort and pool.httpsPort must differ")
+	}
+	if spec.Pool.HTTPSOnly != nil {
+		settings.HTTPSOnly = *spec.Pool.HTTPSOnly
+	}
+	if delay := spec.Pool.TargetGroup.DeregistrationDelay; delay != nil {
+		if delay.Duration < 0 || delay.Duration > time.Hour {
+			return Settings{}, fmt.Errorf("pool.targetGroup.deregistrationDelay must be between 0s and 1h")
+		}
+		settings.DeregistrationDelay = &delay.Duration
+	}
+
+	if len(spec.CertificateTags) > 0 {
+		if key, kind := findSecret(spec.CertificateTags); kind != "" {
+			return Settings{}, fmt.Errorf("certificateTags[%s] looks like %s; tags are readable by anyone who can list the certificates", key, kind)
+		}
+		settings.CertificateTags = maps.Clone(defaults.CertificateTags)
+		if settings.CertificateTags == nil {
+			settings.CertificateTags = map[string]string{}
+		}
+		maps.Copy(settings.CertificateTags, spec.CertificateTags)
+	}
+
+	if len(spec.AllowedDomains) > 0 {
+		settings.AllowedDomains = nil
+		for _, domain := range spec.AllowedDomains {
+			domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+			if domain == "" || strings.Contains(domain, "*") || !strings.Contains(domain, ".") {
+				return Settings{}, fmt.Errorf("allowedDomains: %q is not a domain name", domain)
+			}
+			settings.AllowedDomains = append(settings.AllowedDomains, domain)
+		}
+	}
+
+	if len(spec.Requeue.CertificatePollBackoff) > 0 {
+		settings.CertificatePollBackoff = nil
+		for _, step := range spec.Requeue.CertificatePollBackoff {
+			if step.Duration <= 0 {
+				return Settings{}, fmt.Errorf("requeue.certificatePollBackoff: delays must be positive, got %s", step.Duration)
+			}
+			settings.CertificatePollBackoff = append(settings.CertificatePollBackoff, step.Duration)
+		}
+	}
+
+	if spec.DefaultWafArn != "" {
+		if !strings.Contains(spec.DefaultWafArn, ":regional/webacl/") {
+			return Settings{}, fmt.Errorf("defaultWafArn: %q is not a regional WebACL", spec.DefaultWafArn)
+		}
+		settings.DefaultWafArn = spec.DefaultWafArn
+	}
+
+	if subnets := spec.Pool.Subnets; len(subnets.InternetFacing) > 0 || len(subnets.Internal) > 0 {
+		settings.Subnets = maps.Clone(defaults.Subnets)
+		if settings.Subnets == nil {
+			settings.Subnets = map[string][]string{}
+		}
+		if len(subnets.InternetFacing) > 0 {
+			settings.Subnets["internet-facing"] = slices.Clone(subnets.InternetFacing)
+		}
+		if len(subnets.Internal) > 0 {
+			settings.Subnets["internal"] = slices.Clone(subnets.Internal)
+		}
+	}
+
+	if len(spec.Pool.Gateways) > 0 {
+		settings.Gateways = nil
+		seen := map[string]bool{}
+		for _, entry := range spec.Pool.Gateways {
+			if seen[entry.Name] {
+				return Settings{}, fmt.Errorf("pool.gateways: %q is listed twice", entry.Name)
+			}
+			seen[entry.Name] = true
+			bg, err := bootstrapGateway(entry)
+			if err != nil {
+				return Settings{}, fmt.Errorf("pool.gateways[%s]: %w", entry.Name, err)
+			}
+			settings.Gateways = append(settings.Gateways, bg)
+		}
+	}
+
+	if len(spec.PrivateZoneVpcs) > 0 {
+		settings.PrivateZoneVPCs = nil
+		for _, vpc := range spec.PrivateZoneVpcs {
+			if !strings.HasPrefix(vpc.VpcId, "vpc-") || vpc.Region == "" {
+				return Settings{}, fmt.Errorf("privateZoneVpcs: %q in %q is not a VPC", vpc.VpcId, vpc.Region)
+			}
+			settings.PrivateZoneVPCs = append(settings.PrivateZoneVPCs, aws.VPC{ID: vpc.VpcId, Region: vpc.Region})
+		}
+	}
+
+	return settings, nil
+}
+
+// hostnameAllowed reports whether the hostname is one of the domains or a subdomain of one.
+// Wildcard hostnames are checked by the domain they cover.
+func hostnameAllowed(hostname string, allowedDomains []string) bool {
+	if len(allowedDomains) == 0 {
+		return true
+	}
+	hostname = strings.TrimPrefix(strings.ToLower(hostname), "*.")
+	return slices.ContainsFunc(allowedDomains, func(domain string) bool {
+		return hostname == domain || strings.HasSuffix(hostname, "."+domain)
+	})
+}
+
+// gatewayClassToConfig maps a change of the pool's GatewayClass to the OrchestratorConfig it
+// now selects, so switching parametersRef applies the new config right away
+func (r *OrchestratorConfigReconciler) gatewayClassToConfig(ctx context.Context, obj client.Object) []reconcile.Request {
+	if obj.GetName() != r.GatewayClass {
+		return nil
+	}
+	name, err := r.configName(ctx)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to resolve the OrchestratorConfig of the GatewayClass", "gatewayClass", r.GatewayClass)
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name}}}
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *OrchestratorConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&gatewayv1alpha1.OrchestratorConfig{})
+	if r.GatewayClass != "" {
+		builder = builder.Watches(&gwapiv1.GatewayClass{}, handler.EnqueueRequestsFromMapFunc(r.gatewayClassToConfig))
+	}
+	return builder.Complete(r)
+}
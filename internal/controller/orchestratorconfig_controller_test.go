@@ -0,0 +1,208 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
+
+func TestOrchestratorConfigReconciler_AppliesAndRestoresDefaults(t *testing.T) {
+	ctx := context.Background()
+	config := &gatewayv1alpha1.OrchestratorConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Generation: 1},
+		Spec: gatewayv1alpha1.OrchestratorConfigSpec{
+			Pool: gatewayv1alpha1.OrchestratorConfigPool{
+				MaxCertificatesPerGateway: ptrTo(int32(10)),
+				HTTPSPort:                 ptrTo(int32(8443)),
+				HTTPSOnly:                 ptrTo(true),
+			},
+			CertificateTags: map[string]string{"cost-center": "platform"},
+			AllowedDomains:  []string{"Example.com."},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).
+		WithObjects(config).WithStatusSubresource(config).Build()
+	defaults := Settings{MaxCertificatesPerGateway: 25, HTTPPort: 80, HTTPSPort: 443}
+	pool := gateway.NewPool(c, "edge", "aws-alb", 80, 443)
+	r := &OrchestratorConfigReconciler{
+		Client: c, Recorder: record.NewFakeRecorder(10), GatewayPool: pool,
+		Settings: NewSettingsStore(defaults), Defaults: defaults,
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "default"}}
+
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 10, pool.MaxCertificates())
+	assert.Equal(t, int32(80), pool.HTTPPort())
+	assert.Equal(t, int32(8443), pool.HTTPSPort())
+	assert.True(t, pool.HTTPSOnly())
+	assert.Equal(t, []string{"example.com"}, r.Settings.Load().AllowedDomains)
+	assert.Equal(t, "platform", r.Settings.Load().CertificateTags["cost-center"])
+
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(config), config))
+	assert.True(t, conditions.IsTrue(config.Status.Conditions, ConditionTypeApplied))
+	assert.Equal(t, int64(1), config.Status.ObservedGeneration)
+
+	require.NoError(t, c.Delete(ctx, config))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 25, pool.MaxCertificates())
+	assert.Equal(t, int32(443), pool.HTTPSPort())
+	assert.False(t, pool.HTTPSOnly())
+	assert.Empty(t, r.Settings.Load().AllowedDomains)
+}
+
+func TestOrchestratorConfigReconciler_RejectsInvalid(t *testing.T) {
+	ctx := context.Background()
+	config := &gatewayv1alpha1.OrchestratorConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Generation: 2},
+		Spec: gatewayv1alpha1.OrchestratorConfigSpec{
+			Pool:           gatewayv1alpha1.OrchestratorConfigPool{MaxCertificatesPerGateway: ptrTo(int32(10))},
+			AllowedDomains: []string{"*.example.com"},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).
+		WithObjects(config).WithStatusSubresource(config).Build()
+	defaults := Settings{MaxCertificatesPerGateway: 25}
+	pool := gateway.NewPool(c, "edge", "aws-alb", 0, 0)
+	r := &OrchestratorConfigReconciler{
+		Client: c, Recorder: record.NewFakeRecorder(10), GatewayPool: pool,
+		Settings: NewSettingsStore(defaults), Defaults: defaults,
+	}
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "default"}})
+	require.NoError(t, err)
+	assert.Equal(t, gateway.MaxCertificatesPerGateway, pool.MaxCertificates(), "previous settings stay in effect")
+
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(config), config))
+	assert.True(t, conditions.HasReason(config.Status.Conditions, ConditionTypeApplied, metav1.ConditionFalse, ReasonConfigInvalid))
+}
+
+func TestOrchestratorConfigReconciler_GatewayClassParametersRef(t *testing.T) {
+	ctx := context.Background()
+	config := &gatewayv1alpha1.OrchestratorConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "alb-public", Generation: 1},
+		Spec: gatewayv1alpha1.OrchestratorConfigSpec{
+			Pool: gatewayv1alpha1.OrchestratorConfigPool{
+				MaxCertificatesPerGateway: ptrTo(int32(5)),
+				Subnets:                   gatewayv1alpha1.OrchestratorConfigSubnets{InternetFacing: []string{"subnet-0a1", "subnet-0b2"}},
+			},
+			DefaultWafArn: "arn:aws:wafv2:eu-west-1:123456789012:regional/webacl/baseline/1",
+		},
+	}
+	gc := &gwapiv1.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "aws-alb"},
+		Spec: gwapiv1.GatewayClassSpec{
+			ControllerName: "gateway.k8s.aws/alb",
+			ParametersRef:  &gwapiv1.ParametersReference{Group: "gateway.opendi.com", Kind: "OrchestratorConfig", Name: "alb-public"},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).
+		WithObjects(config, gc).WithStatusSubresource(config).Build()
+	defaults := Settings{MaxCertificatesPerGateway: 25}
+	pool := gateway.NewPool(c, "edge", "aws-alb", 0, 0)
+	r := &OrchestratorConfigReconciler{
+		Client: c, Recorder: record.NewFakeRecorder(10), GatewayPool: pool,
+		Settings: NewSettingsStore(defaults), Defaults: defaults, GatewayClass: "aws-alb",
+	}
+
+	assert.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "alb-public"}}}, r.gatewayClassToConfig(ctx, gc))
+
+	// The config named by the flag no longer applies
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "default"}})
+	require.NoError(t, err)
+	assert.Equal(t, gateway.MaxCertificatesPerGateway, pool.MaxCertificates())
+
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "alb-public"}})
+	require.NoError(t, err)
+	assert.Equal(t, 5, pool.MaxCertificates())
+	assert.Equal(t, []string{"subnet-0a1", "subnet-0b2"}, r.Settings.Load().Subnets["internet-facing"])
+
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "app.example.com"}}
+	ghrReconciler := &GatewayHostnameRequestReconciler{Settings: r.Settings}
+	assert.Equal(t, config.Spec.DefaultWafArn, ghrReconciler.wafArn(ghr))
+	ghr.Spec.AliasTarget = &gatewayv1alpha1.AliasTarget{DNSName: "d111111abcdef8.cloudfront.net"}
+	assert.Empty(t, ghrReconciler.wafArn(ghr), "DNS-only requests have no ALB")
+
+	// References to other kinds are the AWS Load Balancer Controller's
+	gc.Spec.ParametersRef = &gwapiv1.ParametersReference{Group: "gateway.k8s.aws", Kind: "LoadBalancerConfiguration", Name: "alb-public"}
+	require.NoError(t, c.Update(ctx, gc))
+	name, err := r.configName(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultOrchestratorConfigName, name)
+}
+
+func TestMergeSettings_PollBackoff(t *testing.T) {
+	defaults := Settings{CertificatePollBackoff: []time.Duration{time.Minute}}
+
+	settings, err := mergeSettings(defaults, &gatewayv1alpha1.OrchestratorConfigSpec{})
+	require.NoError(t, err)
+	assert.Equal(t, defaults, settings)
+
+	settings, err = mergeSettings(defaults, &gatewayv1alpha1.OrchestratorConfigSpec{
+		Requeue: gatewayv1alpha1.OrchestratorConfigRequeue{
+			CertificatePollBackoff: []metav1.Duration{{Duration: 10 * time.Second}, {Duration: 2 * time.Minute}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{10 * time.Second, 2 * time.Minute}, settings.CertificatePollBackoff)
+
+	r := &GatewayHostnameRequestReconciler{Settings: NewSettingsStore(settings)}
+	assert.Equal(t, 10*time.Second, r.certificatePollInterval(0))
+	assert.Equal(t, 2*time.Minute, r.certificatePollInterval(time.Hour))
+}
+
+func TestHostnameAllowed(t *testing.T) {
+	allowed := []string{"example.com", "example.org"}
+	assert.True(t, hostnameAllowed("anything.test", nil))
+	assert.True(t, hostnameAllowed("example.com", allowed))
+	assert.True(t, hostnameAllowed("api.eu.example.org", allowed))
+	assert.True(t, hostnameAllowed("*.example.com", allowed))
+	assert.False(t, hostnameAllowed("badexample.com", allowed))
+	assert.False(t, hostnameAllowed("example.net", allowed))
+}
+
+func TestValidateRequest_AllowedDomains(t *testing.T) {
+	r := &GatewayHostnameRequestReconciler{Settings: NewSettingsStore(Settings{AllowedDomains: []string{"example.com"}})}
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "api.example.net", ZoneId: "Z123"},
+	}
+	assert.EqualError(t, r.validateRequest(ghr), "hostname api.example.net is not in the allowed domains example.com")
+
+	// Hostnames claimed before the allowlist changed are not affected
+	conditions.Set(&ghr.Status.Conditions, ConditionTypeClaimed, metav1.ConditionTrue, conditions.ReasonClaimed, "", 0)
+	assert.NoError(t, r.validateRequest(ghr))
+}
+
+func TestCertificateTags_OwnTagsWin(t *testing.T) {
+	r := &GatewayHostnameRequestReconciler{Settings: NewSettingsStore(Settings{
+		CertificateTags: map[string]string{"cost-center": "platform", "managed-by": "someone-else"},
+	})}
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop"},
+		Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "*.example.com"},
+	}
+	tags := r.certificateTags(ghr)
+	assert.Equal(t, "platform", tags["cost-center"])
+	assert.Equal(t, certificateManagedBy, tags["managed-by"])
+	assert.Equal(t, "wildcard.example.com", tags["hostname"])
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// overrideTarget returns the spec.overrideTarget the controller honors: none unless the
+// admission webhook restricts the field to the override target groups
+func (r *GatewayHostnameRequestReconciler) overrideTarget(ghr *gatewayv1alpha1.GatewayHostnameRequest) *gatewayv1alpha1.AliasTarget {
+	if !r.OverrideTargetAdmission {
+		return nil
+	}
+	return ghr.Spec.OverrideTarget
+}
+
+// overrideTargetInSync reports whether the ALIAS records point at the honored
+// spec.overrideTarget, or at the ALB without one
+func (r *GatewayHostnameRequestReconciler) overrideTargetInSync(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	target := r.overrideTarget(ghr)
+	if target == nil {
+		return ghr.Status.OverrideTarget == ""
+	}
+	return ghr.Status.OverrideTarget == target.DNSName
+}
+
+// publishOverrideTarget points the ALIAS records at the override target instead of the ALB.
+// The Gateway keeps the hostname's certificate and routes, so clearing the override only
+// switches DNS back.
+func (r *GatewayHostnameRequestReconciler) publishOverrideTarget(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, target *gatewayv1alpha1.AliasTarget) error {
+	aliasTarget, err := externalAliasTarget(target)
+	if err != nil {
+		return err
+	}
+	recordTypes := requestedRecordTypes(ghr, externalAliasRecordTypes(target))
+	if err := r.publishAliasRecords(ctx, ghr, aliasTarget, recordTypes); err != nil {
+		return err
+	}
+	if ghr.Status.OverrideTarget != target.DNSName {
+		r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "TargetOverridden", "DNS points at %s instead of the ALB %s", target.DNSName, ghr.Status.AssignedLoadBalancer)
+	}
+	ghr.Status.OverrideTarget = target.DNSName
+
+	log.FromContext(ctx).Info("Created Route53 ALIAS records for override target",
+		"types", recordTypes,
+		"target", target.DNSName,
+		"loadBalancer", ghr.Status.AssignedLoadBalancer,
+		"zoneIds", ghr.Status.AliasZoneIds)
+	return nil
+}
+
+// publishedAliasTarget returns the DNS name the request's ALIAS records point at
+func publishedAliasTarget(ghr *gatewayv1alpha1.GatewayHostnameRequest) string {
+	if ghr.Status.OverrideTarget != "" {
+		return ghr.Status.OverrideTarget
+	}
+	return ghr.Status.AssignedLoadBalancer
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+func TestEnsureRoute53Alias_OverrideTarget(t *testing.T) {
+	ctx := context.Background()
+	lbDNS := "k8s-gw01-abcdef1234-1234567890.eu-west-1.elb.amazonaws.com"
+	hostnameType := gwapiv1.HostnameAddressType
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw-01", Namespace: "edge"},
+		Status:     gwapiv1.GatewayStatus{Addresses: []gwapiv1.GatewayStatusAddress{{Type: &hostnameType, Value: lbDNS}}},
+	}
+	ghr := assignedGHR("app", "app.example.com")
+	ghr.Spec.ZoneId = "Z123456"
+	ghr.Spec.OverrideTarget = &gatewayv1alpha1.AliasTarget{DNSName: "maintenance.example.com.s3-website-eu-west-1.amazonaws.com"}
+
+	route53Mock := &MockRoute53Client{records: make(map[string][]aws.DNSRecord)}
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(gw, ghr).Build()
+	r := &GatewayHostnameRequestReconciler{
+		Client:        c,
+		Recorder:      record.NewFakeRecorder(10),
+		Route53Client: route53Mock,
+		GatewayPool:   gateway.NewPool(c, "edge", "aws-alb", 0, 0),
+
+		OverrideTargetAdmission: true,
+	}
+	assert.False(t, r.overrideTargetInSync(ghr))
+
+	require.NoError(t, r.ensureRoute53Alias(ctx, ghr))
+	records := route53Mock.records["Z123456"]
+	require.Len(t, records, 1)
+	assert.Equal(t, "maintenance.example.com.s3-website-eu-west-1.amazonaws.com", records[0].AliasTarget.DNSName)
+	assert.Equal(t, aws.S3WebsiteHostedZoneIDs["eu-west-1"], records[0].AliasTarget.HostedZoneID)
+	assert.False(t, records[0].AliasTarget.EvaluateTargetHealth)
+	assert.Equal(t, lbDNS, ghr.Status.AssignedLoadBalancer, "the hostname keeps its ALB")
+	assert.Equal(t, "maintenance.example.com.s3-website-eu-west-1.amazonaws.com", ghr.Status.OverrideTarget)
+	assert.True(t, r.overrideTargetInSync(ghr))
+	assert.True(t, r.aliasRecordTypesInSync(ctx, ghr))
+
+	require.NoError(t, c.Update(ctx, ghr))
+	targets, err := r.managedAliasTargets(ctx)
+	require.NoError(t, err)
+	assert.True(t, targets["maintenance.example.com.s3-website-eu-west-1.amazonaws.com"])
+
+	// Clearing the override points the hostname back at the ALB
+	ghr.Spec.OverrideTarget = nil
+	assert.False(t, r.overrideTargetInSync(ghr))
+	route53Mock.records["Z123456"] = nil
+	require.NoError(t, r.ensureRoute53Alias(ctx, ghr))
+	records = route53Mock.records["Z123456"]
+	require.Len(t, records, 1)
+	assert.Equal(t, lbDNS, records[0].AliasTarget.DNSName)
+	assert.True(t, records[0].AliasTarget.EvaluateTargetHealth)
+	assert.Empty(t, ghr.Status.OverrideTarget)
+	assert.True(t, r.overrideTargetInSync(ghr))
+
+	// Without the admission webhook guarding the field, the override is ignored
+	ghr.Spec.OverrideTarget = &gatewayv1alpha1.AliasTarget{DNSName: "maintenance.example.com.s3-website-eu-west-1.amazonaws.com"}
+	r.OverrideTargetAdmission = false
+	assert.True(t, r.overrideTargetInSync(ghr))
+	route53Mock.records["Z123456"] = nil
+	require.NoError(t, r.ensureRoute53Alias(ctx, ghr))
+	records = route53Mock.records["Z123456"]
+	require.Len(t, records, 1)
+	assert.Equal(t, lbDNS, records[0].AliasTarget.DNSName)
+	assert.Empty(t, ghr.Status.OverrideTarget)
+}
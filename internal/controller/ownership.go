@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// Ownership annotations on Gateways and LoadBalancerConfigurations. The controller sets them on
+// every write of the fields it manages, so other automation can tell those fields apart and
+// notice changes to them. Automation that takes the fields over sets AnnotationManagedBy to its
+// own name, and the controller stops writing the object instead of reverting it.
+const (
+	// AnnotationManagedBy names the manager of the managed fields
+	AnnotationManagedBy = "gateway.opendi.com/managed-by"
+	// AnnotationManagedGeneration is the metadata.generation the object had after the
+	// controller's last write
+	AnnotationManagedGeneration = "gateway.opendi.com/managed-generation"
+	// AnnotationManagedChecksum is a checksum of the managed fields as the controller last
+	// wrote them
+	AnnotationManagedChecksum = "gateway.opendi.com/managed-checksum"
+)
+
+// ManagerName is the value of AnnotationManagedBy on objects the controller manages
+const ManagerName = "gateway-orchestrator"
+
+// ForeignOwnershipError is returned instead of writing an object another manager claimed
+type ForeignOwnershipError struct {
+	// Object is the kind and namespaced name of the object
+	Object string
+	// Manager is the value of its AnnotationManagedBy
+	Manager string
+}
+
+func (e *ForeignOwnershipError) Error() string {
+	return fmt.Sprintf("%s is managed by %s", e.Object, e.Manager)
+}
+
+// foreignManager returns the manager an object's AnnotationManagedBy names, or "" if it is
+// unset or names the controller
+func foreignManager(obj metav1.Object) string {
+	manager := obj.GetAnnotations()[AnnotationManagedBy]
+	if manager == ManagerName {
+		return ""
+	}
+	return manager
+}
+
+// foreignOwnership returns a ForeignOwnershipError if another manager claimed the object, so
+// callers can check before each write; kind names the object in the error
+func foreignOwnership(kind string, obj metav1.Object) error {
+	if manager := foreignManager(obj); manager != "" {
+		return &ForeignOwnershipError{Object: kind + " " + obj.GetNamespace() + "/" + obj.GetName(), Manager: manager}
+	}
+	return nil
+}
+
+// managedChecksum returns the checksum of the managed fields for AnnotationManagedChecksum
+func managedChecksum(fields interface{}) string {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		data = []byte(fmt.Sprint(fields))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// stampManaged sets the ownership annotations for a write of the managed fields; generation is
+// the generation the object has after the write
+func stampManaged(obj metav1.Object, fields interface{}, generation int64) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AnnotationManagedBy] = ManagerName
+	annotations[AnnotationManagedGeneration] = strconv.FormatInt(generation, 10)
+	annotations[AnnotationManagedChecksum] = managedChecksum(fields)
+	obj.SetAnnotations(annotations)
+}
+
+// managedGatewayAnnotations returns the values of the Gateway annotations the controller manages,
+// which its checksum covers
+func managedGatewayAnnotations(annotations map[string]string) map[string]string {
+	managed := make(map[string]string, len(driftedGatewayAnnotations))
+	for _, key := range driftedGatewayAnnotations {
+		managed[key] = annotations[key]
+	}
+	return managed
+}
+
+// reportForeignOwnership keeps ForeignOwnership current after the Gateway configuration sync:
+// True while it stopped at an object another manager claimed, removed once the sync succeeds
+func (r *GatewayHostnameRequestReconciler) reportForeignOwnership(ghr *gatewayv1alpha1.GatewayHostnameRequest, err error) {
+	var foreign *ForeignOwnershipError
+	if !errors.As(err, &foreign) {
+		if err == nil {
+			conditions.Remove(&ghr.Status.Conditions, ConditionTypeForeignOwnership)
+		}
+		return
+	}
+	message := foreign.Error() + "; its configuration is left to that manager"
+	if !conditions.IsTrue(ghr.Status.Conditions, ConditionTypeForeignOwnership) {
+		r.Recorder.Event(ghr, corev1.EventTypeWarning, "ForeignOwnership", message)
+	}
+	r.setCondition(ghr, ConditionTypeForeignOwnership, metav1.ConditionTrue, conditions.ReasonForeignManager, message)
+}
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+func ownershipTestSetup(gatewayManager, configManager string) (*GatewayHostnameRequestReconciler, *gatewayv1alpha1.GatewayHostnameRequest) {
+	scheme := runtime.NewScheme()
+	_ = gatewayv1alpha1.AddToScheme(scheme)
+	_ = gwapiv1.Install(scheme)
+
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+		Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "app.example.com", ZoneId: "Z123"},
+		Status: gatewayv1alpha1.GatewayHostnameRequestStatus{
+			AssignedGateway:          "gw-01",
+			AssignedGatewayNamespace: "edge",
+			CertificateArn:           "arn:aws:acm:us-east-1:123456789012:certificate/app",
+		},
+	}
+	gw := &gwapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gw-01", Namespace: "edge", Generation: 3}}
+	if gatewayManager != "" {
+		gw.Annotations = map[string]string{AnnotationManagedBy: gatewayManager}
+	}
+	lbc := &unstructured.Unstructured{}
+	lbc.SetGroupVersionKind(LoadBalancerConfigurationGVK)
+	lbc.SetName("gw-01-config")
+	lbc.SetNamespace("edge")
+	if configManager != "" {
+		lbc.SetAnnotations(map[string]string{AnnotationManagedBy: configManager})
+	}
+
+	return &GatewayHostnameRequestReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(ghr, gw, lbc).Build(),
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(10),
+	}, ghr
+}
+
+func TestEnsureGatewayConfiguration_StampsOwnership(t *testing.T) {
+	ctx := context.Background()
+	r, ghr := ownershipTestSetup("", "")
+
+	require.NoError(t, r.ensureGatewayConfiguration(ctx, ghr))
+
+	var gw gwapiv1.Gateway
+	require.NoError(t, r.Get(ctx, types.NamespacedName{Name: "gw-01", Namespace: "edge"}, &gw))
+	assert.Equal(t, ManagerName, gw.Annotations[AnnotationManagedBy])
+	assert.Equal(t, "3", gw.Annotations[AnnotationManagedGeneration], "annotations don't change the generation")
+	assert.Equal(t, managedChecksum(managedGatewayAnnotations(gw.Annotations)), gw.Annotations[AnnotationManagedChecksum])
+
+	lbc, err := r.getLoadBalancerConfiguration(ctx, types.NamespacedName{Name: "gw-01-config", Namespace: "edge"})
+	require.NoError(t, err)
+	spec, _, _ := unstructured.NestedMap(lbc.Object, "spec")
+	assert.Equal(t, ManagerName, lbc.GetAnnotations()[AnnotationManagedBy])
+	assert.Equal(t, "1", lbc.GetAnnotations()[AnnotationManagedGeneration], "the spec changed")
+	assert.Equal(t, managedChecksum(spec), lbc.GetAnnotations()[AnnotationManagedChecksum])
+}
+
+func TestEnsureGatewayConfiguration_ForeignOwnership(t *testing.T) {
+	tests := []struct {
+		name           string
+		gatewayManager string
+		configManager  string
+		object         string
+	}{
+		{name: "gateway", gatewayManager: "platform-operator", object: "Gateway edge/gw-01"},
+		{name: "load balancer configuration", configManager: "platform-operator", object: "LoadBalancerConfiguration edge/gw-01-config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			r, ghr := ownershipTestSetup(tt.gatewayManager, tt.configManager)
+
+			err := r.ensureGatewayConfiguration(ctx, ghr)
+			var foreign *ForeignOwnershipError
+			require.True(t, errors.As(err, &foreign), "got %v", err)
+			assert.Equal(t, tt.object, foreign.Object)
+			assert.Equal(t, "platform-operator", foreign.Manager)
+
+			var gw gwapiv1.Gateway
+			require.NoError(t, r.Get(ctx, types.NamespacedName{Name: "gw-01", Namespace: "edge"}, &gw))
+			assert.Empty(t, gw.Annotations["gateway.k8s.aws/loadbalancer-configuration"], "the Gateway isn't written")
+
+			r.reportForeignOwnership(ghr, err)
+			cond := conditions.Get(ghr.Status.Conditions, ConditionTypeForeignOwnership)
+			require.NotNil(t, cond)
+			assert.Equal(t, metav1.ConditionTrue, cond.Status)
+			assert.Equal(t, string(conditions.ReasonForeignManager), cond.Reason)
+			assert.Contains(t, <-r.Recorder.(*record.FakeRecorder).Events, "ForeignOwnership")
+
+			r.reportForeignOwnership(ghr, nil)
+			assert.Nil(t, conditions.Get(ghr.Status.Conditions, ConditionTypeForeignOwnership), "removed once managed again")
+		})
+	}
+}
+
+func TestForeignGatewayIsLeftAlone(t *testing.T) {
+	ctx := context.Background()
+	r, ghr := ownershipTestSetup("platform-operator", "")
+	var gw gwapiv1.Gateway
+	require.NoError(t, r.Get(ctx, types.NamespacedName{Name: "gw-01", Namespace: "edge"}, &gw))
+	gw.Spec.Listeners = []gwapiv1.Listener{{Name: "https", Port: 443, Protocol: gwapiv1.HTTPSProtocolType}}
+	require.NoError(t, r.Update(ctx, &gw))
+
+	var foreign *ForeignOwnershipError
+	err := r.ensureAllowedRoutes(ctx, ghr)
+	require.True(t, errors.As(err, &foreign), "got %v", err)
+
+	require.NoError(t, r.cleanupEmptyGateway(ctx, "gw-01", "edge", ghr.Namespace, ghr.Name))
+	require.NoError(t, r.Get(ctx, types.NamespacedName{Name: "gw-01", Namespace: "edge"}, &gw), "the empty Gateway is kept")
+	assert.Nil(t, gw.Spec.Listeners[0].AllowedRoutes)
+	assert.Empty(t, gw.Annotations[gateway.AnnotationDeletionLease])
+
+	a := &Admin{Reconciler: r, Token: "secret"}
+	rec, _ := callAdmin(a, http.MethodPost, AdminPath+OperationDrainGateway+"?gateway=edge/gw-01")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	require.NoError(t, r.Get(ctx, types.NamespacedName{Name: "gw-01", Namespace: "edge"}, &gw))
+	assert.Empty(t, gw.Annotations[gateway.AnnotationCordoned])
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/policy"
+)
+
+// policyRecheckInterval is how often denied requests are checked again, so they are
+// provisioned once the policy allows them
+const policyRecheckInterval = 10 * time.Minute
+
+// reservedAnnotationPrefix marks the controller's own annotations, which the policy can't set
+const reservedAnnotationPrefix = "gateway.opendi.com/"
+
+// Policy check results used as the "result" label on policyChecksTotal
+const (
+	PolicyAllowed = "allowed"
+	PolicyDenied  = "denied"
+	PolicyError   = "error"
+)
+
+var policyChecksTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_orchestrator_policy_checks_total",
+	Help: "Hostname policy webhook calls by result: allowed, denied or error.",
+}, []string{"result"})
+
+func init() {
+	metrics.Registry.MustRegister(policyChecksTotal)
+}
+
+// checkPolicy asks the policy whether the request may be provisioned and adds the annotations
+// it returns. A denial sets Ready=False with reason PolicyDenied. If the webhook fails, the
+// error is returned so the call is retried, unless PolicyFailOpen allows the request.
+func (r *GatewayHostnameRequestReconciler) checkPolicy(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (bool, error) {
+	logger := log.FromContext(ctx)
+
+	decision, err := r.Policy.Check(ctx, policy.NewReview(ghr))
+	if err != nil {
+		policyChecksTotal.WithLabelValues(PolicyError).Inc()
+		if r.PolicyFailOpen {
+			logger.Info("Hostname policy check failed, allowing request", "error", err.Error())
+			return true, nil
+		}
+		r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "PolicyCheckFailed", "Hostname policy check failed: %v", err)
+		return false, fmt.Errorf("hostname policy check failed: %w", err)
+	}
+
+	changed, skipped := annotatePolicyDecision(ghr, decision.Annotations)
+	for _, key := range skipped {
+		r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "PolicyAnnotationSkipped",
+			"Hostname policy annotation %s looks like %s and was not added", key, secretKind(decision.Annotations[key]))
+	}
+	if changed {
+		if err := r.Update(ctx, ghr); err != nil {
+			return false, fmt.Errorf("failed to add policy annotations: %w", err)
+		}
+	}
+
+	if !decision.Allowed {
+		policyChecksTotal.WithLabelValues(PolicyDenied).Inc()
+		reason := decision.Reason
+		if reason == "" {
+			reason = "denied by hostname policy"
+		}
+		if !conditions.HasReason(ghr.Status.Conditions, ConditionTypeReady, metav1.ConditionFalse, conditions.ReasonPolicyDenied) {
+			r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "PolicyDenied", "Hostname policy denied the request: %s", reason)
+		}
+		r.setCondition(ghr, ConditionTypeReady, metav1.ConditionFalse, conditions.ReasonPolicyDenied, reason)
+		if err := r.Status().Update(ctx, ghr); err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+
+	policyChecksTotal.WithLabelValues(PolicyAllowed).Inc()
+	if conditions.HasReason(ghr.Status.Conditions, ConditionTypeReady, metav1.ConditionFalse, conditions.ReasonPolicyDenied) {
+		// The policy changed its mind; provisioning sets Ready again
+		conditions.Remove(&ghr.Status.Conditions, ConditionTypeReady)
+		r.Recorder.Event(ghr, corev1.EventTypeNormal, "PolicyAllowed", "Hostname policy now allows the request")
+	}
+	return true, nil
+}
+
+// annotatePolicyDecision adds the annotations returned by the policy, skipping reserved keys.
+// Returns true if an annotation was added or changed, and the sorted keys skipped because
+// their values look like secrets.
+func annotatePolicyDecision(ghr *gatewayv1alpha1.GatewayHostnameRequest, annotations map[string]string) (bool, []string) {
+	changed := false
+	var skipped []string
+	for key, value := range annotations {
+		if strings.HasPrefix(key, reservedAnnotationPrefix) || ghr.Annotations[key] == value {
+			continue
+		}
+		if secretKind(value) != "" {
+			skipped = append(skipped, key)
+			continue
+		}
+		if ghr.Annotations == nil {
+			ghr.Annotations = map[string]string{}
+		}
+		ghr.Annotations[key] = value
+		changed = true
+	}
+	sort.Strings(skipped)
+	return changed, skipped
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/policy"
+)
+
+// staticPolicy returns a fixed decision and records the reviews it got
+type staticPolicy struct {
+	decision policy.Decision
+	err      error
+	reviews  []policy.Review
+}
+
+func (p *staticPolicy) Check(ctx context.Context, review policy.Review) (policy.Decision, error) {
+	p.reviews = append(p.reviews, review)
+	return p.decision, p.err
+}
+
+func TestCheckPolicy_DenyThenAllow(t *testing.T) {
+	ctx := context.Background()
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default"},
+		Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "contoso.example.com", ZoneId: "Z123"},
+	}
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr).WithStatusSubresource(ghr).Build()
+	p := &staticPolicy{decision: policy.Decision{
+		Reason: "contoso is a protected trademark",
+		Annotations: map[string]string{
+			"policy.example.com/ticket":    "LEGAL-42",
+			AnnotationConfirmGatewayChange: "gw-01",
+		},
+	}}
+	r := &GatewayHostnameRequestReconciler{Client: c, Recorder: record.NewFakeRecorder(10), Policy: p}
+
+	allowed, err := r.checkPolicy(ctx, ghr)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	require.Len(t, p.reviews, 1)
+	assert.Equal(t, "contoso.example.com", p.reviews[0].Spec.Hostname)
+
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(ghr), ghr))
+	assert.Equal(t, "LEGAL-42", ghr.Annotations["policy.example.com/ticket"])
+	assert.NotContains(t, ghr.Annotations, AnnotationConfirmGatewayChange, "controller annotations are reserved")
+	assert.True(t, conditions.HasReason(ghr.Status.Conditions, ConditionTypeReady, metav1.ConditionFalse, conditions.ReasonPolicyDenied))
+	assert.Equal(t, "Denied by the hostname policy: contoso is a protected trademark", ghr.Status.Message)
+
+	p.decision = policy.Decision{Allowed: true}
+	allowed, err = r.checkPolicy(ctx, ghr)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	assert.Nil(t, conditions.Get(ghr.Status.Conditions, ConditionTypeReady), "the denial is cleared")
+}
+
+func TestCheckPolicy_Failure(t *testing.T) {
+	ctx := context.Background()
+	ghr := assignedGHR("app", "app.example.com")
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr).WithStatusSubresource(ghr).Build()
+	p := &staticPolicy{err: errors.New("connection refused")}
+	r := &GatewayHostnameRequestReconciler{Client: c, Recorder: record.NewFakeRecorder(10), Policy: p}
+
+	_, err := r.checkPolicy(ctx, ghr)
+	assert.ErrorContains(t, err, "connection refused")
+
+	r.PolicyFailOpen = true
+	allowed, err := r.checkPolicy(ctx, ghr)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+}
@@ -0,0 +1,199 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+// privateZoneAssociation formats an association for status.privateZoneVpcs as
+// <zoneId>/<region>/<vpcId>
+func privateZoneAssociation(zoneID string, vpc aws.VPC) string {
+	return zoneID + "/" + vpc.String()
+}
+
+// parsePrivateZoneAssociation is the inverse of privateZoneAssociation
+func parsePrivateZoneAssociation(association string) (string, aws.VPC, bool) {
+	parts := strings.SplitN(association, "/", 3)
+	if len(parts) != 3 {
+		return "", aws.VPC{}, false
+	}
+	return parts[0], aws.VPC{Region: parts[1], ID: parts[2]}, true
+}
+
+// wantedPrivateZoneVPCs returns the associations an internal request needs: each private zone
+// its ALIAS records are published in with each VPC of the settings, sorted. Whether a zone is
+// private never changes, so it is looked up once per zone.
+func (r *GatewayHostnameRequestReconciler) wantedPrivateZoneVPCs(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) ([]string, error) {
+	if ghr.Spec.Visibility != "internal" || r.Settings == nil || len(r.Settings.Load().PrivateZoneVPCs) == 0 {
+		return nil, nil
+	}
+	var want []string
+	for _, zoneID := range aliasZoneIds(ghr) {
+		private, known := r.privateZones.Load(zoneID)
+		if !known {
+			awsCtx, cancel := withAWSTimeout(ctx)
+			zone, err := r.Route53Client.GetHostedZone(awsCtx, zoneID)
+			cancel()
+			if err != nil {
+				return nil, err
+			}
+			private = zone.Private
+			r.privateZones.Store(zoneID, private)
+		}
+		if !private.(bool) {
+			continue
+		}
+		for _, vpc := range r.Settings.Load().PrivateZoneVPCs {
+			want = append(want, privateZoneAssociation(zoneID, vpc))
+		}
+	}
+	slices.Sort(want)
+	return want, nil
+}
+
+// reconcilePrivateZoneVPCs associates the private hosted zones of an internal request with the
+// VPCs of the settings and removes the associations it no longer needs. It only calls AWS when
+// they changed or a full reconcile is requested.
+func (r *GatewayHostnameRequestReconciler) reconcilePrivateZoneVPCs(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	want, err := r.wantedPrivateZoneVPCs(ctx, ghr)
+	if err != nil {
+		return fmt.Errorf("failed to look up hosted zones: %w", err)
+	}
+	if r.privateZoneVPCsInSync(ghr, want) && !reconcileNowPending(ghr) {
+		return nil
+	}
+	return r.syncPrivateZoneVPCs(ctx, ghr, want)
+}
+
+// privateZoneVPCsInSync reports whether status.privateZoneVpcs holds exactly the wanted
+// associations, apart from ones that existed without the controller
+func (r *GatewayHostnameRequestReconciler) privateZoneVPCsInSync(ghr *gatewayv1alpha1.GatewayHostnameRequest, want []string) bool {
+	for _, association := range ghr.Status.PrivateZoneVpcs {
+		if !slices.Contains(want, association) {
+			return false
+		}
+	}
+	for _, association := range want {
+		if _, unmanaged := r.unmanagedZoneVPCs.Load(association); !unmanaged && !slices.Contains(ghr.Status.PrivateZoneVpcs, association) {
+			return false
+		}
+	}
+	return true
+}
+
+// managedPrivateZoneVPC reports whether another request lists the association, so the
+// controller created it and the request shares it
+func (r *GatewayHostnameRequestReconciler) managedPrivateZoneVPC(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, association string) (bool, error) {
+	var requests gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &requests); err != nil {
+		return false, fmt.Errorf("failed to list requests: %w", err)
+	}
+	for _, other := range requests.Items {
+		self := other.Namespace == ghr.Namespace && other.Name == ghr.Name
+		if !self && slices.Contains(other.Status.PrivateZoneVpcs, association) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// syncPrivateZoneVPCs makes status.privateZoneVpcs the wanted associations the controller
+// manages: missing ones are created, and ones no longer wanted are removed unless another
+// request still lists them. Associations that existed without the controller, such as the
+// VPC a zone was created with, are left out of the status, so they are never removed.
+// Associations that could not be removed stay in the status, so they are retried.
+func (r *GatewayHostnameRequestReconciler) syncPrivateZoneVPCs(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, want []string) error {
+	logger := log.FromContext(ctx)
+	awsCtx, cancel := withAWSTimeout(ctx)
+	defer cancel()
+
+	var errs []error
+	var current []string
+	zones := map[string]*aws.HostedZone{}
+	for _, association := range want {
+		zoneID, vpc, _ := parsePrivateZoneAssociation(association)
+		zone, ok := zones[zoneID]
+		if !ok {
+			var err error
+			if zone, err = r.Route53Client.GetHostedZone(awsCtx, zoneID); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			zones[zoneID] = zone
+		}
+		if !slices.Contains(zone.VPCs, vpc) {
+			if err := r.Route53Client.AssociateVPC(awsCtx, zoneID, vpc); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			logger.Info("Associated private hosted zone with VPC", "zoneId", zoneID, "vpc", vpc)
+			r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "VPCAssociated", "Associated hosted zone %s with VPC %s", zoneID, vpc)
+			current = append(current, association)
+			continue
+		}
+
+		// Already associated: by this request, by another request, or without the controller
+		managed := slices.Contains(ghr.Status.PrivateZoneVpcs, association)
+		if !managed {
+			var err error
+			if managed, err = r.managedPrivateZoneVPC(ctx, ghr, association); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		if !managed {
+			r.unmanagedZoneVPCs.Store(association, true)
+			continue
+		}
+		r.unmanagedZoneVPCs.Delete(association)
+		current = append(current, association)
+	}
+
+	for _, association := range ghr.Status.PrivateZoneVpcs {
+		if slices.Contains(want, association) {
+			continue
+		}
+		if err := r.releasePrivateZoneVPC(awsCtx, ghr, association); err != nil {
+			errs = append(errs, err)
+			current = append(current, association)
+		}
+	}
+
+	slices.Sort(current)
+	ghr.Status.PrivateZoneVpcs = current
+	return errors.Join(errs...)
+}
+
+// releasePrivateZoneVPC removes an association unless another request that isn't being deleted
+// still lists it
+func (r *GatewayHostnameRequestReconciler) releasePrivateZoneVPC(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, association string) error {
+	zoneID, vpc, ok := parsePrivateZoneAssociation(association)
+	if !ok {
+		return nil
+	}
+	var requests gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &requests); err != nil {
+		return fmt.Errorf("failed to list requests: %w", err)
+	}
+	for _, other := range requests.Items {
+		self := other.Namespace == ghr.Namespace && other.Name == ghr.Name
+		if !self && other.DeletionTimestamp.IsZero() && slices.Contains(other.Status.PrivateZoneVpcs, association) {
+			return nil
+		}
+	}
+	if err := r.Route53Client.DisassociateVPC(ctx, zoneID, vpc); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("Disassociated private hosted zone from VPC", "zoneId", zoneID, "vpc", vpc)
+	r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "VPCDisassociated", "Disassociated hosted zone %s from VPC %s", zoneID, vpc)
+	return nil
+}
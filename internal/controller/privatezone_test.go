@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+func TestReconcilePrivateZoneVPCs(t *testing.T) {
+	ctx := context.Background()
+	home := aws.VPC{ID: "vpc-0home", Region: "eu-west-1"}
+	spoke := aws.VPC{ID: "vpc-0spoke", Region: "eu-central-1"}
+	route53 := aws.NewMockRoute53Client()
+	route53.HostedZones["corp.example.com"] = &aws.HostedZone{ID: "ZPRIVATE", Name: "corp.example.com", Private: true, VPCs: []aws.VPC{home}}
+	route53.HostedZones["example.com"] = &aws.HostedZone{ID: "ZPUBLIC", Name: "example.com"}
+
+	api := assignedGHR("api", "api.corp.example.com")
+	api.Spec.ZoneId, api.Spec.Visibility = "ZPRIVATE", "internal"
+	web := assignedGHR("web", "web.corp.example.com")
+	web.Spec.ZoneId, web.Spec.Visibility = "ZPRIVATE", "internal"
+	public := assignedGHR("public", "public.example.com")
+	public.Spec.ZoneId, public.Spec.Visibility = "ZPUBLIC", "internal"
+
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(api, web, public).WithStatusSubresource(api, web, public).Build()
+	settings := NewSettingsStore(Settings{PrivateZoneVPCs: []aws.VPC{home, spoke}})
+	r := &GatewayHostnameRequestReconciler{Client: c, Recorder: record.NewFakeRecorder(10), Route53Client: route53, Settings: settings}
+
+	// The zone was created with the home VPC, so only the spoke association is the controller's
+	require.NoError(t, r.reconcilePrivateZoneVPCs(ctx, api))
+	assert.Equal(t, []string{"ZPRIVATE/eu-central-1/vpc-0spoke"}, api.Status.PrivateZoneVpcs)
+	assert.Equal(t, []aws.VPC{home, spoke}, route53.HostedZones["corp.example.com"].VPCs)
+	assert.True(t, r.privateZoneVPCsInSync(api, []string{"ZPRIVATE/eu-central-1/vpc-0spoke", "ZPRIVATE/eu-west-1/vpc-0home"}))
+	require.NoError(t, c.Status().Update(ctx, api))
+
+	// Other requests in the zone share the associations
+	require.NoError(t, r.reconcilePrivateZoneVPCs(ctx, web))
+	assert.Equal(t, api.Status.PrivateZoneVpcs, web.Status.PrivateZoneVpcs)
+	require.NoError(t, c.Status().Update(ctx, web))
+
+	// Public zones resolve everywhere already
+	require.NoError(t, r.reconcilePrivateZoneVPCs(ctx, public))
+	assert.Empty(t, public.Status.PrivateZoneVpcs)
+
+	// Associations are kept while another request needs them
+	require.NoError(t, r.syncPrivateZoneVPCs(ctx, api, nil))
+	assert.Empty(t, api.Status.PrivateZoneVpcs)
+	assert.Equal(t, []aws.VPC{home, spoke}, route53.HostedZones["corp.example.com"].VPCs)
+	require.NoError(t, c.Delete(ctx, api))
+
+	// VPCs dropped from the settings are disassociated
+	settings.Store(Settings{PrivateZoneVPCs: []aws.VPC{home}})
+	require.NoError(t, r.reconcilePrivateZoneVPCs(ctx, web))
+	assert.Empty(t, web.Status.PrivateZoneVpcs)
+	assert.Equal(t, []aws.VPC{home}, route53.HostedZones["corp.example.com"].VPCs)
+
+	// Associations the controller didn't create are never removed
+	require.NoError(t, r.syncPrivateZoneVPCs(ctx, web, nil))
+	assert.Equal(t, []aws.VPC{home}, route53.HostedZones["corp.example.com"].VPCs)
+}
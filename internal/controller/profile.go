@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Controller profiles, selected with --profile: presets for the flags of the optional subsystems
+const (
+	// ProfileCustom leaves every subsystem to its own flag
+	ProfileCustom = "custom"
+
+	// ProfileMinimal runs only the reconcilers, for small clusters: no webhooks, rebalancing,
+	// duplicate certificate sweeps, failover, alarms, probes, fleet health or cost metrics
+	ProfileMinimal = "minimal"
+
+	// ProfileFull turns on every optional subsystem that needs no further configuration, for
+	// large platforms. The webhooks need their serving certificates (see config/webhook).
+	ProfileFull = "full"
+)
+
+// profileFlags are the flag values each profile sets
+var profileFlags = map[string]map[string]string{
+	ProfileMinimal: {
+		"enable-webhooks":                      "false",
+		"rebalance-over-capacity":              "false",
+		"duplicate-certificate-check-interval": "0",
+		"gateway-failover-interval":            "0",
+		"load-balancer-alarms-interval":        "0",
+		"probe-interval":                       "0",
+		"fleet-health-interval":                "0",
+		"cost-estimates":                       "false",
+	},
+	ProfileFull: {
+		"enable-webhooks":               "true",
+		"rebalance-over-capacity":       "true",
+		"repair-route-parent-refs":      "true",
+		"strict-route-access":           "true",
+		"gateway-failover-interval":     "1m",
+		"load-balancer-alarms-interval": "10m",
+		"probe-interval":                "1m",
+		"fleet-health-interval":         "1m",
+		"cost-estimates":                "true",
+	},
+}
+
+var (
+	profileInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gateway_orchestrator_profile_info",
+		Help: "Controller profile (--profile) in use; always 1.",
+	}, []string{"profile"})
+
+	subsystemEnabled = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gateway_orchestrator_subsystem_enabled",
+		Help: "Whether an optional subsystem runs (1) or not (0), as set by --profile and the subsystem's flags.",
+	}, []string{"subsystem"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(profileInfo, subsystemEnabled)
+}
+
+// ApplyProfile sets the flags of a profile on a parsed flag set. Flags given on the command line
+// keep their values, so a profile can be adjusted subsystem by subsystem.
+func ApplyProfile(fs *flag.FlagSet, profile string) error {
+	values, ok := profileFlags[profile]
+	if !ok && profile != ProfileCustom {
+		return fmt.Errorf("unknown profile %q, must be %s, %s or %s", profile, ProfileCustom, ProfileMinimal, ProfileFull)
+	}
+	given := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if given[name] {
+			continue
+		}
+		if err := fs.Set(name, values[name]); err != nil {
+			return fmt.Errorf("profile %s: --%s=%s: %w", profile, name, values[name], err)
+		}
+	}
+	return nil
+}
+
+// RecordProfile exports the profile and which optional subsystems run as metrics
+func RecordProfile(profile string, subsystems map[string]bool) {
+	profileInfo.Reset()
+	profileInfo.WithLabelValues(profile).Set(1)
+	subsystemEnabled.Reset()
+	for name, enabled := range subsystems {
+		value := 0.0
+		if enabled {
+			value = 1
+		}
+		subsystemEnabled.WithLabelValues(name).Set(value)
+	}
+}
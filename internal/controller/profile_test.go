@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func profileFlagSet() (*flag.FlagSet, *bool, *time.Duration, *bool) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	webhooks := fs.Bool("enable-webhooks", false, "")
+	probeInterval := fs.Duration("probe-interval", 0, "")
+	rebalance := fs.Bool("rebalance-over-capacity", false, "")
+	fs.Bool("repair-route-parent-refs", false, "")
+	fs.Bool("strict-route-access", false, "")
+	fs.Duration("duplicate-certificate-check-interval", 0, "")
+	fs.Duration("gateway-failover-interval", 0, "")
+	fs.Duration("load-balancer-alarms-interval", 0, "")
+	fs.Duration("fleet-health-interval", time.Minute, "")
+	fs.Bool("cost-estimates", false, "")
+	return fs, webhooks, probeInterval, rebalance
+}
+
+func TestApplyProfile(t *testing.T) {
+	fs, webhooks, probeInterval, rebalance := profileFlagSet()
+	require.NoError(t, fs.Parse([]string{"--probe-interval=5m"}))
+	require.NoError(t, ApplyProfile(fs, ProfileFull))
+	assert.True(t, *webhooks)
+	assert.True(t, *rebalance)
+	assert.Equal(t, 5*time.Minute, *probeInterval, "explicit flags override the profile")
+
+	fs, webhooks, probeInterval, _ = profileFlagSet()
+	require.NoError(t, fs.Parse([]string{"--enable-webhooks", "--probe-interval=5m"}))
+	require.NoError(t, ApplyProfile(fs, ProfileMinimal))
+	assert.True(t, *webhooks)
+	assert.Equal(t, 5*time.Minute, *probeInterval)
+	assert.Equal(t, "0s", fs.Lookup("fleet-health-interval").Value.String())
+
+	fs, _, probeInterval, _ = profileFlagSet()
+	require.NoError(t, fs.Parse([]string{"--probe-interval=5m"}))
+	require.NoError(t, ApplyProfile(fs, ProfileCustom))
+	assert.Equal(t, time.Minute.String(), fs.Lookup("fleet-health-interval").Value.String(), "custom keeps the defaults")
+	assert.Equal(t, 5*time.Minute, *probeInterval)
+
+	assert.Error(t, ApplyProfile(fs, "lean"))
+}
@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// AnnotationApproveQuarantine releases a quarantined request. The value must be the request's
+// hostname, so an approval doesn't carry over when the hostname changes.
+const AnnotationApproveQuarantine = "gateway.opendi.com/approve-quarantine"
+
+// confusableLetters are Cyrillic and Greek letters that render like Latin ones
+const confusableLetters = "аеорсухіјѕԁӏһԛԝүѵ" + "αικνορυχϲ"
+
+// Quarantine holds requests for suspicious hostnames (lookalikes of corporate brands, punycode
+// homoglyphs) until they are approved, instead of provisioning them automatically
+type Quarantine struct {
+	// Patterns are regular expressions matched against the lowercase hostname
+	Patterns []*regexp.Regexp
+
+	// Punycode quarantines hostnames with punycode labels that mix Latin with Cyrillic or
+	// Greek letters, or consist only of letters that look Latin
+	Punycode bool
+
+	// ExemptDomains are never quarantined, e.g. the brand's own domains matching its pattern
+	ExemptDomains []string
+}
+
+// ParseQuarantinePatterns parses a comma-separated list of regular expressions, e.g.
+// "paypa[l1],micr[o0]s[o0]ft". Patterns can't contain commas.
+func ParseQuarantinePatterns(s string) ([]*regexp.Regexp, error) {
+	var patterns []*regexp.Regexp
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		pattern, err := regexp.Compile(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quarantine pattern %q: %w", entry, err)
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// NewQuarantine builds the quarantine from the comma-separated patterns and exempt domains.
+// Returns nil if there are no patterns and the punycode check is off.
+func NewQuarantine(patterns, exemptDomains string, punycode bool) (*Quarantine, error) {
+	parsed, err := ParseQuarantinePatterns(patterns)
+	if err != nil {
+		return nil, err
+	}
+	if len(parsed) == 0 && !punycode {
+		return nil, nil
+	}
+	q := &Quarantine{Patterns: parsed, Punycode: punycode}
+	for _, domain := range strings.Split(exemptDomains, ",") {
+		if domain = strings.Trim(strings.ToLower(strings.TrimSpace(domain)), "."); domain != "" {
+			q.ExemptDomains = append(q.ExemptDomains, domain)
+		}
+	}
+	return q, nil
+}
+
+// Match returns why the hostname is quarantined, if it is
+func (q *Quarantine) Match(hostname string) (conditions.Reason, string, bool) {
+	hostname = strings.TrimSuffix(strings.ToLower(hostname), ".")
+	if _, exempt := InternalDomainSuffix(hostname, q.ExemptDomains); exempt {
+		return "", "", false
+	}
+	for _, pattern := range q.Patterns {
+		if pattern.MatchString(hostname) {
+			return conditions.ReasonSuspiciousPattern, fmt.Sprintf("hostname matches quarantine pattern %q", pattern.String()), true
+		}
+	}
+	if q.Punycode {
+		for _, label := range strings.Split(hostname, ".") {
+			if !strings.HasPrefix(label, "xn--") {
+				continue
+			}
+			decoded, err := idna.Punycode.ToUnicode(label)
+			if err != nil {
+				return conditions.ReasonHomoglyph, fmt.Sprintf("label %s is not valid punycode", label), true
+			}
+			if imitatesLatin(decoded) {
+				return conditions.ReasonHomoglyph, fmt.Sprintf("label %s (%s) imitates Latin letters", label, decoded), true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// imitatesLatin reports whether a label mixes Latin with Cyrillic or Greek letters, or is
+// made only of Cyrillic and Greek letters that look Latin
+func imitatesLatin(label string) bool {
+	latin, lookalike, other := false, false, false
+	for _, c := range label {
+		switch {
+		case !unicode.IsLetter(c):
+		case unicode.Is(unicode.Latin, c):
+			latin = true
+		case unicode.In(c, unicode.Cyrillic, unicode.Greek):
+			lookalike = true
+			if !strings.ContainsRune(confusableLetters, c) {
+				other = true
+			}
+		}
+	}
+	return lookalike && (latin || !other)
+}
+
+// checkQuarantine holds the request with a Quarantined condition if its hostname is suspicious
+// and not approved through AnnotationApproveQuarantine. Returns false while it is held.
+func (r *GatewayHostnameRequestReconciler) checkQuarantine(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (bool, error) {
+	reason, message, suspicious := r.Quarantine.Match(ghr.Spec.Hostname)
+	approved := ghr.Annotations[AnnotationApproveQuarantine] == ghr.Spec.Hostname
+
+	if !suspicious || approved {
+		if conditions.Get(ghr.Status.Conditions, ConditionTypeQuarantined) != nil {
+			conditions.Remove(&ghr.Status.Conditions, ConditionTypeQuarantined)
+			if approved {
+				r.Recorder.Event(ghr, corev1.EventTypeNormal, "QuarantineApproved", "Quarantined hostname approved, provisioning")
+			}
+		}
+		return true, nil
+	}
+
+	if !conditions.HasReason(ghr.Status.Conditions, ConditionTypeQuarantined, metav1.ConditionTrue, reason) {
+		log.FromContext(ctx).Info("Quarantining suspicious hostname", "reason", message)
+		r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "Quarantined", "Hostname held for approval: %s", message)
+	}
+	r.setCondition(ghr, ConditionTypeQuarantined, metav1.ConditionTrue, reason,
+		fmt.Sprintf("%s; annotate the request with %s=%s to approve", message, AnnotationApproveQuarantine, ghr.Spec.Hostname))
+	if err := r.Status().Update(ctx, ghr); err != nil {
+		return false, err
+	}
+	return false, nil
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+)
+
+func TestQuarantineMatch(t *testing.T) {
+	q, err := NewQuarantine("paypa[l1], micr[o0]s[o0]ft", "paypal.com", true)
+	require.NoError(t, err)
+
+	tests := []struct {
+		hostname string
+		reason   conditions.Reason
+	}{
+		{"paypal-login.example.com", conditions.ReasonSuspiciousPattern},
+		{"www.PAYPA1.example.com", conditions.ReasonSuspiciousPattern},
+		{"shop.paypal.com", ""},
+		{"paypal.com.example.net", conditions.ReasonSuspiciousPattern},
+		{"xn--pypal-4ve.example.com", conditions.ReasonHomoglyph},  // Cyrillic а in pаypal
+		{"xn--80ak6aa92e.example.com", conditions.ReasonHomoglyph}, // аррӏе, all lookalikes
+		{"xn--e1afmkfd.example.com", ""},                           // пример, plain Cyrillic
+		{"xn--mnchen-3ya.example.com", ""},                         // münchen
+		{"xn--zz-!.example.com", conditions.ReasonHomoglyph},
+		{"app.example.com", ""},
+	}
+	for _, tt := range tests {
+		reason, _, suspicious := q.Match(tt.hostname)
+		assert.Equal(t, tt.reason != "", suspicious, tt.hostname)
+		assert.Equal(t, tt.reason, reason, tt.hostname)
+	}
+}
+
+func TestNewQuarantine(t *testing.T) {
+	q, err := NewQuarantine("", "example.com", false)
+	require.NoError(t, err)
+	assert.Nil(t, q, "nothing to quarantine")
+
+	_, err = NewQuarantine("paypa[l1", "", false)
+	assert.ErrorContains(t, err, "invalid quarantine pattern")
+}
+
+func TestCheckQuarantine_HoldThenApprove(t *testing.T) {
+	ctx := context.Background()
+	ghr := assignedGHR("shop", "paypal-login.example.com")
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr).WithStatusSubresource(ghr).Build()
+	q, err := NewQuarantine("paypa[l1]", "", false)
+	require.NoError(t, err)
+	r := &GatewayHostnameRequestReconciler{Client: c, Recorder: record.NewFakeRecorder(10), Quarantine: q}
+
+	released, err := r.checkQuarantine(ctx, ghr)
+	require.NoError(t, err)
+	assert.False(t, released)
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(ghr), ghr))
+	assert.True(t, conditions.HasReason(ghr.Status.Conditions, ConditionTypeQuarantined, metav1.ConditionTrue, conditions.ReasonSuspiciousPattern))
+	assert.Contains(t, ghr.Status.Message, "Quarantined pending approval")
+
+	// An approval for another hostname doesn't count
+	ghr.Annotations = map[string]string{AnnotationApproveQuarantine: "other.example.com"}
+	released, err = r.checkQuarantine(ctx, ghr)
+	require.NoError(t, err)
+	assert.False(t, released)
+
+	ghr.Annotations = map[string]string{AnnotationApproveQuarantine: "paypal-login.example.com"}
+	released, err = r.checkQuarantine(ctx, ghr)
+	require.NoError(t, err)
+	assert.True(t, released)
+	assert.Nil(t, conditions.Get(ghr.Status.Conditions, ConditionTypeQuarantined), "the quarantine is lifted")
+}
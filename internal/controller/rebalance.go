@@ -0,0 +1,284 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+// AnnotationRebalance on a Gateway moves its hostnames beyond the certificate limit, as
+// --rebalance-over-capacity does for all Gateways. Removed once the Gateway is within the limit.
+const AnnotationRebalance = "gateway.opendi.com/rebalance"
+
+// AnnotationDrain on a Gateway moves all its hostnames to other Gateways, e.g. before the Gateway
+// is retired. The Gateway must be cordoned as well, so they aren't assigned back to it. On a
+// request, set to the <namespace>/<name> of its Gateway, it moves only that hostname, which is
+// how OperationDrainGateway paces a drain; it has no effect once the request is elsewhere.
+const AnnotationDrain = "gateway.opendi.com/drain"
+
+// DefaultRebalanceDrainPeriod is how long the old Gateway keeps serving a moved hostname's
+// certificate after its ALIAS records point at the new ALB, covering resolver caches.
+const DefaultRebalanceDrainPeriod = 2 * time.Minute
+
+// maxCertificates returns the per-Gateway certificate limit of the pool
+func (r *GatewayHostnameRequestReconciler) maxCertificates() int {
+	if r.GatewayPool != nil {
+		return r.GatewayPool.MaxCertificates()
+	}
+	return gateway.MaxCertificatesPerGateway
+}
+
+// rebalanceDrainPeriod returns the configured drain period, defaulting to DefaultRebalanceDrainPeriod
+func (r *GatewayHostnameRequestReconciler) rebalanceDrainPeriod() time.Duration {
+	if r.RebalanceDrainPeriod > 0 {
+		return r.RebalanceDrainPeriod
+	}
+	return DefaultRebalanceDrainPeriod
+}
+
+// syncGatewayCapacity records the certificate count on the Gateway and cordons it while it
+// holds more certificates than the pool limit (e.g., after the limit was lowered).
+// Only the orchestrator's own cordon is lifted; manual cordons are left alone.
+// Returns true if the Gateway was modified.
+func (r *GatewayHostnameRequestReconciler) syncGatewayCapacity(ctx context.Context, gw *gwapiv1.Gateway, certCount int) bool {
+	logger := log.FromContext(ctx)
+	changed := false
+
+	if count := strconv.Itoa(certCount); gw.Annotations[AnnotationCertificateCount] != count {
+		gw.Annotations[AnnotationCertificateCount] = count
+		changed = true
+	}
+
+	limit := r.maxCertificates()
+	switch cordon := gw.Annotations[gateway.AnnotationCordoned]; {
+	case certCount > limit && cordon == "":
+		gw.Annotations[gateway.AnnotationCordoned] = gateway.CordonReasonOverCapacity
+		changed = true
+		logger.Info("Cordoned over-capacity Gateway", "gateway", gw.Name, "certificates", certCount, "limit", limit)
+		r.Recorder.Eventf(gw, corev1.EventTypeWarning, "Cordoned",
+			"Gateway holds %d certificates, above the limit of %d; no new hostnames will be assigned", certCount, limit)
+	case certCount <= limit && cordon == gateway.CordonReasonOverCapacity:
+		delete(gw.Annotations, gateway.AnnotationCordoned)
+		changed = true
+		logger.Info("Uncordoned Gateway back within capacity", "gateway", gw.Name, "certificates", certCount, "limit", limit)
+		r.Recorder.Eventf(gw, corev1.EventTypeNormal, "Uncordoned",
+			"Gateway holds %d certificates, within the limit of %d", certCount, limit)
+	}
+	if certCount <= limit && gw.Annotations[AnnotationRebalance] != "" {
+		delete(gw.Annotations, AnnotationRebalance)
+		changed = true
+		logger.Info("Requested rebalance done", "gateway", gw.Name, "certificates", certCount, "limit", limit)
+	}
+
+	return changed
+}
+
+// assignedGatewayAnnotation returns an annotation of the request's Gateway, or "" if the
+// Gateway can't be read
+func (r *GatewayHostnameRequestReconciler) assignedGatewayAnnotation(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, key string) string {
+	var gw gwapiv1.Gateway
+	name := types.NamespacedName{Name: ghr.Status.AssignedGateway, Namespace: ghr.Status.AssignedGatewayNamespace}
+	if err := r.Get(ctx, name, &gw); err != nil {
+		return ""
+	}
+	return gw.Annotations[key]
+}
+
+// rebalanceRequested reports whether the request's Gateway carries AnnotationRebalance
+func (r *GatewayHostnameRequestReconciler) rebalanceRequested(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	return r.assignedGatewayAnnotation(ctx, ghr, AnnotationRebalance) != ""
+}
+
+// drainRequested reports whether the request's Gateway carries AnnotationDrain, or the request
+// carries it for the Gateway it is assigned to
+func (r *GatewayHostnameRequestReconciler) drainRequested(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	if ghr.Annotations[AnnotationDrain] == ghr.Status.AssignedGatewayNamespace+"/"+ghr.Status.AssignedGateway {
+		return true
+	}
+	return r.assignedGatewayAnnotation(ctx, ghr, AnnotationDrain) != ""
+}
+
+// isExcessOnGateway reports whether the request is one of the hostnames beyond the certificate
+// limit on its Gateway. The oldest requests stay put; the newest ones are moved first.
+func (r *GatewayHostnameRequestReconciler) isExcessOnGateway(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (bool, int, error) {
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList); err != nil {
+		return false, 0, fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+
+	var assigned []gatewayv1alpha1.GatewayHostnameRequest
+	for _, other := range ghrList.Items {
+		if !other.DeletionTimestamp.IsZero() || other.Status.CertificateArn == "" {
+			continue
+		}
+		if other.Status.AssignedGateway == ghr.Status.AssignedGateway &&
+			other.Status.AssignedGatewayNamespace == ghr.Status.AssignedGatewayNamespace {
+			assigned = append(assigned, other)
+		}
+	}
+
+	limit := r.maxCertificates()
+	if len(assigned) <= limit {
+		return false, len(assigned), nil
+	}
+
+	sort.Slice(assigned, func(i, j int) bool {
+		a, b := assigned[i], assigned[j]
+		if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+			return a.CreationTimestamp.Before(&b.CreationTimestamp)
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	for i, other := range assigned {
+		if other.Namespace == ghr.Namespace && other.Name == ghr.Name {
+			return i >= limit, len(assigned), nil
+		}
+	}
+	return false, len(assigned), nil
+}
+
+// reconcileCapacity moves the request off its Gateway if that Gateway is over the certificate
+// limit or being drained, and finishes a move once DNS has switched and the drain period passed.
+// Returns how long to wait before finishing an in-flight move, and whether a move was started
+// (the caller must persist status and requeue so the request is assigned to a new Gateway).
+func (r *GatewayHostnameRequestReconciler) reconcileCapacity(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (time.Duration, bool, error) {
+	logger := log.FromContext(ctx)
+
+	if ghr.Status.MigratingFromGateway != "" {
+		return r.finishRebalance(ctx, ghr)
+	}
+	if ghr.Status.AssignedGateway == "" {
+		return 0, false, nil
+	}
+
+	draining := r.drainRequested(ctx, ghr)
+	excess, count, err := r.isExcessOnGateway(ctx, ghr)
+	if err != nil {
+		return 0, false, err
+	}
+	if !excess && !draining {
+		meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeGatewayOverCapacity)
+		return 0, false, nil
+	}
+
+	msg := fmt.Sprintf("Gateway %s holds %d certificates, above the limit of %d",
+		ghr.Status.AssignedGateway, count, r.maxCertificates())
+	reason := conditions.ReasonRebalancing
+	if draining {
+		msg = fmt.Sprintf("Gateway %s is being drained", ghr.Status.AssignedGateway)
+		reason = conditions.ReasonGatewayDrained
+	} else if !r.RebalanceOverCapacity && !r.rebalanceRequested(ctx, ghr) {
+		r.setCondition(ghr, ConditionTypeGatewayOverCapacity, metav1.ConditionTrue, conditions.ReasonRebalancePending,
+			msg+"; enable --rebalance-over-capacity or annotate the Gateway with "+AnnotationRebalance+" to move this hostname")
+		return 0, false, nil
+	}
+
+	logger.Info("Moving hostname off over-capacity Gateway", "gateway", ghr.Status.AssignedGateway)
+	r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "Rebalancing", "%s; moving hostname to another Gateway", msg)
+
+	ghr.Status.MigratingFromGateway = ghr.Status.AssignedGateway
+	ghr.Status.MigratingFromGatewayNamespace = ghr.Status.AssignedGatewayNamespace
+	ghr.Status.AssignedGateway = ""
+	meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeListenerAttached)
+	meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeDnsAliasReady)
+	r.setCondition(ghr, ConditionTypeGatewayOverCapacity, metav1.ConditionTrue, reason, msg)
+	r.setCondition(ghr, ConditionTypeReady, metav1.ConditionFalse, conditions.ReasonRebalancing,
+		fmt.Sprintf("Moving to another Gateway; %s keeps serving the hostname meanwhile", ghr.Status.MigratingFromGateway))
+
+	return 0, true, nil
+}
+
+// finishRebalance releases the old Gateway once the ALIAS records have pointed at the new
+// Gateway for the drain period. Returns the remaining drain time if it has not passed yet.
+// It finishes moves started by reconcilePlacement and reconcileFailover as well; a failed
+// Gateway is deleted once its last hostname moved off.
+func (r *GatewayHostnameRequestReconciler) finishRebalance(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (time.Duration, bool, error) {
+	aliasReady := meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeDnsAliasReady)
+	if aliasReady == nil || aliasReady.Status != metav1.ConditionTrue {
+		return 0, false, nil
+	}
+	// A Gateway whose load balancer failed has nothing left to drain
+	failover := failoverInProgress(ghr)
+	if remaining := time.Until(aliasReady.LastTransitionTime.Add(r.rebalanceDrainPeriod())); remaining > 0 && !failover {
+		return remaining, false, nil
+	}
+
+	oldGateway, oldNamespace := ghr.Status.MigratingFromGateway, migratingFromNamespace(ghr)
+	if err := r.releaseGateway(ctx, ghr, oldGateway, oldNamespace); err != nil {
+		return 0, false, err
+	}
+	if failover {
+		if err := r.cleanupEmptyGateway(ctx, oldGateway, oldNamespace, ghr.Namespace, ghr.Name); err != nil {
+			return 0, false, err
+		}
+	}
+
+	event := "Rebalanced"
+	switch {
+	case failover:
+		event = "FailedOver"
+	case placementMoveInProgress(ghr):
+		event = "Migrated"
+	}
+	ghr.Status.MigratingFromGateway = ""
+	ghr.Status.MigratingFromGatewayNamespace = ""
+	meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeGatewayOverCapacity)
+	meta.RemoveStatusCondition(&ghr.Status.Conditions, ConditionTypeMigrating)
+	recordHistory(ghr, event, "", "MovedGateway",
+		fmt.Sprintf("Moved from %s to %s", oldGateway, ghr.Status.AssignedGateway))
+	r.Recorder.Eventf(ghr, corev1.EventTypeNormal, event, "Moved from Gateway %s to %s", oldGateway, ghr.Status.AssignedGateway)
+
+	return 0, false, nil
+}
+
+// releaseGateway removes the request's certificate (and hostname listener) from a Gateway it
+// is no longer assigned to. The request's own status may not have reached the cache yet, so
+// the remaining certificates are collected from the other requests on the Gateway.
+func (r *GatewayHostnameRequestReconciler) releaseGateway(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, gatewayName, gatewayNamespace string) error {
+	logger := log.FromContext(ctx)
+
+	var gw gwapiv1.Gateway
+	if err := r.Get(ctx, types.NamespacedName{Name: gatewayName, Namespace: gatewayNamespace}, &gw); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get gateway %s: %w", gatewayName, err)
+	}
+
+	remaining, err := r.gatewayCertificateARNsWithout(ctx, gatewayName, gatewayNamespace, ghr)
+	if err != nil {
+		return err
+	}
+
+	visibility := gw.Annotations[AnnotationVisibility]
+	if visibility == "" {
+		visibility = "internet-facing"
+	}
+	if err := r.ensureLoadBalancerConfiguration(ctx, gatewayName, gatewayNamespace, remaining, visibility, gw.Annotations["gateway.opendi.com/waf-arn"]); err != nil {
+		return fmt.Errorf("failed to release certificate from gateway %s: %w", gatewayName, err)
+	}
+
+	if err := r.removeFromGatewaySpec(ctx, &gw, ghr); err != nil {
+		return err
+	}
+
+	logger.Info("Released certificate from previous Gateway", "gateway", gatewayName)
+	return nil
+}
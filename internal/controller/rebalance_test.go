@@ -0,0 +1,211 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+// ghrOnGateway returns a request assigned to gatewayName, created i minutes after a fixed epoch
+func ghrOnGateway(i int, gatewayName string) *gatewayv1alpha1.GatewayHostnameRequest {
+	return &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              fmt.Sprintf("ghr-%d", i),
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(time.Date(2025, 1, 1, 0, i, 0, 0, time.UTC)),
+		},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+			Hostname: fmt.Sprintf("app%d.opendi.com", i),
+			ZoneId:   "Z123456",
+		},
+		Status: gatewayv1alpha1.GatewayHostnameRequestStatus{
+			AssignedGateway:          gatewayName,
+			AssignedGatewayNamespace: "edge",
+			CertificateArn:           fmt.Sprintf("arn:aws:acm:eu-west-1:123456789012:certificate/cert-%d", i),
+		},
+	}
+}
+
+func newRebalanceReconciler(t *testing.T, maxCerts int, ghrs ...*gatewayv1alpha1.GatewayHostnameRequest) *GatewayHostnameRequestReconciler {
+	builder := fake.NewClientBuilder().WithScheme(getTestScheme())
+	for _, ghr := range ghrs {
+		builder = builder.WithObjects(ghr)
+	}
+	c := builder.Build()
+
+	pool := gateway.NewPool(c, "edge", "aws-alb", 0, 0)
+	pool.SetMaxCertificates(maxCerts)
+
+	return &GatewayHostnameRequestReconciler{
+		Client:      c,
+		Scheme:      getTestScheme(),
+		Recorder:    record.NewFakeRecorder(10),
+		GatewayPool: pool,
+	}
+}
+
+func TestSyncGatewayCapacity_CordonsAndUncordons(t *testing.T) {
+	r := newRebalanceReconciler(t, 2)
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw-01", Namespace: "edge", Annotations: map[string]string{}},
+	}
+
+	assert.True(t, r.syncGatewayCapacity(context.Background(), gw, 3))
+	assert.Equal(t, "3", gw.Annotations[AnnotationCertificateCount])
+	assert.Equal(t, gateway.CordonReasonOverCapacity, gw.Annotations[gateway.AnnotationCordoned])
+
+	assert.True(t, r.syncGatewayCapacity(context.Background(), gw, 2))
+	assert.Equal(t, "2", gw.Annotations[AnnotationCertificateCount])
+	assert.NotContains(t, gw.Annotations, gateway.AnnotationCordoned)
+
+	// Manual cordons are left alone
+	gw.Annotations[gateway.AnnotationCordoned] = "maintenance"
+	assert.False(t, r.syncGatewayCapacity(context.Background(), gw, 2))
+	assert.Equal(t, "maintenance", gw.Annotations[gateway.AnnotationCordoned])
+}
+
+func TestReconcileCapacity_MovesNewestRequestsWhenEnabled(t *testing.T) {
+	oldest, middle, newest := ghrOnGateway(1, "gw-01"), ghrOnGateway(2, "gw-01"), ghrOnGateway(3, "gw-01")
+	r := newRebalanceReconciler(t, 2, oldest, middle, newest)
+
+	// Disabled: only surface the problem
+	_, moving, err := r.reconcileCapacity(context.Background(), newest)
+	require.NoError(t, err)
+	assert.False(t, moving)
+	assert.True(t, meta.IsStatusConditionTrue(newest.Status.Conditions, ConditionTypeGatewayOverCapacity))
+	assert.Equal(t, "gw-01", newest.Status.AssignedGateway)
+
+	r.RebalanceOverCapacity = true
+
+	_, moving, err = r.reconcileCapacity(context.Background(), oldest)
+	require.NoError(t, err)
+	assert.False(t, moving, "oldest requests stay on the Gateway")
+
+	_, moving, err = r.reconcileCapacity(context.Background(), newest)
+	require.NoError(t, err)
+	assert.True(t, moving)
+	assert.Equal(t, "", newest.Status.AssignedGateway)
+	assert.Equal(t, "gw-01", newest.Status.MigratingFromGateway)
+	assert.Nil(t, meta.FindStatusCondition(newest.Status.Conditions, ConditionTypeListenerAttached))
+	assert.False(t, meta.IsStatusConditionTrue(newest.Status.Conditions, ConditionTypeReady))
+}
+
+func TestReconcileCapacity_MovesWhenGatewayAnnotated(t *testing.T) {
+	oldest, middle, newest := ghrOnGateway(1, "gw-01"), ghrOnGateway(2, "gw-01"), ghrOnGateway(3, "gw-01")
+	r := newRebalanceReconciler(t, 2, oldest, middle, newest)
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw-01", Namespace: "edge", Annotations: map[string]string{AnnotationRebalance: "2025-01-01T00:00:00Z"}},
+	}
+	require.NoError(t, r.Create(context.Background(), gw))
+
+	_, moving, err := r.reconcileCapacity(context.Background(), newest)
+	require.NoError(t, err)
+	assert.True(t, moving, "the annotation moves hostnames without --rebalance-over-capacity")
+	assert.Equal(t, "gw-01", newest.Status.MigratingFromGateway)
+
+	assert.True(t, r.syncGatewayCapacity(context.Background(), gw, 2))
+	assert.NotContains(t, gw.Annotations, AnnotationRebalance, "removed once within the limit")
+}
+
+func TestReconcileCapacity_MovesOffDrainedGateway(t *testing.T) {
+	ghr := ghrOnGateway(1, "gw-01")
+	r := newRebalanceReconciler(t, 2, ghr)
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw-01", Namespace: "edge", Annotations: map[string]string{AnnotationDrain: "2025-01-01T00:00:00Z"}},
+	}
+	require.NoError(t, r.Create(context.Background(), gw))
+
+	_, moving, err := r.reconcileCapacity(context.Background(), ghr)
+	require.NoError(t, err)
+	assert.True(t, moving, "hostnames within the limit move off a drained Gateway")
+	assert.Equal(t, "gw-01", ghr.Status.MigratingFromGateway)
+	assert.Equal(t, string(conditions.ReasonGatewayDrained), meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeGatewayOverCapacity).Reason)
+}
+
+func TestReconcileCapacity_MovesRequestDrainedOffItsGateway(t *testing.T) {
+	drained := ghrOnGateway(1, "gw-01")
+	drained.Annotations = map[string]string{AnnotationDrain: "edge/gw-01"}
+	stale := ghrOnGateway(2, "gw-02")
+	stale.Annotations = map[string]string{AnnotationDrain: "edge/gw-01"}
+	r := newRebalanceReconciler(t, 2, drained, stale)
+
+	_, moving, err := r.reconcileCapacity(context.Background(), drained)
+	require.NoError(t, err)
+	assert.True(t, moving, "the request annotation moves it off the Gateway it names")
+	assert.Equal(t, string(conditions.ReasonGatewayDrained), meta.FindStatusCondition(drained.Status.Conditions, ConditionTypeGatewayOverCapacity).Reason)
+
+	_, moving, err = r.reconcileCapacity(context.Background(), stale)
+	require.NoError(t, err)
+	assert.False(t, moving, "the annotation has no effect once the request is on another Gateway")
+}
+
+func TestGetGatewayCertificateARNs_KeepsMigratingCertificate(t *testing.T) {
+	staying := ghrOnGateway(1, "gw-01")
+	moving := ghrOnGateway(2, "gw-02")
+	moving.Status.MigratingFromGateway = "gw-01"
+	r := newRebalanceReconciler(t, 2, staying, moving)
+
+	arns, err := r.getGatewayCertificateARNs(context.Background(), "gw-01", "edge")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{staying.Status.CertificateArn, moving.Status.CertificateArn}, arns)
+
+	arns, err = r.getGatewayCertificateARNs(context.Background(), "gw-02", "edge")
+	require.NoError(t, err)
+	assert.Equal(t, []string{moving.Status.CertificateArn}, arns)
+}
+
+func TestGatewayCertificateARNsWithout_KeepsSharedCertificate(t *testing.T) {
+	host := ghrOnGateway(1, "gw-01")
+	// An expand-certificates member served by the host's certificate
+	member := ghrOnGateway(2, "gw-01")
+	member.Status.CertificateArn = host.Status.CertificateArn
+	other := ghrOnGateway(3, "gw-01")
+	r := newRebalanceReconciler(t, 3, host, member, other)
+
+	arns, err := r.gatewayCertificateARNsWithout(context.Background(), "gw-01", "edge", member)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{host.Status.CertificateArn, other.Status.CertificateArn}, arns,
+		"the member leaving doesn't take the host's certificate along")
+
+	arns, err = r.gatewayCertificateARNsWithout(context.Background(), "gw-01", "edge", other)
+	require.NoError(t, err)
+	assert.Equal(t, []string{host.Status.CertificateArn}, arns)
+}
+
+func TestFinishRebalance_WaitsForDrainPeriod(t *testing.T) {
+	ghr := ghrOnGateway(1, "gw-02")
+	ghr.Status.MigratingFromGateway = "gw-01"
+	ghr.Status.Conditions = []metav1.Condition{{
+		Type:               ConditionTypeDnsAliasReady,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(time.Now().Add(-30 * time.Second)),
+	}}
+	r := newRebalanceReconciler(t, 2, ghr)
+	r.RebalanceDrainPeriod = time.Minute
+
+	remaining, _, err := r.finishRebalance(context.Background(), ghr)
+	require.NoError(t, err)
+	assert.Greater(t, remaining, time.Duration(0))
+	assert.Equal(t, "gw-01", ghr.Status.MigratingFromGateway)
+
+	ghr.Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+
+	remaining, _, err = r.finishRebalance(context.Background(), ghr)
+	require.NoError(t, err)
+	assert.Zero(t, remaining)
+	assert.Empty(t, ghr.Status.MigratingFromGateway)
+	assert.Equal(t, "Rebalanced", ghr.Status.History[len(ghr.Status.History)-1].Type)
+}
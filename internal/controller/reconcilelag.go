@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+var (
+	oldestPendingSeconds = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gateway_orchestrator_oldest_pending_seconds",
+		Help: "Age of the oldest GatewayHostnameRequest that isn't Ready, since it was created or stopped being Ready; 0 if none.",
+	})
+
+	pendingHostnames = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gateway_orchestrator_pending_hostnames",
+		Help: "GatewayHostnameRequests that aren't Ready, by what they wait for, e.g. CertificateIssued/PendingIssuance.",
+	}, []string{"reason"})
+
+	lastSuccessfulReconcile = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gateway_orchestrator_last_successful_reconcile_timestamp_seconds",
+		Help: "Unix time of the last successful reconcile of each GatewayHostnameRequest; subtract from time() for the lag.",
+	}, []string{"namespace", "name"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(oldestPendingSeconds, pendingHostnames, lastSuccessfulReconcile)
+}
+
+// recordSuccessfulReconcile timestamps the request's last successful reconcile
+func recordSuccessfulReconcile(ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+	lastSuccessfulReconcile.WithLabelValues(ghr.Namespace, ghr.Name).SetToCurrentTime()
+}
+
+// forgetReconcileLag removes the request's reconcile timestamp once it is deleted
+func forgetReconcileLag(ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+	lastSuccessfulReconcile.DeleteLabelValues(ghr.Namespace, ghr.Name)
+}
+
+// pendingSince returns since when a request that isn't Ready, or is being deleted, has been
+// waiting: its deletion, when Ready last changed, or its creation if Ready was never set
+func pendingSince(ghr *gatewayv1alpha1.GatewayHostnameRequest) time.Time {
+	if ghr.DeletionTimestamp != nil {
+		return ghr.DeletionTimestamp.Time
+	}
+	if cond := conditions.Get(ghr.Status.Conditions, ConditionTypeReady); cond != nil && !cond.LastTransitionTime.IsZero() {
+		return cond.LastTransitionTime.Time
+	}
+	return ghr.CreationTimestamp.Time
+}
+
+// pendingReason returns what a request that isn't Ready waits for, in the order explainStatus
+// looks at it: the condition type, followed by its reason once the step has started. The
+// values are bounded by the condition types and reasons, so they are safe as a metric label.
+func pendingReason(ghr *gatewayv1alpha1.GatewayHostnameRequest) string {
+	conds := ghr.Status.Conditions
+	withReason := func(cond *metav1.Condition) string {
+		if cond.Reason == "" {
+			return cond.Type
+		}
+		return cond.Type + "/" + cond.Reason
+	}
+
+	if ghr.DeletionTimestamp != nil || conditions.Get(conds, ConditionTypeDeleting) != nil {
+		return ConditionTypeDeleting
+	}
+	if cond := conditions.Get(conds, ConditionTypeClaimed); cond != nil && cond.Status == metav1.ConditionFalse {
+		return withReason(cond)
+	}
+	if conditions.HasReason(conds, ConditionTypeReady, metav1.ConditionFalse, conditions.ReasonValidationFailed) ||
+		conditions.HasReason(conds, ConditionTypeReady, metav1.ConditionFalse, conditions.ReasonPolicyDenied) {
+		return withReason(conditions.Get(conds, ConditionTypeReady))
+	}
+	for _, t := range []string{ConditionTypeQuarantined, ConditionTypeMigrating} {
+		if conditions.IsTrue(conds, t) {
+			return withReason(conditions.Get(conds, t))
+		}
+	}
+	step := pendingStep(ghr)
+	if step == "" {
+		return ConditionTypeReady
+	}
+	if cond := conditions.Get(conds, step); cond != nil {
+		return withReason(cond)
+	}
+	return step
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+)
+
+func TestPendingReason(t *testing.T) {
+	cond := func(condType string, status metav1.ConditionStatus, reason string) metav1.Condition {
+		return metav1.Condition{Type: condType, Status: status, Reason: reason}
+	}
+	tests := []struct {
+		name  string
+		conds []metav1.Condition
+		want  string
+	}{
+		{name: "nothing started", want: ConditionTypeClaimed},
+		{
+			name:  "claim conflict",
+			conds: []metav1.Condition{cond(ConditionTypeClaimed, metav1.ConditionFalse, string(conditions.ReasonAlreadyClaimed))},
+			want:  "Claimed/AlreadyClaimed",
+		},
+		{
+			name: "waiting for issuance",
+			conds: []metav1.Condition{
+				cond(ConditionTypeClaimed, metav1.ConditionTrue, "Claimed"),
+				cond(ConditionTypeCertificateRequested, metav1.ConditionTrue, "Requested"),
+				cond(ConditionTypeDnsValidated, metav1.ConditionTrue, "Validated"),
+				cond(ConditionTypeCertificateIssued, metav1.ConditionFalse, string(conditions.ReasonPendingIssuance)),
+			},
+			want: "CertificateIssued/PendingIssuance",
+		},
+		{
+			name: "quarantine overrides the steps",
+			conds: []metav1.Condition{
+				cond(ConditionTypeClaimed, metav1.ConditionTrue, "Claimed"),
+				cond(ConditionTypeQuarantined, metav1.ConditionTrue, "SuspiciousHostname"),
+			},
+			want: "Quarantined/SuspiciousHostname",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ghr := assignedGHR("app", "app.example.com")
+			ghr.Status.Conditions = tt.conds
+			assert.Equal(t, tt.want, pendingReason(ghr))
+		})
+	}
+
+	deleting := assignedGHR("app", "app.example.com")
+	now := metav1.Now()
+	deleting.DeletionTimestamp = &now
+	assert.Equal(t, ConditionTypeDeleting, pendingReason(deleting))
+}
+
+func TestPendingSince(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ghr := assignedGHR("app", "app.example.com")
+	ghr.CreationTimestamp = metav1.NewTime(created)
+	assert.Equal(t, created, pendingSince(ghr), "never Ready: waiting since creation")
+
+	unready := created.Add(time.Hour)
+	ghr.Status.Conditions = []metav1.Condition{{Type: ConditionTypeReady, Status: metav1.ConditionFalse, LastTransitionTime: metav1.NewTime(unready)}}
+	assert.Equal(t, unready, pendingSince(ghr), "waiting since it stopped being Ready")
+}
+
+func TestRecordSuccessfulReconcile(t *testing.T) {
+	ghr := assignedGHR("lag", "lag.example.com")
+	recordSuccessfulReconcile(ghr)
+	assert.InDelta(t, float64(time.Now().Unix()), testutil.ToFloat64(lastSuccessfulReconcile.WithLabelValues(ghr.Namespace, ghr.Name)), 5)
+
+	forgetReconcileLag(ghr)
+	assert.False(t, lastSuccessfulReconcile.DeleteLabelValues(ghr.Namespace, ghr.Name), "already removed")
+}
@@ -0,0 +1,26 @@
+package controller
+
+import (
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// AnnotationReconcileNow forces a full reconcile of a request when set to a new value, e.g. the
+// current timestamp. Besides the drift validation every reconcile does, the DNS validation and
+// ALIAS records are written again and the zone's DNSSEC status is re-read, even if the
+// conditions say they are in place. Meant for support workflows after manual fixes in AWS.
+const AnnotationReconcileNow = "gateway.opendi.com/reconcile-now"
+
+// reconcileNowPending reports whether the reconcile-now annotation holds a value whose full
+// reconcile has not completed yet
+func reconcileNowPending(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	value := ghr.Annotations[AnnotationReconcileNow]
+	return value != "" && value != ghr.Status.LastHandledReconcileNow
+}
+
+// markReconcileNowHandled records that the full reconcile requested through the annotation
+// completed; call it once the request is Ready
+func markReconcileNowHandled(ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+	if reconcileNowPending(ghr) {
+		ghr.Status.LastHandledReconcileNow = ghr.Annotations[AnnotationReconcileNow]
+	}
+}
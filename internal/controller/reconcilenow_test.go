@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+func TestReconcileNow_RewritesRecords(t *testing.T) {
+	ctx := context.Background()
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "cdn", Namespace: "default"},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+			Hostname: "cdn.opendi.com",
+			ZoneId:   "Z123456",
+			AliasTarget: &gatewayv1alpha1.AliasTarget{
+				DNSName:      "d111111abcdef8.cloudfront.net",
+				HostedZoneId: "Z2FDTNDATAQYW2",
+			},
+		},
+		Status: gatewayv1alpha1.GatewayHostnameRequestStatus{
+			CertificateArn: "arn:aws:acm:us-east-1:123456789012:certificate/cdn",
+		},
+	}
+
+	route53Mock := &MockRoute53Client{records: make(map[string][]aws.DNSRecord)}
+	r := &GatewayHostnameRequestReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr).WithStatusSubresource(ghr).Build(),
+		Scheme:        getTestScheme(),
+		Recorder:      record.NewFakeRecorder(10),
+		Route53Client: route53Mock,
+	}
+
+	_, err := r.reconcileDNSOnly(ctx, ghr)
+	require.NoError(t, err)
+	require.Len(t, route53Mock.records["Z123456"], 1)
+
+	// Records deleted by hand are not noticed by a regular reconcile
+	route53Mock.records["Z123456"] = nil
+	_, err = r.reconcileDNSOnly(ctx, ghr)
+	require.NoError(t, err)
+	assert.Empty(t, route53Mock.records["Z123456"])
+
+	// A new reconcile-now value writes them again, once
+	ghr.Annotations = map[string]string{AnnotationReconcileNow: "2026-10-16T10:00:00Z"}
+	require.NoError(t, r.Update(ctx, ghr))
+	assert.True(t, reconcileNowPending(ghr))
+	_, err = r.reconcileDNSOnly(ctx, ghr)
+	require.NoError(t, err)
+	assert.Len(t, route53Mock.records["Z123456"], 1)
+	assert.Equal(t, "2026-10-16T10:00:00Z", ghr.Status.LastHandledReconcileNow)
+	assert.False(t, reconcileNowPending(ghr))
+
+	route53Mock.records["Z123456"] = nil
+	_, err = r.reconcileDNSOnly(ctx, ghr)
+	require.NoError(t, err)
+	assert.Empty(t, route53Mock.records["Z123456"])
+}
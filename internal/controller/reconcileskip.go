@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// DefaultFullReconcileInterval is how long a Ready request whose desired state is unchanged
+// goes without a full reconcile
+const DefaultFullReconcileInterval = 10 * time.Minute
+
+// desiredState is everything a full reconcile derives the request's AWS and Kubernetes
+// resources from. While it is unchanged, a full reconcile would only confirm what the last
+// one did.
+type desiredState struct {
+	Spec        gatewayv1alpha1.GatewayHostnameRequestSpec `json:"spec"`
+	Annotations map[string]string                          `json:"annotations,omitempty"`
+	Labels      map[string]string                          `json:"labels,omitempty"`
+	Settings    *Settings                                  `json:"settings,omitempty"`
+
+	// What the records and the Gateway configuration are made of
+	Gateway            string   `json:"gateway,omitempty"`
+	LoadBalancer       string   `json:"loadBalancer,omitempty"`
+	CertificateArn     string   `json:"certificateArn,omitempty"`
+	CertificateHost    string   `json:"certificateHost,omitempty"`
+	AliasZoneIds       []string `json:"aliasZoneIds,omitempty"`
+	AliasRecordTypes   []string `json:"aliasRecordTypes,omitempty"`
+	AliasWeight        *int64   `json:"aliasWeight,omitempty"`
+	MigratingFrom      string   `json:"migratingFrom,omitempty"`
+	MigratingFromZone  string   `json:"migratingFromZone,omitempty"`
+	CertificateJoiners []string `json:"certificateJoiners,omitempty"`
+
+	// The Kubernetes objects the request configures, as last seen
+	GatewayGeneration         int64                          `json:"gatewayGeneration,omitempty"`
+	GatewayAnnotations        map[string]string              `json:"gatewayAnnotations,omitempty"`
+	GatewayAddresses          []gwapiv1.GatewayStatusAddress `json:"gatewayAddresses,omitempty"`
+	LoadBalancerConfiguration map[string]interface{}         `json:"loadBalancerConfiguration,omitempty"`
+	NamespaceLabels           map[string]string              `json:"namespaceLabels,omitempty"`
+	Routes                    []string                       `json:"routes,omitempty"`
+}
+
+// desiredStateHash hashes the request's desired state, read from the informer cache so it
+// costs no AWS calls
+func (r *GatewayHostnameRequestReconciler) desiredStateHash(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (string, error) {
+	state := desiredState{
+		Spec:              ghr.Spec,
+		Annotations:       ghr.Annotations,
+		Labels:            ghr.Labels,
+		Settings:          r.settings(),
+		LoadBalancer:      ghr.Status.AssignedLoadBalancer,
+		CertificateArn:    ghr.Status.CertificateArn,
+		CertificateHost:   ghr.Status.CertificateHost,
+		AliasZoneIds:      ghr.Status.AliasZoneIds,
+		AliasRecordTypes:  ghr.Status.AliasRecordTypes,
+		AliasWeight:       ghr.Status.AliasWeight,
+		MigratingFromZone: ghr.Status.MigratingFromZoneId,
+	}
+	if ghr.Status.MigratingFromGateway != "" {
+		state.MigratingFrom = migratingFromNamespace(ghr) + "/" + ghr.Status.MigratingFromGateway
+	}
+
+	if ghr.Status.AssignedGateway != "" {
+		state.Gateway = ghr.Status.AssignedGatewayNamespace + "/" + ghr.Status.AssignedGateway
+		var gw gwapiv1.Gateway
+		key := types.NamespacedName{Namespace: ghr.Status.AssignedGatewayNamespace, Name: ghr.Status.AssignedGateway}
+		if err := r.Get(ctx, key, &gw); err != nil && !apierrors.IsNotFound(err) {
+			return "", fmt.Errorf("failed to get Gateway: %w", err)
+		}
+		state.GatewayGeneration = gw.Generation
+		state.GatewayAnnotations = gw.Annotations
+		state.GatewayAddresses = gw.Status.Addresses
+
+		lbcName, err := r.loadBalancerConfigurationName(ghr.Status.AssignedGateway)
+		if err != nil {
+			return "", err
+		}
+		lbc, err := r.getLoadBalancerConfiguration(ctx, types.NamespacedName{Namespace: ghr.Status.AssignedGatewayNamespace, Name: lbcName})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return "", fmt.Errorf("failed to get LoadBalancerConfiguration: %w", err)
+		}
+		if spec, ok := lbc.Object["spec"].(map[string]interface{}); ok {
+			state.LoadBalancerConfiguration = spec
+		}
+	}
+
+	var ns corev1.Namespace
+	if err := r.Get(ctx, types.NamespacedName{Name: ghr.Namespace}, &ns); err != nil && !apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("failed to get namespace: %w", err)
+	}
+	state.NamespaceLabels = ns.Labels
+
+	var routes gwapiv1.HTTPRouteList
+	if err := r.List(ctx, &routes, client.InNamespace(ghr.Namespace)); err != nil {
+		return "", fmt.Errorf("failed to list HTTPRoutes: %w", err)
+	}
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		if routeServesHostname(route, ghr.Spec.Hostname) {
+			state.Routes = append(state.Routes, route.Name+"@"+route.ResourceVersion)
+		}
+	}
+	sort.Strings(state.Routes)
+
+	// Requests that joined the certificate expand it
+	if r.ExpandCertificates {
+		var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+		if err := r.List(ctx, &ghrList, client.InNamespace(ghr.Namespace)); err != nil {
+			return "", fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+		}
+		for _, other := range ghrList.Items {
+			if other.Status.CertificateHost == ghr.Name {
+				state.CertificateJoiners = append(state.CertificateJoiners, other.Name+"="+other.Spec.Hostname)
+			}
+		}
+		sort.Strings(state.CertificateJoiners)
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:8]), nil
+}
+
+// skipReconcile reports whether the request can skip the reconcile because nothing changed
+// since the last full one and it isn't due yet, and how long until it is due. Requests that
+// aren't Ready, have failing reconciles or a pending reconcile-now are never skipped.
+func (r *GatewayHostnameRequestReconciler) skipReconcile(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (time.Duration, bool) {
+	if r.FullReconcileInterval <= 0 || ghr.Status.ObservedStateHash == "" || ghr.Status.NextFullReconcileTime == nil {
+		return 0, false
+	}
+	remaining := time.Until(ghr.Status.NextFullReconcileTime.Time)
+	if remaining <= 0 || ghr.Status.ObservedGeneration != ghr.Generation || ghr.Status.ConsecutiveFailures > 0 ||
+		!conditions.IsTrue(ghr.Status.Conditions, ConditionTypeReady) || reconcileNowPending(ghr) {
+		return 0, false
+	}
+	hash, err := r.desiredStateHash(ctx, ghr)
+	if err != nil || hash != ghr.Status.ObservedStateHash {
+		return 0, false
+	}
+	return remaining, true
+}
+
+// recordFullReconcile records the desired state a successful full reconcile applied, and
+// returns when to requeue: requeueAfter, or sooner if the next full reconcile is due first.
+// The next one is due after FullReconcileInterval, or requeueAfter if that is shorter, so
+// timers such as ACME renewals aren't skipped.
+func (r *GatewayHostnameRequestReconciler) recordFullReconcile(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, requeueAfter time.Duration) time.Duration {
+	ghr.Status.ObservedStateHash = ""
+	ghr.Status.NextFullReconcileTime = nil
+	if r.FullReconcileInterval <= 0 {
+		return requeueAfter
+	}
+	hash, err := r.desiredStateHash(ctx, ghr)
+	if err != nil {
+		// Not skipping is always safe
+		return requeueAfter
+	}
+	due := r.FullReconcileInterval
+	if requeueAfter > 0 && requeueAfter < due {
+		due = requeueAfter
+	}
+	next := metav1.NewTime(time.Now().Add(due))
+	ghr.Status.ObservedStateHash = hash
+	ghr.Status.NextFullReconcileTime = &next
+	return due
+}
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// skipTestReconciler returns a reconciler without AWS clients for a Ready request, so any
+// reconcile that isn't skipped fails
+func skipTestReconciler(t *testing.T) (*GatewayHostnameRequestReconciler, *gatewayv1alpha1.GatewayHostnameRequest) {
+	t.Helper()
+	scheme := getTestScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team-a", Finalizers: []string{FinalizerName}},
+		Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{ZoneId: "Z123", Hostname: "app.example.com"},
+		Status: gatewayv1alpha1.GatewayHostnameRequestStatus{
+			AssignedGateway:          "gw-01",
+			AssignedGatewayNamespace: "edge",
+			AssignedLoadBalancer:     "gw-01.elb.amazonaws.com",
+			CertificateArn:           "arn:aws:acm:us-east-1:123456789012:certificate/app",
+		},
+	}
+	syncRequestLabels(ghr)
+	conditions.Set(&ghr.Status.Conditions, ConditionTypeReady, metav1.ConditionTrue, conditions.ReasonReady, "Hostname request fully provisioned", 0)
+	gw := &gwapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gw-01", Namespace: "edge"}}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}
+	objs := []client.Object{ghr, gw, ns}
+	r := &GatewayHostnameRequestReconciler{
+		Client:                fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithStatusSubresource(ghr).Build(),
+		Scheme:                scheme,
+		Recorder:              record.NewFakeRecorder(10),
+		FullReconcileInterval: time.Hour,
+	}
+	return r, ghr
+}
+
+func TestSkipReconcile_UnchangedDesiredState(t *testing.T) {
+	ctx := context.Background()
+	r, ghr := skipTestReconciler(t)
+
+	_, skip := r.skipReconcile(ctx, ghr)
+	assert.False(t, skip, "never fully reconciled")
+	assert.Equal(t, time.Hour, r.recordFullReconcile(ctx, ghr, 0))
+	require.NotEmpty(t, ghr.Status.ObservedStateHash)
+	remaining, skip := r.skipReconcile(ctx, ghr)
+	assert.True(t, skip)
+	assert.InDelta(t, time.Hour.Seconds(), remaining.Seconds(), 5)
+	require.NoError(t, r.Status().Update(ctx, ghr))
+
+	// A skipped reconcile makes no AWS calls; the reconciler has no AWS clients
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "app"}})
+	require.NoError(t, err)
+	assert.InDelta(t, time.Hour.Seconds(), result.RequeueAfter.Seconds(), 5)
+}
+
+func TestSkipReconcile_Changes(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name   string
+		change func(t *testing.T, r *GatewayHostnameRequestReconciler, ghr *gatewayv1alpha1.GatewayHostnameRequest)
+	}{
+		{"annotation", func(t *testing.T, r *GatewayHostnameRequestReconciler, ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+			ghr.Annotations = map[string]string{"gateway.opendi.com/confirm-gateway-change": "gw-01"}
+		}},
+		{"reconcile-now", func(t *testing.T, r *GatewayHostnameRequestReconciler, ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+			ghr.Annotations = map[string]string{AnnotationReconcileNow: "1"}
+		}},
+		{"spec", func(t *testing.T, r *GatewayHostnameRequestReconciler, ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+			ghr.Generation++
+		}},
+		{"failing", func(t *testing.T, r *GatewayHostnameRequestReconciler, ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+			ghr.Status.ConsecutiveFailures = 1
+		}},
+		{"due", func(t *testing.T, r *GatewayHostnameRequestReconciler, ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+			past := metav1.NewTime(time.Now().Add(-time.Second))
+			ghr.Status.NextFullReconcileTime = &past
+		}},
+		{"gateway", func(t *testing.T, r *GatewayHostnameRequestReconciler, ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+			var gw gwapiv1.Gateway
+			require.NoError(t, r.Get(ctx, types.NamespacedName{Namespace: "edge", Name: "gw-01"}, &gw))
+			gw.Annotations = map[string]string{"alb.ingress.kubernetes.io/certificate-arn": ""}
+			require.NoError(t, r.Update(ctx, &gw))
+		}},
+		{"route", func(t *testing.T, r *GatewayHostnameRequestReconciler, ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+			require.NoError(t, r.Create(ctx, &gwapiv1.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team-a"},
+				Spec:       gwapiv1.HTTPRouteSpec{Hostnames: []gwapiv1.Hostname{"app.example.com"}},
+			}))
+		}},
+		{"namespace labels", func(t *testing.T, r *GatewayHostnameRequestReconciler, ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+			var ns corev1.Namespace
+			require.NoError(t, r.Get(ctx, types.NamespacedName{Name: "team-a"}, &ns))
+			ns.Labels = map[string]string{"team": "a"}
+			require.NoError(t, r.Update(ctx, &ns))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, ghr := skipTestReconciler(t)
+			r.recordFullReconcile(ctx, ghr, 0)
+			tt.change(t, r, ghr)
+			_, skip := r.skipReconcile(ctx, ghr)
+			assert.False(t, skip)
+		})
+	}
+}
+
+func TestRecordFullReconcile(t *testing.T) {
+	ctx := context.Background()
+	r, ghr := skipTestReconciler(t)
+
+	assert.Equal(t, 5*time.Minute, r.recordFullReconcile(ctx, ghr, 5*time.Minute), "a timer due before the interval")
+	assert.WithinDuration(t, time.Now().Add(5*time.Minute), ghr.Status.NextFullReconcileTime.Time, 5*time.Second)
+
+	r.FullReconcileInterval = 0
+	assert.Equal(t, 5*time.Minute, r.recordFullReconcile(ctx, ghr, 5*time.Minute))
+	assert.Empty(t, ghr.Status.ObservedStateHash)
+	assert.Nil(t, ghr.Status.NextFullReconcileTime)
+	_, skip := r.skipReconcile(ctx, ghr)
+	assert.False(t, skip, "disabled")
+}
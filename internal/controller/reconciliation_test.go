@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
@@ -21,15 +22,20 @@ import (
 
 // MockACMClient for testing
 type MockACMClient struct {
-	certificates map[string]string // ARN -> status
+	certificates map[string]string            // ARN -> status
+	tags         map[string]map[string]string // ARN -> tags
 }
 
-func (m *MockACMClient) RequestCertificate(ctx context.Context, hostname string, tags map[string]string) (string, error) {
+func (m *MockACMClient) RequestCertificate(ctx context.Context, hostname, idempotencyToken string, tags map[string]string) (string, error) {
 	arn := "arn:aws:acm:us-east-1:123456789012:certificate/test-cert-" + hostname
 	m.certificates[arn] = "PENDING_VALIDATION"
 	return arn, nil
 }
 
+func (m *MockACMClient) RequestCertificateWithSANs(ctx context.Context, hostname string, subjectAlternativeNames []string, idempotencyToken string, tags map[string]string) (string, error) {
+	return m.RequestCertificate(ctx, hostname, idempotencyToken, tags)
+}
+
 func (m *MockACMClient) DescribeCertificate(ctx context.Context, arn string) (*aws.CertificateDetails, error) {
 	status, ok := m.certificates[arn]
 	if !ok {
@@ -52,9 +58,30 @@ func (m *MockACMClient) DeleteCertificate(ctx context.Context, arn string) error
 	return nil
 }
 
+func (m *MockACMClient) ImportCertificate(ctx context.Context, arn string, certificate, privateKey, chain []byte, tags map[string]string) (string, error) {
+	if arn == "" {
+		arn = "arn:aws:acm:us-east-1:123456789012:certificate/imported-" + tags["hostname"]
+	}
+	m.certificates[arn] = "ISSUED"
+	return arn, nil
+}
+
+func (m *MockACMClient) ListCertificates(ctx context.Context) ([]aws.CertificateSummary, error) {
+	return nil, nil
+}
+
+func (m *MockACMClient) GetCertificateTags(ctx context.Context, arn string) (map[string]string, error) {
+	return m.tags[arn], nil
+}
+
 // MockRoute53Client for testing
 type MockRoute53Client struct {
-	records map[string][]aws.DNSRecord // zoneId -> records
+	records map[string][]aws.DNSRecord   // zoneId -> records
+	dnssec  map[string]*aws.DNSSECStatus // zoneId -> status; zones without an entry are not signed
+	zones   map[string]*aws.HostedZone   // zone name -> zone
+
+	batches        []string // zoneId of every DeleteRecords change batch
+	batchDeleteErr error    // returned by DeleteRecords without deleting anything
 }
 
 func (m *MockRoute53Client) CreateOrUpdateRecord(ctx context.Context, zoneId string, record aws.DNSRecord) error {
@@ -78,6 +105,17 @@ func (m *MockRoute53Client) DeleteRecord(ctx context.Context, zoneId string, rec
 	return nil
 }
 
+func (m *MockRoute53Client) DeleteRecords(ctx context.Context, zoneId string, records []aws.DNSRecord) error {
+	if m.batchDeleteErr != nil {
+		return m.batchDeleteErr
+	}
+	m.batches = append(m.batches, zoneId)
+	for _, record := range records {
+		_ = m.DeleteRecord(ctx, zoneId, record)
+	}
+	return nil
+}
+
 func (m *MockRoute53Client) GetRecord(ctx context.Context, zoneId string, name, recordType string) (*aws.DNSRecord, error) {
 	if records, ok := m.records[zoneId]; ok {
 		for _, r := range records {
@@ -89,6 +127,47 @@ func (m *MockRoute53Client) GetRecord(ctx context.Context, zoneId string, name,
 	return nil, nil
 }
 
+func (m *MockRoute53Client) GetDNSSEC(ctx context.Context, zoneId string) (*aws.DNSSECStatus, error) {
+	if status, ok := m.dnssec[zoneId]; ok {
+		return status, nil
+	}
+	return &aws.DNSSECStatus{ServeSignature: "NOT_SIGNING"}, nil
+}
+
+func (m *MockRoute53Client) GetHostedZoneByName(ctx context.Context, name string) (*aws.HostedZone, error) {
+	return m.zones[name], nil
+}
+
+func (m *MockRoute53Client) CreateHostedZone(ctx context.Context, name, callerReference string) (*aws.HostedZone, error) {
+	if m.zones == nil {
+		m.zones = make(map[string]*aws.HostedZone)
+	}
+	if _, ok := m.zones[name]; ok {
+		return nil, fmt.Errorf("hosted zone %s already exists", name)
+	}
+	zone := &aws.HostedZone{ID: fmt.Sprintf("ZDELEGATED%d", len(m.zones)+1), Name: name,
+		NameServers: []string{"ns-2.awsdns-02.com", "ns-1.awsdns-01.org"}}
+	m.zones[name] = zone
+	return zone, nil
+}
+
+func (m *MockRoute53Client) GetHostedZone(ctx context.Context, zoneId string) (*aws.HostedZone, error) {
+	for _, zone := range m.zones {
+		if zone.ID == zoneId {
+			return zone, nil
+		}
+	}
+	return &aws.HostedZone{ID: zoneId}, nil
+}
+
+func (m *MockRoute53Client) AssociateVPC(ctx context.Context, zoneId string, vpc aws.VPC) error {
+	return nil
+}
+
+func (m *MockRoute53Client) DisassociateVPC(ctx context.Context, zoneId string, vpc aws.VPC) error {
+	return nil
+}
+
 func TestValidateAssignedResources_GatewayDeleted(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = gatewayv1alpha1.AddToScheme(scheme)
@@ -375,14 +454,13 @@ func TestEnsureGatewayConfiguration_AnnotationDrift(t *testing.T) {
 		},
 	}
 
-	// Create Gateway with MISSING loadbalancer-configuration annotation
+	// Create Gateway with MISSING loadbalancer-configuration and visibility annotations
 	gateway := &gwapiv1.Gateway{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "gw-01",
-			Namespace: "edge",
+			Name:        "gw-01",
+			Namespace:   "edge",
 			Annotations: map[string]string{
-				"gateway.opendi.com/visibility": "internal", // Wrong value
-				// Missing: gateway.k8s.aws/loadbalancer-configuration
+				// Missing: gateway.k8s.aws/loadbalancer-configuration and gateway.opendi.com/visibility
 			},
 		},
 	}
@@ -423,7 +501,7 @@ func TestEnsureGatewayConfiguration_AnnotationDrift(t *testing.T) {
 			updatedGw.Annotations["gateway.k8s.aws/loadbalancer-configuration"])
 	}
 
-	// Check visibility annotation was corrected
+	// Check visibility annotation was added
 	if updatedGw.Annotations["gateway.opendi.com/visibility"] != "internet-facing" {
 		t.Errorf("Expected visibility annotation to be 'internet-facing', got %s",
 			updatedGw.Annotations["gateway.opendi.com/visibility"])
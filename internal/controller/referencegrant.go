@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gwapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// LabelManagedBy marks objects created by the orchestrator outside the Gateway namespace
+const LabelManagedBy = "app.kubernetes.io/managed-by"
+
+// referenceGrantName returns the name of the ReferenceGrant letting HTTPRoutes in the
+// Gateway namespace reference Services in a request namespace
+func referenceGrantName(gatewayNamespace string) string {
+	return fmt.Sprintf("gateway-orchestrator-%s", gatewayNamespace)
+}
+
+// desiredReferenceGrantSpec allows HTTPRoutes in the Gateway namespace to use Services as backends
+func desiredReferenceGrantSpec(gatewayNamespace string) gwapiv1beta1.ReferenceGrantSpec {
+	return gwapiv1beta1.ReferenceGrantSpec{
+		From: []gwapiv1beta1.ReferenceGrantFrom{{
+			Group:     "gateway.networking.k8s.io",
+			Kind:      "HTTPRoute",
+			Namespace: gwapiv1beta1.Namespace(gatewayNamespace),
+		}},
+		To: []gwapiv1beta1.ReferenceGrantTo{{
+			Group: "",
+			Kind:  "Service",
+		}},
+	}
+}
+
+// ensureReferenceGrant lets HTTPRoutes in the assigned Gateway's namespace reference Services in
+// the request namespace, for platforms that keep routes next to the Gateway. Only runs with
+// BackendReferenceGrants and when the namespaces differ.
+func (r *GatewayHostnameRequestReconciler) ensureReferenceGrant(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	gatewayNamespace := ghr.Status.AssignedGatewayNamespace
+	if !r.BackendReferenceGrants || gatewayNamespace == "" || gatewayNamespace == ghr.Namespace {
+		return nil
+	}
+
+	logger := log.FromContext(ctx)
+	name := referenceGrantName(gatewayNamespace)
+	desired := desiredReferenceGrantSpec(gatewayNamespace)
+
+	var grant gwapiv1beta1.ReferenceGrant
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: ghr.Namespace}, &grant)
+	if apierrors.IsNotFound(err) {
+		grant = gwapiv1beta1.ReferenceGrant{}
+		grant.Name = name
+		grant.Namespace = ghr.Namespace
+		grant.Labels = map[string]string{LabelManagedBy: "gateway-orchestrator"}
+		grant.Spec = desired
+		if err := r.Create(ctx, &grant); err != nil {
+			return fmt.Errorf("failed to create ReferenceGrant: %w", err)
+		}
+		logger.Info("Created ReferenceGrant for HTTPRoute backends", "name", name, "namespace", ghr.Namespace)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get ReferenceGrant: %w", err)
+	}
+
+	if equality.Semantic.DeepEqual(grant.Spec, desired) {
+		return nil
+	}
+	grant.Spec = desired
+	if err := r.Update(ctx, &grant); err != nil {
+		return fmt.Errorf("failed to update ReferenceGrant: %w", err)
+	}
+	return nil
+}
+
+// removeReferenceGrant deletes the request namespace's ReferenceGrant for the Gateway namespace
+// once no other request in the namespace uses a Gateway there
+func (r *GatewayHostnameRequestReconciler) removeReferenceGrant(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	gatewayNamespace := ghr.Status.AssignedGatewayNamespace
+	if !r.BackendReferenceGrants || gatewayNamespace == "" || gatewayNamespace == ghr.Namespace {
+		return nil
+	}
+
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList); err != nil {
+		return fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+	for _, other := range ghrList.Items {
+		if other.Namespace != ghr.Namespace || other.Name == ghr.Name || !other.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if other.Status.AssignedGatewayNamespace == gatewayNamespace {
+			return nil
+		}
+	}
+
+	var grant gwapiv1beta1.ReferenceGrant
+	if err := r.Get(ctx, types.NamespacedName{Name: referenceGrantName(gatewayNamespace), Namespace: ghr.Namespace}, &grant); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get ReferenceGrant: %w", err)
+	}
+	if grant.Labels[LabelManagedBy] != "gateway-orchestrator" {
+		return nil
+	}
+	if err := r.Delete(ctx, &grant); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete ReferenceGrant: %w", err)
+	}
+	log.FromContext(ctx).Info("Deleted ReferenceGrant", "name", grant.Name, "namespace", grant.Namespace)
+	return nil
+}
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func TestReferenceGrant_CreatedAndRemovedWithLastRequest(t *testing.T) {
+	scheme := getTestScheme()
+	_ = gwapiv1beta1.AddToScheme(scheme)
+
+	first := assignedGHR("a", "a.example.com")
+	second := assignedGHR("b", "b.example.com")
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(first, second).Build()
+	r := &GatewayHostnameRequestReconciler{Client: c, BackendReferenceGrants: true}
+
+	require.NoError(t, r.ensureReferenceGrant(context.Background(), first))
+	require.NoError(t, r.ensureReferenceGrant(context.Background(), second))
+
+	key := types.NamespacedName{Name: "gateway-orchestrator-edge", Namespace: "default"}
+	var grant gwapiv1beta1.ReferenceGrant
+	require.NoError(t, c.Get(context.Background(), key, &grant))
+	require.Len(t, grant.Spec.From, 1)
+	assert.Equal(t, gwapiv1beta1.Namespace("edge"), grant.Spec.From[0].Namespace)
+	assert.Equal(t, gwapiv1beta1.Kind("Service"), grant.Spec.To[0].Kind)
+
+	// Still needed by the second request
+	require.NoError(t, c.Delete(context.Background(), first))
+	require.NoError(t, r.removeReferenceGrant(context.Background(), first))
+	require.NoError(t, c.Get(context.Background(), key, &grant))
+
+	require.NoError(t, c.Delete(context.Background(), second))
+	require.NoError(t, r.removeReferenceGrant(context.Background(), second))
+	assert.True(t, apierrors.IsNotFound(c.Get(context.Background(), key, &grant)))
+}
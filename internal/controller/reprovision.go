@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// minReprovisionRequeue keeps held back requests from requeueing in a tight loop
+const minReprovisionRequeue = 5 * time.Second
+
+var (
+	reprovisionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gateway_orchestrator_reprovisions_total",
+		Help: "Spec changes that started re-provisioning (started) or were held back by --max-reprovisions-per-minute (throttled).",
+	}, []string{"result"})
+
+	reprovisionCircuitOpen = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gateway_orchestrator_reprovision_circuit_open",
+		Help: "1 while spec changes are held back by --max-reprovisions-per-minute, 0 otherwise.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(reprovisionsTotal, reprovisionCircuitOpen)
+}
+
+// ReprovisionLimiter is a global circuit against re-provisioning storms: when many requests
+// change their spec at once, e.g. after a bad CRD default or a mass edit, it lets at most
+// PerMinute of them tear down their records, certificates and Gateway assignments per minute.
+// The others keep serving their previous spec until it is their turn. A nil limiter allows all.
+type ReprovisionLimiter struct {
+	perMinute int
+	limiter   *rate.Limiter
+
+	mu   sync.Mutex
+	open bool
+}
+
+// NewReprovisionLimiter allows perMinute re-provisionings per minute, with bursts of as many;
+// 0 or less returns nil (unlimited)
+func NewReprovisionLimiter(perMinute int) *ReprovisionLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &ReprovisionLimiter{
+		perMinute: perMinute,
+		limiter:   rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), perMinute),
+	}
+}
+
+// Allow takes a slot for a re-provisioning. If none is left, it returns false and how long
+// until the next one. Opening and closing the circuit is logged.
+func (l *ReprovisionLimiter) Allow(ctx context.Context) (bool, time.Duration) {
+	if l == nil {
+		reprovisionsTotal.WithLabelValues("started").Inc()
+		return true, 0
+	}
+	now := time.Now()
+	allowed := l.limiter.AllowN(now, 1)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if allowed {
+		reprovisionsTotal.WithLabelValues("started").Inc()
+		if l.open && l.limiter.TokensAt(now) >= 1 {
+			l.open = false
+			reprovisionCircuitOpen.Set(0)
+			log.FromContext(ctx).Info("Re-provisioning rate back to normal, circuit closed", "perMinute", l.perMinute)
+		}
+		return true, 0
+	}
+
+	reprovisionsTotal.WithLabelValues("throttled").Inc()
+	if !l.open {
+		l.open = true
+		reprovisionCircuitOpen.Set(1)
+		log.FromContext(ctx).Info("More spec changes than --max-reprovisions-per-minute, holding back re-provisioning",
+			"perMinute", l.perMinute)
+	}
+	r := l.limiter.ReserveN(now, 1)
+	wait := r.DelayFrom(now)
+	r.CancelAt(now)
+	return false, max(wait, minReprovisionRequeue)
+}
+
+// holdReprovisioning keeps the request on its previous spec until the limiter has a slot for it
+func (r *GatewayHostnameRequestReconciler) holdReprovisioning(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, wait time.Duration) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Spec changed, re-provisioning held back by the rate limit", "retryAfter", wait)
+
+	message := fmt.Sprintf("Spec change waits for the re-provisioning rate limit (%d per minute); the hostname is served with its previous spec meanwhile",
+		r.Reprovisions.perMinute)
+	if !conditions.IsTrue(ghr.Status.Conditions, ConditionTypeReprovisionPending) {
+		r.Recorder.Eventf(ghr, corev1.EventTypeWarning, string(conditions.ReasonReprovisioningThrottled), "%s", message)
+		r.setCondition(ghr, ConditionTypeReprovisionPending, metav1.ConditionTrue, conditions.ReasonReprovisioningThrottled, message)
+		if err := r.Status().Update(ctx, ghr); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: wait}, nil
+}
+
+// clearReprovisionPending removes ReprovisionPending from a request whose spec no longer needs
+// re-provisioning, e.g. because the held back change was reverted before its turn
+func (r *GatewayHostnameRequestReconciler) clearReprovisionPending(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	if conditions.Get(ghr.Status.Conditions, ConditionTypeReprovisionPending) == nil {
+		return nil
+	}
+	log.FromContext(ctx).Info("Held back spec change reverted, nothing to re-provision")
+	conditions.Remove(&ghr.Status.Conditions, ConditionTypeReprovisionPending)
+	ghr.Status.Message = explainStatus(ghr)
+	return r.Status().Update(ctx, ghr)
+}
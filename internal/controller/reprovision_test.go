@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+func TestReprovisionLimiter(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, NewReprovisionLimiter(0))
+	allowed, _ := (*ReprovisionLimiter)(nil).Allow(ctx)
+	assert.True(t, allowed, "a nil limiter is unlimited")
+
+	l := NewReprovisionLimiter(2)
+	for range 2 {
+		allowed, _ := l.Allow(ctx)
+		assert.True(t, allowed)
+	}
+	allowed, wait := l.Allow(ctx)
+	assert.False(t, allowed)
+	assert.Greater(t, wait, 20*time.Second)
+	assert.LessOrEqual(t, wait, 30*time.Second)
+	assert.True(t, l.open)
+}
+
+func TestHoldReprovisioning(t *testing.T) {
+	ctx := context.Background()
+	ghr := assignedGHR("app", "app.example.com")
+	ghr.Status.ObservedSpecHash = "previous"
+	recorder := record.NewFakeRecorder(10)
+	r := &GatewayHostnameRequestReconciler{
+		Client:       fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr).WithStatusSubresource(ghr).Build(),
+		Recorder:     recorder,
+		Reprovisions: NewReprovisionLimiter(1),
+	}
+
+	result, err := r.holdReprovisioning(ctx, ghr, 40*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, 40*time.Second, result.RequeueAfter)
+	cond := meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeReprovisionPending)
+	require.NotNil(t, cond)
+	assert.Equal(t, string(conditions.ReasonReprovisioningThrottled), cond.Reason)
+	assert.Equal(t, "edge", ghr.Status.AssignedGatewayNamespace, "the request keeps serving its previous spec")
+	assert.Len(t, recorder.Events, 1)
+
+	// Waiting longer doesn't repeat the event
+	_, err = r.holdReprovisioning(ctx, ghr, 20*time.Second)
+	require.NoError(t, err)
+	assert.Len(t, recorder.Events, 1)
+}
+
+func TestReprovisionPending_ClearedWhenSpecReverted(t *testing.T) {
+	ctx := context.Background()
+	hostnameType := gwapiv1.HostnameAddressType
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "gw-01", Namespace: "edge",
+			Annotations: map[string]string{AnnotationVisibility: "internet-facing"},
+		},
+		Spec: gwapiv1.GatewaySpec{GatewayClassName: "aws-alb"},
+		Status: gwapiv1.GatewayStatus{
+			Addresses: []gwapiv1.GatewayStatusAddress{
+				{Type: &hostnameType, Value: "k8s-gw01-abcdef1234-1234567890.us-east-1.elb.amazonaws.com"},
+			},
+			Conditions: []metav1.Condition{{Type: string(gwapiv1.GatewayConditionProgrammed), Status: metav1.ConditionTrue}},
+		},
+	}
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "redirect", Namespace: "default"},
+		Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "old-brand.example.com", ZoneId: "Z123456", TLS: TLSDisabled},
+	}
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(gw, ghr).WithStatusSubresource(ghr).Build()
+	r := &GatewayHostnameRequestReconciler{
+		Client:        c,
+		Scheme:        getTestScheme(),
+		Recorder:      record.NewFakeRecorder(50),
+		ACMClient:     &MockACMClient{certificates: make(map[string]string)},
+		Route53Client: &MockRoute53Client{records: make(map[string][]aws.DNSRecord)},
+		GatewayPool:   gateway.NewPool(c, "edge", "aws-alb", 0, 0),
+		ListenerMode:  ListenerModeHostname,
+		Reprovisions:  NewReprovisionLimiter(1),
+	}
+	_, err := r.reconcileNormal(ctx, ghr)
+	require.NoError(t, err)
+	require.True(t, meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeReady))
+
+	// The only slot is taken, so the spec change is held back
+	allowed, _ := r.Reprovisions.Allow(ctx)
+	require.True(t, allowed)
+	ghr.Spec.TLS = ""
+	result, err := r.reconcileNormal(ctx, ghr)
+	require.NoError(t, err)
+	assert.Positive(t, result.RequeueAfter)
+	require.True(t, meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeReprovisionPending))
+
+	// Reverted before its turn: nothing is pending anymore
+	ghr.Spec.TLS = TLSDisabled
+	_, err = r.reconcileNormal(ctx, ghr)
+	require.NoError(t, err)
+	assert.Nil(t, meta.FindStatusCondition(ghr.Status.Conditions, ConditionTypeReprovisionPending))
+	assert.True(t, meta.IsStatusConditionTrue(ghr.Status.Conditions, ConditionTypeReady))
+	assert.NotContains(t, ghr.Status.Message, "re-provisioning rate limit")
+	assert.Equal(t, "gw-01", ghr.Status.AssignedGateway, "nothing was torn down")
+}
@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"context"
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// reconcileRouteAccess labels the requesting namespace and configures the Gateway's
+// allowedRoutes, so the request's HTTPRoutes can attach. Without StrictRouteAccess failures are
+// only logged. In strict mode NamespaceLabeled and AllowedRoutesConfigured record the outcome;
+// when one of them is False, Ready is set False with ReasonRouteAccessFailed and the error is
+// returned, so the reconcile is retried with backoff.
+func (r *GatewayHostnameRequestReconciler) reconcileRouteAccess(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	logger := log.FromContext(ctx)
+
+	labelErr := r.ensureNamespaceLabel(ctx, ghr)
+	routesErr := r.ensureAllowedRoutes(ctx, ghr)
+	if !r.StrictRouteAccess {
+		if labelErr != nil {
+			logger.Info("Failed to label namespace for gateway access", "error", labelErr.Error())
+		}
+		if routesErr != nil {
+			logger.Info("Failed to configure allowedRoutes, continuing anyway", "error", routesErr.Error())
+		}
+		// Left over from strict mode, they would hold the request in explainStatus
+		conditions.Remove(&ghr.Status.Conditions, ConditionTypeNamespaceLabeled)
+		conditions.Remove(&ghr.Status.Conditions, ConditionTypeAllowedRoutesConfigured)
+		return nil
+	}
+
+	switch {
+	case r.NamespaceAccess == NamespaceAccessGateway:
+		// The allowlist on the Gateway is kept by ensureGatewayConfiguration
+		conditions.Remove(&ghr.Status.Conditions, ConditionTypeNamespaceLabeled)
+	case labelErr != nil:
+		if r.failCondition(ghr, ConditionTypeNamespaceLabeled, conditions.ReasonLabelFailed, labelErr) {
+			r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "NamespaceLabelFailed", "Failed to label namespace %s for Gateway access: %v", ghr.Namespace, labelErr)
+		}
+	default:
+		r.setCondition(ghr, ConditionTypeNamespaceLabeled, metav1.ConditionTrue, conditions.ReasonLabeled,
+			"Namespace labeled for access to Gateway "+ghr.Status.AssignedGateway)
+	}
+
+	if routesErr != nil {
+		if r.failCondition(ghr, ConditionTypeAllowedRoutesConfigured, conditions.ReasonConfigurationFailed, routesErr) {
+			r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "AllowedRoutesFailed", "Failed to configure allowedRoutes of Gateway %s: %v", ghr.Status.AssignedGateway, routesErr)
+		}
+	} else {
+		r.setCondition(ghr, ConditionTypeAllowedRoutesConfigured, metav1.ConditionTrue, conditions.ReasonConfigured,
+			"Gateway "+ghr.Status.AssignedGateway+" accepts HTTPRoutes from all namespaces")
+	}
+
+	err := errors.Join(labelErr, routesErr)
+	if err == nil {
+		return nil
+	}
+	if conditions.IsFalse(ghr.Status.Conditions, ConditionTypeNamespaceLabeled) || conditions.IsFalse(ghr.Status.Conditions, ConditionTypeAllowedRoutesConfigured) {
+		r.setCondition(ghr, ConditionTypeReady, metav1.ConditionFalse, conditions.ReasonRouteAccessFailed,
+			"HTTPRoutes can't attach to Gateway "+ghr.Status.AssignedGateway+": "+err.Error())
+	}
+	if updateErr := r.Status().Update(ctx, ghr); updateErr != nil {
+		return updateErr
+	}
+	return err
+}
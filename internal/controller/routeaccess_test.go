@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+)
+
+func routeAccessReconciler(strict bool) (*GatewayHostnameRequestReconciler, *record.FakeRecorder) {
+	ghr := ghrOnGateway(1, "gw-01")
+	ghr.Status.Conditions = []metav1.Condition{{Type: ConditionTypeReady, Status: metav1.ConditionTrue, Reason: "Ready"}}
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw-01", Namespace: "edge"},
+		Spec:       gwapiv1.GatewaySpec{Listeners: []gwapiv1.Listener{{Name: "http", Port: 80, Protocol: gwapiv1.HTTPProtocolType}}},
+	}
+	scheme := getTestScheme()
+	_ = corev1.AddToScheme(scheme)
+	recorder := record.NewFakeRecorder(10)
+	return &GatewayHostnameRequestReconciler{
+		Client:            fake.NewClientBuilder().WithScheme(scheme).WithObjects(ghr, gw).WithStatusSubresource(ghr).Build(),
+		Scheme:            scheme,
+		Recorder:          recorder,
+		StrictRouteAccess: strict,
+	}, recorder
+}
+
+func TestReconcileRouteAccess_Strict(t *testing.T) {
+	ctx := context.Background()
+	r, recorder := routeAccessReconciler(true)
+	ghr := ghrOnGateway(1, "gw-01")
+	require.NoError(t, r.Get(ctx, client.ObjectKeyFromObject(ghr), ghr))
+
+	// The namespace doesn't exist, so it can't be labeled
+	err := r.reconcileRouteAccess(ctx, ghr)
+	require.Error(t, err)
+	assert.True(t, conditions.IsFalse(ghr.Status.Conditions, ConditionTypeNamespaceLabeled))
+	assert.True(t, conditions.IsTrue(ghr.Status.Conditions, ConditionTypeAllowedRoutesConfigured))
+	assert.True(t, conditions.HasReason(ghr.Status.Conditions, ConditionTypeReady, metav1.ConditionFalse, conditions.ReasonRouteAccessFailed))
+	assert.Contains(t, <-recorder.Events, "NamespaceLabelFailed")
+	cond := conditions.Get(ghr.Status.Conditions, ConditionTypeNamespaceLabeled)
+	assert.Contains(t, explainStep(ghr, ConditionTypeNamespaceLabeled, cond), "labeling namespace default failed")
+
+	require.NoError(t, r.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}))
+	require.NoError(t, r.reconcileRouteAccess(ctx, ghr))
+	assert.True(t, conditions.IsTrue(ghr.Status.Conditions, ConditionTypeNamespaceLabeled))
+	steps := provisioningSteps(ghr)
+	assert.Equal(t, []string{ConditionTypeDnsAliasReady, ConditionTypeNamespaceLabeled, ConditionTypeAllowedRoutesConfigured}, steps[len(steps)-3:])
+}
+
+func TestReconcileRouteAccess_NotStrict(t *testing.T) {
+	ctx := context.Background()
+	r, _ := routeAccessReconciler(false)
+	ghr := ghrOnGateway(1, "gw-01")
+	require.NoError(t, r.Get(ctx, client.ObjectKeyFromObject(ghr), ghr))
+	ghr.Status.Conditions = append(ghr.Status.Conditions, metav1.Condition{Type: ConditionTypeNamespaceLabeled, Status: metav1.ConditionFalse})
+
+	require.NoError(t, r.reconcileRouteAccess(ctx, ghr), "failures are only logged")
+	assert.Nil(t, conditions.Get(ghr.Status.Conditions, ConditionTypeNamespaceLabeled), "left over from strict mode")
+	assert.True(t, conditions.IsTrue(ghr.Status.Conditions, ConditionTypeReady))
+}
@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// gatewayRef identifies a Gateway by namespace and name
+type gatewayRef struct {
+	namespace, name string
+}
+
+// parentGateway returns the Gateway a parentRef points at, or false for other kinds
+func parentGateway(route *gwapiv1.HTTPRoute, ref gwapiv1.ParentReference) (gatewayRef, bool) {
+	if ref.Kind != nil && *ref.Kind != "Gateway" {
+		return gatewayRef{}, false
+	}
+	namespace := route.Namespace
+	if ref.Namespace != nil {
+		namespace = string(*ref.Namespace)
+	}
+	return gatewayRef{namespace: namespace, name: string(ref.Name)}, true
+}
+
+// repairParentRefs points the route's parentRefs for pool Gateways at the request's Gateways:
+// the assigned one, plus the one it is moving off while a move is in flight. References to
+// other pool Gateways are dropped unless inUse reports that another hostname of the route is
+// still assigned there. Refs to Gateways outside the pool are left alone. Routes that don't
+// reference any pool Gateway are not touched. Returns true if the route changed.
+func repairParentRefs(route *gwapiv1.HTTPRoute, isPoolNamespace func(string) bool, assigned, migratingFrom gatewayRef, inUse func(gatewayRef) bool) bool {
+	var refs []gwapiv1.ParentReference
+	var template *gwapiv1.ParentReference
+	hasAssigned := false
+	changed := false
+	for i, ref := range route.Spec.ParentRefs {
+		gw, ok := parentGateway(route, ref)
+		if !ok || !isPoolNamespace(gw.namespace) {
+			refs = append(refs, ref)
+			continue
+		}
+		if template == nil {
+			template = &route.Spec.ParentRefs[i]
+		}
+		switch {
+		case gw == assigned:
+			hasAssigned = true
+		case gw == migratingFrom || inUse(gw):
+		default:
+			changed = true
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	if template == nil {
+		return false
+	}
+	if !hasAssigned {
+		// Keep sectionName and port: listener names are the same on every pool Gateway
+		ref := template.DeepCopy()
+		namespace := gwapiv1.Namespace(assigned.namespace)
+		ref.Namespace = &namespace
+		ref.Name = gwapiv1.ObjectName(assigned.name)
+		refs = append(refs, *ref)
+		changed = true
+	}
+	if changed {
+		route.Spec.ParentRefs = refs
+	}
+	return changed
+}
+
+// ensureRouteParentRefs repairs the parentRefs of the HTTPRoutes in the request namespace that
+// serve the hostname after it was assigned to another Gateway (drift, rebalancing or a
+// placement change), so traffic follows the hostname. During a move the routes reference
+// both Gateways until the old one is drained.
+func (r *GatewayHostnameRequestReconciler) ensureRouteParentRefs(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	if ghr.Status.AssignedGateway == "" || isDNSOnly(ghr) {
+		return nil
+	}
+	assigned := gatewayRef{namespace: ghr.Status.AssignedGatewayNamespace, name: ghr.Status.AssignedGateway}
+	var migratingFrom gatewayRef
+	if ghr.Status.MigratingFromGateway != "" {
+		migratingFrom = gatewayRef{namespace: migratingFromNamespace(ghr), name: ghr.Status.MigratingFromGateway}
+	}
+
+	var routes gwapiv1.HTTPRouteList
+	if err := r.List(ctx, &routes, client.InNamespace(ghr.Namespace)); err != nil {
+		return fmt.Errorf("failed to list HTTPRoutes: %w", err)
+	}
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList, client.InNamespace(ghr.Namespace)); err != nil {
+		return fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		if !route.DeletionTimestamp.IsZero() || !routeServesHostname(route, ghr.Spec.Hostname) {
+			continue
+		}
+		// A Gateway stays referenced while another hostname of the route is assigned to it
+		inUse := func(gw gatewayRef) bool {
+			for _, other := range ghrList.Items {
+				if other.Name == ghr.Name || !other.DeletionTimestamp.IsZero() || !routeServesHostname(route, other.Spec.Hostname) {
+					continue
+				}
+				if gw == (gatewayRef{namespace: other.Status.AssignedGatewayNamespace, name: other.Status.AssignedGateway}) ||
+					(other.Status.MigratingFromGateway != "" &&
+						gw == (gatewayRef{namespace: migratingFromNamespace(&other), name: other.Status.MigratingFromGateway})) {
+					return true
+				}
+			}
+			return false
+		}
+		if !repairParentRefs(route, r.GatewayPool.IsPoolNamespace, assigned, migratingFrom, inUse) {
+			continue
+		}
+		if err := r.Update(ctx, route); err != nil {
+			return fmt.Errorf("failed to update parentRefs of HTTPRoute %s: %w", route.Name, err)
+		}
+		log.FromContext(ctx).Info("Repaired HTTPRoute parentRefs", "route", route.Name, "gateway", assigned.namespace+"/"+assigned.name)
+		r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "RouteParentRefsRepaired",
+			"Pointed HTTPRoute %s at Gateway %s/%s", route.Name, assigned.namespace, assigned.name)
+	}
+	return nil
+}
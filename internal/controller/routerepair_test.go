@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+func parentGateways(route *gwapiv1.HTTPRoute) []string {
+	var names []string
+	for _, ref := range route.Spec.ParentRefs {
+		gw, _ := parentGateway(route, ref)
+		names = append(names, gw.namespace+"/"+gw.name)
+	}
+	return names
+}
+
+func TestEnsureRouteParentRefs_FollowsMove(t *testing.T) {
+	ctx := context.Background()
+	ghr := assignedGHR("app", "app.example.com")
+	ghr.Status.AssignedGateway = "gw-02"
+	ghr.Status.MigratingFromGateway = "gw-01"
+	ghr.Status.MigratingFromGatewayNamespace = "edge"
+
+	route := backendRoute("app", "app.example.com", "app")
+	section := gwapiv1.SectionName("https")
+	route.Spec.ParentRefs[0].SectionName = &section
+	external := gwapiv1.Namespace("mesh")
+	route.Spec.ParentRefs = append(route.Spec.ParentRefs, gwapiv1.ParentReference{Name: "mesh-gw", Namespace: &external})
+	other := backendRoute("other", "other.example.com", "other")
+
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr, route, other).Build()
+	r := &GatewayHostnameRequestReconciler{
+		Client:      c,
+		Recorder:    record.NewFakeRecorder(10),
+		GatewayPool: gateway.NewPool(c, "edge", "aws-alb", 0, 0),
+	}
+
+	// During the move the route references both Gateways
+	require.NoError(t, r.ensureRouteParentRefs(ctx, ghr))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(route), route))
+	assert.Equal(t, []string{"edge/gw-01", "mesh/mesh-gw", "edge/gw-02"}, parentGateways(route))
+	require.NotNil(t, route.Spec.ParentRefs[2].SectionName)
+	assert.Equal(t, section, *route.Spec.ParentRefs[2].SectionName)
+
+	// Once drained, the old Gateway is dropped
+	ghr.Status.MigratingFromGateway = ""
+	ghr.Status.MigratingFromGatewayNamespace = ""
+	require.NoError(t, r.ensureRouteParentRefs(ctx, ghr))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(route), route))
+	assert.Equal(t, []string{"mesh/mesh-gw", "edge/gw-02"}, parentGateways(route))
+
+	// Routes for other hostnames are left alone
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(other), other))
+	assert.Equal(t, []string{"edge/gw-01"}, parentGateways(other))
+}
+
+func TestEnsureRouteParentRefs_KeepsGatewayOfOtherHostnames(t *testing.T) {
+	ctx := context.Background()
+	app := assignedGHR("app", "app.example.com")
+	app.Status.AssignedGateway = "gw-02"
+	api := assignedGHR("api", "api.example.com")
+
+	route := backendRoute("shared", "app.example.com", "app")
+	route.Spec.Hostnames = append(route.Spec.Hostnames, "api.example.com")
+
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(app, api, route).Build()
+	r := &GatewayHostnameRequestReconciler{
+		Client:      c,
+		Recorder:    record.NewFakeRecorder(10),
+		GatewayPool: gateway.NewPool(c, "edge", "aws-alb", 0, 0),
+	}
+
+	require.NoError(t, r.ensureRouteParentRefs(ctx, app))
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(route), route))
+	assert.Equal(t, []string{"edge/gw-01", "edge/gw-02"}, parentGateways(route), "api.example.com is still on gw-01")
+}
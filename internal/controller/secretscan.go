@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"regexp"
+	"sort"
+)
+
+// secretPatterns match values that look like credentials. Tags and annotations are readable
+// by anyone who can list the resources carrying them, and are copied into AWS Config, Cost
+// Explorer and audit logs, so a secret that ends up in one has leaked.
+var secretPatterns = []struct {
+	kind    string
+	pattern *regexp.Regexp
+}{
+	{"an AWS access key ID", regexp.MustCompile(`\b(AKIA|ASIA)[A-Z0-9]{16}\b`)},
+	{"a private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{"a GitHub token", regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`)},
+	{"a Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{"a JSON web token", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.`)},
+	{"a bearer token", regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]{16,}`)},
+	{"a credential", regexp.MustCompile(`(?i)\b(password|passwd|secret|token|api[_-]?key)\s*[=:]\s*\S{8,}`)},
+}
+
+// secretKind returns what kind of secret the value looks like, e.g. "a private key", or "" if
+// it doesn't look like one
+func secretKind(value string) string {
+	for _, p := range secretPatterns {
+		if p.pattern.MatchString(value) {
+			return p.kind
+		}
+	}
+	return ""
+}
+
+// findSecret returns the first key (in sorted order) whose value looks like a secret, and the
+// kind of secret, or "" if none does
+func findSecret(values map[string]string) (string, string) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if kind := secretKind(values[key]); kind != "" {
+			return key, kind
+		}
+	}
+	return "", ""
+}
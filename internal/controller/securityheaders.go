@@ -0,0 +1,207 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+const (
+	// AnnotationManagedResponseHeaders lists the response headers the orchestrator set on an
+	// HTTPRoute, so they can be changed or removed without touching headers set by the route owner
+	AnnotationManagedResponseHeaders = "gateway.opendi.com/managed-response-headers"
+
+	// HeaderStrictTransportSecurity is the HSTS response header
+	HeaderStrictTransportSecurity = "Strict-Transport-Security"
+
+	// HeaderFrameOptions is the X-Frame-Options response header
+	HeaderFrameOptions = "X-Frame-Options"
+)
+
+// desiredSecurityHeaders returns the response headers to enforce for the request, sorted by
+// name: spec.securityHeaders with unset fields taken from DefaultSecurityHeaders
+func (r *GatewayHostnameRequestReconciler) desiredSecurityHeaders(ghr *gatewayv1alpha1.GatewayHostnameRequest) []gwapiv1.HTTPHeader {
+	hsts := r.DefaultSecurityHeaders.StrictTransportSecurity
+	frameOptions := r.DefaultSecurityHeaders.FrameOptions
+	if spec := ghr.Spec.SecurityHeaders; spec != nil {
+		if spec.StrictTransportSecurity != "" {
+			hsts = spec.StrictTransportSecurity
+		}
+		if spec.FrameOptions != "" {
+			frameOptions = spec.FrameOptions
+		}
+	}
+
+	var headers []gwapiv1.HTTPHeader
+	if hsts != "" {
+		headers = append(headers, gwapiv1.HTTPHeader{Name: HeaderStrictTransportSecurity, Value: hsts})
+	}
+	if frameOptions != "" {
+		headers = append(headers, gwapiv1.HTTPHeader{Name: HeaderFrameOptions, Value: frameOptions})
+	}
+	return headers
+}
+
+// managedHeaderNames returns the header names recorded in the route's managed-headers annotation
+func managedHeaderNames(route *gwapiv1.HTTPRoute) []string {
+	value := route.Annotations[AnnotationManagedResponseHeaders]
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// applyResponseHeaders sets the headers on every rule of the route through a
+// ResponseHeaderModifier filter and drops previously managed headers that are no longer
+// desired. Filters left empty are removed. Returns true if the route changed.
+func applyResponseHeaders(route *gwapiv1.HTTPRoute, headers []gwapiv1.HTTPHeader) bool {
+	managed := make(map[string]bool)
+	for _, name := range managedHeaderNames(route) {
+		managed[strings.ToLower(name)] = true
+	}
+	for _, h := range headers {
+		managed[strings.ToLower(string(h.Name))] = true
+	}
+
+	changed := false
+	for i := range route.Spec.Rules {
+		rule := &route.Spec.Rules[i]
+
+		idx := -1
+		for j, f := range rule.Filters {
+			if f.Type == gwapiv1.HTTPRouteFilterResponseHeaderModifier && f.ResponseHeaderModifier != nil {
+				idx = j
+				break
+			}
+		}
+		if idx < 0 {
+			if len(headers) == 0 {
+				continue
+			}
+			rule.Filters = append(rule.Filters, gwapiv1.HTTPRouteFilter{
+				Type:                   gwapiv1.HTTPRouteFilterResponseHeaderModifier,
+				ResponseHeaderModifier: &gwapiv1.HTTPHeaderFilter{},
+			})
+			idx = len(rule.Filters) - 1
+		}
+
+		modifier := rule.Filters[idx].ResponseHeaderModifier
+		var set []gwapiv1.HTTPHeader
+		for _, h := range modifier.Set {
+			if !managed[strings.ToLower(string(h.Name))] {
+				set = append(set, h)
+			}
+		}
+		set = append(set, headers...)
+		if !headersEqual(modifier.Set, set) {
+			modifier.Set = set
+			changed = true
+		}
+
+		if len(modifier.Set) == 0 && len(modifier.Add) == 0 && len(modifier.Remove) == 0 {
+			rule.Filters = append(rule.Filters[:idx], rule.Filters[idx+1:]...)
+			changed = true
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for _, h := range headers {
+		names = append(names, string(h.Name))
+	}
+	annotation := strings.Join(names, ",")
+	if route.Annotations[AnnotationManagedResponseHeaders] != annotation {
+		if annotation == "" {
+			delete(route.Annotations, AnnotationManagedResponseHeaders)
+		} else {
+			if route.Annotations == nil {
+				route.Annotations = make(map[string]string)
+			}
+			route.Annotations[AnnotationManagedResponseHeaders] = annotation
+		}
+		changed = true
+	}
+	return changed
+}
+
+func headersEqual(a, b []gwapiv1.HTTPHeader) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// headerOwner returns the request whose security headers apply to a route: the first by name
+// among the namespace's requests the route serves, so routes shared by several hostnames
+// don't flip between settings
+func headerOwner(route *gwapiv1.HTTPRoute, requests []gatewayv1alpha1.GatewayHostnameRequest) string {
+	var names []string
+	for _, ghr := range requests {
+		if !ghr.DeletionTimestamp.IsZero() || ghr.Status.AssignedGateway == "" {
+			continue
+		}
+		if routeAttachesTo(route, ghr.Status.AssignedGateway, ghr.Status.AssignedGatewayNamespace) &&
+			routeServesHostname(route, ghr.Spec.Hostname) {
+			names = append(names, ghr.Name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return names[0]
+}
+
+// ensureSecurityHeaders enforces the request's security response headers on the HTTPRoutes
+// serving its hostname
+func (r *GatewayHostnameRequestReconciler) ensureSecurityHeaders(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	return r.syncSecurityHeaders(ctx, ghr, r.desiredSecurityHeaders(ghr))
+}
+
+// removeSecurityHeaders drops the request's managed headers from its HTTPRoutes. Another request
+// sharing a route re-applies its own headers when it sees the route change.
+func (r *GatewayHostnameRequestReconciler) removeSecurityHeaders(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	return r.syncSecurityHeaders(ctx, ghr, nil)
+}
+
+func (r *GatewayHostnameRequestReconciler) syncSecurityHeaders(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, headers []gwapiv1.HTTPHeader) error {
+	routes, err := r.routesForHostname(ctx, ghr)
+	if err != nil || len(routes) == 0 {
+		return err
+	}
+
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList, client.InNamespace(ghr.Namespace)); err != nil {
+		return fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+
+	for _, route := range routes {
+		// Routes owned by another request are left to it; a request being deleted
+		// still cleans up what it set
+		if owner := headerOwner(route, ghrList.Items); owner != "" && owner != ghr.Name {
+			continue
+		}
+		if len(headers) == 0 && route.Annotations[AnnotationManagedResponseHeaders] == "" {
+			continue
+		}
+		if !applyResponseHeaders(route, headers) {
+			continue
+		}
+		if err := r.Update(ctx, route); err != nil {
+			return fmt.Errorf("failed to update security headers on HTTPRoute %s: %w", route.Name, err)
+		}
+		log.FromContext(ctx).Info("Updated security headers on HTTPRoute", "route", route.Name, "headers", route.Annotations[AnnotationManagedResponseHeaders])
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+func getRoute(t *testing.T, r *GatewayHostnameRequestReconciler, name string) *gwapiv1.HTTPRoute {
+	t.Helper()
+	var route gwapiv1.HTTPRoute
+	require.NoError(t, r.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, &route))
+	return &route
+}
+
+func TestSecurityHeaders_DefaultsAndOverrides(t *testing.T) {
+	ctx := context.Background()
+	ghr := assignedGHR("api", "api.example.com")
+	ghr.Spec.SecurityHeaders = &gatewayv1alpha1.SecurityHeaders{FrameOptions: "SAMEORIGIN"}
+
+	route := backendRoute("api", "api.example.com", "api-svc")
+	route.Spec.Rules[0].Filters = []gwapiv1.HTTPRouteFilter{{
+		Type: gwapiv1.HTTPRouteFilterResponseHeaderModifier,
+		ResponseHeaderModifier: &gwapiv1.HTTPHeaderFilter{
+			Set: []gwapiv1.HTTPHeader{{Name: "Cache-Control", Value: "no-store"}},
+		},
+	}}
+
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr, route).Build()
+	r := &GatewayHostnameRequestReconciler{
+		Client: c,
+		DefaultSecurityHeaders: gatewayv1alpha1.SecurityHeaders{
+			StrictTransportSecurity: "max-age=31536000",
+			FrameOptions:            "DENY",
+		},
+	}
+
+	require.NoError(t, r.ensureSecurityHeaders(ctx, ghr))
+
+	updated := getRoute(t, r, "api")
+	require.Len(t, updated.Spec.Rules[0].Filters, 1)
+	assert.Equal(t, []gwapiv1.HTTPHeader{
+		{Name: "Cache-Control", Value: "no-store"},
+		{Name: HeaderStrictTransportSecurity, Value: "max-age=31536000"},
+		{Name: HeaderFrameOptions, Value: "SAMEORIGIN"},
+	}, updated.Spec.Rules[0].Filters[0].ResponseHeaderModifier.Set)
+	assert.Equal(t, "Strict-Transport-Security,X-Frame-Options", updated.Annotations[AnnotationManagedResponseHeaders])
+
+	// Applying again is a no-op
+	version := updated.ResourceVersion
+	require.NoError(t, r.ensureSecurityHeaders(ctx, ghr))
+	assert.Equal(t, version, getRoute(t, r, "api").ResourceVersion)
+
+	// Removal keeps the route owner's headers
+	require.NoError(t, r.removeSecurityHeaders(ctx, ghr))
+	updated = getRoute(t, r, "api")
+	require.Len(t, updated.Spec.Rules[0].Filters, 1)
+	assert.Equal(t, []gwapiv1.HTTPHeader{{Name: "Cache-Control", Value: "no-store"}},
+		updated.Spec.Rules[0].Filters[0].ResponseHeaderModifier.Set)
+	assert.NotContains(t, updated.Annotations, AnnotationManagedResponseHeaders)
+}
+
+func TestSecurityHeaders_DroppedHeaderAndEmptyFilter(t *testing.T) {
+	ctx := context.Background()
+	ghr := assignedGHR("api", "api.example.com")
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(
+		ghr, backendRoute("api", "api.example.com", "api-svc"),
+	).Build()
+	r := &GatewayHostnameRequestReconciler{
+		Client:                 c,
+		DefaultSecurityHeaders: gatewayv1alpha1.SecurityHeaders{StrictTransportSecurity: "max-age=300", FrameOptions: "DENY"},
+	}
+	require.NoError(t, r.ensureSecurityHeaders(ctx, ghr))
+
+	// A default taken away is removed from the route
+	r.DefaultSecurityHeaders.StrictTransportSecurity = ""
+	require.NoError(t, r.ensureSecurityHeaders(ctx, ghr))
+	updated := getRoute(t, r, "api")
+	assert.Equal(t, []gwapiv1.HTTPHeader{{Name: HeaderFrameOptions, Value: "DENY"}},
+		updated.Spec.Rules[0].Filters[0].ResponseHeaderModifier.Set)
+
+	// With nothing left to set, the filter the controller added goes away
+	r.DefaultSecurityHeaders.FrameOptions = ""
+	require.NoError(t, r.ensureSecurityHeaders(ctx, ghr))
+	updated = getRoute(t, r, "api")
+	assert.Empty(t, updated.Spec.Rules[0].Filters)
+	assert.NotContains(t, updated.Annotations, AnnotationManagedResponseHeaders)
+}
+
+func TestSecurityHeaders_SharedRouteFirstRequestWins(t *testing.T) {
+	ctx := context.Background()
+	alpha := assignedGHR("alpha", "api.example.com")
+	alpha.Spec.SecurityHeaders = &gatewayv1alpha1.SecurityHeaders{FrameOptions: "DENY"}
+	beta := assignedGHR("beta", "www.example.com")
+	beta.Spec.SecurityHeaders = &gatewayv1alpha1.SecurityHeaders{FrameOptions: "SAMEORIGIN"}
+
+	// A route without hostnames serves both
+	route := backendRoute("shared", "", "svc")
+	route.Spec.Hostnames = nil
+
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(alpha, beta, route).Build()
+	r := &GatewayHostnameRequestReconciler{Client: c}
+
+	require.NoError(t, r.ensureSecurityHeaders(ctx, beta))
+	require.NoError(t, r.ensureSecurityHeaders(ctx, alpha))
+	require.NoError(t, r.ensureSecurityHeaders(ctx, beta))
+
+	updated := getRoute(t, r, "shared")
+	assert.Equal(t, []gwapiv1.HTTPHeader{{Name: HeaderFrameOptions, Value: "DENY"}},
+		updated.Spec.Rules[0].Filters[0].ResponseHeaderModifier.Set)
+}
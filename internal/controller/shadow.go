@@ -0,0 +1,295 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+var (
+	shadowChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gateway_orchestrator_shadow_changes_total",
+		Help: "Writes skipped in shadow mode that would have changed the current state, by kind and operation.",
+	}, []string{"kind", "operation"})
+
+	shadowPendingChanges = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gateway_orchestrator_shadow_pending_changes",
+		Help: "Objects shadow mode would change right now, by kind and the operation last skipped for them. " +
+			"An object drops out once a write to it would no longer change anything.",
+	}, []string{"kind", "operation"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(shadowChanges, shadowPendingChanges)
+}
+
+// ShadowDiffs collects the changes shadow mode skipped, for the shadow metrics and logs
+type ShadowDiffs struct {
+	mu sync.Mutex
+	// pending maps "<kind> <object>" to the operation of the last skipped write that would have
+	// changed the object
+	pending map[string]shadowWrite
+}
+
+type shadowWrite struct {
+	kind      string
+	operation string
+}
+
+// NewShadowDiffs returns an empty ShadowDiffs
+func NewShadowDiffs() *ShadowDiffs {
+	return &ShadowDiffs{pending: map[string]shadowWrite{}}
+}
+
+// Record reports a skipped write of an object. Changes are "path: old -> new" like specDiff; a
+// write without changes was a no-op and clears the object from the pending changes.
+func (d *ShadowDiffs) Record(ctx context.Context, kind, operation, object string, changes []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	key := kind + " " + object
+	if len(changes) == 0 {
+		if _, ok := d.pending[key]; !ok {
+			return
+		}
+		delete(d.pending, key)
+	} else {
+		log.FromContext(ctx).Info("Shadow mode skipped a change", "kind", kind, "operation", operation, "object", object, "changes", changes)
+		shadowChanges.WithLabelValues(kind, operation).Inc()
+		d.pending[key] = shadowWrite{kind: kind, operation: operation}
+	}
+
+	counts := map[shadowWrite]int{}
+	for _, write := range d.pending {
+		counts[write]++
+	}
+	shadowPendingChanges.Reset()
+	for write, n := range counts {
+		shadowPendingChanges.WithLabelValues(write.kind, write.operation).Set(float64(n))
+	}
+}
+
+// AWSReport returns the report of the shadow AWS clients, recording their calls as kind
+func (d *ShadowDiffs) AWSReport(kind string) aws.ShadowReport {
+	return func(ctx context.Context, operation, resource string, changes []string) {
+		d.Record(ctx, kind, operation, resource, changes)
+	}
+}
+
+// ShadowClient reads through to a Kubernetes client and records its writes in ShadowDiffs
+// instead of making them, compared with the current state of each object. Creating an object
+// that exists and changing one that doesn't fail like they would against the API server.
+type ShadowClient struct {
+	client.Client
+	diffs *ShadowDiffs
+}
+
+// NewShadowClient wraps inner so that it never changes the cluster
+func NewShadowClient(inner client.Client, diffs *ShadowDiffs) *ShadowClient {
+	return &ShadowClient{Client: inner, diffs: diffs}
+}
+
+// live reads the current state of obj, or returns nil if it doesn't exist
+func (c *ShadowClient) live(ctx context.Context, obj client.Object) (client.Object, error) {
+	var live client.Object
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		l := &unstructured.Unstructured{}
+		l.SetGroupVersionKind(u.GroupVersionKind())
+		live = l
+	} else {
+		gvk, err := c.GroupVersionKindFor(obj)
+		if err != nil {
+			return nil, err
+		}
+		o, err := c.Scheme().New(gvk)
+		if err != nil {
+			return nil, err
+		}
+		live = o.(client.Object)
+	}
+	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), live); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return live, nil
+}
+
+// shadowedFields returns the fields of obj a write changes: the status for status writes, and
+// otherwise everything else except the metadata the API server maintains
+func shadowedFields(obj client.Object, status bool) (map[string]interface{}, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	if status {
+		return map[string]interface{}{"status": content["status"]}, nil
+	}
+	fields := map[string]interface{}{}
+	for key, value := range content {
+		switch key {
+		case "apiVersion", "kind", "status":
+		case "metadata":
+			meta, _ := value.(map[string]interface{})
+			for _, field := range []string{"labels", "annotations", "finalizers", "ownerReferences"} {
+				if v, ok := meta[field]; ok {
+					fields["metadata."+field] = v
+				}
+			}
+		default:
+			fields[key] = value
+		}
+	}
+	return fields, nil
+}
+
+// objectName returns the kind and namespace/name of obj
+func (c *ShadowClient) objectName(obj client.Object) (kind, name string) {
+	kind = obj.GetObjectKind().GroupVersionKind().Kind
+	if gvk, err := c.GroupVersionKindFor(obj); err == nil {
+		kind = gvk.Kind
+	}
+	name = obj.GetName()
+	if obj.GetNamespace() != "" {
+		name = obj.GetNamespace() + "/" + name
+	}
+	return kind, name
+}
+
+// groupResource names the object's kind in errors
+func (c *ShadowClient) groupResource(obj client.Object) schema.GroupResource {
+	gvk, _ := c.GroupVersionKindFor(obj)
+	return schema.GroupResource{Group: gvk.Group, Resource: strings.ToLower(gvk.Kind)}
+}
+
+// recordChange records a write of obj that replaces the live object
+func (c *ShadowClient) recordChange(ctx context.Context, operation string, obj client.Object, status bool) error {
+	live, err := c.live(ctx, obj)
+	if err != nil {
+		return err
+	}
+	kind, name := c.objectName(obj)
+	if live == nil {
+		return apierrors.NewNotFound(c.groupResource(obj), obj.GetName())
+	}
+	before, err := shadowedFields(live, status)
+	if err != nil {
+		return err
+	}
+	after, err := shadowedFields(obj, status)
+	if err != nil {
+		return err
+	}
+	c.diffs.Record(ctx, kind, operation, name, specDiff(before, after))
+	return nil
+}
+
+func (c *ShadowClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	kind, name := c.objectName(obj)
+	if obj.GetName() != "" {
+		live, err := c.live(ctx, obj)
+		if err != nil {
+			return err
+		}
+		if live != nil {
+			return apierrors.NewAlreadyExists(c.groupResource(obj), obj.GetName())
+		}
+	} else {
+		name = obj.GetNamespace() + "/" + obj.GetGenerateName() + "*"
+	}
+	c.diffs.Record(ctx, kind, "create", name, []string{"object: <unset> -> created"})
+	return nil
+}
+
+func (c *ShadowClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return c.recordChange(ctx, "update", obj, false)
+}
+
+// Patch compares the patched object as the caller built it with the live object, which is
+// exact for the merge patches the controller makes
+func (c *ShadowClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return c.recordChange(ctx, "patch", obj, false)
+}
+
+func (c *ShadowClient) Apply(ctx context.Context, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+	c.diffs.Record(ctx, fmt.Sprintf("%T", obj), "apply", "", []string{"object: applied"})
+	return nil
+}
+
+func (c *ShadowClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	live, err := c.live(ctx, obj)
+	if err != nil {
+		return err
+	}
+	kind, name := c.objectName(obj)
+	if live == nil {
+		return apierrors.NewNotFound(c.groupResource(obj), obj.GetName())
+	}
+	c.diffs.Record(ctx, kind, "delete", name, []string{"object: exists -> <unset>"})
+	return nil
+}
+
+func (c *ShadowClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	kind, _ := c.objectName(obj)
+	c.diffs.Record(ctx, kind, "deleteallof", obj.GetNamespace(), []string{"objects: exist -> <unset>"})
+	return nil
+}
+
+func (c *ShadowClient) Status() client.SubResourceWriter {
+	return &shadowSubResourceWriter{client: c, subResource: "status"}
+}
+
+func (c *ShadowClient) SubResource(subResource string) client.SubResourceClient {
+	return &shadowSubResourceClient{
+		SubResourceReader: c.Client.SubResource(subResource),
+		shadowSubResourceWriter: shadowSubResourceWriter{
+			client:      c,
+			subResource: subResource,
+		},
+	}
+}
+
+type shadowSubResourceClient struct {
+	client.SubResourceReader
+	shadowSubResourceWriter
+}
+
+// shadowSubResourceWriter records status writes, compared with the live status; writes of
+// other subresources are recorded without a comparison
+type shadowSubResourceWriter struct {
+	client      *ShadowClient
+	subResource string
+}
+
+func (w *shadowSubResourceWriter) write(ctx context.Context, operation string, obj client.Object) error {
+	if w.subResource == "status" {
+		return w.client.recordChange(ctx, operation, obj, true)
+	}
+	kind, name := w.client.objectName(obj)
+	w.client.diffs.Record(ctx, kind, operation, name, []string{w.subResource + ": written"})
+	return nil
+}
+
+func (w *shadowSubResourceWriter) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	return w.write(ctx, w.subResource+"-create", obj)
+}
+
+func (w *shadowSubResourceWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	return w.write(ctx, w.subResource+"-update", obj)
+}
+
+func (w *shadowSubResourceWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	return w.write(ctx, w.subResource+"-patch", obj)
+}
@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestShadowClient(t *testing.T) {
+	ctx := context.Background()
+	ghr := ghrOnGateway(1, "gw-01")
+	gw := &gwapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gw-01", Namespace: "edge"}}
+	inner := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr, gw).WithStatusSubresource(ghr).Build()
+	shadowChanges.Reset()
+	c := NewShadowClient(inner, NewShadowDiffs())
+
+	// A change is recorded, not made
+	changed := gw.DeepCopy()
+	changed.Annotations = map[string]string{AnnotationVisibility: "internal"}
+	require.NoError(t, c.Update(ctx, changed))
+	var live gwapiv1.Gateway
+	require.NoError(t, inner.Get(ctx, client.ObjectKeyFromObject(gw), &live))
+	assert.Empty(t, live.Annotations)
+	assert.Equal(t, 1.0, testutil.ToFloat64(shadowChanges.WithLabelValues("Gateway", "update")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(shadowPendingChanges.WithLabelValues("Gateway", "update")))
+
+	// Once the live controller made the change, the same write is a no-op
+	require.NoError(t, inner.Update(ctx, changed.DeepCopy()))
+	require.NoError(t, c.Update(ctx, changed))
+	assert.Equal(t, 1.0, testutil.ToFloat64(shadowChanges.WithLabelValues("Gateway", "update")))
+	assert.Equal(t, 0, testutil.CollectAndCount(shadowPendingChanges))
+
+	// Status writes only compare the status
+	status := ghr.DeepCopy()
+	status.Status.AssignedGateway = "gw-02"
+	require.NoError(t, c.Status().Update(ctx, status))
+	assert.Equal(t, 1.0, testutil.ToFloat64(shadowChanges.WithLabelValues("GatewayHostnameRequest", "status-update")))
+
+	// Writes fail like they would against the API server
+	assert.True(t, apierrors.IsAlreadyExists(c.Create(ctx, gw.DeepCopy())))
+	missing := &gwapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{Name: "gw-09", Namespace: "edge"}}
+	assert.True(t, apierrors.IsNotFound(c.Delete(ctx, missing)))
+	require.NoError(t, c.Create(ctx, missing))
+	assert.True(t, apierrors.IsNotFound(inner.Get(ctx, client.ObjectKeyFromObject(missing), &live)))
+	require.NoError(t, c.Delete(ctx, gw))
+	require.NoError(t, inner.Get(ctx, client.ObjectKeyFromObject(gw), &live))
+	assert.Equal(t, 1.0, testutil.ToFloat64(shadowChanges.WithLabelValues("Gateway", "create")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(shadowChanges.WithLabelValues("Gateway", "delete")))
+}
@@ -0,0 +1,220 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+// Shared ownership lets several requests, typically one per cluster of an active-active setup,
+// serve one hostname with weighted ALIAS records. They share a DomainClaim that lists each of
+// them with the set identifier of its records. A set identifier has exactly one owner, and
+// only that owner writes or deletes the records carrying it.
+
+// setIdentifier returns the set identifier of the request's weighted records, or "" for
+// requests without sharedOwnership
+func setIdentifier(ghr *gatewayv1alpha1.GatewayHostnameRequest) string {
+	if ghr.Spec.SharedOwnership == nil {
+		return ""
+	}
+	return ghr.Spec.SharedOwnership.SetIdentifier
+}
+
+// recordSetContext returns a context in which Route53 lookups find the request's own
+// weighted records rather than simple ones
+func recordSetContext(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) context.Context {
+	return aws.WithSetIdentifier(ctx, setIdentifier(ghr))
+}
+
+// ownAliasRecord makes an ALIAS record the request's weighted record set if it has
+// sharedOwnership
+func ownAliasRecord(ghr *gatewayv1alpha1.GatewayHostnameRequest, record aws.DNSRecord) aws.DNSRecord {
+	if shared := ghr.Spec.SharedOwnership; shared != nil {
+		record.SetIdentifier = shared.SetIdentifier
+		weight := shared.Weight
+		record.Weight = &weight
+	}
+	return record
+}
+
+// aliasWeightInSync reports whether the ALIAS records carry the request's current weight
+func aliasWeightInSync(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	if ghr.Spec.SharedOwnership == nil {
+		return ghr.Status.AliasWeight == nil
+	}
+	return ghr.Status.AliasWeight != nil && *ghr.Status.AliasWeight == ghr.Spec.SharedOwnership.Weight
+}
+
+// claimOwner returns the request as an owner of a shared claim
+func claimOwner(ghr *gatewayv1alpha1.GatewayHostnameRequest) gatewayv1alpha1.DomainClaimOwner {
+	return gatewayv1alpha1.DomainClaimOwner{
+		DomainClaimOwnerRef: gatewayv1alpha1.DomainClaimOwnerRef{
+			Namespace: ghr.Namespace,
+			Name:      ghr.Name,
+			UID:       string(ghr.UID),
+		},
+		SetIdentifier: setIdentifier(ghr),
+	}
+}
+
+// isOwner reports whether the owner of a shared claim is the request
+func isOwner(owner gatewayv1alpha1.DomainClaimOwner, ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	return owner.Namespace == ghr.Namespace && owner.Name == ghr.Name && owner.UID == string(ghr.UID)
+}
+
+// ownerIndex returns the position of the request among the owners of a shared claim, or -1
+func ownerIndex(claim *gatewayv1alpha1.DomainClaim, ghr *gatewayv1alpha1.GatewayHostnameRequest) int {
+	return slices.IndexFunc(claim.Spec.Owners, func(owner gatewayv1alpha1.DomainClaimOwner) bool {
+		return isOwner(owner, ghr)
+	})
+}
+
+// sharedClaimConflict returns why the request can't own the claim alongside its owners, or
+// an empty reason if it can: shared and exclusive ownership don't mix, and each set
+// identifier has a single owner
+func sharedClaimConflict(claim *gatewayv1alpha1.DomainClaim, ghr *gatewayv1alpha1.GatewayHostnameRequest) (conditions.Reason, string) {
+	if ghr.Spec.SharedOwnership == nil {
+		if !claim.Spec.Shared {
+			return "", ""
+		}
+		return conditions.ReasonOwnershipMismatch, fmt.Sprintf(
+			"Hostname is shared by %d owners; set spec.sharedOwnership to serve it with them", len(claim.Spec.Owners))
+	}
+	if !claim.Spec.Shared {
+		return conditions.ReasonOwnershipMismatch, fmt.Sprintf(
+			"Hostname is claimed by %s/%s without sharedOwnership", claim.Spec.OwnerRef.Namespace, claim.Spec.OwnerRef.Name)
+	}
+	for _, owner := range claim.Spec.Owners {
+		if owner.SetIdentifier != setIdentifier(ghr) || isOwner(owner, ghr) {
+			continue
+		}
+		holder := owner.Namespace + "/" + owner.Name
+		if owner.Cluster != "" {
+			holder += " in cluster " + owner.Cluster
+		}
+		return conditions.ReasonSetIdentifierTaken, fmt.Sprintf("Set identifier %s is held by %s", owner.SetIdentifier, holder)
+	}
+	return "", ""
+}
+
+// joinSharedClaim adds the request to the owners of a shared claim, and reports whether it
+// owns the claim. A claim changed by another owner since it was read is an error, so the
+// request is reconciled again with the current owners.
+func (r *GatewayHostnameRequestReconciler) joinSharedClaim(ctx context.Context, claim *gatewayv1alpha1.DomainClaim, ghr *gatewayv1alpha1.GatewayHostnameRequest) (bool, error) {
+	if reason, _ := sharedClaimConflict(claim, ghr); reason != "" {
+		return false, nil
+	}
+	if ownerIndex(claim, ghr) >= 0 {
+		return true, nil
+	}
+
+	updated := claim.DeepCopy()
+	updated.Spec.Owners = append(updated.Spec.Owners, claimOwner(ghr))
+	written, err := r.claimStore().Update(ctx, claim, updated)
+	if err != nil {
+		return false, err
+	}
+	if !written {
+		return false, fmt.Errorf("DomainClaim %s changed while joining it, will retry", claim.Name)
+	}
+	log.FromContext(ctx).Info("Joined shared domain claim", "claim", claim.Name, "owners", len(updated.Spec.Owners))
+	return true, nil
+}
+
+// leaveSharedClaim removes the owners for which remove returns true from a shared claim, and
+// deletes the claim with its last owner. The first remaining owner becomes the claim's
+// ownerRef.
+func leaveSharedClaim(ctx context.Context, store ClaimStore, claim *gatewayv1alpha1.DomainClaim, remove func(gatewayv1alpha1.DomainClaimOwner) bool) error {
+	owners := slices.DeleteFunc(slices.Clone(claim.Spec.Owners), remove)
+	if len(owners) == len(claim.Spec.Owners) {
+		return nil
+	}
+	if len(owners) == 0 {
+		return store.Delete(ctx, claim)
+	}
+
+	updated := claim.DeepCopy()
+	updated.Spec.Owners = owners
+	updated.Spec.OwnerRef = owners[0].DomainClaimOwnerRef
+	written, err := store.Update(ctx, claim, updated)
+	if err != nil {
+		return err
+	}
+	if !written {
+		return fmt.Errorf("DomainClaim %s changed while leaving it, will retry", claim.Name)
+	}
+	return nil
+}
+
+// sweepSharedClaim removes the owners of this cluster whose request is gone from a shared
+// claim, and reports whether there were any. Owners in other clusters are left to them.
+func (r *GatewayHostnameRequestReconciler) sweepSharedClaim(ctx context.Context, store ClaimStore, claim *gatewayv1alpha1.DomainClaim) (bool, error) {
+	var gone []gatewayv1alpha1.DomainClaimOwner
+	for _, owner := range claim.Spec.Owners {
+		if !store.LocalOwner(owner) {
+			continue
+		}
+		// Read the owner from the API server: an owner must not be dropped for a stale cache
+		var ghr gatewayv1alpha1.GatewayHostnameRequest
+		err := r.apiReader().Get(ctx, types.NamespacedName{Namespace: owner.Namespace, Name: owner.Name}, &ghr)
+		if err == nil && string(ghr.UID) == owner.UID {
+			continue
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to get owner of DomainClaim %s: %w", claim.Name, err)
+		}
+		gone = append(gone, owner)
+	}
+	if len(gone) == 0 {
+		return false, nil
+	}
+	if err := leaveSharedClaim(ctx, store, claim, func(owner gatewayv1alpha1.DomainClaimOwner) bool {
+		return slices.Contains(gone, owner)
+	}); err != nil {
+		return false, fmt.Errorf("failed to remove owners from DomainClaim %s: %w", claim.Name, err)
+	}
+	return true, nil
+}
+
+// hasCoOwners reports whether other requests share the request's hostname. Their certificates
+// are validated through the same DNS records, so those stay until the last owner is gone.
+func (r *GatewayHostnameRequestReconciler) hasCoOwners(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (bool, error) {
+	if ghr.Spec.SharedOwnership == nil {
+		return false, nil
+	}
+	claim, err := r.claimStore().Get(ctx, r.claimName(ghr))
+	if err != nil || claim == nil || !claim.Spec.Shared {
+		return false, err
+	}
+	return slices.ContainsFunc(claim.Spec.Owners, func(owner gatewayv1alpha1.DomainClaimOwner) bool {
+		return !isOwner(owner, ghr)
+	}), nil
+}
+
+// recordSharedClaimConflict sets Claimed False with the reason the request can't join the
+// hostname's claim, and reports whether there is one
+func (r *GatewayHostnameRequestReconciler) recordSharedClaimConflict(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	claim, err := r.claimStore().Get(ctx, r.claimName(ghr))
+	if err != nil || claim == nil {
+		return false
+	}
+	reason, message := sharedClaimConflict(claim, ghr)
+	if reason == "" {
+		return false
+	}
+	if !conditions.HasReason(ghr.Status.Conditions, ConditionTypeClaimed, metav1.ConditionFalse, reason) {
+		r.Recorder.Event(ghr, corev1.EventTypeWarning, string(reason), message)
+	}
+	r.setCondition(ghr, ConditionTypeClaimed, metav1.ConditionFalse, reason, message)
+	return true
+}
@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+func sharedRequest(name, uid, setIdentifier string, weight int64) *gatewayv1alpha1.GatewayHostnameRequest {
+	return &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a", UID: types.UID(uid)},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+			ZoneId:          "Z123",
+			Hostname:        "app.example.com",
+			SharedOwnership: &gatewayv1alpha1.SharedOwnership{SetIdentifier: setIdentifier, Weight: weight},
+		},
+	}
+}
+
+func TestSharedOwnership_JoinAndLeave(t *testing.T) {
+	ctx := context.Background()
+	table := aws.NewMockItemTable()
+	east, west := clusterReconciler(table, "east"), clusterReconciler(table, "west")
+	eastApp := sharedRequest("app", "uid-east", "east", 50)
+	westApp := sharedRequest("app", "uid-west", "west", 50)
+
+	claimed, err := east.ensureDomainClaim(ctx, eastApp)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	claimed, err = west.ensureDomainClaim(ctx, westApp)
+	require.NoError(t, err)
+	assert.True(t, claimed, "joins the shared claim")
+	claimed, err = west.ensureDomainClaim(ctx, westApp)
+	require.NoError(t, err)
+	assert.True(t, claimed, "already an owner")
+
+	claim, err := east.claimStore().Get(ctx, "z123-app.example.com")
+	require.NoError(t, err)
+	require.NotNil(t, claim)
+	assert.True(t, claim.Spec.Shared)
+	require.Len(t, claim.Spec.Owners, 2)
+	assert.Equal(t, "east", claim.Spec.Owners[0].Cluster)
+	assert.Equal(t, "west", claim.Spec.Owners[1].Cluster)
+	assert.Equal(t, "west", claim.Spec.Owners[1].SetIdentifier)
+	coOwners, err := east.hasCoOwners(ctx, eastApp)
+	require.NoError(t, err)
+	assert.True(t, coOwners)
+
+	// A set identifier has a single owner
+	taken := sharedRequest("app-2", "uid-west-2", "east", 10)
+	claimed, err = west.ensureDomainClaim(ctx, taken)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+	reason, message := sharedClaimConflict(claim, taken)
+	assert.Equal(t, conditions.ReasonSetIdentifierTaken, reason)
+	assert.Equal(t, "Set identifier east is held by team-a/app in cluster east", message)
+
+	// Shared and exclusive ownership don't mix
+	exclusive := sharedRequest("app-3", "uid-west-3", "", 0)
+	exclusive.Spec.SharedOwnership = nil
+	claimed, err = west.ensureDomainClaim(ctx, exclusive)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+	reason, _ = sharedClaimConflict(claim, exclusive)
+	assert.Equal(t, conditions.ReasonOwnershipMismatch, reason)
+
+	// The claim passes to the remaining owner, and goes with the last one
+	require.NoError(t, east.deleteDomainClaim(ctx, eastApp))
+	item := table.Items["z123-app.example.com"]
+	assert.Equal(t, "west", item["cluster"])
+	assert.Equal(t, "uid-west", item["ownerUid"])
+	coOwners, err = west.hasCoOwners(ctx, westApp)
+	require.NoError(t, err)
+	assert.False(t, coOwners)
+	require.NoError(t, west.deleteDomainClaim(ctx, westApp))
+	assert.Empty(t, table.Items)
+}
+
+func TestSharedOwnership_ExclusiveClaimNotJoined(t *testing.T) {
+	ctx := context.Background()
+	r := clusterReconciler(aws.NewMockItemTable(), "east")
+	exclusive := sharedRequest("app", "uid-1", "", 0)
+	exclusive.Spec.SharedOwnership = nil
+
+	claimed, err := r.ensureDomainClaim(ctx, exclusive)
+	require.NoError(t, err)
+	require.True(t, claimed)
+	shared := sharedRequest("app-2", "uid-2", "east", 50)
+	claimed, err = r.ensureDomainClaim(ctx, shared)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	assert.True(t, r.recordSharedClaimConflict(ctx, shared))
+	assert.True(t, conditions.HasReason(shared.Status.Conditions, ConditionTypeClaimed, metav1.ConditionFalse, conditions.ReasonOwnershipMismatch))
+}
+
+func TestDynamoDBClaimStore_UpdateOnlyAppliesToClaimAsRead(t *testing.T) {
+	ctx := context.Background()
+	store := &DynamoDBClaimStore{Table: aws.NewMockItemTable(), Cluster: "east"}
+	r := &GatewayHostnameRequestReconciler{ClaimStore: store}
+	claimed, err := r.ensureDomainClaim(ctx, sharedRequest("app", "uid-east", "east", 50))
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	read, err := store.Get(ctx, "z123-app.example.com")
+	require.NoError(t, err)
+	first := read.DeepCopy()
+	first.Spec.Owners = append(first.Spec.Owners, gatewayv1alpha1.DomainClaimOwner{SetIdentifier: "west", Cluster: "west"})
+	written, err := store.Update(ctx, read, first)
+	require.NoError(t, err)
+	assert.True(t, written)
+
+	second := read.DeepCopy()
+	second.Spec.Owners = append(second.Spec.Owners, gatewayv1alpha1.DomainClaimOwner{SetIdentifier: "south", Cluster: "south"})
+	written, err = store.Update(ctx, read, second)
+	require.NoError(t, err)
+	assert.False(t, written, "changed since it was read")
+
+	current, err := store.Get(ctx, "z123-app.example.com")
+	require.NoError(t, err)
+	require.Len(t, current.Spec.Owners, 2)
+	assert.Equal(t, "west", current.Spec.Owners[1].SetIdentifier)
+}
+
+func TestSharedOwnership_WeightedAliasRecords(t *testing.T) {
+	ctx := context.Background()
+	route53 := aws.NewMockRoute53Client()
+	r := &GatewayHostnameRequestReconciler{Route53Client: route53}
+	target := &aws.AliasTarget{DNSName: "east-alb.elb.amazonaws.com", HostedZoneID: "ZALB"}
+	// The record set of the hostname's owner in another cluster
+	require.NoError(t, route53.CreateOrUpdateRecord(ctx, "Z123", aws.DNSRecord{Name: "app.example.com", Type: "A",
+		AliasTarget: &aws.AliasTarget{DNSName: "west-alb.elb.amazonaws.com"}, SetIdentifier: "west"}))
+
+	ghr := sharedRequest("app", "uid-east", "east", 50)
+	assert.False(t, aliasWeightInSync(ghr))
+	require.NoError(t, r.publishAliasRecords(ctx, ghr, target, []string{"A"}))
+	record := route53.Records["Z123:app.example.com:A:east"]
+	require.NotNil(t, record.Weight)
+	assert.Equal(t, int64(50), *record.Weight)
+	assert.Equal(t, target, record.AliasTarget)
+	assert.True(t, aliasWeightInSync(ghr))
+
+	ghr.Spec.SharedOwnership.Weight = 0
+	assert.False(t, aliasWeightInSync(ghr), "drained")
+	require.NoError(t, r.publishAliasRecords(ctx, ghr, target, []string{"A"}))
+	assert.Equal(t, int64(0), *route53.Records["Z123:app.example.com:A:east"].Weight)
+
+	assert.Empty(t, r.deleteAliasRecordsInZone(ctx, ghr, "Z123", "A"))
+	assert.NotContains(t, route53.Records, "Z123:app.example.com:A:east")
+	assert.Contains(t, route53.Records, "Z123:app.example.com:A:west", "another owner's records")
+}
@@ -0,0 +1,555 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// Defaults for SLOReport
+const (
+	DefaultTimeToReadyObjective  = 15 * time.Minute
+	DefaultProvisioningObjective = 0.99
+	DefaultSLOWindows            = "1d,7d,30d"
+)
+
+// Provisioning outcomes used as the "outcome" label on sloProvisionings
+const (
+	ProvisioningReady   = "ready"
+	ProvisioningFailed  = "failed"
+	ProvisioningOverdue = "overdue"
+)
+
+// SLOReportKey is the key of the report in the SLO report ConfigMap
+const SLOReportKey = "report.json"
+
+// SLOOutcomesKey is the key of the recorded provisioning outcomes in the SLO report ConfigMap
+const SLOOutcomesKey = "outcomes.json"
+
+// maxSLOOutcomes caps the recorded outcomes so they fit into the ConfigMap; the oldest are
+// dropped first
+const maxSLOOutcomes = 4000
+
+var (
+	timeToReady = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gateway_orchestrator_time_to_ready_seconds",
+		Help:    "Time from a GatewayHostnameRequest's creation, spec change or loss of Ready until it was Ready, by namespace.",
+		Buckets: []float64{30, 60, 120, 300, 600, 900, 1800, 3600, 7200},
+	}, []string{"namespace"})
+
+	sloProvisionings = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gateway_orchestrator_slo_provisionings",
+		Help: "Provisionings per namespace and rolling window by outcome: ready, failed, or overdue (still pending past the time-to-ready objective).",
+	}, []string{"namespace", "window", "outcome"})
+
+	sloTimeToReady = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gateway_orchestrator_slo_time_to_ready_seconds",
+		Help: "Time to Ready of the provisionings per namespace and rolling window, at the 0.5 and 0.95 quantiles.",
+	}, []string{"namespace", "window", "quantile"})
+
+	sloCompliance = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gateway_orchestrator_slo_compliance_ratio",
+		Help: "Fraction of the provisionings per namespace and rolling window that were Ready within the time-to-ready objective.",
+	}, []string{"namespace", "window"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(timeToReady, sloProvisionings, sloTimeToReady, sloCompliance)
+}
+
+// provisioningStart returns when the request's current provisioning started: when it was
+// created, stopped being Ready, or last re-provisioned for a spec change
+func provisioningStart(ghr *gatewayv1alpha1.GatewayHostnameRequest) time.Time {
+	start := pendingSince(ghr)
+	for _, entry := range ghr.Status.History {
+		if entry.Type == "SpecChanged" && entry.Time.After(start) {
+			start = entry.Time.Time
+		}
+	}
+	return start
+}
+
+// recordTimeToReady records in status.timeToReadySeconds and the time-to-ready histogram how
+// long the request took to become Ready; call it when Ready becomes True
+func recordTimeToReady(ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+	seconds := math.Max(1, math.Ceil(time.Since(provisioningStart(ghr)).Seconds()))
+	ghr.Status.TimeToReadySeconds = int64(seconds)
+	timeToReady.WithLabelValues(ghr.Namespace).Observe(seconds)
+}
+
+// ParseSLOWindows parses a comma-separated list of rolling windows, as Go durations or whole
+// days, e.g. "1d,7d,30d" or "6h,24h"
+func ParseSLOWindows(s string) ([]time.Duration, error) {
+	var windows []time.Duration
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		var d time.Duration
+		var err error
+		if days, ok := strings.CutSuffix(entry, "d"); ok {
+			var n int
+			n, err = strconv.Atoi(days)
+			d = time.Duration(n) * 24 * time.Hour
+		} else {
+			d, err = time.ParseDuration(entry)
+		}
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid SLO window %q, expected a positive duration or number of days", entry)
+		}
+		windows = append(windows, d)
+	}
+	if len(windows) == 0 {
+		return nil, fmt.Errorf("SLO windows %q has no windows", s)
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i] < windows[j] })
+	return windows, nil
+}
+
+// windowName names a window for reports and metric labels: whole days as "7d", others as
+// Go durations
+func windowName(window time.Duration) string {
+	if window%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", window/(24*time.Hour))
+	}
+	return window.String()
+}
+
+// SLOReport is the provisioning SLO compliance of each namespace over rolling windows, as
+// served on /slo and written to the SLO report ConfigMap
+type SLOReport struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+
+	// TimeToReadyObjective is how long a provisioning may take to count as within the objective
+	TimeToReadyObjective string `json:"timeToReadyObjective"`
+	// Objective is the target fraction of provisionings within TimeToReadyObjective
+	Objective float64 `json:"objective"`
+
+	Namespaces []NamespaceSLO `json:"namespaces"`
+}
+
+// NamespaceSLO is the provisioning SLO compliance of one namespace over one window
+type NamespaceSLO struct {
+	Namespace string `json:"namespace"`
+	Window    string `json:"window"`
+
+	// Ready counts the provisionings that completed in the window, ReadyWithinObjective those
+	// of them that took no longer than the time-to-ready objective
+	Ready                int `json:"ready"`
+	ReadyWithinObjective int `json:"readyWithinObjective"`
+	// Failed counts the provisionings seen Failed in the window, and the requests that are
+	// Failed now, which count against every window
+	Failed int `json:"failed"`
+	// Overdue counts the requests still provisioning past the time-to-ready objective
+	Overdue int `json:"overdue"`
+
+	TimeToReadyP50Seconds float64 `json:"timeToReadyP50Seconds,omitempty"`
+	TimeToReadyP95Seconds float64 `json:"timeToReadyP95Seconds,omitempty"`
+
+	// FailureRate is the fraction of the window's provisionings that failed
+	FailureRate float64 `json:"failureRate"`
+	// Compliance is the fraction of the window's provisionings that were Ready within the
+	// time-to-ready objective; 1 without provisionings
+	Compliance float64 `json:"compliance"`
+	// Met is whether Compliance reaches the objective
+	Met bool `json:"met"`
+}
+
+// sloOutcome is a provisioning that completed: it became Ready, or was seen Failed
+type sloOutcome struct {
+	Namespace string `json:"namespace"`
+	// Request is the request's UID, or namespace/name without one
+	Request string `json:"request"`
+	// Outcome is ProvisioningReady or ProvisioningFailed
+	Outcome string `json:"outcome"`
+	// Time is when the request became Ready, or when it was first seen Failed
+	Time time.Time `json:"time"`
+
+	TimeToReadySeconds int64 `json:"timeToReadySeconds,omitempty"`
+}
+
+// sloRequestID identifies a request in the recorded outcomes
+func sloRequestID(ghr *gatewayv1alpha1.GatewayHostnameRequest) string {
+	if ghr.UID != "" {
+		return string(ghr.UID)
+	}
+	return ghr.Namespace + "/" + ghr.Name
+}
+
+// SLOReporter aggregates the time to Ready and failures of the requests per namespace over
+// rolling windows, so the platform team can publish the compliance of each tenant with the
+// provisioning SLO. As a manager Runnable on the leader it exports the report as metrics and,
+// if ConfigMap is set, writes it to that ConfigMap; on every replica it serves it as JSON.
+//
+// Every Collect records the provisionings that completed since the last one: requests that
+// became Ready, with status.timeToReadySeconds, and requests seen Failed. The windows are
+// computed from these outcomes, so deleted requests and earlier provisionings of a request
+// keep counting until they roll out of the longest window. The leader keeps the outcomes in
+// the ConfigMap, if set, and other replicas read them from there; without a ConfigMap they
+// are kept in memory and lost on restart.
+type SLOReporter struct {
+	Client   client.Reader
+	Interval time.Duration
+
+	// Windows are the rolling windows to report (default DefaultSLOWindows)
+	Windows []time.Duration
+	// TimeToReadyObjective is how long a provisioning may take (default DefaultTimeToReadyObjective)
+	TimeToReadyObjective time.Duration
+	// Objective is the target fraction of provisionings within TimeToReadyObjective
+	// (default DefaultProvisioningObjective)
+	Objective float64
+	// FailedThreshold is the number of failed reconciles in a row after which a request that
+	// isn't Ready counts as Failed (default DefaultFailedThreshold)
+	FailedThreshold int32
+
+	// ConfigMap, if set, receives the report under SLOReportKey. It is read with APIReader
+	// and written with Writer, so ConfigMaps aren't cached.
+	ConfigMap types.NamespacedName
+	APIReader client.Reader
+	Writer    client.Writer
+
+	mu sync.Mutex
+	// outcomes are the recorded outcomes, in the order they were seen
+	outcomes []sloOutcome
+	// loaded is set once the outcomes were read from the ConfigMap
+	loaded bool
+	// recording is set on the leader, which owns the outcomes in the ConfigMap
+	recording bool
+}
+
+// Start implements manager.Runnable
+func (s *SLOReporter) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("slo-report")
+	logger.Info("Starting provisioning SLO report", "interval", s.Interval, "configMap", s.ConfigMap.String())
+
+	s.mu.Lock()
+	s.recording = true
+	s.loaded = false
+	s.mu.Unlock()
+
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		if report, err := s.Collect(ctx); err != nil {
+			logger.Error(err, "Provisioning SLO report failed")
+		} else {
+			exportSLOReport(report)
+			if s.ConfigMap.Name != "" {
+				if err := s.writeConfigMap(ctx, report); err != nil {
+					logger.Error(err, "Failed to write provisioning SLO report", "configMap", s.ConfigMap.String())
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// exportSLOReport replaces the SLO metrics with the report's, so namespaces without requests
+// disappear
+func exportSLOReport(report *SLOReport) {
+	sloProvisionings.Reset()
+	sloTimeToReady.Reset()
+	sloCompliance.Reset()
+	for _, slo := range report.Namespaces {
+		for outcome, count := range map[string]int{
+			ProvisioningReady:   slo.Ready,
+			ProvisioningFailed:  slo.Failed,
+			ProvisioningOverdue: slo.Overdue,
+		} {
+			sloProvisionings.WithLabelValues(slo.Namespace, slo.Window, outcome).Set(float64(count))
+		}
+		if slo.Ready > 0 {
+			sloTimeToReady.WithLabelValues(slo.Namespace, slo.Window, "0.5").Set(slo.TimeToReadyP50Seconds)
+			sloTimeToReady.WithLabelValues(slo.Namespace, slo.Window, "0.95").Set(slo.TimeToReadyP95Seconds)
+		}
+		sloCompliance.WithLabelValues(slo.Namespace, slo.Window).Set(slo.Compliance)
+	}
+}
+
+// Collect records the outcomes of the requests' provisionings and computes the report from
+// them
+func (s *SLOReporter) Collect(ctx context.Context) (*SLOReport, error) {
+	windows := s.Windows
+	if len(windows) == 0 {
+		windows, _ = ParseSLOWindows(DefaultSLOWindows)
+	}
+	objectiveTime := s.TimeToReadyObjective
+	if objectiveTime <= 0 {
+		objectiveTime = DefaultTimeToReadyObjective
+	}
+	objective := s.Objective
+	if objective <= 0 {
+		objective = DefaultProvisioningObjective
+	}
+	failedThreshold := s.FailedThreshold
+	if failedThreshold <= 0 {
+		failedThreshold = DefaultFailedThreshold
+	}
+
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := s.Client.List(ctx, &ghrList); err != nil {
+		return nil, fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.loadOutcomes(ctx); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	type key struct {
+		namespace string
+		window    time.Duration
+	}
+	slos := map[key]*NamespaceSLO{}
+	samples := map[key][]float64{}
+	sloFor := func(namespace string, window time.Duration) *NamespaceSLO {
+		k := key{namespace, window}
+		if slos[k] == nil {
+			slos[k] = &NamespaceSLO{Namespace: namespace, Window: windowName(window)}
+		}
+		return slos[k]
+	}
+
+	// Requests that are Failed now count in every window, however long ago they failed
+	failedNow := map[string]bool{}
+	for i := range ghrList.Items {
+		ghr := &ghrList.Items[i]
+		if !ghr.DeletionTimestamp.IsZero() || validateOnly(ghr) {
+			continue
+		}
+		ready := conditions.Get(ghr.Status.Conditions, ConditionTypeReady)
+		isReady := ready != nil && ready.Status == metav1.ConditionTrue
+		failed := !isReady && hostnameHealth(ghr, failedThreshold) == HealthFailed
+		overdue := !isReady && !failed && now.Sub(provisioningStart(ghr)) > objectiveTime
+		switch {
+		case isReady && ghr.Status.TimeToReadySeconds > 0:
+			s.recordReady(sloOutcome{Namespace: ghr.Namespace, Request: sloRequestID(ghr), Outcome: ProvisioningReady,
+				Time: ready.LastTransitionTime.UTC(), TimeToReadySeconds: ghr.Status.TimeToReadySeconds})
+		case failed:
+			failedNow[sloRequestID(ghr)] = true
+			s.recordFailed(sloOutcome{Namespace: ghr.Namespace, Request: sloRequestID(ghr), Outcome: ProvisioningFailed,
+				Time: now.UTC()})
+		}
+		for _, window := range windows {
+			slo := sloFor(ghr.Namespace, window)
+			switch {
+			case failed:
+				slo.Failed++
+			case overdue:
+				slo.Overdue++
+			}
+		}
+	}
+	s.pruneOutcomes(now, windows[len(windows)-1])
+
+	for _, outcome := range s.outcomes {
+		if outcome.Outcome == ProvisioningFailed && failedNow[outcome.Request] {
+			continue
+		}
+		for _, window := range windows {
+			if now.Sub(outcome.Time) > window {
+				continue
+			}
+			slo := sloFor(outcome.Namespace, window)
+			if outcome.Outcome == ProvisioningFailed {
+				slo.Failed++
+				continue
+			}
+			slo.Ready++
+			if time.Duration(outcome.TimeToReadySeconds)*time.Second <= objectiveTime {
+				slo.ReadyWithinObjective++
+			}
+			k := key{outcome.Namespace, window}
+			samples[k] = append(samples[k], float64(outcome.TimeToReadySeconds))
+		}
+	}
+
+	report := &SLOReport{GeneratedAt: now.UTC(), TimeToReadyObjective: objectiveTime.String(), Objective: objective}
+	for k, slo := range slos {
+		slo.TimeToReadyP50Seconds = quantile(samples[k], 0.5)
+		slo.TimeToReadyP95Seconds = quantile(samples[k], 0.95)
+		slo.Compliance = 1
+		if total := slo.Ready + slo.Failed + slo.Overdue; total > 0 {
+			slo.FailureRate = float64(slo.Failed) / float64(total)
+			slo.Compliance = float64(slo.ReadyWithinObjective) / float64(total)
+		}
+		slo.Met = slo.Compliance >= objective
+		report.Namespaces = append(report.Namespaces, *slo)
+	}
+	sort.Slice(report.Namespaces, func(i, j int) bool {
+		a, b := report.Namespaces[i], report.Namespaces[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return windowIndex(windows, a.Window) < windowIndex(windows, b.Window)
+	})
+	return report, nil
+}
+
+// loadOutcomes reads the recorded outcomes from the ConfigMap: once on the leader, which then
+// keeps them up to date, and on every Collect on the other replicas
+func (s *SLOReporter) loadOutcomes(ctx context.Context) error {
+	if s.ConfigMap.Name == "" || (s.recording && s.loaded) {
+		return nil
+	}
+	var cm corev1.ConfigMap
+	err := s.APIReader.Get(ctx, s.ConfigMap, &cm)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to read SLO outcomes from ConfigMap %s: %w", s.ConfigMap, err)
+	}
+	s.outcomes = nil
+	s.loaded = true
+	if data := cm.Data[SLOOutcomesKey]; data != "" {
+		if err := json.Unmarshal([]byte(data), &s.outcomes); err != nil {
+			// Start over rather than never reporting again
+			log.FromContext(ctx).Error(err, "Ignoring unreadable SLO outcomes", "configMap", s.ConfigMap.String())
+			s.outcomes = nil
+		}
+	}
+	return nil
+}
+
+// recordReady records a provisioning that became Ready, unless it was recorded already. It
+// replaces the failures seen during the provisioning, which it recovered from.
+func (s *SLOReporter) recordReady(outcome sloOutcome) {
+	for _, o := range s.outcomes {
+		if o.Request == outcome.Request && o.Outcome == ProvisioningReady && o.Time.Equal(outcome.Time) {
+			return
+		}
+	}
+	start := outcome.Time.Add(-time.Duration(outcome.TimeToReadySeconds) * time.Second)
+	kept := s.outcomes[:0]
+	for _, o := range s.outcomes {
+		if o.Request == outcome.Request && o.Outcome == ProvisioningFailed && !o.Time.Before(start) {
+			continue
+		}
+		kept = append(kept, o)
+	}
+	s.outcomes = append(kept, outcome)
+}
+
+// recordFailed records a provisioning seen Failed, unless the request's last outcome is
+// already a failure
+func (s *SLOReporter) recordFailed(outcome sloOutcome) {
+	for i := len(s.outcomes) - 1; i >= 0; i-- {
+		if s.outcomes[i].Request == outcome.Request {
+			if s.outcomes[i].Outcome == ProvisioningFailed {
+				return
+			}
+			break
+		}
+	}
+	s.outcomes = append(s.outcomes, outcome)
+}
+
+// pruneOutcomes drops the outcomes older than the longest window, and the oldest beyond
+// maxSLOOutcomes
+func (s *SLOReporter) pruneOutcomes(now time.Time, longest time.Duration) {
+	kept := s.outcomes[:0]
+	for _, o := range s.outcomes {
+		if now.Sub(o.Time) <= longest {
+			kept = append(kept, o)
+		}
+	}
+	if overflow := len(kept) - maxSLOOutcomes; overflow > 0 {
+		kept = kept[overflow:]
+	}
+	s.outcomes = kept
+}
+
+// windowIndex returns the position of the named window in windows
+func windowIndex(windows []time.Duration, name string) int {
+	for i, window := range windows {
+		if windowName(window) == name {
+			return i
+		}
+	}
+	return len(windows)
+}
+
+// quantile returns the nearest-rank q-quantile of samples, or 0 without samples
+func quantile(samples []float64, q float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(q*float64(len(sorted)))) - 1
+	return sorted[max(rank, 0)]
+}
+
+// writeConfigMap creates or updates the report ConfigMap with the report and the recorded
+// outcomes
+func (s *SLOReporter) writeConfigMap(ctx context.Context, report *SLOReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	outcomes, err := json.Marshal(s.outcomes)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	var cm corev1.ConfigMap
+	err = s.APIReader.Get(ctx, s.ConfigMap, &cm)
+	if apierrors.IsNotFound(err) {
+		cm = corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      s.ConfigMap.Name,
+				Namespace: s.ConfigMap.Namespace,
+				Labels:    map[string]string{"app.kubernetes.io/managed-by": "gateway-orchestrator"},
+			},
+			Data: map[string]string{SLOReportKey: string(data), SLOOutcomesKey: string(outcomes)},
+		}
+		return s.Writer.Create(ctx, &cm)
+	}
+	if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[SLOReportKey] = string(data)
+	cm.Data[SLOOutcomesKey] = string(outcomes)
+	return s.Writer.Update(ctx, &cm)
+}
+
+// ServeHTTP serves the current SLOReport as JSON
+func (s *SLOReporter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	report, err := s.Collect(req.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+func TestParseSLOWindows(t *testing.T) {
+	windows, err := ParseSLOWindows("7d, 6h,1d")
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{6 * time.Hour, 24 * time.Hour, 7 * 24 * time.Hour}, windows)
+	assert.Equal(t, "7d", windowName(windows[2]))
+	assert.Equal(t, "6h0m0s", windowName(windows[0]))
+
+	for _, s := range []string{"", "0d", "-1h", "week"} {
+		_, err := ParseSLOWindows(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestRecordTimeToReady(t *testing.T) {
+	r := &GatewayHostnameRequestReconciler{}
+	ghr := assignedGHR("app", "app.example.com")
+	ghr.CreationTimestamp = metav1.NewTime(time.Now().Add(-10 * time.Minute))
+
+	r.setCondition(ghr, ConditionTypeReady, metav1.ConditionTrue, conditions.ReasonReady, "Hostname request fully provisioned")
+	assert.InDelta(t, (10 * time.Minute).Seconds(), ghr.Status.TimeToReadySeconds, 5)
+
+	// A spec change restarts the clock
+	ghr.Status.Conditions = nil
+	recordHistory(ghr, "SpecChanged", "", "Reprovisioning", "Spec changed, cleaning up for re-provisioning")
+	r.setCondition(ghr, ConditionTypeReady, metav1.ConditionTrue, conditions.ReasonReady, "Hostname request fully provisioned")
+	assert.Equal(t, int64(1), ghr.Status.TimeToReadySeconds)
+
+	// Staying Ready doesn't record it again
+	ghr.Status.TimeToReadySeconds = 42
+	r.setCondition(ghr, ConditionTypeReady, metav1.ConditionTrue, conditions.ReasonReady, "Hostname request fully provisioned")
+	assert.Equal(t, int64(42), ghr.Status.TimeToReadySeconds)
+}
+
+// sloGHR returns a request in namespace that became Ready readyAgo ago after timeToReady,
+// or isn't Ready if readyAgo is 0
+func sloGHR(namespace, name string, readyAgo, timeToReady time.Duration) *gatewayv1alpha1.GatewayHostnameRequest {
+	ghr := assignedGHR(name, name+".example.com")
+	ghr.Namespace = namespace
+	ghr.CreationTimestamp = metav1.NewTime(time.Now().Add(-readyAgo - timeToReady))
+	if readyAgo > 0 {
+		ghr.Status.Conditions = []metav1.Condition{{
+			Type: ConditionTypeReady, Status: metav1.ConditionTrue, Reason: "Ready",
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-readyAgo)),
+		}}
+		ghr.Status.TimeToReadySeconds = int64(timeToReady.Seconds())
+	}
+	return ghr
+}
+
+func TestSLOReporter_Collect(t *testing.T) {
+	failing := sloGHR("team-a", "failing", 0, time.Minute)
+	failing.Status.ConsecutiveFailures = 7
+	objects := []client.Object{
+		sloGHR("team-a", "fast", time.Hour, 2*time.Minute),
+		sloGHR("team-a", "slow", time.Hour, 30*time.Minute),
+		sloGHR("team-a", "last-week", 3*24*time.Hour, 5*time.Minute),
+		sloGHR("team-a", "overdue", 0, time.Hour),
+		sloGHR("team-a", "pending", 0, time.Minute),
+		failing,
+		sloGHR("team-b", "fast", time.Hour, time.Minute),
+	}
+	s := &SLOReporter{
+		Client:    fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(objects...).Build(),
+		Windows:   []time.Duration{24 * time.Hour, 7 * 24 * time.Hour},
+		Objective: 0.9,
+	}
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slo", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var report SLOReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, "15m0s", report.TimeToReadyObjective)
+	require.Len(t, report.Namespaces, 4)
+
+	day := report.Namespaces[0]
+	assert.Equal(t, "team-a", day.Namespace)
+	assert.Equal(t, "1d", day.Window)
+	assert.Equal(t, 2, day.Ready)
+	assert.Equal(t, 1, day.ReadyWithinObjective)
+	assert.Equal(t, 1, day.Failed)
+	assert.Equal(t, 1, day.Overdue, "the pending request is still within the objective")
+	assert.Equal(t, (2 * time.Minute).Seconds(), day.TimeToReadyP50Seconds)
+	assert.Equal(t, (30 * time.Minute).Seconds(), day.TimeToReadyP95Seconds)
+	assert.InDelta(t, 0.25, day.FailureRate, 0.001)
+	assert.InDelta(t, 0.25, day.Compliance, 0.001)
+	assert.False(t, day.Met)
+
+	week := report.Namespaces[1]
+	assert.Equal(t, "7d", week.Window)
+	assert.Equal(t, 3, week.Ready)
+	assert.Equal(t, 2, week.ReadyWithinObjective)
+
+	other := report.Namespaces[2]
+	assert.Equal(t, "team-b", other.Namespace)
+	assert.Equal(t, 1.0, other.Compliance)
+	assert.True(t, other.Met)
+}
+
+func TestSLOReporter_WriteConfigMap(t *testing.T) {
+	ctx := context.Background()
+	scheme := getTestScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sloGHR("team-a", "fast", time.Hour, time.Minute)).Build()
+	key := types.NamespacedName{Namespace: "gateway-system", Name: "slo-report"}
+	s := &SLOReporter{Client: c, APIReader: c, Writer: c, ConfigMap: key}
+
+	for range 2 {
+		report, err := s.Collect(ctx)
+		require.NoError(t, err)
+		require.NoError(t, s.writeConfigMap(ctx, report))
+	}
+
+	var cm corev1.ConfigMap
+	require.NoError(t, c.Get(ctx, key, &cm))
+	var report SLOReport
+	require.NoError(t, json.Unmarshal([]byte(cm.Data[SLOReportKey]), &report))
+	require.Len(t, report.Namespaces, 3)
+	assert.Equal(t, 1, report.Namespaces[0].Ready)
+}
+
+func TestSLOReporter_WindowsRoll(t *testing.T) {
+	ctx := context.Background()
+	scheme := getTestScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	deleted := sloGHR("team-a", "deleted", time.Hour, time.Minute)
+	failing := sloGHR("team-a", "failing", 0, time.Minute)
+	failing.Status.ConsecutiveFailures = 7
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(deleted, failing).Build()
+	key := types.NamespacedName{Namespace: "gateway-system", Name: "slo-report"}
+	windows := []time.Duration{24 * time.Hour, 7 * 24 * time.Hour}
+	leader := &SLOReporter{Client: c, APIReader: c, Writer: c, ConfigMap: key, Windows: windows, recording: true}
+	// An outcome recorded three days ago has rolled out of the 1d window only
+	leader.loaded = true
+	leader.outcomes = []sloOutcome{{Namespace: "team-a", Request: "old", Outcome: ProvisioningReady,
+		Time: time.Now().Add(-3 * 24 * time.Hour), TimeToReadySeconds: 60}}
+
+	report, err := leader.Collect(ctx)
+	require.NoError(t, err)
+	require.NoError(t, leader.writeConfigMap(ctx, report))
+
+	// The failed request recovers and the Ready one is deleted; both keep counting
+	require.NoError(t, c.Delete(ctx, deleted))
+	failing.Status.ConsecutiveFailures = 0
+	failing.Status.Conditions = []metav1.Condition{{Type: ConditionTypeReady, Status: metav1.ConditionTrue,
+		Reason: "Ready", LastTransitionTime: metav1.Now()}}
+	failing.Status.TimeToReadySeconds = 1800
+	require.NoError(t, c.Update(ctx, failing))
+	report, err = leader.Collect(ctx)
+	require.NoError(t, err)
+	require.NoError(t, leader.writeConfigMap(ctx, report))
+
+	// Another replica reads the leader's outcomes from the ConfigMap
+	replica := &SLOReporter{Client: c, APIReader: c, ConfigMap: key, Windows: windows}
+	for _, s := range []*SLOReporter{leader, replica} {
+		report, err := s.Collect(ctx)
+		require.NoError(t, err)
+		require.Len(t, report.Namespaces, 2)
+		day, week := report.Namespaces[0], report.Namespaces[1]
+		assert.Equal(t, 2, day.Ready, "the deleted request and the recovered one")
+		assert.Equal(t, 1, day.ReadyWithinObjective)
+		assert.Equal(t, 0, day.Failed, "the recovered failure is replaced by its Ready outcome")
+		assert.Equal(t, 3, week.Ready)
+		assert.Equal(t, 2, week.ReadyWithinObjective)
+	}
+}
@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// takeoverRecheckInterval is how often a hostname held for resolving elsewhere is looked up
+// again, so it is provisioned once the external records are removed
+const takeoverRecheckInterval = 10 * time.Minute
+
+// takeoverLookupTimeout bounds the public DNS lookups of a takeover check
+const takeoverLookupTimeout = 10 * time.Second
+
+// DNSResolver resolves hostnames in public DNS; satisfied by *net.Resolver
+type DNSResolver interface {
+	LookupCNAME(ctx context.Context, host string) (string, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// checkTakeover looks the hostname up in public DNS before it is claimed and reports whether
+// the request may go ahead: false if the hostname resolves to addresses no managed load balancer
+// or alias target has and spec.allowTakeover isn't set, in which case Claimed is set False with
+// reason ResolvesElsewhere. Hostnames that don't resolve and wildcards are allowed.
+func (r *GatewayHostnameRequestReconciler) checkTakeover(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (bool, error) {
+	message, err := r.takeoverProblem(ctx, ghr)
+	if err != nil {
+		return false, err
+	}
+	if message == "" {
+		return true, nil
+	}
+	if !conditions.HasReason(ghr.Status.Conditions, ConditionTypeClaimed, metav1.ConditionFalse, conditions.ReasonResolvesElsewhere) {
+		r.Recorder.Event(ghr, corev1.EventTypeWarning, "ResolvesElsewhere", message)
+	}
+	r.setCondition(ghr, ConditionTypeClaimed, metav1.ConditionFalse, conditions.ReasonResolvesElsewhere, message)
+	if err := r.Status().Update(ctx, ghr); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// takeoverProblem returns why claiming the hostname would take it over from somewhere else,
+// or "" if it may be claimed. It only reads, so validate-only requests run it too.
+func (r *GatewayHostnameRequestReconciler) takeoverProblem(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (string, error) {
+	if ghr.Spec.AllowTakeover || strings.HasPrefix(ghr.Spec.Hostname, "*.") {
+		return "", nil
+	}
+	// Hostnames shared with other clusters resolve to their load balancers
+	if ghr.Spec.SharedOwnership != nil {
+		claim, err := r.claimStore().Get(ctx, r.claimName(ghr))
+		if err != nil {
+			return "", err
+		}
+		if claim != nil && claim.Spec.Shared {
+			return "", nil
+		}
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, takeoverLookupTimeout)
+	defer cancel()
+
+	addrs, err := r.TakeoverResolver.LookupHost(lookupCtx, ghr.Spec.Hostname)
+	if isNXDomain(err) || (err == nil && len(addrs) == 0) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up %s in public DNS: %w", ghr.Spec.Hostname, err)
+	}
+
+	targets, err := r.managedAliasTargets(ctx)
+	if err != nil {
+		return "", err
+	}
+	// ALIAS records answer with the target's addresses; CNAMEs name the target itself
+	cname, err := r.TakeoverResolver.LookupCNAME(lookupCtx, ghr.Spec.Hostname)
+	if err == nil && targets[CanonicalHostname(cname)] {
+		return "", nil
+	}
+	managed := map[string]bool{}
+	for target := range targets {
+		targetAddrs, err := r.TakeoverResolver.LookupHost(lookupCtx, target)
+		if err != nil {
+			continue
+		}
+		for _, addr := range targetAddrs {
+			managed[addr] = true
+		}
+	}
+	var external []string
+	for _, addr := range addrs {
+		if !managed[addr] {
+			external = append(external, addr)
+		}
+	}
+	if len(external) == 0 {
+		return "", nil
+	}
+
+	sort.Strings(external)
+	message := fmt.Sprintf("Hostname %s already resolves to %s, which this controller doesn't manage", ghr.Spec.Hostname, strings.Join(external, ", "))
+	if cname != "" && CanonicalHostname(cname) != CanonicalHostname(ghr.Spec.Hostname) {
+		message += fmt.Sprintf(" (through %s)", CanonicalHostname(cname))
+	}
+	return message, nil
+}
+
+// managedAliasTargets returns the DNS names the controller points hostnames at: the load
+// balancers of the Gateway pool and the alias and override targets of requests
+func (r *GatewayHostnameRequestReconciler) managedAliasTargets(ctx context.Context) (map[string]bool, error) {
+	targets := map[string]bool{}
+	if r.GatewayPool != nil {
+		gateways, err := r.GatewayPool.ListGateways(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Gateways: %w", err)
+		}
+		for i := range gateways {
+			if dns := r.GatewayPool.Info(&gateways[i]).LoadBalancerDNS; dns != "" {
+				targets[CanonicalHostname(dns)] = true
+			}
+		}
+	}
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList); err != nil {
+		return nil, fmt.Errorf("failed to list requests: %w", err)
+	}
+	for _, other := range ghrList.Items {
+		if target := publishedAliasTarget(&other); target != "" {
+			targets[CanonicalHostname(target)] = true
+		}
+	}
+	return targets, nil
+}
+
+// isNXDomain reports whether a lookup failed because the name doesn't exist
+func isNXDomain(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+// fakeResolver answers lookups from maps; unknown names don't exist
+type fakeResolver struct {
+	hosts  map[string][]string
+	cnames map[string]string
+}
+
+func (f *fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if addrs, ok := f.hosts[host]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func (f *fakeResolver) LookupCNAME(_ context.Context, host string) (string, error) {
+	if cname, ok := f.cnames[host]; ok {
+		return cname, nil
+	}
+	if _, ok := f.hosts[host]; ok {
+		return host + ".", nil
+	}
+	return "", &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestCheckTakeover(t *testing.T) {
+	ctx := context.Background()
+	gw := gatewayWithLoadBalancer("gw-01", "k8s-edge-gw01-1.eu-west-1.elb.amazonaws.com")
+	resolver := &fakeResolver{
+		hosts: map[string][]string{
+			"k8s-edge-gw01-1.eu-west-1.elb.amazonaws.com": {"192.0.2.10", "192.0.2.11"},
+			"legacy.opendi.com":                           {"198.51.100.7"},
+			"moved.opendi.com":                            {"192.0.2.11"},
+			"www.opendi.com":                              {"198.51.100.8"},
+		},
+		cnames: map[string]string{
+			"www.opendi.com": "old-cdn.example.net.",
+		},
+	}
+	newReq := func(name, hostname string, allowTakeover bool) *gatewayv1alpha1.GatewayHostnameRequest {
+		return &gatewayv1alpha1.GatewayHostnameRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: hostname, ZoneId: "Z1", AllowTakeover: allowTakeover},
+		}
+	}
+	legacy := newReq("legacy", "legacy.opendi.com", false)
+	takeover := newReq("takeover", "legacy.opendi.com", true)
+	moved := newReq("moved", "moved.opendi.com", false)
+	fresh := newReq("fresh", "fresh.opendi.com", false)
+	www := newReq("www", "www.opendi.com", false)
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).
+		WithObjects(gw, legacy, takeover, moved, fresh, www).WithStatusSubresource(legacy, takeover, moved, fresh, www).Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &GatewayHostnameRequestReconciler{
+		Client:           c,
+		Recorder:         recorder,
+		GatewayPool:      gateway.NewPool(c, "edge", "aws-alb", 0, 0),
+		TakeoverResolver: resolver,
+	}
+
+	allowed, err := r.checkTakeover(ctx, legacy)
+	require.NoError(t, err)
+	assert.False(t, allowed, "a hostname served elsewhere is held")
+	assert.True(t, conditions.HasReason(legacy.Status.Conditions, ConditionTypeClaimed, metav1.ConditionFalse, conditions.ReasonResolvesElsewhere))
+	assert.Contains(t, legacy.Status.Message, "198.51.100.7")
+	assert.Contains(t, legacy.Status.Message, "spec.allowTakeover")
+	assert.Len(t, recorder.Events, 1)
+
+	// The warning is only emitted when the request is first held
+	_, err = r.checkTakeover(ctx, legacy)
+	require.NoError(t, err)
+	assert.Len(t, recorder.Events, 1)
+
+	allowed, err = r.checkTakeover(ctx, www)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Contains(t, www.Status.Message, "(through old-cdn.example.net)")
+
+	for _, ghr := range []*gatewayv1alpha1.GatewayHostnameRequest{takeover, moved, fresh} {
+		allowed, err := r.checkTakeover(ctx, ghr)
+		require.NoError(t, err)
+		assert.True(t, allowed, "%s: allowTakeover, records pointing at a managed load balancer and names that don't exist pass", ghr.Name)
+		assert.Empty(t, ghr.Status.Conditions)
+	}
+}
@@ -0,0 +1,333 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+// TargetGroupConfigurationGVK is the GVK for AWS TargetGroupConfiguration
+var TargetGroupConfigurationGVK = schema.GroupVersionKind{
+	Group:   "gateway.k8s.aws",
+	Version: "v1beta1",
+	Kind:    "TargetGroupConfiguration",
+}
+
+// targetGroupConfigurationGVK returns the TargetGroupConfiguration GVK, served in the same
+// version as the discovered LoadBalancerConfiguration
+func (r *GatewayHostnameRequestReconciler) targetGroupConfigurationGVK() schema.GroupVersionKind {
+	gvk := TargetGroupConfigurationGVK
+	gvk.Version = r.loadBalancerConfigurationGVK().Version
+	return gvk
+}
+
+// targetType returns the pool's ALB target type
+func (r *GatewayHostnameRequestReconciler) targetType() string {
+	if r.GatewayPool != nil {
+		return r.GatewayPool.TargetType()
+	}
+	return gateway.TargetTypeIP
+}
+
+// routeAttachesTo reports whether the HTTPRoute references the Gateway as a parent
+func routeAttachesTo(route *gwapiv1.HTTPRoute, gatewayName, gatewayNamespace string) bool {
+	for _, ref := range route.Spec.ParentRefs {
+		if gw, ok := parentGateway(route, ref); ok && gw == (gatewayRef{namespace: gatewayNamespace, name: gatewayName}) {
+			return true
+		}
+	}
+	return false
+}
+
+// routeServesHostname reports whether the HTTPRoute matches the hostname; routes
+// without hostnames match every hostname of the Gateway
+func routeServesHostname(route *gwapiv1.HTTPRoute, hostname string) bool {
+	if len(route.Spec.Hostnames) == 0 {
+		return true
+	}
+	for _, h := range route.Spec.Hostnames {
+		if string(h) == hostname {
+			return true
+		}
+	}
+	return false
+}
+
+// routesForHostname returns the HTTPRoutes in the request namespace that serve the hostname
+// on its assigned Gateway
+func (r *GatewayHostnameRequestReconciler) routesForHostname(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) ([]*gwapiv1.HTTPRoute, error) {
+	if ghr.Status.AssignedGateway == "" {
+		return nil, nil
+	}
+
+	var routes gwapiv1.HTTPRouteList
+	if err := r.List(ctx, &routes, client.InNamespace(ghr.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list HTTPRoutes: %w", err)
+	}
+
+	var serving []*gwapiv1.HTTPRoute
+	for i := range routes.Items {
+		route := &routes.Items[i]
+		if route.DeletionTimestamp.IsZero() &&
+			routeAttachesTo(route, ghr.Status.AssignedGateway, ghr.Status.AssignedGatewayNamespace) &&
+			routeServesHostname(route, ghr.Spec.Hostname) {
+			serving = append(serving, route)
+		}
+	}
+	return serving, nil
+}
+
+// backendServices returns the sorted names of Services in the request namespace that HTTPRoutes
+// use as backends for the hostname on its assigned Gateway
+func (r *GatewayHostnameRequestReconciler) backendServices(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) ([]string, error) {
+	routes, err := r.routesForHostname(ctx, ghr)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var services []string
+	for _, route := range routes {
+		for _, rule := range route.Spec.Rules {
+			for _, backend := range rule.BackendRefs {
+				if backend.Group != nil && *backend.Group != "" {
+					continue
+				}
+				if backend.Kind != nil && *backend.Kind != "Service" {
+					continue
+				}
+				// Services in other namespaces are configured by their owners
+				if backend.Namespace != nil && string(*backend.Namespace) != route.Namespace {
+					continue
+				}
+				name := string(backend.Name)
+				if !seen[name] {
+					seen[name] = true
+					services = append(services, name)
+				}
+			}
+		}
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// listTargetGroupConfigurations returns the TargetGroupConfigurations in a namespace by target Service
+func (r *GatewayHostnameRequestReconciler) listTargetGroupConfigurations(ctx context.Context, namespace string) (map[string]*unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(r.targetGroupConfigurationGVK())
+	if err := r.List(ctx, list, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list TargetGroupConfigurations: %w", err)
+	}
+
+	byService := make(map[string]*unstructured.Unstructured, len(list.Items))
+	for i := range list.Items {
+		tgc := &list.Items[i]
+		service, _, _ := unstructured.NestedString(tgc.Object, "spec", "targetReference", "name")
+		byService[service] = tgc
+	}
+	return byService, nil
+}
+
+// ensureTargetGroupConfigurations renders a TargetGroupConfiguration for every backend Service
+// of the hostname so its target groups use the pool's target type (ip unless configured
+// otherwise) and target group attributes. Services with a TargetGroupConfiguration not managed
+// by the orchestrator are left to their owners.
+func (r *GatewayHostnameRequestReconciler) ensureTargetGroupConfigurations(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	logger := log.FromContext(ctx)
+
+	services, err := r.backendServices(ctx, ghr)
+	if err != nil || len(services) == 0 {
+		return err
+	}
+	existing, err := r.listTargetGroupConfigurations(ctx, ghr.Namespace)
+	if err != nil {
+		return err
+	}
+
+	settings, err := r.backendSettings(ctx, ghr.Namespace)
+	if err != nil {
+		return err
+	}
+
+	var deregistrationDelay *time.Duration
+	if pool := r.settings(); pool != nil {
+		deregistrationDelay = pool.DeregistrationDelay
+	}
+	targetType := r.targetType()
+	for _, service := range services {
+		desired := targetGroupDefaultConfiguration(targetType, deregistrationDelay, settings[service])
+		tgc, ok := existing[service]
+		if ok && tgc.GetLabels()[LabelManagedBy] != "gateway-orchestrator" {
+			logger.V(1).Info("Skipping Service with user-managed TargetGroupConfiguration", "service", service, "name", tgc.GetName())
+			continue
+		}
+		if ok {
+			current, _, _ := unstructured.NestedMap(tgc.Object, "spec", "defaultConfiguration")
+			if reflect.DeepEqual(current, desired) {
+				continue
+			}
+			if err := unstructured.SetNestedMap(tgc.Object, desired, "spec", "defaultConfiguration"); err != nil {
+				return err
+			}
+			if err := r.Update(ctx, tgc); err != nil {
+				return fmt.Errorf("failed to update TargetGroupConfiguration for Service %s: %w", service, err)
+			}
+			logger.Info("Updated TargetGroupConfiguration", "service", service, "targetType", targetType)
+			continue
+		}
+
+		name, err := r.naming().TargetGroupConfigurationName(service, ghr.Namespace)
+		if err != nil {
+			return err
+		}
+		tgc = &unstructured.Unstructured{}
+		tgc.SetGroupVersionKind(r.targetGroupConfigurationGVK())
+		tgc.SetName(name)
+		tgc.SetNamespace(ghr.Namespace)
+		tgc.SetLabels(map[string]string{LabelManagedBy: "gateway-orchestrator"})
+		tgc.Object["spec"] = map[string]interface{}{
+			"targetReference": map[string]interface{}{
+				"name": service,
+			},
+			"defaultConfiguration": desired,
+		}
+		if err := r.Create(ctx, tgc); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				// A TargetGroupConfiguration of that name targets another Service; leave it alone
+				logger.Info("TargetGroupConfiguration name taken, skipping", "service", service)
+				continue
+			}
+			return fmt.Errorf("failed to create TargetGroupConfiguration for Service %s: %w", service, err)
+		}
+		logger.Info("Created TargetGroupConfiguration", "service", service, "targetType", targetType)
+	}
+	return nil
+}
+
+// backendSettings returns the spec.backends of the requests in a namespace by Service. Several
+// requests can route to the same Service; the oldest request setting it wins.
+func (r *GatewayHostnameRequestReconciler) backendSettings(ctx context.Context, namespace string) (map[string]*gatewayv1alpha1.BackendSettings, error) {
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+	requests := ghrList.Items
+	sort.Slice(requests, func(i, j int) bool {
+		if !requests[i].CreationTimestamp.Equal(&requests[j].CreationTimestamp) {
+			return requests[i].CreationTimestamp.Before(&requests[j].CreationTimestamp)
+		}
+		return requests[i].Name < requests[j].Name
+	})
+
+	settings := map[string]*gatewayv1alpha1.BackendSettings{}
+	for i := range requests {
+		if !requests[i].DeletionTimestamp.IsZero() {
+			continue
+		}
+		for j := range requests[i].Spec.Backends {
+			backend := &requests[i].Spec.Backends[j]
+			if _, ok := settings[backend.Service]; !ok {
+				settings[backend.Service] = backend
+			}
+		}
+	}
+	return settings, nil
+}
+
+// targetGroupDefaultConfiguration renders the defaultConfiguration of a TargetGroupConfiguration:
+// the target type, the pool's deregistration delay, and the stickiness and slow start attributes
+// of the backend settings, if any
+func targetGroupDefaultConfiguration(targetType string, deregistrationDelay *time.Duration, backend *gatewayv1alpha1.BackendSettings) map[string]interface{} {
+	config := map[string]interface{}{
+		"targetType": targetType,
+	}
+
+	var attributes []interface{}
+	attribute := func(key, value string) {
+		attributes = append(attributes, map[string]interface{}{"key": key, "value": value})
+	}
+	if deregistrationDelay != nil {
+		attribute("deregistration_delay.timeout_seconds", strconv.Itoa(int(deregistrationDelay.Seconds())))
+	}
+	if backend == nil {
+		backend = &gatewayv1alpha1.BackendSettings{}
+	}
+	// Requests tune their Services on top of the pool's attributes
+	if sticky := backend.Stickiness; sticky != nil {
+		seconds := strconv.Itoa(int(sticky.Duration.Seconds()))
+		attribute("stickiness.enabled", "true")
+		if sticky.CookieName != "" {
+			attribute("stickiness.type", "app_cookie")
+			attribute("stickiness.app_cookie.cookie_name", sticky.CookieName)
+			attribute("stickiness.app_cookie.duration_seconds", seconds)
+		} else {
+			attribute("stickiness.type", "lb_cookie")
+			attribute("stickiness.lb_cookie.duration_seconds", seconds)
+		}
+	}
+	if backend.SlowStartSeconds != nil {
+		attribute("slow_start.duration_seconds", strconv.Itoa(int(*backend.SlowStartSeconds)))
+	}
+	if len(attributes) > 0 {
+		config["targetGroupAttributes"] = attributes
+	}
+	return config
+}
+
+// removeTargetGroupConfigurations deletes the managed TargetGroupConfigurations of the hostname's
+// backend Services that no other request in the namespace still routes to
+func (r *GatewayHostnameRequestReconciler) removeTargetGroupConfigurations(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	services, err := r.backendServices(ctx, ghr)
+	if err != nil || len(services) == 0 {
+		return err
+	}
+
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := r.List(ctx, &ghrList, client.InNamespace(ghr.Namespace)); err != nil {
+		return fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+	inUse := make(map[string]bool)
+	for i := range ghrList.Items {
+		other := &ghrList.Items[i]
+		if other.Name == ghr.Name || !other.DeletionTimestamp.IsZero() {
+			continue
+		}
+		otherServices, err := r.backendServices(ctx, other)
+		if err != nil {
+			return err
+		}
+		for _, service := range otherServices {
+			inUse[service] = true
+		}
+	}
+
+	existing, err := r.listTargetGroupConfigurations(ctx, ghr.Namespace)
+	if err != nil {
+		return err
+	}
+	for _, service := range services {
+		tgc, ok := existing[service]
+		if !ok || inUse[service] || tgc.GetLabels()[LabelManagedBy] != "gateway-orchestrator" {
+			continue
+		}
+		if err := r.Delete(ctx, tgc); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete TargetGroupConfiguration for Service %s: %w", service, err)
+		}
+		log.FromContext(ctx).Info("Deleted TargetGroupConfiguration", "service", service, "namespace", ghr.Namespace)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+func backendRoute(name, hostname string, services ...string) *gwapiv1.HTTPRoute {
+	edge := gwapiv1.Namespace("edge")
+	var refs []gwapiv1.HTTPBackendRef
+	for _, svc := range services {
+		refs = append(refs, gwapiv1.HTTPBackendRef{BackendRef: gwapiv1.BackendRef{
+			BackendObjectReference: gwapiv1.BackendObjectReference{Name: gwapiv1.ObjectName(svc)},
+		}})
+	}
+	return &gwapiv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: gwapiv1.HTTPRouteSpec{
+			CommonRouteSpec: gwapiv1.CommonRouteSpec{
+				ParentRefs: []gwapiv1.ParentReference{{Name: "gw-01", Namespace: &edge}},
+			},
+			Hostnames: []gwapiv1.Hostname{gwapiv1.Hostname(hostname)},
+			Rules:     []gwapiv1.HTTPRouteRule{{BackendRefs: refs}},
+		},
+	}
+}
+
+func getTGC(t *testing.T, r *GatewayHostnameRequestReconciler, name string) (*unstructured.Unstructured, error) {
+	t.Helper()
+	tgc := &unstructured.Unstructured{}
+	tgc.SetGroupVersionKind(TargetGroupConfigurationGVK)
+	err := r.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, tgc)
+	return tgc, err
+}
+
+func TestTargetGroupConfigurations_RenderedForRouteBackends(t *testing.T) {
+	ctx := context.Background()
+	api := assignedGHR("api", "api.example.com")
+	web := assignedGHR("web", "web.example.com")
+
+	userOwned := &unstructured.Unstructured{}
+	userOwned.SetGroupVersionKind(TargetGroupConfigurationGVK)
+	userOwned.SetName("legacy-tgc")
+	userOwned.SetNamespace("default")
+	userOwned.Object["spec"] = map[string]interface{}{
+		"targetReference":      map[string]interface{}{"name": "legacy"},
+		"defaultConfiguration": map[string]interface{}{"targetType": "instance"},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(
+		api, web, userOwned,
+		backendRoute("api", "api.example.com", "api-svc", "shared-svc", "legacy"),
+		backendRoute("web", "web.example.com", "shared-svc"),
+		backendRoute("other", "other.example.com", "other-svc"),
+	).Build()
+	r := &GatewayHostnameRequestReconciler{Client: c}
+
+	require.NoError(t, r.ensureTargetGroupConfigurations(ctx, api))
+	require.NoError(t, r.ensureTargetGroupConfigurations(ctx, web))
+
+	tgc, err := getTGC(t, r, "api-svc")
+	require.NoError(t, err)
+	targetType, _, _ := unstructured.NestedString(tgc.Object, "spec", "defaultConfiguration", "targetType")
+	assert.Equal(t, gateway.TargetTypeIP, targetType)
+	service, _, _ := unstructured.NestedString(tgc.Object, "spec", "targetReference", "name")
+	assert.Equal(t, "api-svc", service)
+
+	// Backends of routes for other hostnames and user-managed configurations are left alone
+	_, err = getTGC(t, r, "other-svc")
+	assert.True(t, apierrors.IsNotFound(err))
+	_, err = getTGC(t, r, "legacy")
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// Deleting api keeps the configuration web still routes to
+	require.NoError(t, r.removeTargetGroupConfigurations(ctx, api))
+	_, err = getTGC(t, r, "api-svc")
+	assert.True(t, apierrors.IsNotFound(err))
+	_, err = getTGC(t, r, "shared-svc")
+	assert.NoError(t, err)
+	_, err = getTGC(t, r, "legacy-tgc")
+	assert.NoError(t, err)
+}
+
+func TestTargetGroupConfigurations_PoolTargetType(t *testing.T) {
+	ctx := context.Background()
+	ghr := assignedGHR("api", "api.example.com")
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(
+		ghr, backendRoute("api", "api.example.com", "api-svc"),
+	).Build()
+
+	pool := gateway.NewPool(c, "edge", "aws-alb", 0, 0)
+	r := &GatewayHostnameRequestReconciler{Client: c, GatewayPool: pool}
+	require.NoError(t, r.ensureTargetGroupConfigurations(ctx, ghr))
+
+	// Switching the pool to instance mode updates the managed configuration
+	require.NoError(t, pool.SetTargetType(gateway.TargetTypeInstance))
+	require.NoError(t, r.ensureTargetGroupConfigurations(ctx, ghr))
+
+	tgc, err := getTGC(t, r, "api-svc")
+	require.NoError(t, err)
+	targetType, _, _ := unstructured.NestedString(tgc.Object, "spec", "defaultConfiguration", "targetType")
+	assert.Equal(t, gateway.TargetTypeInstance, targetType)
+
+	assert.Error(t, pool.SetTargetType("lambda"))
+}
+
+func TestTargetGroupConfigurations_BackendSettings(t *testing.T) {
+	ctx := context.Background()
+	api := assignedGHR("api", "api.example.com")
+	api.CreationTimestamp = metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	api.Spec.Backends = []gatewayv1alpha1.BackendSettings{
+		{Service: "api-svc", Stickiness: &gatewayv1alpha1.Stickiness{Duration: metav1.Duration{Duration: time.Hour}}},
+		{Service: "shared-svc", SlowStartSeconds: ptrTo(int32(60))},
+	}
+	web := assignedGHR("web", "web.example.com")
+	web.CreationTimestamp = metav1.NewTime(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+	web.Spec.Backends = []gatewayv1alpha1.BackendSettings{
+		{Service: "shared-svc", Stickiness: &gatewayv1alpha1.Stickiness{Duration: metav1.Duration{Duration: time.Minute}, CookieName: "SESSION"}},
+	}
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(
+		api, web,
+		backendRoute("api", "api.example.com", "api-svc", "shared-svc"),
+		backendRoute("web", "web.example.com", "shared-svc"),
+	).Build()
+	r := &GatewayHostnameRequestReconciler{Client: c}
+
+	require.NoError(t, r.ensureTargetGroupConfigurations(ctx, web))
+	require.NoError(t, r.ensureTargetGroupConfigurations(ctx, api))
+
+	attributes := func(name string) []interface{} {
+		tgc, err := getTGC(t, r, name)
+		require.NoError(t, err)
+		v, _, _ := unstructured.NestedSlice(tgc.Object, "spec", "defaultConfiguration", "targetGroupAttributes")
+		return v
+	}
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"key": "stickiness.enabled", "value": "true"},
+		map[string]interface{}{"key": "stickiness.type", "value": "lb_cookie"},
+		map[string]interface{}{"key": "stickiness.lb_cookie.duration_seconds", "value": "3600"},
+	}, attributes("api-svc"))
+	// The oldest request configuring a shared Service wins
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"key": "slow_start.duration_seconds", "value": "60"},
+	}, attributes("shared-svc"))
+
+	// Removing the settings drops the attributes again
+	api.Spec.Backends = nil
+	require.NoError(t, c.Update(ctx, api))
+	require.NoError(t, r.ensureTargetGroupConfigurations(ctx, api))
+	assert.Empty(t, attributes("api-svc"))
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"key": "stickiness.enabled", "value": "true"},
+		map[string]interface{}{"key": "stickiness.type", "value": "app_cookie"},
+		map[string]interface{}{"key": "stickiness.app_cookie.cookie_name", "value": "SESSION"},
+		map[string]interface{}{"key": "stickiness.app_cookie.duration_seconds", "value": "60"},
+	}, attributes("shared-svc"))
+}
+
+func TestTargetGroupConfigurations_PoolDeregistrationDelay(t *testing.T) {
+	ctx := context.Background()
+	ghr := assignedGHR("api", "api.example.com")
+	ghr.Spec.Backends = []gatewayv1alpha1.BackendSettings{{Service: "api-svc", SlowStartSeconds: ptrTo(int32(30))}}
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(
+		ghr, backendRoute("api", "api.example.com", "api-svc", "web-svc"),
+	).Build()
+	settings, err := mergeSettings(Settings{}, &gatewayv1alpha1.OrchestratorConfigSpec{Pool: gatewayv1alpha1.OrchestratorConfigPool{
+		TargetGroup: gatewayv1alpha1.OrchestratorConfigTargetGroup{DeregistrationDelay: &metav1.Duration{Duration: 15 * time.Second}},
+	}})
+	require.NoError(t, err)
+	r := &GatewayHostnameRequestReconciler{Client: c, Settings: NewSettingsStore(settings)}
+	require.NoError(t, r.ensureTargetGroupConfigurations(ctx, ghr))
+
+	attributes := func(name string) []interface{} {
+		tgc, err := getTGC(t, r, name)
+		require.NoError(t, err)
+		v, _, _ := unstructured.NestedSlice(tgc.Object, "spec", "defaultConfiguration", "targetGroupAttributes")
+		return v
+	}
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"key": "deregistration_delay.timeout_seconds", "value": "15"},
+		map[string]interface{}{"key": "slow_start.duration_seconds", "value": "30"},
+	}, attributes("api-svc"))
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"key": "deregistration_delay.timeout_seconds", "value": "15"},
+	}, attributes("web-svc"))
+
+	// Dropping the setting restores the AWS default
+	r.Settings.Store(Settings{})
+	require.NoError(t, r.ensureTargetGroupConfigurations(ctx, ghr))
+	assert.Empty(t, attributes("web-svc"))
+
+	_, err = mergeSettings(Settings{}, &gatewayv1alpha1.OrchestratorConfigSpec{Pool: gatewayv1alpha1.OrchestratorConfigPool{
+		TargetGroup: gatewayv1alpha1.OrchestratorConfigTargetGroup{DeregistrationDelay: &metav1.Duration{Duration: 2 * time.Hour}},
+	}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+// teardownRecords deletes the hostname's alias records and its certificate's validation
+// records with one Route53 change batch per zone, and waits until each change is in sync.
+// A zone is either torn down completely or left as it was, so a failed deletion can't leave
+// half the records behind once the finalizer is gone. Returns an error if a zone couldn't be
+// read or changed; the deletion must then be retried before the certificate is deleted.
+func (r *GatewayHostnameRequestReconciler) teardownRecords(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	logger := log.FromContext(ctx)
+
+	byZone := map[string][]aws.DNSRecord{}
+	seen := map[string]bool{}
+	add := func(zoneID string, record aws.DNSRecord) {
+		key := zoneID + "/" + strings.TrimSuffix(record.Name, ".") + "/" + record.Type
+		if !seen[key] {
+			seen[key] = true
+			byZone[zoneID] = append(byZone[zoneID], record)
+		}
+	}
+
+	if ghr.Status.AssignedLoadBalancer != "" {
+		zoneIDs := ghr.Status.AliasZoneIds
+		if len(zoneIDs) == 0 {
+			zoneIDs = aliasZoneIds(ghr)
+		}
+		for _, zoneID := range zoneIDs {
+			for _, recordType := range []string{"A", "AAAA"} {
+				existing, err := r.getRecord(recordSetContext(ctx, ghr), zoneID, ghr.Spec.Hostname, recordType)
+				if err != nil {
+					return fmt.Errorf("failed to look up %s alias record in zone %s: %w", recordType, zoneID, err)
+				}
+				if existing == nil {
+					continue
+				}
+				if existing.AliasTarget == nil {
+					logger.Info("Skipping non-alias record at hostname",
+						"type", recordType,
+						"zoneId", zoneID)
+					continue
+				}
+				add(zoneID, *existing)
+			}
+		}
+	}
+
+	// Another request's certificate covering the hostname still needs them for renewal, as do
+	// the certificates of the hostname's other owners
+	shared, err := r.certificateShared(ctx, ghr, ghr.Status.CertificateArn)
+	if err != nil {
+		return err
+	}
+	if !shared {
+		if shared, err = r.hasCoOwners(ctx, ghr); err != nil {
+			return err
+		}
+	}
+	if ghr.Status.CertificateArn != "" && !shared {
+		awsCtx, cancel := withAWSTimeout(ctx)
+		validationRecords, err := r.ACMClient.GetValidationRecords(awsCtx, ghr.Status.CertificateArn)
+		cancel()
+		if err != nil {
+			// The certificate may be gone already; its records can't be found without it
+			logger.Error(err, "Failed to get validation records, leaving them in place",
+				"arn", ghr.Status.CertificateArn)
+		}
+		zoneID := recordZoneID(ghr)
+		existing, err := r.existingValidationRecords(ctx, zoneID, validationRecords)
+		if err != nil {
+			return err
+		}
+		for _, record := range existing {
+			add(zoneID, record)
+		}
+	}
+
+	zoneIDs := make([]string, 0, len(byZone))
+	for zoneID := range byZone {
+		zoneIDs = append(zoneIDs, zoneID)
+	}
+	sort.Strings(zoneIDs)
+	for _, zoneID := range zoneIDs {
+		records := byZone[zoneID]
+		// Waiting for the change to be in sync takes longer than a single call
+		awsCtx, cancel := context.WithTimeout(ctx, 4*AWSCallTimeout)
+		err := r.Route53Client.DeleteRecords(awsCtx, zoneID, records)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("failed to delete %d records of %s in zone %s: %w", len(records), ghr.Spec.Hostname, zoneID, err)
+		}
+		logger.Info("Deleted DNS records", "zoneId", zoneID, "records", len(records))
+	}
+	return nil
+}
+
+// existingValidationRecords returns the validation records of a certificate that exist in the
+// zone as they are, ready to be deleted
+func (r *GatewayHostnameRequestReconciler) existingValidationRecords(ctx context.Context, zoneID string, validationRecords []aws.ValidationRecord) ([]aws.DNSRecord, error) {
+	var records []aws.DNSRecord
+	for _, vr := range validationRecords {
+		existing, err := r.getRecord(ctx, zoneID, vr.Name, vr.Type)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up validation record %s in zone %s: %w", vr.Name, zoneID, err)
+		}
+		// Deleting needs the exact record; one with another value isn't ours
+		if existing == nil || !strings.EqualFold(strings.TrimSuffix(existing.Value, "."), strings.TrimSuffix(vr.Value, ".")) {
+			continue
+		}
+		records = append(records, *existing)
+	}
+	return records, nil
+}
+
+// getRecord looks up a record with the standard AWS call timeout
+func (r *GatewayHostnameRequestReconciler) getRecord(ctx context.Context, zoneID, name, recordType string) (*aws.DNSRecord, error) {
+	awsCtx, cancel := withAWSTimeout(ctx)
+	defer cancel()
+	return r.Route53Client.GetRecord(awsCtx, zoneID, name, recordType)
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+const teardownCertArn = "arn:aws:acm:us-east-1:123456789012:certificate/test"
+
+func teardownFixture() (*gatewayv1alpha1.GatewayHostnameRequest, *MockRoute53Client, *MockACMClient) {
+	alias := &aws.AliasTarget{DNSName: "k8s-gw01-abcdef1234-1234567890.us-east-1.elb.amazonaws.com", HostedZoneID: "Z35SXDOTRQ7X7K"}
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-request", Namespace: "default", Finalizers: []string{FinalizerName}},
+		Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "app.opendi.com", ZoneId: "ZPRIMARY"},
+		Status: gatewayv1alpha1.GatewayHostnameRequestStatus{
+			AssignedLoadBalancer: alias.DNSName,
+			AliasZoneIds:         []string{"ZPRIMARY", "ZLEGACY"},
+			CertificateArn:       teardownCertArn,
+		},
+	}
+	route53Mock := &MockRoute53Client{records: map[string][]aws.DNSRecord{
+		"ZPRIMARY": {
+			{Name: "app.opendi.com", Type: "A", AliasTarget: alias},
+			{Name: "app.opendi.com", Type: "AAAA", AliasTarget: alias},
+			{Name: "_test.example.com", Type: "CNAME", Value: "_validation.acm.aws.", TTL: 300},
+			{Name: "other.opendi.com", Type: "A", AliasTarget: alias},
+		},
+		"ZLEGACY": {
+			{Name: "app.opendi.com", Type: "A", AliasTarget: alias},
+		},
+	}}
+	acmMock := &MockACMClient{certificates: map[string]string{teardownCertArn: "ISSUED"}}
+	return ghr, route53Mock, acmMock
+}
+
+func TestTeardownRecords_OneBatchPerZone(t *testing.T) {
+	ghr, route53Mock, acmMock := teardownFixture()
+	r := &GatewayHostnameRequestReconciler{Route53Client: route53Mock, ACMClient: acmMock}
+
+	require.NoError(t, r.teardownRecords(context.Background(), ghr))
+
+	assert.Equal(t, []string{"ZLEGACY", "ZPRIMARY"}, route53Mock.batches)
+	assert.Empty(t, route53Mock.records["ZLEGACY"])
+	require.Len(t, route53Mock.records["ZPRIMARY"], 1)
+	assert.Equal(t, "other.opendi.com", route53Mock.records["ZPRIMARY"][0].Name)
+
+	// Nothing left to delete: no change batch is sent
+	route53Mock.batches = nil
+	require.NoError(t, r.teardownRecords(context.Background(), ghr))
+	assert.Empty(t, route53Mock.batches)
+}
+
+func TestReconcileDelete_KeepsCertificateAndFinalizerUntilRecordsAreDeleted(t *testing.T) {
+	ghr, route53Mock, acmMock := teardownFixture()
+	route53Mock.batchDeleteErr = errors.New("throttled")
+	scheme := getTestScheme()
+	r := &GatewayHostnameRequestReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(scheme).WithObjects(ghr).WithStatusSubresource(ghr).Build(),
+		Scheme:        scheme,
+		Recorder:      record.NewFakeRecorder(10),
+		Route53Client: route53Mock,
+		ACMClient:     acmMock,
+	}
+
+	_, err := r.reconcileDelete(context.Background(), ghr)
+	require.Error(t, err)
+	assert.Len(t, route53Mock.records["ZPRIMARY"], 4, "a failed batch leaves the zone as it was")
+	assert.Contains(t, acmMock.certificates, teardownCertArn)
+	assert.Contains(t, ghr.Finalizers, FinalizerName)
+}
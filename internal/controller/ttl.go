@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// expiresAt returns when the request is deleted because of spec.ttl; false if it has no TTL
+func expiresAt(ghr *gatewayv1alpha1.GatewayHostnameRequest) (time.Time, bool) {
+	if ghr.Spec.TTL == nil {
+		return time.Time{}, false
+	}
+	return ghr.CreationTimestamp.Add(ghr.Spec.TTL.Duration), true
+}
+
+// reconcileTTL records the request's expiry in status and deletes the request once its TTL has
+// passed. The finalizer then deprovisions certificate, DNS records and DomainClaim as for any
+// other deletion. Returns true if the request was deleted.
+func (r *GatewayHostnameRequestReconciler) reconcileTTL(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (bool, error) {
+	expiry, ok := expiresAt(ghr)
+	if !ok {
+		ghr.Status.ExpiresAt = nil
+		return false, nil
+	}
+	if time.Now().Before(expiry) {
+		ghr.Status.ExpiresAt = &metav1.Time{Time: expiry}
+		return false, nil
+	}
+
+	log.FromContext(ctx).Info("TTL expired, deleting request", "ttl", ghr.Spec.TTL.Duration)
+	r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "Expired", "TTL of %s expired, deprovisioning hostname", ghr.Spec.TTL.Duration)
+	if err := r.Delete(ctx, ghr); client.IgnoreNotFound(err) != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// requeueForTTL makes sure the request is reconciled again when its TTL expires
+func requeueForTTL(ghr *gatewayv1alpha1.GatewayHostnameRequest, result ctrl.Result) ctrl.Result {
+	expiry, ok := expiresAt(ghr)
+	if !ok || result.Requeue {
+		return result
+	}
+	until := time.Until(expiry)
+	if result.RequeueAfter == 0 || until < result.RequeueAfter {
+		result.RequeueAfter = until
+	}
+	return result
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+func TestReconcileTTL(t *testing.T) {
+	ctx := context.Background()
+	preview := assignedGHR("preview", "pr-123.preview.example.com")
+	preview.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	preview.Finalizers = []string{FinalizerName}
+	preview.Spec.TTL = &metav1.Duration{Duration: 3 * time.Hour}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(preview).Build()
+	r := &GatewayHostnameRequestReconciler{
+		Client:   fakeClient,
+		Recorder: record.NewFakeRecorder(10),
+	}
+
+	// Not expired yet: the expiry is recorded and the request is reconciled again then
+	deleted, err := r.reconcileTTL(ctx, preview)
+	require.NoError(t, err)
+	assert.False(t, deleted)
+	require.NotNil(t, preview.Status.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), preview.Status.ExpiresAt.Time, time.Minute)
+	result := requeueForTTL(preview, ctrl.Result{RequeueAfter: 24 * time.Hour})
+	assert.InDelta(t, time.Hour, result.RequeueAfter, float64(time.Minute))
+	assert.Equal(t, time.Minute, requeueForTTL(preview, ctrl.Result{RequeueAfter: time.Minute}).RequeueAfter)
+
+	// Shortening the TTL expires it; the request is deleted and the finalizer deprovisions it
+	preview.Spec.TTL.Duration = time.Hour
+	deleted, err = r.reconcileTTL(ctx, preview)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	var stored gatewayv1alpha1.GatewayHostnameRequest
+	require.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(preview), &stored))
+	assert.False(t, stored.DeletionTimestamp.IsZero())
+
+	// Removing the TTL clears the expiry
+	preview.Spec.TTL = nil
+	deleted, err = r.reconcileTTL(ctx, preview)
+	require.NoError(t, err)
+	assert.False(t, deleted)
+	assert.Nil(t, preview.Status.ExpiresAt)
+}
+
+func TestReconcileTTL_AlreadyDeleted(t *testing.T) {
+	expired := assignedGHR("expired", "pr-7.preview.example.com")
+	expired.Spec.TTL = &metav1.Duration{Duration: time.Minute}
+
+	r := &GatewayHostnameRequestReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(getTestScheme()).Build(),
+		Recorder: record.NewFakeRecorder(10),
+	}
+	deleted, err := r.reconcileTTL(context.Background(), expired)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+}
@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/policy"
+)
+
+// AnnotationValidateOnly set to "true" on a new request only runs the checks that precede
+// provisioning and reports them in the Validated condition; nothing is claimed or created.
+// Requests that were already provisioned ignore it.
+const AnnotationValidateOnly = "gateway.opendi.com/validate-only"
+
+// validateOnly reports whether the request only asks to be validated
+func validateOnly(ghr *gatewayv1alpha1.GatewayHostnameRequest) bool {
+	return ghr.Annotations[AnnotationValidateOnly] == "true"
+}
+
+// reconcileValidateOnly runs the spec validation, the quarantine, hostname policy and takeover
+// checks and the claim, subtree reservation and hosted zone checks without claiming the hostname
+// or creating anything, and reports the outcome in the Validated condition
+func (r *GatewayHostnameRequestReconciler) reconcileValidateOnly(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) (ctrl.Result, error) {
+	problems, err := r.validationProblems(ctx, ghr)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(problems) > 0 {
+		message := strings.Join(problems, "; ")
+		if !conditions.HasReason(ghr.Status.Conditions, ConditionTypeValidated, metav1.ConditionFalse, conditions.ReasonValidationFailed) {
+			r.Recorder.Eventf(ghr, corev1.EventTypeWarning, "ValidationFailed", "Validation failed: %s", message)
+		}
+		r.setCondition(ghr, ConditionTypeValidated, metav1.ConditionFalse, conditions.ReasonValidationFailed, message)
+	} else {
+		r.setCondition(ghr, ConditionTypeValidated, metav1.ConditionTrue, conditions.ReasonValid, "The hostname can be provisioned")
+	}
+	r.setCondition(ghr, ConditionTypeReady, metav1.ConditionFalse, conditions.ReasonValidateOnly,
+		fmt.Sprintf("Nothing is provisioned while %s is set", AnnotationValidateOnly))
+	ghr.Status.ObservedGeneration = ghr.Generation
+	return ctrl.Result{}, r.Status().Update(ctx, ghr)
+}
+
+// validationProblems returns why the request can't be provisioned: an invalid spec, a
+// quarantined hostname, a policy denial, a hostname live elsewhere, claimed by another request
+// or reserved for another namespace, or a hosted zone that doesn't exist or doesn't contain the
+// hostname. Unlike the provisioning steps, it records nothing on the request.
+func (r *GatewayHostnameRequestReconciler) validationProblems(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) ([]string, error) {
+	if err := r.validateRequest(ghr); err != nil {
+		return []string{err.Error()}, nil
+	}
+
+	var problems []string
+	if r.Quarantine != nil && ghr.Annotations[AnnotationApproveQuarantine] != ghr.Spec.Hostname {
+		if _, message, suspicious := r.Quarantine.Match(ghr.Spec.Hostname); suspicious {
+			problems = append(problems, fmt.Sprintf("%s; it would be held until annotated with %s=%s",
+				message, AnnotationApproveQuarantine, ghr.Spec.Hostname))
+		}
+	}
+	if r.Policy != nil {
+		decision, err := r.Policy.Check(ctx, policy.NewReview(ghr))
+		switch {
+		case err != nil && !r.PolicyFailOpen:
+			problems = append(problems, fmt.Sprintf("hostname policy check failed: %v", err))
+		case err == nil && !decision.Allowed:
+			message := "denied by hostname policy"
+			if decision.Reason != "" {
+				message += ": " + decision.Reason
+			}
+			problems = append(problems, message)
+		}
+	}
+	if r.TakeoverResolver != nil {
+		message, err := r.takeoverProblem(ctx, ghr)
+		if err != nil {
+			problems = append(problems, err.Error())
+		} else if message != "" {
+			problems = append(problems, strings.ToLower(message[:1])+message[1:]+"; set spec.allowTakeover to take it over")
+		}
+	}
+
+	subtree, err := r.subtreeClaim(ctx, ghr)
+	if err != nil {
+		return nil, err
+	}
+	if subtree != nil && subtree.Spec.OwnerRef.Namespace != ghr.Namespace {
+		problems = append(problems, fmt.Sprintf("hostname is in %s, reserved for namespace %s by DomainClaim %s",
+			subtree.Spec.Hostname, subtree.Spec.OwnerRef.Namespace, subtree.Name))
+	}
+
+	claim, err := r.claimStore().Get(ctx, r.claimName(ghr))
+	if err != nil {
+		return nil, err
+	}
+	if claim == nil || ownsClaim(claim, ghr) {
+		if claim, err = r.conflictingClaim(ctx, ghr); err != nil {
+			return nil, err
+		}
+	}
+	if claim != nil && !ownsClaim(claim, ghr) && (claim.Spec.Shared || ghr.Spec.SharedOwnership != nil) {
+		// Shared claims take another owner unless it conflicts with the current ones
+		if reason, message := sharedClaimConflict(claim, ghr); reason != "" {
+			problems = append(problems, strings.ToLower(message[:1])+message[1:])
+		}
+	} else if claim != nil && !ownsClaim(claim, ghr) {
+		problems = append(problems, fmt.Sprintf("hostname is already claimed by %s/%s",
+			claim.Spec.OwnerRef.Namespace, claim.Spec.OwnerRef.Name))
+	}
+
+	hostname := CanonicalHostname(ghr.Spec.Hostname)
+	for _, zoneID := range aliasZoneIds(ghr) {
+		awsCtx, cancel := withAWSTimeout(ctx)
+		zone, err := r.Route53Client.GetHostedZone(awsCtx, zoneID)
+		cancel()
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("hosted zone %s: %v", zoneID, err))
+			continue
+		}
+		zoneName := CanonicalHostname(zone.Name)
+		if hostname != zoneName && !strings.HasSuffix(hostname, "."+zoneName) {
+			problems = append(problems, fmt.Sprintf("hostname is not in hosted zone %s (%s)", zoneID, zoneName))
+		}
+	}
+	return problems, nil
+}
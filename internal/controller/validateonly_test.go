@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/policy"
+)
+
+func TestReconcile_ValidateOnly(t *testing.T) {
+	ctx := context.Background()
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "app",
+			Namespace:   "team-a",
+			UID:         "uid-app",
+			Annotations: map[string]string{AnnotationValidateOnly: "true"},
+		},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "app.example.com", ZoneId: "Z123"},
+	}
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr).WithStatusSubresource(ghr).Build()
+	route53Mock := &MockRoute53Client{zones: map[string]*aws.HostedZone{"example.com": {ID: "Z123", Name: "example.com."}}}
+	r := &GatewayHostnameRequestReconciler{Client: c, Recorder: record.NewFakeRecorder(10), Route53Client: route53Mock}
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(ghr)}
+
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, req.NamespacedName, ghr))
+	assert.True(t, conditions.HasReason(ghr.Status.Conditions, ConditionTypeValidated, metav1.ConditionTrue, conditions.ReasonValid))
+	assert.True(t, conditions.HasReason(ghr.Status.Conditions, ConditionTypeReady, metav1.ConditionFalse, conditions.ReasonValidateOnly))
+	assert.Empty(t, ghr.Finalizers, "nothing is held")
+	var claims gatewayv1alpha1.DomainClaimList
+	require.NoError(t, c.List(ctx, &claims))
+	assert.Empty(t, claims.Items, "the hostname is not claimed")
+
+	// Another request holds the claim, and the additional zone doesn't contain the hostname
+	require.NoError(t, c.Create(ctx, &gatewayv1alpha1.DomainClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: r.claimName(ghr)},
+		Spec: gatewayv1alpha1.DomainClaimSpec{
+			ZoneId:   "Z123",
+			Hostname: "app.example.com",
+			OwnerRef: gatewayv1alpha1.DomainClaimOwnerRef{Namespace: "team-b", Name: "app", UID: "uid-other"},
+		},
+	}))
+	route53Mock.zones["example.org"] = &aws.HostedZone{ID: "Z456", Name: "example.org."}
+	ghr.Spec.AdditionalZoneIds = []string{"Z456"}
+	require.NoError(t, c.Update(ctx, ghr))
+
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(ctx, req.NamespacedName, ghr))
+	cond := conditions.Get(ghr.Status.Conditions, ConditionTypeValidated)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, "hostname is already claimed by team-b/app; hostname is not in hosted zone Z456 (example.org)", cond.Message)
+	assert.Equal(t, "The request is invalid: "+cond.Message, ghr.Status.Message)
+	assert.Empty(t, ghr.Finalizers)
+}
+
+func TestValidationProblems_QuarantinePolicyAndTakeover(t *testing.T) {
+	ctx := context.Background()
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "shop",
+			Namespace:   "team-a",
+			Annotations: map[string]string{AnnotationValidateOnly: "true"},
+		},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "paypal.example.com", ZoneId: "Z123"},
+	}
+	c := fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr).WithStatusSubresource(ghr).Build()
+	quarantine, err := NewQuarantine("paypa[l1]", "", false)
+	require.NoError(t, err)
+	p := &staticPolicy{decision: policy.Decision{
+		Reason:      "paypal is a protected trademark",
+		Annotations: map[string]string{"policy.example.com/ticket": "LEGAL-42"},
+	}}
+	r := &GatewayHostnameRequestReconciler{
+		Client:           c,
+		Recorder:         record.NewFakeRecorder(10),
+		Route53Client:    &MockRoute53Client{zones: map[string]*aws.HostedZone{"example.com": {ID: "Z123", Name: "example.com."}}},
+		Quarantine:       quarantine,
+		Policy:           p,
+		TakeoverResolver: &fakeResolver{hosts: map[string][]string{"paypal.example.com": {"198.51.100.7"}}},
+	}
+
+	problems, err := r.validationProblems(ctx, ghr)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`hostname matches quarantine pattern "paypa[l1]"; it would be held until annotated with ` + AnnotationApproveQuarantine + `=paypal.example.com`,
+		"denied by hostname policy: paypal is a protected trademark",
+		"hostname paypal.example.com already resolves to 198.51.100.7, which this controller doesn't manage; set spec.allowTakeover to take it over",
+	}, problems)
+	assert.Len(t, p.reviews, 1)
+
+	var stored gatewayv1alpha1.GatewayHostnameRequest
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(ghr), &stored))
+	assert.NotContains(t, stored.Annotations, "policy.example.com/ticket", "policy annotations aren't added")
+	assert.Empty(t, stored.Status.Conditions, "no condition is set")
+	assert.Empty(t, r.Recorder.(*record.FakeRecorder).Events, "no event is recorded")
+
+	ghr.Annotations[AnnotationApproveQuarantine] = "paypal.example.com"
+	ghr.Spec.AllowTakeover = true
+	p.decision.Allowed = true
+	problems, err = r.validationProblems(ctx, ghr)
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+}
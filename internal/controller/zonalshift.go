@@ -0,0 +1,261 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+// Annotations tracking the zonal shift of a Gateway's ALB
+const (
+	// AnnotationZonalShiftAwayFrom is the availability zone (AZ ID) the ALB's traffic is shifted
+	// away from
+	AnnotationZonalShiftAwayFrom = "gateway.opendi.com/zonal-shift-away-from"
+
+	// AnnotationZonalShiftID is the ID of the ARC zonal shift
+	AnnotationZonalShiftID = "gateway.opendi.com/zonal-shift-id"
+
+	// AnnotationZonalShiftExpires is when ARC ends the shift (RFC 3339)
+	AnnotationZonalShiftExpires = "gateway.opendi.com/zonal-shift-expires"
+)
+
+// Admin operations on availability zones, which act on the ALBs of all pool Gateways
+const (
+	// OperationEvacuateZone shifts the traffic of every ALB away from ?zone= for ?duration=
+	// (default ZonalShifts.Duration)
+	OperationEvacuateZone = "evacuate-zone"
+
+	// OperationRestoreZone ends the zonal shifts away from ?zone= before they expire
+	OperationRestoreZone = "restore-zone"
+)
+
+// maxZonalShiftDuration is the longest zonal shift ARC accepts
+const maxZonalShiftDuration = 72 * time.Hour
+
+// DefaultZonalShiftCheckInterval is how often expired zonal shifts are cleared from Gateways
+const DefaultZonalShiftCheckInterval = time.Minute
+
+// availabilityZoneID matches AZ IDs such as use1-az1, which name the same zone in every account
+var availabilityZoneID = regexp.MustCompile(`^[a-z]+[0-9]+-az[0-9]+$`)
+
+// ZonalShifts evacuates an availability zone for all ALBs of the pool through ARC zonal
+// shift, on request of the admin API. The shifts are tracked in Gateway annotations. ARC
+// reverts them after their duration; as a manager Runnable, ZonalShifts then clears the
+// annotations.
+type ZonalShifts struct {
+	Client        client.Client
+	GatewayPool   *gateway.Pool
+	LoadBalancers aws.LoadBalancerClient
+	Shifts        aws.ZonalShiftClient
+	Recorder      record.EventRecorder
+
+	// Duration is how long an evacuation lasts unless the operation sets one
+	Duration time.Duration
+
+	// Interval defaults to DefaultZonalShiftCheckInterval
+	Interval time.Duration
+}
+
+// Start implements manager.Runnable
+func (z *ZonalShifts) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("zonal-shifts")
+	interval := z.Interval
+	if interval <= 0 {
+		interval = DefaultZonalShiftCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := z.ClearExpired(ctx, time.Now()); err != nil {
+			logger.Error(err, "Clearing expired zonal shifts failed")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// validateZone checks that the zone is an AZ ID; ARC doesn't take zone names like us-east-1a
+func validateZone(zone string) error {
+	if !availabilityZoneID.MatchString(zone) {
+		return fmt.Errorf("zone must be an availability zone ID such as use1-az1, got %q", zone)
+	}
+	return nil
+}
+
+// Evacuate shifts the traffic of the ALB of every pool Gateway away from the zone. Gateways
+// already shifted away from it are left alone; a Gateway without ALB or shifted away from
+// another zone fails without stopping the others.
+func (z *ZonalShifts) Evacuate(ctx context.Context, zone string, duration time.Duration) (*AdminResult, error) {
+	if err := validateZone(zone); err != nil {
+		return nil, err
+	}
+	if duration == 0 {
+		duration = z.Duration
+	}
+	if duration < time.Minute || duration > maxZonalShiftDuration {
+		return nil, fmt.Errorf("duration must be between 1m and %s, got %s", maxZonalShiftDuration, duration)
+	}
+	duration = duration.Truncate(time.Minute)
+
+	gateways, err := z.GatewayPool.ListGateways(ctx)
+	if err != nil {
+		return nil, err
+	}
+	loadBalancers, err := z.LoadBalancers.DescribeLoadBalancers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byDNSName := make(map[string]aws.LoadBalancer, len(loadBalancers))
+	for _, lb := range loadBalancers {
+		byDNSName[strings.ToLower(lb.DNSName)] = lb
+	}
+
+	result := &AdminResult{Operation: OperationEvacuateZone}
+	expires := time.Now().Add(duration).UTC().Truncate(time.Second)
+	failed := 0
+	for i := range gateways {
+		gw := &gateways[i]
+		if !gw.DeletionTimestamp.IsZero() || gw.Annotations[AnnotationZonalShiftAwayFrom] == zone {
+			continue
+		}
+		object := "Gateway/" + gw.Namespace + "/" + gw.Name
+		shiftID, err := z.evacuateGateway(ctx, gw, zone, duration, expires, byDNSName)
+		if err != nil {
+			failed++
+			log.FromContext(ctx).Info("Zonal shift failed for Gateway", "gateway", gw.Namespace+"/"+gw.Name, "zone", zone, "error", err.Error())
+			result.Items = append(result.Items, AdminItem{Object: object, Status: AdminItemFailed, Message: err.Error()})
+			continue
+		}
+		result.Items = append(result.Items, AdminItem{Object: object, Status: AdminItemChanged, Message: "Zonal shift " + shiftID})
+		result.Touched = append(result.Touched, object)
+	}
+	result.Message = fmt.Sprintf("%d ALBs shifted away from %s until %s", len(result.Items)-failed, zone, expires.Format(time.RFC3339))
+	if failed > 0 {
+		result.Message += fmt.Sprintf(", %d failed", failed)
+	}
+	return result, nil
+}
+
+// evacuateGateway starts the zonal shift of the Gateway's ALB and records it on the Gateway
+func (z *ZonalShifts) evacuateGateway(ctx context.Context, gw *gwapiv1.Gateway, zone string, duration time.Duration, expires time.Time, byDNSName map[string]aws.LoadBalancer) (string, error) {
+	if other := gw.Annotations[AnnotationZonalShiftAwayFrom]; other != "" {
+		return "", fmt.Errorf("already shifted away from %s; restore it first", other)
+	}
+	dnsName := z.GatewayPool.Info(gw).LoadBalancerDNS
+	lb, ok := byDNSName[strings.ToLower(dnsName)]
+	if dnsName == "" || !ok {
+		return "", fmt.Errorf("no load balancer found")
+	}
+
+	shiftID, err := z.Shifts.StartZonalShift(ctx, lb.Arn, zone, duration, "gateway-orchestrator evacuation of "+zone)
+	if err != nil {
+		return "", err
+	}
+	patch := client.MergeFrom(gw.DeepCopy())
+	if gw.Annotations == nil {
+		gw.Annotations = map[string]string{}
+	}
+	gw.Annotations[AnnotationZonalShiftAwayFrom] = zone
+	gw.Annotations[AnnotationZonalShiftID] = shiftID
+	gw.Annotations[AnnotationZonalShiftExpires] = expires.Format(time.RFC3339)
+	if err := z.Client.Patch(ctx, gw, patch); err != nil {
+		return "", fmt.Errorf("zonal shift %s started, but annotating the Gateway failed: %w", shiftID, err)
+	}
+	z.Recorder.Eventf(gw, corev1.EventTypeWarning, "ZoneEvacuated", "Traffic of the ALB shifted away from %s until %s (zonal shift %s)",
+		zone, expires.Format(time.RFC3339), shiftID)
+	return shiftID, nil
+}
+
+// Restore cancels the zonal shifts away from the zone and clears them from the Gateways
+func (z *ZonalShifts) Restore(ctx context.Context, zone string) (*AdminResult, error) {
+	if err := validateZone(zone); err != nil {
+		return nil, err
+	}
+	gateways, err := z.GatewayPool.ListGateways(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AdminResult{Operation: OperationRestoreZone}
+	failed := 0
+	for i := range gateways {
+		gw := &gateways[i]
+		if gw.Annotations[AnnotationZonalShiftAwayFrom] != zone {
+			continue
+		}
+		object := "Gateway/" + gw.Namespace + "/" + gw.Name
+		shiftID := gw.Annotations[AnnotationZonalShiftID]
+		// A shift that already ended only leaves the annotations behind
+		if err := z.Shifts.CancelZonalShift(ctx, shiftID); err != nil && !aws.IsNotFound(err) {
+			failed++
+			result.Items = append(result.Items, AdminItem{Object: object, Status: AdminItemFailed, Message: err.Error()})
+			continue
+		}
+		if err := z.clearShift(ctx, gw, "Zonal shift %s away from %s cancelled"); err != nil {
+			failed++
+			result.Items = append(result.Items, AdminItem{Object: object, Status: AdminItemFailed, Message: err.Error()})
+			continue
+		}
+		result.Items = append(result.Items, AdminItem{Object: object, Status: AdminItemChanged, Message: "Cancelled zonal shift " + shiftID})
+		result.Touched = append(result.Touched, object)
+	}
+	result.Message = fmt.Sprintf("%d ALBs serve %s again", len(result.Items)-failed, zone)
+	if failed > 0 {
+		result.Message += fmt.Sprintf(", %d failed", failed)
+	}
+	return result, nil
+}
+
+// ClearExpired removes the zonal shifts that ARC has ended by now from the Gateways
+func (z *ZonalShifts) ClearExpired(ctx context.Context, now time.Time) error {
+	gateways, err := z.GatewayPool.ListGateways(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range gateways {
+		gw := &gateways[i]
+		if gw.Annotations[AnnotationZonalShiftAwayFrom] == "" {
+			continue
+		}
+		// Unreadable expiries are cleared too, as ARC ends every shift within three days
+		expires, err := time.Parse(time.RFC3339, gw.Annotations[AnnotationZonalShiftExpires])
+		if err == nil && now.Before(expires) {
+			continue
+		}
+		log.FromContext(ctx).Info("Zonal shift expired", "gateway", gw.Namespace+"/"+gw.Name, "zone", gw.Annotations[AnnotationZonalShiftAwayFrom])
+		if err := z.clearShift(ctx, gw, "Zonal shift %s away from %s expired"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clearShift removes the zonal shift annotations from the Gateway and records an event with
+// the message, which takes the shift ID and the zone
+func (z *ZonalShifts) clearShift(ctx context.Context, gw *gwapiv1.Gateway, message string) error {
+	shiftID, zone := gw.Annotations[AnnotationZonalShiftID], gw.Annotations[AnnotationZonalShiftAwayFrom]
+	patch := client.MergeFrom(gw.DeepCopy())
+	delete(gw.Annotations, AnnotationZonalShiftAwayFrom)
+	delete(gw.Annotations, AnnotationZonalShiftID)
+	delete(gw.Annotations, AnnotationZonalShiftExpires)
+	if err := z.Client.Patch(ctx, gw, patch); err != nil {
+		return fmt.Errorf("failed to clear the zonal shift of Gateway %s: %w", gw.Name, err)
+	}
+	z.Recorder.Eventf(gw, corev1.EventTypeNormal, "ZoneRestored", message, shiftID, zone)
+	return nil
+}
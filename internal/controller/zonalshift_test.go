@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+// zonalShiftGateway returns a pool Gateway whose ALB has the DNS name
+func zonalShiftGateway(name, dnsName string) *gwapiv1.Gateway {
+	hostnameType := gwapiv1.HostnameAddressType
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "edge", Annotations: map[string]string{AnnotationVisibility: "internet-facing"}},
+		Spec:       gwapiv1.GatewaySpec{GatewayClassName: "aws-alb"},
+	}
+	if dnsName != "" {
+		gw.Status.Addresses = []gwapiv1.GatewayStatusAddress{{Type: &hostnameType, Value: dnsName}}
+	}
+	return gw
+}
+
+func newTestZonalShifts(a *Admin) *aws.MockZonalShiftClient {
+	shifts := &aws.MockZonalShiftClient{}
+	a.ZonalShifts = &ZonalShifts{
+		Client:      a.Reconciler.Client,
+		GatewayPool: a.Reconciler.GatewayPool,
+		LoadBalancers: &aws.MockLoadBalancerClient{LoadBalancers: []aws.LoadBalancer{
+			{Arn: "arn:alb/gw-01", DNSName: "gw-01.elb.amazonaws.com"},
+			{Arn: "arn:alb/gw-02", DNSName: "gw-02.elb.amazonaws.com"},
+		}},
+		Shifts:   shifts,
+		Recorder: record.NewFakeRecorder(10),
+		Duration: time.Hour,
+	}
+	return shifts
+}
+
+func TestZonalShifts_EvacuateAndRestore(t *testing.T) {
+	ctx := context.Background()
+	a := newTestAdmin(zonalShiftGateway("gw-01", "gw-01.elb.amazonaws.com"), zonalShiftGateway("gw-02", "GW-02.elb.amazonaws.com"),
+		zonalShiftGateway("gw-03", ""))
+	shifts := newTestZonalShifts(a)
+
+	rec, result := callAdmin(a, http.MethodPost, AdminPath+OperationEvacuateZone+"?zone=euw1-az1&duration=90m")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, []string{"Gateway/edge/gw-01", "Gateway/edge/gw-02"}, result.Touched)
+	require.Len(t, result.Items, 3)
+	assert.Equal(t, AdminItemFailed, result.Items[2].Status, "gw-03 has no ALB yet")
+	require.Len(t, shifts.Shifts, 2)
+	for _, shift := range shifts.Shifts {
+		assert.Equal(t, "euw1-az1", shift.AwayFrom)
+		assert.Equal(t, 90*time.Minute, shift.ExpiresIn)
+	}
+
+	var gw gwapiv1.Gateway
+	require.NoError(t, a.Reconciler.Get(ctx, client.ObjectKey{Namespace: "edge", Name: "gw-01"}, &gw))
+	assert.Equal(t, "euw1-az1", gw.Annotations[AnnotationZonalShiftAwayFrom])
+	assert.NotEmpty(t, gw.Annotations[AnnotationZonalShiftID])
+	expires, err := time.Parse(time.RFC3339, gw.Annotations[AnnotationZonalShiftExpires])
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(90*time.Minute), expires, time.Minute)
+
+	// Evacuating again leaves the shifted ALBs alone
+	_, result = callAdmin(a, http.MethodPost, AdminPath+OperationEvacuateZone+"?zone=euw1-az1")
+	assert.Empty(t, result.Touched)
+	assert.Len(t, shifts.Shifts, 2)
+
+	rec, result = callAdmin(a, http.MethodPost, AdminPath+OperationRestoreZone+"?zone=euw1-az1")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Len(t, result.Touched, 2)
+	assert.Empty(t, shifts.Shifts)
+	require.NoError(t, a.Reconciler.Get(ctx, client.ObjectKey{Namespace: "edge", Name: "gw-01"}, &gw))
+	assert.NotContains(t, gw.Annotations, AnnotationZonalShiftAwayFrom)
+}
+
+func TestZonalShifts_ClearExpired(t *testing.T) {
+	ctx := context.Background()
+	gw := zonalShiftGateway("gw-01", "gw-01.elb.amazonaws.com")
+	gw.Annotations[AnnotationZonalShiftAwayFrom] = "euw1-az1"
+	gw.Annotations[AnnotationZonalShiftID] = "shift-1"
+	gw.Annotations[AnnotationZonalShiftExpires] = "2026-03-01T12:00:00Z"
+	a := newTestAdmin(gw)
+	newTestZonalShifts(a)
+
+	require.NoError(t, a.ZonalShifts.ClearExpired(ctx, time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC)))
+	var updated gwapiv1.Gateway
+	require.NoError(t, a.Reconciler.Get(ctx, client.ObjectKeyFromObject(gw), &updated))
+	assert.Equal(t, "euw1-az1", updated.Annotations[AnnotationZonalShiftAwayFrom], "still active")
+
+	require.NoError(t, a.ZonalShifts.ClearExpired(ctx, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)))
+	require.NoError(t, a.Reconciler.Get(ctx, client.ObjectKeyFromObject(gw), &updated))
+	assert.NotContains(t, updated.Annotations, AnnotationZonalShiftAwayFrom)
+	assert.NotContains(t, updated.Annotations, AnnotationZonalShiftExpires)
+}
+
+func TestAdmin_ZoneOperationsValidate(t *testing.T) {
+	a := newTestAdmin()
+	rec, _ := callAdmin(a, http.MethodPost, AdminPath+OperationEvacuateZone+"?zone=euw1-az1")
+	assert.Equal(t, http.StatusNotFound, rec.Code, "not enabled")
+
+	newTestZonalShifts(a)
+	rec, _ = callAdmin(a, http.MethodPost, AdminPath+OperationEvacuateZone+"?zone=eu-west-1a")
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "zone names differ between accounts")
+	rec, _ = callAdmin(a, http.MethodPost, AdminPath+OperationEvacuateZone+"?zone=euw1-az1&duration=96h")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestEnsureLoadBalancerConfiguration_ZonalShift(t *testing.T) {
+	ctx := context.Background()
+	a := newTestAdmin()
+	r := a.Reconciler
+	r.ZonalShift = true
+	require.NoError(t, r.ensureLoadBalancerConfiguration(ctx, "gw-01", "edge", nil, "internet-facing", ""))
+
+	lbc := r.newLoadBalancerConfiguration()
+	require.NoError(t, r.Get(ctx, client.ObjectKey{Namespace: "edge", Name: "gw-01-config"}, lbc))
+	attributes, _, _ := unstructured.NestedSlice(lbc.Object, "spec", "loadBalancerAttributes")
+	assert.Equal(t, []interface{}{map[string]interface{}{"key": "zonal_shift.config.enabled", "value": "true"}}, attributes)
+}
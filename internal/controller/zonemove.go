@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// zoneMoveSource returns the hosted zone the records have to be moved out of when spec.zoneId is
+// the only change since the last provisioning, or "" otherwise. Before the move starts that is
+// the first zone the ALIAS records are published in, which is spec.zoneId at the time.
+func zoneMoveSource(ghr *gatewayv1alpha1.GatewayHostnameRequest) string {
+	from := ghr.Status.MigratingFromZoneId
+	if from == "" && ghr.Spec.DelegatedZone == "" && len(ghr.Status.AliasZoneIds) > 0 {
+		from = ghr.Status.AliasZoneIds[0]
+	}
+	if from == "" || from == ghr.Spec.ZoneId || ghr.Status.ObservedSpecHash == "" {
+		return ""
+	}
+	spec := ghr.Spec
+	spec.ZoneId = from
+	if computeSpecHash(&spec) != ghr.Status.ObservedSpecHash {
+		return ""
+	}
+	return from
+}
+
+// moveZone moves the hostname's records from the zone from to spec.zoneId, make-before-break:
+// the new zone is claimed (which releases the old claim) and gets the certificate's validation
+// records and the ALIAS records before they are deleted from the old zone. The certificate and the
+// Gateway assignment are kept. Until the move finished, status.migratingFromZoneId remembers
+// the old zone, so a failed step is retried on the next reconcile.
+func (r *GatewayHostnameRequestReconciler) moveZone(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, from string) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("fromZoneId", from, "toZoneId", ghr.Spec.ZoneId)
+
+	if ghr.Status.MigratingFromZoneId == "" {
+		logger.Info("Zone changed, moving records to the new zone")
+		r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "ZoneMoveStarted", "Moving records from hosted zone %s to %s", from, ghr.Spec.ZoneId)
+		recordHistory(ghr, "ZoneMove", "", "ZoneMoveStarted", fmt.Sprintf("Moving records from hosted zone %s to %s", from, ghr.Spec.ZoneId))
+		ghr.Status.MigratingFromZoneId = from
+		if err := r.Status().Update(ctx, ghr); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Claim the hostname in the new zone; the old zone keeps serving it while the claim conflicts
+	allowed, err := r.checkSubtreeClaim(ctx, ghr)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !allowed {
+		return ctrl.Result{}, nil // Don't requeue, claim conflict
+	}
+	claimed, err := r.ensureDomainClaim(ctx, ghr)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !claimed {
+		message := fmt.Sprintf("Hostname already claimed in hosted zone %s by another request; still served from %s", ghr.Spec.ZoneId, from)
+		if !conditions.HasReason(ghr.Status.Conditions, ConditionTypeClaimed, metav1.ConditionFalse, conditions.ReasonAlreadyClaimed) {
+			r.Recorder.Event(ghr, corev1.EventTypeWarning, "AlreadyClaimed", message)
+		}
+		r.setCondition(ghr, ConditionTypeClaimed, metav1.ConditionFalse, conditions.ReasonAlreadyClaimed, message)
+		_ = r.Status().Update(ctx, ghr)
+		return ctrl.Result{}, nil // Don't requeue, claim conflict
+	}
+	r.setCondition(ghr, ConditionTypeClaimed, metav1.ConditionTrue, conditions.ReasonClaimed, "Domain successfully claimed")
+
+	// ACM keeps validating through the records for renewals, so they move with the hostname.
+	// DNS-01 challenge records only exist during an issuance and aren't moved.
+	moveValidation := !isHTTPOnly(ghr) && certificateIssuer(ghr) == CertificateIssuerACM && ghr.Status.CertificateArn != ""
+	if moveValidation {
+		if err := r.ensureValidationRecords(ctx, ghr); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to create validation records in zone %s: %w", ghr.Spec.ZoneId, err)
+		}
+	}
+
+	// Publishing the ALIAS records in the new zone removes them from the old one afterwards
+	if ghr.Status.AssignedLoadBalancer != "" {
+		if isDNSOnly(ghr) {
+			err = r.ensureExternalAlias(ctx, ghr)
+		} else {
+			err = r.ensureRoute53Alias(ctx, ghr)
+		}
+		if errors.Is(err, ErrLoadBalancerNotReady) {
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to publish ALIAS records in zone %s: %w", ghr.Spec.ZoneId, err)
+		}
+	}
+
+	if moveValidation {
+		if err := r.deleteValidationRecordsInZone(ctx, ghr, from); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	logger.Info("Moved records to the new zone")
+	r.Recorder.Eventf(ghr, corev1.EventTypeNormal, "ZoneMoved", "Moved records from hosted zone %s to %s", from, ghr.Spec.ZoneId)
+	recordHistory(ghr, "ZoneMove", "", "ZoneMoved", fmt.Sprintf("Moved records from hosted zone %s to %s", from, ghr.Spec.ZoneId))
+	ghr.Status.MigratingFromZoneId = ""
+	ghr.Status.ObservedSpecHash = computeSpecHash(&ghr.Spec)
+	if err := r.Status().Update(ctx, ghr); err != nil {
+		return ctrl.Result{}, err
+	}
+	// The rest of the spec didn't change; a full pass picks up what depends on the zone
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// deleteValidationRecordsInZone deletes the certificate's validation records from a zone the
+// hostname no longer lives in
+func (r *GatewayHostnameRequestReconciler) deleteValidationRecordsInZone(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest, zoneID string) error {
+	awsCtx, cancel := withAWSTimeout(ctx)
+	validationRecords, err := r.ACMClient.GetValidationRecords(awsCtx, ghr.Status.CertificateArn)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to get validation records: %w", err)
+	}
+	existing, err := r.existingValidationRecords(ctx, zoneID, validationRecords)
+	if err != nil || len(existing) == 0 {
+		return err
+	}
+	// Waiting for the change to be in sync takes longer than a single call
+	awsCtx, cancel = context.WithTimeout(ctx, 4*AWSCallTimeout)
+	defer cancel()
+	if err := r.Route53Client.DeleteRecords(awsCtx, zoneID, existing); err != nil {
+		return fmt.Errorf("failed to delete validation records from zone %s: %w", zoneID, err)
+	}
+	return nil
+}
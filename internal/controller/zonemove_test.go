@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+func TestZoneMoveSource(t *testing.T) {
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "api.example.com", ZoneId: "ZOLD"},
+	}
+	ghr.Status.ObservedSpecHash = computeSpecHash(&ghr.Spec)
+	ghr.Status.AliasZoneIds = []string{"ZOLD"}
+	assert.Empty(t, zoneMoveSource(ghr), "nothing changed")
+
+	ghr.Spec.ZoneId = "ZNEW"
+	assert.Equal(t, "ZOLD", zoneMoveSource(ghr))
+
+	ghr.Spec.GatewayClass = "internal-alb"
+	assert.Empty(t, zoneMoveSource(ghr), "other changes re-provision")
+
+	ghr.Spec.GatewayClass = ""
+	ghr.Status.AliasZoneIds = []string{"ZNEW"}
+	ghr.Status.MigratingFromZoneId = "ZOLD"
+	assert.Equal(t, "ZOLD", zoneMoveSource(ghr), "a started move is resumed after the aliases moved")
+}
+
+func TestMoveZone(t *testing.T) {
+	ctx := context.Background()
+	acmMock := aws.NewMockACMClient()
+	certArn, err := acmMock.RequestCertificate(ctx, "cdn.example.com", "", nil)
+	require.NoError(t, err)
+
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "cdn", Namespace: "default"},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+			Hostname: "cdn.example.com",
+			ZoneId:   "ZOLD",
+			AliasTarget: &gatewayv1alpha1.AliasTarget{
+				DNSName:      "d111111abcdef8.cloudfront.net",
+				HostedZoneId: "Z2FDTNDATAQYW2",
+			},
+		},
+	}
+	ghr.Status.CertificateArn = certArn
+	ghr.Status.AssignedLoadBalancer = "d111111abcdef8.cloudfront.net"
+	ghr.Status.AliasZoneIds = []string{"ZOLD"}
+	ghr.Status.AliasRecordTypes = []string{"A"}
+	ghr.Status.ObservedSpecHash = computeSpecHash(&ghr.Spec)
+	ghr.Spec.ZoneId = "ZNEW"
+
+	oldClaim := &gatewayv1alpha1.DomainClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: generateClaimName("ZOLD", "cdn.example.com")},
+		Spec: gatewayv1alpha1.DomainClaimSpec{
+			ZoneId: "ZOLD", Hostname: "cdn.example.com",
+			OwnerRef: gatewayv1alpha1.DomainClaimOwnerRef{Namespace: "default", Name: "cdn"},
+		},
+	}
+	route53Mock := aws.NewMockRoute53Client()
+	validation := aws.DNSRecord{Name: "_acm-validation.cdn.example.com", Type: "CNAME", Value: "_validation-value.acm-validations.aws.", TTL: 300}
+	require.NoError(t, route53Mock.CreateOrUpdateRecord(ctx, "ZOLD", validation))
+	require.NoError(t, route53Mock.CreateOrUpdateRecord(ctx, "ZOLD", aws.DNSRecord{
+		Name: "cdn.example.com", Type: "A",
+		AliasTarget: &aws.AliasTarget{DNSName: "d111111abcdef8.cloudfront.net", HostedZoneID: "Z2FDTNDATAQYW2"},
+	}))
+
+	r := &GatewayHostnameRequestReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(getTestScheme()).WithObjects(ghr, oldClaim).WithStatusSubresource(ghr).Build(),
+		Scheme:        getTestScheme(),
+		Recorder:      record.NewFakeRecorder(10),
+		Route53Client: route53Mock,
+		ACMClient:     acmMock,
+	}
+
+	_, err = r.reconcileNormal(ctx, ghr)
+	require.NoError(t, err)
+
+	assert.Contains(t, route53Mock.Records, "ZNEW:cdn.example.com:A")
+	assert.Contains(t, route53Mock.Records, "ZNEW:_acm-validation.cdn.example.com:CNAME")
+	assert.NotContains(t, route53Mock.Records, "ZOLD:cdn.example.com:A")
+	assert.NotContains(t, route53Mock.Records, "ZOLD:_acm-validation.cdn.example.com:CNAME")
+
+	var claim gatewayv1alpha1.DomainClaim
+	require.NoError(t, r.Get(ctx, types.NamespacedName{Name: generateClaimName("ZNEW", "cdn.example.com")}, &claim))
+	err = r.Get(ctx, types.NamespacedName{Name: oldClaim.Name}, &claim)
+	assert.True(t, apierrors.IsNotFound(err), "the old zone's claim is released")
+
+	assert.Equal(t, certArn, ghr.Status.CertificateArn, "the certificate is kept")
+	assert.Equal(t, []string{"ZNEW"}, ghr.Status.AliasZoneIds)
+	assert.Empty(t, ghr.Status.MigratingFromZoneId)
+	assert.Equal(t, computeSpecHash(&ghr.Spec), ghr.Status.ObservedSpecHash)
+}
@@ -0,0 +1,197 @@
+package gateway
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Default naming templates; they produce the names used before naming was configurable
+const (
+	DefaultGatewayNameTemplate                   = `gw-{{printf "%02d" .Index}}`
+	DefaultLoadBalancerConfigurationNameTemplate = `{{.Gateway}}-config`
+	DefaultTargetGroupConfigurationNameTemplate  = `{{.Service}}`
+)
+
+// AnnotationIndex records the index a Gateway was named with, so the next index can be found
+// whatever the name template
+const AnnotationIndex = "gateway.opendi.com/index"
+
+// maxLoadBalancerNameLength is the AWS limit for ALB names
+const maxLoadBalancerNameLength = 32
+
+var invalidLoadBalancerNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// The default templates, used by DefaultNaming
+var (
+	defaultGatewayName                   = template.Must(parseNameTemplate("gateway", DefaultGatewayNameTemplate, ""))
+	defaultLoadBalancerConfigurationName = template.Must(parseNameTemplate("load balancer configuration", DefaultLoadBalancerConfigurationNameTemplate, ""))
+	defaultTargetGroupConfigurationName  = template.Must(parseNameTemplate("target group configuration", DefaultTargetGroupConfigurationNameTemplate, ""))
+)
+
+// NameData is passed to the naming templates. All templates see Cluster and Environment.
+// Gateway templates also see Index, LoadBalancerConfiguration templates Gateway, load balancer
+// templates Gateway and Namespace, and TargetGroupConfiguration templates Service and Namespace.
+type NameData struct {
+	Cluster     string
+	Environment string
+	Index       int
+	Gateway     string
+	Namespace   string
+	Service     string
+}
+
+// Naming renders the names of the Gateways, LoadBalancerConfigurations and
+// TargetGroupConfigurations the controller creates, and of the ALBs behind them, so several
+// clusters sharing an AWS account can be told apart in the console.
+type Naming struct {
+	// Cluster and Environment identify this controller instance in names
+	Cluster     string
+	Environment string
+
+	gateway                   *template.Template
+	loadBalancerConfiguration *template.Template
+	targetGroupConfiguration  *template.Template
+	loadBalancer              *template.Template
+}
+
+// DefaultNaming returns the naming used when nothing is configured: gw-01, gw-01-config,
+// TargetGroupConfigurations named after their Service and ALB names chosen by the AWS Load
+// Balancer Controller
+func DefaultNaming() *Naming {
+	return &Naming{
+		gateway:                   defaultGatewayName,
+		loadBalancerConfiguration: defaultLoadBalancerConfigurationName,
+		targetGroupConfiguration:  defaultTargetGroupConfigurationName,
+	}
+}
+
+// NewNaming parses the templates; empty templates select the defaults, and an empty
+// load balancer template leaves ALB names to the AWS Load Balancer Controller. Templates are
+// checked by rendering sample names, so invalid ones fail at startup; templates that only fail
+// for other data fail the reconcile that renders them.
+func NewNaming(cluster, environment, gatewayTemplate, lbcTemplate, tgcTemplate, loadBalancerTemplate string) (*Naming, error) {
+	n := &Naming{Cluster: cluster, Environment: environment}
+	var err error
+	if n.gateway, err = parseNameTemplate("gateway", gatewayTemplate, DefaultGatewayNameTemplate); err != nil {
+		return nil, err
+	}
+	if n.loadBalancerConfiguration, err = parseNameTemplate("load balancer configuration", lbcTemplate, DefaultLoadBalancerConfigurationNameTemplate); err != nil {
+		return nil, err
+	}
+	if n.targetGroupConfiguration, err = parseNameTemplate("target group configuration", tgcTemplate, DefaultTargetGroupConfigurationNameTemplate); err != nil {
+		return nil, err
+	}
+	if loadBalancerTemplate != "" {
+		if n.loadBalancer, err = parseNameTemplate("load balancer", loadBalancerTemplate, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	first, err := n.render(n.gateway, NameData{Index: 1})
+	if err != nil {
+		return nil, err
+	}
+	second, err := n.render(n.gateway, NameData{Index: 2})
+	if err != nil {
+		return nil, err
+	}
+	if first == second {
+		return nil, fmt.Errorf("gateway name template %q must use {{.Index}} so Gateway names are unique", gatewayTemplate)
+	}
+	lbc, err := n.render(n.loadBalancerConfiguration, NameData{Gateway: first})
+	if err != nil {
+		return nil, err
+	}
+	tgc, err := n.render(n.targetGroupConfiguration, NameData{Service: "web", Namespace: "default"})
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range []string{first, lbc, tgc} {
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			return nil, fmt.Errorf("naming template renders invalid name %q: %s", name, strings.Join(errs, "; "))
+		}
+	}
+	if n.loadBalancer != nil {
+		name, err := n.LoadBalancerName(first, "edge")
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			return nil, fmt.Errorf("load balancer name template %q renders an empty name", loadBalancerTemplate)
+		}
+	}
+	return n, nil
+}
+
+func parseNameTemplate(kind, text, fallback string) (*template.Template, error) {
+	if text == "" {
+		text = fallback
+	}
+	t, err := template.New(kind).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s name template: %w", kind, err)
+	}
+	return t, nil
+}
+
+// render executes a template with the instance's Cluster and Environment filled in
+func (n *Naming) render(t *template.Template, data NameData) (string, error) {
+	data.Cluster, data.Environment = n.Cluster, n.Environment
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s name: %w", t.Name(), err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// GatewayName returns the name of the Gateway with the index
+func (n *Naming) GatewayName(index int) (string, error) {
+	return n.render(n.gateway, NameData{Index: index})
+}
+
+// LoadBalancerConfigurationName returns the name of the Gateway's LoadBalancerConfiguration
+func (n *Naming) LoadBalancerConfigurationName(gatewayName string) (string, error) {
+	return n.render(n.loadBalancerConfiguration, NameData{Gateway: gatewayName})
+}
+
+// TargetGroupConfigurationName returns the name of the TargetGroupConfiguration for a Service
+func (n *Naming) TargetGroupConfigurationName(service, namespace string) (string, error) {
+	return n.render(n.targetGroupConfiguration, NameData{Service: service, Namespace: namespace})
+}
+
+// LoadBalancerName returns the AWS name of the Gateway's ALB, or "" to let the AWS Load
+// Balancer Controller choose. The name is lowercased, invalid characters become hyphens and it
+// is cut to the AWS limit of 32 characters.
+func (n *Naming) LoadBalancerName(gatewayName, gatewayNamespace string) (string, error) {
+	if n.loadBalancer == nil {
+		return "", nil
+	}
+	name, err := n.render(n.loadBalancer, NameData{Gateway: gatewayName, Namespace: gatewayNamespace})
+	if err != nil {
+		return "", err
+	}
+	name = invalidLoadBalancerNameChars.ReplaceAllString(strings.ToLower(name), "-")
+	if len(name) > maxLoadBalancerNameLength {
+		name = name[:maxLoadBalancerNameLength]
+	}
+	return strings.Trim(name, "-"), nil
+}
+
+// LimitName keeps a name derived from a hostname within the 253 characters Kubernetes allows
+// for object and listener names. Longer names are cut and end in a hash of the full name, so
+// they stay unique and deterministic.
+func LimitName(name string) string {
+	if len(name) <= validation.DNS1123SubdomainMaxLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:8])
+	return strings.TrimRight(name[:validation.DNS1123SubdomainMaxLength-len(hash)-1], ".-") + "-" + hash
+}
@@ -0,0 +1,104 @@
+package gateway
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestNaming_Defaults(t *testing.T) {
+	n := DefaultNaming()
+	if got, err := n.GatewayName(3); err != nil || got != "gw-03" {
+		t.Errorf("GatewayName(3) = %q, %v, want gw-03", got, err)
+	}
+	if got, err := n.LoadBalancerConfigurationName("gw-03"); err != nil || got != "gw-03-config" {
+		t.Errorf("LoadBalancerConfigurationName() = %q, %v, want gw-03-config", got, err)
+	}
+	if got, err := n.TargetGroupConfigurationName("web", "team-a"); err != nil || got != "web" {
+		t.Errorf("TargetGroupConfigurationName() = %q, %v, want web", got, err)
+	}
+	if got, err := n.LoadBalancerName("gw-03", "edge"); err != nil || got != "" {
+		t.Errorf("LoadBalancerName() = %q, %v, want empty so the AWS Load Balancer Controller chooses", got, err)
+	}
+}
+
+func TestNaming_Templates(t *testing.T) {
+	n, err := NewNaming("eu-prod-1", "prod",
+		`{{.Environment}}-gw-{{.Index}}`, `{{.Gateway}}-lbc`, `{{.Service}}-tg`, `{{.Cluster}}-{{.Namespace}}_{{.Gateway}}`)
+	if err != nil {
+		t.Fatalf("NewNaming() error = %v", err)
+	}
+	if got, err := n.GatewayName(7); err != nil || got != "prod-gw-7" {
+		t.Errorf("GatewayName(7) = %q, %v, want prod-gw-7", got, err)
+	}
+	if got, err := n.LoadBalancerConfigurationName("prod-gw-7"); err != nil || got != "prod-gw-7-lbc" {
+		t.Errorf("LoadBalancerConfigurationName() = %q, %v, want prod-gw-7-lbc", got, err)
+	}
+	if got, err := n.TargetGroupConfigurationName("web", "team-a"); err != nil || got != "web-tg" {
+		t.Errorf("TargetGroupConfigurationName() = %q, %v, want web-tg", got, err)
+	}
+	if got, err := n.LoadBalancerName("prod-gw-7", "edge"); err != nil || got != "eu-prod-1-edge-prod-gw-7" {
+		t.Errorf("LoadBalancerName() = %q, %v, want eu-prod-1-edge-prod-gw-7", got, err)
+	}
+
+	long, err := NewNaming(strings.Repeat("cluster", 5), "", "", "", "", `{{.Cluster}}-{{.Gateway}}`)
+	if err != nil {
+		t.Fatalf("NewNaming() error = %v", err)
+	}
+	if got, err := long.LoadBalancerName("gw-01", "edge"); err != nil || len(got) > 32 || strings.HasSuffix(got, "-") {
+		t.Errorf("LoadBalancerName() = %q, %v, want at most 32 characters without trailing hyphen", got, err)
+	}
+}
+
+func TestNewNaming_Invalid(t *testing.T) {
+	tests := []struct {
+		name                   string
+		gateway, lbc, tgc, alb string
+	}{
+		{name: "gateway without index", gateway: "gw"},
+		{name: "unparsable template", gateway: "gw-{{.Index"},
+		{name: "unknown field", lbc: "{{.Gatway}}-config"},
+		{name: "invalid Kubernetes name", tgc: "{{.Service}}_TG"},
+		{name: "empty load balancer name", alb: "{{.Cluster}}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewNaming("", "", tt.gateway, tt.lbc, tt.tgc, tt.alb); err == nil {
+				t.Error("NewNaming() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestLimitName(t *testing.T) {
+	if got := LimitName("z123-api.example.com"); got != "z123-api.example.com" {
+		t.Errorf("LimitName() = %q, want short names unchanged", got)
+	}
+
+	long := "z123-" + strings.Repeat(strings.Repeat("a", 63)+".", 3) + strings.Repeat("b", 61) + ".example.com"
+	got := LimitName(long)
+	if len(got) > 253 {
+		t.Errorf("LimitName() is %d characters, want at most 253", len(got))
+	}
+	if errs := validation.IsDNS1123Subdomain(got); len(errs) > 0 {
+		t.Errorf("LimitName() = %q is not a valid name: %v", got, errs)
+	}
+	if LimitName(long) != got {
+		t.Error("LimitName() is not deterministic")
+	}
+	if other := LimitName(strings.Replace(long, "example", "example2", 1)); other == got {
+		t.Error("LimitName() gives names that differ after the cut the same result")
+	}
+}
+
+func TestNaming_RenderError(t *testing.T) {
+	// The sample Service name renders, shorter ones don't
+	n, err := NewNaming("", "", "", "", `{{slice .Service 0 3}}-tg`, "")
+	if err != nil {
+		t.Fatalf("NewNaming() error = %v", err)
+	}
+	if _, err := n.TargetGroupConfigurationName("db", "team-a"); err == nil {
+		t.Error("TargetGroupConfigurationName() error = nil, want error")
+	}
+}
@@ -3,11 +3,18 @@ package gateway
 import (
 	"context"
 	"fmt"
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapisxv1alpha1 "sigs.k8s.io/gateway-api/apisx/v1alpha1"
 )
 
 const (
@@ -16,6 +23,37 @@ const (
 
 	// MaxRulesPerGateway is the soft limit for rules per Gateway
 	MaxRulesPerGateway = 100
+
+	// AnnotationCordoned excludes a Gateway from selection for new hostnames.
+	// The orchestrator sets it to CordonReasonOverCapacity or CordonReasonLoadBalancerFailed and
+	// only ever removes CordonReasonOverCapacity, so operators can cordon a Gateway manually with
+	// any other value.
+	AnnotationCordoned = "gateway.opendi.com/cordoned"
+
+	// CordonReasonOverCapacity marks Gateways holding more certificates than the pool limit
+	CordonReasonOverCapacity = "over-capacity"
+
+	// CordonReasonLoadBalancerFailed marks Gateways whose ALB failed or was deleted in AWS; their
+	// hostnames are moved to a replacement Gateway
+	CordonReasonLoadBalancerFailed = "load-balancer-failed"
+
+	// CordonReasonDrained marks Gateways the admin API drains; like a manual cordon, only an
+	// operator removes it
+	CordonReasonDrained = "drained"
+
+	// AnnotationDeletionLease marks a Gateway whose last request is deleting it, with the
+	// request's namespace/name. Leased Gateways take no new hostnames.
+	AnnotationDeletionLease = "gateway.opendi.com/deletion-lease"
+
+	// AnnotationHTTPSOnly marks a Gateway created without the plain HTTP listener ("true"), so
+	// neither its spec nor its LoadBalancerConfiguration open the HTTP port
+	AnnotationHTTPSOnly = "gateway.opendi.com/https-only"
+
+	// TargetTypeIP registers pod IPs as ALB targets (default)
+	TargetTypeIP = "ip"
+
+	// TargetTypeInstance registers node ports as ALB targets, for clusters without VPC-routable pod IPs
+	TargetTypeInstance = "instance"
 )
 
 // Pool manages the Gateway pool
@@ -23,8 +61,34 @@ type Pool struct {
 	client       client.Client
 	namespace    string
 	gatewayClass string
-	httpPort     int32
-	httpsPort    int32
+
+	// settingsMu guards the settings an OrchestratorConfig can change at runtime: the
+	// listener ports, the certificate limit and the creation cooldown
+	settingsMu sync.RWMutex
+	httpPort   int32
+	httpsPort  int32
+
+	// httpsOnly leaves the HTTP listener off new Gateways
+	httpsOnly bool
+
+	maxCertificates int
+
+	// namespaceByVisibility places Gateways of a visibility in their own namespace
+	namespaceByVisibility map[string]string
+
+	// targetType is the ALB target type for backends of the pool's Gateways
+	targetType string
+
+	// creationCooldown is the minimum time between two Gateway creations (0 disables)
+	creationCooldown time.Duration
+
+	// naming renders the names of created Gateways and their LoadBalancerConfigurations
+	naming *Naming
+
+	// lastCreated is when this process last created a Gateway. It covers Gateways the
+	// cache does not show yet.
+	mu          sync.Mutex
+	lastCreated time.Time
 }
 
 // NewPool creates a new Gateway pool manager
@@ -42,24 +106,260 @@ func NewPool(c client.Client, namespace, gatewayClass string, httpPort, httpsPor
 		gatewayClass: gatewayClass,
 		httpPort:     httpPort,
 		httpsPort:    httpsPort,
+
+		maxCertificates: MaxCertificatesPerGateway,
+		naming:          DefaultNaming(),
 	}
 }
 
+// SetMaxCertificates overrides the per-Gateway certificate limit (0 restores MaxCertificatesPerGateway).
+// Lowering it below the count of existing Gateways cordons them on their next sync.
+func (p *Pool) SetMaxCertificates(n int) {
+	if n <= 0 {
+		n = MaxCertificatesPerGateway
+	}
+	p.settingsMu.Lock()
+	defer p.settingsMu.Unlock()
+	p.maxCertificates = n
+}
+
+// MaxCertificates returns the per-Gateway certificate limit
+func (p *Pool) MaxCertificates() int {
+	p.settingsMu.RLock()
+	defer p.settingsMu.RUnlock()
+	return p.maxCertificates
+}
+
+// SetTargetType sets the ALB target type for backends of the pool's Gateways.
+// Must be TargetTypeIP or TargetTypeInstance; empty resets to TargetTypeIP.
+func (p *Pool) SetTargetType(targetType string) error {
+	switch targetType {
+	case "":
+		targetType = TargetTypeIP
+	case TargetTypeIP, TargetTypeInstance:
+	default:
+		return fmt.Errorf("invalid target type %q, must be %s or %s", targetType, TargetTypeIP, TargetTypeInstance)
+	}
+	p.targetType = targetType
+	return nil
+}
+
+// TargetType returns the ALB target type for backends of the pool's Gateways
+func (p *Pool) TargetType() string {
+	if p.targetType == "" {
+		return TargetTypeIP
+	}
+	return p.targetType
+}
+
+// SetCreationCooldown sets the minimum time between two Gateway creations, which bounds how
+// fast new ALBs are provisioned (0 disables the limit)
+func (p *Pool) SetCreationCooldown(d time.Duration) {
+	p.settingsMu.Lock()
+	defer p.settingsMu.Unlock()
+	p.creationCooldown = d
+}
+
+// CreationCooldown returns the minimum time between two Gateway creations
+func (p *Pool) CreationCooldown() time.Duration {
+	p.settingsMu.RLock()
+	defer p.settingsMu.RUnlock()
+	return p.creationCooldown
+}
+
+// NextCreation returns the earliest time a new Gateway may be created under the creation
+// cooldown; the zero time if there is no limit. It is based on the creation timestamps of
+// the pool's Gateways, so the cooldown survives restarts and leader changes.
+func (p *Pool) NextCreation(ctx context.Context) (time.Time, error) {
+	cooldown := p.CreationCooldown()
+	if cooldown <= 0 {
+		return time.Time{}, nil
+	}
+
+	p.mu.Lock()
+	last := p.lastCreated
+	p.mu.Unlock()
+
+	gateways, err := p.ListGateways(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, gw := range gateways {
+		if gw.CreationTimestamp.After(last) {
+			last = gw.CreationTimestamp.Time
+		}
+	}
+	if last.IsZero() {
+		return time.Time{}, nil
+	}
+	return last.Add(cooldown), nil
+}
+
+// IsCordoned reports whether a Gateway is excluded from selection for new hostnames
+func IsCordoned(gw *gwapiv1.Gateway) bool {
+	return gw.Annotations[AnnotationCordoned] != ""
+}
+
+// IsBeingDeleted reports whether the Gateway is deleted or leased for deletion
+func IsBeingDeleted(gw *gwapiv1.Gateway) bool {
+	return !gw.DeletionTimestamp.IsZero() || gw.Annotations[AnnotationDeletionLease] != ""
+}
+
 // HTTPPort returns the configured HTTP listener port (default: 80)
 func (p *Pool) HTTPPort() int32 {
+	p.settingsMu.RLock()
+	defer p.settingsMu.RUnlock()
 	return p.httpPort
 }
 
 // HTTPSPort returns the configured HTTPS listener port (default: 443)
 func (p *Pool) HTTPSPort() int32 {
+	p.settingsMu.RLock()
+	defer p.settingsMu.RUnlock()
 	return p.httpsPort
 }
 
-// Namespace returns the namespace where Gateways are created
+// SetPorts changes the listener ports of Gateways created from now on (0 defaults to 80/443).
+// Existing Gateways keep their listeners.
+func (p *Pool) SetPorts(httpPort, httpsPort int32) {
+	if httpPort == 0 {
+		httpPort = 80
+	}
+	if httpsPort == 0 {
+		httpsPort = 443
+	}
+	p.settingsMu.Lock()
+	defer p.settingsMu.Unlock()
+	p.httpPort = httpPort
+	p.httpsPort = httpsPort
+}
+
+// SetHTTPSOnly makes Gateways created from now on HTTPS-only, without the HTTP listener.
+// Existing Gateways keep their listeners.
+func (p *Pool) SetHTTPSOnly(httpsOnly bool) {
+	p.settingsMu.Lock()
+	defer p.settingsMu.Unlock()
+	p.httpsOnly = httpsOnly
+}
+
+// HTTPSOnly reports whether new Gateways are created without the HTTP listener
+func (p *Pool) HTTPSOnly() bool {
+	p.settingsMu.RLock()
+	defer p.settingsMu.RUnlock()
+	return p.httpsOnly
+}
+
+// IsHTTPSOnly reports whether a Gateway was created without the HTTP listener
+func IsHTTPSOnly(gw *gwapiv1.Gateway) bool {
+	return gw.Annotations[AnnotationHTTPSOnly] == "true"
+}
+
+// Namespace returns the default namespace where Gateways are created
 func (p *Pool) Namespace() string {
 	return p.namespace
 }
 
+// SetNamespaceByVisibility places Gateways of the given visibilities (internet-facing, internal)
+// in their own namespace instead of the default one
+func (p *Pool) SetNamespaceByVisibility(m map[string]string) {
+	p.namespaceByVisibility = m
+}
+
+// SetNaming sets how created Gateways and their LoadBalancerConfigurations are named
+// (nil restores DefaultNaming). Existing Gateways keep their names.
+func (p *Pool) SetNaming(n *Naming) {
+	if n == nil {
+		n = DefaultNaming()
+	}
+	p.naming = n
+}
+
+// Naming returns how the pool names created resources
+func (p *Pool) Naming() *Naming {
+	return p.naming
+}
+
+// ParseNamespaceByVisibility parses a comma-separated list of "<visibility>=<namespace>" entries,
+// e.g. "internal=edge-internal"
+func ParseNamespaceByVisibility(s string) (map[string]string, error) {
+	m := make(map[string]string)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		visibility, namespace, ok := strings.Cut(entry, "=")
+		visibility, namespace = strings.TrimSpace(visibility), strings.TrimSpace(namespace)
+		if !ok || namespace == "" {
+			return nil, fmt.Errorf("invalid entry %q, expected <visibility>=<namespace>", entry)
+		}
+		if visibility != "internet-facing" && visibility != "internal" {
+			return nil, fmt.Errorf("invalid visibility %q, must be internet-facing or internal", visibility)
+		}
+		m[visibility] = namespace
+	}
+	return m, nil
+}
+
+// NamespaceFor returns the namespace for Gateways of a visibility
+func (p *Pool) NamespaceFor(visibility string) string {
+	if ns := p.namespaceByVisibility[visibility]; ns != "" {
+		return ns
+	}
+	return p.namespace
+}
+
+// Namespaces returns all namespaces holding pool Gateways, sorted
+func (p *Pool) Namespaces() []string {
+	seen := map[string]bool{p.namespace: true}
+	namespaces := []string{p.namespace}
+	for _, ns := range p.namespaceByVisibility {
+		if ns != "" && !seen[ns] {
+			seen[ns] = true
+			namespaces = append(namespaces, ns)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// IsPoolNamespace reports whether pool Gateways may live in the namespace
+func (p *Pool) IsPoolNamespace(namespace string) bool {
+	for _, ns := range p.Namespaces() {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// GatewayClass returns the GatewayClass of pool Gateways
+func (p *Pool) GatewayClass() string {
+	return p.gatewayClass
+}
+
+// ListGateways returns the Gateways of the pool's GatewayClass in all pool namespaces
+func (p *Pool) ListGateways(ctx context.Context) ([]gwapiv1.Gateway, error) {
+	var gateways []gwapiv1.Gateway
+	for _, ns := range p.Namespaces() {
+		var gatewayList gwapiv1.GatewayList
+		if err := p.client.List(ctx, &gatewayList, client.InNamespace(ns)); err != nil {
+			return nil, fmt.Errorf("failed to list gateways in %s: %w", ns, err)
+		}
+		for _, gw := range gatewayList.Items {
+			if string(gw.Spec.GatewayClassName) == p.gatewayClass {
+				gateways = append(gateways, gw)
+			}
+		}
+	}
+	return gateways, nil
+}
+
+// Info returns capacity information for a Gateway
+func (p *Pool) Info(gw *gwapiv1.Gateway) *GatewayInfo {
+	return p.getGatewayInfo(gw)
+}
+
 // GatewayInfo holds Gateway metadata and capacity info
 type GatewayInfo struct {
 	Name             string
@@ -74,9 +374,19 @@ type GatewayInfo struct {
 // If selector is specified, only Gateways matching the label selector will be considered
 // wafArn can be empty (no WAF) or a specific WAF ARN - only Gateways with matching WAF config will be considered
 func (p *Pool) SelectGateway(ctx context.Context, visibility string, wafArn string, selector *metav1.LabelSelector) (*GatewayInfo, error) {
+	return p.selectGateway(ctx, visibility, wafArn, selector, false)
+}
+
+// SelectHTTPGateway chooses a Gateway like SelectGateway, among those with an HTTP listener,
+// for hostnames served over plain HTTP
+func (p *Pool) SelectHTTPGateway(ctx context.Context, visibility string, wafArn string, selector *metav1.LabelSelector) (*GatewayInfo, error) {
+	return p.selectGateway(ctx, visibility, wafArn, selector, true)
+}
+
+func (p *Pool) selectGateway(ctx context.Context, visibility string, wafArn string, selector *metav1.LabelSelector, needsHTTP bool) (*GatewayInfo, error) {
 	// List all Gateways in the namespace
 	var gatewayList gwapiv1.GatewayList
-	if err := p.client.List(ctx, &gatewayList, client.InNamespace(p.namespace)); err != nil {
+	if err := p.client.List(ctx, &gatewayList, client.InNamespace(p.NamespaceFor(visibility))); err != nil {
 		return nil, fmt.Errorf("failed to list gateways: %w", err)
 	}
 
@@ -115,11 +425,26 @@ func (p *Pool) SelectGateway(ctx context.Context, visibility string, wafArn stri
 			continue
 		}
 
+		// HTTPS-only Gateways can't serve plain HTTP hostnames
+		if needsHTTP && IsHTTPSOnly(&gw) {
+			continue
+		}
+
+		// Cordoned Gateways keep serving their hostnames but take no new ones
+		if IsCordoned(&gw) {
+			continue
+		}
+
+		// Gateways being deleted would take the hostname down with them
+		if IsBeingDeleted(&gw) {
+			continue
+		}
+
 		// Get capacity info
 		info := p.getGatewayInfo(&gw)
 
 		// Check if Gateway has capacity (first-fit)
-		if info.CertificateCount < MaxCertificatesPerGateway && info.RuleCount < MaxRulesPerGateway {
+		if info.CertificateCount < p.MaxCertificates() && info.RuleCount < MaxRulesPerGateway {
 			return info, nil
 		}
 	}
@@ -163,18 +488,41 @@ func (p *Pool) getGatewayInfo(gw *gwapiv1.Gateway) *GatewayInfo {
 // Certificate management is handled via LoadBalancerConfiguration, not the Gateway itself
 // wafArn can be empty (no WAF) or a specific WAF ARN to configure on the Gateway
 func (p *Pool) CreateGateway(ctx context.Context, visibility string, wafArn string, index int) (*GatewayInfo, error) {
-	name := fmt.Sprintf("gw-%02d", index)
-	configName := fmt.Sprintf("%s-config", name)
+	return p.CreateGatewayWithMetadata(ctx, visibility, wafArn, index, nil, nil)
+}
+
+// CreateGatewayWithMetadata creates a new Gateway like CreateGateway, with extra labels and
+// annotations. The pool's own annotations take precedence. An AnnotationHTTPSOnly among the
+// annotations overrides the pool's HTTPS-only setting for this Gateway.
+func (p *Pool) CreateGatewayWithMetadata(ctx context.Context, visibility string, wafArn string, index int, labels, annotations map[string]string) (*GatewayInfo, error) {
+	name, err := p.naming.GatewayName(index)
+	if err != nil {
+		return nil, err
+	}
+	configName, err := p.naming.LoadBalancerConfigurationName(name)
+	if err != nil {
+		return nil, err
+	}
 
 	gw := &gwapiv1.Gateway{}
 	gw.Name = name
-	gw.Namespace = p.namespace
+	gw.Namespace = p.NamespaceFor(visibility)
+	gw.Labels = maps.Clone(labels)
 	gw.Annotations = map[string]string{
 		"gateway.opendi.com/visibility":                visibility,
 		"gateway.opendi.com/certificate-count":         "0",
 		"gateway.opendi.com/rule-count":                "0",
 		"gateway.k8s.aws/loadbalancer-configuration":   configName,
 		"gateway.opendi.com/waf-arn":                   wafArn,
+		AnnotationIndex:                                strconv.Itoa(index),
+	}
+	for key, value := range annotations {
+		if _, ok := gw.Annotations[key]; !ok {
+			gw.Annotations[key] = value
+		}
+	}
+	if _, ok := annotations[AnnotationHTTPSOnly]; !ok && p.HTTPSOnly() {
+		gw.Annotations[AnnotationHTTPSOnly] = "true"
 	}
 	gw.Spec.GatewayClassName = gwapiv1.ObjectName(p.gatewayClass)
 
@@ -202,7 +550,7 @@ func (p *Pool) CreateGateway(ctx context.Context, visibility string, wafArn stri
 		{
 			Name:          "https",
 			Protocol:      gwapiv1.HTTPSProtocolType,
-			Port:          gwapiv1.PortNumber(p.httpsPort),
+			Port:          gwapiv1.PortNumber(p.HTTPSPort()),
 			AllowedRoutes: allowedRoutes,
 			TLS: &gwapiv1.ListenerTLSConfig{
 				Mode: ptrTo(gwapiv1.TLSModeTerminate),
@@ -213,40 +561,114 @@ func (p *Pool) CreateGateway(ctx context.Context, visibility string, wafArn stri
 				},
 			},
 		},
-		{
+	}
+	if !IsHTTPSOnly(gw) {
+		gw.Spec.Listeners = append(gw.Spec.Listeners, gwapiv1.Listener{
 			Name:          "http",
 			Protocol:      gwapiv1.HTTPProtocolType,
-			Port:          gwapiv1.PortNumber(p.httpPort),
+			Port:          gwapiv1.PortNumber(p.HTTPPort()),
 			AllowedRoutes: allowedRoutes,
-		},
+		})
 	}
 
 	if err := p.client.Create(ctx, gw); err != nil {
 		return nil, fmt.Errorf("failed to create gateway %s: %w", name, err)
 	}
 
+	p.mu.Lock()
+	p.lastCreated = time.Now()
+	p.mu.Unlock()
+
 	return &GatewayInfo{
 		Name:      name,
-		Namespace: p.namespace,
+		Namespace: gw.Namespace,
 	}, nil
 }
 
+// HostnameListenerPrefix prefixes the names of per-hostname listeners managed by the orchestrator
+const HostnameListenerPrefix = "https-"
+
+// HostnameListenerName returns the listener name used for a dedicated hostname listener.
+// Wildcards are spelled out because '*' is not allowed in listener names.
+func HostnameListenerName(hostname string) gwapiv1.SectionName {
+	return gwapiv1.SectionName(LimitName(HostnameListenerPrefix + strings.ReplaceAll(hostname, "*", "wildcard")))
+}
+
+// IsHostnameListener reports whether a listener was added by HostnameListener
+func IsHostnameListener(l gwapiv1.Listener) bool {
+	return strings.HasPrefix(string(l.Name), HostnameListenerPrefix) && l.Hostname != nil
+}
+
+// HostnameListener builds a dedicated HTTPS listener bound to a single hostname.
+// It shares the pool's HTTPS port with the catch-all "https" listener; the AWS Load Balancer
+// Controller merges them into one ALB listener, so no extra ALB listener is consumed. Like the
+// catch-all listener, it gets its certificate from the HTTPS listener configuration of the
+// Gateway's LoadBalancerConfiguration, which lists the ACM certificate of every hostname on
+// the Gateway; the ALB picks the hostname's own certificate by SNI.
+func (p *Pool) HostnameListener(hostname string) gwapiv1.Listener {
+	fromAll := gwapiv1.NamespacesFromAll
+	h := gwapiv1.Hostname(hostname)
+	return gwapiv1.Listener{
+		Name:     HostnameListenerName(hostname),
+		Hostname: &h,
+		Protocol: gwapiv1.HTTPSProtocolType,
+		Port:     gwapiv1.PortNumber(p.HTTPSPort()),
+		AllowedRoutes: &gwapiv1.AllowedRoutes{
+			Namespaces: &gwapiv1.RouteNamespaces{
+				From: &fromAll,
+			},
+		},
+		TLS: &gwapiv1.ListenerTLSConfig{
+			Mode: ptrTo(gwapiv1.TLSModeTerminate),
+			Options: map[gwapiv1.AnnotationKey]gwapiv1.AnnotationValue{
+				"gateway.opendi.com/acm-managed": "true",
+			},
+		},
+	}
+}
+
+// ListenerSetName returns the name of the XListenerSet holding a Gateway's per-hostname
+// listeners in ListenerSet mode
+func ListenerSetName(gatewayName string) string {
+	return gatewayName + "-hostnames"
+}
+
+// HostnameListenerEntry builds the XListenerSet entry equivalent to HostnameListener
+func (p *Pool) HostnameListenerEntry(hostname string) gwapisxv1alpha1.ListenerEntry {
+	l := p.HostnameListener(hostname)
+	return gwapisxv1alpha1.ListenerEntry{
+		Name:          l.Name,
+		Hostname:      l.Hostname,
+		Port:          l.Port,
+		Protocol:      l.Protocol,
+		TLS:           l.TLS,
+		AllowedRoutes: l.AllowedRoutes,
+	}
+}
+
 // ptrTo returns a pointer to the given value
 func ptrTo[T any](v T) *T {
 	return &v
 }
 
-// GetNextGatewayIndex returns the next available Gateway index
+// GetNextGatewayIndex returns the next available Gateway index.
+// Indexes are unique across all pool namespaces, so Gateway names are too.
 func (p *Pool) GetNextGatewayIndex(ctx context.Context) (int, error) {
-	var gatewayList gwapiv1.GatewayList
-	if err := p.client.List(ctx, &gatewayList, client.InNamespace(p.namespace)); err != nil {
-		return 0, fmt.Errorf("failed to list gateways: %w", err)
-	}
-
 	maxIndex := 0
-	for _, gw := range gatewayList.Items {
-		var idx int
-		if _, err := fmt.Sscanf(gw.Name, "gw-%d", &idx); err == nil {
+	for _, ns := range p.Namespaces() {
+		var gatewayList gwapiv1.GatewayList
+		if err := p.client.List(ctx, &gatewayList, client.InNamespace(ns)); err != nil {
+			return 0, fmt.Errorf("failed to list gateways: %w", err)
+		}
+
+		for _, gw := range gatewayList.Items {
+			// Gateways created before AnnotationIndex existed are named gw-<index>
+			idx, err := strconv.Atoi(gw.Annotations[AnnotationIndex])
+			if err != nil {
+				if _, err := fmt.Sscanf(gw.Name, "gw-%d", &idx); err != nil {
+					continue
+				}
+			}
 			if idx > maxIndex {
 				maxIndex = idx
 			}
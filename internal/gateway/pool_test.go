@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -136,6 +137,52 @@ func TestPool_SelectGateway(t *testing.T) {
 			wantGateway: "gw-02",
 			wantNil:     false,
 		},
+		{
+			name: "skip cordoned gateway",
+			existingGateways: []gwapiv1.Gateway{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "gw-01",
+						Namespace: "edge",
+						Annotations: map[string]string{
+							"gateway.opendi.com/visibility":        "internet-facing",
+							"gateway.opendi.com/certificate-count": "5",
+							"gateway.opendi.com/cordoned":          "maintenance",
+						},
+					},
+					Spec: gwapiv1.GatewaySpec{
+						GatewayClassName: "aws-alb",
+					},
+				},
+			},
+			visibility:  "internet-facing",
+			selector:    nil,
+			wantGateway: "",
+			wantNil:     true,
+		},
+		{
+			name: "skip gateway leased for deletion",
+			existingGateways: []gwapiv1.Gateway{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "gw-01",
+						Namespace: "edge",
+						Annotations: map[string]string{
+							"gateway.opendi.com/visibility":        "internet-facing",
+							"gateway.opendi.com/certificate-count": "0",
+							"gateway.opendi.com/deletion-lease":    "default/last",
+						},
+					},
+					Spec: gwapiv1.GatewaySpec{
+						GatewayClassName: "aws-alb",
+					},
+				},
+			},
+			visibility:  "internet-facing",
+			selector:    nil,
+			wantGateway: "",
+			wantNil:     true,
+		},
 		{
 			name: "select gateway matching label selector",
 			existingGateways: []gwapiv1.Gateway{
@@ -247,6 +294,41 @@ func TestPool_SelectGateway(t *testing.T) {
 	}
 }
 
+func TestPool_SelectGateway_LoweredLimit(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = gwapiv1.AddToScheme(scheme)
+
+	gw := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gw-01",
+			Namespace: "edge",
+			Annotations: map[string]string{
+				"gateway.opendi.com/visibility":        "internet-facing",
+				"gateway.opendi.com/certificate-count": "10",
+			},
+		},
+		Spec: gwapiv1.GatewaySpec{
+			GatewayClassName: "aws-alb",
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(gw).Build()
+	pool := NewPool(client, "edge", "aws-alb", 0, 0)
+
+	got, err := pool.SelectGateway(context.Background(), "internet-facing", "", nil)
+	if err != nil || got == nil || got.Name != "gw-01" {
+		t.Fatalf("SelectGateway() = %v, %v; want gw-01 with the default limit", got, err)
+	}
+
+	pool.SetMaxCertificates(10)
+	if pool.MaxCertificates() != 10 {
+		t.Errorf("MaxCertificates() = %d, want 10", pool.MaxCertificates())
+	}
+	got, err = pool.SelectGateway(context.Background(), "internet-facing", "", nil)
+	if err != nil || got != nil {
+		t.Errorf("SelectGateway() = %v, %v; want nil once the limit is lowered to the current count", got, err)
+	}
+}
+
 func TestPool_GetNextGatewayIndex(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = gwapiv1.AddToScheme(scheme)
@@ -315,6 +397,25 @@ func TestPool_GetNextGatewayIndex(t *testing.T) {
 			},
 			wantIndex: 6,
 		},
+		{
+			name: "templated names use the index annotation",
+			existingGateways: []gwapiv1.Gateway{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "eu1-prod-gw-7",
+						Namespace:   "edge",
+						Annotations: map[string]string{AnnotationIndex: "7"},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "gw-03",
+						Namespace: "edge",
+					},
+				},
+			},
+			wantIndex: 8,
+		},
 	}
 
 	for _, tt := range tests {
@@ -547,3 +648,181 @@ func TestPool_CreateGateway_AllowedRoutesFromAll(t *testing.T) {
 		}
 	}
 }
+
+func TestPool_HostnameListener(t *testing.T) {
+	pool := NewPool(nil, "edge", "aws-alb", 0, 8443)
+
+	l := pool.HostnameListener("*.example.com")
+
+	if l.Name != "https-wildcard.example.com" {
+		t.Errorf("name = %v, want https-wildcard.example.com", l.Name)
+	}
+	if l.Hostname == nil || *l.Hostname != "*.example.com" {
+		t.Errorf("hostname = %v, want *.example.com", l.Hostname)
+	}
+	if l.Port != 8443 {
+		t.Errorf("port = %d, want 8443", l.Port)
+	}
+	if l.Protocol != gwapiv1.HTTPSProtocolType {
+		t.Errorf("protocol = %v, want HTTPS", l.Protocol)
+	}
+	if !IsHostnameListener(l) {
+		t.Error("expected IsHostnameListener to be true")
+	}
+	if IsHostnameListener(gwapiv1.Listener{Name: "https"}) {
+		t.Error("catch-all https listener must not be treated as a hostname listener")
+	}
+	if l.TLS == nil || len(l.TLS.CertificateRefs) != 0 || l.TLS.Options["gateway.opendi.com/acm-managed"] != "true" {
+		t.Errorf("TLS = %+v, want the certificate from the LoadBalancerConfiguration", l.TLS)
+	}
+
+	entry := pool.HostnameListenerEntry("*.example.com")
+	if entry.Name != l.Name || *entry.Hostname != *l.Hostname || entry.Port != l.Port || entry.TLS.Options["gateway.opendi.com/acm-managed"] != "true" {
+		t.Errorf("listener set entry %+v doesn't match listener %+v", entry, l)
+	}
+}
+
+func TestPool_NamespaceByVisibility(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = gwapiv1.AddToScheme(scheme)
+
+	internal := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gw-03",
+			Namespace: "edge-internal",
+			Annotations: map[string]string{
+				"gateway.opendi.com/visibility": "internal",
+			},
+		},
+		Spec: gwapiv1.GatewaySpec{GatewayClassName: "aws-alb"},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(internal).Build()
+	pool := NewPool(client, "edge", "aws-alb", 0, 0)
+
+	m, err := ParseNamespaceByVisibility("internal=edge-internal")
+	if err != nil {
+		t.Fatalf("ParseNamespaceByVisibility() error = %v", err)
+	}
+	pool.SetNamespaceByVisibility(m)
+
+	if got := pool.NamespaceFor("internal"); got != "edge-internal" {
+		t.Errorf("NamespaceFor(internal) = %s, want edge-internal", got)
+	}
+	if got := pool.NamespaceFor("internet-facing"); got != "edge" {
+		t.Errorf("NamespaceFor(internet-facing) = %s, want edge", got)
+	}
+
+	got, err := pool.SelectGateway(context.Background(), "internal", "", nil)
+	if err != nil || got == nil || got.Namespace != "edge-internal" {
+		t.Fatalf("SelectGateway(internal) = %v, %v; want gw-03 in edge-internal", got, err)
+	}
+
+	// Indexes stay unique across pool namespaces
+	index, err := pool.GetNextGatewayIndex(context.Background())
+	if err != nil || index != 4 {
+		t.Errorf("GetNextGatewayIndex() = %d, %v; want 4", index, err)
+	}
+
+	created, err := pool.CreateGateway(context.Background(), "internal", "", index)
+	if err != nil || created.Namespace != "edge-internal" {
+		t.Errorf("CreateGateway(internal) = %v, %v; want a Gateway in edge-internal", created, err)
+	}
+
+	for _, invalid := range []string{"public=edge-public", "internal=", "internal"} {
+		if _, err := ParseNamespaceByVisibility(invalid); err == nil {
+			t.Errorf("ParseNamespaceByVisibility(%q) should fail", invalid)
+		}
+	}
+}
+
+func TestPool_NextCreation(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = gwapiv1.AddToScheme(scheme)
+	ctx := context.Background()
+
+	created := time.Now().Add(-2 * time.Minute).Truncate(time.Second)
+	existing := &gwapiv1.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw-01", Namespace: "edge", CreationTimestamp: metav1.NewTime(created)},
+		Spec:       gwapiv1.GatewaySpec{GatewayClassName: "aws-alb"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+	pool := NewPool(c, "edge", "aws-alb", 0, 0)
+
+	next, err := pool.NextCreation(ctx)
+	if err != nil || !next.IsZero() {
+		t.Fatalf("NextCreation() without cooldown = %v, %v; want zero time", next, err)
+	}
+
+	pool.SetCreationCooldown(10 * time.Minute)
+	next, err = pool.NextCreation(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := created.Add(10 * time.Minute); !next.Equal(want) {
+		t.Errorf("NextCreation() = %v, want %v", next, want)
+	}
+
+	// Gateways created by the pool count even before the cache shows their timestamp
+	before := time.Now()
+	if _, err := pool.CreateGateway(ctx, "internet-facing", "", 2); err != nil {
+		t.Fatal(err)
+	}
+	next, err = pool.NextCreation(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Before(before.Add(10 * time.Minute)) {
+		t.Errorf("NextCreation() = %v, want at least 10m after the last creation", next)
+	}
+}
+
+func TestPool_CreateGateway_HTTPSOnly(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = gwapiv1.AddToScheme(scheme)
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+	pool := NewPool(client, "edge", "aws-alb", 0, 0)
+	ctx := context.Background()
+
+	if _, err := pool.CreateGateway(ctx, "internet-facing", "", 1); err != nil {
+		t.Fatalf("CreateGateway() error = %v", err)
+	}
+	pool.SetHTTPSOnly(true)
+	if _, err := pool.CreateGateway(ctx, "internet-facing", "", 2); err != nil {
+		t.Fatalf("CreateGateway() error = %v", err)
+	}
+	// An annotation overrides the pool setting
+	if _, err := pool.CreateGatewayWithMetadata(ctx, "internet-facing", "", 3, nil, map[string]string{AnnotationHTTPSOnly: "false"}); err != nil {
+		t.Fatalf("CreateGatewayWithMetadata() error = %v", err)
+	}
+
+	for name, want := range map[string]int{"gw-01": 2, "gw-02": 1, "gw-03": 2} {
+		var gw gwapiv1.Gateway
+		if err := client.Get(ctx, types.NamespacedName{Name: name, Namespace: "edge"}, &gw); err != nil {
+			t.Fatalf("gateway %s not created: %v", name, err)
+		}
+		if len(gw.Spec.Listeners) != want {
+			t.Errorf("%s listener count = %d, want %d", name, len(gw.Spec.Listeners), want)
+		}
+		if IsHTTPSOnly(&gw) != (want == 1) {
+			t.Errorf("IsHTTPSOnly(%s) = %v, want %v", name, IsHTTPSOnly(&gw), want == 1)
+		}
+	}
+
+	// Plain HTTP hostnames skip the HTTPS-only Gateway
+	var gw gwapiv1.Gateway
+	if err := client.Get(ctx, types.NamespacedName{Name: "gw-01", Namespace: "edge"}, &gw); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	gw.Annotations[AnnotationHTTPSOnly] = "true"
+	if err := client.Update(ctx, &gw); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	got, err := pool.SelectHTTPGateway(ctx, "internet-facing", "", nil)
+	if err != nil || got == nil || got.Name != "gw-03" {
+		t.Errorf("SelectHTTPGateway() = %v, %v; want gw-03", got, err)
+	}
+	got, err = pool.SelectGateway(ctx, "internet-facing", "", nil)
+	if err != nil || got == nil || got.Name != "gw-01" {
+		t.Errorf("SelectGateway() = %v, %v; want gw-01", got, err)
+	}
+}
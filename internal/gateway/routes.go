@@ -0,0 +1,41 @@
+package gateway
+
+import (
+	"k8s.io/apimachinery/pkg/types"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// RouteParents returns the Gateways an HTTPRoute references as parents, each once
+func RouteParents(route *gwapiv1.HTTPRoute) []types.NamespacedName {
+	var parents []types.NamespacedName
+	seen := map[types.NamespacedName]bool{}
+	for _, ref := range route.Spec.ParentRefs {
+		if ref.Kind != nil && *ref.Kind != "Gateway" {
+			continue
+		}
+		key := types.NamespacedName{Namespace: route.Namespace, Name: string(ref.Name)}
+		if ref.Namespace != nil {
+			key.Namespace = string(*ref.Namespace)
+		}
+		if !seen[key] {
+			seen[key] = true
+			parents = append(parents, key)
+		}
+	}
+	return parents
+}
+
+// CountRoutes returns how many HTTPRoutes each namespace attaches to each Gateway. A route
+// with several parentRefs to the same Gateway counts once.
+func CountRoutes(routes []gwapiv1.HTTPRoute) map[types.NamespacedName]map[string]int {
+	counts := map[types.NamespacedName]map[string]int{}
+	for i := range routes {
+		for _, gw := range RouteParents(&routes[i]) {
+			if counts[gw] == nil {
+				counts[gw] = map[string]int{}
+			}
+			counts[gw][routes[i].Namespace]++
+		}
+	}
+	return counts
+}
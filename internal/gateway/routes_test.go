@@ -0,0 +1,42 @@
+package gateway
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestCountRoutes(t *testing.T) {
+	edge := gwapiv1.Namespace("edge")
+	service := gwapiv1.Kind("Service")
+	route := func(namespace, name string, refs ...gwapiv1.ParentReference) gwapiv1.HTTPRoute {
+		return gwapiv1.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       gwapiv1.HTTPRouteSpec{CommonRouteSpec: gwapiv1.CommonRouteSpec{ParentRefs: refs}},
+		}
+	}
+	gw01 := gwapiv1.ParentReference{Name: "gw-01", Namespace: &edge}
+	gw01Section := gwapiv1.ParentReference{Name: "gw-01", Namespace: &edge, SectionName: ptrTo(gwapiv1.SectionName("https"))}
+	gw02 := gwapiv1.ParentReference{Name: "gw-02", Namespace: &edge}
+
+	counts := CountRoutes([]gwapiv1.HTTPRoute{
+		route("team-a", "a1", gw01, gw01Section),
+		route("team-a", "a2", gw01, gw02),
+		route("team-b", "b1", gw01),
+		route("team-b", "mesh", gwapiv1.ParentReference{Name: "svc", Kind: &service}),
+		route("edge", "local", gwapiv1.ParentReference{Name: "gw-02"}),
+	})
+
+	gwKey := func(name string) types.NamespacedName { return types.NamespacedName{Namespace: "edge", Name: name} }
+	if got := counts[gwKey("gw-01")]; got["team-a"] != 2 || got["team-b"] != 1 || len(got) != 2 {
+		t.Errorf("gw-01 counts = %v, want team-a=2 team-b=1", got)
+	}
+	if got := counts[gwKey("gw-02")]; got["team-a"] != 1 || got["edge"] != 1 || len(got) != 2 {
+		t.Errorf("gw-02 counts = %v, want team-a=1 edge=1", got)
+	}
+	if len(counts) != 2 {
+		t.Errorf("counts = %v, want only Gateway parents", counts)
+	}
+}
@@ -0,0 +1,179 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Event types
+const (
+	EventHostnameReady     = "HostnameReady"
+	EventCertificateFailed = "CertificateFailed"
+	EventClaimConflict     = "ClaimConflict"
+	EventGatewayCreated    = "GatewayCreated"
+	EventGatewayDeleted    = "GatewayDeleted"
+	EventGatewayFailed     = "GatewayFailed"
+)
+
+// EventTypes lists all event types, for validating filters
+var EventTypes = []string{EventHostnameReady, EventCertificateFailed, EventClaimConflict, EventGatewayCreated, EventGatewayDeleted, EventGatewayFailed}
+
+// Delivery results used as the "result" label on notificationsTotal
+const (
+	ResultDelivered = "delivered"
+	ResultFailed    = "failed"
+	ResultDropped   = "dropped"
+)
+
+// DefaultQueueSize is the number of events buffered while sinks are slow
+const DefaultQueueSize = 256
+
+// deliveryTimeout bounds a single delivery to a sink
+const deliveryTimeout = 10 * time.Second
+
+var notificationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "gateway_orchestrator_notifications_total",
+	Help: "Lifecycle notifications per sink and result: delivered, failed, or dropped because the queue was full.",
+}, []string{"sink", "result"})
+
+func init() {
+	metrics.Registry.MustRegister(notificationsTotal)
+}
+
+// Event is a lifecycle event sent to the configured sinks
+type Event struct {
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+
+	// Hostname, Namespace and Name identify the GatewayHostnameRequest, if any
+	Hostname  string `json:"hostname,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+
+	// Gateway is the namespace/name of the Gateway involved, if any
+	Gateway string `json:"gateway,omitempty"`
+
+	Message string `json:"message"`
+}
+
+// Summary returns a one-line, human-readable description of the event
+func (e Event) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s]", e.Type)
+	if e.Hostname != "" {
+		fmt.Fprintf(&b, " %s (%s/%s)", e.Hostname, e.Namespace, e.Name)
+	}
+	if e.Gateway != "" {
+		fmt.Fprintf(&b, " gateway %s", e.Gateway)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	return b.String()
+}
+
+// Sink delivers events to an external system
+type Sink interface {
+	// Name identifies the sink in logs and metrics
+	Name() string
+
+	Send(ctx context.Context, event Event) error
+}
+
+// Dispatcher queues events and delivers them to every sink in the background, so a slow or
+// unreachable endpoint never blocks reconciliation. Events are dropped when the queue is full.
+// It runs as a manager Runnable; a nil Dispatcher discards events.
+type Dispatcher struct {
+	sinks  []Sink
+	events map[string]bool
+	queue  chan Event
+}
+
+// NewDispatcher creates a dispatcher for the sinks. If eventTypes is empty, all events are sent.
+func NewDispatcher(sinks []Sink, eventTypes []string, queueSize int) *Dispatcher {
+	if queueSize <= 0 {
+		queueSize = DefaultQueueSize
+	}
+	d := &Dispatcher{sinks: sinks, queue: make(chan Event, queueSize)}
+	if len(eventTypes) > 0 {
+		d.events = make(map[string]bool, len(eventTypes))
+		for _, t := range eventTypes {
+			d.events[t] = true
+		}
+	}
+	return d
+}
+
+// ParseEventTypes parses a comma-separated list of event types
+func ParseEventTypes(s string) ([]string, error) {
+	var types []string
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		known := false
+		for _, t := range EventTypes {
+			if t == entry {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown event type %q, expected one of %s", entry, strings.Join(EventTypes, ", "))
+		}
+		types = append(types, entry)
+	}
+	return types, nil
+}
+
+// Notify queues the event without blocking
+func (d *Dispatcher) Notify(event Event) {
+	if d == nil || len(d.sinks) == 0 || (d.events != nil && !d.events[event.Type]) {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	select {
+	case d.queue <- event:
+	default:
+		for _, sink := range d.sinks {
+			notificationsTotal.WithLabelValues(sink.Name(), ResultDropped).Inc()
+		}
+	}
+}
+
+// Start implements manager.Runnable
+func (d *Dispatcher) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-d.queue:
+			d.deliver(ctx, event)
+		}
+	}
+}
+
+// deliver sends the event to every sink; failures are logged and counted, not retried
+func (d *Dispatcher) deliver(ctx context.Context, event Event) {
+	logger := log.FromContext(ctx).WithName("notify")
+	for _, sink := range d.sinks {
+		sendCtx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+		err := sink.Send(sendCtx, event)
+		cancel()
+		if err != nil {
+			notificationsTotal.WithLabelValues(sink.Name(), ResultFailed).Inc()
+			logger.Error(err, "Failed to deliver notification", "sink", sink.Name(), "type", event.Type)
+			continue
+		}
+		notificationsTotal.WithLabelValues(sink.Name(), ResultDelivered).Inc()
+	}
+}
@@ -0,0 +1,136 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink records delivered events and optionally fails
+type recordingSink struct {
+	name   string
+	events []Event
+	err    error
+}
+
+func (s *recordingSink) Name() string { return s.name }
+
+func (s *recordingSink) Send(ctx context.Context, event Event) error {
+	s.events = append(s.events, event)
+	return s.err
+}
+
+func TestDispatcher_FiltersAndDelivers(t *testing.T) {
+	ok := &recordingSink{name: "test-ok"}
+	failing := &recordingSink{name: "test-failing", err: errors.New("unreachable")}
+	d := NewDispatcher([]Sink{ok, failing}, []string{EventHostnameReady}, 0)
+
+	d.Notify(Event{Type: EventHostnameReady, Hostname: "app.example.com"})
+	d.Notify(Event{Type: EventGatewayCreated, Gateway: "edge/gw-01"})
+	require.Len(t, d.queue, 1)
+
+	d.deliver(context.Background(), <-d.queue)
+	require.Len(t, ok.events, 1)
+	assert.Equal(t, "app.example.com", ok.events[0].Hostname)
+	assert.False(t, ok.events[0].Time.IsZero())
+	assert.Len(t, failing.events, 1)
+	assert.Equal(t, 1.0, testutil.ToFloat64(notificationsTotal.WithLabelValues("test-ok", ResultDelivered)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(notificationsTotal.WithLabelValues("test-failing", ResultFailed)))
+}
+
+func TestDispatcher_DropsWhenFull(t *testing.T) {
+	sink := &recordingSink{name: "test-full"}
+	d := NewDispatcher([]Sink{sink}, nil, 1)
+
+	d.Notify(Event{Type: EventGatewayCreated})
+	d.Notify(Event{Type: EventGatewayDeleted})
+	assert.Len(t, d.queue, 1)
+	assert.Equal(t, 1.0, testutil.ToFloat64(notificationsTotal.WithLabelValues("test-full", ResultDropped)))
+
+	// A nil dispatcher discards events
+	var nilDispatcher *Dispatcher
+	nilDispatcher.Notify(Event{Type: EventGatewayCreated})
+}
+
+func TestParseEventTypes(t *testing.T) {
+	types, err := ParseEventTypes(" HostnameReady, ClaimConflict ,")
+	require.NoError(t, err)
+	assert.Equal(t, []string{EventHostnameReady, EventClaimConflict}, types)
+
+	types, err = ParseEventTypes("")
+	require.NoError(t, err)
+	assert.Empty(t, types)
+
+	_, err = ParseEventTypes("HostnameReady,Unknown")
+	assert.Error(t, err)
+}
+
+func TestWebhookAndSlack(t *testing.T) {
+	var bodies []map[string]interface{}
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	event := Event{
+		Type:      EventCertificateFailed,
+		Time:      time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Hostname:  "app.example.com",
+		Namespace: "team-a",
+		Name:      "app",
+		Message:   "certificate in failed state: FAILED",
+	}
+
+	require.NoError(t, (&Webhook{URL: server.URL}).Send(context.Background(), event))
+	require.NoError(t, (&Slack{URL: server.URL}).Send(context.Background(), event))
+	require.Len(t, bodies, 2)
+	assert.Equal(t, "CertificateFailed", bodies[0]["type"])
+	assert.Equal(t, "app.example.com", bodies[0]["hostname"])
+	assert.Equal(t, "2026-01-02T03:04:05Z", bodies[0]["time"])
+	assert.NotContains(t, bodies[0], "gateway")
+	assert.Equal(t, "[CertificateFailed] app.example.com (team-a/app): certificate in failed state: FAILED", bodies[1]["text"])
+
+	status = http.StatusInternalServerError
+	assert.Error(t, (&Webhook{URL: server.URL}).Send(context.Background(), event))
+}
+
+// fakePublisher records SNS publish requests
+type fakePublisher struct {
+	inputs []*sns.PublishInput
+}
+
+func (f *fakePublisher) Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error) {
+	f.inputs = append(f.inputs, params)
+	return &sns.PublishOutput{}, nil
+}
+
+func TestSNS(t *testing.T) {
+	publisher := &fakePublisher{}
+	sink := &SNS{TopicArn: "arn:aws:sns:us-east-1:123456789012:gateway-events", Client: publisher}
+
+	require.NoError(t, sink.Send(context.Background(), Event{Type: EventGatewayDeleted, Gateway: "edge/gw-02"}))
+	require.Len(t, publisher.inputs, 1)
+	input := publisher.inputs[0]
+	assert.Equal(t, "arn:aws:sns:us-east-1:123456789012:gateway-events", *input.TopicArn)
+	assert.Equal(t, "[GatewayDeleted] gateway edge/gw-02", *input.Subject)
+	assert.Equal(t, "GatewayDeleted", *input.MessageAttributes["type"].StringValue)
+
+	var event Event
+	require.NoError(t, json.Unmarshal([]byte(*input.Message), &event))
+	assert.Equal(t, "edge/gw-02", event.Gateway)
+}
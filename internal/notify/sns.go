@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+)
+
+// SNSPublisher is the subset of the SNS API used by the SNS sink
+type SNSPublisher interface {
+	Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error)
+}
+
+// SNS publishes each event as JSON to an SNS topic. The event type is set as the
+// "type" message attribute so subscriptions can filter on it.
+type SNS struct {
+	TopicArn string
+	Client   SNSPublisher
+}
+
+// NewSNS creates an SNS sink using the provided AWS config
+func NewSNS(cfg aws.Config, topicArn string) *SNS {
+	return &SNS{
+		TopicArn: topicArn,
+		Client:   sns.NewFromConfig(cfg),
+	}
+}
+
+func (s *SNS) Name() string {
+	return "sns"
+}
+
+func (s *SNS) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	_, err = s.Client.Publish(ctx, &sns.PublishInput{
+		TopicArn: aws.String(s.TopicArn),
+		Subject:  aws.String(truncate(event.Summary(), 100)),
+		Message:  aws.String(string(body)),
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			"type": {DataType: aws.String("String"), StringValue: aws.String(event.Type)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", s.TopicArn, err)
+	}
+	return nil
+}
+
+// truncate shortens s to at most n bytes; SNS subjects are limited to 100 characters
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Webhook POSTs each event as JSON to a URL
+type Webhook struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+func (w *Webhook) Name() string {
+	return "webhook"
+}
+
+func (w *Webhook) Send(ctx context.Context, event Event) error {
+	return postJSON(ctx, w.HTTPClient, w.URL, event)
+}
+
+// Slack posts a one-line summary of each event to a Slack incoming webhook
+type Slack struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+func (s *Slack) Name() string {
+	return "slack"
+}
+
+func (s *Slack) Send(ctx context.Context, event Event) error {
+	return postJSON(ctx, s.HTTPClient, s.URL, map[string]string{"text": event.Summary()})
+}
+
+// postJSON POSTs the payload and treats any non-2xx response as an error
+func postJSON(ctx context.Context, httpClient *http.Client, url string, payload interface{}) error {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notification endpoint returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+// Package plugin implements the kubectl gwo plugin commands, which summarize the orchestrator's
+// resources without joining them by hand
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+// PoolRow is one managed Gateway in the pool summary
+type PoolRow struct {
+	Gateway         string
+	Visibility      string
+	Waf             string
+	Certificates    string
+	Rules           string
+	LoadBalancerDNS string
+	Age             string
+}
+
+// PoolRows joins the GatewayPool summaries with the Gateways they list, sorted by pool name and
+// then by Gateway namespace and name. The summaries carry the counts and limits, the Gateways
+// their age.
+func PoolRows(ctx context.Context, c client.Reader, now time.Time) ([]PoolRow, error) {
+	var pools gatewayv1alpha1.GatewayPoolList
+	if err := c.List(ctx, &pools); err != nil {
+		return nil, fmt.Errorf("failed to list GatewayPools: %w", err)
+	}
+
+	sort.Slice(pools.Items, func(i, j int) bool { return pools.Items[i].Name < pools.Items[j].Name })
+
+	var rows []PoolRow
+	for _, pool := range pools.Items {
+		var poolRows []PoolRow
+		for _, summary := range pool.Status.Gateways {
+			namespace := summary.Namespace
+			if namespace == "" {
+				namespace = pool.Status.GatewayNamespace
+			}
+			age := "<unknown>"
+			var gw gwapiv1.Gateway
+			if err := c.Get(ctx, types.NamespacedName{Name: summary.Name, Namespace: namespace}, &gw); err == nil {
+				age = duration.HumanDuration(now.Sub(gw.CreationTimestamp.Time))
+			} else if client.IgnoreNotFound(err) != nil {
+				return nil, fmt.Errorf("failed to get Gateway %s/%s: %w", namespace, summary.Name, err)
+			}
+
+			poolRows = append(poolRows, PoolRow{
+				Gateway:         namespace + "/" + summary.Name,
+				Visibility:      orNone(summary.Visibility),
+				Waf:             wafName(summary.WafArn),
+				Certificates:    usage(summary.CertificateCount, pool.Status.MaxCertificatesPerGateway),
+				Rules:           usage(summary.RuleCount, gateway.MaxRulesPerGateway),
+				LoadBalancerDNS: orNone(summary.LoadBalancerDNS),
+				Age:             age,
+			})
+		}
+		sort.Slice(poolRows, func(i, j int) bool { return poolRows[i].Gateway < poolRows[j].Gateway })
+		rows = append(rows, poolRows...)
+	}
+	return rows, nil
+}
+
+// WritePoolTable prints the rows as a kubectl-style table
+func WritePoolTable(w io.Writer, rows []PoolRow) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(tw, "GATEWAY\tVISIBILITY\tWAF\tCERTIFICATES\tRULES\tLOAD BALANCER\tAGE")
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			row.Gateway, row.Visibility, row.Waf, row.Certificates, row.Rules, row.LoadBalancerDNS, row.Age)
+	}
+	return tw.Flush()
+}
+
+// wafName shortens a WebACL ARN (arn:aws:wafv2:<region>:<account>:regional/webacl/<name>/<id>)
+// to its name
+func wafName(arn string) string {
+	if arn == "" {
+		return "<none>"
+	}
+	if _, rest, ok := strings.Cut(arn, ":regional/webacl/"); ok {
+		name, _, _ := strings.Cut(rest, "/")
+		return name
+	}
+	return arn
+}
+
+// usage formats a count against its limit, e.g. 12/25; a limit of 0 is left out
+func usage(count, limit int) string {
+	if limit <= 0 {
+		return strconv.Itoa(count)
+	}
+	return fmt.Sprintf("%d/%d", count, limit)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
@@ -0,0 +1,89 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+func TestPoolRows(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, gatewayv1alpha1.AddToScheme(scheme))
+	require.NoError(t, gwapiv1.Install(scheme))
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	pool := &gatewayv1alpha1.GatewayPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "edge"},
+		Status: gatewayv1alpha1.GatewayPoolStatus{
+			GatewayNamespace:          "edge",
+			MaxCertificatesPerGateway: 25,
+			Gateways: []gatewayv1alpha1.PoolGatewayStatus{
+				// Deleted since the pool was last summarized
+				{Name: "gw-02", Visibility: "internal", CertificateCount: 0, RuleCount: 0},
+				{
+					Name: "gw-01", Namespace: "edge", Visibility: "internet-facing",
+					WafArn:           "arn:aws:wafv2:us-east-1:123456789012:regional/webacl/edge-waf/0a1b2c3d",
+					CertificateCount: 12, RuleCount: 37,
+					LoadBalancerDNS: "k8s-gw01-abcdef1234-1234567890.us-east-1.elb.amazonaws.com",
+				},
+			},
+		},
+	}
+	// Its Gateways follow those of edge
+	internal := &gatewayv1alpha1.GatewayPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "edge-internal"},
+		Status: gatewayv1alpha1.GatewayPoolStatus{
+			GatewayNamespace: "edge-internal",
+			Gateways:         []gatewayv1alpha1.PoolGatewayStatus{{Name: "gw-03", Visibility: "internal"}},
+		},
+	}
+	gw := &gwapiv1.Gateway{ObjectMeta: metav1.ObjectMeta{
+		Name: "gw-01", Namespace: "edge", CreationTimestamp: metav1.NewTime(now.Add(-50 * time.Hour)),
+	}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(internal, pool, gw).Build()
+
+	rows, err := PoolRows(context.Background(), c, now)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, "edge/gw-01", rows[0].Gateway)
+	assert.Equal(t, "edge/gw-02", rows[1].Gateway)
+	assert.Equal(t, "edge-internal/gw-03", rows[2].Gateway)
+	rows = rows[:2]
+	assert.Equal(t, PoolRow{
+		Gateway:         "edge/gw-01",
+		Visibility:      "internet-facing",
+		Waf:             "edge-waf",
+		Certificates:    "12/25",
+		Rules:           "37/100",
+		LoadBalancerDNS: "k8s-gw01-abcdef1234-1234567890.us-east-1.elb.amazonaws.com",
+		Age:             "2d2h",
+	}, rows[0])
+	assert.Equal(t, PoolRow{
+		Gateway: "edge/gw-02", Visibility: "internal", Waf: "<none>", Certificates: "0/25", Rules: "0/100",
+		LoadBalancerDNS: "<none>", Age: "<unknown>",
+	}, rows[1])
+
+	var out bytes.Buffer
+	require.NoError(t, WritePoolTable(&out, rows))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, []string{"GATEWAY", "VISIBILITY", "WAF", "CERTIFICATES", "RULES", "LOAD", "BALANCER", "AGE"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"edge/gw-01", "internet-facing", "edge-waf", "12/25", "37/100",
+		"k8s-gw01-abcdef1234-1234567890.us-east-1.elb.amazonaws.com", "2d2h"}, strings.Fields(lines[1]))
+}
+
+func TestUsage(t *testing.T) {
+	assert.Equal(t, "12/25", usage(12, 25))
+	assert.Equal(t, "12", usage(12, 0))
+}
@@ -0,0 +1,114 @@
+// Package policy asks an external webhook whether a hostname request may be provisioned, so
+// company-specific rules (trademark lists, naming conventions) can veto or annotate requests
+// without changing the controller.
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// Failure policies: what happens to a request when the webhook can't be reached or answers
+// with an error
+const (
+	// FailurePolicyFail keeps the request waiting and retries (default)
+	FailurePolicyFail = "Fail"
+	// FailurePolicyIgnore provisions the request as if the webhook allowed it
+	FailurePolicyIgnore = "Ignore"
+)
+
+// DefaultTimeout bounds a single webhook call
+const DefaultTimeout = 5 * time.Second
+
+// Review is the JSON body POSTed to the webhook for every request before its hostname is claimed
+type Review struct {
+	Namespace   string                                     `json:"namespace"`
+	Name        string                                     `json:"name"`
+	Labels      map[string]string                          `json:"labels,omitempty"`
+	Annotations map[string]string                          `json:"annotations,omitempty"`
+	Spec        gatewayv1alpha1.GatewayHostnameRequestSpec `json:"spec"`
+}
+
+// NewReview builds the review of a request
+func NewReview(ghr *gatewayv1alpha1.GatewayHostnameRequest) Review {
+	return Review{
+		Namespace:   ghr.Namespace,
+		Name:        ghr.Name,
+		Labels:      ghr.Labels,
+		Annotations: ghr.Annotations,
+		Spec:        ghr.Spec,
+	}
+}
+
+// Decision is the webhook's JSON response
+type Decision struct {
+	// Allowed lets the request be provisioned
+	Allowed bool `json:"allowed"`
+
+	// Reason explains a denial to the request owner; it ends up in status.message
+	Reason string `json:"reason,omitempty"`
+
+	// Annotations are added to the request, e.g. the owning cost center or a review ticket.
+	// Keys under gateway.opendi.com/ are reserved for the controller and ignored.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Checker decides whether a request may be provisioned
+type Checker interface {
+	Check(ctx context.Context, review Review) (Decision, error)
+}
+
+// Webhook POSTs the review as JSON to a URL and expects a Decision in return
+type Webhook struct {
+	URL        string
+	HTTPClient *http.Client
+
+	// Timeout bounds a single call (default DefaultTimeout)
+	Timeout time.Duration
+}
+
+func (w *Webhook) Check(ctx context.Context, review Review) (Decision, error) {
+	httpClient := w.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	timeout := w.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	body, err := json.Marshal(review)
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to encode policy review: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to call policy webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return Decision{}, fmt.Errorf("policy webhook returned %s", resp.Status)
+	}
+	var decision Decision
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&decision); err != nil {
+		return Decision{}, fmt.Errorf("failed to decode policy decision: %w", err)
+	}
+	return decision, nil
+}
@@ -0,0 +1,52 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+func TestWebhook_Check(t *testing.T) {
+	var received Review
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		_ = json.NewEncoder(w).Encode(Decision{
+			Allowed:     false,
+			Reason:      "contoso is a protected trademark",
+			Annotations: map[string]string{"policy.example.com/ticket": "LEGAL-42"},
+		})
+	}))
+	defer server.Close()
+
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "team-a", Labels: map[string]string{"team": "a"}},
+		Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "contoso.example.com", ZoneId: "Z123"},
+	}
+	decision, err := (&Webhook{URL: server.URL}).Check(context.Background(), NewReview(ghr))
+	require.NoError(t, err)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, "contoso is a protected trademark", decision.Reason)
+	assert.Equal(t, "LEGAL-42", decision.Annotations["policy.example.com/ticket"])
+
+	assert.Equal(t, "team-a", received.Namespace)
+	assert.Equal(t, "contoso.example.com", received.Spec.Hostname)
+	assert.Equal(t, "a", received.Labels["team"])
+}
+
+func TestWebhook_CheckError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := (&Webhook{URL: server.URL}).Check(context.Background(), Review{})
+	assert.ErrorContains(t, err, "503")
+}
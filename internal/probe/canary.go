@@ -0,0 +1,212 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/controller"
+)
+
+// CanaryName is the name of the GatewayHostnameRequest maintained by the canary
+const CanaryName = "gateway-orchestrator-canary"
+
+// Canary cycle results used as the "result" label on canaryCycles
+const (
+	CanaryResultReady   = "ready"
+	CanaryResultTimeout = "timeout"
+)
+
+var (
+	canaryReady = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gateway_orchestrator_canary_ready",
+		Help: "Whether the canary hostname is Ready (1) or not (0).",
+	})
+
+	canaryReachable = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gateway_orchestrator_canary_reachable",
+		Help: "Whether the last HTTPS probe of the Ready canary hostname succeeded (1) or failed (0).",
+	})
+
+	canaryPending = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "gateway_orchestrator_canary_pending_seconds",
+		Help: "Time the current canary hostname has been waiting to become Ready, 0 once Ready.",
+	})
+
+	canaryProvisioning = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "gateway_orchestrator_canary_provisioning_duration_seconds",
+		Help:    "Time from creating the canary hostname to Ready (certificate, DNS and Gateway).",
+		Buckets: prometheus.ExponentialBuckets(30, 2, 8),
+	})
+
+	canaryCycles = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gateway_orchestrator_canary_cycles_total",
+		Help: "Canary provisioning cycles by result: ready or timeout.",
+	}, []string{"result"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(canaryReady, canaryReachable, canaryPending, canaryProvisioning, canaryCycles)
+}
+
+// Canary maintains one synthetic GatewayHostnameRequest end to end as a continuous self-test
+// of the AWS pipeline. It measures how long the hostname takes to become Ready, probes it once
+// Ready, and deletes it after RecycleAfter so the next cycle provisions it from scratch.
+// Like the Prober it runs only on the leader.
+type Canary struct {
+	Client    client.Client
+	Namespace string
+	Hostname  string
+	ZoneID    string
+
+	Interval time.Duration
+
+	// RecycleAfter is how long a Ready canary is kept before it is re-provisioned (0 keeps it)
+	RecycleAfter time.Duration
+
+	// ProvisionTimeout is how long the canary may take to become Ready before the cycle
+	// is counted as timed out
+	ProvisionTimeout time.Duration
+
+	// Resolver, HTTPClient and Timeout configure the reachability probe, as for the Prober
+	Resolver   Resolver
+	HTTPClient *http.Client
+	Timeout    time.Duration
+
+	// recorded is set once the current request's cycle result has been counted
+	recorded bool
+}
+
+// Start implements manager.Runnable
+func (c *Canary) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("canary")
+	logger.Info("Starting canary", "hostname", c.Hostname, "namespace", c.Namespace,
+		"interval", c.Interval, "recycleAfter", c.RecycleAfter)
+
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.Check(ctx); err != nil {
+			logger.Error(err, "Canary check failed")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check advances the canary by one step: it creates the request if missing, records the
+// provisioning result once per request, probes the Ready hostname and recycles it when due.
+func (c *Canary) Check(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("canary")
+
+	var ghr gatewayv1alpha1.GatewayHostnameRequest
+	err := c.Client.Get(ctx, types.NamespacedName{Name: CanaryName, Namespace: c.Namespace}, &ghr)
+	if apierrors.IsNotFound(err) {
+		canaryReady.Set(0)
+		canaryReachable.Set(0)
+		canaryPending.Set(0)
+		return c.create(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get canary GatewayHostnameRequest: %w", err)
+	}
+
+	// Cleanup of the previous cycle is still running
+	if !ghr.DeletionTimestamp.IsZero() {
+		canaryReady.Set(0)
+		canaryReachable.Set(0)
+		return nil
+	}
+
+	if ghr.Spec.Hostname != c.Hostname || ghr.Spec.ZoneId != c.ZoneID {
+		logger.Info("Canary configuration changed, re-provisioning", "hostname", c.Hostname)
+		return c.recycle(ctx, &ghr)
+	}
+
+	ready := conditions.Get(ghr.Status.Conditions, conditions.TypeReady)
+	if ready == nil || ready.Status != metav1.ConditionTrue {
+		pending := time.Since(ghr.CreationTimestamp.Time)
+		canaryReady.Set(0)
+		canaryReachable.Set(0)
+		canaryPending.Set(pending.Seconds())
+		if c.ProvisionTimeout > 0 && pending > c.ProvisionTimeout && !c.recorded {
+			c.recorded = true
+			canaryCycles.WithLabelValues(CanaryResultTimeout).Inc()
+			logger.Info("Canary hostname did not become Ready in time", "hostname", ghr.Spec.Hostname,
+				"pending", pending.Round(time.Second), "timeout", c.ProvisionTimeout)
+		}
+		return nil
+	}
+
+	canaryReady.Set(1)
+	canaryPending.Set(0)
+	if !c.recorded {
+		c.recorded = true
+		duration := ready.LastTransitionTime.Sub(ghr.CreationTimestamp.Time)
+		canaryProvisioning.Observe(duration.Seconds())
+		canaryCycles.WithLabelValues(CanaryResultReady).Inc()
+		logger.Info("Canary hostname is Ready", "hostname", ghr.Spec.Hostname, "duration", duration.Round(time.Second))
+	}
+
+	prober := &Prober{Resolver: c.Resolver, HTTPClient: c.HTTPClient, Timeout: c.Timeout}
+	probeCtx, cancel := context.WithTimeout(ctx, prober.timeout())
+	result := prober.check(probeCtx, probeScheme(&ghr), ghr.Spec.Hostname)
+	cancel()
+	if result == ResultSuccess {
+		canaryReachable.Set(1)
+	} else {
+		canaryReachable.Set(0)
+		logger.Info("Canary hostname probe failed", "hostname", ghr.Spec.Hostname, "result", result)
+	}
+
+	if c.RecycleAfter > 0 && time.Since(ready.LastTransitionTime.Time) >= c.RecycleAfter {
+		return c.recycle(ctx, &ghr)
+	}
+	return nil
+}
+
+// create submits a new canary request
+func (c *Canary) create(ctx context.Context) error {
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CanaryName,
+			Namespace: c.Namespace,
+			Labels:    map[string]string{controller.LabelManagedBy: "gateway-orchestrator"},
+		},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+			Hostname: c.Hostname,
+			ZoneId:   c.ZoneID,
+		},
+	}
+	if err := c.Client.Create(ctx, ghr); err != nil {
+		return fmt.Errorf("failed to create canary GatewayHostnameRequest: %w", err)
+	}
+	c.recorded = false
+	log.FromContext(ctx).WithName("canary").Info("Created canary hostname", "hostname", c.Hostname)
+	return nil
+}
+
+// recycle deletes the canary request; the controller releases its certificate, DNS records and
+// Gateway assignment, and the next check provisions it again
+func (c *Canary) recycle(ctx context.Context, ghr *gatewayv1alpha1.GatewayHostnameRequest) error {
+	if err := c.Client.Delete(ctx, ghr); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete canary GatewayHostnameRequest: %w", err)
+	}
+	log.FromContext(ctx).WithName("canary").Info("Recycling canary hostname", "hostname", ghr.Spec.Hostname)
+	return nil
+}
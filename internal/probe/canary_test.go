@@ -0,0 +1,99 @@
+package probe
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/controller"
+)
+
+func TestCanary_Lifecycle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	p := newTestProber(t, server, fakeResolver{"canary.example.com": true})
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, gatewayv1alpha1.AddToScheme(scheme))
+	c := &Canary{
+		Client:       fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&gatewayv1alpha1.GatewayHostnameRequest{}).Build(),
+		Namespace:    "gateway-orchestrator-system",
+		Hostname:     "canary.example.com",
+		ZoneID:       "Z123",
+		RecycleAfter: time.Hour,
+		Resolver:     p.Resolver,
+		HTTPClient:   p.HTTPClient,
+	}
+	canaryCycles.Reset()
+	ctx := context.Background()
+	key := types.NamespacedName{Name: CanaryName, Namespace: c.Namespace}
+
+	// First check creates the request
+	require.NoError(t, c.Check(ctx))
+	var ghr gatewayv1alpha1.GatewayHostnameRequest
+	require.NoError(t, c.Client.Get(ctx, key, &ghr))
+	assert.Equal(t, "canary.example.com", ghr.Spec.Hostname)
+	assert.Equal(t, "Z123", ghr.Spec.ZoneId)
+
+	// Still provisioning
+	require.NoError(t, c.Check(ctx))
+	assert.Equal(t, 0.0, testutil.ToFloat64(canaryReady))
+
+	// Ready: the cycle is counted once and the hostname is probed
+	meta.SetStatusCondition(&ghr.Status.Conditions, metav1.Condition{
+		Type: controller.ConditionTypeReady, Status: metav1.ConditionTrue, Reason: "Ready",
+	})
+	require.NoError(t, c.Client.Status().Update(ctx, &ghr))
+	require.NoError(t, c.Check(ctx))
+	require.NoError(t, c.Check(ctx))
+	assert.Equal(t, 1.0, testutil.ToFloat64(canaryReady))
+	assert.Equal(t, 1.0, testutil.ToFloat64(canaryReachable))
+	assert.Equal(t, 1.0, testutil.ToFloat64(canaryCycles.WithLabelValues(CanaryResultReady)))
+
+	// Recycled once RecycleAfter has passed since Ready
+	c.RecycleAfter = time.Nanosecond
+	require.NoError(t, c.Check(ctx))
+	assert.True(t, apierrors.IsNotFound(c.Client.Get(ctx, key, &ghr)))
+}
+
+func TestCanary_ProvisionTimeout(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, gatewayv1alpha1.AddToScheme(scheme))
+	stuck := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              CanaryName,
+			Namespace:         "gateway-orchestrator-system",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+		},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "canary.example.com", ZoneId: "Z123"},
+	}
+	c := &Canary{
+		Client:           fake.NewClientBuilder().WithScheme(scheme).WithObjects(stuck).Build(),
+		Namespace:        "gateway-orchestrator-system",
+		Hostname:         "canary.example.com",
+		ZoneID:           "Z123",
+		ProvisionTimeout: 30 * time.Minute,
+	}
+	canaryCycles.Reset()
+
+	require.NoError(t, c.Check(context.Background()))
+	require.NoError(t, c.Check(context.Background()))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(canaryCycles.WithLabelValues(CanaryResultTimeout)))
+	assert.Greater(t, testutil.ToFloat64(canaryPending), 1800.0)
+}
@@ -0,0 +1,224 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/sync/errgroup"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/controller"
+)
+
+// Probe results used as the "result" label on probeTotal
+const (
+	ResultSuccess   = "success"
+	ResultDNSError  = "dns_error"
+	ResultHTTPError = "http_error"
+	ResultBadStatus = "bad_status"
+)
+
+var (
+	probeSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gateway_orchestrator_probe_success",
+		Help: "Whether the last reachability probe for a hostname succeeded (1) or failed (0).",
+	}, []string{"hostname"})
+
+	probeDuration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gateway_orchestrator_probe_duration_seconds",
+		Help: "Duration of the last reachability probe for a hostname, including DNS resolution.",
+	}, []string{"hostname"})
+
+	probeTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gateway_orchestrator_probe_total",
+		Help: "Reachability probes performed per hostname and result.",
+	}, []string{"hostname", "result"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(probeSuccess, probeDuration, probeTotal)
+}
+
+// DefaultConcurrency is how many hostnames are probed at once unless Prober.Concurrency is set
+const DefaultConcurrency = 20
+
+// Resolver resolves hostnames; satisfied by *net.Resolver
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// Prober periodically resolves every Ready hostname and sends a HEAD request to it, over HTTPS
+// or, for requests with tls: disabled, plain HTTP, exporting the results as Prometheus metrics. It runs as a manager Runnable and only
+// on the leader, so replicas don't multiply the probe traffic.
+type Prober struct {
+	Client   client.Reader
+	Interval time.Duration
+	Timeout  time.Duration
+
+	// Concurrency bounds the probes in flight (default DefaultConcurrency), so large fleets
+	// don't open thousands of connections at once or skew the measured durations
+	Concurrency int
+
+	// Resolver and HTTPClient default to net.DefaultResolver and a client with Timeout
+	Resolver   Resolver
+	HTTPClient *http.Client
+
+	mu     sync.Mutex
+	probed map[string]bool
+}
+
+// Start implements manager.Runnable
+func (p *Prober) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("prober")
+	logger.Info("Starting hostname prober", "interval", p.Interval, "timeout", p.Timeout)
+
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.ProbeAll(ctx); err != nil {
+			logger.Error(err, "Probe round failed")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProbeAll probes every Ready hostname once and drops metrics for hostnames that are
+// no longer Ready.
+func (p *Prober) ProbeAll(ctx context.Context) error {
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := p.Client.List(ctx, &ghrList); err != nil {
+		return fmt.Errorf("failed to list GatewayHostnameRequests: %w", err)
+	}
+
+	current := make(map[string]bool)
+	schemes := make(map[string]string)
+	for _, ghr := range ghrList.Items {
+		if !ghr.DeletionTimestamp.IsZero() || !conditions.IsReady(ghr.Status.Conditions) {
+			continue
+		}
+		// Wildcards have no single name to resolve
+		if strings.HasPrefix(ghr.Spec.Hostname, "*.") {
+			continue
+		}
+		current[ghr.Spec.Hostname] = true
+		// HTTPS wins if any request for the hostname serves it
+		if schemes[ghr.Spec.Hostname] != "https" {
+			schemes[ghr.Spec.Hostname] = probeScheme(&ghr)
+		}
+	}
+
+	var g errgroup.Group
+	g.SetLimit(p.concurrency())
+	for hostname := range current {
+		g.Go(func() error {
+			p.probe(ctx, schemes[hostname], hostname)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for hostname := range p.probed {
+		if !current[hostname] {
+			probeSuccess.DeleteLabelValues(hostname)
+			probeDuration.DeleteLabelValues(hostname)
+			probeTotal.DeletePartialMatch(prometheus.Labels{"hostname": hostname})
+		}
+	}
+	p.probed = current
+
+	return nil
+}
+
+// probe resolves a hostname and sends a HEAD request to it with the scheme (https or http).
+// Any response below 500 counts as reachable: the edge accepted and routed the request.
+func (p *Prober) probe(ctx context.Context, scheme, hostname string) string {
+	logger := log.FromContext(ctx).WithName("prober")
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout())
+	defer cancel()
+
+	start := time.Now()
+	result := p.check(ctx, scheme, hostname)
+	elapsed := time.Since(start)
+
+	probeDuration.WithLabelValues(hostname).Set(elapsed.Seconds())
+	probeTotal.WithLabelValues(hostname, result).Inc()
+	if result == ResultSuccess {
+		probeSuccess.WithLabelValues(hostname).Set(1)
+	} else {
+		probeSuccess.WithLabelValues(hostname).Set(0)
+		logger.V(1).Info("Hostname probe failed", "hostname", hostname, "result", result)
+	}
+
+	return result
+}
+
+func (p *Prober) check(ctx context.Context, scheme, hostname string) string {
+	resolver := p.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	if _, err := resolver.LookupHost(ctx, hostname); err != nil {
+		return ResultDNSError
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, scheme+"://"+hostname+"/", nil)
+	if err != nil {
+		return ResultHTTPError
+	}
+
+	httpClient := p.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: p.timeout()}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return ResultHTTPError
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return ResultBadStatus
+	}
+	return ResultSuccess
+}
+
+// probeScheme is http for requests with tls: disabled, which have no HTTPS listener, and
+// https otherwise
+func probeScheme(ghr *gatewayv1alpha1.GatewayHostnameRequest) string {
+	if ghr.Spec.TLS == controller.TLSDisabled {
+		return "http"
+	}
+	return "https"
+}
+
+func (p *Prober) concurrency() int {
+	if p.Concurrency <= 0 {
+		return DefaultConcurrency
+	}
+	return p.Concurrency
+}
+
+func (p *Prober) timeout() time.Duration {
+	if p.Timeout <= 0 {
+		return 10 * time.Second
+	}
+	return p.Timeout
+}
@@ -0,0 +1,173 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/controller"
+)
+
+type fakeResolver map[string]bool
+
+func (f fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if f[host] {
+		return []string{"127.0.0.1"}, nil
+	}
+	return nil, errors.New("no such host")
+}
+
+// slowResolver fails every lookup after a delay and records how many lookups overlapped
+type slowResolver struct {
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+	calls    int
+}
+
+func (s *slowResolver) LookupHost(_ context.Context, _ string) ([]string, error) {
+	s.mu.Lock()
+	s.inFlight++
+	s.calls++
+	s.peak = max(s.peak, s.inFlight)
+	s.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	s.mu.Lock()
+	s.inFlight--
+	s.mu.Unlock()
+	return nil, errors.New("no such host")
+}
+
+func readyGHR(name, hostname string) *gatewayv1alpha1.GatewayHostnameRequest {
+	return &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: hostname},
+		Status: gatewayv1alpha1.GatewayHostnameRequestStatus{
+			Conditions: []metav1.Condition{{Type: controller.ConditionTypeReady, Status: metav1.ConditionTrue}},
+		},
+	}
+}
+
+// newTestProber returns a prober whose HTTP client sends every request to server
+func newTestProber(t *testing.T, server *httptest.Server, resolver fakeResolver, objs ...*gatewayv1alpha1.GatewayHostnameRequest) *Prober {
+	scheme := runtime.NewScheme()
+	require.NoError(t, gatewayv1alpha1.AddToScheme(scheme))
+
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, obj := range objs {
+		builder = builder.WithObjects(obj)
+	}
+
+	httpClient := server.Client()
+	transport := httpClient.Transport.(*http.Transport)
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	}
+
+	return &Prober{
+		Client:     builder.Build(),
+		Resolver:   resolver,
+		HTTPClient: httpClient,
+	}
+}
+
+func TestProber_ProbeResults(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host == "broken.example.com" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	p := newTestProber(t, server, fakeResolver{"ok.example.com": true, "broken.example.com": true})
+	ctx := context.Background()
+
+	assert.Equal(t, ResultSuccess, p.probe(ctx, "https", "ok.example.com"))
+	assert.Equal(t, ResultBadStatus, p.probe(ctx, "https", "broken.example.com"))
+	assert.Equal(t, ResultDNSError, p.probe(ctx, "https", "missing.example.com"))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(probeSuccess.WithLabelValues("ok.example.com")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(probeSuccess.WithLabelValues("broken.example.com")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(probeTotal.WithLabelValues("missing.example.com", ResultDNSError)))
+}
+
+func TestProber_ProbeAllSkipsNonReadyAndDropsStaleMetrics(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	pending := readyGHR("pending", "pending.example.com")
+	pending.Status.Conditions[0].Status = metav1.ConditionFalse
+
+	p := newTestProber(t, server, fakeResolver{"app.example.com": true, "pending.example.com": true},
+		readyGHR("app", "app.example.com"),
+		readyGHR("wildcard", "*.example.com"),
+		pending,
+	)
+	probeSuccess.Reset()
+	probeDuration.Reset()
+	probeTotal.Reset()
+
+	// Left over from a previous round for a hostname that is gone now
+	p.probed = map[string]bool{"gone.example.com": true}
+	probeSuccess.WithLabelValues("gone.example.com").Set(1)
+
+	require.NoError(t, p.ProbeAll(context.Background()))
+
+	assert.Equal(t, map[string]bool{"app.example.com": true}, p.probed)
+	assert.Equal(t, 1.0, testutil.ToFloat64(probeSuccess.WithLabelValues("app.example.com")))
+	// Only app.example.com remains; gone.example.com was dropped
+	assert.Equal(t, 1, testutil.CollectAndCount(probeSuccess))
+}
+
+func TestProber_ProbeAllUsesHTTPForHTTPOnlyHostnames(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	httpOnly := readyGHR("redirect", "redirect.example.com")
+	httpOnly.Spec.TLS = controller.TLSDisabled
+	p := newTestProber(t, server, fakeResolver{"redirect.example.com": true}, httpOnly)
+	probeSuccess.Reset()
+
+	require.NoError(t, p.ProbeAll(context.Background()))
+	assert.Equal(t, []string{http.MethodHead}, methods, "the plain HTTP server answered the probe")
+	assert.Equal(t, 1.0, testutil.ToFloat64(probeSuccess.WithLabelValues("redirect.example.com")))
+}
+
+func TestProber_ProbeAllBoundsConcurrency(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, gatewayv1alpha1.AddToScheme(scheme))
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+		builder = builder.WithObjects(readyGHR(name, name+".example.com"))
+	}
+	resolver := &slowResolver{}
+	p := &Prober{Client: builder.Build(), Resolver: resolver, Concurrency: 3}
+
+	require.NoError(t, p.ProbeAll(context.Background()))
+	assert.Equal(t, 10, resolver.calls)
+	assert.LessOrEqual(t, resolver.peak, 3)
+	assert.Greater(t, resolver.peak, 1, "hostnames are still probed in parallel")
+}
@@ -0,0 +1,144 @@
+package secondarydns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NS1Endpoint is the NS1 API base URL
+const NS1Endpoint = "https://api.nsone.net/v1"
+
+// DefaultTimeout bounds a single provider API call
+const DefaultTimeout = 10 * time.Second
+
+// NS1 writes records through the NS1 REST API
+type NS1 struct {
+	APIKey     string
+	HTTPClient *http.Client
+
+	// Endpoint is the API base URL (default NS1Endpoint)
+	Endpoint string
+}
+
+// ns1Record is the NS1 record body
+type ns1Record struct {
+	Zone    string      `json:"zone"`
+	Domain  string      `json:"domain"`
+	Type    string      `json:"type"`
+	TTL     int64       `json:"ttl,omitempty"`
+	Answers []ns1Answer `json:"answers"`
+}
+
+type ns1Answer struct {
+	Answer []string `json:"answer"`
+}
+
+func (p *NS1) Name() string {
+	return "ns1"
+}
+
+// UpsertRecord replaces the record's answers, creating the record if it doesn't exist.
+// NS1 creates with PUT and updates with POST.
+func (p *NS1) UpsertRecord(ctx context.Context, zone string, record Record) error {
+	body := ns1Record{Zone: zone, Domain: record.Name, Type: record.Type, TTL: record.TTL}
+	for _, value := range record.Values {
+		body.Answers = append(body.Answers, ns1Answer{Answer: ns1AnswerFields(record.Type, value)})
+	}
+
+	status, err := p.do(ctx, http.MethodPost, zone, record.Name, record.Type, body)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound {
+		status, err = p.do(ctx, http.MethodPut, zone, record.Name, record.Type, body)
+		if err != nil {
+			return err
+		}
+	}
+	if status < 200 || status >= 300 {
+		return fmt.Errorf("ns1 returned %d for %s %s", status, record.Type, record.Name)
+	}
+	return nil
+}
+
+func (p *NS1) DeleteRecord(ctx context.Context, zone string, name, recordType string) error {
+	status, err := p.do(ctx, http.MethodDelete, zone, name, recordType, nil)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound || (status >= 200 && status < 300) {
+		return nil
+	}
+	return fmt.Errorf("ns1 returned %d deleting %s %s", status, recordType, name)
+}
+
+// do calls /zones/<zone>/<name>/<type> and returns the response status
+func (p *NS1) do(ctx context.Context, method, zone, name, recordType string, body any) (int, error) {
+	httpClient := p.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	endpoint := p.Endpoint
+	if endpoint == "" {
+		endpoint = NS1Endpoint
+	}
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode ns1 record: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	u := fmt.Sprintf("%s/zones/%s/%s/%s", strings.TrimSuffix(endpoint, "/"),
+		url.PathEscape(zone), url.PathEscape(name), url.PathEscape(recordType))
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-NSONE-Key", p.APIKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call ns1: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+// ns1AnswerFields splits a Route53 value into NS1 answer fields: CAA values are
+// flags, tag and unquoted value; TXT values are unquoted; others are a single field
+func ns1AnswerFields(recordType, value string) []string {
+	switch recordType {
+	case "CAA":
+		fields := strings.SplitN(value, " ", 3)
+		if len(fields) == 3 {
+			return []string{fields[0], fields[1], unquote(fields[2])}
+		}
+	case "TXT":
+		return []string{unquote(value)}
+	}
+	return []string{value}
+}
+
+func unquote(s string) string {
+	if u, err := strconv.Unquote(s); err == nil {
+		return u
+	}
+	return s
+}
@@ -0,0 +1,49 @@
+package secondarydns
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNS1_UpsertCreatesMissingRecord(t *testing.T) {
+	var calls []string
+	var created ns1Record
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("X-NSONE-Key"))
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+	}))
+	defer server.Close()
+
+	p := &NS1{APIKey: "secret", Endpoint: server.URL}
+	err := p.UpsertRecord(context.Background(), "example.com", Record{
+		Name: "example.com", Type: "CAA", TTL: 300, Values: []string{`0 issue "amazon.com"`},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"POST /zones/example.com/example.com/CAA", "PUT /zones/example.com/example.com/CAA"}, calls)
+	assert.Equal(t, []ns1Answer{{Answer: []string{"0", "issue", "amazon.com"}}}, created.Answers)
+}
+
+func TestNS1_DeleteIgnoresMissingRecord(t *testing.T) {
+	status := http.StatusNotFound
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	p := &NS1{APIKey: "secret", Endpoint: server.URL}
+	assert.NoError(t, p.DeleteRecord(context.Background(), "example.com", "api.example.com", "CNAME"))
+
+	status = http.StatusInternalServerError
+	assert.Error(t, p.DeleteRecord(context.Background(), "example.com", "api.example.com", "CNAME"))
+}
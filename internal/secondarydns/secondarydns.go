@@ -0,0 +1,177 @@
+// Package secondarydns mirrors the records the orchestrator writes to Route53 to a secondary
+// DNS provider, for organizations that serve their zones from two providers.
+package secondarydns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+// aliasTTL is the TTL of the records that stand in for Route53 ALIAS records, which have none
+const aliasTTL = 60
+
+// Record is a record set at the secondary provider
+type Record struct {
+	// Name is the fully qualified name without trailing dot
+	Name string
+	Type string
+	TTL  int64
+	// Values in Route53 presentation format, e.g. `0 issue "amazon.com"` for CAA
+	Values []string
+}
+
+// Provider writes records to a secondary DNS provider. Deleting a record that doesn't exist
+// is not an error.
+type Provider interface {
+	// Name identifies the provider in flags and errors, e.g. ns1
+	Name() string
+	UpsertRecord(ctx context.Context, zone string, record Record) error
+	DeleteRecord(ctx context.Context, zone string, name, recordType string) error
+}
+
+// Zone is the secondary of a Route53 hosted zone: the provider and the zone's name there
+type Zone struct {
+	Provider Provider
+	Name     string
+}
+
+// ParseZones parses a comma-separated list of "<zoneId>=<provider>:<zone name>" entries,
+// e.g. "Z123=ns1:example.com", with providers looked up by name
+func ParseZones(s string, providers map[string]Provider) (map[string]Zone, error) {
+	zones := make(map[string]Zone)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		zoneId, spec, ok := strings.Cut(entry, "=")
+		providerName, zoneName, ok2 := strings.Cut(spec, ":")
+		zoneName = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(zoneName)), ".")
+		if !ok || !ok2 || strings.TrimSpace(zoneId) == "" || zoneName == "" {
+			return nil, fmt.Errorf("invalid secondary zone %q, expected <zoneId>=<provider>:<zone name>", entry)
+		}
+		provider, ok := providers[strings.TrimSpace(providerName)]
+		if !ok || provider == nil {
+			return nil, fmt.Errorf("unknown or unconfigured secondary DNS provider %q in %q", providerName, entry)
+		}
+		zones[strings.TrimSpace(zoneId)] = Zone{Provider: provider, Name: zoneName}
+	}
+	return zones, nil
+}
+
+// WriteThroughRoute53Client writes every record change in a zone that has a secondary to the
+// secondary provider as well. Reads only go to Route53.
+// Upserts go to Route53 first; deletes go to the secondary first, since the controller reads
+// a record from Route53 before deleting it and would not retry a delete that is gone there.
+// A failed secondary write fails the call, so it is retried on the next reconcile.
+type WriteThroughRoute53Client struct {
+	inner aws.Route53Client
+	zones map[string]Zone
+}
+
+// NewWriteThroughRoute53Client wraps inner, mirroring writes in the given zones (by hosted zone ID)
+func NewWriteThroughRoute53Client(inner aws.Route53Client, zones map[string]Zone) *WriteThroughRoute53Client {
+	return &WriteThroughRoute53Client{inner: inner, zones: zones}
+}
+
+func (c *WriteThroughRoute53Client) CreateOrUpdateRecord(ctx context.Context, zoneId string, record aws.DNSRecord) error {
+	if err := c.inner.CreateOrUpdateRecord(ctx, zoneId, record); err != nil {
+		return err
+	}
+	zone, ok := c.zones[zoneId]
+	if !ok {
+		return nil
+	}
+	secondary, ok := secondaryRecord(zone.Name, record)
+	if !ok {
+		return nil
+	}
+	if err := zone.Provider.UpsertRecord(ctx, zone.Name, secondary); err != nil {
+		return fmt.Errorf("failed to write %s record %s to secondary DNS %s: %w", secondary.Type, secondary.Name, zone.Provider.Name(), err)
+	}
+	return nil
+}
+
+func (c *WriteThroughRoute53Client) DeleteRecord(ctx context.Context, zoneId string, record aws.DNSRecord) error {
+	if zone, ok := c.zones[zoneId]; ok {
+		if secondary, ok := secondaryRecord(zone.Name, record); ok {
+			if err := zone.Provider.DeleteRecord(ctx, zone.Name, secondary.Name, secondary.Type); err != nil {
+				return fmt.Errorf("failed to delete %s record %s from secondary DNS %s: %w", secondary.Type, secondary.Name, zone.Provider.Name(), err)
+			}
+		}
+	}
+	return c.inner.DeleteRecord(ctx, zoneId, record)
+}
+
+func (c *WriteThroughRoute53Client) DeleteRecords(ctx context.Context, zoneId string, records []aws.DNSRecord) error {
+	if zone, ok := c.zones[zoneId]; ok {
+		for _, record := range records {
+			if secondary, ok := secondaryRecord(zone.Name, record); ok {
+				if err := zone.Provider.DeleteRecord(ctx, zone.Name, secondary.Name, secondary.Type); err != nil {
+					return fmt.Errorf("failed to delete %s record %s from secondary DNS %s: %w", secondary.Type, secondary.Name, zone.Provider.Name(), err)
+				}
+			}
+		}
+	}
+	return c.inner.DeleteRecords(ctx, zoneId, records)
+}
+
+func (c *WriteThroughRoute53Client) GetRecord(ctx context.Context, zoneId string, name, recordType string) (*aws.DNSRecord, error) {
+	return c.inner.GetRecord(ctx, zoneId, name, recordType)
+}
+
+func (c *WriteThroughRoute53Client) GetDNSSEC(ctx context.Context, zoneId string) (*aws.DNSSECStatus, error) {
+	return c.inner.GetDNSSEC(ctx, zoneId)
+}
+
+func (c *WriteThroughRoute53Client) GetHostedZoneByName(ctx context.Context, name string) (*aws.HostedZone, error) {
+	return c.inner.GetHostedZoneByName(ctx, name)
+}
+
+func (c *WriteThroughRoute53Client) CreateHostedZone(ctx context.Context, name, callerReference string) (*aws.HostedZone, error) {
+	return c.inner.CreateHostedZone(ctx, name, callerReference)
+}
+
+func (c *WriteThroughRoute53Client) GetHostedZone(ctx context.Context, zoneId string) (*aws.HostedZone, error) {
+	return c.inner.GetHostedZone(ctx, zoneId)
+}
+
+func (c *WriteThroughRoute53Client) AssociateVPC(ctx context.Context, zoneId string, vpc aws.VPC) error {
+	return c.inner.AssociateVPC(ctx, zoneId, vpc)
+}
+
+func (c *WriteThroughRoute53Client) DisassociateVPC(ctx context.Context, zoneId string, vpc aws.VPC) error {
+	return c.inner.DisassociateVPC(ctx, zoneId, vpc)
+}
+
+// secondaryRecord translates a Route53 record for the secondary provider, or returns false if
+// it has no counterpart there. Other providers can't alias to an ALB, so an A ALIAS becomes a
+// CNAME to the ALB's DNS name (ALIAS at the zone apex, where CNAMEs aren't allowed), which
+// also answers AAAA queries; AAAA ALIAS records are therefore skipped.
+func secondaryRecord(zoneName string, record aws.DNSRecord) (Record, bool) {
+	name := strings.TrimSuffix(strings.ToLower(record.Name), ".")
+	if record.AliasTarget != nil {
+		if record.Type != "A" {
+			return Record{}, false
+		}
+		recordType := "CNAME"
+		if name == zoneName {
+			recordType = "ALIAS"
+		}
+		return Record{
+			Name:   name,
+			Type:   recordType,
+			TTL:    aliasTTL,
+			Values: []string{strings.TrimSuffix(record.AliasTarget.DNSName, ".")},
+		}, true
+	}
+
+	values := record.Values
+	if len(values) == 0 {
+		values = []string{record.Value}
+	}
+	return Record{Name: name, Type: record.Type, TTL: record.TTL, Values: values}, true
+}
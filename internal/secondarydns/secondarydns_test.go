@@ -0,0 +1,89 @@
+package secondarydns
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+type fakeProvider struct {
+	records map[string]Record
+	err     error
+}
+
+func (p *fakeProvider) Name() string { return "fake" }
+
+func (p *fakeProvider) UpsertRecord(ctx context.Context, zone string, record Record) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.records[zone+"/"+record.Name+"/"+record.Type] = record
+	return nil
+}
+
+func (p *fakeProvider) DeleteRecord(ctx context.Context, zone string, name, recordType string) error {
+	if p.err != nil {
+		return p.err
+	}
+	delete(p.records, zone+"/"+name+"/"+recordType)
+	return nil
+}
+
+func TestParseZones(t *testing.T) {
+	provider := &fakeProvider{}
+	zones, err := ParseZones(" Z123=fake:Example.com. ,", map[string]Provider{"fake": provider})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Zone{"Z123": {Provider: provider, Name: "example.com"}}, zones)
+
+	_, err = ParseZones("Z123=other:example.com", map[string]Provider{"fake": provider})
+	assert.Error(t, err)
+	_, err = ParseZones("Z123=example.com", map[string]Provider{"fake": provider})
+	assert.Error(t, err)
+}
+
+func TestWriteThroughRoute53Client(t *testing.T) {
+	ctx := context.Background()
+	provider := &fakeProvider{records: map[string]Record{}}
+	inner := aws.NewMockRoute53Client()
+	c := NewWriteThroughRoute53Client(inner, map[string]Zone{"Z123": {Provider: provider, Name: "example.com"}})
+
+	alias := &aws.AliasTarget{DNSName: "k8s-edge-gw01.eu-west-1.elb.amazonaws.com.", HostedZoneID: "Z32O12XQLNTSW2"}
+	require.NoError(t, c.CreateOrUpdateRecord(ctx, "Z123", aws.DNSRecord{Name: "api.example.com", Type: "A", AliasTarget: alias}))
+	require.NoError(t, c.CreateOrUpdateRecord(ctx, "Z123", aws.DNSRecord{Name: "api.example.com", Type: "AAAA", AliasTarget: alias}))
+	require.NoError(t, c.CreateOrUpdateRecord(ctx, "Z123", aws.DNSRecord{Name: "example.com", Type: "A", AliasTarget: alias}))
+	require.NoError(t, c.CreateOrUpdateRecord(ctx, "Z123", aws.DNSRecord{Name: "_x1.api.example.com.", Type: "CNAME", Value: "_y1.acm-validations.aws.", TTL: 300}))
+	require.NoError(t, c.CreateOrUpdateRecord(ctx, "Z999", aws.DNSRecord{Name: "api.example.org", Type: "A", AliasTarget: alias}))
+
+	assert.Equal(t, map[string]Record{
+		"example.com/api.example.com/CNAME": {Name: "api.example.com", Type: "CNAME", TTL: aliasTTL,
+			Values: []string{"k8s-edge-gw01.eu-west-1.elb.amazonaws.com"}},
+		"example.com/example.com/ALIAS": {Name: "example.com", Type: "ALIAS", TTL: aliasTTL,
+			Values: []string{"k8s-edge-gw01.eu-west-1.elb.amazonaws.com"}},
+		"example.com/_x1.api.example.com/CNAME": {Name: "_x1.api.example.com", Type: "CNAME", TTL: 300,
+			Values: []string{"_y1.acm-validations.aws."}},
+	}, provider.records)
+
+	require.NoError(t, c.DeleteRecord(ctx, "Z123", aws.DNSRecord{Name: "api.example.com", Type: "A", AliasTarget: alias}))
+	assert.NotContains(t, provider.records, "example.com/api.example.com/CNAME")
+}
+
+func TestWriteThroughRoute53Client_SecondaryFailure(t *testing.T) {
+	ctx := context.Background()
+	provider := &fakeProvider{records: map[string]Record{}, err: errors.New("unavailable")}
+	inner := aws.NewMockRoute53Client()
+	c := NewWriteThroughRoute53Client(inner, map[string]Zone{"Z123": {Provider: provider, Name: "example.com"}})
+	record := aws.DNSRecord{Name: "_x1.api.example.com", Type: "CNAME", Value: "_y1.acm-validations.aws", TTL: 300}
+
+	assert.Error(t, c.CreateOrUpdateRecord(ctx, "Z123", record))
+
+	// A failed secondary delete leaves the Route53 record in place so the delete is retried
+	assert.Error(t, c.DeleteRecord(ctx, "Z123", record))
+	got, err := inner.GetRecord(ctx, "Z123", record.Name, record.Type)
+	require.NoError(t, err)
+	assert.NotNil(t, got)
+}
@@ -0,0 +1,113 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/controller"
+)
+
+// Namespace annotations platform teams use to set per-tenant defaults
+const (
+	AnnotationDefaultVisibility = "gateway.opendi.com/default-visibility"
+	AnnotationDefaultWafArn     = "gateway.opendi.com/default-waf-arn"
+)
+
+// DefaultVisibility is used when neither the request nor its namespace specify a visibility
+const DefaultVisibility = "internet-facing"
+
+// wafArnPattern mirrors the CRD validation on spec.wafArn (including the regional WebACL rule)
+// so a malformed namespace annotation is ignored instead of making every request in the
+// namespace fail validation.
+var wafArnPattern = regexp.MustCompile(`^arn:aws:wafv2:[a-z0-9-]+:[0-9]+:regional/webacl/.*$`)
+
+// GatewayHostnameRequestDefaulter fills unset GatewayHostnameRequest fields from
+// annotations on the request's namespace. Explicit values in the spec always win.
+type GatewayHostnameRequestDefaulter struct {
+	Client client.Reader
+}
+
+//+kubebuilder:webhook:path=/mutate-gateway-opendi-com-v1alpha1-gatewayhostnamerequest,mutating=true,failurePolicy=ignore,sideEffects=None,groups=gateway.opendi.com,resources=gatewayhostnamerequests,verbs=create,versions=v1alpha1,name=mgatewayhostnamerequest.gateway.opendi.com,admissionReviewVersions=v1
+
+// SetupWithManager registers the defaulting webhook with the Manager
+func (d *GatewayHostnameRequestDefaulter) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&gatewayv1alpha1.GatewayHostnameRequest{}).
+		WithDefaulter(d).
+		Complete()
+}
+
+// Default implements admission.CustomDefaulter
+func (d *GatewayHostnameRequestDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	logger := log.FromContext(ctx)
+
+	ghr, ok := obj.(*gatewayv1alpha1.GatewayHostnameRequest)
+	if !ok {
+		return fmt.Errorf("expected a GatewayHostnameRequest but got %T", obj)
+	}
+
+	// Only on create. Stored requests keep their spec on update, e.g. when the controller adds
+	// its finalizer: the hostname is immutable, a cleared wafArn stays cleared, and a namespace
+	// default added later doesn't move existing requests to another Gateway.
+	if !ghr.CreationTimestamp.IsZero() {
+		return nil
+	}
+
+	// Accept App.Example.com. as app.example.com.
+	ghr.Spec.Hostname = controller.CanonicalHostname(ghr.Spec.Hostname)
+
+	if ghr.Spec.Visibility != "" && ghr.Spec.WafArn != "" {
+		return nil
+	}
+
+	// The namespace is not always populated on the object during CREATE
+	namespace := ghr.Namespace
+	if namespace == "" {
+		if req, err := admission.RequestFromContext(ctx); err == nil {
+			namespace = req.Namespace
+		}
+	}
+
+	var ns corev1.Namespace
+	if err := d.Client.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+		}
+	}
+
+	if ghr.Spec.Visibility == "" {
+		switch v := ns.Annotations[AnnotationDefaultVisibility]; v {
+		case "internet-facing", "internal":
+			ghr.Spec.Visibility = v
+		case "":
+			ghr.Spec.Visibility = DefaultVisibility
+		default:
+			logger.Info("Ignoring invalid namespace default visibility", "namespace", namespace, "value", v)
+			ghr.Spec.Visibility = DefaultVisibility
+		}
+	}
+
+	// DNS-only requests have no ALB to attach a WAF to
+	if ghr.Spec.WafArn == "" && ghr.Spec.AliasTarget == nil {
+		if v := ns.Annotations[AnnotationDefaultWafArn]; v != "" {
+			if wafArnPattern.MatchString(v) {
+				ghr.Spec.WafArn = v
+			} else {
+				logger.Info("Ignoring invalid namespace default WAF ARN", "namespace", namespace, "value", v)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+const testWafArn = "arn:aws:wafv2:eu-central-1:123456789012:regional/webacl/tenant/abc"
+
+func TestGatewayHostnameRequestDefaulter_Default(t *testing.T) {
+	tests := []struct {
+		name           string
+		annotations    map[string]string
+		spec           gatewayv1alpha1.GatewayHostnameRequestSpec
+		wantVisibility string
+		wantWafArn     string
+	}{
+		{
+			name:           "no namespace annotations falls back to internet-facing",
+			wantVisibility: "internet-facing",
+		},
+		{
+			name: "namespace defaults applied to unset fields",
+			annotations: map[string]string{
+				AnnotationDefaultVisibility: "internal",
+				AnnotationDefaultWafArn:     testWafArn,
+			},
+			wantVisibility: "internal",
+			wantWafArn:     testWafArn,
+		},
+		{
+			name: "explicit spec values win over namespace defaults",
+			annotations: map[string]string{
+				AnnotationDefaultVisibility: "internal",
+				AnnotationDefaultWafArn:     testWafArn,
+			},
+			spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+				Visibility: "internet-facing",
+				WafArn:     "arn:aws:wafv2:eu-central-1:123456789012:regional/webacl/own/def",
+			},
+			wantVisibility: "internet-facing",
+			wantWafArn:     "arn:aws:wafv2:eu-central-1:123456789012:regional/webacl/own/def",
+		},
+		{
+			name: "invalid namespace annotations are ignored",
+			annotations: map[string]string{
+				AnnotationDefaultVisibility: "public",
+				AnnotationDefaultWafArn:     "not-an-arn",
+			},
+			wantVisibility: "internet-facing",
+		},
+		{
+			name: "global WAF ARN annotation is ignored",
+			annotations: map[string]string{
+				AnnotationDefaultWafArn: "arn:aws:wafv2:us-east-1:123456789012:global/webacl/edge/abc",
+			},
+			wantVisibility: "internet-facing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = corev1.AddToScheme(scheme)
+			_ = gatewayv1alpha1.AddToScheme(scheme)
+
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-a", Annotations: tt.annotations},
+			}
+			d := &GatewayHostnameRequestDefaulter{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(ns).Build(),
+			}
+
+			ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+				ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "team-a"},
+				Spec:       tt.spec,
+			}
+
+			err := d.Default(context.Background(), ghr)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantVisibility, ghr.Spec.Visibility)
+			assert.Equal(t, tt.wantWafArn, ghr.Spec.WafArn)
+		})
+	}
+}
+
+func TestGatewayHostnameRequestDefaulter_MissingNamespace(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	d := &GatewayHostnameRequestDefaulter{
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+	}
+
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "gone"},
+	}
+
+	err := d.Default(context.Background(), ghr)
+	assert.NoError(t, err)
+	assert.Equal(t, "internet-facing", ghr.Spec.Visibility)
+}
+
+func TestGatewayHostnameRequestDefaulter_CanonicalHostname(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	d := &GatewayHostnameRequestDefaulter{
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+	}
+
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "team-a"},
+		Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "App.Example.com.", Visibility: "internal", WafArn: testWafArn},
+	}
+	assert.NoError(t, d.Default(context.Background(), ghr))
+	assert.Equal(t, "app.example.com", ghr.Spec.Hostname)
+
+	// Stored requests keep their spelling: the hostname is immutable
+	stored := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "team-a", CreationTimestamp: metav1.Now()},
+		Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "App.Example.com."},
+	}
+	assert.NoError(t, d.Default(context.Background(), stored))
+	assert.Equal(t, "App.Example.com.", stored.Spec.Hostname)
+}
+
+func TestGatewayHostnameRequestDefaulter_UpdateKeepsSpec(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Annotations: map[string]string{
+		AnnotationDefaultVisibility: "internal",
+		AnnotationDefaultWafArn:     testWafArn,
+	}}}
+	d := &GatewayHostnameRequestDefaulter{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(ns).Build(),
+	}
+
+	// An existing request without a WAF, e.g. created while the webhook was unreachable,
+	// updated by the controller adding its finalizer
+	stored := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "api", Namespace: "team-a", CreationTimestamp: metav1.Now(),
+			Finalizers: []string{"gateway-orchestrator.opendi.com/finalizer"},
+		},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "api.example.com"},
+	}
+	want := stored.Spec.DeepCopy()
+	assert.NoError(t, d.Default(context.Background(), stored))
+	assert.Equal(t, *want, stored.Spec)
+}
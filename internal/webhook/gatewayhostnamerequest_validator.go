@@ -0,0 +1,150 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/controller"
+)
+
+// DefaultOverrideTargetGroups are the groups allowed to set spec.overrideTarget unless configured
+var DefaultOverrideTargetGroups = []string{"system:masters"}
+
+// DefaultQuarantineApproverGroups are the groups allowed to approve quarantined hostnames unless
+// configured
+var DefaultQuarantineApproverGroups = []string{"system:masters"}
+
+// GatewayHostnameRequestValidator rejects new GatewayHostnameRequests for hostnames that break
+// DNS length and label rules, or are in cluster-internal domains (cluster.local, svc and
+// --internal-domain-suffixes), which can never get public certificates or DNS. The CRD rejects
+// the built-in suffixes on its own; the webhook adds the configured ones. Updates are only
+// validated for changes of spec.overrideTarget, which is reserved to OverrideTargetGroups, and
+// approvals of quarantined hostnames (controller.AnnotationApproveQuarantine), which are
+// reserved to QuarantineApproverGroups, so existing requests can always be changed and deleted.
+//
+// The webhook is registered twice on the same path: for every request with failurePolicy
+// Ignore, and, in config/webhook/manifests.yaml, with failurePolicy Fail for the requests
+// whose matchConditions show such a privileged change, so these fail closed while the webhook
+// is down.
+type GatewayHostnameRequestValidator struct {
+	// InternalDomainSuffixes are the rejected domains; nil means
+	// controller.DefaultInternalDomainSuffixes
+	InternalDomainSuffixes []string
+
+	// OverrideTargetGroups may set, change and clear spec.overrideTarget; nil means
+	// DefaultOverrideTargetGroups
+	OverrideTargetGroups []string
+
+	// QuarantineApproverGroups may set and change controller.AnnotationApproveQuarantine; nil
+	// means DefaultQuarantineApproverGroups. Anyone may remove it.
+	QuarantineApproverGroups []string
+}
+
+//+kubebuilder:webhook:path=/validate-gateway-opendi-com-v1alpha1-gatewayhostnamerequest,mutating=false,failurePolicy=ignore,sideEffects=None,groups=gateway.opendi.com,resources=gatewayhostnamerequests,verbs=create;update,versions=v1alpha1,name=vgatewayhostnamerequest.gateway.opendi.com,admissionReviewVersions=v1
+
+// SetupWithManager registers the validating webhook with the Manager
+func (v *GatewayHostnameRequestValidator) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&gatewayv1alpha1.GatewayHostnameRequest{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *GatewayHostnameRequestValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	ghr, ok := obj.(*gatewayv1alpha1.GatewayHostnameRequest)
+	if !ok {
+		return nil, fmt.Errorf("expected a GatewayHostnameRequest but got %T", obj)
+	}
+
+	if err := controller.ValidateHostname(ghr.Spec.Hostname); err != nil {
+		return nil, err
+	}
+
+	suffixes := v.InternalDomainSuffixes
+	if suffixes == nil {
+		suffixes = controller.DefaultInternalDomainSuffixes
+	}
+	if suffix, internal := controller.InternalDomainSuffix(ghr.Spec.Hostname, suffixes); internal {
+		return nil, fmt.Errorf("hostname %s is in the cluster-internal domain %s and can't get public DNS or certificates", ghr.Spec.Hostname, suffix)
+	}
+	if ghr.Spec.OverrideTarget != nil {
+		if err := v.authorizeOverrideTarget(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if ghr.Annotations[controller.AnnotationApproveQuarantine] != "" {
+		return nil, v.authorizeQuarantineApproval(ctx)
+	}
+	return nil, nil
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *GatewayHostnameRequestValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldGHR, ok := oldObj.(*gatewayv1alpha1.GatewayHostnameRequest)
+	if !ok {
+		return nil, fmt.Errorf("expected a GatewayHostnameRequest but got %T", oldObj)
+	}
+	newGHR, ok := newObj.(*gatewayv1alpha1.GatewayHostnameRequest)
+	if !ok {
+		return nil, fmt.Errorf("expected a GatewayHostnameRequest but got %T", newObj)
+	}
+	if !equality.Semantic.DeepEqual(oldGHR.Spec.OverrideTarget, newGHR.Spec.OverrideTarget) {
+		if err := v.authorizeOverrideTarget(ctx); err != nil {
+			return nil, err
+		}
+	}
+	approval := newGHR.Annotations[controller.AnnotationApproveQuarantine]
+	if approval != "" && approval != oldGHR.Annotations[controller.AnnotationApproveQuarantine] {
+		return nil, v.authorizeQuarantineApproval(ctx)
+	}
+	return nil, nil
+}
+
+// authorizeOverrideTarget checks that the user of the admission request is in one of the
+// OverrideTargetGroups
+func (v *GatewayHostnameRequestValidator) authorizeOverrideTarget(ctx context.Context) error {
+	groups := v.OverrideTargetGroups
+	if groups == nil {
+		groups = DefaultOverrideTargetGroups
+	}
+	return authorizeGroups(ctx, groups, "change spec.overrideTarget")
+}
+
+// authorizeQuarantineApproval checks that the user of the admission request is in one of the
+// QuarantineApproverGroups
+func (v *GatewayHostnameRequestValidator) authorizeQuarantineApproval(ctx context.Context) error {
+	groups := v.QuarantineApproverGroups
+	if groups == nil {
+		groups = DefaultQuarantineApproverGroups
+	}
+	return authorizeGroups(ctx, groups, "set "+controller.AnnotationApproveQuarantine)
+}
+
+// authorizeGroups checks that the user of the admission request is in one of the groups; action
+// describes what they may not do otherwise
+func authorizeGroups(ctx context.Context, groups []string, action string) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("only members of %s may %s", strings.Join(groups, ", "), action)
+	}
+	for _, group := range req.UserInfo.Groups {
+		if slices.Contains(groups, group) {
+			return nil
+		}
+	}
+	return fmt.Errorf("user %s may not %s; it is reserved to members of %s", req.UserInfo.Username, action, strings.Join(groups, ", "))
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *GatewayHostnameRequestValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
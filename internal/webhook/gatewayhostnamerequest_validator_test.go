@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/controller"
+)
+
+func TestGatewayHostnameRequestValidator_ValidateCreate(t *testing.T) {
+	v := &GatewayHostnameRequestValidator{InternalDomainSuffixes: []string{"cluster.local", "svc", "corp.internal"}}
+
+	for hostname, wantErr := range map[string]bool{
+		"api.example.com":              false,
+		"api.team-a.svc":               true,
+		"api.team-a.svc.cluster.local": true,
+		"*.corp.internal":              true,
+		"internal.example.com":         false,
+		"-api.example.com":             true,
+	} {
+		ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "team-a"},
+			Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: hostname},
+		}
+		_, err := v.ValidateCreate(context.Background(), ghr)
+		assert.Equal(t, wantErr, err != nil, hostname)
+
+		// Existing requests can always be updated (e.g., to remove the finalizer)
+		_, err = v.ValidateUpdate(context.Background(), ghr, ghr)
+		assert.NoError(t, err, hostname)
+	}
+}
+
+func TestGatewayHostnameRequestValidator_OverrideTarget(t *testing.T) {
+	v := &GatewayHostnameRequestValidator{OverrideTargetGroups: []string{"platform-admins"}}
+	asUser := func(groups ...string) context.Context {
+		return admission.NewContextWithRequest(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			UserInfo: authenticationv1.UserInfo{Username: "jane", Groups: groups},
+		}})
+	}
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "team-a"},
+		Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "api.example.com"},
+	}
+	overridden := ghr.DeepCopy()
+	overridden.Spec.OverrideTarget = &gatewayv1alpha1.AliasTarget{DNSName: "maintenance.s3-website-eu-west-1.amazonaws.com"}
+
+	_, err := v.ValidateCreate(asUser("system:authenticated"), overridden)
+	assert.Error(t, err)
+	_, err = v.ValidateCreate(asUser("system:authenticated", "platform-admins"), overridden)
+	assert.NoError(t, err)
+
+	for name, update := range map[string][2]*gatewayv1alpha1.GatewayHostnameRequest{
+		"set":   {ghr, overridden},
+		"clear": {overridden, ghr},
+	} {
+		_, err = v.ValidateUpdate(asUser("team-a"), update[0], update[1])
+		assert.Error(t, err, name)
+		_, err = v.ValidateUpdate(asUser("platform-admins"), update[0], update[1])
+		assert.NoError(t, err, name)
+	}
+
+	// Other changes of an overridden request are not restricted
+	changed := overridden.DeepCopy()
+	changed.Spec.Environment = "prod"
+	_, err = v.ValidateUpdate(asUser("team-a"), overridden, changed)
+	assert.NoError(t, err)
+}
+
+func TestGatewayHostnameRequestValidator_QuarantineApproval(t *testing.T) {
+	v := &GatewayHostnameRequestValidator{QuarantineApproverGroups: []string{"security-reviewers"}}
+	asUser := func(groups ...string) context.Context {
+		return admission.NewContextWithRequest(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			UserInfo: authenticationv1.UserInfo{Username: "jane", Groups: groups},
+		}})
+	}
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "team-a"},
+		Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: "paypal-login.example.com"},
+	}
+	approved := ghr.DeepCopy()
+	approved.Annotations = map[string]string{controller.AnnotationApproveQuarantine: "paypal-login.example.com"}
+
+	_, err := v.ValidateCreate(asUser("team-a"), approved)
+	assert.Error(t, err, "a request can't be created approved")
+	_, err = v.ValidateCreate(asUser("security-reviewers"), approved)
+	assert.NoError(t, err)
+
+	_, err = v.ValidateUpdate(asUser("team-a"), ghr, approved)
+	assert.Error(t, err)
+	_, err = v.ValidateUpdate(asUser("security-reviewers"), ghr, approved)
+	assert.NoError(t, err)
+
+	// Withdrawing an approval and other changes of an approved request are not restricted
+	_, err = v.ValidateUpdate(asUser("team-a"), approved, ghr)
+	assert.NoError(t, err)
+	changed := approved.DeepCopy()
+	changed.Spec.Environment = "prod"
+	_, err = v.ValidateUpdate(asUser("team-a"), approved, changed)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+// HTTPRouteLimitValidator caps the HTTPRoutes one namespace attaches to each pool Gateway, so
+// a single tenant can't use up the listener rules of an ALB shared with other tenants. Only
+// new attachments are checked: routes already over the limit can still be changed and deleted.
+type HTTPRouteLimitValidator struct {
+	Client client.Reader
+
+	// GatewayPool decides which Gateways are limited; Gateways outside its namespaces are not
+	GatewayPool *gateway.Pool
+
+	// MaxRoutesPerNamespace is the number of HTTPRoutes a namespace may attach to one Gateway
+	MaxRoutesPerNamespace int
+}
+
+//+kubebuilder:webhook:path=/validate-gateway-networking-k8s-io-v1-httproute,mutating=false,failurePolicy=ignore,sideEffects=None,groups=gateway.networking.k8s.io,resources=httproutes,verbs=create;update,versions=v1,name=vhttproute.gateway.opendi.com,admissionReviewVersions=v1
+
+// SetupWithManager registers the validating webhook with the Manager
+func (v *HTTPRouteLimitValidator) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&gwapiv1.HTTPRoute{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *HTTPRouteLimitValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	route, ok := obj.(*gwapiv1.HTTPRoute)
+	if !ok {
+		return nil, fmt.Errorf("expected an HTTPRoute but got %T", obj)
+	}
+	return nil, v.validate(ctx, route, nil)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *HTTPRouteLimitValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	route, ok := newObj.(*gwapiv1.HTTPRoute)
+	if !ok {
+		return nil, fmt.Errorf("expected an HTTPRoute but got %T", newObj)
+	}
+	old, ok := oldObj.(*gwapiv1.HTTPRoute)
+	if !ok {
+		return nil, fmt.Errorf("expected an HTTPRoute but got %T", oldObj)
+	}
+	return nil, v.validate(ctx, route, old)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *HTTPRouteLimitValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate rejects the route if it attaches to a pool Gateway that its namespace already
+// attaches MaxRoutesPerNamespace other routes to. Gateways the old route was attached to
+// are skipped.
+func (v *HTTPRouteLimitValidator) validate(ctx context.Context, route, old *gwapiv1.HTTPRoute) error {
+	if v.MaxRoutesPerNamespace <= 0 {
+		return nil
+	}
+
+	attached := map[types.NamespacedName]bool{}
+	if old != nil {
+		for _, gw := range gateway.RouteParents(old) {
+			attached[gw] = true
+		}
+	}
+	var added []types.NamespacedName
+	for _, gw := range gateway.RouteParents(route) {
+		if !attached[gw] && v.GatewayPool.IsPoolNamespace(gw.Namespace) {
+			added = append(added, gw)
+		}
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	var routes gwapiv1.HTTPRouteList
+	if err := v.Client.List(ctx, &routes, client.InNamespace(route.Namespace)); err != nil {
+		// Fail open: the limit must never block route changes cluster-wide
+		log.FromContext(ctx).Error(err, "Failed to list HTTPRoutes, allowing route", "namespace", route.Namespace, "route", route.Name)
+		return nil
+	}
+	others := routes.Items[:0]
+	for _, r := range routes.Items {
+		if r.Name != route.Name {
+			others = append(others, r)
+		}
+	}
+	counts := gateway.CountRoutes(others)
+
+	var full []string
+	for _, gw := range added {
+		if counts[gw][route.Namespace] >= v.MaxRoutesPerNamespace {
+			full = append(full, gw.String())
+		}
+	}
+	if len(full) == 0 {
+		return nil
+	}
+	return fmt.Errorf("namespace %s already attaches %d HTTPRoutes to Gateway %s, the limit per namespace; "+
+		"merge rules into fewer routes or ask the platform team to raise --max-routes-per-namespace",
+		route.Namespace, v.MaxRoutesPerNamespace, strings.Join(full, ", "))
+}
@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+func testRoute(namespace, name string, gatewayNames ...string) *gwapiv1.HTTPRoute {
+	edge := gwapiv1.Namespace("edge")
+	route := &gwapiv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	for _, gw := range gatewayNames {
+		route.Spec.ParentRefs = append(route.Spec.ParentRefs, gwapiv1.ParentReference{Name: gwapiv1.ObjectName(gw), Namespace: &edge})
+	}
+	return route
+}
+
+func TestHTTPRouteLimitValidator(t *testing.T) {
+	existing := []*gwapiv1.HTTPRoute{
+		testRoute("team-a", "a1", "gw-01"),
+		testRoute("team-a", "a2", "gw-01"),
+		testRoute("team-b", "b1", "gw-01"),
+	}
+
+	tests := []struct {
+		name    string
+		max     int
+		old     *gwapiv1.HTTPRoute
+		route   *gwapiv1.HTTPRoute
+		wantErr bool
+	}{
+		{
+			name:    "namespace at the limit can't attach another route",
+			max:     2,
+			route:   testRoute("team-a", "a3", "gw-01"),
+			wantErr: true,
+		},
+		{
+			name:  "other namespaces have their own budget",
+			max:   2,
+			route: testRoute("team-b", "b2", "gw-01"),
+		},
+		{
+			name:  "other Gateways have their own budget",
+			max:   2,
+			route: testRoute("team-a", "a3", "gw-02"),
+		},
+		{
+			name:  "updating an attached route is allowed",
+			max:   2,
+			old:   testRoute("team-a", "a2", "gw-01"),
+			route: testRoute("team-a", "a2", "gw-01"),
+		},
+		{
+			name:    "moving a route onto a full Gateway is rejected",
+			max:     1,
+			old:     testRoute("team-b", "b2", "gw-02"),
+			route:   testRoute("team-b", "b2", "gw-01"),
+			wantErr: true,
+		},
+		{
+			name:  "Gateways outside the pool are not limited",
+			max:   1,
+			route: &gwapiv1.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Name: "a3", Namespace: "team-a"}, Spec: gwapiv1.HTTPRouteSpec{CommonRouteSpec: gwapiv1.CommonRouteSpec{ParentRefs: []gwapiv1.ParentReference{{Name: "gw-01"}}}}},
+		},
+		{
+			name:  "zero disables the limit",
+			route: testRoute("team-a", "a3", "gw-01"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = gwapiv1.AddToScheme(scheme)
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			for _, route := range existing {
+				builder = builder.WithObjects(route.DeepCopy())
+			}
+			c := builder.Build()
+			v := &HTTPRouteLimitValidator{
+				Client:                c,
+				GatewayPool:           gateway.NewPool(c, "edge", "aws-alb", 0, 0),
+				MaxRoutesPerNamespace: tt.max,
+			}
+
+			var err error
+			if tt.old == nil {
+				_, err = v.ValidateCreate(context.Background(), tt.route)
+			} else {
+				_, err = v.ValidateUpdate(context.Background(), tt.old, tt.route)
+			}
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "--max-routes-per-namespace")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+)
+
+// AnnotationAllowDeletion opts a namespace out of deletion protection
+const AnnotationAllowDeletion = "gateway.opendi.com/allow-deletion"
+
+// Namespace deletion protection modes
+const (
+	NamespaceProtectionDeny = "deny"
+	NamespaceProtectionWarn = "warn"
+	NamespaceProtectionOff  = "off"
+)
+
+// NamespaceDeletionValidator blocks (or warns on) deletion of namespaces that still
+// contain Ready GatewayHostnameRequests, so a team deleting "their" namespace doesn't
+// silently tear down production hostnames.
+type NamespaceDeletionValidator struct {
+	Client client.Reader
+
+	// Mode is deny or warn. Deny can be bypassed per namespace with AnnotationAllowDeletion.
+	Mode string
+}
+
+//+kubebuilder:webhook:path=/validate--v1-namespace,mutating=false,failurePolicy=ignore,sideEffects=None,groups="",resources=namespaces,verbs=delete,versions=v1,name=vnamespace.gateway.opendi.com,admissionReviewVersions=v1
+
+// SetupWithManager registers the validating webhook with the Manager
+func (v *NamespaceDeletionValidator) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&corev1.Namespace{}).
+		WithValidator(v).
+		Complete()
+}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *NamespaceDeletionValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *NamespaceDeletionValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *NamespaceDeletionValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	logger := log.FromContext(ctx)
+
+	ns, ok := obj.(*corev1.Namespace)
+	if !ok {
+		return nil, fmt.Errorf("expected a Namespace but got %T", obj)
+	}
+
+	hostnames, err := v.readyHostnames(ctx, ns.Name)
+	if err != nil {
+		// Fail open: protection must never block namespace deletion cluster-wide
+		logger.Error(err, "Failed to list GatewayHostnameRequests, allowing namespace deletion", "namespace", ns.Name)
+		return nil, nil
+	}
+	if len(hostnames) == 0 {
+		return nil, nil
+	}
+
+	msg := fmt.Sprintf("namespace %s still serves Ready hostnames: %s", ns.Name, strings.Join(hostnames, ", "))
+	if v.Mode == NamespaceProtectionWarn || ns.Annotations[AnnotationAllowDeletion] == "true" {
+		logger.Info("Namespace with Ready hostnames is being deleted", "namespace", ns.Name, "hostnames", hostnames)
+		return admission.Warnings{msg + "; they will be torn down"}, nil
+	}
+
+	return nil, fmt.Errorf("%s; delete the GatewayHostnameRequests first or annotate the namespace with %s=true",
+		msg, AnnotationAllowDeletion)
+}
+
+// readyHostnames returns the sorted hostnames of Ready, non-deleting requests in a namespace
+func (v *NamespaceDeletionValidator) readyHostnames(ctx context.Context, namespace string) ([]string, error) {
+	var ghrList gatewayv1alpha1.GatewayHostnameRequestList
+	if err := v.Client.List(ctx, &ghrList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	var hostnames []string
+	for _, ghr := range ghrList.Items {
+		if !ghr.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if conditions.IsReady(ghr.Status.Conditions) {
+			hostnames = append(hostnames, ghr.Spec.Hostname)
+		}
+	}
+	sort.Strings(hostnames)
+	return hostnames, nil
+}
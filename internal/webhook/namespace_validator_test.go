@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/controller"
+)
+
+func testGHR(namespace, name, hostname string, ready metav1.ConditionStatus) *gatewayv1alpha1.GatewayHostnameRequest {
+	return &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       gatewayv1alpha1.GatewayHostnameRequestSpec{Hostname: hostname},
+		Status: gatewayv1alpha1.GatewayHostnameRequestStatus{
+			Conditions: []metav1.Condition{{Type: controller.ConditionTypeReady, Status: ready}},
+		},
+	}
+}
+
+func TestNamespaceDeletionValidator_ValidateDelete(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        string
+		annotations map[string]string
+		ghrs        []*gatewayv1alpha1.GatewayHostnameRequest
+		wantErr     bool
+		wantWarning bool
+	}{
+		{
+			name: "no requests allows deletion",
+			mode: NamespaceProtectionDeny,
+		},
+		{
+			name: "only non-ready requests allows deletion",
+			mode: NamespaceProtectionDeny,
+			ghrs: []*gatewayv1alpha1.GatewayHostnameRequest{
+				testGHR("team-a", "pending", "pending.example.com", metav1.ConditionFalse),
+			},
+		},
+		{
+			name: "ready request denies deletion",
+			mode: NamespaceProtectionDeny,
+			ghrs: []*gatewayv1alpha1.GatewayHostnameRequest{
+				testGHR("team-a", "api", "api.example.com", metav1.ConditionTrue),
+			},
+			wantErr: true,
+		},
+		{
+			name: "ready request in another namespace is ignored",
+			mode: NamespaceProtectionDeny,
+			ghrs: []*gatewayv1alpha1.GatewayHostnameRequest{
+				testGHR("team-b", "api", "api.example.com", metav1.ConditionTrue),
+			},
+		},
+		{
+			name:        "allow-deletion annotation downgrades to warning",
+			mode:        NamespaceProtectionDeny,
+			annotations: map[string]string{AnnotationAllowDeletion: "true"},
+			ghrs: []*gatewayv1alpha1.GatewayHostnameRequest{
+				testGHR("team-a", "api", "api.example.com", metav1.ConditionTrue),
+			},
+			wantWarning: true,
+		},
+		{
+			name: "warn mode only warns",
+			mode: NamespaceProtectionWarn,
+			ghrs: []*gatewayv1alpha1.GatewayHostnameRequest{
+				testGHR("team-a", "api", "api.example.com", metav1.ConditionTrue),
+			},
+			wantWarning: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = corev1.AddToScheme(scheme)
+			_ = gatewayv1alpha1.AddToScheme(scheme)
+
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			for _, ghr := range tt.ghrs {
+				builder = builder.WithObjects(ghr)
+			}
+			v := &NamespaceDeletionValidator{Client: builder.Build(), Mode: tt.mode}
+
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Annotations: tt.annotations}}
+			warnings, err := v.ValidateDelete(context.Background(), ns)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), "api.example.com")
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantWarning, len(warnings) > 0)
+		})
+	}
+}
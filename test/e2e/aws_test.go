@@ -0,0 +1,242 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+// awsBackend is the AWS the controller talks to: LocalStack if E2E_AWS_ENDPOINT is set,
+// the in-memory mocks otherwise. Calls are serialized, so tests can inspect the mocks while
+// the controller runs, and the controller's calls can be made to fail through faults.
+type awsBackend struct {
+	mu      sync.Mutex
+	acm     aws.ACMClient
+	route53 aws.Route53Client
+
+	// mockACM and mockRoute53 are set when running against the in-memory mocks
+	mockACM     *aws.MockACMClient
+	mockRoute53 *aws.MockRoute53Client
+
+	faults *aws.FaultInjector
+}
+
+func newAWSBackend(ctx context.Context) (*awsBackend, error) {
+	endpoint := os.Getenv("E2E_AWS_ENDPOINT")
+	if endpoint == "" {
+		b := &awsBackend{mockACM: aws.NewMockACMClient(), mockRoute53: aws.NewMockRoute53Client(), faults: aws.NewFaultInjector(1)}
+		b.acm, b.route53 = b.mockACM, b.mockRoute53
+		return b, nil
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion("us-east-1"),
+		config.WithBaseEndpoint(endpoint),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider("test", "test", "")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config for %s: %w", endpoint, err)
+	}
+	return &awsBackend{acm: aws.NewSDKACMClient(cfg), route53: aws.NewSDKRoute53Client(cfg), faults: aws.NewFaultInjector(1)}, nil
+}
+
+// ACM returns the ACM client for the controller
+func (b *awsBackend) ACM() aws.ACMClient {
+	return aws.NewFaultInjectingACMClient(&acmClient{b}, b.faults)
+}
+
+// Route53 returns the Route53 client for the controller
+func (b *awsBackend) Route53() aws.Route53Client {
+	return aws.NewFaultInjectingRoute53Client(&route53Client{b}, b.faults)
+}
+
+// record returns a record as the test sees it, bypassing faults
+func (b *awsBackend) record(ctx context.Context, zoneId, name, recordType string) (*aws.DNSRecord, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.route53.GetRecord(ctx, zoneId, name, recordType)
+}
+
+// certificate returns a certificate as the test sees it, bypassing faults
+func (b *awsBackend) certificate(ctx context.Context, arn string) (*aws.CertificateDetails, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	details, err := b.acm.DescribeCertificate(ctx, arn)
+	if err != nil || details == nil {
+		return details, err
+	}
+	copied := *details
+	return &copied, nil
+}
+
+// validateCertificates stands in for ACM's DNS validation with the in-memory mocks: a
+// pending certificate is issued once all its validation records are in Route53
+func (b *awsBackend) validateCertificates(ctx context.Context) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		b.mu.Lock()
+		for arn, cert := range b.mockACM.Certificates {
+			if cert.Status == "PENDING_VALIDATION" && b.hasRecords(b.mockACM.ValidationRecords[arn]) {
+				cert.Status = "ISSUED"
+			}
+		}
+		b.mu.Unlock()
+	}
+}
+
+// hasRecords reports whether the validation records exist in any zone; called with mu held
+func (b *awsBackend) hasRecords(records []aws.ValidationRecord) bool {
+	for _, vr := range records {
+		found := false
+		for _, record := range b.mockRoute53.Records {
+			if strings.TrimSuffix(record.Name, ".") == strings.TrimSuffix(vr.Name, ".") && record.Type == vr.Type {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return len(records) > 0
+}
+
+// acmClient serializes the controller's calls to the backend's ACM
+type acmClient struct {
+	b *awsBackend
+}
+
+func (c *acmClient) RequestCertificate(ctx context.Context, domain, idempotencyToken string, tags map[string]string) (string, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	return c.b.acm.RequestCertificate(ctx, domain, idempotencyToken, tags)
+}
+
+func (c *acmClient) RequestCertificateWithSANs(ctx context.Context, domain string, subjectAlternativeNames []string, idempotencyToken string, tags map[string]string) (string, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	return c.b.acm.RequestCertificateWithSANs(ctx, domain, subjectAlternativeNames, idempotencyToken, tags)
+}
+
+func (c *acmClient) DescribeCertificate(ctx context.Context, certArn string) (*aws.CertificateDetails, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	details, err := c.b.acm.DescribeCertificate(ctx, certArn)
+	if err != nil || details == nil {
+		return details, err
+	}
+	// The mock hands out its own struct, which validateCertificates writes
+	copied := *details
+	return &copied, nil
+}
+
+func (c *acmClient) DeleteCertificate(ctx context.Context, certArn string) error {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	return c.b.acm.DeleteCertificate(ctx, certArn)
+}
+
+func (c *acmClient) GetValidationRecords(ctx context.Context, certArn string) ([]aws.ValidationRecord, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	return c.b.acm.GetValidationRecords(ctx, certArn)
+}
+
+func (c *acmClient) ImportCertificate(ctx context.Context, certArn string, certificate, privateKey, chain []byte, tags map[string]string) (string, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	return c.b.acm.ImportCertificate(ctx, certArn, certificate, privateKey, chain, tags)
+}
+
+func (c *acmClient) ListCertificates(ctx context.Context) ([]aws.CertificateSummary, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	return c.b.acm.ListCertificates(ctx)
+}
+
+func (c *acmClient) GetCertificateTags(ctx context.Context, certArn string) (map[string]string, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	return c.b.acm.GetCertificateTags(ctx, certArn)
+}
+
+// route53Client serializes the controller's calls to the backend's Route53
+type route53Client struct {
+	b *awsBackend
+}
+
+func (c *route53Client) CreateOrUpdateRecord(ctx context.Context, zoneId string, record aws.DNSRecord) error {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	return c.b.route53.CreateOrUpdateRecord(ctx, zoneId, record)
+}
+
+func (c *route53Client) DeleteRecord(ctx context.Context, zoneId string, record aws.DNSRecord) error {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	return c.b.route53.DeleteRecord(ctx, zoneId, record)
+}
+
+func (c *route53Client) DeleteRecords(ctx context.Context, zoneId string, records []aws.DNSRecord) error {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	return c.b.route53.DeleteRecords(ctx, zoneId, records)
+}
+
+func (c *route53Client) GetRecord(ctx context.Context, zoneId string, name, recordType string) (*aws.DNSRecord, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	return c.b.route53.GetRecord(ctx, zoneId, name, recordType)
+}
+
+func (c *route53Client) GetDNSSEC(ctx context.Context, zoneId string) (*aws.DNSSECStatus, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	return c.b.route53.GetDNSSEC(ctx, zoneId)
+}
+
+func (c *route53Client) GetHostedZoneByName(ctx context.Context, name string) (*aws.HostedZone, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	return c.b.route53.GetHostedZoneByName(ctx, name)
+}
+
+func (c *route53Client) CreateHostedZone(ctx context.Context, name, callerReference string) (*aws.HostedZone, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	return c.b.route53.CreateHostedZone(ctx, name, callerReference)
+}
+
+func (c *route53Client) GetHostedZone(ctx context.Context, zoneId string) (*aws.HostedZone, error) {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	return c.b.route53.GetHostedZone(ctx, zoneId)
+}
+
+func (c *route53Client) AssociateVPC(ctx context.Context, zoneId string, vpc aws.VPC) error {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	return c.b.route53.AssociateVPC(ctx, zoneId, vpc)
+}
+
+func (c *route53Client) DisassociateVPC(ctx context.Context, zoneId string, vpc aws.VPC) error {
+	c.b.mu.Lock()
+	defer c.b.mu.Unlock()
+	return c.b.route53.DisassociateVPC(ctx, zoneId, vpc)
+}
@@ -0,0 +1,175 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/michelfeldheim/gateway-orchestrator/api/conditions"
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/aws"
+)
+
+func TestCreateAndDelete(t *testing.T) {
+	ctx := context.Background()
+	ghr := createRequest(t, "app", "app."+zoneName)
+	waitReady(t, ghr)
+
+	assert.NotEmpty(t, ghr.Status.CertificateArn)
+	cert, err := backend.certificate(ctx, ghr.Status.CertificateArn)
+	require.NoError(t, err)
+	assert.Equal(t, "ISSUED", cert.Status)
+
+	var gw gwapiv1.Gateway
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Namespace: ghr.Status.AssignedGatewayNamespace, Name: ghr.Status.AssignedGateway}, &gw))
+	assert.Equal(t, loadBalancerDNS(&gw), ghr.Status.AssignedLoadBalancer)
+	assertAlias(t, ghr.Spec.Hostname, ghr.Status.AssignedLoadBalancer)
+	assert.NotNil(t, findClaim(t, ghr.Spec.Hostname), "the hostname is claimed")
+
+	deleteRequest(t, ghr)
+	assertDeprovisioned(t, ghr)
+}
+
+func TestVisibilityChangeMovesGateway(t *testing.T) {
+	ctx := context.Background()
+	ghr := createRequest(t, "move", "move."+zoneName)
+	waitReady(t, ghr)
+	oldGateway := ghr.Status.AssignedGateway
+
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(ghr), ghr))
+	ghr.Spec.Visibility = "internal"
+	require.NoError(t, k8sClient.Update(ctx, ghr))
+
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		require.NoError(c, k8sClient.Get(ctx, client.ObjectKeyFromObject(ghr), ghr))
+		assert.NotEqual(c, oldGateway, ghr.Status.AssignedGateway)
+		assert.Empty(c, ghr.Status.MigratingFromGateway, "the move is finished")
+		assert.True(c, conditions.IsReady(ghr.Status.Conditions))
+	}, timeout, interval)
+	assertAlias(t, ghr.Spec.Hostname, ghr.Status.AssignedLoadBalancer)
+
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		var gw gwapiv1.Gateway
+		err := k8sClient.Get(ctx, client.ObjectKey{Namespace: gatewayNamespace, Name: oldGateway}, &gw)
+		assert.True(c, apierrors.IsNotFound(err), "the empty Gateway is deleted")
+	}, timeout, interval)
+
+	deleteRequest(t, ghr)
+	assertDeprovisioned(t, ghr)
+}
+
+func TestRecoversFromAWSFailures(t *testing.T) {
+	backend.faults.FailNext("RequestCertificate", 2)
+	backend.faults.FailNext("CreateOrUpdateRecord", 3)
+	requests := backend.faults.Calls("CreateOrUpdateRecord")
+
+	ghr := createRequest(t, "flaky", "flaky."+zoneName)
+	waitReady(t, ghr)
+	assert.Greater(t, backend.faults.Calls("CreateOrUpdateRecord")-requests, 3, "failed writes are retried")
+	assertAlias(t, ghr.Spec.Hostname, ghr.Status.AssignedLoadBalancer)
+
+	deleteRequest(t, ghr)
+	assertDeprovisioned(t, ghr)
+}
+
+func TestSurvivesChaos(t *testing.T) {
+	backend.faults.Set(aws.AllOperations, aws.Fault{Latency: 20 * time.Millisecond, ThrottleRate: 0.2})
+	backend.faults.Set("CreateOrUpdateRecord", aws.Fault{ThrottleRate: 0.2, PartialRate: 0.3})
+	backend.faults.Set("DeleteRecords", aws.Fault{PartialRate: 0.5})
+	defer backend.faults.Reset()
+
+	ghr := createRequest(t, "chaos", "chaos."+zoneName)
+	waitReady(t, ghr)
+	assertAlias(t, ghr.Spec.Hostname, ghr.Status.AssignedLoadBalancer)
+
+	deleteRequest(t, ghr)
+	assertDeprovisioned(t, ghr)
+}
+
+// createRequest creates a request for the hostname in the test zone
+func createRequest(t *testing.T, name, hostname string) *gatewayv1alpha1.GatewayHostnameRequest {
+	t.Helper()
+	ghr := &gatewayv1alpha1.GatewayHostnameRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec: gatewayv1alpha1.GatewayHostnameRequestSpec{
+			Hostname: hostname,
+			ZoneId:   zoneId,
+		},
+	}
+	require.NoError(t, k8sClient.Create(context.Background(), ghr))
+	return ghr
+}
+
+// waitReady waits until the request is Ready and refreshes it
+func waitReady(t *testing.T, ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+	t.Helper()
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		require.NoError(c, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(ghr), ghr))
+		assert.True(c, conditions.IsReady(ghr.Status.Conditions), "status: %s", ghr.Status.Message)
+	}, timeout, interval)
+}
+
+// deleteRequest deletes the request and waits until its finalizer is removed
+func deleteRequest(t *testing.T, ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+	t.Helper()
+	ctx := context.Background()
+	require.NoError(t, k8sClient.Delete(ctx, ghr))
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		err := k8sClient.Get(ctx, client.ObjectKeyFromObject(ghr), &gatewayv1alpha1.GatewayHostnameRequest{})
+		assert.True(c, apierrors.IsNotFound(err), "the request is gone")
+	}, timeout, interval)
+}
+
+// assertAlias checks that the hostname's A record aliases the load balancer
+func assertAlias(t *testing.T, hostname, loadBalancer string) {
+	t.Helper()
+	record, err := backend.record(context.Background(), zoneId, hostname, "A")
+	require.NoError(t, err)
+	require.NotNil(t, record, "A record for %s", hostname)
+	require.NotNil(t, record.AliasTarget)
+	assert.Contains(t, record.AliasTarget.DNSName, loadBalancer)
+}
+
+// assertDeprovisioned checks that nothing of a deleted request is left: claim, alias record,
+// certificate, and Gateways once no request uses them
+func assertDeprovisioned(t *testing.T, ghr *gatewayv1alpha1.GatewayHostnameRequest) {
+	t.Helper()
+	ctx := context.Background()
+
+	assert.Nil(t, findClaim(t, ghr.Spec.Hostname), "the claim is released")
+	record, err := backend.record(ctx, zoneId, ghr.Spec.Hostname, "A")
+	require.NoError(t, err)
+	assert.Nil(t, record, "the alias record is deleted")
+	if ghr.Status.CertificateArn != "" {
+		_, err := backend.certificate(ctx, ghr.Status.CertificateArn)
+		assert.Error(t, err, "the certificate is deleted")
+	}
+
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		var gateways gwapiv1.GatewayList
+		require.NoError(c, k8sClient.List(ctx, &gateways, client.InNamespace(gatewayNamespace)))
+		assert.Empty(c, gateways.Items, "Gateways without requests are deleted")
+	}, timeout, interval)
+}
+
+// findClaim returns the DomainClaim of the hostname, or nil
+func findClaim(t *testing.T, hostname string) *gatewayv1alpha1.DomainClaim {
+	t.Helper()
+	var claims gatewayv1alpha1.DomainClaimList
+	require.NoError(t, k8sClient.List(context.Background(), &claims))
+	for i := range claims.Items {
+		if claims.Items[i].Spec.Hostname == hostname {
+			return &claims.Items[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// loadBalancerDNS is the ALB DNS name the fake AWS Load Balancer Controller gives a Gateway
+func loadBalancerDNS(gw *gwapiv1.Gateway) string {
+	return fmt.Sprintf("k8s-%s-%s-0123456789.us-east-1.elb.amazonaws.com", gw.Namespace, gw.Name)
+}
+
+// fakeLoadBalancerController stands in for the AWS Load Balancer Controller: it programs every
+// Gateway in the gateway namespace with an ALB address, and again after each spec change
+func fakeLoadBalancerController(c client.Client) func(context.Context) error {
+	return func(ctx context.Context) error {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+
+			var gateways gwapiv1.GatewayList
+			if err := c.List(ctx, &gateways, client.InNamespace(gatewayNamespace)); err != nil {
+				continue
+			}
+			for i := range gateways.Items {
+				gw := &gateways.Items[i]
+				programmed := meta.FindStatusCondition(gw.Status.Conditions, string(gwapiv1.GatewayConditionProgrammed))
+				if (len(gw.Status.Addresses) > 0 && programmed != nil && programmed.ObservedGeneration == gw.Generation) ||
+					!gw.DeletionTimestamp.IsZero() {
+					continue
+				}
+				gw.Status.Addresses = []gwapiv1.GatewayStatusAddress{{
+					Type:  ptrTo(gwapiv1.HostnameAddressType),
+					Value: loadBalancerDNS(gw),
+				}}
+				meta.SetStatusCondition(&gw.Status.Conditions, metav1.Condition{
+					Type:               string(gwapiv1.GatewayConditionProgrammed),
+					Status:             metav1.ConditionTrue,
+					Reason:             string(gwapiv1.GatewayReasonProgrammed),
+					ObservedGeneration: gw.Generation,
+				})
+				// Conflicts with the orchestrator's updates are retried on the next tick
+				_ = c.Status().Update(ctx, gw)
+			}
+		}
+	}
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,203 @@
+//go:build e2e
+
+// Package e2e runs the GatewayHostnameRequest controller against a real API server (envtest)
+// and LocalStack or the in-memory AWS mocks, so regressions between provisioning steps that
+// unit tests with fake clients can't see are caught. Run it with make test-e2e.
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"go/build"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	gatewayv1alpha1 "github.com/michelfeldheim/gateway-orchestrator/api/v1alpha1"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/controller"
+	"github.com/michelfeldheim/gateway-orchestrator/internal/gateway"
+)
+
+const (
+	gatewayNamespace = "edge"
+	testNamespace    = "e2e"
+	zoneName         = "e2e.example.com"
+
+	// timeout covers the controller's 30s wait for a new Gateway's load balancer and, with
+	// LocalStack, certificate issuance
+	timeout  = 3 * time.Minute
+	interval = 250 * time.Millisecond
+)
+
+var (
+	k8sClient client.Client
+	backend   *awsBackend
+	zoneId    string
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(runSuite(m))
+}
+
+// runSuite starts the API server, the AWS backend and the controller, then runs the tests
+func runSuite(m *testing.M) int {
+	if os.Getenv("E2E_VERBOSE") != "" {
+		ctrl.SetLogger(zap.New(zap.UseDevMode(true)))
+	} else {
+		ctrl.SetLogger(logr.Discard())
+	}
+
+	env := &envtest.Environment{
+		CRDDirectoryPaths: []string{
+			filepath.Join("..", "..", "config", "crd"),
+			gatewayAPICRDs(),
+			filepath.Join("testdata", "crds"),
+		},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start envtest (is KUBEBUILDER_ASSETS set?): %v\n", err)
+		return 1
+	}
+	defer func() { _ = env.Stop() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(gwapiv1.AddToScheme(scheme))
+	utilruntime.Must(gwapiv1beta1.AddToScheme(scheme))
+
+	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create client: %v\n", err)
+		return 1
+	}
+	for _, ns := range []string{gatewayNamespace, testNamespace} {
+		if err := k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}}); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to create namespace %s: %v\n", ns, err)
+			return 1
+		}
+	}
+
+	backend, err = newAWSBackend(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	zone, err := backend.route53.CreateHostedZone(ctx, zoneName, fmt.Sprintf("e2e-%d", time.Now().UnixNano()))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create hosted zone %s: %v\n", zoneName, err)
+		return 1
+	}
+	zoneId = zone.ID
+
+	mgr, err := newManager(cfg, scheme)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		if err := mgr.Start(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "manager stopped: %v\n", err)
+		}
+	}()
+	defer func() {
+		cancel()
+		<-stopped
+	}()
+
+	return m.Run()
+}
+
+// newManager sets up the controller as cmd/controller does, with the AWS backend, a fake
+// AWS Load Balancer Controller and, with the mocks, fake ACM validation
+func newManager(cfg *rest.Config, scheme *runtime.Scheme) (manager.Manager, error) {
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:  scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create manager: %w", err)
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	lbcVersion := controller.NewLBCVersionResolver(discoveryClient)
+	if err := lbcVersion.Refresh(context.Background()); err != nil {
+		return nil, err
+	}
+
+	if err := (&controller.GatewayHostnameRequestReconciler{
+		Client:        mgr.GetClient(),
+		APIReader:     mgr.GetAPIReader(),
+		Scheme:        mgr.GetScheme(),
+		Recorder:      mgr.GetEventRecorderFor("gateway-orchestrator"),
+		ACMClient:     backend.ACM(),
+		Route53Client: backend.Route53(),
+		GatewayPool:   gateway.NewPool(mgr.GetClient(), gatewayNamespace, "alb", 80, 443),
+		LBCVersion:    lbcVersion,
+
+		CertificatePollBackoff: []time.Duration{time.Second},
+		RebalanceDrainPeriod:   time.Second,
+	}).SetupWithManager(mgr); err != nil {
+		return nil, fmt.Errorf("failed to set up controller: %w", err)
+	}
+
+	if err := mgr.Add(manager.RunnableFunc(fakeLoadBalancerController(mgr.GetClient()))); err != nil {
+		return nil, err
+	}
+	if backend.mockACM != nil {
+		if err := mgr.Add(manager.RunnableFunc(backend.validateCertificates)); err != nil {
+			return nil, err
+		}
+	}
+	return mgr, nil
+}
+
+// gatewayAPICRDs returns the directory of the standard Gateway API CRDs of the version in
+// go.mod in the module cache, unless GATEWAY_API_CRDS points elsewhere
+func gatewayAPICRDs() string {
+	if dir := os.Getenv("GATEWAY_API_CRDS"); dir != "" {
+		return dir
+	}
+	version := ""
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == "sigs.k8s.io/gateway-api" {
+				version = dep.Version
+			}
+		}
+	}
+	modCache := os.Getenv("GOMODCACHE")
+	if modCache == "" {
+		modCache = filepath.Join(build.Default.GOPATH, "pkg", "mod")
+	}
+	return filepath.Join(modCache, "sigs.k8s.io", "gateway-api@"+version, "config", "crd", "standard")
+}